@@ -0,0 +1,297 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/spf13/cobra"
+)
+
+// newACLsCmd returns the "acls" command group for managing ACLs as code
+func newACLsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "acls",
+		Short: "Export and import ACLs",
+	}
+	cmd.AddCommand(newACLsExportCmd(), newACLsImportCmd(), newACLsSyncCmd())
+	return cmd
+}
+
+func newACLsExportCmd() *cobra.Command {
+	var (
+		output string
+		format string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export all ACLs to YAML or JSON",
+		Example: `  kconduit acls export > acls.yaml
+  kconduit acls export --output acls.json
+  kconduit acls export --format go-template='{{range .acls}}{{.principal}} {{.operation}} {{.resourceName}}{{"\n"}}{{end}}'`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format == "" {
+				format = "yaml"
+				if output != "" {
+					format = kafka.FileFormatFromPath(output)
+				}
+			}
+			if err := kafka.CheckOutputFormat(format); err != nil {
+				return err
+			}
+
+			client, err := connect()
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err := client.Close(); err != nil {
+					log.Printf("Error closing Kafka client: %v", err)
+				}
+			}()
+
+			acls, err := client.ListACLs()
+			if err != nil {
+				return err
+			}
+			data, err := kafka.MarshalACLs(acls, format)
+			if err != nil {
+				return err
+			}
+
+			if output == "" {
+				_, err = os.Stdout.Write(data)
+				return err
+			}
+			if err := os.WriteFile(output, data, 0o644); err != nil {
+				return fmt.Errorf("failed to write %s: %v", output, err)
+			}
+			fmt.Fprintf(os.Stderr, "Exported %d ACLs to %s\n", len(acls), output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file (default stdout)")
+	cmd.Flags().StringVar(&format, "format", "", outputFormatUsage)
+	return cmd
+}
+
+func newACLsImportCmd() *cobra.Command {
+	var (
+		format string
+		prune  bool
+		dryRun bool
+		yes    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Create the ACLs defined in a YAML or JSON file, showing a diff first",
+		Long: `Compares the ACLs in FILE with those in the cluster, prints the difference and
+applies it after confirmation. ACLs missing from the file are only deleted with --prune.`,
+		Example: `  kconduit acls import acls.yaml --dry-run
+  kconduit acls import acls.yaml --prune --yes`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := args[0]
+			if format == "" {
+				format = kafka.FileFormatFromPath(path)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %v", path, err)
+			}
+			desired, err := kafka.UnmarshalACLs(data, format)
+			if err != nil {
+				return err
+			}
+
+			client, err := connect()
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err := client.Close(); err != nil {
+					log.Printf("Error closing Kafka client: %v", err)
+				}
+			}()
+
+			current, err := client.ListACLs()
+			if err != nil {
+				return err
+			}
+			diff := kafka.DiffACLs(current, desired)
+			if !prune {
+				diff.Remove = nil
+			}
+
+			printACLDiff(diff)
+			if len(diff.Add) == 0 && len(diff.Remove) == 0 {
+				fmt.Println("Cluster ACLs already match the file")
+				return nil
+			}
+			if dryRun {
+				return nil
+			}
+			if !yes && !confirm("Apply these changes?") {
+				return fmt.Errorf("aborted")
+			}
+			return applyACLDiff(client, diff)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "Input format: yaml or json (default from the file extension)")
+	cmd.Flags().BoolVar(&prune, "prune", false, "Delete cluster ACLs that are not in the file")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only show the diff")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Apply without asking for confirmation")
+	return cmd
+}
+
+func newACLsSyncCmd() *cobra.Command {
+	var (
+		prune  bool
+		dryRun bool
+		yes    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "sync TARGET_CLUSTER",
+		Short: "Copy the ACLs of the connected cluster to another, showing a diff first",
+		Long: `Compares the ACLs of the connected cluster, the source, with those of
+TARGET_CLUSTER, a profile from the clusters section of the config file, prints
+the difference and applies it to the target after confirmation. ACLs only on
+the target are only deleted with --prune.`,
+		Example: `  kconduit --cluster prod acls sync dr --dry-run
+  kconduit --cluster prod acls sync dr --prune --yes`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			targetSettings, err := clusterSettings(args[0])
+			if err != nil {
+				return err
+			}
+
+			source, err := connect()
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err := source.Close(); err != nil {
+					log.Printf("Error closing Kafka client: %v", err)
+				}
+			}()
+			target, err := dial(targetSettings)
+			if err != nil {
+				return fmt.Errorf("%s: %w", args[0], err)
+			}
+			defer func() {
+				if err := target.Close(); err != nil {
+					log.Printf("Error closing Kafka client: %v", err)
+				}
+			}()
+
+			_, err = syncACLs(source, target, args[0], prune, dryRun, func(question string) bool {
+				return yes || confirm(question)
+			})
+			return err
+		},
+	}
+
+	cmd.Flags().BoolVar(&prune, "prune", false, "Delete target ACLs that are not on the source")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only show the diff")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Apply without asking for confirmation")
+	return cmd
+}
+
+// aclStore is the part of a cluster client ACLs are read from and applied to
+type aclStore interface {
+	ListACLs() ([]kafka.ACL, error)
+	CreateACL(acl kafka.ACL) error
+	DeleteACL(acl kafka.ACL) error
+}
+
+// syncACLs prints the changes that bring the ACLs of target, the cluster
+// named name, in line with those of source and applies them once approve
+// agrees. ACLs only on the target are kept unless prune is set, and nothing
+// is changed on a dry run. It returns the changes it computed.
+func syncACLs(source, target aclStore, name string, prune, dryRun bool, approve func(question string) bool) (kafka.ACLDiff, error) {
+	desired, err := source.ListACLs()
+	if err != nil {
+		return kafka.ACLDiff{}, err
+	}
+	current, err := target.ListACLs()
+	if err != nil {
+		return kafka.ACLDiff{}, fmt.Errorf("%s: %w", name, err)
+	}
+	diff := kafka.DiffACLs(current, desired)
+	if !prune {
+		diff.Remove = nil
+	}
+
+	printACLDiff(diff)
+	if len(diff.Add) == 0 && len(diff.Remove) == 0 {
+		fmt.Printf("%s already has the same ACLs\n", name)
+		return diff, nil
+	}
+	if dryRun {
+		return diff, nil
+	}
+	if !approve(fmt.Sprintf("Apply these changes to %s?", name)) {
+		return diff, fmt.Errorf("aborted")
+	}
+	return diff, applyACLDiff(target, diff)
+}
+
+// applyACLDiff creates and deletes ACLs. New ones are created before
+// anything is deleted so a failure never leaves principals with fewer
+// permissions than before.
+func applyACLDiff(client aclStore, diff kafka.ACLDiff) error {
+	var failed int
+	for _, acl := range diff.Add {
+		if err := client.CreateACL(acl); err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "Failed to create %s: %v\n", acl, err)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d ACLs could not be created, nothing was deleted", failed, len(diff.Add))
+	}
+	for _, acl := range diff.Remove {
+		if err := client.DeleteACL(acl); err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "Failed to delete %s: %v\n", acl, err)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d ACLs could not be deleted", failed, len(diff.Remove))
+	}
+
+	fmt.Printf("Created %d and deleted %d ACLs\n", len(diff.Add), len(diff.Remove))
+	return nil
+}
+
+// printACLDiff prints additions and removals in a diff-like format
+func printACLDiff(diff kafka.ACLDiff) {
+	for _, acl := range diff.Add {
+		fmt.Printf("+ %s\n", acl)
+	}
+	for _, acl := range diff.Remove {
+		fmt.Printf("- %s\n", acl)
+	}
+	fmt.Printf("\n%d to add, %d to delete, %d unchanged\n", len(diff.Add), len(diff.Remove), len(diff.Unchanged))
+}
+
+// outputFormatUsage describes the --format flag of the export commands
+const outputFormatUsage = "Output format: yaml, json, go-template=TEMPLATE or go-template-file=PATH (default from the file extension, else yaml)"
+
+// confirm asks a yes/no question on stdin
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
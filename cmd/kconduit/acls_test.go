@@ -0,0 +1,102 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+// fakeACLStore holds ACLs in memory and records the changes made to them
+type fakeACLStore struct {
+	acls    []kafka.ACL
+	created []kafka.ACL
+	deleted []kafka.ACL
+}
+
+func (f *fakeACLStore) ListACLs() ([]kafka.ACL, error) { return f.acls, nil }
+
+func (f *fakeACLStore) CreateACL(acl kafka.ACL) error {
+	f.created = append(f.created, acl)
+	return nil
+}
+
+func (f *fakeACLStore) DeleteACL(acl kafka.ACL) error {
+	f.deleted = append(f.deleted, acl)
+	return nil
+}
+
+func TestSyncACLs(t *testing.T) {
+	acl := func(principal, topic string) kafka.ACL {
+		return kafka.ACL{Principal: principal, Host: "*", Operation: "Read", PermissionType: "Allow",
+			ResourceType: "Topic", ResourceName: topic, PatternType: "Literal"}
+	}
+	shared := acl("User:alice", "orders")
+	missing := acl("User:bob", "payments")
+	extra := acl("User:carol", "legacy")
+	// The target lists the shared ACL without the defaults filled in
+	sharedOnTarget := kafka.ACL{Principal: "User:alice", Operation: "Read", ResourceType: "Topic", ResourceName: "orders"}
+
+	tests := []struct {
+		name        string
+		prune       bool
+		dryRun      bool
+		approved    bool
+		wantCreated []kafka.ACL
+		wantDeleted []kafka.ACL
+	}{
+		{"creates missing and skips existing", false, false, true, []kafka.ACL{missing}, nil},
+		{"prune deletes target only ACLs", true, false, true, []kafka.ACL{missing}, []kafka.ACL{extra}},
+		{"dry run changes nothing", true, true, true, nil, nil},
+		{"declined changes nothing", false, false, false, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &fakeACLStore{acls: []kafka.ACL{shared, missing}}
+			target := &fakeACLStore{acls: []kafka.ACL{sharedOnTarget, extra}}
+			asked := false
+			approve := func(string) bool {
+				asked = true
+				return tt.approved
+			}
+
+			diff, err := syncACLs(source, target, "dr", tt.prune, tt.dryRun, approve)
+			if tt.approved && err != nil {
+				t.Fatal(err)
+			}
+			if !tt.approved && err == nil {
+				t.Error("expected an error when the changes are declined")
+			}
+			if !reflect.DeepEqual(diff.Add, []kafka.ACL{missing}) || !reflect.DeepEqual(diff.Unchanged, []kafka.ACL{shared}) {
+				t.Errorf("plan adds %v, keeps %v", diff.Add, diff.Unchanged)
+			}
+			if tt.prune != (len(diff.Remove) == 1) {
+				t.Errorf("plan removes %v with prune %v", diff.Remove, tt.prune)
+			}
+			if asked == tt.dryRun {
+				t.Errorf("asked for confirmation = %v on dry run %v", asked, tt.dryRun)
+			}
+			if !reflect.DeepEqual(target.created, tt.wantCreated) || !reflect.DeepEqual(target.deleted, tt.wantDeleted) {
+				t.Errorf("created %v, deleted %v", target.created, target.deleted)
+			}
+			if source.created != nil || source.deleted != nil {
+				t.Error("the source was changed")
+			}
+		})
+	}
+}
+
+func TestSyncACLsInSync(t *testing.T) {
+	acls := []kafka.ACL{{Principal: "User:alice", Operation: "Read", ResourceType: "Topic", ResourceName: "orders"}}
+	target := &fakeACLStore{acls: acls}
+	approve := func(string) bool {
+		t.Error("asked for confirmation with nothing to change")
+		return true
+	}
+	if _, err := syncACLs(&fakeACLStore{acls: acls}, target, "dr", true, false, approve); err != nil {
+		t.Fatal(err)
+	}
+	if target.created != nil || target.deleted != nil {
+		t.Errorf("created %v, deleted %v", target.created, target.deleted)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/spf13/cobra"
+)
+
+// histogramWidth is the length of the longest bar in a latency histogram
+const histogramWidth = 40
+
+// newBenchmarkCmd returns the "benchmark" command measuring throughput and
+// latency against a topic
+func newBenchmarkCmd() *cobra.Command {
+	var opts kafka.BenchmarkOptions
+
+	cmd := &cobra.Command{
+		Use:   "benchmark TOPIC",
+		Short: "Measure produce and consume throughput and latency",
+		Long: `Produces messages of a fixed size to TOPIC for a while, at a set rate or as
+fast as the cluster takes them, and consumes them back. Reports throughput and
+histograms of the produce (send to acknowledgement) and end-to-end (send to
+consumed) latency. The connection flags are the same as for the UI.
+
+Use a topic set aside for benchmarks, created with the partitions and
+replication you want to measure; the messages stay in it.`,
+		Example: `  kconduit benchmark bench --duration 1m
+  kconduit benchmark bench --size 4096 --rate 5000 --acks 1
+  kconduit benchmark bench --consume=false`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: firstArg(completeTopics),
+		SilenceUsage:      true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			return withClient(func(client *kafka.Client) error {
+				rate := "as fast as possible"
+				if opts.Rate > 0 {
+					rate = fmt.Sprintf("%d msg/s", opts.Rate)
+				}
+				fmt.Printf("Producing %d byte messages to %s for %s, %s, acks=%s\n\n", opts.MessageSize, args[0], opts.Duration, rate, opts.Acks)
+
+				opts.OnProgress = func(s kafka.BenchmarkStats) {
+					fmt.Fprintf(os.Stderr, "%6s  sent %d  acked %d  consumed %d  errors %d  %.0f msg/s  %s/s\n",
+						s.Elapsed.Round(time.Second), s.Sent, s.Acked, s.Consumed, s.Errors, s.MessagesPerSec(), formatSize(s.BytesPerSec()))
+				}
+				stats, err := client.Benchmark(ctx, args[0], opts)
+				if err != nil {
+					return err
+				}
+
+				fmt.Printf("\nAcknowledged %d messages in %s: %.0f msg/s, %s/s\n",
+					stats.Acked, stats.Elapsed.Round(time.Millisecond), stats.MessagesPerSec(), formatSize(stats.BytesPerSec()))
+				if stats.Errors > 0 {
+					fmt.Printf("%d messages failed, the last with: %v\n", stats.Errors, stats.LastErr)
+				}
+				printHistogram("Produce latency", stats.Produce)
+				if opts.Consume {
+					fmt.Printf("\nConsumed %d of them\n", stats.Consumed)
+					printHistogram("End-to-end latency", stats.EndToEnd)
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&opts.MessageSize, "size", 1024, "Message size in bytes")
+	cmd.Flags().IntVar(&opts.Rate, "rate", 0, "Target messages per second (0 for as fast as possible)")
+	cmd.Flags().DurationVar(&opts.Duration, "duration", 30*time.Second, "How long to produce for")
+	cmd.Flags().StringVar(&opts.Acks, "acks", "all", "Acknowledgements to wait for: all, 1 or 0")
+	cmd.Flags().BoolVar(&opts.Consume, "consume", true, "Consume the messages back and measure end-to-end latency")
+	return cmd
+}
+
+// printHistogram prints percentiles and a bar per latency bucket
+func printHistogram(title string, h *kafka.LatencyHistogram) {
+	if h.Count() == 0 {
+		return
+	}
+	fmt.Printf("\n%s: mean %s, p50 %s, p95 %s, p99 %s, max %s\n", title, formatLatency(h.Mean()),
+		formatLatency(h.Percentile(50)), formatLatency(h.Percentile(95)), formatLatency(h.Percentile(99)), formatLatency(h.Max()))
+
+	buckets := h.Buckets()
+	var most int64
+	for _, b := range buckets {
+		most = max(most, b.Count)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	for _, b := range buckets {
+		bound := "> 5s"
+		if b.Le > 0 {
+			bound = "<= " + b.Le.String()
+		}
+		bar := strings.Repeat("#", int(b.Count*histogramWidth/most))
+		fmt.Fprintf(w, "  %s\t%d\t %s\n", bound, b.Count, bar)
+	}
+	w.Flush()
+}
+
+// formatSize formats a byte count with a binary unit
+func formatSize(bytes float64) string {
+	units := []string{"B", "KiB", "MiB", "GiB"}
+	i := 0
+	for bytes >= 1024 && i < len(units)-1 {
+		bytes /= 1024
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", bytes, units[i])
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/digitalis-io/kconduit/pkg/profiles"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// activeSettings returns the connection settings in use: the profile chosen
+// with --cluster, otherwise the flags, environment and top level of the
+// config file
+func activeSettings() (*viper.Viper, error) {
+	if name := viper.GetString("cluster"); name != "" {
+		return clusterSettings(name)
+	}
+	return viper.GetViper(), nil
+}
+
+// clusterSettings returns a cluster profile from the clusters section of
+// the config file. Profiles take the same keys as the top level; flags and
+// environment variables do not apply to them.
+func clusterSettings(name string) (*viper.Viper, error) {
+	settings := viper.Sub("clusters." + name)
+	if settings == nil {
+		names := clusterNames()
+		if len(names) == 0 {
+			return nil, fmt.Errorf("cluster %q not found: the config file has no clusters section", name)
+		}
+		return nil, fmt.Errorf("cluster %q not found, the config file defines %s", name, strings.Join(names, ", "))
+	}
+	if settings.GetString("brokers") == "" {
+		return nil, fmt.Errorf("cluster %q has no brokers", name)
+	}
+	settings.SetDefault("sasl_mechanism", "PLAIN")
+	settings.SetDefault("sasl_protocol", "SASL_PLAINTEXT")
+	return settings, nil
+}
+
+// clusterLabel names the connected cluster for people: the --cluster
+// profile, otherwise the brokers
+func clusterLabel() string {
+	if name := viper.GetString("cluster"); name != "" {
+		return name
+	}
+	return viper.GetString("brokers")
+}
+
+// configFilePath is the config file kconduit reads, which need not exist yet
+func configFilePath() (string, error) {
+	if path := viper.ConfigFileUsed(); path != "" {
+		return path, nil
+	}
+	if cfgConfigFile != "" {
+		return cfgConfigFile, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the user config directory: %v", err)
+	}
+	return filepath.Join(dir, "kconduit", "config.yaml"), nil
+}
+
+// newClustersCmd returns the "clusters" command group for managing the
+// cluster profiles in the config file
+func newClustersCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clusters",
+		Short: "List and import cluster profiles",
+	}
+	cmd.AddCommand(newClustersListCmd(), newClustersImportCmd())
+	return cmd
+}
+
+// clusterListEntry is a profile as printed by clusters list --format
+type clusterListEntry struct {
+	Name    string `json:"name" yaml:"name"`
+	Brokers string `json:"brokers" yaml:"brokers"`
+}
+
+func newClustersListCmd() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List the cluster profiles in the config file",
+		Example: `  kconduit clusters list --format go-template='{{range .clusters}}{{.name}}{{"\n"}}{{end}}'`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			names := clusterNames()
+			if format != "" {
+				if err := kafka.CheckOutputFormat(format); err != nil {
+					return err
+				}
+				entries := make([]clusterListEntry, 0, len(names))
+				for _, name := range names {
+					entries = append(entries, clusterListEntry{Name: name, Brokers: viper.GetString("clusters." + name + ".brokers")})
+				}
+				data, err := kafka.EncodeOutput(map[string][]clusterListEntry{"clusters": entries}, format)
+				if err != nil {
+					return err
+				}
+				_, err = os.Stdout.Write(data)
+				return err
+			}
+			if len(names) == 0 {
+				fmt.Fprintln(os.Stderr, "No cluster profiles defined")
+				return nil
+			}
+			for _, name := range names {
+				fmt.Printf("%-24s  %s\n", name, viper.GetString("clusters."+name+".brokers"))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "Output format: yaml, json, go-template=TEMPLATE or go-template-file=PATH (default a table)")
+	return cmd
+}
+
+func newClustersImportCmd() *cobra.Command {
+	var (
+		from  string
+		name  string
+		force bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Create cluster profiles from a kcat or kafkactl config file",
+		Long: `Reads the connection settings of another Kafka tool and adds them to the
+clusters section of the config file, creating the file if needed.
+
+--from kcat reads a librdkafka properties file, as passed to kcat with -F, and
+needs --name for the profile. --from kafkactl reads a kafkactl config file and
+imports each of its contexts under the context's name, or only the context
+given with --name. The format is guessed from the file extension when --from
+is not given: .yml and .yaml are kafkactl, anything else kcat.
+
+Settings kconduit has no equivalent for are reported and left out. Passwords
+are copied as they are; replace them with keyring:, cmd: or file: references
+afterwards to keep them out of the config file.`,
+		Example: `  kconduit clusters import ~/.config/kcat.conf --name prod
+  kconduit clusters import ~/.config/kafkactl/config.yml
+  kconduit clusters import ~/.config/kafkactl/config.yml --name staging --force`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %v", args[0], err)
+			}
+			if from == "" {
+				from = "kcat"
+				if ext := filepath.Ext(args[0]); ext == ".yml" || ext == ".yaml" {
+					from = "kafkactl"
+				}
+			}
+
+			imported := make(map[string]profiles.Profile)
+			var warnings []string
+			switch from {
+			case "kcat":
+				if name == "" {
+					return fmt.Errorf("--name is required when importing a kcat config")
+				}
+				var p profiles.Profile
+				if p, warnings, err = profiles.FromKcat(data); err != nil {
+					return fmt.Errorf("failed to import %s: %v", args[0], err)
+				}
+				imported[name] = p
+			case "kafkactl":
+				var contexts map[string]profiles.Profile
+				if contexts, warnings, err = profiles.FromKafkactl(data); err != nil {
+					return fmt.Errorf("failed to import %s: %v", args[0], err)
+				}
+				if name == "" {
+					imported = contexts
+				} else if p, ok := contexts[name]; ok {
+					imported[name] = p
+				} else {
+					return fmt.Errorf("context %q not found in %s", name, args[0])
+				}
+			default:
+				return fmt.Errorf("invalid --from %q, use kcat or kafkactl", from)
+			}
+			for _, w := range warnings {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+			}
+			if len(imported) == 0 {
+				return fmt.Errorf("nothing to import from %s", args[0])
+			}
+
+			path, err := configFilePath()
+			if err != nil {
+				return err
+			}
+			config, err := os.ReadFile(path)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("failed to read %s: %v", path, err)
+			}
+			names := make([]string, 0, len(imported))
+			for n := range imported {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			for _, n := range names {
+				if config, err = profiles.AddToConfig(config, n, imported[n], force); err != nil {
+					if errors.Is(err, profiles.ErrExists) {
+						return fmt.Errorf("%v in %s, use --force to replace it", err, path)
+					}
+					return err
+				}
+			}
+
+			if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+				return fmt.Errorf("failed to create %s: %v", filepath.Dir(path), err)
+			}
+			if err := os.WriteFile(path, config, 0o600); err != nil {
+				return fmt.Errorf("failed to write %s: %v", path, err)
+			}
+			for _, n := range names {
+				fmt.Printf("Imported cluster %s (%s), use it with --cluster %s\n", n, imported[n].Brokers, n)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Format of FILE: kcat or kafkactl (default guessed from the extension)")
+	cmd.Flags().StringVar(&name, "name", "", "Profile name for a kcat config, or the kafkactl context to import")
+	cmd.Flags().BoolVar(&force, "force", false, "Replace profiles that already exist")
+	return cmd
+}
+
+// clusterNames lists the profiles in the config file, sorted
+func clusterNames() []string {
+	var names []string
+	for name := range viper.GetStringMap("clusters") {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/spf13/cobra"
+)
+
+func newCompareCmd() *cobra.Command {
+	var (
+		topics string
+		sync   bool
+		yes    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "compare TARGET_CLUSTER",
+		Short: "Compare topics with another cluster and optionally copy the differences to it",
+		Long: `Compares the topics of the connected cluster, the source, with those of
+TARGET_CLUSTER, a profile from the clusters section of the config file: topics
+missing from either side, partition counts and topic-level config overrides.
+
+With --sync the target is changed to match after confirmation. Missing topics
+are created with the source's partitions, replication factor and configs,
+partitions are added where the target has fewer and config overrides are
+replaced with the source's. Topics only on the target are never deleted.`,
+		Example: `  kconduit --cluster prod compare staging
+  kconduit --cluster prod compare dr --topics '^orders\.' --sync`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var filter *regexp.Regexp
+			if topics != "" {
+				var err error
+				if filter, err = regexp.Compile(topics); err != nil {
+					return fmt.Errorf("invalid --topics: %v", err)
+				}
+			}
+			targetSettings, err := clusterSettings(args[0])
+			if err != nil {
+				return err
+			}
+
+			source, err := connect()
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err := source.Close(); err != nil {
+					log.Printf("Error closing Kafka client: %v", err)
+				}
+			}()
+			target, err := dial(targetSettings)
+			if err != nil {
+				return fmt.Errorf("%s: %w", args[0], err)
+			}
+			defer func() {
+				if err := target.Close(); err != nil {
+					log.Printf("Error closing Kafka client: %v", err)
+				}
+			}()
+
+			sourceTopics, err := source.TopicSnapshots()
+			if err != nil {
+				return err
+			}
+			targetTopics, err := target.TopicSnapshots()
+			if err != nil {
+				return fmt.Errorf("%s: %w", args[0], err)
+			}
+			if filter != nil {
+				for _, snapshots := range []map[string]kafka.TopicSnapshot{sourceTopics, targetTopics} {
+					for name := range snapshots {
+						if !filter.MatchString(name) {
+							delete(snapshots, name)
+						}
+					}
+				}
+			}
+
+			comparison := kafka.CompareTopics(sourceTopics, targetTopics)
+			printTopicComparison(comparison)
+			if !sync || len(comparison.Differences) == 0 {
+				return nil
+			}
+			if !yes && !confirm(fmt.Sprintf("Change %s to match?", args[0])) {
+				return fmt.Errorf("aborted")
+			}
+
+			var failed, synced int
+			for _, d := range comparison.Differences {
+				if d.Source == nil {
+					continue
+				}
+				if err := target.SyncTopic(d); err != nil {
+					failed++
+					fmt.Fprintf(os.Stderr, "Failed to sync %s: %v\n", d.Topic, err)
+					continue
+				}
+				synced++
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d topics could not be synced", failed, failed+synced)
+			}
+			fmt.Printf("Synced %d topics\n", synced)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&topics, "topics", "", "Only compare topics matching this regular expression")
+	cmd.Flags().BoolVar(&sync, "sync", false, "Change the target to match the source")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Sync without asking for confirmation")
+	return cmd
+}
+
+// printTopicComparison prints the differences in a diff-like format: +
+// for topics only on the source, - for topics only on the target and ~ for
+// topics that differ
+func printTopicComparison(comparison kafka.TopicComparison) {
+	var missing, extra, changed int
+	for _, d := range comparison.Differences {
+		switch {
+		case d.Target == nil:
+			missing++
+			fmt.Printf("+ %s (%d partitions, replication factor %d, %d configs) missing from target\n",
+				d.Topic, d.Source.Partitions, d.Source.ReplicationFactor, len(d.Source.Configs))
+		case d.Source == nil:
+			extra++
+			fmt.Printf("- %s only on target\n", d.Topic)
+		default:
+			changed++
+			fmt.Printf("~ %s\n", d.Topic)
+			if d.PartitionsDiffer() {
+				fmt.Printf("    partitions: %d on source, %d on target\n", d.Source.Partitions, d.Target.Partitions)
+			}
+			for _, c := range d.Configs {
+				fmt.Printf("    %s: %s on source, %s on target\n", c.Name, configOrDefault(c.Source), configOrDefault(c.Target))
+			}
+		}
+	}
+	fmt.Printf("\n%d missing from target, %d only on target, %d different, %d identical\n",
+		missing, extra, changed, comparison.Identical)
+}
+
+func configOrDefault(value string) string {
+	if value == "" {
+		return "default"
+	}
+	return value
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/spf13/cobra"
+)
+
+// completionTimeout bounds the cluster lookup behind a completion, so an
+// unreachable cluster does not hang the shell
+const completionTimeout = 5 * time.Second
+
+// completeFunc is the signature cobra calls for dynamic completions
+type completeFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// completeFromCluster completes with the names list returns, connecting
+// with the same flags and config as the command being completed
+func completeFromCluster(list func(client *kafka.Client) ([]string, error)) completeFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeNames(func() ([]string, error) {
+			var names []string
+			err := withClient(func(client *kafka.Client) error {
+				var err error
+				names, err = list(client)
+				return err
+			})
+			return names, err
+		}, toComplete, completionTimeout)
+	}
+}
+
+// completeNames returns the names lookup finds that start with toComplete,
+// giving up when lookup takes longer than timeout
+func completeNames(lookup func() ([]string, error), toComplete string, timeout time.Duration) ([]string, cobra.ShellCompDirective) {
+	type result struct {
+		names []string
+		err   error
+	}
+	found := make(chan result, 1)
+	go func() {
+		names, err := lookup()
+		found <- result{names, err}
+	}()
+
+	select {
+	case r := <-found:
+		if r.err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		var matches []string
+		for _, name := range r.names {
+			if strings.HasPrefix(name, toComplete) {
+				matches = append(matches, name)
+			}
+		}
+		return matches, cobra.ShellCompDirectiveNoFileComp
+	case <-time.After(timeout):
+		return nil, cobra.ShellCompDirectiveError
+	}
+}
+
+// firstArg applies complete to the first positional argument only
+func firstArg(complete completeFunc) completeFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return complete(cmd, args, toComplete)
+	}
+}
+
+// completeTopics completes topic names
+var completeTopics = completeFromCluster(func(client *kafka.Client) ([]string, error) {
+	return client.ListTopics()
+})
+
+// completeGroups completes consumer group IDs
+var completeGroups = completeFromCluster(func(client *kafka.Client) ([]string, error) {
+	groups, err := client.GetConsumerGroups()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(groups))
+	for i, g := range groups {
+		ids[i] = g.GroupID
+	}
+	return ids, nil
+})
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestCompleteNames(t *testing.T) {
+	names := func() ([]string, error) { return []string{"orders", "orders-dlq", "payments"}, nil }
+
+	got, directive := completeNames(names, "ord", time.Second)
+	if !reflect.DeepEqual(got, []string{"orders", "orders-dlq"}) || directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("got %v, %v", got, directive)
+	}
+	if got, _ := completeNames(names, "", time.Second); len(got) != 3 {
+		t.Errorf("empty prefix completed %v, want every name", got)
+	}
+
+	failing := func() ([]string, error) { return nil, errors.New("connection refused") }
+	if got, directive := completeNames(failing, "", time.Second); got != nil || directive != cobra.ShellCompDirectiveError {
+		t.Errorf("failed lookup = %v, %v", got, directive)
+	}
+
+	// An unreachable cluster must not hang the shell
+	block := make(chan struct{})
+	defer close(block)
+	hanging := func() ([]string, error) { <-block; return nil, nil }
+	if _, directive := completeNames(hanging, "", 10*time.Millisecond); directive != cobra.ShellCompDirectiveError {
+		t.Errorf("timed out lookup directive = %v", directive)
+	}
+}
+
+func TestFirstArg(t *testing.T) {
+	called := false
+	complete := firstArg(func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		called = true
+		return []string{"orders"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	if got, _ := complete(nil, nil, ""); !called || len(got) != 1 {
+		t.Errorf("first argument completed %v", got)
+	}
+	called = false
+	if got, directive := complete(nil, []string{"orders"}, ""); called || got != nil || directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("second argument completed %v, %v", got, directive)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// newDoctorCmd returns the "doctor" command diagnosing connection problems
+func newDoctorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check connectivity to each broker and explain what fails",
+		Long: `Connects to each broker step by step: TCP, the TLS handshake, SASL
+authentication, API version negotiation and a metadata fetch. The brokers the
+cluster advertises are checked as well as the bootstrap addresses, since
+clients need to reach both. Failures come with a suggestion of what to change.
+
+Takes the same connection flags, environment and config file as kconduit.`,
+		Example:      `  kconduit doctor -b kafka-1:9093 --tls --sasl --sasl-mechanism SCRAM-SHA-512`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := logger.Init(viper.GetString("log_level"), viper.GetString("log_file")); err != nil {
+				return fmt.Errorf("failed to initialize logger: %v", err)
+			}
+			settings, err := activeSettings()
+			if err != nil {
+				return err
+			}
+			brokers, saslConfig, tlsConfig, netConfig, err := connectionSettings(settings)
+			if err != nil {
+				return err
+			}
+
+			results, err := kafka.Diagnose(brokers, saslConfig, tlsConfig, netConfig)
+			if err != nil {
+				return err
+			}
+
+			failed := 0
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			for _, result := range results {
+				name := result.Address
+				if result.Advertised {
+					name += " (advertised by the cluster)"
+				}
+				fmt.Fprintln(w, name)
+				for _, step := range result.Steps {
+					if step.Err != nil {
+						fmt.Fprintf(w, "  FAIL\t%s\t%s\t%v\n", step.Name, step.Duration.Round(time.Millisecond), step.Err)
+						if step.Hint != "" {
+							fmt.Fprintf(w, "  \t\t\t-> %s\n", step.Hint)
+						}
+						continue
+					}
+					fmt.Fprintf(w, "  ok\t%s\t%s\t%s\n", step.Name, step.Duration.Round(time.Millisecond), step.Detail)
+				}
+				if !result.OK() {
+					failed++
+				}
+			}
+			w.Flush()
+
+			if failed > 0 {
+				return fmt.Errorf("%d of %d brokers failed", failed, len(results))
+			}
+			fmt.Printf("\nAll %d brokers passed\n", len(results))
+			return nil
+		},
+	}
+	return cmd
+}
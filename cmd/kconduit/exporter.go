@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/digitalis-io/kconduit/pkg/exporter"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+// newExporterCmd returns the "exporter" command serving Prometheus metrics
+func newExporterCmd() *cobra.Command {
+	var listen string
+
+	cmd := &cobra.Command{
+		Use:   "exporter",
+		Short: "Serve cluster metrics for Prometheus on /metrics",
+		Long: `Runs an HTTP server exposing broker health, topic partitions and sizes and
+consumer group lag in the Prometheus text format. The cluster is queried on
+every scrape, so set the scrape interval accordingly on large clusters.`,
+		Example: `  kconduit exporter --listen :9308`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(func(client *kafka.Client) error {
+				mux := http.NewServeMux()
+				mux.Handle("/metrics", exporter.New(client))
+				mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path != "/" {
+						http.NotFound(w, r)
+						return
+					}
+					fmt.Fprintln(w, "kconduit exporter, metrics are served on /metrics")
+				})
+
+				logger.Get().WithField("listen", listen).Info("Serving Prometheus metrics")
+				fmt.Printf("Serving metrics on http://%s/metrics\n", listen)
+				return http.ListenAndServe(listen, mux)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&listen, "listen", ":9308", "Address to serve /metrics on")
+	return cmd
+}
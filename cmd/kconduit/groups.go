@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/spf13/cobra"
+)
+
+// newGroupsCmd returns the "groups" command group for consumer group maintenance
+func newGroupsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "groups",
+		Short: "Manage consumer groups",
+	}
+
+	offsets := &cobra.Command{
+		Use:   "offsets",
+		Short: "Export, restore and delete committed offsets",
+	}
+	offsets.AddCommand(newGroupOffsetsExportCmd(), newGroupOffsetsRestoreCmd(), newGroupOffsetsDeleteCmd())
+	cmd.AddCommand(offsets, newGroupsLagCmd())
+	return cmd
+}
+
+func newGroupsLagCmd() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "lag [GROUP...]",
+		Short: "Report the lag of every partition of consumer groups",
+		Long: `Prints a row per group, topic and partition with the committed offset, the
+log end offset and the lag, for the given groups or every consumer group.
+Unknown offsets, e.g. partitions the group never committed, are left empty in
+CSV and null in JSON, so the output can be loaded into a spreadsheet or a
+database on a schedule.`,
+		Example: `  kconduit groups lag
+  kconduit groups lag orders-service billing --output csv > lag.csv
+  kconduit groups lag --output json`,
+		ValidArgsFunction: completeGroups,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch output {
+			case "table", "csv", "json", "yaml":
+			default:
+				return fmt.Errorf("unsupported output %q, use table, csv, json or yaml", output)
+			}
+
+			return withClient(func(client *kafka.Client) error {
+				report, err := client.GetLagReport(args)
+				if err != nil {
+					return err
+				}
+				switch output {
+				case "csv":
+					return report.WriteCSV(os.Stdout)
+				case "json", "yaml":
+					data, err := report.Marshal(output)
+					if err != nil {
+						return err
+					}
+					_, err = os.Stdout.Write(data)
+					return err
+				}
+
+				if len(report.Rows) == 0 {
+					fmt.Fprintln(os.Stderr, "No committed offsets")
+					return nil
+				}
+				cell := func(v *int64) string {
+					if v == nil {
+						return "-"
+					}
+					return fmt.Sprintf("%d", *v)
+				}
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "GROUP\tTOPIC\tPARTITION\tCOMMITTED\tEND\tLAG")
+				for _, row := range report.Rows {
+					fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n", row.Group, row.Topic, row.Partition, cell(row.Committed), cell(row.End), cell(row.Lag))
+				}
+				return w.Flush()
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table, csv, json or yaml")
+	return cmd
+}
+
+func newGroupOffsetsExportCmd() *cobra.Command {
+	var (
+		output string
+		format string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export GROUP",
+		Short: "Export a group's committed offsets to YAML or JSON",
+		Example: `  kconduit groups offsets export orders-service > offsets.yaml
+  kconduit groups offsets export orders-service --output offsets.json
+  kconduit groups offsets export orders-service --format go-template='{{range .offsets}}{{.topic}}/{{.partition}} {{.offset}}{{"\n"}}{{end}}'`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: firstArg(completeGroups),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format == "" {
+				format = "yaml"
+				if output != "" {
+					format = kafka.FileFormatFromPath(output)
+				}
+			}
+			if err := kafka.CheckOutputFormat(format); err != nil {
+				return err
+			}
+
+			return withClient(func(client *kafka.Client) error {
+				snapshot, err := client.GetGroupOffsets(args[0])
+				if err != nil {
+					return err
+				}
+				if len(snapshot.Offsets) == 0 {
+					return fmt.Errorf("group %s has no committed offsets", args[0])
+				}
+				data, err := snapshot.Marshal(format)
+				if err != nil {
+					return err
+				}
+
+				if output == "" {
+					_, err = os.Stdout.Write(data)
+					return err
+				}
+				if err := os.WriteFile(output, data, 0o644); err != nil {
+					return fmt.Errorf("failed to write %s: %v", output, err)
+				}
+				fmt.Fprintf(os.Stderr, "Exported %d offsets of %s to %s\n", len(snapshot.Offsets), args[0], output)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file (default stdout)")
+	cmd.Flags().StringVar(&format, "format", "", outputFormatUsage)
+	return cmd
+}
+
+func newGroupOffsetsRestoreCmd() *cobra.Command {
+	var (
+		group  string
+		format string
+		dryRun bool
+		yes    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "restore FILE",
+		Short: "Commit the offsets from an export, optionally into a different group",
+		Long: `Commits the offsets saved by "groups offsets export". The target group must
+have no running consumers. Use --group to restore into a new group ID, e.g. to
+start a green deployment where the blue one left off.`,
+		Example: `  kconduit groups offsets restore offsets.yaml --dry-run
+  kconduit groups offsets restore offsets.yaml --group orders-service-green --yes`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := args[0]
+			if format == "" {
+				format = kafka.FileFormatFromPath(path)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %v", path, err)
+			}
+			snapshot, err := kafka.UnmarshalGroupOffsets(data, format)
+			if err != nil {
+				return err
+			}
+			target := snapshot.Group
+			if group != "" {
+				target = group
+			}
+			if target == "" {
+				return fmt.Errorf("the file has no group, set one with --group")
+			}
+
+			return withClient(func(client *kafka.Client) error {
+				current, err := client.GetGroupOffsets(target)
+				if err != nil {
+					return err
+				}
+				committed := make(map[string]int64)
+				for _, o := range current.Offsets {
+					committed[fmt.Sprintf("%s/%d", o.Topic, o.Partition)] = o.Offset
+				}
+
+				fmt.Printf("Restoring %d offsets exported from %s at %s into %s\n\n",
+					len(snapshot.Offsets), snapshot.Group, snapshot.ExportedAt.Format("2006-01-02 15:04:05 MST"), target)
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "TOPIC\tPARTITION\tCURRENT\tNEW")
+				for _, o := range snapshot.Offsets {
+					currentOffset := "-"
+					if v, ok := committed[fmt.Sprintf("%s/%d", o.Topic, o.Partition)]; ok {
+						currentOffset = fmt.Sprintf("%d", v)
+					}
+					fmt.Fprintf(w, "%s\t%d\t%s\t%d\n", o.Topic, o.Partition, currentOffset, o.Offset)
+				}
+				w.Flush()
+				fmt.Println()
+
+				if dryRun {
+					return nil
+				}
+				if !yes && !confirm(fmt.Sprintf("Commit these offsets for %s?", target)) {
+					return fmt.Errorf("aborted")
+				}
+				if err := client.RestoreGroupOffsets(target, snapshot.Offsets); err != nil {
+					return err
+				}
+				fmt.Printf("Restored %d offsets for %s\n", len(snapshot.Offsets), target)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&group, "group", "g", "", "Target group ID (default the exported group)")
+	_ = cmd.RegisterFlagCompletionFunc("group", completeGroups)
+	cmd.Flags().StringVar(&format, "format", "", "Input format: yaml or json (default from the file extension)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only show the offsets that would be committed")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Restore without asking for confirmation")
+	return cmd
+}
+
+func newGroupOffsetsDeleteCmd() *cobra.Command {
+	var (
+		topic string
+		yes   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "delete GROUP",
+		Short: "Delete a group's committed offsets for one topic",
+		Long: `Removes the committed offsets of GROUP for every partition of --topic, so a
+topic the group no longer consumes stops showing up in its offsets and lag.
+The rest of the group is left untouched.`,
+		Example:           `  kconduit groups offsets delete orders-service --topic legacy-orders`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: firstArg(completeGroups),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			groupID := args[0]
+			if !yes && !confirm(fmt.Sprintf("Delete the committed offsets of %s for topic %s?", groupID, topic)) {
+				return fmt.Errorf("aborted")
+			}
+
+			return withClient(func(client *kafka.Client) error {
+				partitions, err := client.DeleteGroupTopicOffsets(groupID, topic)
+				if len(partitions) > 0 {
+					fmt.Printf("Deleted offsets of %s for %s partitions %v\n", groupID, topic, partitions)
+				}
+				return err
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&topic, "topic", "t", "", "Topic whose offsets are deleted")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Delete without asking for confirmation")
+	_ = cmd.MarkFlagRequired("topic")
+	_ = cmd.RegisterFlagCompletionFunc("topic", completeTopics)
+	return cmd
+}
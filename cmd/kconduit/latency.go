@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+	"time"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/spf13/cobra"
+)
+
+// newLatencyCmd returns the "latency" command measuring produce and consume
+// round trips
+func newLatencyCmd() *cobra.Command {
+	var opts kafka.LatencyOptions
+
+	cmd := &cobra.Command{
+		Use:   "latency TOPIC",
+		Short: "Measure end-to-end latency with probe messages",
+		Long: `Produces timestamped probe messages to TOPIC while consuming it, and reports
+percentiles of how long the brokers took to acknowledge each probe and how long
+it took to be consumed back. Use a topic set aside for probes: the messages
+stay in it, marked with a "` + kafka.ProbeHeader + `" header.`,
+		Example: `  kconduit latency kconduit-probes
+  kconduit latency kconduit-probes --count 100 --interval 50ms`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: firstArg(completeTopics),
+		SilenceUsage:      true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			return withClient(func(client *kafka.Client) error {
+				opts.OnSample = func(seq int, sample kafka.LatencySample) {
+					fmt.Printf("probe %d: produce %s, round trip %s\n", seq,
+						sample.Produce.Round(time.Microsecond), sample.RoundTrip.Round(time.Microsecond))
+				}
+				result, err := client.ProbeLatency(ctx, args[0], opts)
+				if err != nil {
+					return err
+				}
+
+				fmt.Println()
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "\tP50\tP90\tP99\tMAX")
+				for _, row := range []struct {
+					name string
+					p    kafka.LatencyPercentiles
+				}{{"Produce", result.Produce}, {"Round trip", result.RoundTrip}} {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", row.name, formatLatency(row.p.P50), formatLatency(row.p.P90),
+						formatLatency(row.p.P99), formatLatency(row.p.Max))
+				}
+				w.Flush()
+
+				if lost := result.Sent - result.Received; lost > 0 {
+					return fmt.Errorf("%d of %d probes did not come back within %s", lost, result.Sent, opts.Timeout)
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().IntVarP(&opts.Count, "count", "n", 10, "Number of probes to send")
+	cmd.Flags().DurationVar(&opts.Interval, "interval", 200*time.Millisecond, "Pause between probes")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 10*time.Second, "How long to wait for the last probe to come back")
+	return cmd
+}
+
+// formatLatency rounds a duration to a readable precision
+func formatLatency(d time.Duration) string {
+	if d < time.Millisecond {
+		return d.Round(time.Microsecond).String()
+	}
+	return d.Round(100 * time.Microsecond).String()
+}
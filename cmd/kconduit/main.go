@@ -1,13 +1,24 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/digitalis-io/kconduit/pkg/ai"
+	"github.com/digitalis-io/kconduit/pkg/alerts"
+	"github.com/digitalis-io/kconduit/pkg/audit"
 	"github.com/digitalis-io/kconduit/pkg/kafka"
 	"github.com/digitalis-io/kconduit/pkg/logger"
+	"github.com/digitalis-io/kconduit/pkg/schemaregistry"
+	"github.com/digitalis-io/kconduit/pkg/secrets"
+	"github.com/digitalis-io/kconduit/pkg/topictags"
+	"github.com/digitalis-io/kconduit/pkg/tracing"
 	"github.com/digitalis-io/kconduit/pkg/ui"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
@@ -29,9 +40,57 @@ var (
 	cfgTlsCACert     string
 	cfgTlsClientCert string
 	cfgTlsClientKey  string
+	cfgTlsKeyPass    string
 	cfgTlsSkipVerify bool
+	cfgMaxMessages   int
+	cfgAlertRules    string
+	cfgProbeTopic    string
+	cfgAuditLog      string
+	cfgRecord        string
+	cfgOTLPEndpoint  string
+	cfgConfigFile    string
+	cfgCluster       string
+	cfgTopicCacheTTL time.Duration
+	cfgNoEmoji       bool
 )
 
+// shutdownTracing flushes spans when tracing was enabled by connect
+var shutdownTracing func(context.Context) error
+
+// Schema Registry settings
+var (
+	cfgSchemaRegistryURL      string
+	cfgSchemaRegistryUsername string
+	cfgSchemaRegistryPassword string
+)
+
+// Java keystore settings, an alternative to the PEM certificate and key
+var (
+	cfgTlsKeystore           string
+	cfgTlsKeystorePassword   string
+	cfgTlsTruststore         string
+	cfgTlsTruststorePassword string
+)
+
+// SSH tunnel settings, for brokers only reachable through a bastion
+var (
+	cfgSSHHost          string
+	cfgSSHUser          string
+	cfgSSHKey           string
+	cfgSSHKeyPassphrase string
+	cfgSSHKnownHosts    string
+	cfgSSHIgnoreHostKey bool
+)
+
+// Proxy settings, for networks that only allow outbound traffic through one
+var (
+	cfgProxy   string
+	cfgAIProxy string
+)
+
+// Broker address overrides, for clusters that advertise internal addresses
+var cfgBrokerAddressMap map[string]string
+
 // These variables are set via ldflags during build
 var (
 	Version   = "dev"
@@ -55,63 +114,48 @@ func main() {
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Merge Viper and flags
-			brokers := viper.GetString("brokers")
-			logLevel := viper.GetString("log_level")
-			logFile := viper.GetString("log_file")
 			aiEngine := viper.GetString("ai_engine")
 			aiModel := viper.GetString("ai_model")
-			saslEnabled := viper.GetBool("sasl_enabled")
-			saslMechanism := viper.GetString("sasl_mechanism")
-			saslUsername := viper.GetString("sasl_username")
-			saslPassword := viper.GetString("sasl_password")
-			saslProtocol := viper.GetString("sasl_protocol")
-			tlsEnabled := viper.GetBool("tls_enabled")
-			tlsCACert := viper.GetString("tls_ca_cert")
-			tlsClientCert := viper.GetString("tls_client_cert")
-			tlsClientKey := viper.GetString("tls_client_key")
-			tlsSkipVerify := viper.GetBool("tls_skip_verify")
-			// Version flag is handled before RunE, so this code path won't be reached
-			// when --version is used
-
-			// Initialize logger
-			if err := logger.Init(logLevel, logFile); err != nil {
-				return fmt.Errorf("failed to initialize logger: %v", err)
+			maxMessages := viper.GetInt("max_messages")
+			schemaRegistryURL := viper.GetString("schema_registry_url")
+			schemaRegistryUsername := viper.GetString("schema_registry_username")
+			schemaRegistryPassword, err := resolveSecret("schema_registry_password")
+			if err != nil {
+				return err
 			}
-
-			// Parse brokers list
-			brokerList := strings.Split(brokers, ",")
-			for i := range brokerList {
-				brokerList[i] = strings.TrimSpace(brokerList[i])
+			// Resolved before the UI starts, a secret command may prompt
+			if err := resolveSecretEnv("OPENAI_API_KEY", "GEMINI_API_KEY", "ANTHROPIC_API_KEY"); err != nil {
+				return err
 			}
-
-			// Create SASL config if authentication is enabled
-			var saslConfig *kafka.SASLConfig
-			if saslEnabled {
-				saslConfig = &kafka.SASLConfig{
-					Enabled:   true,
-					Mechanism: saslMechanism,
-					Username:  saslUsername,
-					Password:  saslPassword,
-					Protocol:  saslProtocol,
-				}
+			if err := configureAIProxy(); err != nil {
+				return err
 			}
-
-			// Create TLS config if SSL is enabled or SASL_SSL is used
-			var tlsConfig *kafka.TLSConfig
-			if tlsEnabled || (saslConfig != nil && saslProtocol == "SASL_SSL") {
-				tlsConfig = &kafka.TLSConfig{
-					Enabled:            true,
-					CACert:             tlsCACert,
-					ClientCert:         tlsClientCert,
-					ClientKey:          tlsClientKey,
-					InsecureSkipVerify: tlsSkipVerify,
+			alertRules := viper.GetString("alert_rules")
+			var aiPolicy ai.Policy
+			if err := viper.UnmarshalKey("ai_policy", &aiPolicy); err != nil {
+				return fmt.Errorf("invalid ai_policy in config file: %w", err)
+			}
+			if err := aiPolicy.Validate(); err != nil {
+				return err
+			}
+			var aiPlugins []ui.AIPluginConfig
+			if err := viper.UnmarshalKey("ai_plugins", &aiPlugins); err != nil {
+				return fmt.Errorf("invalid ai_plugins in config file: %w", err)
+			}
+			for _, cfg := range aiPlugins {
+				plugin, err := ui.NewAIPlugin(cfg)
+				if err != nil {
+					return err
 				}
+				ui.RegisterAIProvider(plugin)
+				defer func() { _ = plugin.Close() }()
 			}
+			// Version flag is handled before RunE, so this code path won't be reached
+			// when --version is used
 
-			// Kafka client with optional SASL authentication and TLS
-			client, err := kafka.NewClientWithAuth(brokerList, saslConfig, tlsConfig)
+			client, err := connect()
 			if err != nil {
-				return fmt.Errorf("failed to connect to Kafka: %v", err)
+				return err
 			}
 			defer func() {
 				if err := client.Close(); err != nil {
@@ -119,8 +163,50 @@ func main() {
 				}
 			}()
 
+			client.SetTopicCacheTTL(viper.GetDuration("topic_cache_ttl"))
+			if cfgRecord != "" {
+				recorder, err := kafka.NewSessionRecorder(cfgRecord)
+				if err != nil {
+					return err
+				}
+				client.RecordSession(recorder)
+				defer func() {
+					fmt.Printf("Recorded %d changes to %s, replay them with: kconduit replay %s\n", recorder.Len(), recorder.Path(), recorder.Path())
+				}()
+			}
+
 			// Run UI
+			ui.MaxConsumerMessages = maxMessages
 			model := ui.NewModel(client, aiEngine, aiModel)
+			if schemaRegistryURL != "" {
+				model = model.WithSchemaRegistry(schemaregistry.NewClient(schemaRegistryURL, schemaRegistryUsername, schemaRegistryPassword))
+			}
+			if alertRules != "" {
+				cfg, err := alerts.LoadConfig(alertRules)
+				if err != nil {
+					return err
+				}
+				if cfg.Cluster == "" {
+					cfg.Cluster = clusterLabel()
+				}
+				model = model.WithAlerts(cfg)
+			}
+			if path, err := topictags.DefaultPath(); err == nil {
+				store, err := topictags.Load(path)
+				if err != nil {
+					return err
+				}
+				settings, err := activeSettings()
+				if err != nil {
+					return err
+				}
+				// Keyed by the bootstrap brokers, so --cluster and -b share the marks
+				model = model.WithTopicTags(store, settings.GetString("brokers"))
+			}
+			if topic := viper.GetString("latency_probe_topic"); topic != "" {
+				model = model.WithLatencyProbe(topic)
+			}
+			model = model.WithAIPolicy(aiPolicy)
 			p := tea.NewProgram(model, tea.WithAltScreen())
 			if _, err := p.Run(); err != nil {
 				return fmt.Errorf("error running program: %v", err)
@@ -130,54 +216,368 @@ func main() {
 		},
 	}
 
-	// Define flags
-	rootCmd.Flags().StringVarP(&cfgBrokers, "brokers", "b", "localhost:9092", "Comma-separated list of Kafka broker addresses")
-	rootCmd.Flags().StringVar(&cfgLogLevel, "log-level", "info", "Log level (debug, info, warn, error)")
-	rootCmd.Flags().StringVar(&cfgLogFile, "log-file", "", "Log file path (if empty, logs to stderr)")
-	rootCmd.Flags().StringVar(&cfgAiEngine, "ai-engine", "gemini", "AI engine to use (e.g., openai)")
+	rootCmd.AddCommand(newProduceCmd(), newACLsCmd(), newGroupsCmd(), newClustersCmd(), newCompareCmd(), newStrimziCmd(), newExporterCmd(), newDoctorCmd(), newLatencyCmd(), newBenchmarkCmd(), newReplayCmd())
+
+	cobra.OnInitialize(initConfig)
+	rootCmd.PersistentFlags().StringVar(&cfgConfigFile, "config", "", "Config file (default kconduit/config.yaml in the user config directory, e.g. ~/.config)")
+
+	// Connection flags are shared with subcommands
+	rootCmd.PersistentFlags().StringVarP(&cfgBrokers, "brokers", "b", "localhost:9092", "Comma-separated list of Kafka broker addresses")
+	rootCmd.PersistentFlags().StringVar(&cfgCluster, "cluster", "", "Connect with a cluster profile from the clusters section of the config file instead of the connection flags")
+	rootCmd.PersistentFlags().StringVar(&cfgLogLevel, "log-level", "info", "Log level (debug, info, warn, error)")
+	rootCmd.PersistentFlags().StringVar(&cfgLogFile, "log-file", "", "Log file path (if empty, logs to stderr)")
+	rootCmd.PersistentFlags().StringVar(&cfgAuditLog, "audit-log", "", "Append a JSON line for every change made to the cluster to this file")
+	rootCmd.PersistentFlags().BoolVar(&cfgNoEmoji, "no-emoji", false, "Draw with ASCII instead of emoji and box-drawing characters (also chosen automatically for non-UTF-8 locales and the Linux console)")
+	rootCmd.PersistentFlags().StringVar(&cfgOTLPEndpoint, "otlp-endpoint", "", "OTLP/HTTP collector URL to send traces of Kafka calls to, e.g. http://localhost:4318")
+	rootCmd.Flags().StringVar(&cfgAiEngine, "ai-engine", "gemini", "AI engine to use (openai, gemini, anthropic, ollama or an ai_plugins name)")
 	rootCmd.Flags().StringVar(&cfgAiModel, "ai-model", "gemini-1.5-pro-latest", "AI model to use (e.g., gpt-3.5-turbo, gpt-4)")
 
 	// SASL authentication flags
-	rootCmd.Flags().BoolVar(&cfgSaslEnabled, "sasl", false, "Enable SASL authentication")
-	rootCmd.Flags().StringVar(&cfgSaslMechanism, "sasl-mechanism", "PLAIN", "SASL mechanism (PLAIN, SCRAM-SHA-256, SCRAM-SHA-512)")
-	rootCmd.Flags().StringVar(&cfgSaslUsername, "sasl-username", "", "SASL username")
-	rootCmd.Flags().StringVar(&cfgSaslPassword, "sasl-password", "", "SASL password")
-	rootCmd.Flags().StringVar(&cfgSaslProtocol, "sasl-protocol", "SASL_PLAINTEXT", "Security protocol (SASL_PLAINTEXT, SASL_SSL)")
+	rootCmd.PersistentFlags().BoolVar(&cfgSaslEnabled, "sasl", false, "Enable SASL authentication")
+	rootCmd.PersistentFlags().StringVar(&cfgSaslMechanism, "sasl-mechanism", "PLAIN", "SASL mechanism (PLAIN, SCRAM-SHA-256, SCRAM-SHA-512)")
+	rootCmd.PersistentFlags().StringVar(&cfgSaslUsername, "sasl-username", "", "SASL username")
+	rootCmd.PersistentFlags().StringVar(&cfgSaslPassword, "sasl-password", "", "SASL password, or a keyring:, cmd: or file: reference to it")
+	rootCmd.PersistentFlags().StringVar(&cfgSaslProtocol, "sasl-protocol", "SASL_PLAINTEXT", "Security protocol (SASL_PLAINTEXT, SASL_SSL)")
 
 	// TLS/SSL flags
-	rootCmd.Flags().BoolVar(&cfgTlsEnabled, "tls", false, "Enable TLS/SSL")
-	rootCmd.Flags().StringVar(&cfgTlsCACert, "tls-ca-cert", "", "Path to CA certificate file")
-	rootCmd.Flags().StringVar(&cfgTlsClientCert, "tls-client-cert", "", "Path to client certificate file")
-	rootCmd.Flags().StringVar(&cfgTlsClientKey, "tls-client-key", "", "Path to client key file")
-	rootCmd.Flags().BoolVar(&cfgTlsSkipVerify, "tls-skip-verify", false, "Skip TLS certificate verification (insecure)")
+	rootCmd.PersistentFlags().BoolVar(&cfgTlsEnabled, "tls", false, "Enable TLS/SSL")
+	rootCmd.PersistentFlags().StringVar(&cfgTlsCACert, "tls-ca-cert", "", "Path to CA certificate file")
+	rootCmd.PersistentFlags().StringVar(&cfgTlsClientCert, "tls-client-cert", "", "Path to client certificate file")
+	rootCmd.PersistentFlags().StringVar(&cfgTlsClientKey, "tls-client-key", "", "Path to client key file")
+	rootCmd.PersistentFlags().StringVar(&cfgTlsKeyPass, "tls-client-key-password", "", "Password of an encrypted client key, or a keyring:, cmd: or file: reference to it")
+	rootCmd.PersistentFlags().StringVar(&cfgTlsKeystore, "tls-keystore", "", "PKCS12 or JKS keystore with the client certificate and key, instead of --tls-client-cert and --tls-client-key")
+	rootCmd.PersistentFlags().StringVar(&cfgTlsKeystorePassword, "tls-keystore-password", "", "Keystore password, or a keyring:, cmd: or file: reference to it")
+	rootCmd.PersistentFlags().StringVar(&cfgTlsTruststore, "tls-truststore", "", "PKCS12 or JKS truststore with the CA certificates, instead of --tls-ca-cert")
+	rootCmd.PersistentFlags().StringVar(&cfgTlsTruststorePassword, "tls-truststore-password", "", "Truststore password, or a keyring:, cmd: or file: reference to it")
+	rootCmd.PersistentFlags().BoolVar(&cfgTlsSkipVerify, "tls-skip-verify", false, "Skip TLS certificate verification (insecure)")
+
+	// SSH tunnel flags
+	rootCmd.PersistentFlags().StringVar(&cfgSSHHost, "ssh-host", "", "Reach the brokers through this SSH bastion (host or host:port)")
+	rootCmd.PersistentFlags().StringVar(&cfgSSHUser, "ssh-user", "", "SSH user (defaults to the current user)")
+	rootCmd.PersistentFlags().StringVar(&cfgSSHKey, "ssh-key", "", "SSH private key (defaults to the SSH agent and ~/.ssh keys)")
+	rootCmd.PersistentFlags().StringVar(&cfgSSHKeyPassphrase, "ssh-key-passphrase", "", "Passphrase of the SSH key, or a keyring:, cmd: or file: reference to it")
+	rootCmd.PersistentFlags().StringVar(&cfgSSHKnownHosts, "ssh-known-hosts", "", "known_hosts file to verify the bastion with (defaults to ~/.ssh/known_hosts)")
+	rootCmd.PersistentFlags().BoolVar(&cfgSSHIgnoreHostKey, "ssh-insecure-ignore-host-key", false, "Skip verifying the bastion's host key (insecure)")
+
+	// Proxy flags
+	rootCmd.PersistentFlags().StringVar(&cfgProxy, "proxy", "", "Reach the brokers and AI providers through this proxy (socks5://, socks5h:// or http:// URL)")
+	rootCmd.Flags().StringVar(&cfgAIProxy, "ai-proxy", "", "Proxy for the AI providers only, overriding --proxy and HTTPS_PROXY")
+	rootCmd.PersistentFlags().StringToStringVar(&cfgBrokerAddressMap, "broker-address-map", nil, "Dial advertised broker addresses at reachable ones instead (advertised=reachable,...)")
+
+	// Consumer flags
+	rootCmd.Flags().IntVar(&cfgMaxMessages, "max-messages", 10000, "Maximum messages retained by the consumer view, older ones are dropped (0 for unlimited)")
+	rootCmd.Flags().StringVar(&cfgRecord, "record", "", "Record every change made in the session to this YAML script, for kconduit replay")
+	rootCmd.Flags().DurationVar(&cfgTopicCacheTTL, "topic-cache-ttl", kafka.DefaultTopicCacheTTL, "How long the topic list is reused before it is fetched again (0 disables caching)")
+
+	// Schema Registry flags
+	rootCmd.Flags().StringVar(&cfgSchemaRegistryURL, "schema-registry-url", "", "Schema Registry URL, enables Avro encoding in the producer")
+	rootCmd.Flags().StringVar(&cfgSchemaRegistryUsername, "schema-registry-username", "", "Schema Registry basic auth username")
+	rootCmd.Flags().StringVar(&cfgSchemaRegistryPassword, "schema-registry-password", "", "Schema Registry basic auth password, or a keyring:, cmd: or file: reference to it")
+
+	// Alerting flags
+	rootCmd.Flags().StringVar(&cfgAlertRules, "alert-rules", "", "YAML file with alert rules evaluated in the background")
+	rootCmd.Flags().StringVar(&cfgProbeTopic, "latency-probe-topic", "", "Topic to send latency probes through, shown on the dashboard")
 
 	// Version flag
 	rootCmd.Flags().BoolP("version", "v", false, "Print version information and exit")
 
 	// Bind Viper to flags
-	_ = viper.BindPFlag("brokers", rootCmd.Flags().Lookup("brokers"))
-	_ = viper.BindPFlag("log_level", rootCmd.Flags().Lookup("log-level"))
-	_ = viper.BindPFlag("log_file", rootCmd.Flags().Lookup("log-file"))
+	_ = viper.BindPFlag("brokers", rootCmd.PersistentFlags().Lookup("brokers"))
+	_ = viper.BindPFlag("cluster", rootCmd.PersistentFlags().Lookup("cluster"))
+	_ = viper.BindPFlag("log_level", rootCmd.PersistentFlags().Lookup("log-level"))
+	_ = viper.BindPFlag("log_file", rootCmd.PersistentFlags().Lookup("log-file"))
+	_ = viper.BindPFlag("audit_log", rootCmd.PersistentFlags().Lookup("audit-log"))
+	_ = viper.BindPFlag("no_emoji", rootCmd.PersistentFlags().Lookup("no-emoji"))
+	_ = viper.BindPFlag("otlp_endpoint", rootCmd.PersistentFlags().Lookup("otlp-endpoint"))
 	_ = viper.BindPFlag("ai_engine", rootCmd.Flags().Lookup("ai-engine"))
 	_ = viper.BindPFlag("ai_model", rootCmd.Flags().Lookup("ai-model"))
-	_ = viper.BindPFlag("sasl_enabled", rootCmd.Flags().Lookup("sasl"))
-	_ = viper.BindPFlag("sasl_mechanism", rootCmd.Flags().Lookup("sasl-mechanism"))
-	_ = viper.BindPFlag("sasl_username", rootCmd.Flags().Lookup("sasl-username"))
-	_ = viper.BindPFlag("sasl_password", rootCmd.Flags().Lookup("sasl-password"))
-	_ = viper.BindPFlag("sasl_protocol", rootCmd.Flags().Lookup("sasl-protocol"))
-	_ = viper.BindPFlag("tls_enabled", rootCmd.Flags().Lookup("tls"))
-	_ = viper.BindPFlag("tls_ca_cert", rootCmd.Flags().Lookup("tls-ca-cert"))
-	_ = viper.BindPFlag("tls_client_cert", rootCmd.Flags().Lookup("tls-client-cert"))
-	_ = viper.BindPFlag("tls_client_key", rootCmd.Flags().Lookup("tls-client-key"))
-	_ = viper.BindPFlag("tls_skip_verify", rootCmd.Flags().Lookup("tls-skip-verify"))
+	_ = viper.BindPFlag("sasl_enabled", rootCmd.PersistentFlags().Lookup("sasl"))
+	_ = viper.BindPFlag("sasl_mechanism", rootCmd.PersistentFlags().Lookup("sasl-mechanism"))
+	_ = viper.BindPFlag("sasl_username", rootCmd.PersistentFlags().Lookup("sasl-username"))
+	_ = viper.BindPFlag("sasl_password", rootCmd.PersistentFlags().Lookup("sasl-password"))
+	_ = viper.BindPFlag("sasl_protocol", rootCmd.PersistentFlags().Lookup("sasl-protocol"))
+	_ = viper.BindPFlag("tls_enabled", rootCmd.PersistentFlags().Lookup("tls"))
+	_ = viper.BindPFlag("tls_ca_cert", rootCmd.PersistentFlags().Lookup("tls-ca-cert"))
+	_ = viper.BindPFlag("tls_client_cert", rootCmd.PersistentFlags().Lookup("tls-client-cert"))
+	_ = viper.BindPFlag("tls_client_key", rootCmd.PersistentFlags().Lookup("tls-client-key"))
+	_ = viper.BindPFlag("tls_client_key_password", rootCmd.PersistentFlags().Lookup("tls-client-key-password"))
+	_ = viper.BindPFlag("tls_keystore", rootCmd.PersistentFlags().Lookup("tls-keystore"))
+	_ = viper.BindPFlag("tls_keystore_password", rootCmd.PersistentFlags().Lookup("tls-keystore-password"))
+	_ = viper.BindPFlag("tls_truststore", rootCmd.PersistentFlags().Lookup("tls-truststore"))
+	_ = viper.BindPFlag("tls_truststore_password", rootCmd.PersistentFlags().Lookup("tls-truststore-password"))
+	_ = viper.BindPFlag("tls_skip_verify", rootCmd.PersistentFlags().Lookup("tls-skip-verify"))
+	_ = viper.BindPFlag("ssh_host", rootCmd.PersistentFlags().Lookup("ssh-host"))
+	_ = viper.BindPFlag("ssh_user", rootCmd.PersistentFlags().Lookup("ssh-user"))
+	_ = viper.BindPFlag("ssh_key", rootCmd.PersistentFlags().Lookup("ssh-key"))
+	_ = viper.BindPFlag("ssh_key_passphrase", rootCmd.PersistentFlags().Lookup("ssh-key-passphrase"))
+	_ = viper.BindPFlag("ssh_known_hosts", rootCmd.PersistentFlags().Lookup("ssh-known-hosts"))
+	_ = viper.BindPFlag("ssh_insecure_ignore_host_key", rootCmd.PersistentFlags().Lookup("ssh-insecure-ignore-host-key"))
+	_ = viper.BindPFlag("proxy", rootCmd.PersistentFlags().Lookup("proxy"))
+	_ = viper.BindPFlag("ai_proxy", rootCmd.Flags().Lookup("ai-proxy"))
+	_ = viper.BindPFlag("broker_address_map", rootCmd.PersistentFlags().Lookup("broker-address-map"))
+	_ = viper.BindPFlag("max_messages", rootCmd.Flags().Lookup("max-messages"))
+	_ = viper.BindPFlag("topic_cache_ttl", rootCmd.Flags().Lookup("topic-cache-ttl"))
+	_ = viper.BindPFlag("schema_registry_url", rootCmd.Flags().Lookup("schema-registry-url"))
+	_ = viper.BindPFlag("schema_registry_username", rootCmd.Flags().Lookup("schema-registry-username"))
+	_ = viper.BindPFlag("schema_registry_password", rootCmd.Flags().Lookup("schema-registry-password"))
+	_ = viper.BindPFlag("alert_rules", rootCmd.Flags().Lookup("alert-rules"))
+	_ = viper.BindPFlag("latency_probe_topic", rootCmd.Flags().Lookup("latency-probe-topic"))
 	_ = viper.BindPFlag("version", rootCmd.Flags().Lookup("version"))
 
 	// Environment variable support
 	viper.SetEnvPrefix("KCONDUIT") // e.g. KCONDUIT_BROKERS
 	viper.AutomaticEnv()
 
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	if shutdownTracing != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := shutdownTracing(ctx); err != nil {
+			log.Printf("Error flushing traces: %v", err)
+		}
+		cancel()
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
 }
+
+// initConfig loads the config file. Its keys match the flag names with
+// underscores, e.g. "brokers" or "sasl_username"; flags and KCONDUIT_*
+// environment variables take precedence. A missing default file is ignored.
+func initConfig() {
+	if cfgConfigFile != "" {
+		viper.SetConfigFile(cfgConfigFile)
+	} else {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return
+		}
+		viper.AddConfigPath(filepath.Join(dir, "kconduit"))
+		viper.SetConfigName("config")
+		viper.SetConfigType("yaml")
+	}
+
+	if err := viper.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if cfgConfigFile == "" && errors.As(err, &notFound) {
+			return
+		}
+		fmt.Fprintf(os.Stderr, "Error reading config file: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// connect initializes logging and creates a Kafka client from the connection
+// flags and environment
+func connect() (*kafka.Client, error) {
+	if err := logger.Init(viper.GetString("log_level"), viper.GetString("log_file")); err != nil {
+		return nil, fmt.Errorf("failed to initialize logger: %v", err)
+	}
+	ui.ASCIIOnly = viper.GetBool("no_emoji") || ui.DetectASCII()
+
+	if endpoint := viper.GetString("otlp_endpoint"); endpoint != "" && shutdownTracing == nil {
+		shutdown, err := tracing.Init(context.Background(), endpoint, Version)
+		if err != nil {
+			return nil, err
+		}
+		shutdownTracing = shutdown
+	}
+
+	settings, err := activeSettings()
+	if err != nil {
+		return nil, err
+	}
+	return dial(settings)
+}
+
+// dial creates a Kafka client from connection settings, either the global
+// ones or a cluster profile's
+func dial(settings *viper.Viper) (*kafka.Client, error) {
+	brokerList, saslConfig, tlsConfig, netConfig, err := connectionSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	// Topic defaults and guardrails and producer options are set per
+	// cluster profile
+	var topicPolicy kafka.TopicPolicy
+	if err := settings.UnmarshalKey("topic_policy", &topicPolicy); err != nil {
+		return nil, fmt.Errorf("invalid topic_policy: %w", err)
+	}
+	if err := topicPolicy.Validate(); err != nil {
+		return nil, err
+	}
+	var producerSettings kafka.ProducerSettings
+	if err := settings.UnmarshalKey("producer", &producerSettings); err != nil {
+		return nil, fmt.Errorf("invalid producer settings: %w", err)
+	}
+	if err := producerSettings.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Kafka client with optional SASL authentication, TLS and tunnel
+	client, err := kafka.NewClientWithAuth(brokerList, saslConfig, tlsConfig, netConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Kafka: %v", err)
+	}
+	client.SetTopicPolicy(topicPolicy)
+	if producerSettings != (kafka.ProducerSettings{}) {
+		if err := client.SetProducerSettings(producerSettings); err != nil {
+			_ = client.Close()
+			return nil, err
+		}
+	}
+
+	if path := viper.GetString("audit_log"); path != "" {
+		auditLog, err := audit.Open(path)
+		if err != nil {
+			_ = client.Close()
+			return nil, err
+		}
+		client.SetAuditLog(auditLog)
+	}
+	return client, nil
+}
+
+// connectionSettings reads the brokers, authentication, TLS and network
+// settings from settings
+func connectionSettings(settings *viper.Viper) ([]string, *kafka.SASLConfig, *kafka.TLSConfig, *kafka.NetworkConfig, error) {
+	// Parse brokers list
+	brokerList := strings.Split(settings.GetString("brokers"), ",")
+	for i := range brokerList {
+		brokerList[i] = strings.TrimSpace(brokerList[i])
+	}
+
+	// Create SASL config if authentication is enabled
+	var saslConfig *kafka.SASLConfig
+	saslProtocol := settings.GetString("sasl_protocol")
+	if settings.GetBool("sasl_enabled") {
+		password, err := resolveSetting(settings, "sasl_password")
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		saslConfig = &kafka.SASLConfig{
+			Enabled:   true,
+			Mechanism: settings.GetString("sasl_mechanism"),
+			Username:  settings.GetString("sasl_username"),
+			Password:  password,
+			Protocol:  saslProtocol,
+		}
+	}
+
+	// Create TLS config if SSL is enabled or SASL_SSL is used
+	var tlsConfig *kafka.TLSConfig
+	if settings.GetBool("tls_enabled") || (saslConfig != nil && saslProtocol == "SASL_SSL") {
+		var passwords [3]string
+		for i, key := range []string{"tls_client_key_password", "tls_keystore_password", "tls_truststore_password"} {
+			password, err := resolveSetting(settings, key)
+			if err != nil {
+				return nil, nil, nil, nil, err
+			}
+			passwords[i] = password
+		}
+		tlsConfig = &kafka.TLSConfig{
+			Enabled:            true,
+			CACert:             settings.GetString("tls_ca_cert"),
+			ClientCert:         settings.GetString("tls_client_cert"),
+			ClientKey:          settings.GetString("tls_client_key"),
+			ClientKeyPassword:  passwords[0],
+			Keystore:           settings.GetString("tls_keystore"),
+			KeystorePassword:   passwords[1],
+			Truststore:         settings.GetString("tls_truststore"),
+			TruststorePassword: passwords[2],
+			InsecureSkipVerify: settings.GetBool("tls_skip_verify"),
+		}
+	}
+
+	// Dial through a proxy, tunnel through an SSH bastion and rewrite broker
+	// addresses if set. The proxy URL may carry credentials, so it can be a
+	// secret reference too.
+	proxyURL, err := resolveSetting(settings, "proxy")
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	netConfig := &kafka.NetworkConfig{
+		Proxy:           proxyURL,
+		BrokerAddresses: settings.GetStringMapString("broker_address_map"),
+	}
+	if host := settings.GetString("ssh_host"); host != "" {
+		passphrase, err := resolveSetting(settings, "ssh_key_passphrase")
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		netConfig.SSH = &kafka.SSHConfig{
+			Host:                  host,
+			User:                  settings.GetString("ssh_user"),
+			KeyFile:               settings.GetString("ssh_key"),
+			KeyPassphrase:         passphrase,
+			KnownHosts:            settings.GetString("ssh_known_hosts"),
+			InsecureIgnoreHostKey: settings.GetBool("ssh_insecure_ignore_host_key"),
+		}
+	}
+
+	return brokerList, saslConfig, tlsConfig, netConfig, nil
+}
+
+// resolveSecret reads a setting that may hold a keyring:, cmd: or file:
+// reference instead of the secret itself
+func resolveSecret(key string) (string, error) {
+	return resolveSetting(viper.GetViper(), key)
+}
+
+// resolveSetting is resolveSecret for a setting of settings
+func resolveSetting(settings *viper.Viper, key string) (string, error) {
+	value, err := secrets.Resolve(settings.GetString(key))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", key, err)
+	}
+	return value, nil
+}
+
+// configureAIProxy points the AI clients at --ai-proxy, or --proxy when that
+// is unset. Without either they follow HTTPS_PROXY as before.
+func configureAIProxy() error {
+	key := "ai_proxy"
+	if viper.GetString(key) == "" {
+		key = "proxy"
+	}
+	proxyURL, err := resolveSecret(key)
+	if err != nil || proxyURL == "" {
+		return err
+	}
+	return ui.SetAIProxy(proxyURL)
+}
+
+// resolveSecretEnv replaces secret references in environment variables
+// with the secrets, for settings read from the environment later on
+func resolveSecretEnv(names ...string) error {
+	for _, name := range names {
+		value := os.Getenv(name)
+		if !secrets.IsReference(value) {
+			continue
+		}
+		secret, err := secrets.Resolve(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if err := os.Setenv(name, secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withClient connects, runs fn and closes the client
+func withClient(fn func(client *kafka.Client) error) error {
+	client, err := connect()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Close(); err != nil {
+			log.Printf("Error closing Kafka client: %v", err)
+		}
+	}()
+	return fn(client)
+}
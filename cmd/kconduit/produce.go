@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/spf13/cobra"
+)
+
+// newProduceCmd returns the "produce" subcommand, which sends a single message,
+// or every line of a file, without starting the TUI
+func newProduceCmd() *cobra.Command {
+	var (
+		topic     string
+		key       string
+		keyPath   string
+		value     string
+		file      string
+		headers   []string
+		timestamp string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "produce",
+		Short: "Produce a single message to a topic",
+		Example: `  kconduit produce --topic orders --key 42 --value '{"id":42}'
+  echo '{"id":42}' | kconduit produce --topic orders --timestamp -48h
+  kconduit produce --topic orders --value hello --header source=cli --timestamp "2024-01-31 12:00:00"
+  kconduit produce --topic orders --file orders.jsonl --key-path '$.order.id'`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts := kafka.ProduceOptions{Headers: make(map[string]string)}
+			for _, h := range headers {
+				k, v, ok := strings.Cut(h, "=")
+				if !ok || strings.TrimSpace(k) == "" {
+					return fmt.Errorf("invalid header %q, expected key=value", h)
+				}
+				opts.Headers[strings.TrimSpace(k)] = v
+			}
+
+			ts, err := kafka.ParseTimestamp(timestamp)
+			if err != nil {
+				return err
+			}
+			opts.Timestamp = ts
+
+			var values []string
+			switch {
+			case file != "":
+				if values, err = readMessageLines(cmd, file); err != nil {
+					return err
+				}
+			case cmd.Flags().Changed("value"):
+				values = []string{value}
+			default:
+				// Read the value from stdin when it is not given as a flag
+				data, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return fmt.Errorf("failed to read value from stdin: %v", err)
+				}
+				values = []string{strings.TrimSuffix(string(data), "\n")}
+			}
+
+			// Keys are worked out before connecting, so a line without one
+			// does not leave the file half produced
+			keys := make([]string, len(values))
+			if keyPath != "" {
+				path, err := kafka.ParseKeyPath(keyPath)
+				if err != nil {
+					return err
+				}
+				for i, v := range values {
+					if keys[i], err = path.Extract(v); err != nil {
+						if file != "" {
+							return fmt.Errorf("message %d: %v", i+1, err)
+						}
+						return err
+					}
+				}
+			} else {
+				for i := range keys {
+					keys[i] = key
+				}
+			}
+
+			client, err := connect()
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err := client.Close(); err != nil {
+					log.Printf("Error closing Kafka client: %v", err)
+				}
+			}()
+
+			if file == "" {
+				partition, offset, err := client.ProduceMessage(topic, keys[0], values[0], opts)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(os.Stdout, "Produced to %s partition %d at offset %d\n", topic, partition, offset)
+				return nil
+			}
+			for i, v := range values {
+				if _, _, err := client.ProduceMessage(topic, keys[i], v, opts); err != nil {
+					return fmt.Errorf("message %d: %v (%d produced)", i+1, err, i)
+				}
+			}
+			fmt.Fprintf(os.Stdout, "Produced %d messages to %s\n", len(values), topic)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&topic, "topic", "t", "", "Topic to produce to")
+	cmd.Flags().StringVarP(&key, "key", "k", "", "Message key")
+	cmd.Flags().StringVar(&keyPath, "key-path", "", "Take the key from this field of the JSON value, e.g. $.order.id")
+	cmd.Flags().StringVar(&value, "value", "", "Message value (read from stdin if not set)")
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Produce each line of this file as a message (- for stdin)")
+	cmd.Flags().StringArrayVarP(&headers, "header", "H", nil, "Message header as key=value (repeatable)")
+	cmd.Flags().StringVar(&timestamp, "timestamp", "", "Message timestamp: Unix milliseconds, RFC3339, \"2006-01-02 15:04:05\" or relative like -24h")
+	_ = cmd.MarkFlagRequired("topic")
+	_ = cmd.RegisterFlagCompletionFunc("topic", completeTopics)
+	cmd.MarkFlagsMutuallyExclusive("key", "key-path")
+	cmd.MarkFlagsMutuallyExclusive("value", "file")
+
+	return cmd
+}
+
+// readMessageLines reads the messages of a file, one per line, skipping
+// blank lines; - reads stdin
+func readMessageLines(cmd *cobra.Command, path string) ([]string, error) {
+	in := cmd.InOrStdin()
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open messages: %v", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if line := strings.TrimSuffix(scanner.Text(), "\r"); strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read messages from %s: %v", path, err)
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("%s has no messages", path)
+	}
+	return lines, nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/spf13/cobra"
+)
+
+func newReplayCmd() *cobra.Command {
+	var (
+		dryRun bool
+		start  int
+		yes    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "replay SCRIPT",
+		Short: "Apply the changes of a recorded session to the connected cluster",
+		Long: `Applies a session script recorded with kconduit --record to the connected
+cluster, one change at a time and in the order they were made: topics created
+or deleted, topic config and partition changes and ACLs, including undos and
+changes made by the AI assistant.
+
+Replay stops at the first change that fails, so the cluster is never left with
+later changes applied on top of a missing earlier one. Fix the cause and resume
+with --start at the failed change.`,
+		Example: `  kconduit --cluster staging --record changes.yaml
+  kconduit --cluster prod replay changes.yaml --dry-run
+  kconduit --cluster prod replay changes.yaml --start 4`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			script, err := kafka.LoadSessionScript(args[0])
+			if err != nil {
+				return err
+			}
+			if start < 1 || start > len(script.Changes) {
+				if len(script.Changes) == 0 {
+					fmt.Println("The script has no changes")
+					return nil
+				}
+				return fmt.Errorf("--start must be between 1 and %d", len(script.Changes))
+			}
+
+			fmt.Printf("Recorded on %s at %s:\n", script.Cluster, script.Recorded.Local().Format("2006-01-02 15:04"))
+			for i := start - 1; i < len(script.Changes); i++ {
+				fmt.Printf("%3d. %s\n", i+1, script.Changes[i])
+			}
+			if dryRun {
+				return nil
+			}
+
+			client, err := connect()
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err := client.Close(); err != nil {
+					log.Printf("Error closing Kafka client: %v", err)
+				}
+			}()
+			if !yes && !confirm(fmt.Sprintf("Apply %d changes to %s?", len(script.Changes)-start+1, clusterLabel())) {
+				return fmt.Errorf("aborted")
+			}
+
+			client = client.WithAuditSource("replay")
+			for i := start - 1; i < len(script.Changes); i++ {
+				ch := script.Changes[i]
+				if err := client.Replay(ch); err != nil {
+					fmt.Fprintf(os.Stderr, "Failed %d. %s: %v\n", i+1, ch, err)
+					return fmt.Errorf("replay stopped at change %d, resume with --start %d", i+1, i+1)
+				}
+				fmt.Printf("Applied %d. %s\n", i+1, ch)
+			}
+			fmt.Printf("Replayed %d changes\n", len(script.Changes)-start+1)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the changes without connecting")
+	cmd.Flags().IntVar(&start, "start", 1, "Number of the first change to apply, to resume a replay that stopped")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Apply without asking for confirmation")
+	return cmd
+}
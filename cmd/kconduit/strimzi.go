@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/spf13/cobra"
+)
+
+// newStrimziCmd returns the "strimzi" command group for moving a cluster
+// under the Strimzi operator
+func newStrimziCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "strimzi",
+		Short: "Export topics and ACLs as Strimzi custom resources",
+	}
+	cmd.AddCommand(newStrimziExportCmd())
+	return cmd
+}
+
+func newStrimziExportCmd() *cobra.Command {
+	var (
+		opts       kafka.StrimziOptions
+		output     string
+		topics     string
+		principals []string
+		noTopics   bool
+		noUsers    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write KafkaTopic and KafkaUser resources for the cluster's topics and ACLs",
+		Long: `Writes a KafkaTopic for each topic, with its partitions, replication factor
+and topic-level configs, and a KafkaUser with simple authorization for each User
+principal that has ACLs, so a manually managed cluster can be handed over to
+the Strimzi operator. Apply the result with kubectl apply -f.
+
+ACLs for other principal types or for delegation tokens have no KafkaUser
+equivalent; they are listed on stderr and left out.`,
+		Example: `  kconduit strimzi export --kafka my-cluster --namespace kafka --output resources.yaml
+  kconduit strimzi export --kafka my-cluster --topics '^orders' --principals User:alice --authentication scram-sha-512`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var filter *regexp.Regexp
+			if topics != "" {
+				var err error
+				if filter, err = regexp.Compile(topics); err != nil {
+					return fmt.Errorf("invalid --topics: %v", err)
+				}
+			}
+			switch opts.Authentication {
+			case "", "scram-sha-512", "tls", "tls-external":
+			default:
+				return fmt.Errorf("invalid --authentication %q, use scram-sha-512, tls or tls-external", opts.Authentication)
+			}
+
+			client, err := connect()
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err := client.Close(); err != nil {
+					log.Printf("Error closing Kafka client: %v", err)
+				}
+			}()
+
+			var resources []any
+			var topicCount, userCount int
+			if !noTopics {
+				snapshots, err := client.TopicSnapshots()
+				if err != nil {
+					return err
+				}
+				var selected []kafka.TopicSnapshot
+				for name, s := range snapshots {
+					if filter == nil || filter.MatchString(name) {
+						selected = append(selected, s)
+					}
+				}
+				resources = append(resources, kafka.StrimziTopics(selected, opts)...)
+				topicCount = len(selected)
+			}
+			if !noUsers {
+				acls, err := client.ListACLs()
+				if err != nil {
+					return err
+				}
+				if len(principals) > 0 {
+					wanted := make(map[string]bool, len(principals))
+					for _, p := range principals {
+						wanted[strings.TrimSpace(p)] = true
+					}
+					var selected []kafka.ACL
+					for _, acl := range acls {
+						if wanted[acl.Principal] {
+							selected = append(selected, acl)
+						}
+					}
+					acls = selected
+				}
+				users, skipped, err := kafka.StrimziUsers(acls, opts)
+				if err != nil {
+					return err
+				}
+				for _, acl := range skipped {
+					fmt.Fprintf(os.Stderr, "Skipped ACL without a KafkaUser equivalent: %s\n", acl)
+				}
+				resources = append(resources, users...)
+				userCount = len(users)
+			}
+
+			data, err := kafka.MarshalStrimzi(resources)
+			if err != nil {
+				return err
+			}
+			if output == "" {
+				_, err = os.Stdout.Write(data)
+				return err
+			}
+			if err := os.WriteFile(output, data, 0o644); err != nil {
+				return fmt.Errorf("failed to write %s: %v", output, err)
+			}
+			fmt.Fprintf(os.Stderr, "Exported %d KafkaTopics and %d KafkaUsers to %s\n", topicCount, userCount, output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Kafka, "kafka", "", "Name of the Strimzi Kafka resource, set as the strimzi.io/cluster label")
+	cmd.Flags().StringVar(&opts.Namespace, "namespace", "", "Namespace of the resources (default none, kubectl's current namespace)")
+	cmd.Flags().StringVar(&opts.Authentication, "authentication", "", "Authentication of the KafkaUsers: scram-sha-512, tls or tls-external (default none)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file (default stdout)")
+	cmd.Flags().StringVar(&topics, "topics", "", "Only export topics matching this regular expression")
+	cmd.Flags().StringSliceVar(&principals, "principals", nil, "Only export users for these principals, e.g. User:alice")
+	cmd.Flags().BoolVar(&noTopics, "no-topics", false, "Leave out the KafkaTopics")
+	cmd.Flags().BoolVar(&noUsers, "no-users", false, "Leave out the KafkaUsers")
+	_ = cmd.MarkFlagRequired("kafka")
+	return cmd
+}
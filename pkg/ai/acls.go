@@ -0,0 +1,284 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+// ACLSpec is an ACL as the model describes it
+type ACLSpec struct {
+	Principal      string `json:"principal"`
+	Host           string `json:"host"`
+	ResourceType   string `json:"resource_type"`
+	ResourceName   string `json:"resource_name"`
+	PatternType    string `json:"pattern_type"`
+	Operation      string `json:"operation"`
+	PermissionType string `json:"permission_type"`
+}
+
+// Values accepted for ACL fields, in the spelling the Kafka client expects.
+// The Any and Match pattern types only filter existing ACLs, so none can be
+// created with them.
+var (
+	aclResourceTypes   = []string{"Topic", "Group", "Cluster", "TransactionalId", "DelegationToken"}
+	aclPatternTypes    = []string{"Literal", "Prefixed"}
+	aclOperations      = []string{"Read", "Write", "Create", "Delete", "Alter", "Describe", "ClusterAction", "DescribeConfigs", "AlterConfigs", "IdempotentWrite", "All"}
+	aclPermissionTypes = []string{"Allow", "Deny"}
+)
+
+func (s ACLSpec) validate() error {
+	if s.Principal == "" || s.ResourceType == "" || s.ResourceName == "" {
+		return fmt.Errorf("principal, resource_type and resource_name are required")
+	}
+	n := s.normalized()
+	for _, f := range []struct{ name, value string }{
+		{"resource_type", n.ResourceType},
+		{"pattern_type", n.PatternType},
+		{"operation", n.Operation},
+		{"permission_type", n.PermissionType},
+	} {
+		if f.value == "" {
+			return fmt.Errorf("invalid %s", f.name)
+		}
+	}
+	return nil
+}
+
+// normalized fills in defaults (any host, literal pattern, allow) and
+// fixes the case of enumerated fields. Unknown values become empty.
+func (s ACLSpec) normalized() ACLSpec {
+	if s.Host == "" {
+		s.Host = "*"
+	}
+	if s.PatternType == "" {
+		s.PatternType = "Literal"
+	}
+	if s.PermissionType == "" {
+		s.PermissionType = "Allow"
+	}
+	s.ResourceType = canonical(s.ResourceType, aclResourceTypes)
+	s.PatternType = canonical(s.PatternType, aclPatternTypes)
+	s.Operation = canonical(s.Operation, aclOperations)
+	s.PermissionType = canonical(s.PermissionType, aclPermissionTypes)
+	return s
+}
+
+// canonical returns the entry of values equal to v ignoring case, or ""
+func canonical(v string, values []string) string {
+	for _, c := range values {
+		if strings.EqualFold(v, c) {
+			return c
+		}
+	}
+	return ""
+}
+
+func (s ACLSpec) acl() kafka.ACL {
+	n := s.normalized()
+	return kafka.ACL{
+		Principal:      n.Principal,
+		Host:           n.Host,
+		ResourceType:   n.ResourceType,
+		ResourceName:   n.ResourceName,
+		PatternType:    n.PatternType,
+		Operation:      n.Operation,
+		PermissionType: n.PermissionType,
+	}
+}
+
+// ACLRequest is a set of ACLs that differ only in their operation, the
+// shape the Create ACL form works with
+type ACLRequest struct {
+	Principal      string
+	Host           string
+	ResourceType   string
+	ResourceName   string
+	PatternType    string
+	PermissionType string
+	Operations     []string
+}
+
+// SplitACLRequests separates the ACL creations among commands from the rest
+// so they can be confirmed by the user first. ACLs for the same principal,
+// host, resource and permission are merged into one request.
+func SplitACLRequests(commands []Command) (requests []ACLRequest, rest []Command) {
+	index := make(map[ACLSpec]int) // Keyed by the spec without its operation
+	add := func(s ACLSpec) {
+		n := s.normalized()
+		key := n
+		key.Operation = ""
+		i, ok := index[key]
+		if !ok {
+			i = len(requests)
+			index[key] = i
+			requests = append(requests, ACLRequest{
+				Principal:      n.Principal,
+				Host:           n.Host,
+				ResourceType:   n.ResourceType,
+				ResourceName:   n.ResourceName,
+				PatternType:    n.PatternType,
+				PermissionType: n.PermissionType,
+			})
+		}
+		requests[i].Operations = append(requests[i].Operations, n.Operation)
+	}
+
+	for _, cmd := range commands {
+		switch a := cmd.Action.(type) {
+		case *CreateACL:
+			if a.Validate() != nil {
+				rest = append(rest, cmd)
+				continue
+			}
+			add(a.ACLSpec)
+		case *CreateACLs:
+			if a.Validate() != nil {
+				rest = append(rest, cmd)
+				continue
+			}
+			for _, s := range a.ACLs {
+				add(s)
+			}
+		default:
+			rest = append(rest, cmd)
+		}
+	}
+	return requests, rest
+}
+
+// CreateACL creates one ACL
+type CreateACL struct {
+	ACLSpec
+}
+
+func (a *CreateACL) Validate() error { return a.validate() }
+
+func (a *CreateACL) Execute(c Cluster) (string, error) {
+	acl := a.acl()
+	if err := c.CreateACL(acl); err != nil {
+		return fmt.Sprintf("❌ Failed to create ACL: %v", err), err
+	}
+	return fmt.Sprintf("✅ Successfully created ACL:\n• Principal: %s\n• Resource: %s %s\n• Operation: %s %s\n• Host: %s",
+		acl.Principal, acl.ResourceType, acl.ResourceName, acl.Operation, acl.PermissionType, acl.Host), nil
+}
+
+// CreateACLs creates several ACLs, carrying on past failures
+type CreateACLs struct {
+	ACLs []ACLSpec `json:"acls"`
+}
+
+func (a *CreateACLs) Validate() error {
+	if len(a.ACLs) == 0 {
+		return fmt.Errorf("acls are required")
+	}
+	for i, s := range a.ACLs {
+		if err := s.validate(); err != nil {
+			return fmt.Errorf("acl %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (a *CreateACLs) Execute(c Cluster) (string, error) {
+	var created, failed []string
+	for _, s := range a.ACLs {
+		acl := s.acl()
+		desc := fmt.Sprintf("%s %s %s on %s %s", acl.Principal, acl.Operation, acl.PermissionType, acl.ResourceType, acl.ResourceName)
+		if err := c.CreateACL(acl); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", desc, err))
+		} else {
+			created = append(created, desc)
+		}
+	}
+
+	var parts []string
+	if len(created) > 0 {
+		parts = append(parts, bulletList(fmt.Sprintf("✅ Successfully created %d ACL(s):", len(created)), created))
+	}
+	if len(failed) > 0 {
+		parts = append(parts, bulletList(fmt.Sprintf("❌ Failed to create %d ACL(s):", len(failed)), failed))
+	}
+	return strings.Join(parts, "\n"), nil
+}
+
+// DeleteACL deletes one ACL
+type DeleteACL struct {
+	ACLSpec
+}
+
+func (a *DeleteACL) Validate() error { return a.validate() }
+
+func (a *DeleteACL) Execute(c Cluster) (string, error) {
+	acl := a.acl()
+	if err := c.DeleteACL(acl); err != nil {
+		return fmt.Sprintf("❌ Failed to delete ACL: %v", err), err
+	}
+	return fmt.Sprintf("✅ Successfully deleted ACL:\n• Principal: %s\n• Resource: %s %s\n• Operation: %s %s",
+		acl.Principal, acl.ResourceType, acl.ResourceName, acl.Operation, acl.PermissionType), nil
+}
+
+// ACLFilter selects ACLs for query_acls. Principal and resource name match
+// substrings case-insensitively; unset fields match anything.
+type ACLFilter struct {
+	Principal    string `json:"principal"`
+	ResourceType string `json:"resource_type"`
+	ResourceName string `json:"resource_name"`
+	Operation    string `json:"operation"`
+}
+
+func (f ACLFilter) matches(acl kafka.ACL) bool {
+	if f.Principal != "" && !strings.Contains(strings.ToLower(acl.Principal), strings.ToLower(f.Principal)) {
+		return false
+	}
+	if f.ResourceType != "" && !strings.EqualFold(acl.ResourceType, f.ResourceType) {
+		return false
+	}
+	if f.ResourceName != "" && !strings.Contains(strings.ToLower(acl.ResourceName), strings.ToLower(f.ResourceName)) {
+		return false
+	}
+	if f.Operation != "" && !strings.EqualFold(acl.Operation, f.Operation) {
+		return false
+	}
+	return true
+}
+
+// QueryACLs lists the ACLs matching a filter, grouped by resource
+type QueryACLs struct {
+	Filter ACLFilter `json:"filter"`
+}
+
+func (a *QueryACLs) Validate() error { return nil }
+
+func (a *QueryACLs) Execute(c Cluster) (string, error) {
+	acls, err := c.ListACLs()
+	if err != nil {
+		return fmt.Sprintf("❌ Failed to fetch ACLs: %v", err), err
+	}
+
+	byResource := make(map[string][]kafka.ACL)
+	count := 0
+	for _, acl := range acls {
+		if a.Filter.matches(acl) {
+			key := fmt.Sprintf("%s: %s", acl.ResourceType, acl.ResourceName)
+			byResource[key] = append(byResource[key], acl)
+			count++
+		}
+	}
+	if count == 0 {
+		return "No ACLs found matching the criteria.", nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Found %d ACL(s):\n\n", count))
+	for _, resource := range sortedKeys(byResource) {
+		sb.WriteString(fmt.Sprintf("📋 %s\n", resource))
+		for _, acl := range byResource[resource] {
+			sb.WriteString(fmt.Sprintf("  • %s → %s %s (from %s)\n",
+				acl.Principal, acl.Operation, acl.PermissionType, acl.Host))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
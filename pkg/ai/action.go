@@ -0,0 +1,209 @@
+// Package ai turns the actions returned by AI providers, as tool calls or as
+// JSON in their text, into Kafka operations.
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+// Cluster is the part of the Kafka client actions operate on
+type Cluster interface {
+	GetTopicDetails() ([]kafka.TopicInfo, error)
+	GetTopicConfig(topicName string) (*kafka.TopicConfig, error)
+	GetTopicConfigs(topicNames []string) (map[string]map[string]string, error)
+	CreateTopic(name string, numPartitions int32, replicationFactor int16) error
+	UpdateTopicConfig(topicName, configKey, configValue string) error
+	ModifyTopicPartitions(topicName string, numPartitions int32) error
+	ProduceMessage(topic, key, value string, opts kafka.ProduceOptions) (int32, int64, error)
+	GetConsumerGroups() ([]kafka.ConsumerGroupInfo, error)
+	ListACLs() ([]kafka.ACL, error)
+	CreateACL(acl kafka.ACL) error
+	DeleteACL(acl kafka.ACL) error
+}
+
+// Action is a single operation requested by the model
+type Action interface {
+	// Validate reports missing or invalid fields before anything runs
+	Validate() error
+	// Execute runs the action and returns a summary for the user. The
+	// summary is also set when the action fails.
+	Execute(c Cluster) (string, error)
+}
+
+// Previewer is implemented by actions changing many topics at once, whose
+// exact changes are shown for confirmation before they run
+type Previewer interface {
+	// Preview lists the change Execute would make to each affected topic,
+	// as "name: current→new"
+	Preview(c Cluster) ([]string, error)
+}
+
+// Spec describes an action to the registry, the system prompt and as a tool
+type Spec struct {
+	Name        string
+	Description string         // Completes "For ..., respond with JSON:"
+	Examples    []string       // JSON examples shown to the model
+	Parameters  map[string]any // JSON Schema of the fields, for tool calling
+	New         func() Action
+}
+
+var registry []Spec
+
+// Register adds an action to the registry. Actions appear in the system
+// prompt in registration order.
+func Register(spec Spec) {
+	if _, ok := Lookup(spec.Name); ok {
+		panic(fmt.Sprintf("ai: action %q registered twice", spec.Name))
+	}
+	registry = append(registry, spec)
+}
+
+// Lookup returns the spec of a registered action
+func Lookup(name string) (Spec, bool) {
+	for _, s := range registry {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Spec{}, false
+}
+
+// Specs returns every registered action
+func Specs() []Spec {
+	return append([]Spec(nil), registry...)
+}
+
+// Command is a decoded action together with the name it was requested by
+type Command struct {
+	Name   string
+	Action Action
+}
+
+// Parse extracts every JSON object with an "action" field from a model
+// response, ignoring markdown fences and surrounding text, and decodes it
+// into its registered type
+func Parse(response string) ([]Command, error) {
+	var commands []Command
+	for _, obj := range jsonObjects(response) {
+		var header struct {
+			Action string `json:"action"`
+		}
+		if err := json.Unmarshal([]byte(obj), &header); err != nil || header.Action == "" {
+			continue
+		}
+
+		cmd, err := DecodeCall(header.Action, json.RawMessage(obj))
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, cmd)
+	}
+	return commands, nil
+}
+
+// jsonObjects returns the top-level brace-delimited objects in s
+func jsonObjects(s string) []string {
+	var objs []string
+	depth, start := 0, -1
+	inString, escaped := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			if depth > 0 {
+				inString = true
+			}
+		case '{':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case '}':
+			if depth == 0 {
+				continue
+			}
+			depth--
+			if depth == 0 {
+				objs = append(objs, s[start:i+1])
+			}
+		}
+	}
+	return objs
+}
+
+// Configs holds topic config overrides. Models sometimes send numbers or
+// booleans, so any scalar is accepted and kept as its string form.
+type Configs map[string]string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (c *Configs) UnmarshalJSON(data []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	out := make(Configs, len(raw))
+	for k, v := range raw {
+		switch v := v.(type) {
+		case string:
+			out[k] = v
+		case float64:
+			out[k] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			out[k] = strconv.FormatBool(v)
+		default:
+			return fmt.Errorf("config %s: unsupported value %v", k, v)
+		}
+	}
+	*c = out
+	return nil
+}
+
+// apply sets every config on a topic and returns the applied "key=value"
+// pairs and the failures
+func (c Configs) apply(cluster Cluster, topic string) (applied, failed []string) {
+	for _, key := range sortedKeys(c) {
+		if err := cluster.UpdateTopicConfig(topic, key, c[key]); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", key, err))
+		} else {
+			applied = append(applied, fmt.Sprintf("%s=%s", key, c[key]))
+		}
+	}
+	return applied, failed
+}
+
+// bulletList renders a heading followed by one bullet per item
+func bulletList(heading string, items []string) string {
+	var sb strings.Builder
+	sb.WriteString(heading + "\n")
+	for _, item := range items {
+		sb.WriteString(fmt.Sprintf("  • %s\n", item))
+	}
+	return sb.String()
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
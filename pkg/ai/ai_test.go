@@ -0,0 +1,521 @@
+package ai
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+// fakeCluster records changes instead of talking to Kafka
+type fakeCluster struct {
+	topics   []kafka.TopicInfo
+	configs  map[string]map[string]string
+	acls     []kafka.ACL
+	failOn   string // Topic whose changes fail
+	produced []string
+	spread   int32 // Partitions produced messages cycle through, default 2
+}
+
+func (f *fakeCluster) GetTopicDetails() ([]kafka.TopicInfo, error) { return f.topics, nil }
+
+func (f *fakeCluster) GetTopicConfig(topic string) (*kafka.TopicConfig, error) {
+	return &kafka.TopicConfig{Name: topic, Configs: f.configs[topic]}, nil
+}
+
+func (f *fakeCluster) GetTopicConfigs(topics []string) (map[string]map[string]string, error) {
+	configs := make(map[string]map[string]string, len(topics))
+	for _, topic := range topics {
+		if c, ok := f.configs[topic]; ok {
+			configs[topic] = c
+		}
+	}
+	return configs, nil
+}
+
+func (f *fakeCluster) CreateTopic(name string, partitions int32, rf int16) error {
+	f.topics = append(f.topics, kafka.TopicInfo{Name: name, Partitions: int(partitions), ReplicationFactor: int(rf)})
+	return nil
+}
+
+func (f *fakeCluster) UpdateTopicConfig(topic, key, value string) error {
+	if topic == f.failOn {
+		return fmt.Errorf("denied")
+	}
+	if f.configs == nil {
+		f.configs = make(map[string]map[string]string)
+	}
+	if f.configs[topic] == nil {
+		f.configs[topic] = make(map[string]string)
+	}
+	f.configs[topic][key] = value
+	return nil
+}
+
+func (f *fakeCluster) ModifyTopicPartitions(topic string, partitions int32) error {
+	if topic == f.failOn {
+		return fmt.Errorf("denied")
+	}
+	for i := range f.topics {
+		if f.topics[i].Name == topic {
+			f.topics[i].Partitions = int(partitions)
+		}
+	}
+	return nil
+}
+
+func (f *fakeCluster) ProduceMessage(topic, key, value string, opts kafka.ProduceOptions) (int32, int64, error) {
+	f.produced = append(f.produced, key+"="+value)
+	spread := f.spread
+	if spread == 0 {
+		spread = 2
+	}
+	return int32(len(f.produced)) % spread, int64(len(f.produced)), nil
+}
+
+func (f *fakeCluster) GetConsumerGroups() ([]kafka.ConsumerGroupInfo, error) { return nil, nil }
+func (f *fakeCluster) ListACLs() ([]kafka.ACL, error)                        { return f.acls, nil }
+func (f *fakeCluster) CreateACL(acl kafka.ACL) error                         { f.acls = append(f.acls, acl); return nil }
+func (f *fakeCluster) DeleteACL(acl kafka.ACL) error                         { return nil }
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []Command
+		wantErr bool
+	}{
+		{
+			name:  "fenced single action",
+			input: "```json\n{\"action\": \"modify_partitions\", \"topic\": \"orders\", \"partitions\": 12}\n```",
+			want:  []Command{{Name: "modify_partitions", Action: &ModifyPartitions{Topic: "orders", Partitions: 12}}},
+		},
+		{
+			name:  "numeric configs and braces in strings",
+			input: `Sure: {"action": "modify_config", "topic": "a{b}", "configs": {"retention.ms": 86400000, "cleanup.policy": "delete"}}`,
+			want: []Command{{Name: "modify_config", Action: &ModifyConfig{
+				Topic:   "a{b}",
+				Configs: Configs{"retention.ms": "86400000", "cleanup.policy": "delete"},
+			}}},
+		},
+		{
+			name:  "several actions and unrelated objects",
+			input: `{"note": 1} {"action": "query_topics", "filter": {"partitions_greater_than": 3}} {"action": "create_acl", "principal": "User:alice", "resource_type": "Topic", "resource_name": "payments", "operation": "Read", "permission_type": "Allow"}`,
+			want: []Command{
+				{Name: "query_topics", Action: &QueryTopics{Filter: TopicFilter{PartitionsGreaterThan: ptr(3)}}},
+				{Name: "create_acl", Action: &CreateACL{ACLSpec{Principal: "User:alice", ResourceType: "Topic", ResourceName: "payments", Operation: "Read", PermissionType: "Allow"}}},
+			},
+		},
+		{
+			name:  "plain text",
+			input: "I can only help with Kafka.",
+		},
+		{
+			name:    "unknown action",
+			input:   `{"action": "drop_cluster"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestExecutor(t *testing.T) {
+	cluster := &fakeCluster{
+		topics: []kafka.TopicInfo{
+			{Name: "events-a", Partitions: 3},
+			{Name: "events-b", Partitions: 3},
+			{Name: "orders", Partitions: 6},
+		},
+		failOn: "events-b",
+	}
+	commands, err := Parse(`
+{"action": "modify_matching_configs", "pattern": "starts_with:events", "configs": {"compression.type": "lz4"}}
+{"action": "modify_partitions", "topic": "", "partitions": 4}
+{"action": "modify_all_partitions", "partitions": 6}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := NewExecutor(cluster, Policy{}).Execute(commands)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"Step 1: ✅ Successfully updated 1 topic(s) matching 'starts_with:events':\n  • events-a: compression.type=lz4",
+		"❌ Failed to update 1 topic(s):\n  • events-b: compression.type: denied",
+		"Step 2: ⚠️ Skipped modify_partitions: topic is required",
+		"Step 3: ✅ Successfully updated 1 topic(s):\n  • events-a (3→6)",
+		"❌ Failed to update 1 topic(s):\n  • events-b: denied",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("result missing %q:\n%s", want, result)
+		}
+	}
+	if got := cluster.configs["events-a"]["compression.type"]; got != "lz4" {
+		t.Errorf("events-a compression.type = %q, want lz4", got)
+	}
+	if _, ok := cluster.configs["orders"]; ok {
+		t.Errorf("orders should not have been changed")
+	}
+}
+
+func TestExecuteWithProgress(t *testing.T) {
+	cluster := &fakeCluster{topics: []kafka.TopicInfo{{Name: "orders", Partitions: 3}}}
+	commands, err := Parse(`
+{"action": "modify_partitions", "topic": "orders", "partitions": 6}
+{"action": "query_topics", "filter": {}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var steps []string
+	result, err := NewExecutor(cluster, Policy{}).ExecuteWithProgress(commands, func(s Step) {
+		if s.Total != 2 {
+			t.Errorf("step total = %d, want 2", s.Total)
+		}
+		if s.Done {
+			steps = append(steps, s.Result)
+		} else {
+			steps = append(steps, fmt.Sprintf("start %d %s", s.Index, s.Name))
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != 4 || steps[0] != "start 0 modify_partitions" || steps[2] != "start 1 query_topics" {
+		t.Fatalf("steps = %q", steps)
+	}
+	// The final result is the finished steps together
+	if want := steps[1] + "\n" + steps[3]; result != want {
+		t.Errorf("result = %q, want %q", result, want)
+	}
+}
+
+func TestCreateACLDefaultsHost(t *testing.T) {
+	cluster := &fakeCluster{}
+	action := &CreateACL{ACLSpec{Principal: "User:alice", ResourceType: "Topic", ResourceName: "payments", Operation: "Read", PermissionType: "Allow"}}
+	if _, err := action.Execute(cluster); err != nil {
+		t.Fatal(err)
+	}
+	if len(cluster.acls) != 1 || cluster.acls[0].Host != "*" {
+		t.Errorf("acls = %+v, want one ACL for host *", cluster.acls)
+	}
+}
+
+func TestSystemPromptListsActions(t *testing.T) {
+	prompt := SystemPrompt(Policy{})
+	for _, s := range Specs() {
+		if !strings.Contains(prompt, `"action": "`+s.Name+`"`) {
+			t.Errorf("system prompt has no example for %s", s.Name)
+		}
+	}
+
+	prompt = SystemPrompt(Policy{Allow: []string{"query_*"}})
+	if strings.Contains(prompt, `"action": "create_topic"`) {
+		t.Errorf("restricted system prompt offers create_topic")
+	}
+}
+
+func TestSystemPromptInstructions(t *testing.T) {
+	rule := "Never touch topics prefixed prod."
+	prompt := SystemPrompt(Policy{Instructions: "  " + rule + "\n"})
+	if !strings.HasSuffix(prompt, "\n"+rule) {
+		t.Errorf("system prompt does not end with the instructions:\n%s", prompt)
+	}
+	if strings.Contains(SystemPrompt(Policy{}), "instructions of their own") {
+		t.Errorf("system prompt mentions instructions when there are none")
+	}
+}
+
+func TestTools(t *testing.T) {
+	for _, tool := range Tools(Policy{}) {
+		if tool.Parameters["type"] != "object" {
+			t.Errorf("%s has no parameter schema", tool.Name)
+		}
+		if strings.Contains(tool.Description, `"action"`) {
+			t.Errorf("%s description still names the action field: %s", tool.Name, tool.Description)
+		}
+	}
+	if n := len(Tools(Policy{Allow: []string{"query_*"}})); n != 3 {
+		t.Errorf("restricted policy offers %d tools, want 3", n)
+	}
+	if strings.Contains(ToolSystemPrompt(Policy{}), "respond with JSON") {
+		t.Error("tool system prompt asks for JSON")
+	}
+}
+
+func TestDecodeCall(t *testing.T) {
+	cmd, err := DecodeCall("modify_config", []byte(`{"topic": "orders", "configs": {"retention.ms": 86400000}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &ModifyConfig{Topic: "orders", Configs: Configs{"retention.ms": "86400000"}}
+	if cmd.Name != "modify_config" || !reflect.DeepEqual(cmd.Action, want) {
+		t.Errorf("got %s %+v, want %+v", cmd.Name, cmd.Action, want)
+	}
+	if _, err := DecodeCall("delete_topic", []byte(`{}`)); err == nil {
+		t.Error("expected an error for an unknown action")
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		action string
+		want   bool
+	}{
+		{"empty allows everything", Policy{}, "modify_all_configs", true},
+		{"allow list", Policy{Allow: []string{"query_*"}}, "query_topics", true},
+		{"outside allow list", Policy{Allow: []string{"query_*"}}, "create_topic", false},
+		{"deny glob", Policy{Deny: []string{"modify_all_*"}}, "modify_all_partitions", false},
+		{"deny leaves others", Policy{Deny: []string{"modify_all_*"}}, "modify_partitions", true},
+		{"deny wins over allow", Policy{Allow: []string{"*"}, Deny: []string{"delete_acl"}}, "delete_acl", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Allowed(tt.action); got != tt.want {
+				t.Errorf("Allowed(%q) = %v, want %v", tt.action, got, tt.want)
+			}
+		})
+	}
+
+	if err := (Policy{Deny: []string{"modify_everything"}}).Validate(); err == nil {
+		t.Errorf("expected an error for a pattern matching no action")
+	}
+}
+
+func TestExecutorRefusesDisallowedActions(t *testing.T) {
+	cluster := &fakeCluster{topics: []kafka.TopicInfo{{Name: "orders", Partitions: 3}}}
+	commands, err := Parse(`{"action": "query_topics", "filter": {}} {"action": "modify_all_partitions", "partitions": 12}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := NewExecutor(cluster, Policy{Allow: []string{"query_*"}}).Execute(commands)
+	if err == nil || !strings.Contains(result, "modify_all_partitions") {
+		t.Errorf("expected modify_all_partitions to be refused, got %q, %v", result, err)
+	}
+	if cluster.topics[0].Partitions != 3 {
+		t.Errorf("refused plan changed the cluster")
+	}
+}
+
+func TestPreviewBulkActions(t *testing.T) {
+	cluster := &fakeCluster{
+		topics: []kafka.TopicInfo{{Name: "orders", Partitions: 3}, {Name: "events", Partitions: 12}, {Name: "audit", Partitions: 1}},
+		configs: map[string]map[string]string{
+			"orders": {"retention.ms": "604800000"},
+			"events": {"retention.ms": "86400000"},
+		},
+	}
+	commands, err := Parse(`{"action": "modify_all_partitions", "partitions": 6}
+{"action": "modify_all_configs", "configs": {"retention.ms": "86400000"}}
+{"action": "create_topic", "name": "payments", "partitions": 3}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !NeedsPreview(commands) || NeedsPreview(commands[2:]) {
+		t.Errorf("NeedsPreview should only hold for plans with bulk actions")
+	}
+
+	got, err := NewExecutor(cluster, Policy{}).Preview(commands)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "🔍 Review the changes before they are applied:\n\n" +
+		"Step 1: modify_all_partitions would change 2 topic(s):\n" +
+		"  • orders: partitions 3→6\n" +
+		"  • audit: partitions 1→6\n" +
+		"Step 2: modify_all_configs would change 2 topic(s):\n" +
+		"  • orders: retention.ms 604800000→86400000\n" +
+		"  • audit: retention.ms (unset)→86400000\n" +
+		"Step 3: create_topic"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if cluster.topics[0].Partitions != 3 || cluster.configs["orders"]["retention.ms"] != "604800000" {
+		t.Errorf("preview changed the cluster")
+	}
+}
+
+func TestMaxTopicsLimit(t *testing.T) {
+	cluster := &fakeCluster{topics: []kafka.TopicInfo{{Name: "a", Partitions: 1}, {Name: "b", Partitions: 1}, {Name: "c", Partitions: 1}}}
+	commands, err := Parse(`{"action": "create_topic", "name": "d"} {"action": "modify_all_partitions", "partitions": 3}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	executor := NewExecutor(cluster, Policy{MaxTopics: 2})
+	if _, err := executor.Preview(commands); err == nil || !strings.Contains(err.Error(), "would change 3 topics, more than max_topics (2)") {
+		t.Errorf("Preview error = %v", err)
+	}
+	if _, err := executor.Execute(commands); err == nil {
+		t.Errorf("expected the plan to be refused")
+	}
+	if len(cluster.topics) != 3 || cluster.topics[0].Partitions != 1 {
+		t.Errorf("refused plan changed the cluster")
+	}
+
+	if _, err := NewExecutor(cluster, Policy{MaxTopics: 3}).Execute(commands); err != nil {
+		t.Errorf("plan within the limit failed: %v", err)
+	}
+	if (Policy{MaxTopics: -1}).Validate() == nil {
+		t.Errorf("expected an error for a negative max_topics")
+	}
+}
+
+func TestQueryTopicsByCompression(t *testing.T) {
+	cluster := &fakeCluster{
+		topics: []kafka.TopicInfo{{Name: "events-a"}, {Name: "events-b"}, {Name: "events-c"}, {Name: "orders"}},
+		configs: map[string]map[string]string{
+			"events-a": {"compression.type": "lz4"},
+			"events-b": {"compression.type": "producer"},
+			"events-c": {"compression.type": "zstd"},
+		},
+	}
+	result, err := (&QueryTopics{Filter: TopicFilter{NameContains: "events", Compression: "none"}}).Execute(cluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result, "Found 1 topic(s)") || !strings.Contains(result, "events-b") {
+		t.Errorf("result = %q, want only events-b", result)
+	}
+}
+
+func TestSplitACLRequests(t *testing.T) {
+	commands, err := Parse(`
+{"action": "create_acls", "acls": [
+  {"principal": "User:alice", "resource_type": "topic", "resource_name": "payments", "operation": "read", "permission_type": "Allow"},
+  {"principal": "User:alice", "resource_type": "Topic", "resource_name": "payments", "operation": "Describe", "permission_type": "Allow"},
+  {"principal": "User:alice", "resource_type": "Group", "resource_name": "billing", "operation": "Read", "permission_type": "Allow"}]}
+{"action": "query_acls", "filter": {}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests, rest := SplitACLRequests(commands)
+	want := []ACLRequest{
+		{Principal: "User:alice", Host: "*", ResourceType: "Topic", ResourceName: "payments", PatternType: "Literal", PermissionType: "Allow", Operations: []string{"Read", "Describe"}},
+		{Principal: "User:alice", Host: "*", ResourceType: "Group", ResourceName: "billing", PatternType: "Literal", PermissionType: "Allow", Operations: []string{"Read"}},
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %+v, want %+v", requests, want)
+	}
+	if len(rest) != 1 || rest[0].Name != "query_acls" {
+		t.Errorf("rest = %+v, want only query_acls", rest)
+	}
+}
+
+func TestACLSpecValidate(t *testing.T) {
+	spec := ACLSpec{Principal: "User:alice", ResourceType: "Topic", ResourceName: "payments", Operation: "Fly"}
+	if err := spec.validate(); err == nil {
+		t.Errorf("expected an unknown operation to be rejected")
+	}
+	// Filter pattern types cannot be created
+	for _, pattern := range []string{"Any", "match"} {
+		spec := ACLSpec{Principal: "User:alice", ResourceType: "Topic", ResourceName: "payments", PatternType: pattern, Operation: "Read"}
+		if err := spec.validate(); err == nil {
+			t.Errorf("pattern type %s was accepted", pattern)
+		}
+	}
+}
+
+func TestGenerateMessages(t *testing.T) {
+	commands, err := Parse(`{"action": "generate_messages", "topic": "orders", "key_field": "id", "messages": [
+  {"id": "o-1", "total": 10},
+  {"id": 2, "total": 20},
+  {"total": 30}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cluster := &fakeCluster{}
+	result, err := NewExecutor(cluster, Policy{}).Execute(commands)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`o-1={"id":"o-1","total":10}`, `2={"id":2,"total":20}`, `={"total":30}`}
+	if !reflect.DeepEqual(cluster.produced, want) {
+		t.Errorf("produced %q, want %q", cluster.produced, want)
+	}
+	if !strings.Contains(result, "Produced 3 generated message(s) to 'orders' (partitions 0, 1)") {
+		t.Errorf("unexpected result %q", result)
+	}
+
+	// Partitions are listed in numeric order
+	commands, err = Parse(`{"action": "generate_messages", "topic": "orders", "messages": [{}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cluster = &fakeCluster{spread: 12}
+	result, err = NewExecutor(cluster, Policy{}).Execute(commands)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result, "(partitions 0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11)") {
+		t.Errorf("unexpected result %q", result)
+	}
+}
+
+func TestExplainRequest(t *testing.T) {
+	got := ExplainRequest(&kafka.TopicConfig{
+		Name: "orders", Partitions: 12, ReplicationFactor: 3,
+		Configs: map[string]string{"retention.ms": "604800000", "cleanup.policy": "delete"},
+	})
+	want := "Topic: orders\nPartitions: 12\nReplication factor: 3\n\nConfiguration:\ncleanup.policy=delete\nretention.ms=604800000\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestQueryMessagesRequest(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	got := QueryMessagesRequest("orders", []kafka.Message{
+		{Partition: 0, Offset: 7, Key: "o-1", Value: `{"status": "FAILED"}`, Timestamp: ts},
+		{Partition: 1, Offset: 3, Value: "plain text", Headers: map[string]string{"source": "billing"}},
+	}, "How many failed?")
+	want := "Topic: orders\nMessages: all 2 consumed\n\n" +
+		`{"partition":0,"offset":7,"timestamp":"2024-05-01T12:00:00Z","key":"o-1","value":{"status":"FAILED"}}` + "\n" +
+		`{"partition":1,"offset":3,"headers":{"source":"billing"},"value":"plain text"}` + "\n" +
+		"\nQuestion: How many failed?\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestQueryMessagesRequestKeepsMostRecent(t *testing.T) {
+	messages := make([]kafka.Message, maxQueryMessages+10)
+	for i := range messages {
+		messages[i] = kafka.Message{Offset: int64(i), Value: strings.Repeat("x", 3000)}
+	}
+	got := QueryMessagesRequest("orders", messages, "?")
+	if len(got) > maxQueryContextBytes+200 {
+		t.Errorf("request is %d bytes, over the %d byte budget", len(got), maxQueryContextBytes)
+	}
+	if !strings.Contains(got, "truncated") {
+		t.Error("long values should be truncated")
+	}
+	if !strings.Contains(got, fmt.Sprintf(`"offset":%d,`, len(messages)-1)) || strings.Contains(got, `"offset":0,`) {
+		t.Error("the most recent messages should be kept")
+	}
+	if !strings.Contains(got, fmt.Sprintf("most recent of %d consumed", len(messages))) {
+		t.Errorf("missing sample note in %q", got[:100])
+	}
+}
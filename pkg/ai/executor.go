@@ -0,0 +1,173 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/digitalis-io/kconduit/pkg/logger"
+)
+
+// Executor runs parsed commands against a cluster
+type Executor struct {
+	cluster Cluster
+	policy  Policy
+}
+
+// NewExecutor returns an executor operating on cluster. Commands the policy
+// does not permit are refused whatever the model asked for.
+func NewExecutor(cluster Cluster, policy Policy) *Executor {
+	return &Executor{cluster: cluster, policy: policy}
+}
+
+// Check returns an error naming the commands the policy does not permit
+func (e *Executor) Check(commands []Command) error {
+	var refused []string
+	for _, cmd := range commands {
+		if !e.policy.Allowed(cmd.Name) {
+			refused = append(refused, cmd.Name)
+		}
+	}
+	if len(refused) > 0 {
+		logger.Get().WithField("actions", refused).Warn("Refused AI commands not permitted by policy")
+		return fmt.Errorf("not permitted by the AI policy: %s", strings.Join(refused, ", "))
+	}
+	return nil
+}
+
+// NeedsPreview reports whether a plan has actions changing many topics at
+// once, which should be previewed and confirmed before it runs
+func NeedsPreview(commands []Command) bool {
+	for _, cmd := range commands {
+		if _, ok := cmd.Action.(Previewer); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Preview lists the exact changes of the plan's bulk actions, and names its
+// other steps, for the user to confirm. An action changing more topics than
+// the policy's MaxTopics refuses the whole plan.
+func (e *Executor) Preview(commands []Command) (string, error) {
+	changes, err := e.previewChanges(commands)
+	if err != nil {
+		return fmt.Sprintf("🚫 Refused: %v", err), err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🔍 Review the changes before they are applied:\n\n")
+	for i, cmd := range commands {
+		if len(commands) > 1 {
+			sb.WriteString(fmt.Sprintf("Step %d: ", i+1))
+		}
+		topics, ok := changes[i]
+		switch {
+		case !ok:
+			sb.WriteString(cmd.Name + "\n")
+		case len(topics) == 0:
+			sb.WriteString(fmt.Sprintf("%s would change no topics\n", cmd.Name))
+		default:
+			sb.WriteString(bulletList(fmt.Sprintf("%s would change %d topic(s):", cmd.Name, len(topics)), topics))
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n"), nil
+}
+
+// previewChanges previews the valid bulk actions of a plan, keyed by their
+// index, enforcing the policy's MaxTopics
+func (e *Executor) previewChanges(commands []Command) (map[int][]string, error) {
+	changes := make(map[int][]string)
+	for i, cmd := range commands {
+		previewer, ok := cmd.Action.(Previewer)
+		if !ok || cmd.Action.Validate() != nil {
+			continue
+		}
+		topics, err := previewer.Preview(e.cluster)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", cmd.Name, err)
+		}
+		if e.policy.MaxTopics > 0 && len(topics) > e.policy.MaxTopics {
+			logger.Get().WithField("action", cmd.Name).WithField("topics", len(topics)).Warn("Refused AI command over the topic limit")
+			return nil, fmt.Errorf("%s would change %d topics, more than max_topics (%d) in the AI policy", cmd.Name, len(topics), e.policy.MaxTopics)
+		}
+		changes[i] = topics
+	}
+	return changes, nil
+}
+
+// Step reports the progress of one command of a plan
+type Step struct {
+	Index  int // From 0
+	Total  int
+	Name   string
+	Done   bool   // False when the step starts
+	Result string // Summary of a finished step, as in Execute's result
+	Err    error
+}
+
+// Execute validates and runs the commands in order and returns a summary of
+// their results. Nothing runs if the policy refuses any of the commands or
+// one changes more topics than its MaxTopics, so a plan is never half
+// applied. Otherwise invalid commands are skipped and a
+// failing command does not stop the ones after it. The error is the first
+// failure, if any.
+func (e *Executor) Execute(commands []Command) (string, error) {
+	return e.ExecuteWithProgress(commands, nil)
+}
+
+// ExecuteWithProgress is Execute calling progress as each command starts and
+// finishes, so long plans can be followed step by step
+func (e *Executor) ExecuteWithProgress(commands []Command, progress func(Step)) (string, error) {
+	log := logger.Get()
+	if progress == nil {
+		progress = func(Step) {}
+	}
+
+	if err := e.Check(commands); err != nil {
+		return fmt.Sprintf("🚫 Refused: %v", err), err
+	}
+	// Topics may have been created since the plan was previewed, so the
+	// limit is checked again
+	if e.policy.MaxTopics > 0 {
+		if _, err := e.previewChanges(commands); err != nil {
+			return fmt.Sprintf("🚫 Refused: %v", err), err
+		}
+	}
+
+	var results []string
+	var firstErr error
+	for i, cmd := range commands {
+		log.WithField("action", cmd.Name).WithField("step", i+1).Info("Executing AI command")
+		step := Step{Index: i, Total: len(commands), Name: cmd.Name}
+		progress(step)
+
+		var result string
+		if err := cmd.Action.Validate(); err != nil {
+			result = fmt.Sprintf("⚠️ Skipped %s: %v", cmd.Name, err)
+		} else {
+			var err error
+			result, err = cmd.Action.Execute(e.cluster)
+			if err != nil {
+				log.WithField("action", cmd.Name).WithError(err).Warn("AI command failed")
+				step.Err = err
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+
+		if len(commands) > 1 {
+			result = fmt.Sprintf("Step %d: %s", i+1, result)
+		}
+		result = strings.TrimSuffix(result, "\n")
+		results = append(results, result)
+		step.Done = true
+		step.Result = result
+		progress(step)
+	}
+
+	if len(results) == 0 {
+		return "No actions were executed", nil
+	}
+	return strings.Join(results, "\n"), firstErr
+}
@@ -0,0 +1,29 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+// ExplainPrompt is the system prompt for explaining a topic's configuration.
+// The answer is only displayed, never executed.
+const ExplainPrompt = `You are a Kafka expert reviewing the configuration of a single topic.
+
+Explain in plain language what the topic's settings mean for how data is stored, retained, compacted, compressed and replicated. Focus on the values that differ from Kafka's defaults or that carry risk, rather than listing every setting.
+
+Then give a short list of concrete tuning suggestions with the config key, the suggested value and why, and mention the trade-offs. If the configuration looks sound, say so.
+
+Answer in prose and bullet points. Do NOT respond with JSON.`
+
+// ExplainRequest describes a topic's configuration to the model
+func ExplainRequest(cfg *kafka.TopicConfig) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Topic: %s\nPartitions: %d\nReplication factor: %d\n\nConfiguration:\n",
+		cfg.Name, cfg.Partitions, cfg.ReplicationFactor))
+	for _, key := range sortedKeys(cfg.Configs) {
+		sb.WriteString(fmt.Sprintf("%s=%s\n", key, cfg.Configs[key]))
+	}
+	return sb.String()
+}
@@ -0,0 +1,67 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+// GroupFilter selects consumer groups for query_consumer_groups. Unset
+// fields match anything.
+type GroupFilter struct {
+	LagGreaterThan  *int64 `json:"lag_greater_than"`
+	GroupIDContains string `json:"group_id_contains"`
+	State           string `json:"state"`
+}
+
+// QueryConsumerGroups lists the consumer groups matching a filter
+type QueryConsumerGroups struct {
+	Filter GroupFilter `json:"filter"`
+}
+
+func (a *QueryConsumerGroups) Validate() error { return nil }
+
+func (a *QueryConsumerGroups) Execute(c Cluster) (string, error) {
+	groups, err := c.GetConsumerGroups()
+	if err != nil {
+		return fmt.Sprintf("❌ Failed to fetch consumer groups: %v", err), err
+	}
+
+	f := a.Filter
+	var matched []kafka.ConsumerGroupInfo
+	for _, group := range groups {
+		if f.LagGreaterThan != nil && group.ConsumerLag <= *f.LagGreaterThan {
+			continue
+		}
+		if f.GroupIDContains != "" && !strings.Contains(group.GroupID, f.GroupIDContains) {
+			continue
+		}
+		if f.State != "" && !strings.EqualFold(group.State, f.State) {
+			continue
+		}
+		matched = append(matched, group)
+	}
+
+	if len(matched) == 0 {
+		return "No consumer groups found matching the criteria.", nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Found %d consumer group(s):\n\n", len(matched)))
+	for _, group := range matched {
+		sb.WriteString(fmt.Sprintf("📊 Group: %s\n", group.GroupID))
+		sb.WriteString(fmt.Sprintf("   • State: %s\n", group.State))
+		sb.WriteString(fmt.Sprintf("   • Members: %d\n", group.NumMembers))
+		sb.WriteString(fmt.Sprintf("   • Topics: %d\n", group.NumTopics))
+		sb.WriteString(fmt.Sprintf("   • Total Lag: %d\n", group.ConsumerLag))
+		if len(group.Topics) > 0 && len(group.Topics) <= 5 {
+			sb.WriteString(fmt.Sprintf("   • Consuming: %s\n", strings.Join(group.Topics, ", ")))
+		} else if len(group.Topics) > 5 {
+			sb.WriteString(fmt.Sprintf("   • Consuming: %s, ... (%d total)\n",
+				strings.Join(group.Topics[:5], ", "), len(group.Topics)))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,225 @@
+package ai
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LocalExamples are requests ParseLocal understands, shown when no AI
+// provider is available
+var LocalExamples = []string{
+	"create topic orders with 6 partitions and replication factor 3",
+	"set retention of orders to 7 days",
+	"change retention for topics starting with logs- to 12 hours",
+	"show consumer groups with lag over 1000",
+	"show lag for group billing",
+}
+
+// localRule turns a request matching pattern into an action, or returns
+// nil when the request only looks like one the rule handles
+type localRule struct {
+	pattern *regexp.Regexp
+	action  func(match []string) map[string]any
+}
+
+const topicNamePattern = `([A-Za-z0-9._-]+)`
+
+var localRules = []localRule{
+	{
+		regexp.MustCompile(`(?i)^(?:create|make|add) (?:a |the )?(?:new )?topic (?:named |called )?` + topicNamePattern + `(.*)$`),
+		createTopicAction,
+	},
+	{
+		regexp.MustCompile(`(?i)^(?:set|change|update|make) (?:the )?retention(?:\.ms)? (?:of|for|on) (?:all|every) topics? to (.+)$`),
+		func(m []string) map[string]any {
+			return retentionAction(m[1], map[string]any{"action": "modify_all_configs"})
+		},
+	},
+	{
+		regexp.MustCompile(`(?i)^(?:set|change|update|make) (?:the )?retention(?:\.ms)? (?:of|for|on) (?:all )?topics (starting with|beginning with|containing|ending with) ` + topicNamePattern + ` to (.+)$`),
+		func(m []string) map[string]any {
+			kind := map[string]string{
+				"starting with": "starts_with", "beginning with": "starts_with",
+				"containing": "contains", "ending with": "ends_with",
+			}[strings.ToLower(m[1])]
+			return retentionAction(m[3], map[string]any{"action": "modify_matching_configs", "pattern": kind + ":" + m[2]})
+		},
+	},
+	{
+		regexp.MustCompile(`(?i)^(?:set|change|update|make) (?:the )?retention(?:\.ms)? (?:of|for|on) (?:the )?(?:topic )?` + topicNamePattern + ` to (.+)$`),
+		func(m []string) map[string]any {
+			return retentionAction(m[2], map[string]any{"action": "modify_config", "topic": m[1]})
+		},
+	},
+	{
+		regexp.MustCompile(`(?i)^(?:set|change|update) (?:the )?(?:topic )?` + topicNamePattern + `(?:'s)? retention(?:\.ms)? to (.+)$`),
+		func(m []string) map[string]any {
+			return retentionAction(m[2], map[string]any{"action": "modify_config", "topic": m[1]})
+		},
+	},
+	{
+		regexp.MustCompile(`(?i)^(?:set|change|update) (?:the )?retention(?:\.ms)? to (.+?) (?:on|for) (?:all|every) topics?$`),
+		func(m []string) map[string]any {
+			return retentionAction(m[1], map[string]any{"action": "modify_all_configs"})
+		},
+	},
+	{
+		regexp.MustCompile(`(?i)^(?:set|change|update) (?:the )?retention(?:\.ms)? to (.+?) (?:on|for) (?:the )?(?:topic )?` + topicNamePattern + `(?: topic)?$`),
+		func(m []string) map[string]any {
+			return retentionAction(m[1], map[string]any{"action": "modify_config", "topic": m[2]})
+		},
+	},
+	{
+		regexp.MustCompile(`(?i)^(?:show|list|find|get) (?:me )?(?:all )?(?:the )?(?:consumer )?groups (?:with|having|that have) (?:a )?lag (?:greater than|more than|over|above|of more than|>) ([\d,]+)$`),
+		func(m []string) map[string]any {
+			n, err := strconv.ParseInt(strings.ReplaceAll(m[1], ",", ""), 10, 64)
+			if err != nil {
+				return nil
+			}
+			return lagAction(map[string]any{"lag_greater_than": n})
+		},
+	},
+	{
+		regexp.MustCompile(`(?i)^(?:show|get|what is|what's) (?:me )?(?:the )?(?:consumer )?lag (?:of|for) (?:the )?(?:consumer )?(?:group )?` + topicNamePattern + `$`),
+		func(m []string) map[string]any {
+			return lagAction(map[string]any{"group_id_contains": m[1]})
+		},
+	},
+	{
+		regexp.MustCompile(`(?i)^(?:(?:show|list|find|get) (?:me )?(?:the )?(?:consumer )?(?:group )?lag|(?:which|what) (?:consumer )?groups are (?:lagging|behind))$`),
+		func([]string) map[string]any {
+			return lagAction(map[string]any{"lag_greater_than": 0})
+		},
+	},
+}
+
+// ParseLocal recognises the most common requests — creating a topic, changing
+// retention and querying consumer lag — without an AI provider. It returns the
+// action as a model would, for Parse to decode, or false when the request
+// needs a model.
+func ParseLocal(request string) (string, bool) {
+	text := normalizeRequest(request)
+	for _, rule := range localRules {
+		match := rule.pattern.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
+		action := rule.action(match)
+		if action == nil {
+			return "", false
+		}
+		out, err := json.Marshal(action)
+		if err != nil {
+			return "", false
+		}
+		return string(out), true
+	}
+	return "", false
+}
+
+// normalizeRequest trims courtesy words and punctuation and collapses
+// whitespace, keeping names as typed
+func normalizeRequest(request string) string {
+	words := strings.Fields(strings.TrimRight(strings.TrimSpace(request), ".!?"))
+	for len(words) > 0 {
+		switch strings.ToLower(words[0]) {
+		case "please", "kindly", "can", "could", "would", "you":
+			words = words[1:]
+			continue
+		}
+		break
+	}
+	if n := len(words); n > 0 && strings.EqualFold(words[n-1], "please") {
+		words = words[:n-1]
+	}
+	return strings.Join(words, " ")
+}
+
+var (
+	partitionsPattern  = regexp.MustCompile(`(?i)(\d+) partitions?`)
+	replicationPattern = regexp.MustCompile(`(?i)(?:replication(?: factor)?(?: of)? (\d+)|(\d+) replicas?|rf=?(\d+))`)
+	fillerPattern      = regexp.MustCompile(`(?i)\b(?:with|and|a|an)\b|,`)
+)
+
+// createTopicAction reads the optional partition count and replication
+// factor following the topic name. Anything else, such as configs, is left
+// to a model.
+func createTopicAction(m []string) map[string]any {
+	action := map[string]any{"action": "create_topic", "name": m[1]}
+	rest := m[2]
+	if p := partitionsPattern.FindStringSubmatch(rest); p != nil {
+		n, err := strconv.ParseInt(p[1], 10, 32)
+		if err != nil || n <= 0 {
+			return nil
+		}
+		action["partitions"] = n
+		rest = strings.Replace(rest, p[0], "", 1)
+	}
+	if r := replicationPattern.FindStringSubmatch(rest); r != nil {
+		n, err := strconv.ParseInt(r[1]+r[2]+r[3], 10, 16)
+		if err != nil || n <= 0 {
+			return nil
+		}
+		action["replication_factor"] = n
+		rest = strings.Replace(rest, r[0], "", 1)
+	}
+	if strings.TrimSpace(fillerPattern.ReplaceAllString(rest, "")) != "" {
+		return nil
+	}
+	return action
+}
+
+// retentionAction sets retention.ms from a duration such as "7 days",
+// "12h" or "forever"
+func retentionAction(duration string, action map[string]any) map[string]any {
+	ms, ok := parseRetention(duration)
+	if !ok {
+		return nil
+	}
+	action["configs"] = map[string]string{"retention.ms": ms}
+	return action
+}
+
+func lagAction(filter map[string]any) map[string]any {
+	return map[string]any{"action": "query_consumer_groups", "filter": filter}
+}
+
+var durationPattern = regexp.MustCompile(`^(\d+(?:\.\d+)?|an?|one) ?([a-z]+)$`)
+
+// retentionUnits are the duration units understood, in milliseconds
+var retentionUnits = map[string]float64{
+	"ms": 1, "millisecond": 1, "milliseconds": 1,
+	"s": 1000, "sec": 1000, "secs": 1000, "second": 1000, "seconds": 1000,
+	"m": 60e3, "min": 60e3, "mins": 60e3, "minute": 60e3, "minutes": 60e3,
+	"h": 3600e3, "hr": 3600e3, "hrs": 3600e3, "hour": 3600e3, "hours": 3600e3,
+	"d": 86400e3, "day": 86400e3, "days": 86400e3,
+	"w": 604800e3, "week": 604800e3, "weeks": 604800e3,
+}
+
+// parseRetention converts a duration such as "7 days", "12h" or "a week" to
+// milliseconds, -1 for no limit
+func parseRetention(s string) (string, bool) {
+	s = strings.ToLower(s)
+	switch s {
+	case "forever", "unlimited", "infinite", "-1":
+		return "-1", true
+	}
+	m := durationPattern.FindStringSubmatch(s)
+	if m == nil {
+		return "", false
+	}
+	unit, ok := retentionUnits[m[2]]
+	if !ok {
+		return "", false
+	}
+	n := 1.0
+	if c := m[1][0]; c >= '0' && c <= '9' {
+		var err error
+		if n, err = strconv.ParseFloat(m[1], 64); err != nil || n <= 0 {
+			return "", false
+		}
+	}
+	return strconv.FormatInt(int64(n*unit), 10), true
+}
@@ -0,0 +1,52 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLocal(t *testing.T) {
+	tests := []struct {
+		request string
+		want    string // "" when a model is needed
+	}{
+		{"create topic Orders", `{"action":"create_topic","name":"Orders"}`},
+		{"Please create a new topic named orders-v2 with 6 partitions and replication factor 3.", `{"action":"create_topic","name":"orders-v2","partitions":6,"replication_factor":3}`},
+		{"create topic events with 2 replicas", `{"action":"create_topic","name":"events","replication_factor":2}`},
+		{"create topic events with lz4 compression", ""},
+		{"set retention of orders to 7 days", `{"action":"modify_config","configs":{"retention.ms":"604800000"},"topic":"orders"}`},
+		{"Change the retention for topic app.logs to 12h", `{"action":"modify_config","configs":{"retention.ms":"43200000"},"topic":"app.logs"}`},
+		{"set orders retention to a week", `{"action":"modify_config","configs":{"retention.ms":"604800000"},"topic":"orders"}`},
+		{"set retention of orders to forever", `{"action":"modify_config","configs":{"retention.ms":"-1"},"topic":"orders"}`},
+		{"set retention of orders to 7 fortnights", ""},
+		{"update retention on all topics to 3 days", `{"action":"modify_all_configs","configs":{"retention.ms":"259200000"}}`},
+		{"change retention for topics starting with logs- to 30 minutes", `{"action":"modify_matching_configs","configs":{"retention.ms":"1800000"},"pattern":"starts_with:logs-"}`},
+		{"Set retention to 7 days on orders topic", `{"action":"modify_config","configs":{"retention.ms":"604800000"},"topic":"orders"}`},
+		{"Change retention to 30 days for all topics", `{"action":"modify_all_configs","configs":{"retention.ms":"2592000000"}}`},
+		{"show consumer groups with lag over 1,000", `{"action":"query_consumer_groups","filter":{"lag_greater_than":1000}}`},
+		{"what's the lag of group billing?", `{"action":"query_consumer_groups","filter":{"group_id_contains":"billing"}}`},
+		{"which groups are lagging", `{"action":"query_consumer_groups","filter":{"lag_greater_than":0}}`},
+		{"give User:alice read access to topic payments", ""},
+	}
+	for _, tt := range tests {
+		got, ok := ParseLocal(tt.request)
+		if ok != (tt.want != "") || got != tt.want {
+			t.Errorf("ParseLocal(%q) = %s, %v, want %s", tt.request, got, ok, tt.want)
+		}
+	}
+}
+
+func TestParseLocalDecodes(t *testing.T) {
+	response, ok := ParseLocal("create topic orders with 3 partitions")
+	if !ok {
+		t.Fatal("request not recognised")
+	}
+	commands, err := Parse(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &CreateTopic{Name: "orders", Partitions: 3}
+	if len(commands) != 1 || !reflect.DeepEqual(commands[0].Action, want) {
+		t.Errorf("decoded %+v, want %+v", commands, want)
+	}
+}
@@ -0,0 +1,92 @@
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+// maxGeneratedMessages caps how many messages one generate_messages action
+// may publish
+const maxGeneratedMessages = 500
+
+// GenerateMessages publishes sample messages written by the model
+type GenerateMessages struct {
+	Topic    string            `json:"topic"`
+	KeyField string            `json:"key_field"` // Top-level field used as the message key
+	Messages []json.RawMessage `json:"messages"`
+}
+
+func (a *GenerateMessages) Validate() error {
+	if a.Topic == "" {
+		return fmt.Errorf("topic is required")
+	}
+	if len(a.Messages) == 0 {
+		return fmt.Errorf("messages are required")
+	}
+	if len(a.Messages) > maxGeneratedMessages {
+		return fmt.Errorf("at most %d messages can be generated at once", maxGeneratedMessages)
+	}
+	return nil
+}
+
+func (a *GenerateMessages) Execute(c Cluster) (string, error) {
+	partitions := make(map[int32]bool)
+	var failed []string
+	produced := 0
+	for i, raw := range a.Messages {
+		var value bytes.Buffer
+		if err := json.Compact(&value, raw); err != nil {
+			failed = append(failed, fmt.Sprintf("message %d: %v", i+1, err))
+			continue
+		}
+		partition, _, err := c.ProduceMessage(a.Topic, messageKey(raw, a.KeyField), value.String(), kafka.ProduceOptions{})
+		if err != nil {
+			// The same error usually repeats for every message
+			return fmt.Sprintf("❌ Failed to produce to %s after %d message(s): %v", a.Topic, produced, err), err
+		}
+		partitions[partition] = true
+		produced++
+	}
+
+	sorted := make([]int32, 0, len(partitions))
+	for p := range partitions {
+		sorted = append(sorted, p)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	ids := make([]string, len(sorted))
+	for i, p := range sorted {
+		ids[i] = fmt.Sprint(p)
+	}
+
+	result := fmt.Sprintf("✅ Produced %d generated message(s) to '%s' (partitions %s)", produced, a.Topic, strings.Join(ids, ", "))
+	if len(failed) > 0 {
+		result += "\n" + bulletList(fmt.Sprintf("⚠️ Skipped %d invalid message(s):", len(failed)), failed)
+	}
+	return result, nil
+}
+
+// messageKey returns the named top-level field of a JSON object as a string,
+// or "" when the field is absent
+func messageKey(raw json.RawMessage, field string) string {
+	if field == "" {
+		return ""
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return ""
+	}
+	switch v := obj[field].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
@@ -0,0 +1,82 @@
+package ai
+
+import (
+	"fmt"
+	"path"
+)
+
+// Policy limits the actions the assistant may run. Entries are action names
+// or globs such as "query_*". Deny wins over allow, and an empty allow list
+// permits every action that is not denied.
+type Policy struct {
+	Allow []string `mapstructure:"allow" yaml:"allow"`
+	Deny  []string `mapstructure:"deny" yaml:"deny"`
+	// Instructions are the organisation's own rules, such as naming
+	// conventions, added to the system prompt. Unlike Allow and Deny they
+	// are followed by the model, not enforced.
+	Instructions string `mapstructure:"instructions" yaml:"instructions"`
+	// MaxTopics refuses actions changing more topics than this at once,
+	// such as modify_all_configs. 0 means no limit.
+	MaxTopics int `mapstructure:"max_topics" yaml:"max_topics"`
+}
+
+// Validate checks that every entry is a valid glob matching at least one
+// registered action, so typos do not silently widen or narrow the policy
+func (p Policy) Validate() error {
+	for _, list := range [][]string{p.Allow, p.Deny} {
+		for _, pattern := range list {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid AI policy pattern %q", pattern)
+			}
+			if !matchesAny(pattern) {
+				return fmt.Errorf("AI policy pattern %q matches no action", pattern)
+			}
+		}
+	}
+	if p.MaxTopics < 0 {
+		return fmt.Errorf("AI policy max_topics must not be negative")
+	}
+	return nil
+}
+
+// Allowed reports whether the policy permits the named action
+func (p Policy) Allowed(name string) bool {
+	if matchList(p.Deny, name) {
+		return false
+	}
+	return len(p.Allow) == 0 || matchList(p.Allow, name)
+}
+
+// Restricted reports whether the policy forbids anything
+func (p Policy) Restricted() bool {
+	return len(p.Allow) > 0 || len(p.Deny) > 0
+}
+
+// AllowedSpecs returns the registered actions the policy permits
+func (p Policy) AllowedSpecs() []Spec {
+	var specs []Spec
+	for _, s := range registry {
+		if p.Allowed(s.Name) {
+			specs = append(specs, s)
+		}
+	}
+	return specs
+}
+
+func matchList(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(pattern string) bool {
+	for _, s := range registry {
+		if ok, _ := path.Match(pattern, s.Name); ok {
+			return true
+		}
+	}
+	return false
+}
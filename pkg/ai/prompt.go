@@ -0,0 +1,205 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	Register(Spec{
+		Name:        "create_topic",
+		Description: "creating topics",
+		Examples:    []string{`{"action": "create_topic", "name": "topic-name", "partitions": 3, "replication_factor": 1, "configs": {"compression.type": "gzip"}}`},
+		Parameters: object(map[string]any{
+			"name":               stringParam("Topic name"),
+			"partitions":         integerParam("Number of partitions"),
+			"replication_factor": integerParam("Replication factor"),
+			"configs":            configsParam,
+		}, "name"),
+		New: func() Action { return &CreateTopic{} },
+	})
+	Register(Spec{
+		Name:        "modify_partitions",
+		Description: "modifying topic partitions",
+		Examples:    []string{`{"action": "modify_partitions", "topic": "topic-name", "partitions": 10}`},
+		Parameters: object(map[string]any{
+			"topic":      stringParam("Topic name"),
+			"partitions": integerParam("New total number of partitions, more than the current count"),
+		}, "topic", "partitions"),
+		New: func() Action { return &ModifyPartitions{} },
+	})
+	Register(Spec{
+		Name:        "modify_all_partitions",
+		Description: "modifying partitions on ALL topics",
+		Examples:    []string{`{"action": "modify_all_partitions", "partitions": 100}`},
+		Parameters: object(map[string]any{
+			"partitions": integerParam("New total number of partitions for every topic"),
+		}, "partitions"),
+		New: func() Action { return &ModifyAllPartitions{} },
+	})
+	Register(Spec{
+		Name:        "generate_messages",
+		Description: "generating sample or test messages for a topic (write the messages yourself as realistic JSON matching the schema the user describes, or one inferred from the topic name; produce exactly as many as requested, up to 500; key_field is optional)",
+		Examples:    []string{`{"action": "generate_messages", "topic": "orders", "key_field": "order_id", "messages": [{"order_id": "o-1001", "customer": "alice", "total": 42.5}, {"order_id": "o-1002", "customer": "bob", "total": 17.0}]}`},
+		Parameters: object(map[string]any{
+			"topic":     stringParam("Topic to produce to"),
+			"key_field": stringParam("Top-level field of each message used as its key"),
+			"messages": map[string]any{
+				"type":        "array",
+				"description": "The messages, as JSON objects",
+				"items":       map[string]any{"type": "object"},
+			},
+		}, "topic", "messages"),
+		New: func() Action { return &GenerateMessages{} },
+	})
+	Register(Spec{
+		Name:        "modify_config",
+		Description: "modifying topic configurations (like compression, retention, etc.)",
+		Examples:    []string{`{"action": "modify_config", "topic": "topic-name", "configs": {"compression.type": "snappy", "retention.ms": "86400000"}}`},
+		Parameters: object(map[string]any{
+			"topic":   stringParam("Topic name"),
+			"configs": configsParam,
+		}, "topic", "configs"),
+		New: func() Action { return &ModifyConfig{} },
+	})
+	Register(Spec{
+		Name:        "modify_all_configs",
+		Description: "modifying configurations on ALL topics",
+		Examples:    []string{`{"action": "modify_all_configs", "configs": {"compression.type": "gzip", "retention.ms": "604800000"}}`},
+		Parameters: object(map[string]any{
+			"configs": configsParam,
+		}, "configs"),
+		New: func() Action { return &ModifyAllConfigs{} },
+	})
+	Register(Spec{
+		Name:        "modify_matching_configs",
+		Description: "modifying configurations on topics matching a pattern",
+		Examples: []string{
+			`{"action": "modify_matching_configs", "pattern": "starts_with:random", "configs": {"compression.type": "lz4"}}`,
+			`{"action": "modify_matching_configs", "pattern": "contains:events", "configs": {"retention.ms": "86400000"}}`,
+			`{"action": "modify_matching_configs", "pattern": "ends_with:log", "configs": {"compression.type": "gzip"}}`,
+		},
+		Parameters: object(map[string]any{
+			"pattern": stringParam(`Topics to change, "starts_with:PREFIX", "contains:TEXT" or "ends_with:SUFFIX"`),
+			"configs": configsParam,
+		}, "pattern", "configs"),
+		New: func() Action { return &ModifyMatchingConfigs{} },
+	})
+	Register(Spec{
+		Name:        "query_consumer_groups",
+		Description: "querying consumer groups (find groups with lag, list groups, etc.)",
+		Examples: []string{
+			`{"action": "query_consumer_groups", "filter": {"lag_greater_than": 10}}`,
+			`{"action": "query_consumer_groups", "filter": {"group_id_contains": "my-group"}}`,
+			`{"action": "query_consumer_groups", "filter": {"state": "Stable"}}`,
+		},
+		Parameters: object(map[string]any{
+			"filter": object(map[string]any{
+				"lag_greater_than":  integerParam("Only groups with more total lag"),
+				"group_id_contains": stringParam("Only groups whose ID contains this"),
+				"state":             stringParam(`Only groups in this state, e.g. "Stable" or "Empty"`),
+			}),
+		}, "filter"),
+		New: func() Action { return &QueryConsumerGroups{} },
+	})
+	Register(Spec{
+		Name:        "query_topics",
+		Description: "querying topics (list topics with specific configurations)",
+		Examples: []string{
+			`{"action": "query_topics", "filter": {"compression": "none"}}`,
+			`{"action": "query_topics", "filter": {"partitions_greater_than": 10}}`,
+			`{"action": "query_topics", "filter": {"name_contains": "events"}}`,
+			`{"action": "query_topics", "filter": {"replication_factor": 3}}`,
+		},
+		Parameters: object(map[string]any{
+			"filter": object(map[string]any{
+				"compression":             stringParam(`Only topics with this compression.type, "none" for uncompressed`),
+				"partitions_greater_than": integerParam("Only topics with more partitions"),
+				"name_contains":           stringParam("Only topics whose name contains this"),
+				"replication_factor":      integerParam("Only topics with this replication factor"),
+			}),
+		}, "filter"),
+		New: func() Action { return &QueryTopics{} },
+	})
+	Register(Spec{
+		Name:        "create_acl",
+		Description: "creating an ACL or granting access (e.g. \"give User:alice read access to topic payments\")",
+		Examples:    []string{`{"action": "create_acl", "principal": "User:alice", "host": "*", "resource_type": "Topic", "resource_name": "my-topic", "pattern_type": "Literal", "operation": "Read", "permission_type": "Allow"}`},
+		Parameters:  object(aclParams(), aclRequired...),
+		New:         func() Action { return &CreateACL{} },
+	})
+	Register(Spec{
+		Name:        "create_acls",
+		Description: "creating multiple ACLs at once",
+		Examples:    []string{`{"action": "create_acls", "acls": [{"principal": "User:alice", "host": "*", "resource_type": "Topic", "resource_name": "my-topic", "pattern_type": "Literal", "operation": "Read", "permission_type": "Allow"}, {"principal": "User:alice", "host": "*", "resource_type": "Topic", "resource_name": "my-topic", "pattern_type": "Literal", "operation": "Write", "permission_type": "Allow"}]}`},
+		Parameters: object(map[string]any{
+			"acls": map[string]any{"type": "array", "items": object(aclParams(), aclRequired...)},
+		}, "acls"),
+		New: func() Action { return &CreateACLs{} },
+	})
+	Register(Spec{
+		Name:        "delete_acl",
+		Description: "deleting an ACL",
+		Examples:    []string{`{"action": "delete_acl", "principal": "User:alice", "host": "*", "resource_type": "Topic", "resource_name": "my-topic", "pattern_type": "Literal", "operation": "Read", "permission_type": "Allow"}`},
+		Parameters:  object(aclParams(), aclRequired...),
+		New:         func() Action { return &DeleteACL{} },
+	})
+	Register(Spec{
+		Name:        "query_acls",
+		Description: "querying/listing ACLs (an empty filter lists all ACLs)",
+		Examples: []string{
+			`{"action": "query_acls", "filter": {"principal": "User:alice"}}`,
+			`{"action": "query_acls", "filter": {"resource_type": "Topic", "resource_name": "my-topic"}}`,
+			`{"action": "query_acls", "filter": {}}`,
+		},
+		Parameters: object(map[string]any{
+			"filter": object(map[string]any{
+				"principal":     stringParam(`Only ACLs for this principal, e.g. "User:alice"`),
+				"resource_type": enumParam("Only ACLs on this resource type", "Topic", "Group", "Cluster", "TransactionalId"),
+				"resource_name": stringParam("Only ACLs on this resource"),
+				"operation":     stringParam("Only ACLs for this operation"),
+			}),
+		}, "filter"),
+		New: func() Action { return &QueryACLs{} },
+	})
+}
+
+const promptIntro = `You are a Kafka assistant. Convert natural language commands into specific Kafka operations.`
+
+const promptACLValues = `Valid ACL resource types: Topic, Group, Cluster, TransactionalId
+Valid ACL operations: Read, Write, Create, Delete, Alter, Describe, ClusterAction, DescribeConfigs, AlterConfigs, IdempotentWrite, All
+Valid ACL permission types: Allow, Deny
+Valid ACL pattern types: Literal, Prefixed`
+
+const promptRules = `Always respond with ONLY the appropriate JSON for the requested operation. Do NOT include explanations, markdown formatting, or multiple JSON blocks. Return a single, clean JSON object that can be directly executed.
+
+If it requires multiple steps, ensure they are in the right order and all necessary fields are included.
+
+Refuse to perform any actions that are not related to Kafka. Never delete anything.`
+
+// SystemPrompt describes the actions the policy permits to the model, followed
+// by the policy's instructions
+func SystemPrompt(p Policy) string {
+	var sb strings.Builder
+	sb.WriteString(promptIntro + "\n\n")
+	for _, s := range p.AllowedSpecs() {
+		sb.WriteString(fmt.Sprintf("For %s, respond with JSON:\n", s.Description))
+		sb.WriteString(strings.Join(s.Examples, "\nor\n"))
+		sb.WriteString("\n\n")
+	}
+	sb.WriteString(promptACLValues + "\n\n")
+	sb.WriteString(promptRules)
+	if p.Restricted() {
+		sb.WriteString("\n\nOnly the operations listed above are permitted on this cluster. If the user asks for anything else, explain that it is not allowed instead of returning JSON.")
+	}
+	writeInstructions(&sb, p)
+	return sb.String()
+}
+
+// writeInstructions appends the policy's own instructions, if any
+func writeInstructions(sb *strings.Builder, p Policy) {
+	if instructions := strings.TrimSpace(p.Instructions); instructions != "" {
+		sb.WriteString("\n\nThe operators of this cluster have these instructions of their own. Follow them too, and if a request goes against them explain why instead of carrying it out:\n")
+		sb.WriteString(instructions)
+	}
+}
@@ -0,0 +1,99 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+// Limits on the messages sent with a question, keeping the request within
+// what the models accept. The most recent messages are kept.
+const (
+	maxQueryMessages     = 500
+	maxQueryValueBytes   = 2000
+	maxQueryContextBytes = 64000
+)
+
+// QueryMessagesPrompt is the system prompt for answering questions about
+// consumed messages. The answer is only displayed, never executed.
+const QueryMessagesPrompt = `You are a Kafka expert answering questions about a batch of messages consumed from a topic.
+
+The messages are given one per line as JSON with their partition, offset, timestamp, key, headers and value. Values that were JSON are embedded as JSON, anything else as a string. Long values are cut short and marked as truncated.
+
+Answer the question from these messages only. When counting or aggregating, go through every message, give the numbers and say which field you used. If the messages given are only part of those consumed, say that the answer covers the sample. If the question cannot be answered from the messages, say so.
+
+Answer in prose, bullet points or small tables. Do NOT respond with JSON.`
+
+// queryMessage is how one message is described to the model
+type queryMessage struct {
+	Partition int32             `json:"partition"`
+	Offset    int64             `json:"offset"`
+	Timestamp string            `json:"timestamp,omitempty"`
+	Key       string            `json:"key,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Value     json.RawMessage   `json:"value"`
+}
+
+// QueryMessagesRequest describes the messages and the question to the model.
+// Only the most recent messages that fit the request are included and the
+// model is told how many were left out.
+func QueryMessagesRequest(topic string, messages []kafka.Message, question string) string {
+	var lines []string
+	size := 0
+	for i := len(messages) - 1; i >= 0 && len(lines) < maxQueryMessages; i-- {
+		line := describeQueryMessage(messages[i])
+		if size+len(line) > maxQueryContextBytes {
+			break
+		}
+		size += len(line) + 1
+		lines = append(lines, line)
+	}
+	// Back to the order they were consumed in
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Topic: %s\n", topic))
+	if len(lines) < len(messages) {
+		sb.WriteString(fmt.Sprintf("Messages: the %d most recent of %d consumed\n\n", len(lines), len(messages)))
+	} else {
+		sb.WriteString(fmt.Sprintf("Messages: all %d consumed\n\n", len(messages)))
+	}
+	for _, line := range lines {
+		sb.WriteString(line + "\n")
+	}
+	sb.WriteString("\nQuestion: " + question + "\n")
+	return sb.String()
+}
+
+// describeQueryMessage renders a message as one line of JSON
+func describeQueryMessage(msg kafka.Message) string {
+	q := queryMessage{
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Key:       msg.Key,
+		Headers:   msg.Headers,
+	}
+	if !msg.Timestamp.IsZero() {
+		q.Timestamp = msg.Timestamp.UTC().Format(time.RFC3339Nano)
+	}
+	value := msg.Value
+	if len(value) <= maxQueryValueBytes && json.Valid([]byte(value)) {
+		q.Value = json.RawMessage(value)
+	} else {
+		if len(value) > maxQueryValueBytes {
+			value = strings.ToValidUTF8(value[:maxQueryValueBytes], "") + "…[truncated]"
+		}
+		q.Value, _ = json.Marshal(value)
+	}
+	line, err := json.Marshal(q)
+	if err != nil {
+		// Not expected, the value has been checked to be valid JSON
+		return fmt.Sprintf(`{"partition":%d,"offset":%d}`, msg.Partition, msg.Offset)
+	}
+	return string(line)
+}
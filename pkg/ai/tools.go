@@ -0,0 +1,127 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Tool describes an action to providers with native tool calling, which
+// return the action's fields as structured arguments instead of JSON in text
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  map[string]any // JSON Schema of the action's fields
+}
+
+// Tools returns the actions the policy permits as tools
+func Tools(p Policy) []Tool {
+	var tools []Tool
+	for _, s := range p.AllowedSpecs() {
+		tools = append(tools, Tool{Name: s.Name, Description: toolDescription(s), Parameters: s.Parameters})
+	}
+	return tools
+}
+
+// toolDescription is the spec's description with its examples as tool
+// arguments, without the "action" field the tool name replaces
+func toolDescription(s Spec) string {
+	var sb strings.Builder
+	sb.WriteString("Use for " + s.Description + ".")
+	for _, example := range s.Examples {
+		var args map[string]any
+		if err := json.Unmarshal([]byte(example), &args); err != nil {
+			continue
+		}
+		delete(args, "action")
+		out, err := json.Marshal(args)
+		if err != nil {
+			continue
+		}
+		sb.WriteString("\nExample: " + string(out))
+	}
+	return sb.String()
+}
+
+// DecodeCall decodes a tool call into the action it names
+func DecodeCall(name string, args json.RawMessage) (Command, error) {
+	spec, ok := Lookup(name)
+	if !ok {
+		return Command{}, fmt.Errorf("unknown action %q", name)
+	}
+	action := spec.New()
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, action); err != nil {
+			return Command{}, fmt.Errorf("invalid %s action: %w", spec.Name, err)
+		}
+	}
+	return Command{Name: spec.Name, Action: action}, nil
+}
+
+// DescribeCall words a tool call for the conversation history, so later
+// requests know what was done without being shown JSON to imitate
+func DescribeCall(name string, args json.RawMessage) string {
+	return fmt.Sprintf("Called %s with %s", name, strings.TrimSpace(string(args)))
+}
+
+// ToolSystemPrompt is the system prompt for providers that are given the
+// actions the policy permits as tools
+func ToolSystemPrompt(p Policy) string {
+	var sb strings.Builder
+	sb.WriteString(promptIntro + "\n\n")
+	sb.WriteString(promptACLValues + "\n\n")
+	sb.WriteString(promptToolRules)
+	if p.Restricted() {
+		sb.WriteString("\n\nOnly the operations offered as tools are permitted on this cluster. If the user asks for anything else, explain that it is not allowed.")
+	}
+	writeInstructions(&sb, p)
+	return sb.String()
+}
+
+const promptToolRules = `Carry out requests by calling the tools. When a request needs several operations, call the tools in the order they must run and include all the fields each one needs. Answer questions that need no operation in plain text.
+
+Refuse to perform any actions that are not related to Kafka. Never delete anything.`
+
+// Helpers building the JSON Schemas of action parameters
+
+func object(properties map[string]any, required ...string) map[string]any {
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func stringParam(description string) map[string]any {
+	return map[string]any{"type": "string", "description": description}
+}
+
+func enumParam(description string, values ...string) map[string]any {
+	return map[string]any{"type": "string", "description": description, "enum": values}
+}
+
+func integerParam(description string) map[string]any {
+	return map[string]any{"type": "integer", "description": description}
+}
+
+// configsParam is a map of topic config overrides
+var configsParam = map[string]any{
+	"type":                 "object",
+	"description":          `Topic configs to set, e.g. {"compression.type": "snappy", "retention.ms": "86400000"}`,
+	"additionalProperties": map[string]any{"type": "string"},
+}
+
+// aclParams are the fields of a single ACL
+func aclParams() map[string]any {
+	return map[string]any{
+		"principal":       stringParam(`Principal such as "User:alice"`),
+		"host":            stringParam(`Host the ACL applies to, "*" for any`),
+		"resource_type":   enumParam("Resource type", "Topic", "Group", "Cluster", "TransactionalId"),
+		"resource_name":   stringParam("Resource name, or prefix for Prefixed patterns"),
+		"pattern_type":    enumParam("How resource_name matches", "Literal", "Prefixed"),
+		"operation":       enumParam("Operation", "Read", "Write", "Create", "Delete", "Alter", "Describe", "ClusterAction", "DescribeConfigs", "AlterConfigs", "IdempotentWrite", "All"),
+		"permission_type": enumParam("Permission", "Allow", "Deny"),
+	}
+}
+
+var aclRequired = []string{"principal", "resource_type", "resource_name", "operation", "permission_type"}
@@ -0,0 +1,381 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+)
+
+// CreateTopic creates a topic and applies its config overrides
+type CreateTopic struct {
+	Name              string  `json:"name"`
+	Partitions        int32   `json:"partitions"`
+	ReplicationFactor int16   `json:"replication_factor"`
+	Configs           Configs `json:"configs"`
+}
+
+func (a *CreateTopic) Validate() error {
+	if a.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	return nil
+}
+
+func (a *CreateTopic) Execute(c Cluster) (string, error) {
+	if err := c.CreateTopic(a.Name, a.Partitions, a.ReplicationFactor); err != nil {
+		return fmt.Sprintf("❌ Failed to create topic %s: %v", a.Name, err), err
+	}
+	result := fmt.Sprintf("✅ Successfully created topic '%s' with %d partitions and replication factor %d",
+		a.Name, a.Partitions, a.ReplicationFactor)
+	if _, failed := a.Configs.apply(c, a.Name); len(failed) > 0 {
+		logger.Get().WithField("topic", a.Name).WithField("failed", failed).Warn("Failed to apply config")
+		result += "\n⚠️ Failed to apply: " + strings.Join(failed, ", ")
+	}
+	return result, nil
+}
+
+// ModifyPartitions increases the partition count of one topic
+type ModifyPartitions struct {
+	Topic      string `json:"topic"`
+	Partitions int32  `json:"partitions"`
+}
+
+func (a *ModifyPartitions) Validate() error {
+	if a.Topic == "" {
+		return fmt.Errorf("topic is required")
+	}
+	if a.Partitions <= 0 {
+		return fmt.Errorf("partitions must be positive")
+	}
+	return nil
+}
+
+func (a *ModifyPartitions) Execute(c Cluster) (string, error) {
+	if err := c.ModifyTopicPartitions(a.Topic, a.Partitions); err != nil {
+		return fmt.Sprintf("❌ Failed to modify partitions for %s: %v", a.Topic, err), err
+	}
+	return fmt.Sprintf("✅ Successfully increased partitions for topic '%s' to %d", a.Topic, a.Partitions), nil
+}
+
+// ModifyAllPartitions raises every topic to at least the given partition
+// count. Kafka cannot shrink topics, so larger ones are left alone.
+type ModifyAllPartitions struct {
+	Partitions int32 `json:"partitions"`
+}
+
+func (a *ModifyAllPartitions) Validate() error {
+	if a.Partitions <= 0 {
+		return fmt.Errorf("partitions must be positive")
+	}
+	return nil
+}
+
+func (a *ModifyAllPartitions) Execute(c Cluster) (string, error) {
+	topics, err := c.GetTopicDetails()
+	if err != nil {
+		return fmt.Sprintf("❌ Failed to fetch topics: %v", err), err
+	}
+
+	var successes, failures []string
+	for _, topic := range topics {
+		if topic.Partitions >= int(a.Partitions) {
+			continue
+		}
+		if err := c.ModifyTopicPartitions(topic.Name, a.Partitions); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", topic.Name, err))
+			logger.Get().WithField("topic", topic.Name).WithError(err).Warn("Failed to modify partitions")
+		} else {
+			successes = append(successes, fmt.Sprintf("%s (%d→%d)", topic.Name, topic.Partitions, a.Partitions))
+		}
+	}
+
+	if len(successes) == 0 && len(failures) == 0 {
+		return fmt.Sprintf("ℹ️ All topics already have %d or more partitions", a.Partitions), nil
+	}
+	return summarize("topic(s)", successes, failures), nil
+}
+
+func (a *ModifyAllPartitions) Preview(c Cluster) ([]string, error) {
+	topics, err := c.GetTopicDetails()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch topics: %w", err)
+	}
+	var changes []string
+	for _, topic := range topics {
+		if topic.Partitions < int(a.Partitions) {
+			changes = append(changes, fmt.Sprintf("%s: partitions %d→%d", topic.Name, topic.Partitions, a.Partitions))
+		}
+	}
+	return changes, nil
+}
+
+// ModifyConfig changes config overrides of one topic
+type ModifyConfig struct {
+	Topic   string  `json:"topic"`
+	Configs Configs `json:"configs"`
+}
+
+func (a *ModifyConfig) Validate() error {
+	if a.Topic == "" {
+		return fmt.Errorf("topic is required")
+	}
+	if len(a.Configs) == 0 {
+		return fmt.Errorf("configs are required")
+	}
+	return nil
+}
+
+func (a *ModifyConfig) Execute(c Cluster) (string, error) {
+	applied, failed := a.Configs.apply(c, a.Topic)
+	if len(failed) > 0 {
+		return fmt.Sprintf("⚠️ Partially updated topic '%s'.\nSuccessful: %s\nFailed: %s",
+			a.Topic, strings.Join(applied, ", "), strings.Join(failed, ", ")), nil
+	}
+	return fmt.Sprintf("✅ Successfully updated configuration for topic '%s':\n%s",
+		a.Topic, strings.Join(applied, "\n")), nil
+}
+
+// ModifyAllConfigs changes config overrides of every topic
+type ModifyAllConfigs struct {
+	Configs Configs `json:"configs"`
+}
+
+func (a *ModifyAllConfigs) Validate() error {
+	if len(a.Configs) == 0 {
+		return fmt.Errorf("configs are required")
+	}
+	return nil
+}
+
+func (a *ModifyAllConfigs) Execute(c Cluster) (string, error) {
+	return configureTopics(c, a.Configs, func(string) bool { return true }, "")
+}
+
+func (a *ModifyAllConfigs) Preview(c Cluster) ([]string, error) {
+	return previewConfigs(c, a.Configs, func(string) bool { return true })
+}
+
+// ModifyMatchingConfigs changes config overrides of the topics matching a
+// pattern: "starts_with:x", "contains:x", "ends_with:x" or an exact name
+type ModifyMatchingConfigs struct {
+	Pattern string  `json:"pattern"`
+	Configs Configs `json:"configs"`
+}
+
+func (a *ModifyMatchingConfigs) Validate() error {
+	if a.Pattern == "" {
+		return fmt.Errorf("pattern is required")
+	}
+	if len(a.Configs) == 0 {
+		return fmt.Errorf("configs are required")
+	}
+	return nil
+}
+
+func (a *ModifyMatchingConfigs) Execute(c Cluster) (string, error) {
+	return configureTopics(c, a.Configs, topicMatcher(a.Pattern), a.Pattern)
+}
+
+func (a *ModifyMatchingConfigs) Preview(c Cluster) ([]string, error) {
+	return previewConfigs(c, a.Configs, topicMatcher(a.Pattern))
+}
+
+// topicMatcher turns a modify_matching_configs pattern into a predicate
+func topicMatcher(pattern string) func(string) bool {
+	switch {
+	case strings.HasPrefix(pattern, "starts_with:"):
+		prefix := strings.TrimPrefix(pattern, "starts_with:")
+		return func(name string) bool { return strings.HasPrefix(name, prefix) }
+	case strings.HasPrefix(pattern, "contains:"):
+		substr := strings.TrimPrefix(pattern, "contains:")
+		return func(name string) bool { return strings.Contains(name, substr) }
+	case strings.HasPrefix(pattern, "ends_with:"):
+		suffix := strings.TrimPrefix(pattern, "ends_with:")
+		return func(name string) bool { return strings.HasSuffix(name, suffix) }
+	default:
+		return func(name string) bool { return name == pattern }
+	}
+}
+
+// configureTopics applies configs to every topic accepted by match. pattern
+// is only used to describe the selection in the summary.
+func configureTopics(c Cluster, configs Configs, match func(string) bool, pattern string) (string, error) {
+	topics, err := c.GetTopicDetails()
+	if err != nil {
+		return fmt.Sprintf("❌ Failed to fetch topics: %v", err), err
+	}
+
+	var successes, failures []string
+	matched := 0
+	for _, topic := range topics {
+		if !match(topic.Name) {
+			continue
+		}
+		matched++
+		applied, failed := configs.apply(c, topic.Name)
+		if len(applied) > 0 {
+			successes = append(successes, fmt.Sprintf("%s: %s", topic.Name, strings.Join(applied, ", ")))
+		}
+		if len(failed) > 0 {
+			failures = append(failures, fmt.Sprintf("%s: %s", topic.Name, strings.Join(failed, ", ")))
+			logger.Get().WithField("topic", topic.Name).WithField("failed", failed).Warn("Failed to apply config")
+		}
+	}
+
+	if pattern != "" && matched == 0 {
+		return fmt.Sprintf("ℹ️ No topics found matching pattern '%s'", pattern), nil
+	}
+	what := "topic(s)"
+	if pattern != "" {
+		what = fmt.Sprintf("topic(s) matching '%s'", pattern)
+	}
+	return summarize(what, successes, failures), nil
+}
+
+// previewConfigs lists the configs that differ from the new values on each
+// topic accepted by match. Topics already set as asked are left out.
+func previewConfigs(c Cluster, configs Configs, match func(string) bool) ([]string, error) {
+	topics, err := c.GetTopicDetails()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch topics: %w", err)
+	}
+	var names []string
+	for _, topic := range topics {
+		if match(topic.Name) {
+			names = append(names, topic.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	current, err := c.GetTopicConfigs(names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch topic configs: %w", err)
+	}
+
+	var changes []string
+	for _, name := range names {
+		var diffs []string
+		for _, key := range sortedKeys(configs) {
+			old, ok := current[name][key]
+			if ok && old == configs[key] {
+				continue
+			}
+			if !ok {
+				old = "(unset)"
+			}
+			diffs = append(diffs, fmt.Sprintf("%s %s→%s", key, old, configs[key]))
+		}
+		if len(diffs) > 0 {
+			changes = append(changes, fmt.Sprintf("%s: %s", name, strings.Join(diffs, ", ")))
+		}
+	}
+	return changes, nil
+}
+
+// summarize lists what was updated and what failed
+func summarize(what string, successes, failures []string) string {
+	var parts []string
+	if len(successes) > 0 {
+		parts = append(parts, bulletList(fmt.Sprintf("✅ Successfully updated %d %s:", len(successes), what), successes))
+	}
+	if len(failures) > 0 {
+		parts = append(parts, bulletList(fmt.Sprintf("❌ Failed to update %d topic(s):", len(failures)), failures))
+	}
+	return strings.Join(parts, "\n")
+}
+
+// TopicFilter selects topics for query_topics. Unset fields match anything.
+type TopicFilter struct {
+	NameContains          string `json:"name_contains"`
+	PartitionsGreaterThan *int   `json:"partitions_greater_than"`
+	ReplicationFactor     *int   `json:"replication_factor"`
+	Compression           string `json:"compression"` // "none" also matches the producer default
+}
+
+// QueryTopics lists the topics matching a filter
+type QueryTopics struct {
+	Filter TopicFilter `json:"filter"`
+}
+
+func (a *QueryTopics) Validate() error { return nil }
+
+func (a *QueryTopics) Execute(c Cluster) (string, error) {
+	topics, err := c.GetTopicDetails()
+	if err != nil {
+		return fmt.Sprintf("❌ Failed to fetch topics: %v", err), err
+	}
+
+	f := a.Filter
+	var candidates []kafka.TopicInfo
+	for _, topic := range topics {
+		if f.NameContains != "" && !strings.Contains(topic.Name, f.NameContains) {
+			continue
+		}
+		if f.PartitionsGreaterThan != nil && topic.Partitions <= *f.PartitionsGreaterThan {
+			continue
+		}
+		if f.ReplicationFactor != nil && topic.ReplicationFactor != *f.ReplicationFactor {
+			continue
+		}
+		candidates = append(candidates, topic)
+	}
+
+	// The configs of all candidates are fetched together rather than
+	// describing each topic in turn
+	matched := candidates
+	compression := make(map[string]string)
+	if f.Compression != "" && len(candidates) > 0 {
+		names := make([]string, len(candidates))
+		for i, topic := range candidates {
+			names[i] = topic.Name
+		}
+		configs, err := c.GetTopicConfigs(names)
+		if err != nil {
+			return fmt.Sprintf("❌ Failed to fetch topic configs: %v", err), err
+		}
+		matched = nil
+		for _, topic := range candidates {
+			config, ok := configs[topic.Name]
+			if ok {
+				compression[topic.Name] = config["compression.type"]
+				if !compressionMatches(compression[topic.Name], f.Compression) {
+					continue
+				}
+			}
+			matched = append(matched, topic)
+		}
+	}
+
+	if len(matched) == 0 {
+		return "No topics found matching the criteria.", nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Found %d topic(s) matching criteria:\n\n", len(matched)))
+	for _, topic := range matched {
+		sb.WriteString(fmt.Sprintf("📋 Topic: %s\n", topic.Name))
+		sb.WriteString(fmt.Sprintf("   • Partitions: %d\n", topic.Partitions))
+		sb.WriteString(fmt.Sprintf("   • Replication Factor: %d\n", topic.ReplicationFactor))
+		if ct, ok := compression[topic.Name]; ok {
+			if ct == "" || ct == "producer" {
+				sb.WriteString("   • Compression: none (using producer default)\n")
+			} else {
+				sb.WriteString(fmt.Sprintf("   • Compression: %s\n", ct))
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+// compressionMatches compares a topic's compression.type with a query, where
+// "none" also matches topics that leave compression to the producer
+func compressionMatches(actual, want string) bool {
+	if want == "none" {
+		return actual == "" || actual == "producer" || actual == "none"
+	}
+	return actual == want
+}
@@ -0,0 +1,290 @@
+package alerts
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"gopkg.in/yaml.v3"
+)
+
+// RuleType selects what a rule measures
+type RuleType string
+
+const (
+	// RuleConsumerLag fires when a consumer group's total lag exceeds the threshold
+	RuleConsumerLag RuleType = "consumer_lag"
+	// RuleUnderReplicated fires when more partitions than the threshold are under-replicated
+	RuleUnderReplicated RuleType = "under_replicated"
+	// RuleOfflinePartitions fires when more partitions than the threshold have no leader
+	RuleOfflinePartitions RuleType = "offline_partitions"
+	// RuleBrokerOffline fires when a broker seen earlier disappears from the
+	// metadata, when fewer brokers than the threshold are online, or when the
+	// cluster cannot be reached at all
+	RuleBrokerOffline RuleType = "broker_offline"
+)
+
+// Rule is a single alerting rule
+type Rule struct {
+	Name      string   `yaml:"name"`
+	Type      RuleType `yaml:"type"`
+	Group     string   `yaml:"group,omitempty"` // Glob of the groups a consumer_lag rule applies to, default all
+	Threshold int64    `yaml:"threshold"`
+}
+
+// Webhook payload formats
+const (
+	FormatJSON  = "json"  // kconduit's own payload, the default
+	FormatSlack = "slack" // Slack incoming webhook message, also understood by Mattermost and others
+)
+
+// Webhook is a URL that receives a POST when alerts fire or resolve
+type Webhook struct {
+	URL    string `yaml:"url"`
+	Format string `yaml:"format,omitempty"`
+}
+
+// Config is the alert rules file
+type Config struct {
+	Rules    []Rule    `yaml:"rules"`
+	Cluster  string    `yaml:"cluster,omitempty"` // Name of the cluster in notifications
+	Webhook  string    `yaml:"webhook,omitempty"` // Shorthand for a single JSON webhook
+	Webhooks []Webhook `yaml:"webhooks,omitempty"`
+	Desktop  bool      `yaml:"desktop,omitempty"` // Show a desktop notification when alerts fire
+}
+
+// AllWebhooks returns the webhooks to notify, including the webhook shorthand
+func (c *Config) AllWebhooks() []Webhook {
+	hooks := append([]Webhook(nil), c.Webhooks...)
+	if c.Webhook != "" {
+		hooks = append(hooks, Webhook{URL: c.Webhook, Format: FormatJSON})
+	}
+	return hooks
+}
+
+// LoadConfig reads and validates an alert rules file
+func LoadConfig(file string) (*Config, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alert rules: %w", err)
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig decodes and validates alert rules from YAML
+func ParseConfig(data []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse alert rules: %w", err)
+	}
+	if len(cfg.Rules) == 0 {
+		return nil, fmt.Errorf("no alert rules defined")
+	}
+
+	names := make(map[string]bool)
+	for i := range cfg.Rules {
+		r := &cfg.Rules[i]
+		if r.Name == "" {
+			r.Name = string(r.Type)
+		}
+		if names[r.Name] {
+			return nil, fmt.Errorf("rule %d: duplicate name %q", i+1, r.Name)
+		}
+		names[r.Name] = true
+
+		switch r.Type {
+		case RuleConsumerLag, RuleUnderReplicated, RuleOfflinePartitions, RuleBrokerOffline:
+		default:
+			return nil, fmt.Errorf("rule %q: unknown type %q", r.Name, r.Type)
+		}
+		if r.Threshold < 0 {
+			return nil, fmt.Errorf("rule %q: threshold must not be negative", r.Name)
+		}
+		if r.Group != "" {
+			if _, err := path.Match(r.Group, ""); err != nil {
+				return nil, fmt.Errorf("rule %q: invalid group pattern %q", r.Name, r.Group)
+			}
+		}
+	}
+
+	for i := range cfg.Webhooks {
+		w := &cfg.Webhooks[i]
+		if u, err := url.Parse(w.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("webhook %d: invalid url %q", i+1, w.URL)
+		}
+		switch w.Format {
+		case "":
+			w.Format = FormatJSON
+		case FormatJSON, FormatSlack:
+		default:
+			return nil, fmt.Errorf("webhook %d: unknown format %q, use json or slack", i+1, w.Format)
+		}
+	}
+	return &cfg, nil
+}
+
+// Snapshot is the cluster state rules are evaluated against
+type Snapshot struct {
+	Groups  []kafka.ConsumerGroupInfo
+	Stats   *kafka.ClusterStats
+	Brokers []kafka.BrokerInfo
+	// Unreachable is set when the brokers could not be listed. Every broker
+	// counts as offline and the other rules keep firing as they were, since
+	// nothing is known about them.
+	Unreachable bool
+}
+
+// Violation is a rule that currently fails for one subject
+type Violation struct {
+	Rule      string   `json:"rule"`
+	Type      RuleType `json:"type"`
+	Threshold int64    `json:"threshold"`
+	Subject   string   `json:"subject"` // Group ID, broker or "cluster"
+	Value     int64    `json:"value"`
+	Message   string   `json:"message"`
+}
+
+func (v Violation) key() string {
+	return v.Rule + "\x00" + v.Subject
+}
+
+// Evaluator checks snapshots against the rules and remembers which
+// violations are already firing, so notifications are only sent on changes
+type Evaluator struct {
+	rules   []Rule
+	brokers map[int32]string // Every broker seen so far, by ID
+	firing  map[string]Violation
+}
+
+// NewEvaluator returns an evaluator for the given rules
+func NewEvaluator(rules []Rule) *Evaluator {
+	return &Evaluator{
+		rules:   rules,
+		brokers: make(map[int32]string),
+		firing:  make(map[string]Violation),
+	}
+}
+
+// Evaluate returns every violation in the snapshot, plus those that started
+// and stopped since the previous call
+func (e *Evaluator) Evaluate(s Snapshot) (firing, started, resolved []Violation) {
+	for _, b := range s.Brokers {
+		if b.Status != kafka.BrokerOffline {
+			e.brokers[b.ID] = fmt.Sprintf("%s:%d", b.Host, b.Port)
+		} else if _, known := e.brokers[b.ID]; !known && b.ID >= 0 {
+			// Down since before the first snapshot, still holding replicas
+			e.brokers[b.ID] = "address unknown"
+		}
+	}
+	for _, r := range e.rules {
+		if s.Unreachable && r.Type != RuleBrokerOffline {
+			var kept []Violation
+			for _, v := range e.firing {
+				if v.Rule == r.Name {
+					kept = append(kept, v)
+				}
+			}
+			sortViolations(kept)
+			firing = append(firing, kept...)
+			continue
+		}
+		firing = append(firing, e.check(r, s)...)
+	}
+
+	current := make(map[string]Violation, len(firing))
+	for _, v := range firing {
+		current[v.key()] = v
+		if _, ok := e.firing[v.key()]; !ok {
+			started = append(started, v)
+		}
+	}
+	for k, v := range e.firing {
+		if _, ok := current[k]; !ok {
+			resolved = append(resolved, v)
+		}
+	}
+	e.firing = current
+
+	sortViolations(resolved)
+	return firing, started, resolved
+}
+
+func (e *Evaluator) check(r Rule, s Snapshot) []Violation {
+	out := e.violations(r, s)
+	for i := range out {
+		out[i].Threshold = r.Threshold
+	}
+	sortViolations(out)
+	return out
+}
+
+func (e *Evaluator) violations(r Rule, s Snapshot) []Violation {
+	var out []Violation
+	switch r.Type {
+	case RuleConsumerLag:
+		for _, g := range s.Groups {
+			if r.Group != "" {
+				if ok, _ := path.Match(r.Group, g.GroupID); !ok {
+					continue
+				}
+			}
+			if g.ConsumerLag > r.Threshold {
+				out = append(out, Violation{
+					Rule: r.Name, Type: r.Type, Subject: g.GroupID, Value: g.ConsumerLag,
+					Message: fmt.Sprintf("group %s lag %d > %d", g.GroupID, g.ConsumerLag, r.Threshold),
+				})
+			}
+		}
+	case RuleUnderReplicated, RuleOfflinePartitions:
+		if s.Stats == nil {
+			return nil
+		}
+		value, what := int64(s.Stats.UnderReplicatedPartitions), "under-replicated"
+		if r.Type == RuleOfflinePartitions {
+			value, what = int64(s.Stats.OfflinePartitions), "offline"
+		}
+		if value > r.Threshold {
+			out = append(out, Violation{
+				Rule: r.Name, Type: r.Type, Subject: "cluster", Value: value,
+				Message: fmt.Sprintf("%d %s partitions", value, what),
+			})
+		}
+	case RuleBrokerOffline:
+		if s.Brokers == nil && !s.Unreachable {
+			return nil
+		}
+		online := make(map[int32]bool, len(s.Brokers))
+		for _, b := range s.Brokers {
+			if b.Status != kafka.BrokerOffline {
+				online[b.ID] = true
+			}
+		}
+		for id, addr := range e.brokers {
+			if !online[id] {
+				out = append(out, Violation{
+					Rule: r.Name, Type: r.Type, Subject: fmt.Sprintf("broker %d", id), Value: int64(id),
+					Message: fmt.Sprintf("broker %d (%s) is offline", id, addr),
+				})
+			}
+		}
+		if s.Unreachable {
+			out = append(out, Violation{
+				Rule: r.Name, Type: r.Type, Subject: "cluster",
+				Message: "cluster is unreachable",
+			})
+		} else if count := int64(len(online)); r.Threshold > 0 && count < r.Threshold {
+			out = append(out, Violation{
+				Rule: r.Name, Type: r.Type, Subject: "cluster", Value: count,
+				Message: fmt.Sprintf("%d of %d brokers online", count, r.Threshold),
+			})
+		}
+	}
+	return out
+}
+
+func sortViolations(vs []Violation) {
+	sort.Slice(vs, func(i, j int) bool { return vs[i].key() < vs[j].key() })
+}
@@ -0,0 +1,139 @@
+package alerts
+
+import (
+	"testing"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr bool
+	}{
+		{"valid", "rules:\n  - name: lag\n    type: consumer_lag\n    group: orders-*\n    threshold: 100\n", false},
+		{"name defaults to type", "rules:\n  - type: under_replicated\n", false},
+		{"no rules", "webhook: http://example.com\n", true},
+		{"unknown type", "rules:\n  - type: disk_full\n", true},
+		{"duplicate name", "rules:\n  - type: broker_offline\n  - type: broker_offline\n", true},
+		{"negative threshold", "rules:\n  - type: consumer_lag\n    threshold: -1\n", true},
+		{"bad pattern", "rules:\n  - type: consumer_lag\n    group: \"[\"\n", true},
+		{"slack webhook", "rules:\n  - type: under_replicated\nwebhooks:\n  - url: https://hooks.slack.com/services/x\n    format: slack\n", false},
+		{"webhook without url", "rules:\n  - type: under_replicated\nwebhooks:\n  - format: slack\n", true},
+		{"unknown webhook format", "rules:\n  - type: under_replicated\nwebhooks:\n  - url: https://example.com\n    format: teams\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseConfig([]byte(tt.yaml))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvaluatorConsumerLag(t *testing.T) {
+	e := NewEvaluator([]Rule{{Name: "lag", Type: RuleConsumerLag, Group: "orders-*", Threshold: 100}})
+	snapshot := func(lag int64) Snapshot {
+		return Snapshot{Groups: []kafka.ConsumerGroupInfo{
+			{GroupID: "orders-service", ConsumerLag: lag},
+			{GroupID: "billing", ConsumerLag: 5000},
+		}}
+	}
+
+	firing, started, resolved := e.Evaluate(snapshot(500))
+	if len(firing) != 1 || firing[0].Subject != "orders-service" || len(started) != 1 || len(resolved) != 0 {
+		t.Fatalf("first evaluation: firing=%v started=%v resolved=%v", firing, started, resolved)
+	}
+
+	firing, started, _ = e.Evaluate(snapshot(600))
+	if len(firing) != 1 || len(started) != 0 {
+		t.Errorf("still firing should not start again: firing=%v started=%v", firing, started)
+	}
+
+	firing, _, resolved = e.Evaluate(snapshot(100))
+	if len(firing) != 0 || len(resolved) != 1 || resolved[0].Value != 600 {
+		t.Errorf("lag at threshold should resolve: firing=%v resolved=%v", firing, resolved)
+	}
+}
+
+func TestEvaluatorClusterRules(t *testing.T) {
+	e := NewEvaluator([]Rule{
+		{Name: "urp", Type: RuleUnderReplicated},
+		{Name: "offline", Type: RuleOfflinePartitions},
+		{Name: "brokers", Type: RuleBrokerOffline, Threshold: 3},
+	})
+	brokers := []kafka.BrokerInfo{{ID: 1, Host: "a", Port: 9092}, {ID: 2, Host: "b", Port: 9092}, {ID: 3, Host: "c", Port: 9092}}
+
+	firing, _, _ := e.Evaluate(Snapshot{Stats: &kafka.ClusterStats{}, Brokers: brokers})
+	if len(firing) != 0 {
+		t.Fatalf("healthy cluster fired %v", firing)
+	}
+
+	firing, started, _ := e.Evaluate(Snapshot{
+		Stats:   &kafka.ClusterStats{UnderReplicatedPartitions: 4},
+		Brokers: brokers[:2],
+	})
+	got := make(map[string]bool)
+	for _, v := range firing {
+		got[v.Rule+"/"+v.Subject] = true
+	}
+	for _, want := range []string{"urp/cluster", "brokers/broker 3", "brokers/cluster"} {
+		if !got[want] {
+			t.Errorf("missing violation %s in %v", want, firing)
+		}
+	}
+	if len(firing) != 3 || len(started) != 3 {
+		t.Errorf("firing=%d started=%d, want 3 and 3", len(firing), len(started))
+	}
+}
+
+func TestEvaluatorBrokerListedOffline(t *testing.T) {
+	e := NewEvaluator([]Rule{{Name: "brokers", Type: RuleBrokerOffline, Threshold: 2}})
+	firing, _, _ := e.Evaluate(Snapshot{Brokers: []kafka.BrokerInfo{
+		{ID: 1, Host: "a", Port: 9092, Status: kafka.BrokerOnline},
+		{ID: 2, Status: kafka.BrokerOffline},
+		{ID: -1, Host: "c", Port: 9092, Status: kafka.BrokerOffline},
+	}})
+	got := make(map[string]bool)
+	for _, v := range firing {
+		got[v.Subject] = true
+	}
+	if len(firing) != 2 || !got["broker 2"] || !got["cluster"] {
+		t.Errorf("broker offline since the first snapshot should fire: %v", firing)
+	}
+}
+
+func TestEvaluatorClusterUnreachable(t *testing.T) {
+	e := NewEvaluator([]Rule{
+		{Name: "lag", Type: RuleConsumerLag, Threshold: 10},
+		{Name: "brokers", Type: RuleBrokerOffline},
+	})
+	e.Evaluate(Snapshot{
+		Groups:  []kafka.ConsumerGroupInfo{{GroupID: "orders", ConsumerLag: 50}},
+		Brokers: []kafka.BrokerInfo{{ID: 1, Host: "a", Port: 9092}, {ID: 2, Host: "b", Port: 9092}},
+	})
+
+	firing, started, resolved := e.Evaluate(Snapshot{Unreachable: true})
+	got := make(map[string]bool)
+	for _, v := range firing {
+		got[v.Rule+"/"+v.Subject] = true
+	}
+	for _, want := range []string{"lag/orders", "brokers/broker 1", "brokers/broker 2", "brokers/cluster"} {
+		if !got[want] {
+			t.Errorf("missing violation %s in %v", want, firing)
+		}
+	}
+	// The lag alert is neither resolved nor started again without data
+	if len(started) != 3 || len(resolved) != 0 {
+		t.Errorf("started = %v, resolved = %v", started, resolved)
+	}
+
+	// Unreachable from the start still reports the cluster
+	firing, _, _ = NewEvaluator([]Rule{{Name: "brokers", Type: RuleBrokerOffline}}).Evaluate(Snapshot{Unreachable: true})
+	if len(firing) != 1 || firing[0].Subject != "cluster" {
+		t.Errorf("firing = %v, want the cluster unreachable", firing)
+	}
+}
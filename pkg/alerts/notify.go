@@ -0,0 +1,159 @@
+package alerts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/digitalis-io/kconduit/pkg/logger"
+)
+
+// Notifier delivers alert changes to webhooks and the desktop
+type Notifier struct {
+	cluster    string
+	webhooks   []Webhook
+	desktop    bool
+	httpClient *http.Client
+}
+
+// NewNotifier returns a notifier for the webhook and desktop settings of cfg
+func NewNotifier(cfg *Config) *Notifier {
+	return &Notifier{
+		cluster:    cfg.Cluster,
+		webhooks:   cfg.AllWebhooks(),
+		desktop:    cfg.Desktop,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// webhookPayload is the JSON body posted to json webhooks
+type webhookPayload struct {
+	Status  string      `json:"status"` // "firing" or "resolved"
+	Cluster string      `json:"cluster,omitempty"`
+	Time    time.Time   `json:"time"`
+	Alerts  []Violation `json:"alerts"`
+}
+
+// slackPayload is the body posted to slack webhooks
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+// slackMessage formats a payload as a Slack message, one line per alert
+func slackMessage(p webhookPayload) slackPayload {
+	icon, noun := ":rotating_light:", "firing"
+	if p.Status == "resolved" {
+		icon, noun = ":white_check_mark:", "resolved"
+	}
+	plural := "alert"
+	if len(p.Alerts) != 1 {
+		plural = "alerts"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s *%d %s %s*", icon, len(p.Alerts), plural, noun)
+	if p.Cluster != "" {
+		fmt.Fprintf(&sb, " on `%s`", p.Cluster)
+	}
+	for _, v := range p.Alerts {
+		fmt.Fprintf(&sb, "\n• *%s* (%s): %s", v.Rule, v.Type, v.Message)
+	}
+	return slackPayload{Text: sb.String()}
+}
+
+// Notify reports violations that started or resolved. Desktop notifications
+// are only shown for new violations.
+func (n *Notifier) Notify(started, resolved []Violation) error {
+	var errs []string
+	for _, p := range []webhookPayload{{Status: "firing", Alerts: started}, {Status: "resolved", Alerts: resolved}} {
+		if len(p.Alerts) == 0 {
+			continue
+		}
+		p.Cluster, p.Time = n.cluster, time.Now().UTC()
+		for _, w := range n.webhooks {
+			if err := n.post(w, p); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+	if n.desktop && len(started) > 0 {
+		msgs := make([]string, len(started))
+		for i, v := range started {
+			msgs[i] = v.Message
+		}
+		if err := desktopNotify("kconduit alert", strings.Join(msgs, "\n")); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to send alert notifications: %s", strings.Join(errs, "; "))
+	}
+	logger.Get().WithFields(logger.Fields{
+		"started":  len(started),
+		"resolved": len(resolved),
+	}).Info("Sent alert notifications")
+	return nil
+}
+
+func (n *Notifier) post(w Webhook, payload webhookPayload) error {
+	var body []byte
+	var err error
+	if w.Format == FormatSlack {
+		body, err = json.Marshal(slackMessage(payload))
+	} else {
+		body, err = json.Marshal(payload)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+	resp, err := n.httpClient.Post(w.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// The URL in the error carries the webhook's secret path
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("webhook %s request failed: %w", redactURL(w.URL), err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned %s", redactURL(w.URL), resp.Status)
+	}
+	return nil
+}
+
+// redactURL keeps the host of a webhook URL for error messages; Slack and
+// similar services put the secret in the path
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "(invalid url)"
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// desktopNotify shows a notification with notify-send on Linux and
+// osascript on macOS
+func desktopNotify(title, body string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "linux":
+		cmd = exec.Command("notify-send", title, body)
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", body, title)
+		cmd = exec.Command("osascript", "-e", script)
+	default:
+		return fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("desktop notification failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package alerts
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNotifierWebhooks(t *testing.T) {
+	bodies := make(map[string][]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies[r.URL.Path] = append(bodies[r.URL.Path], string(body))
+	}))
+	defer server.Close()
+
+	n := NewNotifier(&Config{
+		Cluster:  "prod",
+		Webhook:  server.URL + "/json",
+		Webhooks: []Webhook{{URL: server.URL + "/slack", Format: FormatSlack}},
+	})
+	started := []Violation{{Rule: "orders lag", Type: RuleConsumerLag, Threshold: 100, Subject: "orders", Value: 500, Message: "group orders lag 500 > 100"}}
+	resolved := []Violation{{Rule: "replication", Type: RuleUnderReplicated, Subject: "cluster", Value: 3, Message: "3 under-replicated partitions"}}
+	if err := n.Notify(started, resolved); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bodies["/json"]) != 2 || len(bodies["/slack"]) != 2 {
+		t.Fatalf("expected a firing and a resolved post to each webhook, got %v", bodies)
+	}
+	var payload webhookPayload
+	if err := json.Unmarshal([]byte(bodies["/json"][0]), &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.Status != "firing" || payload.Cluster != "prod" || len(payload.Alerts) != 1 || payload.Alerts[0].Threshold != 100 {
+		t.Errorf("unexpected json payload %s", bodies["/json"][0])
+	}
+
+	var slack slackPayload
+	if err := json.Unmarshal([]byte(bodies["/slack"][1]), &slack); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(slack.Text, "1 alert resolved* on `prod`") || !strings.Contains(slack.Text, "3 under-replicated partitions") {
+		t.Errorf("unexpected slack message %q", slack.Text)
+	}
+}
+
+func TestNotifierWebhookError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	n := NewNotifier(&Config{Webhooks: []Webhook{{URL: server.URL + "/secret-token", Format: FormatSlack}}})
+	err := n.Notify([]Violation{{Rule: "r", Message: "m"}}, nil)
+	if err == nil || !strings.Contains(err.Error(), "403") || strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("err = %v, want a 403 without the URL path", err)
+	}
+}
+
+func TestNotifierWebhookTransportError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	addr := server.URL
+	server.Close()
+
+	n := NewNotifier(&Config{Webhooks: []Webhook{{URL: addr + "/services/secret-token", Format: FormatSlack}}})
+	err := n.Notify([]Violation{{Rule: "r", Message: "m"}}, nil)
+	if err == nil || strings.Contains(err.Error(), "secret-token") || !strings.Contains(err.Error(), addr) {
+		t.Errorf("err = %v, want the webhook host without its path", err)
+	}
+}
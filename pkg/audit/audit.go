@@ -0,0 +1,75 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+)
+
+// Entry is one mutating operation, written as a JSON line
+type Entry struct {
+	Time      time.Time `json:"time"`
+	User      string    `json:"user"`                // Operating system user running kconduit
+	Principal string    `json:"principal,omitempty"` // SASL user the cluster saw
+	Cluster   string    `json:"cluster"`
+	Source    string    `json:"source"` // "user" or "ai"
+	Action    string    `json:"action"` // e.g. "topic.create", "acl.delete"
+	Resource  string    `json:"resource"`
+	Before    any       `json:"before,omitempty"`
+	After     any       `json:"after,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Log appends entries to an audit file. A nil *Log discards everything.
+type Log struct {
+	mu   sync.Mutex
+	file *os.File
+	user string
+}
+
+// Open appends to the audit file at path, creating it readable only by the owner
+func Open(path string) (*Log, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &Log{file: f, user: currentUser()}, nil
+}
+
+// Record writes an entry, filling in the time and user
+func (l *Log) Record(e Entry) error {
+	if l == nil {
+		return nil
+	}
+	e.Time = time.Now().UTC()
+	e.User = l.user
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return nil
+}
+
+// Close closes the audit file
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
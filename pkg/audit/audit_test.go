@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Record(Entry{Cluster: "kafka:9092", Source: "user", Action: "topic.create", Resource: "orders", After: map[string]int{"partitions": 3}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Record(Entry{Cluster: "kafka:9092", Source: "ai", Action: "topic.delete", Resource: "orders", Error: "boom"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), data)
+	}
+
+	var first Entry
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatal(err)
+	}
+	if first.Action != "topic.create" || first.Time.IsZero() || first.User == "" {
+		t.Errorf("unexpected first entry %+v", first)
+	}
+	if !strings.Contains(lines[1], `"error":"boom"`) || strings.Contains(lines[1], `"before"`) {
+		t.Errorf("unexpected second entry %s", lines[1])
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("audit log mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestNilLog(t *testing.T) {
+	var l *Log
+	if err := l.Record(Entry{Action: "topic.create"}); err != nil {
+		t.Errorf("nil log Record() = %v", err)
+	}
+	if err := l.Close(); err != nil {
+		t.Errorf("nil log Close() = %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+)
+
+// Snapshot is the cluster state exposed on one scrape
+type Snapshot struct {
+	Brokers    []kafka.BrokerInfo
+	Topics     []kafka.TopicInfo
+	TopicSizes map[string]int64
+	Groups     []kafka.ConsumerGroupInfo
+	Stats      *kafka.ClusterStats
+}
+
+// Exporter serves cluster metrics in the Prometheus text format
+type Exporter struct {
+	client *kafka.Client
+	mu     sync.Mutex // Serializes scrapes so slow clusters aren't queried concurrently
+}
+
+// New returns an exporter that gathers metrics with client
+func New(client *kafka.Client) *Exporter {
+	return &Exporter{client: client}
+}
+
+// Collect gathers a snapshot of the cluster
+func (e *Exporter) Collect() (*Snapshot, error) {
+	var s Snapshot
+	var err error
+	if s.Brokers, err = e.client.GetBrokers(); err != nil {
+		return nil, err
+	}
+	if s.Topics, err = e.client.GetTopicDetails(); err != nil {
+		return nil, err
+	}
+	if s.Groups, err = e.client.GetConsumerGroups(); err != nil {
+		return nil, err
+	}
+	if s.Stats, err = e.client.GetClusterStats(); err != nil {
+		return nil, err
+	}
+
+	var ids []int32
+	for _, b := range s.Brokers {
+		if b.Status == kafka.BrokerOnline {
+			ids = append(ids, b.ID)
+		}
+	}
+	// Sizes need DescribeLogDirs permissions, so the rest is still exported without them
+	if s.TopicSizes, err = e.client.GetTopicSizes(ids); err != nil {
+		logger.Get().WithError(err).Warn("Failed to get topic sizes")
+	}
+	return &s, nil
+}
+
+// ServeHTTP answers a scrape. kconduit_up is 0 when the cluster could not be queried.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	start := time.Now()
+	snapshot, err := e.Collect()
+	if err != nil {
+		logger.Get().WithError(err).Error("Scrape failed")
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	WriteMetrics(w, snapshot, time.Since(start))
+}
+
+// WriteMetrics renders a snapshot in the Prometheus text exposition format.
+// A nil snapshot only reports kconduit_up 0.
+func WriteMetrics(w io.Writer, s *Snapshot, took time.Duration) {
+	m := &metricWriter{w: w}
+	up := 0.0
+	if s != nil {
+		up = 1
+	}
+	m.gauge("kconduit_up", "Whether the last scrape of the cluster succeeded", nil, up)
+	m.gauge("kconduit_scrape_duration_seconds", "Time taken to gather the metrics", nil, took.Seconds())
+	if s == nil {
+		return
+	}
+
+	m.help("kconduit_broker_info", "Brokers present in the cluster metadata")
+	live, offline := 0, 0
+	for _, b := range s.Brokers {
+		if b.Status == kafka.BrokerOffline {
+			offline++
+			continue
+		}
+		live++
+		m.sample("kconduit_broker_info", labels{
+			"id": strconv.Itoa(int(b.ID)), "host": b.Host, "rack": b.Rack, "controller": strconv.FormatBool(b.IsController),
+		}, 1)
+	}
+	m.gauge("kconduit_brokers", "Number of brokers in the cluster metadata", nil, float64(live))
+	m.gauge("kconduit_brokers_offline", "Brokers missing from the cluster metadata that still hold replicas or are unreachable bootstrap servers", nil, float64(offline))
+
+	if s.Stats != nil {
+		m.gauge("kconduit_partitions", "Partitions of non-internal topics", nil, float64(s.Stats.TotalPartitions))
+		m.gauge("kconduit_under_replicated_partitions", "Partitions whose ISR is smaller than the replica set", nil, float64(s.Stats.UnderReplicatedPartitions))
+		m.gauge("kconduit_offline_partitions", "Partitions without a leader", nil, float64(s.Stats.OfflinePartitions))
+	}
+
+	m.help("kconduit_topic_partitions", "Number of partitions per topic")
+	for _, t := range s.Topics {
+		m.sample("kconduit_topic_partitions", labels{"topic": t.Name}, float64(t.Partitions))
+	}
+	m.help("kconduit_topic_replication_factor", "Replication factor per topic")
+	for _, t := range s.Topics {
+		m.sample("kconduit_topic_replication_factor", labels{"topic": t.Name}, float64(t.ReplicationFactor))
+	}
+	if s.TopicSizes != nil {
+		m.help("kconduit_topic_size_bytes", "Bytes on disk per topic, summed over all replicas")
+		for _, topic := range sortedKeys(s.TopicSizes) {
+			m.sample("kconduit_topic_size_bytes", labels{"topic": topic}, float64(s.TopicSizes[topic]))
+		}
+	}
+
+	m.help("kconduit_consumergroup_lag", "Total lag of a consumer group over all its partitions")
+	for _, g := range s.Groups {
+		m.sample("kconduit_consumergroup_lag", labels{"group": g.GroupID}, float64(g.ConsumerLag))
+	}
+	m.help("kconduit_consumergroup_members", "Members of a consumer group")
+	for _, g := range s.Groups {
+		m.sample("kconduit_consumergroup_members", labels{"group": g.GroupID, "state": g.State}, float64(g.NumMembers))
+	}
+}
+
+type labels map[string]string
+
+type metricWriter struct {
+	w io.Writer
+}
+
+func (m *metricWriter) help(name, help string) {
+	fmt.Fprintf(m.w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+func (m *metricWriter) gauge(name, help string, l labels, value float64) {
+	m.help(name, help)
+	m.sample(name, l, value)
+}
+
+func (m *metricWriter) sample(name string, l labels, value float64) {
+	fmt.Fprintf(m.w, "%s%s %s\n", name, formatLabels(l), strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatLabels(l labels) string {
+	if len(l) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(l))
+	for _, k := range sortedKeys(l) {
+		parts = append(parts, k+`="`+labelEscaper.Replace(l[k])+`"`)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,47 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	s := &Snapshot{
+		Brokers:    []kafka.BrokerInfo{{ID: 1, Host: "kafka-1", Rack: "a", IsController: true}},
+		Topics:     []kafka.TopicInfo{{Name: "orders", Partitions: 6, ReplicationFactor: 3}},
+		TopicSizes: map[string]int64{"orders": 2048},
+		Groups:     []kafka.ConsumerGroupInfo{{GroupID: `we"ird`, ConsumerLag: 42, NumMembers: 2, State: "Stable"}},
+		Stats:      &kafka.ClusterStats{TotalPartitions: 6, UnderReplicatedPartitions: 1},
+	}
+
+	var sb strings.Builder
+	WriteMetrics(&sb, s, 1500*time.Millisecond)
+	out := sb.String()
+
+	for _, want := range []string{
+		"kconduit_up 1\n",
+		"kconduit_scrape_duration_seconds 1.5\n",
+		`kconduit_broker_info{controller="true",host="kafka-1",id="1",rack="a"} 1` + "\n",
+		"kconduit_under_replicated_partitions 1\n",
+		`kconduit_topic_partitions{topic="orders"} 6` + "\n",
+		`kconduit_topic_size_bytes{topic="orders"} 2048` + "\n",
+		`kconduit_consumergroup_lag{group="we\"ird"} 42` + "\n",
+		`kconduit_consumergroup_members{group="we\"ird",state="Stable"} 2` + "\n",
+		"# TYPE kconduit_consumergroup_lag gauge\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteMetricsDown(t *testing.T) {
+	var sb strings.Builder
+	WriteMetrics(&sb, nil, time.Second)
+	if !strings.Contains(sb.String(), "kconduit_up 0\n") || strings.Contains(sb.String(), "kconduit_brokers") {
+		t.Errorf("unexpected output for failed scrape:\n%s", sb.String())
+	}
+}
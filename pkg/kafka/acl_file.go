@@ -0,0 +1,121 @@
+package kafka
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/IBM/sarama"
+)
+
+// ACLFile is the document format used to export and import ACLs
+type ACLFile struct {
+	ACLs []ACL `json:"acls" yaml:"acls"`
+}
+
+// MarshalACLs encodes ACLs as "yaml" or "json", sorted so exports diff cleanly
+func MarshalACLs(acls []ACL, format string) ([]byte, error) {
+	sorted := make([]ACL, len(acls))
+	copy(sorted, acls)
+	sort.Slice(sorted, func(i, j int) bool { return aclSortKey(sorted[i]) < aclSortKey(sorted[j]) })
+	return encodeFile(ACLFile{ACLs: sorted}, format)
+}
+
+// UnmarshalACLs decodes and validates an ACL file. Host, pattern type and
+// permission default to "*", Literal and Allow.
+func UnmarshalACLs(data []byte, format string) ([]ACL, error) {
+	var file ACLFile
+	if err := decodeFile(data, format, &file); err != nil {
+		return nil, err
+	}
+
+	acls := make([]ACL, 0, len(file.ACLs))
+	for i, acl := range file.ACLs {
+		acl = normalizeACL(acl)
+		if err := validateACL(acl); err != nil {
+			return nil, fmt.Errorf("acl %d: %w", i+1, err)
+		}
+		acls = append(acls, acl)
+	}
+	return acls, nil
+}
+
+// ACLDiff is the set of changes needed to go from the current ACLs to the
+// desired ones
+type ACLDiff struct {
+	Add       []ACL
+	Remove    []ACL // ACLs present in the cluster but not in the file
+	Unchanged []ACL
+}
+
+// DiffACLs compares the cluster's ACLs with the desired ones
+func DiffACLs(current, desired []ACL) ACLDiff {
+	currentSet := make(map[ACL]bool, len(current))
+	for _, acl := range current {
+		currentSet[normalizeACL(acl)] = true
+	}
+	desiredSet := make(map[ACL]bool, len(desired))
+	for _, acl := range desired {
+		desiredSet[normalizeACL(acl)] = true
+	}
+
+	var diff ACLDiff
+	for acl := range desiredSet {
+		if currentSet[acl] {
+			diff.Unchanged = append(diff.Unchanged, acl)
+		} else {
+			diff.Add = append(diff.Add, acl)
+		}
+	}
+	for acl := range currentSet {
+		if !desiredSet[acl] {
+			diff.Remove = append(diff.Remove, acl)
+		}
+	}
+
+	for _, list := range [][]ACL{diff.Add, diff.Remove, diff.Unchanged} {
+		sort.Slice(list, func(i, j int) bool { return aclSortKey(list[i]) < aclSortKey(list[j]) })
+	}
+	return diff
+}
+
+// String renders an ACL on one line for previews and logs
+func (a ACL) String() string {
+	return fmt.Sprintf("%s %s %s on %s:%s (%s) from %s",
+		a.Principal, a.PermissionType, a.Operation, a.ResourceType, a.ResourceName, a.PatternType, a.Host)
+}
+
+func normalizeACL(acl ACL) ACL {
+	if acl.Host == "" {
+		acl.Host = "*"
+	}
+	if acl.PatternType == "" {
+		acl.PatternType = "Literal"
+	}
+	if acl.PermissionType == "" {
+		acl.PermissionType = "Allow"
+	}
+	return acl
+}
+
+func validateACL(acl ACL) error {
+	switch {
+	case acl.Principal == "":
+		return fmt.Errorf("principal is required")
+	case acl.ResourceName == "":
+		return fmt.Errorf("resourceName is required")
+	case parseResourceType(acl.ResourceType) == sarama.AclResourceUnknown:
+		return fmt.Errorf("invalid resourceType %q", acl.ResourceType)
+	case parseOperation(acl.Operation) == sarama.AclOperationUnknown:
+		return fmt.Errorf("invalid operation %q", acl.Operation)
+	case parsePermissionType(acl.PermissionType) == sarama.AclPermissionUnknown:
+		return fmt.Errorf("invalid permission %q", acl.PermissionType)
+	case acl.PatternType != "Literal" && acl.PatternType != "Prefixed":
+		return fmt.Errorf("invalid patternType %q, use Literal or Prefixed", acl.PatternType)
+	}
+	return nil
+}
+
+func aclSortKey(a ACL) string {
+	return strings.Join([]string{a.Principal, a.ResourceType, a.ResourceName, a.PatternType, a.Operation, a.PermissionType, a.Host}, "\x00")
+}
@@ -0,0 +1,66 @@
+package kafka
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestACLFileRoundTrip(t *testing.T) {
+	acls := []ACL{
+		{Principal: "User:bob", Host: "*", Operation: "Read", PermissionType: "Allow", ResourceType: "Group", ResourceName: "billing", PatternType: "Literal"},
+		{Principal: "User:alice", Host: "*", Operation: "Write", PermissionType: "Deny", ResourceType: "Topic", ResourceName: "orders-", PatternType: "Prefixed"},
+	}
+
+	for _, format := range []string{"yaml", "json"} {
+		t.Run(format, func(t *testing.T) {
+			data, err := MarshalACLs(acls, format)
+			if err != nil {
+				t.Fatalf("MarshalACLs() error = %v", err)
+			}
+			got, err := UnmarshalACLs(data, format)
+			if err != nil {
+				t.Fatalf("UnmarshalACLs() error = %v", err)
+			}
+			want := []ACL{acls[1], acls[0]} // Sorted by principal
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("round trip = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalACLsDefaultsAndValidation(t *testing.T) {
+	got, err := UnmarshalACLs([]byte("acls:\n  - principal: User:alice\n    operation: Read\n    resourceType: Topic\n    resourceName: orders\n"), "yaml")
+	if err != nil {
+		t.Fatalf("UnmarshalACLs() error = %v", err)
+	}
+	want := []ACL{{Principal: "User:alice", Host: "*", Operation: "Read", PermissionType: "Allow", ResourceType: "Topic", ResourceName: "orders", PatternType: "Literal"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UnmarshalACLs() = %+v, want %+v", got, want)
+	}
+
+	_, err = UnmarshalACLs([]byte(`{"acls":[{"principal":"User:a","operation":"Read","resourceType":"Queue","resourceName":"x"}]}`), "json")
+	if err == nil || !strings.Contains(err.Error(), "resourceType") {
+		t.Errorf("UnmarshalACLs() error = %v, want invalid resourceType", err)
+	}
+}
+
+func TestDiffACLs(t *testing.T) {
+	read := ACL{Principal: "User:a", Host: "*", Operation: "Read", PermissionType: "Allow", ResourceType: "Topic", ResourceName: "t", PatternType: "Literal"}
+	write := read
+	write.Operation = "Write"
+	describe := read
+	describe.Operation = "Describe"
+
+	diff := DiffACLs([]ACL{read, write}, []ACL{read, describe})
+	if !reflect.DeepEqual(diff.Add, []ACL{describe}) {
+		t.Errorf("Add = %+v, want %+v", diff.Add, []ACL{describe})
+	}
+	if !reflect.DeepEqual(diff.Remove, []ACL{write}) {
+		t.Errorf("Remove = %+v, want %+v", diff.Remove, []ACL{write})
+	}
+	if !reflect.DeepEqual(diff.Unchanged, []ACL{read}) {
+		t.Errorf("Unchanged = %+v, want %+v", diff.Unchanged, []ACL{read})
+	}
+}
@@ -0,0 +1,104 @@
+package kafka
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ACLRole is a preset that expands into the ACLs a typical client needs
+type ACLRole string
+
+const (
+	ACLRoleProducer ACLRole = "producer"
+	ACLRoleConsumer ACLRole = "consumer"
+	ACLRoleAdmin    ACLRole = "admin"
+)
+
+// ACLRoles lists the available presets
+var ACLRoles = []ACLRole{ACLRoleProducer, ACLRoleConsumer, ACLRoleAdmin}
+
+// ACLPreset describes a role grant for one principal
+type ACLPreset struct {
+	Role        ACLRole
+	Principal   string
+	Host        string // Defaults to *
+	Topic       string
+	PatternType string // Literal or Prefixed, applies to the topic, group and transactional id
+	// Group is required for consumers and optional for admins
+	Group string
+	// TransactionalID optionally grants a producer use of transactions
+	TransactionalID string
+	// Idempotent grants a producer IdempotentWrite on the cluster, only needed
+	// by brokers older than Kafka 2.8
+	Idempotent bool
+}
+
+// ExpandACLPreset returns the Allow ACLs that implement a preset. The sets
+// match those of kafka-acls.sh --producer and --consumer; admin grants All on
+// the topic and, if given, the group.
+func ExpandACLPreset(p ACLPreset) ([]ACL, error) {
+	if strings.TrimSpace(p.Principal) == "" {
+		return nil, fmt.Errorf("principal is required")
+	}
+	if strings.TrimSpace(p.Topic) == "" {
+		return nil, fmt.Errorf("topic is required")
+	}
+	host := p.Host
+	if host == "" {
+		host = "*"
+	}
+	pattern := p.PatternType
+	if pattern == "" {
+		pattern = "Literal"
+	}
+
+	var acls []ACL
+	grant := func(resourceType, resourceName string, operations ...string) {
+		for _, op := range operations {
+			acls = append(acls, ACL{
+				Principal:      p.Principal,
+				Host:           host,
+				Operation:      op,
+				PermissionType: "Allow",
+				ResourceType:   resourceType,
+				ResourceName:   resourceName,
+				PatternType:    pattern,
+			})
+		}
+	}
+
+	switch p.Role {
+	case ACLRoleProducer:
+		grant("Topic", p.Topic, "Write", "Describe", "Create")
+		if p.TransactionalID != "" {
+			grant("TransactionalId", p.TransactionalID, "Write", "Describe")
+		}
+		if p.Idempotent {
+			// The cluster resource is always literal
+			acls = append(acls, ACL{
+				Principal:      p.Principal,
+				Host:           host,
+				Operation:      "IdempotentWrite",
+				PermissionType: "Allow",
+				ResourceType:   "Cluster",
+				ResourceName:   "kafka-cluster",
+				PatternType:    "Literal",
+			})
+		}
+	case ACLRoleConsumer:
+		if strings.TrimSpace(p.Group) == "" {
+			return nil, fmt.Errorf("consumer group is required for the consumer role")
+		}
+		grant("Topic", p.Topic, "Read", "Describe")
+		grant("Group", p.Group, "Read")
+	case ACLRoleAdmin:
+		grant("Topic", p.Topic, "All")
+		if p.Group != "" {
+			grant("Group", p.Group, "All")
+		}
+	default:
+		return nil, fmt.Errorf("unknown ACL role %q", p.Role)
+	}
+
+	return acls, nil
+}
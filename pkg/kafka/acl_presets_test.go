@@ -0,0 +1,91 @@
+package kafka
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpandACLPreset(t *testing.T) {
+	acl := func(resourceType, resourceName, operation, pattern string) ACL {
+		return ACL{
+			Principal:      "User:app",
+			Host:           "*",
+			Operation:      operation,
+			PermissionType: "Allow",
+			ResourceType:   resourceType,
+			ResourceName:   resourceName,
+			PatternType:    pattern,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		preset  ACLPreset
+		want    []ACL
+		wantErr bool
+	}{
+		{
+			name:   "producer",
+			preset: ACLPreset{Role: ACLRoleProducer, Principal: "User:app", Topic: "orders"},
+			want: []ACL{
+				acl("Topic", "orders", "Write", "Literal"),
+				acl("Topic", "orders", "Describe", "Literal"),
+				acl("Topic", "orders", "Create", "Literal"),
+			},
+		},
+		{
+			name: "transactional idempotent producer",
+			preset: ACLPreset{Role: ACLRoleProducer, Principal: "User:app", Topic: "orders-", PatternType: "Prefixed",
+				TransactionalID: "tx-", Idempotent: true},
+			want: []ACL{
+				acl("Topic", "orders-", "Write", "Prefixed"),
+				acl("Topic", "orders-", "Describe", "Prefixed"),
+				acl("Topic", "orders-", "Create", "Prefixed"),
+				acl("TransactionalId", "tx-", "Write", "Prefixed"),
+				acl("TransactionalId", "tx-", "Describe", "Prefixed"),
+				acl("Cluster", "kafka-cluster", "IdempotentWrite", "Literal"),
+			},
+		},
+		{
+			name:   "consumer",
+			preset: ACLPreset{Role: ACLRoleConsumer, Principal: "User:app", Topic: "orders", Group: "billing"},
+			want: []ACL{
+				acl("Topic", "orders", "Read", "Literal"),
+				acl("Topic", "orders", "Describe", "Literal"),
+				acl("Group", "billing", "Read", "Literal"),
+			},
+		},
+		{
+			name:   "admin without group",
+			preset: ACLPreset{Role: ACLRoleAdmin, Principal: "User:app", Topic: "orders"},
+			want:   []ACL{acl("Topic", "orders", "All", "Literal")},
+		},
+		{
+			name:    "consumer without group",
+			preset:  ACLPreset{Role: ACLRoleConsumer, Principal: "User:app", Topic: "orders"},
+			wantErr: true,
+		},
+		{
+			name:    "missing topic",
+			preset:  ACLPreset{Role: ACLRoleAdmin, Principal: "User:app"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown role",
+			preset:  ACLPreset{Role: "auditor", Principal: "User:app", Topic: "orders"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandACLPreset(tt.preset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExpandACLPreset() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExpandACLPreset() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package kafka
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/digitalis-io/kconduit/pkg/logger"
+)
+
+// ACLReplaceStage is the step at which ReplaceACL stopped
+type ACLReplaceStage string
+
+const (
+	ACLReplaceCreate ACLReplaceStage = "create" // Some replacements could not be created
+	ACLReplaceVerify ACLReplaceStage = "verify" // Replacements were created but are not all listed
+	ACLReplaceDelete ACLReplaceStage = "delete" // Replacements are in place but the original could not be deleted
+	ACLReplaceDone   ACLReplaceStage = "done"
+)
+
+// ACLReplaceResult reports what ReplaceACL changed, so callers can offer to
+// retry or roll back after a partial failure
+type ACLReplaceResult struct {
+	Stage           ACLReplaceStage
+	Created         []ACL // Replacements created by this call
+	Failed          []ACL // Replacements that could not be created or verified
+	OriginalDeleted bool
+}
+
+// ReplaceACL swaps original for replacements without leaving the principal
+// without permissions: replacements are created and verified first, and the
+// original is only deleted once they all exist. On failure the original is
+// kept and the result says which replacements were created. Replacements
+// that already exist are left alone, so rolling back the created ones never
+// removes access the principal had before.
+func (c *Client) ReplaceACL(original ACL, replacements []ACL) (*ACLReplaceResult, error) {
+	log := logger.Get()
+	result := &ACLReplaceResult{Stage: ACLReplaceCreate}
+	current, err := c.ListACLs()
+	if err != nil {
+		return result, fmt.Errorf("failed to list ACLs, nothing was changed: %w", err)
+	}
+	toCreate, keepOriginal := planACLReplace(original, replacements, current)
+	if c.journal != nil {
+		c.journal.Begin("edited ACL " + original.String())
+		defer c.journal.End()
+	}
+
+	var errs []string
+	for _, acl := range toCreate {
+		if err := c.CreateACL(acl); err != nil {
+			result.Failed = append(result.Failed, acl)
+			errs = append(errs, fmt.Sprintf("%s: %v", acl.Operation, err))
+			continue
+		}
+		result.Created = append(result.Created, acl)
+	}
+	if len(errs) > 0 {
+		return result, fmt.Errorf("failed to create %d of %d ACLs, the original ACL was kept: %s", len(errs), len(toCreate), strings.Join(errs, "; "))
+	}
+
+	result.Stage = ACLReplaceVerify
+	existing, err := c.ListACLs()
+	if err != nil {
+		return result, fmt.Errorf("failed to verify new ACLs, the original ACL was kept: %w", err)
+	}
+	if missing := missingACLs(existing, toCreate); len(missing) > 0 {
+		result.Failed = missing
+		return result, fmt.Errorf("%d new ACLs are not listed by the cluster, the original ACL was kept", len(missing))
+	}
+
+	result.Stage = ACLReplaceDelete
+	if !keepOriginal {
+		if err := c.DeleteACL(original); err != nil {
+			return result, fmt.Errorf("new ACLs are in place but the original could not be deleted: %w", err)
+		}
+		result.OriginalDeleted = true
+	}
+
+	result.Stage = ACLReplaceDone
+	log.WithFields(logger.Fields{
+		"principal": original.Principal,
+		"created":   len(result.Created),
+		"deleted":   result.OriginalDeleted,
+	}).Info("Replaced ACL")
+	return result, nil
+}
+
+// planACLReplace returns the replacements that are not among existing and
+// whether the original is itself one of the replacements
+func planACLReplace(original ACL, replacements, existing []ACL) (toCreate []ACL, keepOriginal bool) {
+	original = normalizeACL(original)
+	seen := make(map[ACL]bool, len(existing))
+	for _, acl := range existing {
+		seen[normalizeACL(acl)] = true
+	}
+	for _, acl := range replacements {
+		acl = normalizeACL(acl)
+		if acl == original {
+			keepOriginal = true
+			continue
+		}
+		if !seen[acl] {
+			seen[acl] = true
+			toCreate = append(toCreate, acl)
+		}
+	}
+	return toCreate, keepOriginal
+}
+
+// missingACLs returns the wanted ACLs that are not in existing
+func missingACLs(existing, wanted []ACL) []ACL {
+	set := make(map[ACL]bool, len(existing))
+	for _, acl := range existing {
+		set[normalizeACL(acl)] = true
+	}
+	var missing []ACL
+	for _, acl := range wanted {
+		if !set[normalizeACL(acl)] {
+			missing = append(missing, acl)
+		}
+	}
+	return missing
+}
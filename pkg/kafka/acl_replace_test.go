@@ -0,0 +1,51 @@
+package kafka
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlanACLReplace(t *testing.T) {
+	read := ACL{Principal: "User:a", Host: "*", Operation: "Read", PermissionType: "Allow", ResourceType: "Topic", ResourceName: "t", PatternType: "Literal"}
+	write := read
+	write.Operation = "Write"
+	describe := read
+	describe.Operation = "Describe"
+
+	tests := []struct {
+		name         string
+		replacements []ACL
+		existing     []ACL
+		wantCreate   []ACL
+		wantKeep     bool
+	}{
+		{"changed operation", []ACL{write}, []ACL{read}, []ACL{write}, false},
+		{"original kept with an extra operation", []ACL{read, write}, []ACL{read}, []ACL{write}, true},
+		{"duplicates collapsed", []ACL{write, write}, []ACL{read}, []ACL{write}, false},
+		{"defaults normalized", []ACL{{Principal: "User:a", Operation: "Read", ResourceType: "Topic", ResourceName: "t"}}, []ACL{read}, nil, true},
+		// Rolling back must not delete a binding the principal already had
+		{"existing replacement not created", []ACL{write, describe}, []ACL{read, {Principal: "User:a", Operation: "Write", ResourceType: "Topic", ResourceName: "t"}}, []ACL{describe}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotCreate, gotKeep := planACLReplace(read, tt.replacements, tt.existing)
+			if !reflect.DeepEqual(gotCreate, tt.wantCreate) || gotKeep != tt.wantKeep {
+				t.Errorf("planACLReplace() = %+v, %v, want %+v, %v", gotCreate, gotKeep, tt.wantCreate, tt.wantKeep)
+			}
+		})
+	}
+}
+
+func TestMissingACLs(t *testing.T) {
+	read := ACL{Principal: "User:a", Host: "*", Operation: "Read", PermissionType: "Allow", ResourceType: "Topic", ResourceName: "t", PatternType: "Literal"}
+	write := read
+	write.Operation = "Write"
+
+	if got := missingACLs([]ACL{read}, []ACL{read, write}); !reflect.DeepEqual(got, []ACL{write}) {
+		t.Errorf("missingACLs() = %+v, want %+v", got, []ACL{write})
+	}
+	if got := missingACLs([]ACL{read, write}, []ACL{read}); got != nil {
+		t.Errorf("missingACLs() = %+v, want none", got)
+	}
+}
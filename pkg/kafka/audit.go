@@ -0,0 +1,100 @@
+package kafka
+
+import (
+	"strings"
+
+	"github.com/IBM/sarama"
+
+	"github.com/digitalis-io/kconduit/pkg/audit"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+)
+
+// SetAuditLog records every mutating call made through the client to log,
+// which is closed together with the client
+func (c *Client) SetAuditLog(log *audit.Log) {
+	c.auditLog = log
+}
+
+// WithAuditSource returns a client sharing c's connections whose audit
+// entries are attributed to source, e.g. "ai" for assistant actions
+func (c *Client) WithAuditSource(source string) *Client {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	clone.auditSource = source
+	return &clone
+}
+
+// auditing reports whether mutations are recorded, so callers only fetch
+// before values when needed
+func (c *Client) auditing() bool {
+	return c.auditLog != nil
+}
+
+func (c *Client) audit(action, resource string, before, after any, err error) {
+	if c.auditLog == nil {
+		return
+	}
+	source := c.auditSource
+	if source == "" {
+		source = "user"
+	}
+	entry := audit.Entry{
+		Cluster:  strings.Join(c.brokers, ","),
+		Source:   source,
+		Action:   action,
+		Resource: resource,
+		Before:   before,
+		After:    after,
+	}
+	if c.config != nil && c.config.Net.SASL.Enable {
+		entry.Principal = c.config.Net.SASL.User
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if werr := c.auditLog.Record(entry); werr != nil {
+		logger.Get().WithError(werr).Error("Failed to write audit log")
+	}
+}
+
+// topicConfigState returns a topic's current value for key, including
+// broker defaults, and every config the topic overrides. The AlterConfig
+// behind UpdateTopicConfig replaces the whole override set, so undo needs
+// all of them, not just key.
+func (c *Client) topicConfigState(topic, key string) (string, map[string]string, bool) {
+	entries, err := c.admin.DescribeConfig(sarama.ConfigResource{
+		Type: sarama.TopicResource,
+		Name: topic,
+	})
+	if err != nil {
+		logger.Get().WithError(err).WithField("topic", topic).Debug("Failed to read current config value")
+		return "", nil, false
+	}
+	value, found := "", false
+	overrides := map[string]string{}
+	for _, entry := range entries {
+		if entry.Name == key {
+			value, found = entry.Value, true
+		}
+		// DescribeConfigs v0 only says whether a value is the default
+		if entry.Source == sarama.SourceTopic || (entry.Source == sarama.SourceUnknown && !entry.Default) {
+			overrides[entry.Name] = entry.Value
+		}
+	}
+	return value, overrides, found
+}
+
+// topicAuditState captures a topic's partitions and configs before a change
+func (c *Client) topicAuditState(name string) any {
+	config, err := c.GetTopicConfig(name)
+	if err != nil {
+		return nil
+	}
+	return map[string]any{
+		"partitions":        config.Partitions,
+		"replicationFactor": config.ReplicationFactor,
+		"configs":           config.Configs,
+	}
+}
@@ -0,0 +1,474 @@
+package kafka
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// BenchmarkOptions describes a benchmark run
+type BenchmarkOptions struct {
+	MessageSize int           // Bytes per message, at least 8 for the timestamp; default 1024
+	Rate        int           // Target messages per second, 0 for as fast as possible
+	Duration    time.Duration // How long to produce for, default 30s
+	Acks        string        // "all", "1" or "0", default "all"
+	Consume     bool          // Also consume the messages back and measure end-to-end latency
+
+	// OnProgress, when set, is called every second with the stats so far
+	OnProgress func(BenchmarkStats)
+}
+
+// BenchmarkStats is the progress or outcome of a benchmark
+type BenchmarkStats struct {
+	Elapsed  time.Duration
+	Sent     int64
+	Acked    int64
+	Errors   int64
+	Consumed int64
+	Bytes    int64 // Bytes acknowledged
+	LastErr  error
+
+	Produce  *LatencyHistogram // Send to acknowledgement
+	EndToEnd *LatencyHistogram // Send to consumed, when consuming
+}
+
+// MessagesPerSec returns the acknowledged message rate
+func (s BenchmarkStats) MessagesPerSec() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Acked) / s.Elapsed.Seconds()
+}
+
+// BytesPerSec returns the acknowledged throughput
+func (s BenchmarkStats) BytesPerSec() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Bytes) / s.Elapsed.Seconds()
+}
+
+// parseAcks maps the acks setting to sarama's
+func parseAcks(acks string) (sarama.RequiredAcks, error) {
+	switch strings.ToLower(acks) {
+	case "", "all", "-1":
+		return sarama.WaitForAll, nil
+	case "1", "leader":
+		return sarama.WaitForLocal, nil
+	case "0", "none":
+		return sarama.NoResponse, nil
+	}
+	return 0, fmt.Errorf("invalid acks %q: use all, 1 or 0", acks)
+}
+
+// Benchmark produces messages to topic at the configured size and rate, and
+// optionally consumes them back, measuring throughput and latency. Each
+// message carries its send time in the first 8 bytes, so the topic should be
+// one set aside for benchmarks.
+func (c *Client) Benchmark(ctx context.Context, topic string, opts BenchmarkOptions) (_ *BenchmarkStats, err error) {
+	ctx, span := startSpan(ctx, "Benchmark", attribute.String("topic", topic))
+	defer func() { endSpan(span, err) }()
+
+	if opts.MessageSize <= 0 {
+		opts.MessageSize = 1024
+	}
+	if opts.MessageSize < 8 {
+		return nil, fmt.Errorf("message size must be at least 8 bytes for the timestamp")
+	}
+	if opts.Duration <= 0 {
+		opts.Duration = 30 * time.Second
+	}
+	acks, err := parseAcks(opts.Acks)
+	if err != nil {
+		return nil, err
+	}
+
+	config := *c.config
+	config.Producer.RequiredAcks = acks
+	config.Producer.Return.Successes = true
+	config.Producer.Return.Errors = true
+	config.Producer.Idempotent = false
+
+	run := &benchmarkRun{
+		started:  time.Now(),
+		produce:  NewLatencyHistogram(),
+		endToEnd: NewLatencyHistogram(),
+	}
+
+	var stopConsumer func()
+	if opts.Consume {
+		stopConsumer, err = run.consume(ctx, c.brokers, &config, topic)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	producer, err := sarama.NewAsyncProducer(c.brokers, &config)
+	if err != nil {
+		if stopConsumer != nil {
+			stopConsumer()
+		}
+		return nil, fmt.Errorf("failed to create producer: %w", err)
+	}
+	var results sync.WaitGroup
+	results.Add(2)
+	go func() {
+		defer results.Done()
+		for msg := range producer.Successes() {
+			run.produce.Record(time.Since(msg.Metadata.(time.Time)))
+			run.acked.Add(1)
+			run.bytes.Add(int64(opts.MessageSize))
+		}
+	}()
+	go func() {
+		defer results.Done()
+		for perr := range producer.Errors() {
+			run.errors.Add(1)
+			run.setErr(perr.Err)
+		}
+	}()
+
+	progressDone := make(chan struct{})
+	if opts.OnProgress != nil {
+		go func() {
+			ticker := time.NewTicker(time.Second)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-progressDone:
+					return
+				case <-ticker.C:
+					opts.OnProgress(run.stats())
+				}
+			}
+		}()
+	}
+
+	// Paced from here, so setting up the consumer does not cause a burst
+	run.mu.Lock()
+	run.started = time.Now()
+	run.mu.Unlock()
+	run.produceFor(ctx, producer, topic, opts)
+
+	// Wait for the messages still in flight
+	producer.AsyncClose()
+	results.Wait()
+	if opts.Consume {
+		run.waitConsumed(ctx, 10*time.Second)
+		stopConsumer()
+	}
+	close(progressDone)
+
+	stats := run.stats()
+	if stats.Acked == 0 && stats.LastErr != nil {
+		return &stats, fmt.Errorf("no message was acknowledged: %w", stats.LastErr)
+	}
+	return &stats, nil
+}
+
+// benchmarkRun holds the counters of a benchmark, shared by the producer
+// and consumer goroutines
+type benchmarkRun struct {
+	started  time.Time
+	sent     atomic.Int64
+	acked    atomic.Int64
+	errors   atomic.Int64
+	consumed atomic.Int64
+	bytes    atomic.Int64
+
+	produce  *LatencyHistogram
+	endToEnd *LatencyHistogram
+
+	mu      sync.Mutex
+	lastErr error
+	ended   time.Time
+}
+
+func (r *benchmarkRun) setErr(err error) {
+	r.mu.Lock()
+	r.lastErr = err
+	r.mu.Unlock()
+}
+
+func (r *benchmarkRun) stats() BenchmarkStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	elapsed := time.Since(r.started)
+	if !r.ended.IsZero() {
+		elapsed = r.ended.Sub(r.started)
+	}
+	return BenchmarkStats{
+		Elapsed:  elapsed,
+		Sent:     r.sent.Load(),
+		Acked:    r.acked.Load(),
+		Errors:   r.errors.Load(),
+		Consumed: r.consumed.Load(),
+		Bytes:    r.bytes.Load(),
+		LastErr:  r.lastErr,
+		Produce:  r.produce,
+		EndToEnd: r.endToEnd,
+	}
+}
+
+// produceFor sends messages until the duration is up, pacing them when a
+// rate is set. Throughput is measured over this period.
+func (r *benchmarkRun) produceFor(ctx context.Context, producer sarama.AsyncProducer, topic string, opts BenchmarkOptions) {
+	payload := make([]byte, opts.MessageSize)
+	_, _ = rand.Read(payload)
+	deadline := time.NewTimer(opts.Duration)
+	defer deadline.Stop()
+	defer func() {
+		r.mu.Lock()
+		r.ended = time.Now()
+		r.mu.Unlock()
+	}()
+
+	send := func() bool {
+		value := make([]byte, len(payload))
+		copy(value, payload)
+		now := time.Now()
+		binary.BigEndian.PutUint64(value, uint64(now.UnixNano()))
+		msg := &sarama.ProducerMessage{Topic: topic, Value: sarama.ByteEncoder(value), Metadata: now}
+		select {
+		case producer.Input() <- msg:
+			r.sent.Add(1)
+			return true
+		case <-deadline.C:
+			return false
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	if opts.Rate <= 0 {
+		for send() {
+		}
+		return
+	}
+
+	// Top up to the number of messages due every few milliseconds, which
+	// holds rates above what a per-message ticker could manage
+	pace := time.NewTicker(5 * time.Millisecond)
+	defer pace.Stop()
+	for {
+		select {
+		case <-deadline.C:
+			return
+		case <-ctx.Done():
+			return
+		case <-pace.C:
+			due := int64(time.Since(r.started).Seconds() * float64(opts.Rate))
+			for r.sent.Load() < due {
+				if !send() {
+					return
+				}
+			}
+		}
+	}
+}
+
+// consume reads every partition of topic from its current end and records
+// how long each benchmark message took to arrive
+func (r *benchmarkRun) consume(ctx context.Context, brokers []string, config *sarama.Config, topic string) (func(), error) {
+	consumer, err := sarama.NewConsumer(brokers, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer: %w", err)
+	}
+	partitions, err := consumer.Partitions(topic)
+	if err != nil {
+		consumer.Close()
+		return nil, fmt.Errorf("failed to get partitions: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	var pcs []sarama.PartitionConsumer
+	for _, partition := range partitions {
+		pc, err := consumer.ConsumePartition(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			cancel()
+			for _, pc := range pcs {
+				pc.Close()
+			}
+			consumer.Close()
+			return nil, fmt.Errorf("failed to consume partition %d: %w", partition, err)
+		}
+		pcs = append(pcs, pc)
+		wg.Add(1)
+		go func(pc sarama.PartitionConsumer) {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case msg, ok := <-pc.Messages():
+					if !ok {
+						return
+					}
+					if len(msg.Value) < 8 {
+						continue
+					}
+					sent := time.Unix(0, int64(binary.BigEndian.Uint64(msg.Value)))
+					r.endToEnd.Record(time.Since(sent))
+					r.consumed.Add(1)
+				case err := <-pc.Errors():
+					if err != nil {
+						r.setErr(err)
+					}
+				}
+			}
+		}(pc)
+	}
+
+	return func() {
+		cancel()
+		wg.Wait()
+		for _, pc := range pcs {
+			pc.Close()
+		}
+		consumer.Close()
+	}, nil
+}
+
+// waitConsumed waits for the consumer to catch up with the acknowledged
+// messages, or for timeout
+func (r *benchmarkRun) waitConsumed(ctx context.Context, timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for r.consumed.Load() < r.acked.Load() && time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+// latencyBuckets are the upper bounds of the histogram buckets
+var latencyBuckets = []time.Duration{
+	time.Millisecond, 2 * time.Millisecond, 5 * time.Millisecond,
+	10 * time.Millisecond, 20 * time.Millisecond, 50 * time.Millisecond,
+	100 * time.Millisecond, 200 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 2 * time.Second, 5 * time.Second,
+}
+
+// LatencyHistogram counts latencies into fixed buckets, so long runs take no
+// more memory than short ones
+type LatencyHistogram struct {
+	mu     sync.Mutex
+	counts []int64 // One per bucket plus one for anything slower
+	total  int64
+	sum    time.Duration
+	max    time.Duration
+}
+
+func NewLatencyHistogram() *LatencyHistogram {
+	return &LatencyHistogram{counts: make([]int64, len(latencyBuckets)+1)}
+}
+
+// Record adds one latency
+func (h *LatencyHistogram) Record(d time.Duration) {
+	i := len(latencyBuckets)
+	for j, bound := range latencyBuckets {
+		if d <= bound {
+			i = j
+			break
+		}
+	}
+	h.mu.Lock()
+	h.counts[i]++
+	h.total++
+	h.sum += d
+	h.max = max(h.max, d)
+	h.mu.Unlock()
+}
+
+// HistogramBucket is the number of latencies up to Le, the bucket's upper
+// bound; the last bucket has no bound and Le is zero
+type HistogramBucket struct {
+	Le    time.Duration
+	Count int64
+}
+
+// Buckets returns the bucket counts, without the empty buckets at either end
+func (h *LatencyHistogram) Buckets() []HistogramBucket {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	first, last := -1, -1
+	for i, n := range h.counts {
+		if n > 0 {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 {
+		return nil
+	}
+	var buckets []HistogramBucket
+	for i := first; i <= last; i++ {
+		b := HistogramBucket{Count: h.counts[i]}
+		if i < len(latencyBuckets) {
+			b.Le = latencyBuckets[i]
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets
+}
+
+// Count returns how many latencies were recorded
+func (h *LatencyHistogram) Count() int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.total
+}
+
+// Mean returns the average latency
+func (h *LatencyHistogram) Mean() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.total == 0 {
+		return 0
+	}
+	return h.sum / time.Duration(h.total)
+}
+
+// Max returns the highest latency recorded
+func (h *LatencyHistogram) Max() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.max
+}
+
+// Percentile returns the upper bound of the bucket the p-th percentile falls
+// in, or the maximum for the open-ended last bucket
+func (h *LatencyHistogram) Percentile(p float64) time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.total == 0 {
+		return 0
+	}
+	rank := int64(p / 100 * float64(h.total))
+	if rank < 1 {
+		rank = 1
+	}
+	var seen int64
+	for i, n := range h.counts {
+		seen += n
+		if seen >= rank {
+			if i < len(latencyBuckets) {
+				return min(latencyBuckets[i], h.max)
+			}
+			break
+		}
+	}
+	return h.max
+}
@@ -0,0 +1,79 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+func TestLatencyHistogram(t *testing.T) {
+	h := NewLatencyHistogram()
+	if h.Percentile(50) != 0 || h.Buckets() != nil {
+		t.Fatalf("empty histogram reports latencies")
+	}
+	for i := 0; i < 90; i++ {
+		h.Record(3 * time.Millisecond)
+	}
+	for i := 0; i < 9; i++ {
+		h.Record(40 * time.Millisecond)
+	}
+	h.Record(7 * time.Second)
+
+	if h.Count() != 100 {
+		t.Errorf("count = %d", h.Count())
+	}
+	for p, want := range map[float64]time.Duration{50: 5 * time.Millisecond, 95: 50 * time.Millisecond, 100: 7 * time.Second} {
+		if got := h.Percentile(p); got != want {
+			t.Errorf("p%v = %v, want %v", p, got, want)
+		}
+	}
+	buckets := h.Buckets()
+	if len(buckets) != 11 || buckets[0].Le != 5*time.Millisecond || buckets[0].Count != 90 || buckets[10].Le != 0 || buckets[10].Count != 1 {
+		t.Errorf("buckets = %+v", buckets)
+	}
+}
+
+func TestParseAcks(t *testing.T) {
+	for acks, want := range map[string]sarama.RequiredAcks{"": sarama.WaitForAll, "all": sarama.WaitForAll, "1": sarama.WaitForLocal, "0": sarama.NoResponse} {
+		if got, err := parseAcks(acks); err != nil || got != want {
+			t.Errorf("parseAcks(%q) = %v, %v", acks, got, err)
+		}
+	}
+	if _, err := parseAcks("2"); err == nil {
+		t.Errorf("parseAcks(2) did not fail")
+	}
+}
+
+func TestBenchmarkRate(t *testing.T) {
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"ApiVersionsRequest": sarama.NewMockApiVersionsResponse(t),
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader("bench", 0, broker.BrokerID()),
+		"ProduceRequest": sarama.NewMockProduceResponse(t),
+	})
+
+	config := sarama.NewConfig()
+	config.Producer.Return.Successes = true
+	client := &Client{brokers: []string{broker.Addr()}, config: config}
+
+	stats, err := client.Benchmark(context.Background(), "bench", BenchmarkOptions{
+		MessageSize: 100,
+		Rate:        200,
+		Duration:    500 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 100 messages are due, give or take the last pacing tick
+	if stats.Acked < 90 || stats.Acked > 101 || stats.Acked != stats.Sent {
+		t.Errorf("sent %d, acked %d, want about 100", stats.Sent, stats.Acked)
+	}
+	if stats.Bytes != stats.Acked*100 || stats.Produce.Count() != stats.Acked {
+		t.Errorf("bytes %d, latencies %d for %d messages", stats.Bytes, stats.Produce.Count(), stats.Acked)
+	}
+}
@@ -0,0 +1,70 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// LogLevels are the log4j levels a broker logger can be set to, most
+// verbose first
+var LogLevels = []string{"TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL", "OFF"}
+
+// BrokerLogger is one log4j logger of a broker and its effective level
+type BrokerLogger struct {
+	Name  string
+	Level string
+}
+
+// GetBrokerLoggers returns the loggers of a broker sorted by name
+func (c *Client) GetBrokerLoggers(brokerID int32) (_ []BrokerLogger, err error) {
+	_, span := startSpan(context.Background(), "GetBrokerLoggers", attribute.Int("broker", int(brokerID)))
+	defer func() { endSpan(span, err) }()
+
+	entries, err := c.admin.DescribeConfig(sarama.ConfigResource{
+		Type: sarama.BrokerLoggerResource,
+		Name: strconv.Itoa(int(brokerID)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe loggers of broker %d: %w", brokerID, err)
+	}
+
+	loggers := make([]BrokerLogger, len(entries))
+	for i, e := range entries {
+		loggers[i] = BrokerLogger{Name: e.Name, Level: e.Value}
+	}
+	sort.Slice(loggers, func(i, j int) bool { return loggers[i].Name < loggers[j].Name })
+	return loggers, nil
+}
+
+// SetBrokerLoggerLevel changes the level of a broker logger. The broker does
+// not persist it: the level from its log4j config is back after a restart.
+// An empty level resets the logger to the root logger's level.
+func (c *Client) SetBrokerLoggerLevel(brokerID int32, name, level string) (err error) {
+	_, span := startSpan(context.Background(), "SetBrokerLoggerLevel",
+		attribute.Int("broker", int(brokerID)), attribute.String("logger", name))
+	defer func() { endSpan(span, err) }()
+
+	entry := sarama.IncrementalAlterConfigsEntry{Operation: sarama.IncrementalAlterConfigsOperationDelete}
+	if level != "" {
+		level = strings.ToUpper(level)
+		if !slices.Contains(LogLevels, level) {
+			return fmt.Errorf("invalid log level %q, use one of %s", level, strings.Join(LogLevels, ", "))
+		}
+		entry = sarama.IncrementalAlterConfigsEntry{Operation: sarama.IncrementalAlterConfigsOperationSet, Value: &level}
+	}
+
+	err = c.admin.IncrementalAlterConfig(sarama.BrokerLoggerResource, strconv.Itoa(int(brokerID)),
+		map[string]sarama.IncrementalAlterConfigsEntry{name: entry}, false)
+	if err != nil {
+		err = fmt.Errorf("failed to set %s on broker %d: %w", name, brokerID, err)
+	}
+	c.audit("broker.logger.set", fmt.Sprintf("broker/%d", brokerID), nil, map[string]any{"logger": name, "level": level}, err)
+	return err
+}
@@ -0,0 +1,14 @@
+package kafka
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetBrokerLoggerLevelRejectsUnknownLevel(t *testing.T) {
+	c := &Client{}
+	err := c.SetBrokerLoggerLevel(1, "kafka.request.logger", "VERBOSE")
+	if err == nil || !strings.Contains(err.Error(), "invalid log level") {
+		t.Fatalf("err = %v, want an invalid log level error", err)
+	}
+}
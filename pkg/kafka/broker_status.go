@@ -0,0 +1,96 @@
+package kafka
+
+import (
+	"net"
+	"sort"
+	"strconv"
+
+	"github.com/IBM/sarama"
+)
+
+// Broker statuses reported in BrokerInfo.Status
+const (
+	BrokerOnline  = "Online"
+	BrokerOffline = "Offline"
+	// Registered with the cluster but kconduit could not connect to it
+	BrokerUnreachable = "Unreachable"
+)
+
+// splitBrokerAddr splits a host:port address, defaulting to port 9092
+func splitBrokerAddr(addr string) (string, int32) {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr, 9092
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return addr, 9092
+	}
+	return host, int32(port)
+}
+
+// missingBrokers lists the brokers absent from the metadata: IDs still
+// assigned replicas of some partition, and bootstrap addresses that match
+// no live broker and cannot be reached. Metadata only holds live brokers,
+// so without this a dead broker silently disappears. An unreachable
+// bootstrap address has no known ID and is reported with ID -1. Bootstrap
+// addresses are only dialed when the live and dead brokers are fewer than
+// they name, so a healthy cluster is not probed on every refresh and a dead
+// broker is not listed twice.
+func missingBrokers(metadata *sarama.MetadataResponse, bootstrap []string, reachable func(addr string) bool) []BrokerInfo {
+	live := make(map[int32]bool, len(metadata.Brokers))
+	liveAddrs := make(map[string]bool, len(metadata.Brokers))
+	for _, b := range metadata.Brokers {
+		live[b.ID()] = true
+		liveAddrs[b.Addr()] = true
+	}
+
+	dead := map[int32]bool{}
+	for _, t := range metadata.Topics {
+		for _, p := range t.Partitions {
+			for _, ids := range [][]int32{p.Replicas, p.Isr, p.OfflineReplicas} {
+				for _, id := range ids {
+					if id >= 0 && !live[id] {
+						dead[id] = true
+					}
+				}
+			}
+		}
+	}
+
+	var missing []BrokerInfo
+	for id := range dead {
+		missing = append(missing, BrokerInfo{ID: id, ApiVersions: "Unknown", Status: BrokerOffline})
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i].ID < missing[j].ID })
+
+	// A bootstrap address may name a live broker by another host name, or a
+	// dead one already listed by ID, so one that matches no address only
+	// counts when there are more of them than brokers they could stand for
+	matched := 0
+	var unmatched []string
+	seen := map[string]bool{}
+	for _, addr := range bootstrap {
+		if seen[addr] {
+			continue
+		}
+		seen[addr] = true
+		if liveAddrs[addr] {
+			matched++
+		} else {
+			unmatched = append(unmatched, addr)
+		}
+	}
+	if len(unmatched) <= len(metadata.Brokers)-matched+len(dead) {
+		return missing
+	}
+
+	for _, addr := range unmatched {
+		if reachable(addr) {
+			continue
+		}
+		host, port := splitBrokerAddr(addr)
+		missing = append(missing, BrokerInfo{ID: -1, Host: host, Port: port, ApiVersions: "Unknown", Status: BrokerOffline})
+	}
+	return missing
+}
@@ -0,0 +1,90 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/IBM/sarama"
+)
+
+func TestMissingBrokers(t *testing.T) {
+	metadata := &sarama.MetadataResponse{}
+	metadata.AddBroker("kafka-1:9092", 1)
+	metadata.AddBroker("kafka-2:9092", 2)
+	metadata.AddTopicPartition("orders", 0, 1, []int32{1, 2, 3}, []int32{1, 2}, []int32{3}, sarama.ErrNoError)
+
+	dialed := map[string]bool{}
+	reachable := func(addr string) bool {
+		dialed[addr] = true
+		return addr == "localhost:9092"
+	}
+	// localhost stands for kafka-2 and broker 3 for one of the others, which
+	// leaves one more bootstrap address than brokers
+	bootstrap := []string{"kafka-1:9092", "localhost:9092", "kafka-8:9093", "kafka-9:9093", "kafka-9:9093"}
+
+	got := missingBrokers(metadata, bootstrap, reachable)
+	if len(got) != 3 {
+		t.Fatalf("got %d missing brokers, want 3: %+v", len(got), got)
+	}
+	if got[0].ID != 3 || got[0].Status != BrokerOffline || got[0].Host != "" {
+		t.Errorf("missing[0] = %+v, want offline broker 3", got[0])
+	}
+	for i, host := range []string{"kafka-8", "kafka-9"} {
+		if b := got[i+1]; b.ID != -1 || b.Host != host || b.Port != 9093 || b.Status != BrokerOffline {
+			t.Errorf("unreachable bootstrap = %+v, want %s:9093 offline with ID -1", b, host)
+		}
+	}
+	if dialed["kafka-1:9092"] {
+		t.Error("a bootstrap address of a live broker should not be dialed")
+	}
+
+	// A dead broker in the bootstrap list that still holds replicas is only
+	// listed by its ID
+	bootstrap = []string{"kafka-1:9092", "kafka-2:9092", "kafka-3:9092"}
+	got = missingBrokers(metadata, bootstrap, func(string) bool { return false })
+	if len(got) != 1 || got[0].ID != 3 || got[0].Status != BrokerOffline {
+		t.Errorf("got %+v, want broker 3 offline once", got)
+	}
+}
+
+func TestSplitBrokerAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		host string
+		port int32
+	}{
+		{"kafka:9093", "kafka", 9093},
+		{"[::1]:9094", "::1", 9094},
+		{"kafka", "kafka", 9092},
+	}
+	for _, tt := range tests {
+		if host, port := splitBrokerAddr(tt.addr); host != tt.host || port != tt.port {
+			t.Errorf("splitBrokerAddr(%q) = %q, %d, want %q, %d", tt.addr, host, port, tt.host, tt.port)
+		}
+	}
+}
+
+func TestMissingBrokersSkipsProbeWhenAllLive(t *testing.T) {
+	metadata := &sarama.MetadataResponse{}
+	metadata.AddBroker("kafka-1.internal:9092", 1)
+	metadata.AddBroker("kafka-2.internal:9092", 2)
+	metadata.AddBroker("kafka-3.internal:9092", 3)
+
+	dialed := 0
+	reachable := func(addr string) bool {
+		dialed++
+		return false
+	}
+	// Bootstrap through other names, or a load balancer, for a full cluster
+	for _, bootstrap := range [][]string{
+		{"kafka-1:9092", "kafka-2:9092", "kafka-3:9092"},
+		{"kafka.example.com:9092"},
+		{"kafka-1.internal:9092", "kafka-2:9092", "kafka-2:9092"},
+	} {
+		if got := missingBrokers(metadata, bootstrap, reachable); len(got) != 0 {
+			t.Errorf("%v: got missing brokers %+v", bootstrap, got)
+		}
+	}
+	if dialed != 0 {
+		t.Errorf("dialed %d bootstrap addresses with every broker live", dialed)
+	}
+}
@@ -5,25 +5,34 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"io"
 	"os"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/IBM/sarama"
+	"github.com/digitalis-io/kconduit/pkg/audit"
 	"github.com/digitalis-io/kconduit/pkg/logger"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"github.com/twmb/franz-go/pkg/kversion"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-const topicCacheDuration = 1 * time.Minute
-
 type Client struct {
-	brokers           []string
-	config            *sarama.Config
-	admin             sarama.ClusterAdmin
-	producer          sarama.SyncProducer
-	topics            []TopicInfo
-	topicsLastFetched time.Time
+	brokers     []string
+	config      *sarama.Config
+	admin       sarama.ClusterAdmin
+	producer    *sharedProducer
+	topicCache  *topicCache
+	auditLog    *audit.Log
+	auditSource string
+	journal     *Journal
+	session     *SessionRecorder
+	topicPolicy TopicPolicy
+	tunnel      io.Closer // SSH connection the brokers are reached through
 }
 
 // SASLConfig holds SASL authentication configuration
@@ -41,18 +50,73 @@ type TLSConfig struct {
 	CACert             string // Path to CA certificate file
 	ClientCert         string // Path to client certificate file
 	ClientKey          string // Path to client key file
+	ClientKeyPassword  string // Decrypts an encrypted client key, or the key in a JKS keystore
+	Keystore           string // PKCS12 or JKS file with the client certificate and key
+	KeystorePassword   string
+	Truststore         string // PKCS12 or JKS file with the CA certificates
+	TruststorePassword string
 	InsecureSkipVerify bool   // Skip server certificate verification
 }
 
 func NewClient(brokers []string) (*Client, error) {
-	return NewClientWithAuth(brokers, nil, nil)
+	return NewClientWithAuth(brokers, nil, nil, nil)
 }
 
-// NewClientWithAuth creates a new Kafka client with optional SASL authentication
-func NewClientWithAuth(brokers []string, saslConfig *SASLConfig, tlsConfig *TLSConfig) (*Client, error) {
+// NewClientWithAuth creates a new Kafka client with optional SASL
+// authentication, TLS and a tunnel or proxy to reach the brokers through
+func NewClientWithAuth(brokers []string, saslConfig *SASLConfig, tlsConfig *TLSConfig, netConfig *NetworkConfig) (*Client, error) {
 	log := logger.Get()
 	log.WithField("brokers", brokers).Debug("Creating new Kafka client")
 
+	config, err := newSaramaConfig(saslConfig, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	tunnel, err := configureNetwork(config, netConfig)
+	if err != nil {
+		return nil, err
+	}
+	closeTunnel := func() {
+		if tunnel != nil {
+			_ = tunnel.Close()
+		}
+	}
+
+	admin, err := sarama.NewClusterAdmin(brokers, config)
+	if err != nil {
+		closeTunnel()
+		log.WithError(err).WithField("brokers", brokers).Error("Failed to create cluster admin")
+		return nil, fmt.Errorf("failed to create cluster admin: %w", err)
+	}
+
+	producer, err := sarama.NewSyncProducer(brokers, config)
+	if err != nil {
+		if closeErr := admin.Close(); closeErr != nil {
+			log.WithError(closeErr).Warn("Failed to close admin client after producer creation failure")
+		}
+		closeTunnel()
+		log.WithError(err).WithField("brokers", brokers).Error("Failed to create producer")
+		return nil, fmt.Errorf("failed to create producer: %w", err)
+	}
+
+	log.WithField("brokers", brokers).Info("Successfully connected to Kafka cluster")
+	return &Client{
+		brokers:    brokers,
+		config:     config,
+		admin:      admin,
+		producer:   &sharedProducer{producer: producer},
+		journal:    NewJournal(),
+		topicCache: newTopicCache(DefaultTopicCacheTTL),
+		tunnel:     tunnel,
+	}, nil
+}
+
+// newSaramaConfig returns the client settings kconduit uses, with SASL and
+// TLS applied
+func newSaramaConfig(saslConfig *SASLConfig, tlsConfig *TLSConfig) (*sarama.Config, error) {
+	log := logger.Get()
+
 	config := sarama.NewConfig()
 	config.Version = sarama.V2_8_0_0
 	config.Producer.Return.Successes = true
@@ -125,6 +189,16 @@ func NewClientWithAuth(brokers []string, saslConfig *SASLConfig, tlsConfig *TLSC
 				}
 				tlsConf.RootCAs = caCertPool
 			}
+
+			// Or from a Java truststore
+			if tlsConfig.Truststore != "" {
+				log.WithField("truststore", tlsConfig.Truststore).Debug("Loading truststore")
+				pool, err := loadTruststore(tlsConfig.Truststore, tlsConfig.TruststorePassword)
+				if err != nil {
+					return nil, err
+				}
+				tlsConf.RootCAs = pool
+			}
 			
 			// Load client certificate and key if provided
 			if tlsConfig.ClientCert != "" && tlsConfig.ClientKey != "" {
@@ -133,7 +207,17 @@ func NewClientWithAuth(brokers []string, saslConfig *SASLConfig, tlsConfig *TLSC
 					"client_key":  tlsConfig.ClientKey,
 				}).Debug("Loading client certificate and key")
 				
-				cert, err := tls.LoadX509KeyPair(tlsConfig.ClientCert, tlsConfig.ClientKey)
+				cert, err := loadClientCertificate(tlsConfig.ClientCert, tlsConfig.ClientKey, tlsConfig.ClientKeyPassword)
+				if err != nil {
+					return nil, fmt.Errorf("failed to load client certificate: %w", err)
+				}
+				tlsConf.Certificates = []tls.Certificate{cert}
+			}
+
+			// Or from a Java keystore
+			if tlsConfig.Keystore != "" {
+				log.WithField("keystore", tlsConfig.Keystore).Debug("Loading keystore")
+				cert, err := loadKeystore(tlsConfig.Keystore, tlsConfig.KeystorePassword, tlsConfig.ClientKeyPassword)
 				if err != nil {
 					return nil, fmt.Errorf("failed to load client certificate: %w", err)
 				}
@@ -144,31 +228,13 @@ func NewClientWithAuth(brokers []string, saslConfig *SASLConfig, tlsConfig *TLSC
 		config.Net.TLS.Config = tlsConf
 	}
 
-	admin, err := sarama.NewClusterAdmin(brokers, config)
-	if err != nil {
-		log.WithError(err).WithField("brokers", brokers).Error("Failed to create cluster admin")
-		return nil, fmt.Errorf("failed to create cluster admin: %w", err)
-	}
-
-	producer, err := sarama.NewSyncProducer(brokers, config)
-	if err != nil {
-		if closeErr := admin.Close(); closeErr != nil {
-			log.WithError(closeErr).Warn("Failed to close admin client after producer creation failure")
-		}
-		log.WithError(err).WithField("brokers", brokers).Error("Failed to create producer")
-		return nil, fmt.Errorf("failed to create producer: %w", err)
-	}
-
-	log.WithField("brokers", brokers).Info("Successfully connected to Kafka cluster")
-	return &Client{
-		brokers:  brokers,
-		config:   config,
-		admin:    admin,
-		producer: producer,
-	}, nil
+	return config, nil
 }
 
-func (c *Client) ListTopics() ([]string, error) {
+func (c *Client) ListTopics() (_ []string, err error) {
+	_, span := startSpan(context.Background(), "ListTopics")
+	defer func() { endSpan(span, err) }()
+
 	metadata, err := c.admin.ListTopics()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list topics: %w", err)
@@ -184,9 +250,12 @@ func (c *Client) ListTopics() ([]string, error) {
 	return topics, nil
 }
 
-func (c *Client) GetTopicDetails() ([]TopicInfo, error) {
-	if c.topicsLastFetched.Add(topicCacheDuration).After(time.Now()) && len(c.topics) > 0 {
-		return c.topics, nil
+func (c *Client) GetTopicDetails() (_ []TopicInfo, err error) {
+	_, span := startSpan(context.Background(), "GetTopicDetails")
+	defer func() { endSpan(span, err) }()
+
+	if topics, ok := c.topicCache.get(); ok {
+		return topics, nil
 	}
 
 	metadata, err := c.admin.ListTopics()
@@ -209,13 +278,20 @@ func (c *Client) GetTopicDetails() ([]TopicInfo, error) {
 		return topicInfos[i].Name < topicInfos[j].Name
 	})
 
-	// Cache topics
-	c.topics = topicInfos
-
-	return c.topics, nil
+	c.topicCache.set(topicInfos)
+	return topicInfos, nil
 }
 
 func (c *Client) GetTopicConfig(topicName string) (*TopicConfig, error) {
+	return c.GetTopicConfigContext(context.Background(), topicName)
+}
+
+// GetTopicConfigContext is GetTopicConfig that gives up between requests
+// once ctx is cancelled, so a superseded lookup stops early
+func (c *Client) GetTopicConfigContext(ctx context.Context, topicName string) (_ *TopicConfig, err error) {
+	_, span := startSpan(ctx, "DescribeTopicConfig", attribute.String("topic", topicName))
+	defer func() { endSpan(span, err) }()
+
 	// Get topic metadata
 	metadata, err := c.admin.ListTopics()
 	if err != nil {
@@ -232,9 +308,14 @@ func (c *Client) GetTopicConfig(topicName string) (*TopicConfig, error) {
 		Partitions:        int(topicMeta.NumPartitions),
 		ReplicationFactor: int(topicMeta.ReplicationFactor),
 		Configs:           make(map[string]string),
+		Sources:           make(map[string]string),
 		PartitionDetails:  make([]PartitionInfo, 0),
 	}
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Get topic configuration
 	resource := sarama.ConfigResource{
 		Type: sarama.TopicResource,
@@ -245,9 +326,14 @@ func (c *Client) GetTopicConfig(topicName string) (*TopicConfig, error) {
 	if err == nil && configs != nil {
 		for _, entry := range configs {
 			config.Configs[entry.Name] = entry.Value
+			config.Sources[entry.Name] = configSourceLabel(entry)
 		}
 	}
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Get partition details
 	controller, err := c.admin.Controller()
 	if err == nil {
@@ -283,7 +369,10 @@ func (c *Client) GetTopicConfig(topicName string) (*TopicConfig, error) {
 	return config, nil
 }
 
-func (c *Client) GetBrokers() ([]BrokerInfo, error) {
+func (c *Client) GetBrokers() (_ []BrokerInfo, err error) {
+	_, span := startSpan(context.Background(), "GetBrokers")
+	defer func() { endSpan(span, err) }()
+
 	log := logger.Get()
 
 	// Get the controller broker
@@ -331,7 +420,7 @@ func (c *Client) GetBrokers() ([]BrokerInfo, error) {
 			Host:   host,
 			Port:   port,
 			Rack:   broker.Rack(),
-			Status: "Online", // Brokers in metadata are online
+			Status: BrokerOnline, // Brokers in metadata are online
 		}
 
 		// Check if this broker is the controller
@@ -346,29 +435,29 @@ func (c *Client) GetBrokers() ([]BrokerInfo, error) {
 			}).Info("Found active controller broker")
 		}
 
-		// Try to get API versions from the broker
-		// First ensure broker is connected
-		if err := broker.Open(c.config); err == nil {
-			connected, _ := broker.Connected()
-			if connected {
-				apiVersions, err := broker.ApiVersions(&sarama.ApiVersionsRequest{})
-				if err == nil && apiVersions != nil && len(apiVersions.ApiKeys) > 0 {
-					// Get Kafka version from API versions
-					info.ApiVersions = c.getKafkaVersion(apiVersions.ApiKeys)
-					info.ListenerCount = len(apiVersions.ApiKeys)
-				}
+		// Get the API versions and features from the broker
+		if conn, err := dialRaw(c.config, broker.Addr()); err == nil {
+			if len(conn.apiVersions.ApiKeys) > 0 {
+				info.ApiVersions = kafkaVersion(conn.apiVersions)
+				info.ListenerCount = len(conn.apiVersions.ApiKeys)
 			}
+			conn.Close()
+		} else {
+			info.Status = BrokerUnreachable
+			log.WithError(err).WithField("broker", broker.ID()).Debug("Failed to get API versions")
 		}
 
-		// If we still don't have version, use a default
 		if info.ApiVersions == "" {
-			info.ApiVersions = "2.8+" // Based on our config version
+			info.ApiVersions = "Unknown"
 		}
 
 		// Get log dir count (requires broker connection)
 		if descLogDirs, err := c.admin.DescribeLogDirs([]int32{broker.ID()}); err == nil {
 			if logDirs, ok := descLogDirs[broker.ID()]; ok {
 				info.LogDirCount = len(logDirs)
+				for _, dir := range summarizeLogDirs(logDirs) {
+					info.DiskUsed += dir.Size
+				}
 			}
 		}
 
@@ -379,11 +468,26 @@ func (c *Client) GetBrokers() ([]BrokerInfo, error) {
 		return brokers[i].ID < brokers[j].ID
 	})
 
+	// Brokers that dropped out of the metadata are listed as offline
+	missing := missingBrokers(metadata, c.brokers, func(addr string) bool {
+		conn, err := dialRaw(c.config, addr)
+		if err != nil {
+			log.WithError(err).WithField("broker", addr).Debug("Bootstrap broker unreachable")
+			return false
+		}
+		conn.Close()
+		return true
+	})
+	brokers = append(brokers, missing...)
+
 	return brokers, nil
 }
 
 // GetClusterStats retrieves cluster-wide partition and replication statistics
-func (c *Client) GetClusterStats() (*ClusterStats, error) {
+func (c *Client) GetClusterStats() (_ *ClusterStats, err error) {
+	_, span := startSpan(context.Background(), "GetClusterStats")
+	defer func() { endSpan(span, err) }()
+
 	log := logger.Get()
 	
 	// Get controller for metadata request
@@ -439,92 +543,46 @@ func (c *Client) GetClusterStats() (*ClusterStats, error) {
 	return stats, nil
 }
 
-func (c *Client) getKafkaVersion(apiKeys []sarama.ApiVersionsResponseKey) string {
-	// Determine Kafka version based on API versions
-	if len(apiKeys) == 0 {
+// versionPrefix matches the v kversion puts before release numbers
+var versionPrefix = regexp.MustCompile(`\bv(\d)`)
+
+// kafkaVersion names the release a broker runs, matched from the highest
+// version of every API it supports. Patch releases do not change those, so
+// only major.minor is known. A KRaft cluster still running an older
+// metadata.version, as during an upgrade, has that shown too.
+func kafkaVersion(resp *kmsg.ApiVersionsResponse) string {
+	if len(resp.ApiKeys) == 0 {
 		return "Unknown"
 	}
+	version := versionPrefix.ReplaceAllString(kversion.FromApiVersionsResponse(resp).VersionGuess(), "$1")
 
-	maxApiKey := int16(0)
-	apiVersionMap := make(map[int16]int16)
-
-	for _, api := range apiKeys {
-		if api.ApiKey > maxApiKey {
-			maxApiKey = api.ApiKey
+	for _, f := range resp.FinalizedFeatures {
+		if f.Name != MetadataVersionFeature {
+			continue
 		}
-		apiVersionMap[api.ApiKey] = api.MaxVersion
-	}
-
-	// More accurate version detection based on specific API keys
-	// Check for API keys introduced in different Kafka versions
-
-	// Kafka 3.0+ introduced API key 67 (DescribeCluster)
-	if _, hasKey67 := apiVersionMap[67]; hasKey67 {
-		if maxApiKey >= 68 {
-			return "3.5+"
+		if release := metadataVersionRelease(f.MaxVersionLevel); !strings.HasSuffix(version, release) {
+			version += " (metadata " + release + ")"
 		}
-		return "3.0+"
-	}
-
-	// Kafka 2.8 introduced API key 60 (DescribeQuorum)
-	if _, hasKey60 := apiVersionMap[60]; hasKey60 {
-		return "2.8+"
-	}
-
-	// Kafka 2.6 introduced API key 48 (DescribeClientQuotas)
-	if _, hasKey48 := apiVersionMap[48]; hasKey48 {
-		return "2.6+"
-	}
-
-	// Kafka 2.4 introduced API key 43 (ElectLeaders)
-	if _, hasKey43 := apiVersionMap[43]; hasKey43 {
-		return "2.4+"
 	}
-
-	// Kafka 2.0 introduced API key 36 (SaslAuthenticate)
-	if _, hasKey36 := apiVersionMap[36]; hasKey36 {
-		return "2.0+"
-	}
-
-	// Check by max API key for older versions
-	if maxApiKey >= 35 {
-		return "2.0+"
-	}
-
-	if maxApiKey >= 20 {
-		return "1.0+"
-	}
-
-	return "0.11+"
+	return version
 }
 
-func (c *Client) CreateTopic(name string, numPartitions int32, replicationFactor int16) error {
+func (c *Client) CreateTopic(name string, numPartitions int32, replicationFactor int16) (err error) {
+	_, span := startSpan(context.Background(), "CreateTopic", attribute.String("topic", name))
+	defer func() { endSpan(span, err) }()
+
 	if name == "" {
 		return fmt.Errorf("topic name cannot be empty")
 	}
 
-	if numPartitions < 1 {
-		numPartitions = 1
-	}
-
-	if replicationFactor < 1 {
-		replicationFactor = 1
-	}
-
-	topicDetail := &sarama.TopicDetail{
-		NumPartitions:     numPartitions,
-		ReplicationFactor: replicationFactor,
-	}
-
-	err := c.admin.CreateTopic(name, topicDetail, false)
-	if err != nil {
-		return fmt.Errorf("failed to create topic: %w", err)
-	}
-
-	return nil
+	// Unset partitions and replication factor take the topic policy defaults
+	return c.createTopicLike(TopicSnapshot{Name: name, Partitions: numPartitions, ReplicationFactor: replicationFactor})
 }
 
-func (c *Client) DeleteTopic(name string) error {
+func (c *Client) DeleteTopic(name string) (err error) {
+	_, span := startSpan(context.Background(), "DeleteTopic", attribute.String("topic", name))
+	defer func() { endSpan(span, err) }()
+
 	log := logger.Get()
 
 	if name == "" {
@@ -533,35 +591,96 @@ func (c *Client) DeleteTopic(name string) error {
 
 	log.WithField("topic", name).Info("Deleting topic")
 
+	var before any
+	if c.auditing() {
+		before = c.topicAuditState(name)
+	}
+
 	// Delete the topic
-	err := c.admin.DeleteTopic(name)
+	err = c.admin.DeleteTopic(name)
+	c.audit("topic.delete", name, before, nil, err)
 	if err != nil {
 		log.WithField("topic", name).WithError(err).Error("Failed to delete topic")
 		return fmt.Errorf("failed to delete topic: %w", err)
 	}
+	c.topicCache.invalidate()
+	c.recordChange(Change{Kind: ChangeTopicDelete, Topic: name})
 
 	log.WithField("topic", name).Info("Successfully deleted topic")
 	return nil
 }
 
-func (c *Client) ProduceMessage(topic, key, value string) error {
+// ProduceOptions carries optional per-message settings for ProduceMessage
+type ProduceOptions struct {
+	Headers map[string]string
+	// Timestamp overrides the message timestamp; zero uses the current time.
+	// Topics with message.timestamp.type=LogAppendTime ignore it.
+	Timestamp time.Time
+}
+
+// ProduceMessage sends a message and returns the partition and offset it was written to
+func (c *Client) ProduceMessage(topic, key, value string, opts ProduceOptions) (_ int32, _ int64, err error) {
+	_, span := startSpan(context.Background(), "Produce", attribute.String("topic", topic))
+	defer func() { endSpan(span, err) }()
+
 	msg := &sarama.ProducerMessage{
-		Topic: topic,
-		Value: sarama.StringEncoder(value),
+		Topic:     topic,
+		Value:     sarama.StringEncoder(value),
+		Timestamp: opts.Timestamp,
 	}
 
 	if key != "" {
 		msg.Key = sarama.StringEncoder(key)
 	}
 
-	partition, offset, err := c.producer.SendMessage(msg)
+	if len(opts.Headers) > 0 {
+		// Sort for a stable header order on the wire
+		names := make([]string, 0, len(opts.Headers))
+		for name := range opts.Headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			msg.Headers = append(msg.Headers, sarama.RecordHeader{
+				Key:   []byte(name),
+				Value: []byte(opts.Headers[name]),
+			})
+		}
+	}
+
+	partition, offset, err := c.producer.send(msg)
 	if err != nil {
-		return fmt.Errorf("failed to send message: %w", err)
+		return 0, 0, fmt.Errorf("failed to send message: %w", err)
 	}
 
-	_ = partition
-	_ = offset
-	return nil
+	return partition, offset, nil
+}
+
+// ParseTimestamp parses a message timestamp given as Unix milliseconds,
+// RFC3339, "2006-01-02 15:04:05", "2006-01-02", "now", or a signed duration
+// relative to now such as "-36h". An empty string returns the zero time.
+func ParseTimestamp(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if strings.EqualFold(s, "now") {
+		return time.Now(), nil
+	}
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.UnixMilli(ms), nil
+	}
+	if s[0] == '-' || s[0] == '+' {
+		if d, err := time.ParseDuration(s); err == nil {
+			return time.Now().Add(d), nil
+		}
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q: use Unix milliseconds, RFC3339, \"2006-01-02 15:04:05\" or a relative duration like -1h", s)
 }
 
 func (c *Client) ConsumeMessages(ctx context.Context, topic string, messageChan chan<- Message) error {
@@ -569,22 +688,123 @@ func (c *Client) ConsumeMessages(ctx context.Context, topic string, messageChan
 }
 
 func (c *Client) ConsumeMessagesWithOffset(ctx context.Context, topic string, messageChan chan<- Message, startOffset int64) error {
-	consumer, err := sarama.NewConsumer(c.brokers, c.config)
+	return c.ConsumeMessagesWithOptions(ctx, topic, messageChan, ConsumeOptions{StartOffset: startOffset})
+}
+
+// ConsumeOptions controls where consumption of a topic starts and, when an
+// end is set, where it stops
+type ConsumeOptions struct {
+	StartOffset int64     // sarama.OffsetOldest, sarama.OffsetNewest or an absolute offset
+	StartTime   time.Time // When set, start at the first record at or after it instead
+	LastN       int64     // When > 0, start LastN messages before the high watermark of each partition
+	Partitions  []int32   // Partitions to consume, empty means all
+
+	EndOffset int64     // When > 0, stop each partition before this offset
+	EndTime   time.Time // When set, stop each partition at its first record after it
+
+	IsolationLevel sarama.IsolationLevel // ReadUncommitted (default) or ReadCommitted
+}
+
+// ConsumeMessagesWithOptions consumes the selected partitions of a topic until
+// ctx is cancelled or, for bounded options, every partition reached its end
+func (c *Client) ConsumeMessagesWithOptions(ctx context.Context, topic string, messageChan chan<- Message, opts ConsumeOptions) (err error) {
+	ctx, span := startSpan(ctx, "Consume", attribute.String("topic", topic))
+	defer func() { endSpan(span, err) }()
+
+	config := *c.config
+	config.Consumer.IsolationLevel = opts.IsolationLevel
+
+	saramaClient, err := sarama.NewClient(c.brokers, &config)
 	if err != nil {
 		return fmt.Errorf("failed to create consumer: %w", err)
 	}
+	defer func() {
+		if closeErr := saramaClient.Close(); closeErr != nil {
+			logger.Get().WithError(closeErr).Warn("Failed to close consumer client during cleanup")
+		}
+	}()
 
-	partitions, err := consumer.Partitions(topic)
+	partitions, err := saramaClient.Partitions(topic)
 	if err != nil {
-		if closeErr := consumer.Close(); closeErr != nil {
-			logger.Get().WithError(closeErr).Warn("Failed to close consumer after partition error")
-		}
 		return fmt.Errorf("failed to get partitions: %w", err)
 	}
+	if len(opts.Partitions) > 0 {
+		available := make(map[int32]bool, len(partitions))
+		for _, p := range partitions {
+			available[p] = true
+		}
+		for _, p := range opts.Partitions {
+			if !available[p] {
+				return fmt.Errorf("partition %d does not exist in topic %s", p, topic)
+			}
+		}
+		partitions = opts.Partitions
+	}
 
+	startOffsets := make(map[int32]int64, len(partitions))
+	for _, partition := range partitions {
+		if !opts.StartTime.IsZero() {
+			if startOffsets[partition], err = offsetAtTime(saramaClient, topic, partition, opts.StartTime); err != nil {
+				return err
+			}
+			continue
+		}
+		if opts.LastN <= 0 {
+			startOffsets[partition] = opts.StartOffset
+			continue
+		}
+
+		newest, err := saramaClient.GetOffset(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return fmt.Errorf("failed to get newest offset for partition %d: %w", partition, err)
+		}
+		oldest, err := saramaClient.GetOffset(topic, partition, sarama.OffsetOldest)
+		if err != nil {
+			return fmt.Errorf("failed to get oldest offset for partition %d: %w", partition, err)
+		}
+		startOffsets[partition] = lastNOffset(oldest, newest, opts.LastN)
+	}
+
+	var ends map[int32]consumeEnd
+	if opts.Bounded() {
+		ends = make(map[int32]consumeEnd, len(partitions))
+		for _, partition := range partitions {
+			if startOffsets[partition], err = concreteOffset(saramaClient, topic, partition, startOffsets[partition]); err != nil {
+				return err
+			}
+			if ends[partition], err = resolveConsumeEnd(saramaClient, topic, partition, opts); err != nil {
+				return err
+			}
+		}
+	}
+
+	consumer, err := sarama.NewConsumerFromClient(saramaClient)
+	if err != nil {
+		return fmt.Errorf("failed to create consumer: %w", err)
+	}
+
+	if ends != nil {
+		return c.consumeBoundedPartitions(ctx, consumer, topic, startOffsets, ends, messageChan)
+	}
+	return c.consumePartitions(ctx, consumer, topic, startOffsets, messageChan)
+}
+
+// lastNOffset returns the offset n messages before the high watermark, clamped
+// to the log start offset so that short or truncated partitions still work
+func lastNOffset(oldest, newest, n int64) int64 {
+	start := newest - n
+	if start < oldest {
+		return oldest
+	}
+	return start
+}
+
+// consumePartitions starts a partition consumer per entry in startOffsets and
+// forwards messages until ctx is cancelled. It always closes consumer.
+func (c *Client) consumePartitions(ctx context.Context, consumer sarama.Consumer, topic string, startOffsets map[int32]int64, messageChan chan<- Message) error {
 	var partitionConsumers []sarama.PartitionConsumer
 
-	for _, partition := range partitions {
+	for partition, startOffset := range startOffsets {
 		pc, err := consumer.ConsumePartition(topic, partition, startOffset)
 		if err != nil {
 			// Close all previously opened partition consumers
@@ -601,6 +821,7 @@ func (c *Client) ConsumeMessagesWithOffset(ctx context.Context, topic string, me
 		partitionConsumers = append(partitionConsumers, pc)
 
 		go func(pc sarama.PartitionConsumer, partition int32) {
+			var gaps offsetGapTracker
 			for {
 				select {
 				case <-ctx.Done():
@@ -610,20 +831,8 @@ func (c *Client) ConsumeMessagesWithOffset(ctx context.Context, topic string, me
 						return
 					}
 
-					headers := make(map[string]string)
-					for _, h := range msg.Headers {
-						headers[string(h.Key)] = string(h.Value)
-					}
-
-					message := Message{
-						Topic:     msg.Topic,
-						Partition: msg.Partition,
-						Offset:    msg.Offset,
-						Key:       string(msg.Key),
-						Value:     string(msg.Value),
-						Timestamp: msg.Timestamp,
-						Headers:   headers,
-					}
+					message := newMessage(msg)
+					message.Skipped = gaps.observe(msg.Partition, msg.Offset)
 
 					select {
 					case messageChan <- message:
@@ -660,6 +869,42 @@ func (c *Client) ConsumeMessagesWithOffset(ctx context.Context, topic string, me
 	return nil
 }
 
+// newMessage converts a sarama consumer message into a Message
+func newMessage(msg *sarama.ConsumerMessage) Message {
+	headers := make(map[string]string)
+	for _, h := range msg.Headers {
+		headers[string(h.Key)] = string(h.Value)
+	}
+
+	return Message{
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Key:       string(msg.Key),
+		Value:     string(msg.Value),
+		Timestamp: msg.Timestamp,
+		Headers:   headers,
+	}
+}
+
+// offsetGapTracker remembers the last offset seen per partition to detect
+// offsets that were never delivered
+type offsetGapTracker map[int32]int64
+
+// observe records offset and returns how many offsets were skipped since the
+// previous message of the same partition
+func (t *offsetGapTracker) observe(partition int32, offset int64) int64 {
+	if *t == nil {
+		*t = make(offsetGapTracker)
+	}
+	prev, seen := (*t)[partition]
+	(*t)[partition] = offset
+	if !seen || offset <= prev+1 {
+		return 0
+	}
+	return offset - prev - 1
+}
+
 // parseTimeToMilliseconds converts human-readable time formats to milliseconds
 // Supports formats like: "1h" (1 hour), "1d" (1 day), "30m" (30 minutes), "1w" (1 week)
 // Returns the original value if it's already a number or doesn't match time format
@@ -713,7 +958,10 @@ func parseTimeToMilliseconds(value string) string {
 	return strconv.FormatInt(milliseconds, 10)
 }
 
-func (c *Client) UpdateTopicConfig(topicName string, configKey string, configValue string) error {
+func (c *Client) UpdateTopicConfig(topicName string, configKey string, configValue string) (err error) {
+	_, span := startSpan(context.Background(), "AlterTopicConfig", attribute.String("topic", topicName), attribute.String("config", configKey))
+	defer func() { endSpan(span, err) }()
+
 	log := logger.Get()
 
 	if topicName == "" || configKey == "" {
@@ -735,6 +983,12 @@ func (c *Client) UpdateTopicConfig(topicName string, configKey string, configVal
 		"message.timestamp.difference.max.ms": true,
 	}
 	
+	if configKey == minISRConfig {
+		if err := c.topicPolicy.checkMinISR(configValue, 0); err != nil {
+			return err
+		}
+	}
+
 	originalValue := configValue
 	if timeBasedConfigs[configKey] {
 		configValue = parseTimeToMilliseconds(configValue)
@@ -758,8 +1012,16 @@ func (c *Client) UpdateTopicConfig(topicName string, configKey string, configVal
 		configKey: &configValue,
 	}
 
+	// The previous value is kept for the audit log and the undo journal
+	previous, overrides, havePrevious := c.topicConfigState(topicName, configKey)
+	var before any
+	if havePrevious {
+		before = map[string]string{configKey: previous}
+	}
+
 	// Apply the configuration change
-	err := c.admin.AlterConfig(sarama.TopicResource, topicName, configEntries, false)
+	err = c.admin.AlterConfig(sarama.TopicResource, topicName, configEntries, false)
+	c.audit("topic.config.update", topicName, before, map[string]string{configKey: configValue}, err)
 	if err != nil {
 		log.WithFields(map[string]interface{}{
 			"topic": topicName,
@@ -769,6 +1031,13 @@ func (c *Client) UpdateTopicConfig(topicName string, configKey string, configVal
 		}).Error("Failed to update topic configuration")
 		return fmt.Errorf("failed to update topic config: %w", err)
 	}
+	change := Change{Kind: ChangeTopicConfig, Topic: topicName, Key: configKey, Previous: previous, Value: configValue, Overrides: overrides}
+	if havePrevious && previous != configValue {
+		c.recordChange(change)
+	} else {
+		// Cannot be undone without the previous value, but is still replayed
+		c.recordSession(change)
+	}
 
 	log.WithFields(map[string]interface{}{
 		"topic": topicName,
@@ -779,7 +1048,10 @@ func (c *Client) UpdateTopicConfig(topicName string, configKey string, configVal
 	return nil
 }
 
-func (c *Client) ModifyTopicPartitions(topicName string, numPartitions int32) error {
+func (c *Client) ModifyTopicPartitions(topicName string, numPartitions int32) (err error) {
+	_, span := startSpan(context.Background(), "CreatePartitions", attribute.String("topic", topicName))
+	defer func() { endSpan(span, err) }()
+
 	log := logger.Get()
 
 	if topicName == "" {
@@ -793,6 +1065,9 @@ func (c *Client) ModifyTopicPartitions(topicName string, numPartitions int32) er
 		log.WithError(err).Error("Invalid partition count")
 		return err
 	}
+	if err := c.topicPolicy.checkMaxPartitions(numPartitions); err != nil {
+		return err
+	}
 
 	log.WithFields(map[string]interface{}{
 		"topic":      topicName,
@@ -818,6 +1093,7 @@ func (c *Client) ModifyTopicPartitions(topicName string, numPartitions int32) er
 
 	// Create partition update
 	err = c.admin.CreatePartitions(topicName, numPartitions, nil, false)
+	c.audit("topic.partitions.update", topicName, map[string]any{"partitions": currentPartitions}, map[string]any{"partitions": numPartitions}, err)
 	if err != nil {
 		log.WithFields(map[string]interface{}{
 			"topic":      topicName,
@@ -826,6 +1102,13 @@ func (c *Client) ModifyTopicPartitions(topicName string, numPartitions int32) er
 		}).Error("Failed to modify topic partitions")
 		return fmt.Errorf("failed to modify partitions: %w", err)
 	}
+	c.topicCache.invalidate()
+	c.recordChange(Change{
+		Kind:     ChangeTopicPartitions,
+		Topic:    topicName,
+		Previous: strconv.Itoa(int(currentPartitions)),
+		Value:    strconv.Itoa(int(numPartitions)),
+	})
 
 	log.WithFields(map[string]interface{}{
 		"topic":         topicName,
@@ -836,7 +1119,10 @@ func (c *Client) ModifyTopicPartitions(topicName string, numPartitions int32) er
 	return nil
 }
 
-func (c *Client) GetConsumerGroups() ([]ConsumerGroupInfo, error) {
+func (c *Client) GetConsumerGroups() (_ []ConsumerGroupInfo, err error) {
+	_, span := startSpan(context.Background(), "GetConsumerGroups")
+	defer func() { endSpan(span, err) }()
+
 	log := logger.Get()
 
 	// List all consumer groups
@@ -869,8 +1155,13 @@ func (c *Client) GetConsumerGroups() ([]ConsumerGroupInfo, error) {
 			NumMembers: len(desc.Members),
 		}
 
-		// Get coordinator info - coordinator is typically embedded in the description
-		info.Coordinator = "unknown"
+		// The coordinator is found separately, it is not part of the description
+		info.Coordinator = coordinatorLabel(-1, "")
+		if coordinator, err := c.admin.Coordinator(groupID); err != nil {
+			log.WithField("groupID", groupID).WithError(err).Debug("Failed to find group coordinator")
+		} else {
+			info.Coordinator = coordinatorLabel(coordinator.ID(), coordinator.Addr())
+		}
 
 		// Collect unique topics from member metadata
 		topicSet := make(map[string]struct{})
@@ -989,7 +1280,7 @@ func (c *Client) Close() error {
 	var errs []error
 
 	if c.producer != nil {
-		if err := c.producer.Close(); err != nil {
+		if err := c.producer.close(); err != nil {
 			errs = append(errs, fmt.Errorf("failed to close producer: %w", err))
 		}
 	}
@@ -1000,6 +1291,16 @@ func (c *Client) Close() error {
 		}
 	}
 
+	if err := c.auditLog.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close audit log: %w", err))
+	}
+
+	if c.tunnel != nil {
+		if err := c.tunnel.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close SSH tunnel: %w", err))
+		}
+	}
+
 	if len(errs) > 0 {
 		return fmt.Errorf("errors closing client: %v", errs)
 	}
@@ -1017,7 +1318,32 @@ type TopicConfig struct {
 	Partitions        int
 	ReplicationFactor int
 	Configs           map[string]string
-	PartitionDetails  []PartitionInfo
+	// Sources says where each value comes from: "topic", "broker" or
+	// "default", empty when the broker did not say
+	Sources          map[string]string
+	PartitionDetails []PartitionInfo
+}
+
+// IsDefault reports whether a config is left at Kafka's default, i.e. set
+// neither on the topic nor on the brokers
+func (t *TopicConfig) IsDefault(name string) bool {
+	return t.Sources[name] == "default"
+}
+
+// configSourceLabel names where a config value comes from for TopicConfig
+func configSourceLabel(entry sarama.ConfigEntry) string {
+	switch entry.Source {
+	case sarama.SourceTopic:
+		return "topic"
+	case sarama.SourceDynamicBroker, sarama.SourceDynamicDefaultBroker, sarama.SourceStaticBroker:
+		return "broker"
+	case sarama.SourceDefault:
+		return "default"
+	}
+	if entry.Default {
+		return "default"
+	}
+	return ""
 }
 
 type PartitionInfo struct {
@@ -1036,7 +1362,8 @@ type BrokerInfo struct {
 	ApiVersions   string
 	ListenerCount int
 	LogDirCount   int
-	Status        string // "Online", "Offline", "Unknown"
+	DiskUsed      int64  // Bytes of all replicas in its log dirs
+	Status        string // BrokerOnline, BrokerOffline or BrokerUnreachable
 }
 
 type Message struct {
@@ -1047,6 +1374,10 @@ type Message struct {
 	Value     string
 	Timestamp time.Time
 	Headers   map[string]string
+	// Skipped counts offsets between this message and the previous one from the
+	// same partition that were never delivered: transaction markers, aborted
+	// records under read_committed, or records removed by compaction.
+	Skipped int64
 }
 
 type ConsumerGroupInfo struct {
@@ -1070,17 +1401,20 @@ type ClusterStats struct {
 
 // ACL represents a Kafka ACL entry
 type ACL struct {
-	Principal      string
-	Host           string
-	Operation      string
-	PermissionType string
-	ResourceType   string
-	ResourceName   string
-	PatternType    string
+	Principal      string `json:"principal" yaml:"principal"`
+	Host           string `json:"host" yaml:"host"`
+	Operation      string `json:"operation" yaml:"operation"`
+	PermissionType string `json:"permission" yaml:"permission"`
+	ResourceType   string `json:"resourceType" yaml:"resourceType"`
+	ResourceName   string `json:"resourceName" yaml:"resourceName"`
+	PatternType    string `json:"patternType" yaml:"patternType"`
 }
 
 // ListACLs retrieves all ACLs from the cluster
-func (c *Client) ListACLs() ([]ACL, error) {
+func (c *Client) ListACLs() (_ []ACL, err error) {
+	_, span := startSpan(context.Background(), "ListACLs")
+	defer func() { endSpan(span, err) }()
+
 	log := logger.Get()
 	log.Info("Listing ACLs")
 
@@ -1132,7 +1466,10 @@ func (c *Client) ListACLs() ([]ACL, error) {
 }
 
 // CreateACL creates a new ACL in the cluster
-func (c *Client) CreateACL(acl ACL) error {
+func (c *Client) CreateACL(acl ACL) (err error) {
+	_, span := startSpan(context.Background(), "CreateACL", attribute.String("principal", acl.Principal))
+	defer func() { endSpan(span, err) }()
+
 	log := logger.Get()
 	log.WithFields(map[string]interface{}{
 		"principal":      acl.Principal,
@@ -1170,7 +1507,8 @@ func (c *Client) CreateACL(acl ACL) error {
 		"acl":      fmt.Sprintf("%+v", aclCreation),
 	}).Debug("About to call admin.CreateACL")
 
-	err := c.admin.CreateACL(resource, aclCreation)
+	err = c.admin.CreateACL(resource, aclCreation)
+	c.audit("acl.create", acl.Principal, nil, acl, err)
 	if err != nil {
 		log.WithFields(map[string]interface{}{
 			"error":        err.Error(),
@@ -1181,6 +1519,7 @@ func (c *Client) CreateACL(acl ACL) error {
 		}).Error("Failed to create ACL - detailed error")
 		return fmt.Errorf("failed to create ACL: %w", err)
 	}
+	c.recordChange(Change{Kind: ChangeACLCreate, ACL: acl})
 
 	log.WithFields(map[string]interface{}{
 		"principal":    acl.Principal,
@@ -1192,7 +1531,10 @@ func (c *Client) CreateACL(acl ACL) error {
 }
 
 // DeleteACL deletes an ACL from the cluster
-func (c *Client) DeleteACL(acl ACL) error {
+func (c *Client) DeleteACL(acl ACL) (err error) {
+	_, span := startSpan(context.Background(), "DeleteACL", attribute.String("principal", acl.Principal))
+	defer func() { endSpan(span, err) }()
+
 	log := logger.Get()
 	log.WithFields(map[string]interface{}{
 		"principal":      acl.Principal,
@@ -1226,6 +1568,7 @@ func (c *Client) DeleteACL(acl ACL) error {
 	}).Debug("Constructed ACL filter")
 
 	matches, err := c.admin.DeleteACL(filter, false)
+	defer func() { c.audit("acl.delete", acl.Principal, acl, nil, err) }()
 	if err != nil {
 		log.WithError(err).Error("Failed to delete ACL")
 		return fmt.Errorf("failed to delete ACL: %w", err)
@@ -1244,10 +1587,12 @@ func (c *Client) DeleteACL(acl ACL) error {
 		}
 		
 		if len(matches) == 0 {
-			return fmt.Errorf("no matching ACLs found to delete")
+			err = fmt.Errorf("no matching ACLs found to delete")
+			return err
 		}
 	}
 
+	c.recordChange(Change{Kind: ChangeACLDelete, ACL: acl})
 	log.WithField("deleted", len(matches)).Info("Successfully deleted ACL(s)")
 	return nil
 }
@@ -2,6 +2,9 @@ package kafka
 
 import (
 	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
 )
 
 func TestParseTimeToMilliseconds(t *testing.T) {
@@ -13,7 +16,7 @@ func TestParseTimeToMilliseconds(t *testing.T) {
 		// Already milliseconds
 		{"1000", "1000", "pure number stays as-is"},
 		{"86400000", "86400000", "large number stays as-is"},
-		
+
 		// Go duration formats
 		{"1h", "3600000", "1 hour"},
 		{"24h", "86400000", "24 hours"},
@@ -21,25 +24,25 @@ func TestParseTimeToMilliseconds(t *testing.T) {
 		{"1h30m", "5400000", "1 hour 30 minutes"},
 		{"10s", "10000", "10 seconds"},
 		{"500ms", "500", "500 milliseconds"},
-		
+
 		// Day and week formats
 		{"1d", "86400000", "1 day"},
 		{"7d", "604800000", "7 days"},
 		{"1w", "604800000", "1 week"},
 		{"2w", "1209600000", "2 weeks"},
 		{"1.5d", "129600000", "1.5 days"},
-		
+
 		// With spaces
 		{"1 d", "86400000", "1 day with space"},
 		{"2 weeks", "1209600000", "2 weeks spelled out"},
 		{"3 days", "259200000", "3 days spelled out"},
-		
+
 		// Invalid formats return as-is
 		{"invalid", "invalid", "invalid format"},
 		{"", "", "empty string"},
 		{"abc123", "abc123", "mixed invalid"},
 	}
-	
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			result := parseTimeToMilliseconds(tt.input)
@@ -48,4 +51,113 @@ func TestParseTimeToMilliseconds(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestLastNOffset(t *testing.T) {
+	tests := []struct {
+		oldest   int64
+		newest   int64
+		n        int64
+		expected int64
+		name     string
+	}{
+		{0, 1000, 100, 900, "enough messages"},
+		{0, 50, 100, 0, "fewer messages than requested"},
+		{500, 550, 100, 500, "clamped to log start after retention"},
+		{0, 0, 10, 0, "empty partition"},
+		{200, 300, 100, 200, "exactly n messages"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := lastNOffset(tt.oldest, tt.newest, tt.n)
+			if result != tt.expected {
+				t.Errorf("lastNOffset(%d, %d, %d) = %d, want %d", tt.oldest, tt.newest, tt.n, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOffsetGapTracker(t *testing.T) {
+	var gaps offsetGapTracker
+
+	steps := []struct {
+		partition int32
+		offset    int64
+		expected  int64
+	}{
+		{0, 10, 0}, // first message of a partition never reports a gap
+		{0, 11, 0},
+		{0, 13, 1}, // one transaction marker
+		{1, 5, 0},
+		{0, 20, 6},
+		{1, 6, 0},
+	}
+
+	for _, s := range steps {
+		if result := gaps.observe(s.partition, s.offset); result != s.expected {
+			t.Errorf("observe(%d, %d) = %d, want %d", s.partition, s.offset, result, s.expected)
+		}
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "empty", input: "", want: time.Time{}},
+		{name: "unix millis", input: "1700000000123", want: time.UnixMilli(1700000000123)},
+		{name: "rfc3339", input: "2024-01-31T12:00:00Z", want: time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)},
+		{name: "date time", input: "2024-01-31 12:00:00", want: time.Date(2024, 1, 31, 12, 0, 0, 0, time.Local)},
+		{name: "date", input: "2024-01-31", want: time.Date(2024, 1, 31, 0, 0, 0, 0, time.Local)},
+		{name: "invalid", input: "yesterday", wantErr: true},
+		{name: "unsigned duration", input: "1h", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTimestamp(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTimestamp(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("ParseTimestamp(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	got, err := ParseTimestamp("-1h")
+	if err != nil {
+		t.Fatalf("ParseTimestamp(-1h) error = %v", err)
+	}
+	if d := time.Since(got); d < 59*time.Minute || d > 61*time.Minute {
+		t.Errorf("ParseTimestamp(-1h) is %v ago, want about 1h", d)
+	}
+}
+
+func TestConfigSourceLabel(t *testing.T) {
+	tests := []struct {
+		entry sarama.ConfigEntry
+		want  string
+	}{
+		{sarama.ConfigEntry{Source: sarama.SourceTopic}, "topic"},
+		{sarama.ConfigEntry{Source: sarama.SourceStaticBroker}, "broker"},
+		{sarama.ConfigEntry{Source: sarama.SourceDefault, Default: true}, "default"},
+		// DescribeConfigs v0 only says whether a value is the default
+		{sarama.ConfigEntry{Source: sarama.SourceUnknown, Default: true}, "default"},
+		{sarama.ConfigEntry{Source: sarama.SourceUnknown}, ""},
+	}
+	for _, tt := range tests {
+		if got := configSourceLabel(tt.entry); got != tt.want {
+			t.Errorf("configSourceLabel(%+v) = %q, want %q", tt.entry, got, tt.want)
+		}
+	}
+
+	config := &TopicConfig{Sources: map[string]string{"retention.ms": "topic", "flush.ms": "default"}}
+	if config.IsDefault("retention.ms") || !config.IsDefault("flush.ms") {
+		t.Error("IsDefault should follow the config's source")
+	}
+}
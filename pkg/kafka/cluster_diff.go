@@ -0,0 +1,220 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// TopicSnapshot is what is compared of a topic between two clusters
+type TopicSnapshot struct {
+	Name              string
+	Partitions        int32
+	ReplicationFactor int16
+	Configs           map[string]string // Set on the topic itself, not inherited from the broker
+}
+
+// ConfigDifference is a topic config set differently on the two clusters.
+// An empty value means the topic does not override it there.
+type ConfigDifference struct {
+	Name   string
+	Source string
+	Target string
+}
+
+// TopicDifference is a topic that differs between a source and a target
+// cluster
+type TopicDifference struct {
+	Topic   string
+	Source  *TopicSnapshot // Nil when the topic only exists in the target
+	Target  *TopicSnapshot // Nil when the topic only exists in the source
+	Configs []ConfigDifference
+}
+
+// PartitionsDiffer reports whether the topic exists on both sides with a
+// different number of partitions
+func (d TopicDifference) PartitionsDiffer() bool {
+	return d.Source != nil && d.Target != nil && d.Source.Partitions != d.Target.Partitions
+}
+
+// TopicComparison is the outcome of comparing the topics of two clusters
+type TopicComparison struct {
+	Differences []TopicDifference
+	Identical   int
+}
+
+// CompareTopics lists the topics missing from either cluster and those
+// whose partition count or config overrides differ, sorted by name
+func CompareTopics(source, target map[string]TopicSnapshot) TopicComparison {
+	names := make(map[string]bool, len(source)+len(target))
+	for name := range source {
+		names[name] = true
+	}
+	for name := range target {
+		names[name] = true
+	}
+
+	var comparison TopicComparison
+	for name := range names {
+		d := TopicDifference{Topic: name}
+		if s, ok := source[name]; ok {
+			d.Source = &s
+		}
+		if t, ok := target[name]; ok {
+			d.Target = &t
+		}
+		if d.Source != nil && d.Target != nil {
+			d.Configs = diffConfigs(d.Source.Configs, d.Target.Configs)
+			if !d.PartitionsDiffer() && len(d.Configs) == 0 {
+				comparison.Identical++
+				continue
+			}
+		}
+		comparison.Differences = append(comparison.Differences, d)
+	}
+	sort.Slice(comparison.Differences, func(i, j int) bool {
+		return comparison.Differences[i].Topic < comparison.Differences[j].Topic
+	})
+	return comparison
+}
+
+func diffConfigs(source, target map[string]string) []ConfigDifference {
+	var diffs []ConfigDifference
+	for name, value := range source {
+		if target[name] != value {
+			diffs = append(diffs, ConfigDifference{Name: name, Source: value, Target: target[name]})
+		}
+	}
+	for name, value := range target {
+		if _, ok := source[name]; !ok {
+			diffs = append(diffs, ConfigDifference{Name: name, Target: value})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Name < diffs[j].Name })
+	return diffs
+}
+
+// TopicSnapshots returns the partition count, replication factor and config
+// overrides of every non-internal topic
+func (c *Client) TopicSnapshots() (_ map[string]TopicSnapshot, err error) {
+	_, span := startSpan(context.Background(), "TopicSnapshots")
+	defer func() { endSpan(span, err) }()
+
+	metadata, err := c.admin.ListTopics()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list topics: %w", err)
+	}
+	var names []string
+	for name := range metadata {
+		if !strings.HasPrefix(name, "__") {
+			names = append(names, name)
+		}
+	}
+	entries, err := c.describeTopicConfigs(names, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make(map[string]TopicSnapshot, len(names))
+	for _, name := range names {
+		s := TopicSnapshot{
+			Name:              name,
+			Partitions:        metadata[name].NumPartitions,
+			ReplicationFactor: metadata[name].ReplicationFactor,
+			Configs:           make(map[string]string),
+		}
+		for _, entry := range entries[name] {
+			// Version 0 responses only say whether a value is the default
+			overridden := entry.Source == sarama.SourceTopic || (entry.Source == sarama.SourceUnknown && !entry.Default)
+			if overridden && !entry.Sensitive {
+				s.Configs[entry.Name] = entry.Value
+			}
+		}
+		snapshots[name] = s
+	}
+	return snapshots, nil
+}
+
+// SyncTopic makes a topic on this cluster, the target of d, match the
+// source: a missing topic is created with the source's partitions,
+// replication factor and configs, partitions are added when the target has
+// fewer, and the config overrides are replaced with the source's. Topics
+// only on the target are left alone, and partitions are never removed.
+func (c *Client) SyncTopic(d TopicDifference) (err error) {
+	_, span := startSpan(context.Background(), "SyncTopic", attribute.String("topic", d.Topic))
+	defer func() { endSpan(span, err) }()
+
+	switch {
+	case d.Source == nil:
+		return nil
+	case d.Target == nil:
+		return c.createTopicLike(*d.Source)
+	}
+
+	if d.Target.Partitions > d.Source.Partitions {
+		return fmt.Errorf("%s has %d partitions on the target and %d on the source, partitions cannot be removed",
+			d.Topic, d.Target.Partitions, d.Source.Partitions)
+	}
+	if d.PartitionsDiffer() {
+		if err := c.ModifyTopicPartitions(d.Topic, d.Source.Partitions); err != nil {
+			return err
+		}
+	}
+	if len(d.Configs) > 0 {
+		return c.replaceTopicConfigs(d.Topic, d.Target.Configs, d.Source.Configs)
+	}
+	return nil
+}
+
+// createTopicLike creates a topic with the partitions, replication factor
+// and configs of s, as completed and checked by the topic policy
+func (c *Client) createTopicLike(s TopicSnapshot) error {
+	var err error
+	s.Partitions, s.ReplicationFactor, s.Configs, err = c.topicPolicy.newTopic(s.Partitions, s.ReplicationFactor, s.Configs)
+	if err != nil {
+		return err
+	}
+	entries := make(map[string]*string, len(s.Configs))
+	for name, value := range s.Configs {
+		entries[name] = &value
+	}
+	err = c.admin.CreateTopic(s.Name, &sarama.TopicDetail{
+		NumPartitions:     s.Partitions,
+		ReplicationFactor: s.ReplicationFactor,
+		ConfigEntries:     entries,
+	}, false)
+	c.audit("topic.create", s.Name, nil, map[string]any{
+		"partitions": s.Partitions, "replicationFactor": s.ReplicationFactor, "configs": s.Configs,
+	}, err)
+	if err != nil {
+		return fmt.Errorf("failed to create topic %s: %w", s.Name, err)
+	}
+	c.topicCache.invalidate()
+	c.recordChange(Change{Kind: ChangeTopicCreate, Topic: s.Name, Partitions: s.Partitions, ReplicationFactor: s.ReplicationFactor, Configs: s.Configs})
+	return nil
+}
+
+// replaceTopicConfigs sets exactly configs as the topic's overrides;
+// AlterConfigs resets any override left out to the broker default
+func (c *Client) replaceTopicConfigs(topic string, current, configs map[string]string) error {
+	if value, ok := configs[minISRConfig]; ok {
+		if err := c.topicPolicy.checkMinISR(value, 0); err != nil {
+			return err
+		}
+	}
+	entries := make(map[string]*string, len(configs))
+	for name, value := range configs {
+		entries[name] = &value
+	}
+	err := c.admin.AlterConfig(sarama.TopicResource, topic, entries, false)
+	c.audit("topic.config.replace", topic, maps.Clone(current), maps.Clone(configs), err)
+	if err != nil {
+		return fmt.Errorf("failed to update configs of %s: %w", topic, err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package kafka
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompareTopics(t *testing.T) {
+	source := map[string]TopicSnapshot{
+		"orders":   {Name: "orders", Partitions: 6, ReplicationFactor: 3, Configs: map[string]string{"retention.ms": "604800000"}},
+		"payments": {Name: "payments", Partitions: 3, Configs: map[string]string{"cleanup.policy": "compact"}},
+		"same":     {Name: "same", Partitions: 1, Configs: map[string]string{}},
+	}
+	target := map[string]TopicSnapshot{
+		"payments": {Name: "payments", Partitions: 1, Configs: map[string]string{"cleanup.policy": "compact", "segment.ms": "1000"}},
+		"same":     {Name: "same", Partitions: 1, Configs: map[string]string{}},
+		"legacy":   {Name: "legacy", Partitions: 1, Configs: map[string]string{}},
+	}
+
+	comparison := CompareTopics(source, target)
+	if comparison.Identical != 1 {
+		t.Errorf("Identical = %d, want 1", comparison.Identical)
+	}
+	var topics []string
+	for _, d := range comparison.Differences {
+		topics = append(topics, d.Topic)
+	}
+	if want := []string{"legacy", "orders", "payments"}; !reflect.DeepEqual(topics, want) {
+		t.Fatalf("differences = %v, want %v", topics, want)
+	}
+
+	legacy, orders, payments := comparison.Differences[0], comparison.Differences[1], comparison.Differences[2]
+	if legacy.Source != nil || legacy.Target == nil {
+		t.Errorf("legacy should only be on the target")
+	}
+	if orders.Target != nil || orders.Source == nil {
+		t.Errorf("orders should only be on the source")
+	}
+	if !payments.PartitionsDiffer() {
+		t.Errorf("payments partitions should differ")
+	}
+	if want := []ConfigDifference{{Name: "segment.ms", Target: "1000"}}; !reflect.DeepEqual(payments.Configs, want) {
+		t.Errorf("payments configs = %+v, want %+v", payments.Configs, want)
+	}
+}
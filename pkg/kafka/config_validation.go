@@ -0,0 +1,210 @@
+package kafka
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// configKind is how a topic config value is written and checked
+type configKind int
+
+const (
+	configDuration configKind = iota // Milliseconds, human durations such as 7d accepted
+	configSize                       // Bytes, human sizes such as 1GiB accepted
+	configCount
+	configRatio
+	configBool
+	configEnum
+	configList // Comma separated values from a fixed set
+)
+
+// configSpec is the metadata a topic config value is validated against
+type configSpec struct {
+	kind     configKind
+	min, max int64 // Inclusive range for durations, sizes and counts
+	values   []string
+	// special describes values below min that the broker accepts, e.g. -1
+	// for unlimited
+	special map[int64]string
+}
+
+const maxInt32 = math.MaxInt32
+
+// unlimitedValue marks -1 as meaning no limit
+var unlimitedValue = map[int64]string{-1: "unlimited"}
+
+// topicConfigSpecs are the topic configs whose values are checked before
+// they are sent, with the types and ranges the broker enforces
+var topicConfigSpecs = map[string]configSpec{
+	"cleanup.policy":                      {kind: configList, values: []string{"delete", "compact"}},
+	"compression.type":                    {kind: configEnum, values: []string{"producer", "uncompressed", "gzip", "snappy", "lz4", "zstd"}},
+	"delete.retention.ms":                 {kind: configDuration, min: 0, max: math.MaxInt64},
+	"file.delete.delay.ms":                {kind: configDuration, min: 0, max: math.MaxInt64},
+	"flush.messages":                      {kind: configCount, min: 1, max: math.MaxInt64},
+	"flush.ms":                            {kind: configDuration, min: 0, max: math.MaxInt64},
+	"index.interval.bytes":                {kind: configSize, min: 0, max: maxInt32},
+	"local.retention.bytes":               {kind: configSize, min: 0, max: math.MaxInt64, special: map[int64]string{-1: "unlimited", -2: "same as retention.bytes"}},
+	"local.retention.ms":                  {kind: configDuration, min: 0, max: math.MaxInt64, special: map[int64]string{-1: "unlimited", -2: "same as retention.ms"}},
+	"max.compaction.lag.ms":               {kind: configDuration, min: 1, max: math.MaxInt64},
+	"max.message.bytes":                   {kind: configSize, min: 0, max: maxInt32},
+	"message.downconversion.enable":       {kind: configBool},
+	"message.timestamp.after.max.ms":      {kind: configDuration, min: 0, max: math.MaxInt64},
+	"message.timestamp.before.max.ms":     {kind: configDuration, min: 0, max: math.MaxInt64},
+	"message.timestamp.difference.max.ms": {kind: configDuration, min: 0, max: math.MaxInt64},
+	"message.timestamp.type":              {kind: configEnum, values: []string{"CreateTime", "LogAppendTime"}},
+	"min.cleanable.dirty.ratio":           {kind: configRatio},
+	"min.compaction.lag.ms":               {kind: configDuration, min: 0, max: math.MaxInt64},
+	"min.insync.replicas":                 {kind: configCount, min: 1, max: maxInt32},
+	"preallocate":                         {kind: configBool},
+	"remote.storage.enable":               {kind: configBool},
+	"retention.bytes":                     {kind: configSize, min: 0, max: math.MaxInt64, special: unlimitedValue},
+	"retention.ms":                        {kind: configDuration, min: 0, max: math.MaxInt64, special: unlimitedValue},
+	"segment.bytes":                       {kind: configSize, min: 14, max: maxInt32},
+	"segment.index.bytes":                 {kind: configSize, min: 4, max: maxInt32},
+	"segment.jitter.ms":                   {kind: configDuration, min: 0, max: math.MaxInt64},
+	"segment.ms":                          {kind: configDuration, min: 1, max: math.MaxInt64},
+	"unclean.leader.election.enable":      {kind: configBool},
+}
+
+// ConfigTakesDuration reports whether a topic config is a number of
+// milliseconds that NormalizeTopicConfig accepts as a duration like 7d
+func ConfigTakesDuration(key string) bool {
+	spec, ok := topicConfigSpecs[key]
+	return ok && spec.kind == configDuration
+}
+
+// ConfigTakesSize reports whether a topic config is a number of bytes that
+// NormalizeTopicConfig accepts as a size like 1GiB
+func ConfigTakesSize(key string) bool {
+	spec, ok := topicConfigSpecs[key]
+	return ok && spec.kind == configSize
+}
+
+// NormalizeTopicConfig checks value against what the broker accepts for the
+// topic config key and returns it in the form the broker expects: durations
+// such as 7d become milliseconds and sizes such as 1GiB become bytes. Unknown
+// configs are returned as they are.
+func NormalizeTopicConfig(key, value string) (string, error) {
+	spec, ok := topicConfigSpecs[key]
+	value = strings.TrimSpace(value)
+	if !ok {
+		return value, nil
+	}
+	if value == "" {
+		return "", fmt.Errorf("%s needs a value", key)
+	}
+
+	switch spec.kind {
+	case configBool:
+		switch strings.ToLower(value) {
+		case "true", "false":
+			return strings.ToLower(value), nil
+		}
+		return "", fmt.Errorf("%s must be true or false, got %q", key, value)
+
+	case configEnum:
+		for _, v := range spec.values {
+			if strings.EqualFold(value, v) {
+				return v, nil
+			}
+		}
+		return "", fmt.Errorf("%s must be one of %s, got %q", key, strings.Join(spec.values, ", "), value)
+
+	case configList:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			item = strings.ToLower(strings.TrimSpace(item))
+			if !slices.Contains(spec.values, item) {
+				return "", fmt.Errorf("%s takes a comma separated list of %s, got %q", key, strings.Join(spec.values, ", "), item)
+			}
+			if !slices.Contains(items, item) {
+				items = append(items, item)
+			}
+		}
+		return strings.Join(items, ","), nil
+
+	case configRatio:
+		ratio, err := strconv.ParseFloat(value, 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			return "", fmt.Errorf("%s must be a number between 0 and 1, got %q", key, value)
+		}
+		return strconv.FormatFloat(ratio, 'f', -1, 64), nil
+	}
+
+	var n int64
+	var err error
+	switch spec.kind {
+	case configDuration:
+		n, err = parseConfigDuration(value)
+	case configSize:
+		n, err = parseConfigSize(value)
+	default:
+		n, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			err = fmt.Errorf("invalid number %q", value)
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", key, err)
+	}
+	if _, ok := spec.special[n]; !ok && (n < spec.min || n > spec.max) {
+		return "", fmt.Errorf("%s must be %s, got %d", key, spec.describeRange(), n)
+	}
+	return strconv.FormatInt(n, 10), nil
+}
+
+// describeRange words the values a numeric config accepts, e.g. "-1
+// (unlimited) or at least 0"
+func (s configSpec) describeRange() string {
+	var parts []string
+	for _, n := range []int64{-2, -1} {
+		if meaning, ok := s.special[n]; ok {
+			parts = append(parts, fmt.Sprintf("%d (%s)", n, meaning))
+		}
+	}
+	limit := fmt.Sprintf("between %d and %d", s.min, s.max)
+	if s.max == math.MaxInt64 {
+		limit = fmt.Sprintf("at least %d", s.min)
+	}
+	parts = append(parts, limit)
+	return strings.Join(parts, " or ")
+}
+
+// parseConfigDuration parses milliseconds or a duration such as 1h30m, 7d or
+// 2w, as UpdateTopicConfig converts them
+func parseConfigDuration(s string) (int64, error) {
+	n, err := strconv.ParseInt(parseTimeToMilliseconds(s), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: use milliseconds or a duration such as 30s, 1h, 7d or 2w", s)
+	}
+	return n, nil
+}
+
+// parseConfigSize parses bytes or a size such as 512MiB or 1GB. Units are
+// binary, as for ParseByteRate.
+func parseConfigSize(s string) (int64, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+	upper := strings.ToUpper(s)
+	for _, unit := range []struct {
+		suffix string
+		size   float64
+	}{
+		{"TIB", 1 << 40}, {"GIB", 1 << 30}, {"MIB", 1 << 20}, {"KIB", 1 << 10},
+		{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+		{"T", 1 << 40}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1},
+	} {
+		if number, ok := strings.CutSuffix(upper, unit.suffix); ok {
+			v, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+			if err != nil || v < 0 || v*unit.size > math.MaxInt64 {
+				break
+			}
+			return int64(v * unit.size), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid size %q: use bytes or a size such as 512KiB, 100MiB or 1GiB", s)
+}
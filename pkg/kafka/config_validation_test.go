@@ -0,0 +1,50 @@
+package kafka
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTopicConfig(t *testing.T) {
+	tests := []struct {
+		key, value string
+		want       string
+		wantErr    string
+	}{
+		{"retention.ms", "604800000", "604800000", ""},
+		{"retention.ms", "7d", "604800000", ""},
+		{"retention.ms", "1h30m", "5400000", ""},
+		{"retention.ms", "2w", "1209600000", ""},
+		{"retention.ms", "-1", "-1", ""},
+		{"retention.ms", "-5", "", "retention.ms must be -1 (unlimited) or at least 0, got -5"},
+		{"retention.ms", "7x", "", `invalid duration "7x"`},
+		{"retention.bytes", "1GiB", "1073741824", ""},
+		{"retention.bytes", "512 MB", "536870912", ""},
+		{"segment.bytes", "4GiB", "", "segment.bytes must be between 14 and 2147483647, got 4294967296"},
+		{"segment.ms", "0", "", "segment.ms must be at least 1, got 0"},
+		{"min.insync.replicas", "2", "2", ""},
+		{"min.insync.replicas", "two", "", `invalid number "two"`},
+		{"unclean.leader.election.enable", "TRUE", "true", ""},
+		{"unclean.leader.election.enable", "yes", "", "must be true or false"},
+		{"compression.type", "ZSTD", "zstd", ""},
+		{"compression.type", "none", "", "must be one of producer, uncompressed"},
+		{"cleanup.policy", "compact, delete", "compact,delete", ""},
+		{"cleanup.policy", "archive", "", `got "archive"`},
+		{"min.cleanable.dirty.ratio", "0.5", "0.5", ""},
+		{"min.cleanable.dirty.ratio", "1.5", "", "between 0 and 1"},
+		{"local.retention.ms", "-2", "-2", ""},
+		{"follower.replication.throttled.replicas", " 0:1,1:2 ", "0:1,1:2", ""},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeTopicConfig(tt.key, tt.value)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NormalizeTopicConfig(%q, %q) error = %v, want %q", tt.key, tt.value, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeTopicConfig(%q, %q) = %q, %v; want %q", tt.key, tt.value, got, err, tt.want)
+		}
+	}
+}
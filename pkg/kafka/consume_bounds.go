@@ -0,0 +1,197 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+)
+
+// partitionReadIdle is how long reading a partition up to an end already in
+// the log waits for the next record before treating the partition as done,
+// the last offsets before the end may be transaction markers or compacted
+// away so the record just before it may never arrive
+const partitionReadIdle = 3 * time.Second
+
+// Bounded reports whether consumption stops at an end boundary rather than
+// running until it is cancelled
+func (o ConsumeOptions) Bounded() bool {
+	return o.EndOffset > 0 || !o.EndTime.IsZero()
+}
+
+// consumeEnd is where a bounded consumer stops reading one partition
+type consumeEnd struct {
+	offset int64     // Exclusive end offset, -1 when it is not known yet
+	time   time.Time // Stop at the first record after it, zero for none
+	inLog  bool      // The end offset has already been written
+}
+
+// reached reports whether msg lies past the end and must not be delivered
+func (e consumeEnd) reached(msg *sarama.ConsumerMessage) bool {
+	return (e.offset >= 0 && msg.Offset >= e.offset) || (!e.time.IsZero() && msg.Timestamp.After(e.time))
+}
+
+// last reports whether msg is the final record before the end offset
+func (e consumeEnd) last(msg *sarama.ConsumerMessage) bool {
+	return e.offset >= 0 && msg.Offset+1 >= e.offset
+}
+
+// offsetAtTime returns the first offset with a timestamp at or after t, or
+// the end of the partition when every record is older
+func offsetAtTime(client sarama.Client, topic string, partition int32, t time.Time) (int64, error) {
+	offset, err := client.GetOffset(topic, partition, t.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("failed to get offset at %s for partition %d: %w", t.Format(time.RFC3339), partition, err)
+	}
+	if offset < 0 {
+		if offset, err = client.GetOffset(topic, partition, sarama.OffsetNewest); err != nil {
+			return 0, fmt.Errorf("failed to get newest offset for partition %d: %w", partition, err)
+		}
+	}
+	return offset, nil
+}
+
+// resolveConsumeEnd works out where a bounded consumer of one partition stops.
+// An end time already covered by the log becomes an offset; one past the last
+// record is checked against each record's timestamp as it arrives.
+func resolveConsumeEnd(client sarama.Client, topic string, partition int32, opts ConsumeOptions) (consumeEnd, error) {
+	newest, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return consumeEnd{}, fmt.Errorf("failed to get newest offset for partition %d: %w", partition, err)
+	}
+	end := consumeEnd{offset: -1, time: opts.EndTime}
+	if opts.EndOffset > 0 {
+		end.offset = opts.EndOffset
+	}
+	if !opts.EndTime.IsZero() {
+		// The first record after the end time, i.e. at or after the next millisecond
+		after, err := client.GetOffset(topic, partition, opts.EndTime.Add(time.Millisecond).UnixMilli())
+		if err != nil {
+			return consumeEnd{}, fmt.Errorf("failed to get offset at %s for partition %d: %w", opts.EndTime.Format(time.RFC3339), partition, err)
+		}
+		if after >= 0 && (end.offset < 0 || after < end.offset) {
+			end.offset = after
+		}
+	}
+	end.inLog = end.offset >= 0 && end.offset <= newest
+	return end, nil
+}
+
+// concreteOffset turns the sarama.OffsetOldest and OffsetNewest sentinels into
+// the partition's actual offsets so they can be compared with an end
+func concreteOffset(client sarama.Client, topic string, partition int32, offset int64) (int64, error) {
+	if offset != sarama.OffsetOldest && offset != sarama.OffsetNewest {
+		return offset, nil
+	}
+	resolved, err := client.GetOffset(topic, partition, offset)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get offset for partition %d: %w", partition, err)
+	}
+	return resolved, nil
+}
+
+// consumeBoundedPartitions reads each partition from its start offset to its
+// end and returns once all are done or ctx is cancelled. It always closes
+// consumer.
+func (c *Client) consumeBoundedPartitions(ctx context.Context, consumer sarama.Consumer, topic string, startOffsets map[int32]int64, ends map[int32]consumeEnd, messageChan chan<- Message) error {
+	defer func() {
+		if closeErr := consumer.Close(); closeErr != nil {
+			logger.Get().WithError(closeErr).Warn("Failed to close consumer during cleanup")
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for partition, start := range startOffsets {
+		end := ends[partition]
+		if end.offset >= 0 && start >= end.offset {
+			continue
+		}
+		pc, err := consumer.ConsumePartition(topic, partition, start)
+		if err != nil {
+			cancel()
+			wg.Wait()
+			return fmt.Errorf("failed to consume partition %d: %w", partition, err)
+		}
+		wg.Add(1)
+		go func(pc sarama.PartitionConsumer) {
+			defer wg.Done()
+			defer func() {
+				if closeErr := pc.Close(); closeErr != nil {
+					logger.Get().WithError(closeErr).Warn("Failed to close partition consumer during cleanup")
+				}
+			}()
+			readBoundedPartition(ctx, pc, topic, end, messageChan)
+		}(pc)
+	}
+	wg.Wait()
+	return nil
+}
+
+// readBoundedPartition forwards records until the end is reached
+func readBoundedPartition(ctx context.Context, pc sarama.PartitionConsumer, topic string, end consumeEnd, messageChan chan<- Message) {
+	var gaps offsetGapTracker
+	// A partition still waiting for its end offset to be written never goes
+	// idle
+	_ = readPartition(ctx, pc, end.inLog, func(msg *sarama.ConsumerMessage) bool {
+		if end.reached(msg) {
+			return false
+		}
+		message := newMessage(msg)
+		message.Skipped = gaps.observe(msg.Partition, msg.Offset)
+		select {
+		case messageChan <- message:
+		case <-ctx.Done():
+			return false
+		}
+		return !end.last(msg)
+	}, func(err error) error {
+		select {
+		case messageChan <- Message{Topic: topic, Value: fmt.Sprintf("Error: %v", err)}:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// readPartition passes the records of pc to fn until it returns false, the
+// partition is closed or ctx is done. With idle set it also stops once no
+// record has arrived for partitionReadIdle. Errors go to onErr, reading stops
+// on any error it returns, a nil onErr ignores them
+func readPartition(ctx context.Context, pc sarama.PartitionConsumer, idle bool, fn func(*sarama.ConsumerMessage) bool, onErr func(error) error) error {
+	// A nil channel never fires
+	var idleC <-chan time.Time
+	var timer *time.Timer
+	if idle {
+		timer = time.NewTimer(partitionReadIdle)
+		defer timer.Stop()
+		idleC = timer.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-idleC:
+			return nil
+		case msg, ok := <-pc.Messages():
+			if !ok || msg == nil || !fn(msg) {
+				return nil
+			}
+			if timer != nil {
+				timer.Reset(partitionReadIdle)
+			}
+		case err := <-pc.Errors():
+			if err != nil && onErr != nil {
+				if err := onErr(err); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
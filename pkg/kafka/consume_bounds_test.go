@@ -0,0 +1,82 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+func TestConsumeEnd(t *testing.T) {
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	msg := func(offset int64, ts time.Time) *sarama.ConsumerMessage {
+		return &sarama.ConsumerMessage{Offset: offset, Timestamp: ts}
+	}
+
+	byOffset := consumeEnd{offset: 10}
+	if byOffset.reached(msg(9, at)) || !byOffset.last(msg(9, at)) {
+		t.Errorf("offset 9 should be the last record before end 10")
+	}
+	if !byOffset.reached(msg(12, at)) {
+		t.Errorf("offset 12 should be past end 10")
+	}
+
+	byTime := consumeEnd{offset: -1, time: at}
+	if byTime.reached(msg(100, at)) || byTime.last(msg(100, at)) {
+		t.Errorf("a record stamped at the end time is still read")
+	}
+	if !byTime.reached(msg(101, at.Add(time.Millisecond))) {
+		t.Errorf("a record after the end time should stop the partition")
+	}
+}
+
+func TestConsumeOptionsBounded(t *testing.T) {
+	if (ConsumeOptions{StartOffset: 5}).Bounded() {
+		t.Errorf("options without an end are not bounded")
+	}
+	if !(ConsumeOptions{EndOffset: 5}).Bounded() || !(ConsumeOptions{EndTime: time.Now()}).Bounded() {
+		t.Errorf("an end offset or time bounds consumption")
+	}
+}
+
+func TestConsumeMessagesStopsAtEndOffset(t *testing.T) {
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+
+	fetch := sarama.NewMockFetchResponse(t, 1).SetHighWaterMark("orders", 0, 4)
+	for offset := int64(0); offset < 4; offset++ {
+		fetch.SetMessage("orders", 0, offset, sarama.StringEncoder("value"))
+	}
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"ApiVersionsRequest": sarama.NewMockApiVersionsResponse(t),
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader("orders", 0, broker.BrokerID()),
+		"OffsetRequest": sarama.NewMockOffsetResponse(t).
+			SetOffset("orders", 0, sarama.OffsetOldest, 0).
+			SetOffset("orders", 0, sarama.OffsetNewest, 4),
+		"FetchRequest": fetch,
+	})
+
+	client := &Client{brokers: []string{broker.Addr()}, config: sarama.NewConfig()}
+	messages := make(chan Message, 10)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := client.ConsumeMessagesWithOptions(ctx, "orders", messages, ConsumeOptions{StartOffset: sarama.OffsetOldest, EndOffset: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("consumption did not stop at the end offset")
+	}
+	close(messages)
+	var offsets []int64
+	for msg := range messages {
+		offsets = append(offsets, msg.Offset)
+	}
+	if len(offsets) != 2 || offsets[0] != 0 || offsets[1] != 1 {
+		t.Errorf("read offsets %v, want [0 1]", offsets)
+	}
+}
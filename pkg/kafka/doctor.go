@@ -0,0 +1,246 @@
+package kafka
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// doctorTimeout bounds each network step, so a black-holed broker fails
+// quickly instead of after sarama's 30 second default
+const doctorTimeout = 10 * time.Second
+
+// DiagnosticStep is the outcome of one connectivity check against a broker
+type DiagnosticStep struct {
+	Name     string
+	Duration time.Duration
+	Detail   string // What was found, when the step passed
+	Err      error
+	Hint     string // What to try next, when the step failed
+}
+
+// BrokerDiagnosis holds the checks run against one broker address
+type BrokerDiagnosis struct {
+	Address    string
+	Advertised bool // Found in the cluster metadata rather than given
+	Steps      []DiagnosticStep
+}
+
+// OK reports whether every check passed
+func (d BrokerDiagnosis) OK() bool {
+	for _, step := range d.Steps {
+		if step.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Diagnose checks each broker in turn: the TCP connection, the TLS
+// handshake, SASL authentication, API version negotiation and a metadata
+// fetch. Brokers the cluster advertises are checked too, since clients must
+// reach those as well as the bootstrap addresses. The error is only set when
+// the settings themselves are unusable.
+func Diagnose(brokers []string, saslConfig *SASLConfig, tlsConfig *TLSConfig, netConfig *NetworkConfig) ([]BrokerDiagnosis, error) {
+	config, err := newSaramaConfig(saslConfig, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	config.Net.DialTimeout = doctorTimeout
+	config.Net.ReadTimeout = doctorTimeout
+	config.Net.WriteTimeout = doctorTimeout
+	// Versions are asked for as a step of their own
+	config.ApiVersionsRequest = false
+
+	tunnel, err := configureNetwork(config, netConfig)
+	if err != nil {
+		return nil, err
+	}
+	if tunnel != nil {
+		defer tunnel.Close()
+	}
+
+	var results []BrokerDiagnosis
+	checked := make(map[string]bool)
+	queue := append([]string{}, brokers...)
+	for i := 0; i < len(queue); i++ {
+		addr := queue[i]
+		if checked[addr] {
+			continue
+		}
+		checked[addr] = true
+
+		diagnosis, advertised := diagnoseBroker(config, addr)
+		diagnosis.Advertised = i >= len(brokers)
+		results = append(results, diagnosis)
+		queue = append(queue, advertised...)
+	}
+	return results, nil
+}
+
+// diagnoseBroker runs the checks against addr, stopping at the first that
+// fails, and returns the broker addresses its metadata advertises
+func diagnoseBroker(config *sarama.Config, addr string) (BrokerDiagnosis, []string) {
+	d := BrokerDiagnosis{Address: addr}
+	step := func(name string, fn func() (string, error)) bool {
+		start := time.Now()
+		detail, err := fn()
+		s := DiagnosticStep{Name: name, Duration: time.Since(start), Detail: detail, Err: err}
+		if err != nil {
+			s.Hint = diagnosticHint(name, err, config)
+		}
+		d.Steps = append(d.Steps, s)
+		return err == nil
+	}
+
+	var conn net.Conn
+	if !step("TCP connect", func() (string, error) {
+		var err error
+		conn, err = brokerDialer(config).Dial("tcp", addr)
+		if err != nil {
+			return "", err
+		}
+		return "connected to " + conn.RemoteAddr().String(), nil
+	}) {
+		return d, nil
+	}
+
+	if config.Net.TLS.Enable {
+		ok := step("TLS handshake", func() (string, error) {
+			tlsConn := tls.Client(conn, validServerName(addr, config.Net.TLS.Config))
+			_ = tlsConn.SetDeadline(time.Now().Add(doctorTimeout))
+			if err := tlsConn.Handshake(); err != nil {
+				return "", err
+			}
+			state := tlsConn.ConnectionState()
+			leaf := state.PeerCertificates[0]
+			return fmt.Sprintf("%s, certificate for %s expires %s", tls.VersionName(state.Version),
+				leaf.Subject.CommonName, leaf.NotAfter.Format("2006-01-02")), nil
+		})
+		if !ok {
+			conn.Close()
+			return d, nil
+		}
+	}
+	conn.Close()
+
+	broker := sarama.NewBroker(addr)
+	defer broker.Close()
+	open := func() (string, error) {
+		if err := broker.Open(config); err != nil {
+			return "", err
+		}
+		if _, err := broker.Connected(); err != nil {
+			return "", err
+		}
+		if config.Net.SASL.Enable {
+			return fmt.Sprintf("%s as %s", config.Net.SASL.Mechanism, config.Net.SASL.User), nil
+		}
+		return "", nil
+	}
+	versions := func() (string, error) {
+		resp, err := broker.ApiVersions(&sarama.ApiVersionsRequest{})
+		if err != nil {
+			return "", err
+		}
+		if resp.ErrorCode != int16(sarama.ErrNoError) {
+			return "", sarama.KError(resp.ErrorCode)
+		}
+		return describeAPIVersions(resp.ApiKeys), nil
+	}
+	if config.Net.SASL.Enable {
+		if !step("SASL authentication", open) || !step("API versions", versions) {
+			return d, nil
+		}
+	} else if !step("API versions", func() (string, error) {
+		if _, err := open(); err != nil {
+			return "", err
+		}
+		return versions()
+	}) {
+		return d, nil
+	}
+
+	var advertised []string
+	step("Metadata", func() (string, error) {
+		resp, err := broker.GetMetadata(sarama.NewMetadataRequest(config.Version, nil))
+		if err != nil {
+			return "", err
+		}
+		for _, b := range resp.Brokers {
+			advertised = append(advertised, b.Addr())
+		}
+		return fmt.Sprintf("%d brokers, %d topics, controller %d", len(resp.Brokers), len(resp.Topics), resp.ControllerID), nil
+	})
+	return d, advertised
+}
+
+// describeAPIVersions summarizes the highest versions of the APIs kconduit
+// leans on most
+func describeAPIVersions(keys []sarama.ApiVersionsResponseKey) string {
+	names := map[int16]string{0: "Produce", 1: "Fetch", 3: "Metadata"}
+	var parts []string
+	for _, key := range keys {
+		if name, ok := names[key.ApiKey]; ok {
+			parts = append(parts, fmt.Sprintf("%s v%d", name, key.MaxVersion))
+		}
+	}
+	return fmt.Sprintf("%d APIs (%s)", len(keys), strings.Join(parts, ", "))
+}
+
+// diagnosticHint suggests what to change for the error a step failed with
+func diagnosticHint(step string, err error, config *sarama.Config) string {
+	var (
+		dnsErr      *net.DNSError
+		netErr      net.Error
+		unknownCA   x509.UnknownAuthorityError
+		hostnameErr x509.HostnameError
+		invalidErr  x509.CertificateInvalidError
+		recordErr   tls.RecordHeaderError
+	)
+	switch {
+	case errors.As(err, &dnsErr):
+		return "The host name does not resolve from here. If it is an internal name the cluster advertises, map it to a reachable address with --broker-address-map"
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return "Nothing is listening on that port. Check the port and that the broker is running"
+	case errors.As(err, &unknownCA):
+		return "The broker's certificate is not signed by a trusted CA. Pass the CA with --tls-ca-cert or --tls-truststore"
+	case errors.As(err, &hostnameErr):
+		return "The broker's certificate does not cover this host name. Connect with a name the certificate lists"
+	case errors.As(err, &invalidErr):
+		return "The broker's certificate is not valid: " + invalidErr.Error()
+	case errors.As(err, &recordErr):
+		return "The listener does not speak TLS. Drop --tls or connect to the broker's TLS port"
+	case errors.Is(err, sarama.ErrSASLAuthenticationFailed):
+		return "The broker rejected the credentials. Check --sasl-username, --sasl-password and --sasl-mechanism"
+	case errors.Is(err, sarama.ErrUnsupportedSASLMechanism):
+		return "The broker does not offer this SASL mechanism. Try another --sasl-mechanism"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		if step == "TCP connect" {
+			return "No response. A firewall may be dropping the traffic, or the broker may only be reachable through --proxy or --ssh-host"
+		}
+		if !config.Net.TLS.Enable {
+			return "The broker did not answer. The listener may expect TLS, try --tls"
+		}
+		return "The broker did not answer in time"
+	case errors.Is(err, io.EOF), errors.Is(err, syscall.ECONNRESET):
+		switch {
+		case !config.Net.TLS.Enable:
+			return "The broker closed the connection. The listener may expect TLS (--tls) or SASL (--sasl)"
+		case !config.Net.SASL.Enable:
+			return "The broker closed the connection. The listener may expect SASL, try --sasl"
+		case step == "TLS handshake":
+			return "The broker closed the connection during the handshake. It may require a client certificate (--tls-client-cert or --tls-keystore)"
+		}
+		return "The broker closed the connection. Check the security protocol matches the listener"
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package kafka
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/IBM/sarama"
+)
+
+func TestDiagnose(t *testing.T) {
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"ApiVersionsRequest": sarama.NewMockApiVersionsResponse(t),
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetController(1).
+			SetBroker(broker.Addr(), 1),
+	})
+
+	results, err := Diagnose([]string{broker.Addr()}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || !results[0].OK() {
+		t.Fatalf("results = %+v, want one passing broker", results)
+	}
+	var names []string
+	for _, step := range results[0].Steps {
+		names = append(names, step.Name)
+	}
+	if got := strings.Join(names, ","); got != "TCP connect,API versions,Metadata" {
+		t.Errorf("steps = %s", got)
+	}
+}
+
+func TestDiagnoseAdvertisedBroker(t *testing.T) {
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+	// Nothing listens on the second broker's address once it is closed
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unreachable := listener.Addr().String()
+	listener.Close()
+
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"ApiVersionsRequest": sarama.NewMockApiVersionsResponse(t),
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetController(1).
+			SetBroker(broker.Addr(), 1).
+			SetBroker(unreachable, 2),
+	})
+
+	results, err := Diagnose([]string{broker.Addr()}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want the bootstrap and advertised brokers", len(results))
+	}
+	advertised := results[1]
+	if advertised.Address != unreachable || !advertised.Advertised || advertised.OK() {
+		t.Fatalf("advertised broker = %+v, want a failed check of %s", advertised, unreachable)
+	}
+	if hint := advertised.Steps[0].Hint; !strings.Contains(hint, "Nothing is listening") {
+		t.Errorf("hint = %q", hint)
+	}
+}
@@ -0,0 +1,114 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// MetadataVersionFeature is the feature KRaft clusters gate metadata and
+// inter-broker protocol changes on
+const MetadataVersionFeature = "metadata.version"
+
+// metadataVersions names the metadata.version levels after the release
+// that introduced them
+var metadataVersions = []string{
+	1: "3.0-IV1", 2: "3.1-IV0", 3: "3.2-IV0", 4: "3.3-IV0", 5: "3.3-IV1", 6: "3.3-IV2", 7: "3.3-IV3",
+	8: "3.4-IV0", 9: "3.5-IV0", 10: "3.5-IV1", 11: "3.5-IV2", 12: "3.6-IV0", 13: "3.6-IV1", 14: "3.6-IV2",
+	15: "3.7-IV0", 16: "3.7-IV1", 17: "3.7-IV2", 18: "3.7-IV3", 19: "3.7-IV4", 20: "3.8-IV0", 21: "3.9-IV0",
+	22: "4.0-IV0", 23: "4.0-IV1", 24: "4.0-IV2", 25: "4.0-IV3",
+}
+
+// MetadataVersionName returns the release name of a metadata.version level,
+// such as 3.7-IV4, or the bare level when it is newer than kconduit knows
+func MetadataVersionName(level int16) string {
+	if level > 0 && int(level) < len(metadataVersions) {
+		return metadataVersions[level]
+	}
+	return fmt.Sprintf("level %d", level)
+}
+
+// metadataVersionRelease returns the release a metadata.version level
+// needs, the newest kconduit knows for levels it does not
+func metadataVersionRelease(level int16) string {
+	level = min(max(level, 1), int16(len(metadataVersions)-1))
+	release, _, _ := strings.Cut(metadataVersions[level], "-")
+	return release
+}
+
+// Feature is one versioned cluster feature. Supported is the range the
+// broker asked can run; Finalized is the level the cluster has agreed on.
+type Feature struct {
+	Name         string
+	IsSupported  bool
+	MinSupported int16
+	MaxSupported int16
+	IsFinalized  bool
+	Finalized    int16
+}
+
+// ClusterFeatures are the feature flags a broker reports
+type ClusterFeatures struct {
+	Broker   int32
+	Epoch    int64 // Of the finalized features, -1 when there are none
+	Features []Feature
+}
+
+// Feature looks a feature up by name
+func (f *ClusterFeatures) Feature(name string) (Feature, bool) {
+	for _, feature := range f.Features {
+		if feature.Name == name {
+			return feature, true
+		}
+	}
+	return Feature{}, false
+}
+
+// GetClusterFeatures asks the controller for the supported and finalized
+// feature versions, which brokers report from Kafka 2.7 on
+func (c *Client) GetClusterFeatures() (_ *ClusterFeatures, err error) {
+	_, span := startSpan(context.Background(), "GetClusterFeatures")
+	defer func() { endSpan(span, err) }()
+
+	controller, err := c.admin.Controller()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get controller: %w", err)
+	}
+	conn, err := dialRaw(c.config, controller.Addr())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	features := featuresOf(conn.apiVersions)
+	features.Broker = controller.ID()
+	return features, nil
+}
+
+// featuresOf collects the features of an ApiVersions response
+func featuresOf(resp *kmsg.ApiVersionsResponse) *ClusterFeatures {
+	byName := make(map[string]Feature)
+	for _, s := range resp.SupportedFeatures {
+		f := byName[s.Name]
+		f.Name, f.IsSupported, f.MinSupported, f.MaxSupported = s.Name, true, s.MinVersion, s.MaxVersion
+		byName[s.Name] = f
+	}
+	for _, fin := range resp.FinalizedFeatures {
+		f := byName[fin.Name]
+		f.Name, f.Finalized, f.IsFinalized = fin.Name, fin.MaxVersionLevel, true
+		byName[fin.Name] = f
+	}
+
+	features := &ClusterFeatures{Epoch: -1}
+	if len(resp.FinalizedFeatures) > 0 {
+		features.Epoch = resp.FinalizedFeaturesEpoch
+	}
+	for _, f := range byName {
+		features.Features = append(features.Features, f)
+	}
+	sort.Slice(features.Features, func(i, j int) bool { return features.Features[i].Name < features.Features[j].Name })
+	return features
+}
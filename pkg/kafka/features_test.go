@@ -0,0 +1,68 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"github.com/twmb/franz-go/pkg/kversion"
+)
+
+func TestFeaturesOf(t *testing.T) {
+	resp := kmsg.NewPtrApiVersionsResponse()
+	resp.SupportedFeatures = []kmsg.ApiVersionsResponseSupportedFeature{
+		{Name: "metadata.version", MinVersion: 1, MaxVersion: 19},
+		{Name: "kraft.version", MinVersion: 0, MaxVersion: 1},
+	}
+	resp.FinalizedFeaturesEpoch = 42
+	resp.FinalizedFeatures = []kmsg.ApiVersionsResponseFinalizedFeature{
+		{Name: "metadata.version", MinVersionLevel: 1, MaxVersionLevel: 19},
+	}
+
+	features := featuresOf(resp)
+	if features.Epoch != 42 || len(features.Features) != 2 {
+		t.Fatalf("features = %+v", features)
+	}
+	if features.Features[0].Name != "kraft.version" || features.Features[0].IsFinalized {
+		t.Errorf("first feature = %+v, want kraft.version, not finalized", features.Features[0])
+	}
+	mv, ok := features.Feature(MetadataVersionFeature)
+	if !ok || !mv.IsFinalized || mv.Finalized != 19 || mv.MaxSupported != 19 {
+		t.Errorf("metadata.version = %+v", mv)
+	}
+
+	if got := featuresOf(kmsg.NewPtrApiVersionsResponse()); got.Epoch != -1 || len(got.Features) != 0 {
+		t.Errorf("features of a pre-2.7 broker = %+v, want none", got)
+	}
+}
+
+func TestKafkaVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions *kversion.Versions
+		metadata int16 // Finalized metadata.version level, 0 for none
+		want     string
+	}{
+		{"2.8", kversion.V2_8_0(), 0, "2.8"},
+		{"3.7", kversion.V3_7_0(), 0, "3.7"},
+		{"metadata.version matches", kversion.V3_7_0(), 19, "3.7"},
+		{"metadata.version not yet upgraded", kversion.V3_7_0(), 14, "3.7 (metadata 3.6)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := kmsg.NewPtrApiVersionsResponse()
+			tt.versions.EachMaxKeyVersion(func(k, v int16) {
+				resp.ApiKeys = append(resp.ApiKeys, kmsg.ApiVersionsResponseApiKey{ApiKey: k, MaxVersion: v})
+			})
+			if tt.metadata > 0 {
+				resp.FinalizedFeatures = []kmsg.ApiVersionsResponseFinalizedFeature{{Name: MetadataVersionFeature, MaxVersionLevel: tt.metadata}}
+			}
+			if got := kafkaVersion(resp); got != tt.want {
+				t.Errorf("kafkaVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := kafkaVersion(kmsg.NewPtrApiVersionsResponse()); got != "Unknown" {
+		t.Errorf("kafkaVersion() with no API keys = %q, want Unknown", got)
+	}
+}
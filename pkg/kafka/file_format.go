@@ -0,0 +1,215 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats rendering a Go template instead of yaml or json, as with
+// kubectl's --output
+const (
+	templateFormat     = "go-template="
+	templateFileFormat = "go-template-file="
+)
+
+// FileFormatFromPath picks "json" for .json files and "yaml" otherwise
+func FileFormatFromPath(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return "json"
+	}
+	return "yaml"
+}
+
+// EncodeOutput marshals command output as "yaml" (the default), "json",
+// "go-template=TEMPLATE" or "go-template-file=PATH"
+func EncodeOutput(v any, format string) ([]byte, error) {
+	return encodeFile(v, format)
+}
+
+// CheckOutputFormat reports an unknown format or a template that does not
+// parse, so commands can fail before doing any work
+func CheckOutputFormat(format string) error {
+	switch {
+	case format == "yaml", format == "json", format == "":
+		return nil
+	case strings.HasPrefix(format, templateFormat), strings.HasPrefix(format, templateFileFormat):
+		_, err := parseOutputTemplate(format)
+		return err
+	}
+	return fmt.Errorf("unsupported format %q, use yaml, json, go-template=TEMPLATE or go-template-file=PATH", format)
+}
+
+// parseOutputTemplate parses the template of a go-template format
+func parseOutputTemplate(format string) (*template.Template, error) {
+	text, isTemplate := strings.CutPrefix(format, templateFormat)
+	if path, ok := strings.CutPrefix(format, templateFileFormat); ok {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template: %w", err)
+		}
+		text, isTemplate = string(data), true
+	}
+	if !isTemplate {
+		return nil, fmt.Errorf("%q is not a go-template format", format)
+	}
+	tmpl, err := template.New("output").Option("missingkey=error").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+		"eq": templateEq,
+		"ne": func(a, b any) (bool, error) {
+			eq, err := templateEq(a, b)
+			return !eq, err
+		},
+		"lt": func(a, b any) (bool, error) { c, err := templateCompare(a, b); return c < 0, err },
+		"le": func(a, b any) (bool, error) { c, err := templateCompare(a, b); return c <= 0, err },
+		"gt": func(a, b any) (bool, error) { c, err := templateCompare(a, b); return c > 0, err },
+		"ge": func(a, b any) (bool, error) { c, err := templateCompare(a, b); return c >= 0, err },
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// templateNumber returns v as an exact rational when it is a number. The
+// comparison functions use it so the json.Number values a template sees
+// compare by value with literals, e.g. 1 equals 1.0.
+func templateNumber(v any) (*big.Rat, bool) {
+	if n, ok := v.(json.Number); ok {
+		return new(big.Rat).SetString(string(n))
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Rat).SetInt64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(rv.Uint())), true
+	case reflect.Float32, reflect.Float64:
+		if r := new(big.Rat).SetFloat64(rv.Float()); r != nil {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// templateEq is the template eq, comparing numbers by value. Like the
+// builtin it reports whether a equals any of bs.
+func templateEq(a any, bs ...any) (bool, error) {
+	if len(bs) == 0 {
+		return false, fmt.Errorf("missing argument for comparison")
+	}
+	x, xNum := templateNumber(a)
+	for _, b := range bs {
+		if y, yNum := templateNumber(b); xNum && yNum {
+			if x.Cmp(y) == 0 {
+				return true, nil
+			}
+			continue
+		}
+		if a == nil || b == nil {
+			if a == b {
+				return true, nil
+			}
+			continue
+		}
+		if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+			return false, fmt.Errorf("incompatible types for comparison: %T and %T", a, b)
+		}
+		if a == b {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// templateCompare orders two numbers by value, or two strings
+func templateCompare(a, b any) (int, error) {
+	x, xNum := templateNumber(a)
+	y, yNum := templateNumber(b)
+	if xNum && yNum {
+		return x.Cmp(y), nil
+	}
+	if x, ok := a.(string); ok {
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), nil
+		}
+	}
+	return 0, fmt.Errorf("incompatible types for comparison: %T and %T", a, b)
+}
+
+// executeTemplate renders v with a go-template format. The template sees v
+// as its JSON encoding, so field names match --format json.
+func executeTemplate(v any, format string) ([]byte, error) {
+	tmpl, err := parseOutputTemplate(format)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode json: %w", err)
+	}
+	// Numbers stay json.Number so offsets are not printed as floats
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode json: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, doc); err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeFile marshals v as "yaml" (the default) or "json", or renders it
+// with a go-template format
+func encodeFile(v any, format string) ([]byte, error) {
+	if strings.HasPrefix(format, templateFormat) || strings.HasPrefix(format, templateFileFormat) {
+		return executeTemplate(v, format)
+	}
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode json: %w", err)
+		}
+		return append(data, '\n'), nil
+	case "yaml", "":
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode yaml: %w", err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q, use yaml or json", format)
+	}
+}
+
+// decodeFile unmarshals data written by encodeFile into v
+func decodeFile(data []byte, format string, v any) error {
+	var err error
+	switch format {
+	case "json":
+		err = json.Unmarshal(data, v)
+	case "yaml", "":
+		err = yaml.Unmarshal(data, v)
+	default:
+		return fmt.Errorf("unsupported format %q, use yaml or json", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse %s file: %w", format, err)
+	}
+	return nil
+}
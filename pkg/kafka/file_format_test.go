@@ -0,0 +1,54 @@
+package kafka
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncodeOutputTemplate(t *testing.T) {
+	snapshot := &GroupOffsets{Group: "orders", Offsets: []PartitionOffset{
+		{Topic: "orders", Partition: 0, Offset: 12345678},
+		{Topic: "orders", Partition: 1, Offset: 42},
+	}}
+	file := filepath.Join(t.TempDir(), "offsets.tmpl")
+	if err := os.WriteFile(file, []byte(`{{len .offsets}} offsets`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		format   string
+		want     string
+		wantErr  bool
+		checkErr bool // Caught before rendering
+	}{
+		{"fields by json name", `go-template={{.group}}:{{range .offsets}} {{.partition}}={{.offset}}{{end}}`, "orders: 0=12345678 1=42", false, false},
+		{"json function", `go-template={{json (index .offsets 1)}}`, `{"offset":42,"partition":1,"topic":"orders"}`, false, false},
+		{"template file", "go-template-file=" + file, "2 offsets", false, false},
+		{"missing key", `go-template={{.nope}}`, "", true, false},
+		{"parse error", `go-template={{.group`, "", true, true},
+		{"numbers compare by value", `go-template={{range .offsets}}{{if eq .partition 1.0}}{{.offset}}{{end}}{{end}}`, "42", false, false},
+		{"numbers order by value", `go-template={{range .offsets}}{{if gt .offset 100}}{{.partition}}{{end}}{{if ne .partition 0}}!{{end}}{{end}}`, "0!", false, false},
+		{"strings compare", `go-template={{if eq .group "orders" "payments"}}yes{{end}}`, "yes", false, false},
+		{"incompatible comparison", `go-template={{if eq .group 1}}yes{{end}}`, "", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckOutputFormat(tt.format); (err != nil) != tt.checkErr {
+				t.Errorf("CheckOutputFormat() error = %v, want error %v", err, tt.checkErr)
+			}
+			got, err := EncodeOutput(snapshot, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EncodeOutput() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("EncodeOutput() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if err := CheckOutputFormat("table"); err == nil {
+		t.Errorf("expected an error for an unknown format")
+	}
+}
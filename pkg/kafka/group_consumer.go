@@ -0,0 +1,214 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+)
+
+// GroupEventType identifies what happened in a consumer group session
+type GroupEventType string
+
+const (
+	GroupEventAssigned GroupEventType = "assigned"
+	GroupEventRevoked  GroupEventType = "revoked"
+	GroupEventError    GroupEventType = "error"
+)
+
+// GroupEvent describes a rebalance or error seen by a GroupConsumer
+type GroupEvent struct {
+	Type         GroupEventType
+	MemberID     string
+	GenerationID int32
+	Claims       map[string][]int32
+	Err          error
+	Time         time.Time
+}
+
+// GroupConsumer consumes a topic as a member of a consumer group. Offsets are
+// never committed automatically; call Commit to advance the group explicitly.
+type GroupConsumer struct {
+	groupID string
+	group   sarama.ConsumerGroup
+	events  chan GroupEvent
+
+	mu      sync.Mutex
+	session sarama.ConsumerGroupSession
+}
+
+// NewGroupConsumer joins groupID. initialOffset (sarama.OffsetOldest or
+// sarama.OffsetNewest) is used for partitions without a committed offset.
+func (c *Client) NewGroupConsumer(groupID string, initialOffset int64, isolation sarama.IsolationLevel) (*GroupConsumer, error) {
+	if groupID == "" {
+		return nil, fmt.Errorf("group id is required")
+	}
+
+	config := *c.config
+	config.Consumer.Offsets.AutoCommit.Enable = false
+	config.Consumer.Offsets.Initial = initialOffset
+	config.Consumer.Return.Errors = true
+	config.Consumer.IsolationLevel = isolation
+
+	group, err := sarama.NewConsumerGroup(c.brokers, groupID, &config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer group: %w", err)
+	}
+
+	logger.Get().WithField("group", groupID).Info("Joined consumer group")
+
+	return &GroupConsumer{
+		groupID: groupID,
+		group:   group,
+		events:  make(chan GroupEvent, 32),
+	}, nil
+}
+
+// GroupID returns the consumer group id
+func (g *GroupConsumer) GroupID() string {
+	return g.groupID
+}
+
+// Events returns rebalance and error notifications. The channel is closed
+// once Consume has returned.
+func (g *GroupConsumer) Events() <-chan GroupEvent {
+	return g.events
+}
+
+// Consume forwards messages from topic until ctx is cancelled, rejoining the
+// group after every rebalance. The group and the events channel are closed
+// when Consume returns.
+func (g *GroupConsumer) Consume(ctx context.Context, topic string, messageChan chan<- Message) error {
+	errorsDone := make(chan struct{})
+	defer func() {
+		if err := g.group.Close(); err != nil {
+			logger.Get().WithError(err).Warn("Failed to close consumer group")
+		}
+		// Closing the group ends its errors, so nothing is emitted after this
+		<-errorsDone
+		close(g.events)
+	}()
+
+	go func() {
+		defer close(errorsDone)
+		for err := range g.group.Errors() {
+			g.emit(GroupEvent{Type: GroupEventError, Err: err})
+		}
+	}()
+
+	handler := &groupHandler{owner: g, messageChan: messageChan}
+	for {
+		if err := g.group.Consume(ctx, []string{topic}, handler); err != nil {
+			if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+				return nil
+			}
+			return fmt.Errorf("consumer group %s failed: %w", g.groupID, err)
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// Commit sets the committed offset of a partition, which must currently be
+// assigned to this member. offset is the next offset the group will read.
+func (g *GroupConsumer) Commit(topic string, partition int32, offset int64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.session == nil {
+		return fmt.Errorf("consumer group %s has no active session", g.groupID)
+	}
+
+	assigned := false
+	for _, p := range g.session.Claims()[topic] {
+		if p == partition {
+			assigned = true
+			break
+		}
+	}
+	if !assigned {
+		return fmt.Errorf("partition %d is not assigned to this member", partition)
+	}
+
+	g.session.ResetOffset(topic, partition, offset, "")
+	g.session.Commit()
+
+	logger.Get().WithFields(logger.Fields{
+		"group":     g.groupID,
+		"topic":     topic,
+		"partition": partition,
+		"offset":    offset,
+	}).Info("Committed consumer group offset")
+	return nil
+}
+
+// emit delivers an event without blocking the consumer if nobody is listening
+func (g *GroupConsumer) emit(event GroupEvent) {
+	event.Time = time.Now()
+	select {
+	case g.events <- event:
+	default:
+		logger.Get().WithField("type", event.Type).Debug("Dropped consumer group event")
+	}
+}
+
+// groupHandler implements sarama.ConsumerGroupHandler for GroupConsumer
+type groupHandler struct {
+	owner       *GroupConsumer
+	messageChan chan<- Message
+}
+
+func (h *groupHandler) Setup(session sarama.ConsumerGroupSession) error {
+	h.owner.mu.Lock()
+	h.owner.session = session
+	h.owner.mu.Unlock()
+
+	h.owner.emit(GroupEvent{
+		Type:         GroupEventAssigned,
+		MemberID:     session.MemberID(),
+		GenerationID: session.GenerationID(),
+		Claims:       session.Claims(),
+	})
+	return nil
+}
+
+func (h *groupHandler) Cleanup(session sarama.ConsumerGroupSession) error {
+	h.owner.mu.Lock()
+	h.owner.session = nil
+	h.owner.mu.Unlock()
+
+	h.owner.emit(GroupEvent{
+		Type:         GroupEventRevoked,
+		MemberID:     session.MemberID(),
+		GenerationID: session.GenerationID(),
+		Claims:       session.Claims(),
+	})
+	return nil
+}
+
+func (h *groupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	var gaps offsetGapTracker
+	for {
+		select {
+		case <-session.Context().Done():
+			return nil
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			message := newMessage(msg)
+			message.Skipped = gaps.observe(msg.Partition, msg.Offset)
+
+			select {
+			case h.messageChan <- message:
+			case <-session.Context().Done():
+				return nil
+			}
+		}
+	}
+}
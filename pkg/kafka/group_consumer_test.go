@@ -0,0 +1,126 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// fakeConsumerGroup runs one generation: it sets up and cleans up a session
+// with the given claims, then returns
+type fakeConsumerGroup struct {
+	sarama.ConsumerGroup
+	session *fakeGroupSession
+	errors  chan error
+}
+
+func (f *fakeConsumerGroup) Consume(ctx context.Context, topics []string, handler sarama.ConsumerGroupHandler) error {
+	if err := handler.Setup(f.session); err != nil {
+		return err
+	}
+	return handler.Cleanup(f.session)
+}
+
+func (f *fakeConsumerGroup) Errors() <-chan error { return f.errors }
+
+func (f *fakeConsumerGroup) Close() error {
+	close(f.errors)
+	return nil
+}
+
+type fakeGroupSession struct {
+	sarama.ConsumerGroupSession
+	claims map[string][]int32
+	reset  map[int32]int64
+}
+
+func (s *fakeGroupSession) Claims() map[string][]int32 { return s.claims }
+func (s *fakeGroupSession) MemberID() string           { return "member-1" }
+func (s *fakeGroupSession) GenerationID() int32        { return 3 }
+func (s *fakeGroupSession) Commit()                    {}
+
+func (s *fakeGroupSession) ResetOffset(topic string, partition int32, offset int64, metadata string) {
+	s.reset[partition] = offset
+}
+
+func TestGroupConsumerEvents(t *testing.T) {
+	session := &fakeGroupSession{claims: map[string][]int32{"orders": {0, 2}}}
+	group := &fakeConsumerGroup{session: session, errors: make(chan error, 1)}
+	group.errors <- errors.New("coordinator moved")
+	g := &GroupConsumer{groupID: "billing", group: group, events: make(chan GroupEvent, 32)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := g.Consume(ctx, "orders", make(chan Message)); err != nil {
+		t.Fatal(err)
+	}
+
+	var rebalances []GroupEvent
+	sawError := false
+	timeout := time.After(5 * time.Second)
+	for done := false; !done; {
+		select {
+		case event, ok := <-g.Events():
+			if !ok {
+				done = true
+				break
+			}
+			if event.Time.IsZero() {
+				t.Errorf("%s event has no time", event.Type)
+			}
+			if event.Type == GroupEventError {
+				sawError = event.Err.Error() == "coordinator moved"
+				continue
+			}
+			rebalances = append(rebalances, event)
+		case <-timeout:
+			t.Fatal("events channel was not closed after Consume returned")
+		}
+	}
+
+	if !sawError {
+		t.Error("group error was not reported")
+	}
+	if len(rebalances) != 2 || rebalances[0].Type != GroupEventAssigned || rebalances[1].Type != GroupEventRevoked {
+		t.Fatalf("events = %+v, want assigned then revoked", rebalances)
+	}
+	assigned := rebalances[0]
+	if assigned.MemberID != "member-1" || assigned.GenerationID != 3 || !reflect.DeepEqual(assigned.Claims, session.claims) {
+		t.Errorf("assigned event = %+v", assigned)
+	}
+}
+
+func TestGroupConsumerCommit(t *testing.T) {
+	session := &fakeGroupSession{claims: map[string][]int32{"orders": {0, 2}}, reset: map[int32]int64{}}
+	g := &GroupConsumer{groupID: "billing", events: make(chan GroupEvent, 32)}
+
+	if err := g.Commit("orders", 0, 10); err == nil {
+		t.Error("expected an error without an active session")
+	}
+
+	// Setup makes the session active, as a rebalance does
+	handler := &groupHandler{owner: g}
+	if err := handler.Setup(session); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Commit("orders", 1, 10); err == nil {
+		t.Error("expected an error for a partition assigned to another member")
+	}
+	if err := g.Commit("orders", 2, 10); err != nil {
+		t.Fatal(err)
+	}
+	if session.reset[2] != 10 {
+		t.Errorf("offsets reset = %v, want partition 2 at 10", session.reset)
+	}
+
+	if err := handler.Cleanup(session); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Commit("orders", 2, 11); err == nil {
+		t.Error("expected an error once the partitions are revoked")
+	}
+}
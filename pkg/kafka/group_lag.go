@@ -0,0 +1,130 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/IBM/sarama"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// PartitionLag is how far a group's committed offset of one partition is
+// behind the partition's log end
+type PartitionLag struct {
+	Topic     string
+	Partition int32
+	Committed int64 // -1 when the group has not committed an offset
+	LogEnd    int64 // -1 when the partition has no leader
+}
+
+// Lag returns the number of messages the group has yet to consume, -1 when
+// either offset is unknown
+func (p PartitionLag) Lag() int64 {
+	if p.Committed < 0 || p.LogEnd < 0 {
+		return -1
+	}
+	return max(p.LogEnd-p.Committed, 0)
+}
+
+// GetGroupLag returns the lag of every partition of the topics a group has
+// committed offsets for, sorted by topic and partition
+func (c *Client) GetGroupLag(groupID string) (_ []PartitionLag, err error) {
+	_, span := startSpan(context.Background(), "GetGroupLag", attribute.String("group", groupID))
+	defer func() { endSpan(span, err) }()
+
+	resp, err := c.admin.ListConsumerGroupOffsets(groupID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch offsets of group %s: %w", groupID, err)
+	}
+	if resp.Err != sarama.ErrNoError {
+		return nil, fmt.Errorf("failed to fetch offsets of group %s: %w", groupID, resp.Err)
+	}
+	topics := make([]string, 0, len(resp.Blocks))
+	for topic := range resp.Blocks {
+		topics = append(topics, topic)
+	}
+
+	client, err := sarama.NewClient(c.brokers, c.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	defer func() {
+		if closeErr := client.Close(); closeErr != nil {
+			logger.Get().WithError(closeErr).Warn("Failed to close group lag client")
+		}
+	}()
+
+	newest, err := leaderOffsets(client, topics, sarama.OffsetNewest)
+	if err != nil {
+		return nil, err
+	}
+	return groupLag(resp.Blocks, newest, client.Partitions), nil
+}
+
+// groupLag pairs committed offsets with log end offsets for every partition
+// partitionsOf lists, including those the group never committed
+func groupLag(committed map[string]map[int32]*sarama.OffsetFetchResponseBlock, newest map[string]map[int32]int64,
+	partitionsOf func(topic string) ([]int32, error)) []PartitionLag {
+	var lags []PartitionLag
+	for topic, blocks := range committed {
+		partitions, err := partitionsOf(topic)
+		if err != nil {
+			logger.Get().WithField("topic", topic).WithError(err).Debug("Failed to get partitions for group lag")
+			continue
+		}
+		for _, partition := range partitions {
+			p := PartitionLag{Topic: topic, Partition: partition, Committed: -1, LogEnd: -1}
+			if block, ok := blocks[partition]; ok && block.Err == sarama.ErrNoError && block.Offset >= 0 {
+				p.Committed = block.Offset
+			}
+			if end, ok := newest[topic][partition]; ok {
+				p.LogEnd = end
+			}
+			lags = append(lags, p)
+		}
+	}
+	sort.Slice(lags, func(i, j int) bool {
+		if lags[i].Topic != lags[j].Topic {
+			return lags[i].Topic < lags[j].Topic
+		}
+		return lags[i].Partition < lags[j].Partition
+	})
+	return lags
+}
+
+// TopicLag is a group's lag summed over the partitions of one topic
+type TopicLag struct {
+	Topic      string
+	Partitions int
+	Lag        int64
+	Unknown    int // Partitions whose lag is unknown, left out of Lag
+}
+
+// TopicLags sums partition lags per topic, the most lagging topic first
+func TopicLags(partitions []PartitionLag) []TopicLag {
+	index := map[string]int{}
+	var topics []TopicLag
+	for _, p := range partitions {
+		i, ok := index[p.Topic]
+		if !ok {
+			i = len(topics)
+			index[p.Topic] = i
+			topics = append(topics, TopicLag{Topic: p.Topic})
+		}
+		topics[i].Partitions++
+		if lag := p.Lag(); lag >= 0 {
+			topics[i].Lag += lag
+		} else {
+			topics[i].Unknown++
+		}
+	}
+	sort.SliceStable(topics, func(i, j int) bool {
+		if topics[i].Lag != topics[j].Lag {
+			return topics[i].Lag > topics[j].Lag
+		}
+		return topics[i].Topic < topics[j].Topic
+	})
+	return topics
+}
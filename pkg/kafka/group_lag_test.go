@@ -0,0 +1,55 @@
+package kafka
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/IBM/sarama"
+)
+
+func TestGroupLag(t *testing.T) {
+	committed := map[string]map[int32]*sarama.OffsetFetchResponseBlock{
+		"orders": {
+			0: {Offset: 90},
+			1: {Offset: -1}, // Fetched but never committed
+			// Partition 2 missing from the response
+		},
+	}
+	newest := map[string]map[int32]int64{
+		"orders": {0: 100, 1: 40}, // Partition 2 has no leader
+	}
+	partitionsOf := func(string) ([]int32, error) { return []int32{0, 1, 2}, nil }
+
+	want := []PartitionLag{
+		{Topic: "orders", Partition: 0, Committed: 90, LogEnd: 100},
+		{Topic: "orders", Partition: 1, Committed: -1, LogEnd: 40},
+		{Topic: "orders", Partition: 2, Committed: -1, LogEnd: -1},
+	}
+	got := groupLag(committed, newest, partitionsOf)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("groupLag() = %+v, want %+v", got, want)
+	}
+	for i, lag := range []int64{10, -1, -1} {
+		if got[i].Lag() != lag {
+			t.Errorf("partition %d Lag() = %d, want %d", i, got[i].Lag(), lag)
+		}
+	}
+}
+
+func TestTopicLags(t *testing.T) {
+	partitions := []PartitionLag{
+		{Topic: "audit", Partition: 0, Committed: 5, LogEnd: 10},
+		{Topic: "orders", Partition: 0, Committed: 0, LogEnd: 1500000},
+		{Topic: "orders", Partition: 1, Committed: 100, LogEnd: 500100},
+		{Topic: "orders", Partition: 2, Committed: -1, LogEnd: 40},
+		{Topic: "payments", Partition: 0, Committed: 7, LogEnd: 7},
+	}
+	want := []TopicLag{
+		{Topic: "orders", Partitions: 3, Lag: 2000000, Unknown: 1},
+		{Topic: "audit", Partitions: 1, Lag: 5},
+		{Topic: "payments", Partitions: 1, Lag: 0},
+	}
+	if got := TopicLags(partitions); !reflect.DeepEqual(got, want) {
+		t.Fatalf("TopicLags() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,295 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GroupMember is one member of a consumer group and the partitions the
+// group leader assigned to it
+type GroupMember struct {
+	MemberID   string
+	InstanceID string // group.instance.id of a static member, empty for dynamic ones
+	ClientID   string
+	ClientHost string
+	Rack       string
+	Generation int32              // Generation the member last joined in, -1 when its client does not say
+	Subscribed []string           // Topics the member asked for
+	Assigned   map[string][]int32 // Partitions it was given, by topic
+}
+
+// Static reports whether the member uses static membership, so restarting
+// it within the session timeout does not rebalance the group
+func (m GroupMember) Static() bool {
+	return m.InstanceID != ""
+}
+
+// identity names the member across rejoins: static members keep their
+// instance ID while their member ID changes
+func (m GroupMember) identity() string {
+	if m.Static() {
+		return m.InstanceID
+	}
+	return m.MemberID
+}
+
+// PartitionCount returns how many partitions the member was assigned
+func (m GroupMember) PartitionCount() int {
+	n := 0
+	for _, partitions := range m.Assigned {
+		n += len(partitions)
+	}
+	return n
+}
+
+// GroupDescription is a consumer group's state and membership
+type GroupDescription struct {
+	GroupID         string
+	State           string
+	Assignor        string // Partition assignment strategy the members agreed on
+	Members         []GroupMember
+	Coordinator     int32          // -1 when it could not be found
+	CoordinatorAddr string         // Host and port of the coordinator, empty when not found
+	Timeouts        *GroupTimeouts // Nil when the coordinator's config could not be read
+}
+
+// GroupTimeouts are the coordinator's bounds on the session timeout members
+// ask for, and how long it waits for more members before the first
+// assignment of a new group
+type GroupTimeouts struct {
+	MinSession            time.Duration
+	MaxSession            time.Duration
+	InitialRebalanceDelay time.Duration
+}
+
+// Generation returns the newest generation a member reports, -1 when none
+// does
+func (g *GroupDescription) Generation() int32 {
+	generation := int32(-1)
+	for _, m := range g.Members {
+		generation = max(generation, m.Generation)
+	}
+	return generation
+}
+
+// RebalanceReason compares the group to an earlier description of it and
+// explains what would have made it rebalance: members joining or leaving,
+// or changed subscriptions. Kafka does not report why a group rebalanced,
+// so this is only what can be told from the outside. ok is false when
+// nothing changed.
+func (g *GroupDescription) RebalanceReason(prev *GroupDescription) (reason string, ok bool) {
+	before := make(map[string]GroupMember, len(prev.Members))
+	for _, m := range prev.Members {
+		before[m.identity()] = m
+	}
+
+	var joined, left, resubscribed, restarted []string
+	for _, m := range g.Members {
+		old, found := before[m.identity()]
+		delete(before, m.identity())
+		switch {
+		case !found:
+			joined = append(joined, m.identity())
+		case !slices.Equal(old.Subscribed, m.Subscribed):
+			resubscribed = append(resubscribed, m.identity())
+		case old.MemberID != m.MemberID:
+			restarted = append(restarted, m.identity())
+		}
+	}
+	for id := range before {
+		left = append(left, id)
+	}
+	sort.Strings(left)
+
+	var parts []string
+	describe := func(ids []string, what string) {
+		if len(ids) > 0 {
+			parts = append(parts, fmt.Sprintf("%s %s", strings.Join(ids, ", "), what))
+		}
+	}
+	describe(joined, "joined")
+	describe(left, "left")
+	describe(resubscribed, "changed subscription")
+	describe(restarted, "rejoined as a new member")
+	if len(parts) == 0 {
+		if g.Generation() != prev.Generation() {
+			return fmt.Sprintf("generation %d → %d with the same members", prev.Generation(), g.Generation()), true
+		}
+		return "", false
+	}
+	return strings.Join(parts, "; "), true
+}
+
+// Topics returns every topic assigned to a member, sorted
+func (g *GroupDescription) Topics() []string {
+	seen := make(map[string]bool)
+	var topics []string
+	for _, m := range g.Members {
+		for topic := range m.Assigned {
+			if !seen[topic] {
+				seen[topic] = true
+				topics = append(topics, topic)
+			}
+		}
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+// Unbalanced reports whether the partitions of topic are spread unevenly:
+// some member subscribed to it holds two or more partitions more than
+// another
+func (g *GroupDescription) Unbalanced(topic string) bool {
+	lowest, highest := -1, 0
+	for _, m := range g.Members {
+		if !m.subscribes(topic) {
+			continue
+		}
+		n := len(m.Assigned[topic])
+		if lowest < 0 || n < lowest {
+			lowest = n
+		}
+		highest = max(highest, n)
+	}
+	return lowest >= 0 && highest-lowest > 1
+}
+
+func (m GroupMember) subscribes(topic string) bool {
+	if _, ok := m.Assigned[topic]; ok {
+		return true
+	}
+	for _, t := range m.Subscribed {
+		if t == topic {
+			return true
+		}
+	}
+	return false
+}
+
+// DescribeGroup returns a consumer group's members with their subscriptions
+// and assignments, decoded from the consumer protocol
+func (c *Client) DescribeGroup(groupID string) (_ *GroupDescription, err error) {
+	_, span := startSpan(context.Background(), "DescribeGroup", attribute.String("group", groupID))
+	defer func() { endSpan(span, err) }()
+
+	descriptions, err := c.admin.DescribeConsumerGroups([]string{groupID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe group %s: %w", groupID, err)
+	}
+	if len(descriptions) == 0 {
+		return nil, fmt.Errorf("group %s not found", groupID)
+	}
+	desc := descriptions[0]
+	if desc.Err != 0 {
+		return nil, fmt.Errorf("failed to describe group %s: %w", groupID, desc.Err)
+	}
+
+	g := &GroupDescription{GroupID: desc.GroupId, State: desc.State, Assignor: desc.Protocol, Coordinator: -1}
+	for _, member := range desc.Members {
+		m := GroupMember{
+			MemberID:   member.MemberId,
+			ClientID:   member.ClientId,
+			ClientHost: member.ClientHost,
+			Generation: -1,
+			Assigned:   map[string][]int32{},
+		}
+		if member.GroupInstanceId != nil {
+			m.InstanceID = *member.GroupInstanceId
+		}
+		// Groups of other protocol types, such as Connect workers, do not
+		// carry consumer subscriptions and assignments
+		if desc.ProtocolType == "consumer" {
+			if metadata, err := member.GetMemberMetadata(); err == nil && metadata != nil {
+				m.Subscribed = metadata.Topics
+				if metadata.Version >= 2 {
+					m.Generation = metadata.GenerationID
+				}
+				if metadata.RackID != nil {
+					m.Rack = *metadata.RackID
+				}
+			}
+			if assignment, err := member.GetMemberAssignment(); err == nil && assignment != nil {
+				for topic, partitions := range assignment.Topics {
+					sorted := append([]int32(nil), partitions...)
+					sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+					m.Assigned[topic] = sorted
+				}
+			}
+		}
+		g.Members = append(g.Members, m)
+	}
+	sort.Slice(g.Members, func(i, j int) bool { return g.Members[i].MemberID < g.Members[j].MemberID })
+
+	// The coordinator and its timeouts are extra detail, so failing to get
+	// them is not an error
+	coordinator, err := c.admin.Coordinator(groupID)
+	if err != nil {
+		logger.Get().WithField("group", groupID).WithError(err).Debug("Failed to find group coordinator")
+		return g, nil
+	}
+	g.Coordinator, g.CoordinatorAddr = coordinator.ID(), coordinator.Addr()
+	if g.Timeouts, err = c.groupTimeouts(coordinator.ID()); err != nil {
+		logger.Get().WithField("group", groupID).WithError(err).Debug("Failed to get group timeouts")
+	}
+	return g, nil
+}
+
+// CoordinatorLabel names the group's coordinator as its broker ID and
+// address, e.g. "2 (kafka-2:9092)", or "unknown"
+func (g *GroupDescription) CoordinatorLabel() string {
+	return coordinatorLabel(g.Coordinator, g.CoordinatorAddr)
+}
+
+func coordinatorLabel(id int32, addr string) string {
+	if id < 0 {
+		return "unknown"
+	}
+	if addr == "" {
+		return strconv.Itoa(int(id))
+	}
+	return fmt.Sprintf("%d (%s)", id, addr)
+}
+
+// groupTimeouts reads the session timeout bounds from the config of the
+// group's coordinator
+func (c *Client) groupTimeouts(coordinator int32) (*GroupTimeouts, error) {
+	entries, err := c.admin.DescribeConfig(sarama.ConfigResource{
+		Type: sarama.BrokerResource,
+		Name: strconv.Itoa(int(coordinator)),
+		ConfigNames: []string{
+			"group.min.session.timeout.ms",
+			"group.max.session.timeout.ms",
+			"group.initial.rebalance.delay.ms",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	timeouts := &GroupTimeouts{}
+	for _, e := range entries {
+		ms, err := strconv.ParseInt(e.Value, 10, 64)
+		if err != nil {
+			continue
+		}
+		d := time.Duration(ms) * time.Millisecond
+		switch e.Name {
+		case "group.min.session.timeout.ms":
+			timeouts.MinSession = d
+		case "group.max.session.timeout.ms":
+			timeouts.MaxSession = d
+		case "group.initial.rebalance.delay.ms":
+			timeouts.InitialRebalanceDelay = d
+		}
+	}
+	return timeouts, nil
+}
@@ -0,0 +1,110 @@
+package kafka
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupDescriptionUnbalanced(t *testing.T) {
+	g := &GroupDescription{Members: []GroupMember{
+		{MemberID: "a", Subscribed: []string{"orders", "payments"}, Assigned: map[string][]int32{"orders": {0, 1, 2}, "payments": {0}}},
+		{MemberID: "b", Subscribed: []string{"orders", "payments"}, Assigned: map[string][]int32{"orders": {3}, "payments": {1}}},
+		// Subscribed to orders but given nothing yet
+		{MemberID: "c", Subscribed: []string{"orders"}, Assigned: map[string][]int32{}},
+	}}
+
+	if got, want := g.Topics(), []string{"orders", "payments"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Topics() = %v, want %v", got, want)
+	}
+	tests := []struct {
+		topic string
+		want  bool
+	}{
+		{"orders", true},
+		{"payments", false},
+		{"unknown", false},
+	}
+	for _, tt := range tests {
+		if got := g.Unbalanced(tt.topic); got != tt.want {
+			t.Errorf("Unbalanced(%q) = %v, want %v", tt.topic, got, tt.want)
+		}
+	}
+	if n := g.Members[0].PartitionCount(); n != 4 {
+		t.Errorf("PartitionCount() = %d, want 4", n)
+	}
+}
+
+func TestGroupDescriptionRebalanceReason(t *testing.T) {
+	prev := &GroupDescription{Members: []GroupMember{
+		{MemberID: "a-1", Generation: 4, Subscribed: []string{"orders"}},
+		{MemberID: "b-1", InstanceID: "worker-b", Generation: 4, Subscribed: []string{"orders"}},
+		{MemberID: "c-1", Generation: 4, Subscribed: []string{"orders"}},
+	}}
+
+	tests := []struct {
+		name    string
+		members []GroupMember
+		want    string
+		wantOK  bool
+	}{
+		{
+			name:    "unchanged",
+			members: prev.Members,
+		},
+		{
+			name: "joined and left",
+			members: []GroupMember{
+				{MemberID: "a-1", Generation: 5, Subscribed: []string{"orders"}},
+				{MemberID: "b-1", InstanceID: "worker-b", Generation: 5, Subscribed: []string{"orders"}},
+				{MemberID: "d-1", Generation: 5, Subscribed: []string{"orders"}},
+			},
+			want:   "d-1 joined; c-1 left",
+			wantOK: true,
+		},
+		{
+			name: "static member restarted",
+			members: []GroupMember{
+				{MemberID: "a-1", Generation: 4, Subscribed: []string{"orders"}},
+				{MemberID: "b-2", InstanceID: "worker-b", Generation: 4, Subscribed: []string{"orders"}},
+				{MemberID: "c-1", Generation: 4, Subscribed: []string{"orders", "payments"}},
+			},
+			want:   "c-1 changed subscription; worker-b rejoined as a new member",
+			wantOK: true,
+		},
+		{
+			name: "generation only",
+			members: []GroupMember{
+				{MemberID: "a-1", Generation: 6, Subscribed: []string{"orders"}},
+				{MemberID: "b-1", InstanceID: "worker-b", Generation: 6, Subscribed: []string{"orders"}},
+				{MemberID: "c-1", Generation: 6, Subscribed: []string{"orders"}},
+			},
+			want:   "generation 4 → 6 with the same members",
+			wantOK: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &GroupDescription{Members: tt.members}
+			got, ok := g.RebalanceReason(prev)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("RebalanceReason() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGroupDescriptionCoordinatorLabel(t *testing.T) {
+	tests := []struct {
+		g    GroupDescription
+		want string
+	}{
+		{GroupDescription{Coordinator: 2, CoordinatorAddr: "kafka-2:9092"}, "2 (kafka-2:9092)"},
+		{GroupDescription{Coordinator: 0}, "0"},
+		{GroupDescription{Coordinator: -1}, "unknown"},
+	}
+	for _, tt := range tests {
+		if got := tt.g.CoordinatorLabel(); got != tt.want {
+			t.Errorf("CoordinatorLabel() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+)
+
+// PartitionOffset is a committed offset of one topic partition
+type PartitionOffset struct {
+	Topic     string `json:"topic" yaml:"topic"`
+	Partition int32  `json:"partition" yaml:"partition"`
+	Offset    int64  `json:"offset" yaml:"offset"`
+	Metadata  string `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+}
+
+// GroupOffsets is a snapshot of a consumer group's committed offsets, used
+// to export and restore them
+type GroupOffsets struct {
+	Group      string            `json:"group" yaml:"group"`
+	ExportedAt time.Time         `json:"exportedAt" yaml:"exportedAt"`
+	Offsets    []PartitionOffset `json:"offsets" yaml:"offsets"`
+}
+
+// Marshal encodes the snapshot as "yaml" or "json"
+func (g *GroupOffsets) Marshal(format string) ([]byte, error) {
+	return encodeFile(g, format)
+}
+
+// UnmarshalGroupOffsets decodes a snapshot written by GroupOffsets.Marshal
+func UnmarshalGroupOffsets(data []byte, format string) (*GroupOffsets, error) {
+	var g GroupOffsets
+	if err := decodeFile(data, format, &g); err != nil {
+		return nil, err
+	}
+	for i, o := range g.Offsets {
+		if o.Topic == "" || o.Partition < 0 || o.Offset < 0 {
+			return nil, fmt.Errorf("offset %d: topic, partition and a non-negative offset are required", i+1)
+		}
+	}
+	return &g, nil
+}
+
+// GetGroupOffsets returns the committed offsets of a group, sorted by topic
+// and partition. Partitions without a committed offset are left out.
+func (c *Client) GetGroupOffsets(groupID string) (*GroupOffsets, error) {
+	resp, err := c.admin.ListConsumerGroupOffsets(groupID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch offsets of group %s: %w", groupID, err)
+	}
+	if resp.Err != sarama.ErrNoError {
+		return nil, fmt.Errorf("failed to fetch offsets of group %s: %w", groupID, resp.Err)
+	}
+
+	snapshot := &GroupOffsets{Group: groupID, ExportedAt: time.Now().UTC()}
+	for topic, partitions := range resp.Blocks {
+		for partition, block := range partitions {
+			if block.Err != sarama.ErrNoError || block.Offset < 0 {
+				continue
+			}
+			snapshot.Offsets = append(snapshot.Offsets, PartitionOffset{
+				Topic:     topic,
+				Partition: partition,
+				Offset:    block.Offset,
+				Metadata:  block.Metadata,
+			})
+		}
+	}
+	sortPartitionOffsets(snapshot.Offsets)
+	return snapshot, nil
+}
+
+// RestoreGroupOffsets commits offsets for groupID, which may differ from the
+// group they were exported from. The group must have no active members,
+// otherwise the coordinator rejects the commit.
+func (c *Client) RestoreGroupOffsets(groupID string, offsets []PartitionOffset) error {
+	if len(offsets) == 0 {
+		return fmt.Errorf("no offsets to restore")
+	}
+	if err := c.ensureGroupInactive(groupID); err != nil {
+		return err
+	}
+
+	coordinator, err := c.admin.Coordinator(groupID)
+	if err != nil {
+		return fmt.Errorf("failed to find coordinator for group %s: %w", groupID, err)
+	}
+
+	var before any
+	if c.auditing() {
+		if current, err := c.GetGroupOffsets(groupID); err == nil {
+			before = current.Offsets
+		}
+	}
+
+	// Generation -1 and an empty member id commit on behalf of an empty group
+	req := &sarama.OffsetCommitRequest{
+		Version:                 2,
+		ConsumerGroup:           groupID,
+		ConsumerGroupGeneration: sarama.GroupGenerationUndefined,
+		RetentionTime:           -1,
+	}
+	for _, o := range offsets {
+		req.AddBlock(o.Topic, o.Partition, o.Offset, 0, o.Metadata)
+	}
+
+	resp, err := coordinator.CommitOffset(req)
+	if err != nil {
+		c.audit("group.offsets.restore", groupID, before, offsets, err)
+		return fmt.Errorf("failed to commit offsets for group %s: %w", groupID, err)
+	}
+
+	var errs []string
+	for topic, partitions := range resp.Errors {
+		for partition, kerr := range partitions {
+			if kerr != sarama.ErrNoError {
+				errs = append(errs, fmt.Sprintf("%s/%d: %v", topic, partition, kerr))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		err := fmt.Errorf("failed to commit %d of %d offsets: %s", len(errs), len(offsets), strings.Join(errs, "; "))
+		c.audit("group.offsets.restore", groupID, before, offsets, err)
+		return err
+	}
+	c.audit("group.offsets.restore", groupID, before, offsets, nil)
+
+	logger.Get().WithFields(logger.Fields{
+		"group":   groupID,
+		"offsets": len(offsets),
+	}).Info("Restored consumer group offsets")
+	return nil
+}
+
+// DeleteGroupTopicOffsets removes a group's committed offsets for every
+// partition of topic and returns the partitions that were cleared. Kafka
+// refuses while the group's members are still subscribed to the topic.
+func (c *Client) DeleteGroupTopicOffsets(groupID, topic string) ([]int32, error) {
+	resp, err := c.admin.ListConsumerGroupOffsets(groupID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch offsets of group %s: %w", groupID, err)
+	}
+	if resp.Err != sarama.ErrNoError {
+		return nil, fmt.Errorf("failed to fetch offsets of group %s: %w", groupID, resp.Err)
+	}
+
+	var partitions []int32
+	var before []PartitionOffset
+	for partition, block := range resp.Blocks[topic] {
+		if block.Err == sarama.ErrNoError && block.Offset >= 0 {
+			partitions = append(partitions, partition)
+			before = append(before, PartitionOffset{Topic: topic, Partition: partition, Offset: block.Offset, Metadata: block.Metadata})
+		}
+	}
+	sortPartitionOffsets(before)
+	if len(partitions) == 0 {
+		return nil, fmt.Errorf("group %s has no committed offsets for topic %s", groupID, topic)
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
+
+	var deleted []int32
+	for _, partition := range partitions {
+		err := c.admin.DeleteConsumerGroupOffset(groupID, topic, partition)
+		c.audit("group.offsets.delete", groupID, before[len(deleted)], nil, err)
+		if err != nil {
+			if errors.Is(err, sarama.ErrGroupSubscribedToTopic) {
+				return deleted, fmt.Errorf("group %s is still consuming %s, stop or resubscribe its consumers first: %w", groupID, topic, err)
+			}
+			return deleted, fmt.Errorf("failed to delete offset of %s/%d: %w", topic, partition, err)
+		}
+		deleted = append(deleted, partition)
+	}
+
+	logger.Get().WithFields(logger.Fields{
+		"group":      groupID,
+		"topic":      topic,
+		"partitions": len(deleted),
+	}).Info("Deleted consumer group offsets")
+	return deleted, nil
+}
+
+// ensureGroupInactive fails if the group has members, since offsets can only
+// be changed externally while nobody is consuming
+func (c *Client) ensureGroupInactive(groupID string) error {
+	descriptions, err := c.admin.DescribeConsumerGroups([]string{groupID})
+	if err != nil {
+		return fmt.Errorf("failed to describe group %s: %w", groupID, err)
+	}
+	for _, desc := range descriptions {
+		if len(desc.Members) > 0 {
+			return fmt.Errorf("group %s has %d active members (state %s), stop its consumers first", groupID, len(desc.Members), desc.State)
+		}
+	}
+	return nil
+}
+
+func sortPartitionOffsets(offsets []PartitionOffset) {
+	sort.Slice(offsets, func(i, j int) bool {
+		if offsets[i].Topic != offsets[j].Topic {
+			return offsets[i].Topic < offsets[j].Topic
+		}
+		return offsets[i].Partition < offsets[j].Partition
+	})
+}
@@ -0,0 +1,92 @@
+package kafka
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+func TestGroupOffsetsRoundTrip(t *testing.T) {
+	snapshot := &GroupOffsets{
+		Group:      "orders",
+		ExportedAt: time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC),
+		Offsets: []PartitionOffset{
+			{Topic: "orders", Partition: 0, Offset: 42},
+			{Topic: "orders", Partition: 1, Offset: 7, Metadata: "app"},
+		},
+	}
+
+	for _, format := range []string{"yaml", "json"} {
+		t.Run(format, func(t *testing.T) {
+			data, err := snapshot.Marshal(format)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			got, err := UnmarshalGroupOffsets(data, format)
+			if err != nil {
+				t.Fatalf("UnmarshalGroupOffsets() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, snapshot) {
+				t.Errorf("round trip = %+v, want %+v", got, snapshot)
+			}
+		})
+	}
+}
+
+func TestUnmarshalGroupOffsetsValidation(t *testing.T) {
+	if _, err := UnmarshalGroupOffsets([]byte(`{"group":"g","offsets":[{"topic":"t","partition":0,"offset":-1}]}`), "json"); err == nil {
+		t.Error("UnmarshalGroupOffsets() accepted a negative offset")
+	}
+}
+
+// fakeOffsetsAdmin answers offset requests with a fixed response and records
+// the offsets deleted
+type fakeOffsetsAdmin struct {
+	sarama.ClusterAdmin
+	offsets *sarama.OffsetFetchResponse
+	deleted []int32
+}
+
+func (f *fakeOffsetsAdmin) ListConsumerGroupOffsets(group string, partitions map[string][]int32) (*sarama.OffsetFetchResponse, error) {
+	return f.offsets, nil
+}
+
+func (f *fakeOffsetsAdmin) DeleteConsumerGroupOffset(group, topic string, partition int32) error {
+	f.deleted = append(f.deleted, partition)
+	return nil
+}
+
+func TestDeleteGroupTopicOffsets(t *testing.T) {
+	offsets := &sarama.OffsetFetchResponse{}
+	offsets.AddBlock("orders", 1, &sarama.OffsetFetchResponseBlock{Offset: 7})
+	offsets.AddBlock("orders", 0, &sarama.OffsetFetchResponseBlock{Offset: 42})
+	offsets.AddBlock("orders", 2, &sarama.OffsetFetchResponseBlock{Offset: -1})
+	offsets.AddBlock("orders", 3, &sarama.OffsetFetchResponseBlock{Offset: 5, Err: sarama.ErrUnknownTopicOrPartition})
+	admin := &fakeOffsetsAdmin{offsets: offsets}
+	c := &Client{admin: admin}
+
+	deleted, err := c.DeleteGroupTopicOffsets("billing", "orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(deleted, []int32{0, 1}) || !reflect.DeepEqual(admin.deleted, []int32{0, 1}) {
+		t.Errorf("deleted %v (admin %v), want [0 1]", deleted, admin.deleted)
+	}
+}
+
+func TestDeleteGroupTopicOffsetsReportsGroupError(t *testing.T) {
+	// A coordinator or authorization error must not read as "no offsets"
+	admin := &fakeOffsetsAdmin{offsets: &sarama.OffsetFetchResponse{Err: sarama.ErrGroupAuthorizationFailed}}
+	c := &Client{admin: admin}
+
+	_, err := c.DeleteGroupTopicOffsets("billing", "orders")
+	if !errors.Is(err, sarama.ErrGroupAuthorizationFailed) {
+		t.Errorf("error = %v, want %v", err, sarama.ErrGroupAuthorizationFailed)
+	}
+	if len(admin.deleted) > 0 {
+		t.Errorf("deleted %v after a failed fetch", admin.deleted)
+	}
+}
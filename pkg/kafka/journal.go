@@ -0,0 +1,253 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+)
+
+// maxJournalEntries bounds the session journal; the oldest entries are
+// dropped first
+const maxJournalEntries = 200
+
+// ChangeKind identifies what a journalled change modified
+type ChangeKind string
+
+const (
+	ChangeTopicConfig     ChangeKind = "topic.config.update"
+	ChangeTopicCreate     ChangeKind = "topic.create"
+	ChangeTopicDelete     ChangeKind = "topic.delete"
+	ChangeTopicPartitions ChangeKind = "topic.partitions.update"
+	ChangeACLCreate       ChangeKind = "acl.create"
+	ChangeACLDelete       ChangeKind = "acl.delete"
+)
+
+// Change is a single modification made through the client together with
+// what is needed to revert or replay it
+type Change struct {
+	Kind              ChangeKind        `yaml:"action"`
+	Topic             string            `yaml:"topic,omitempty"`
+	Key               string            `yaml:"key,omitempty"`      // Config key
+	Previous          string            `yaml:"previous,omitempty"` // Config value or partition count before the change
+	Value             string            `yaml:"value,omitempty"`    // Config value or partition count after the change
+	Partitions        int32             `yaml:"partitions,omitempty"`
+	ReplicationFactor int16             `yaml:"replication_factor,omitempty"`
+	Configs           map[string]string `yaml:"configs,omitempty"` // Set on the created topic
+	ACL               ACL               `yaml:"acl,omitempty"`
+	// Every config the topic overrode before a config change, restored on
+	// undo. Keys absent here were broker or default values.
+	Overrides map[string]string `yaml:"-"`
+}
+
+// Revertible reports whether Undo can revert the change. Kafka cannot
+// remove partitions, and a deleted topic's data is gone.
+func (ch Change) Revertible() bool {
+	return ch.Kind != ChangeTopicDelete && ch.Kind != ChangeTopicPartitions
+}
+
+func (ch Change) String() string {
+	switch ch.Kind {
+	case ChangeTopicConfig:
+		return fmt.Sprintf("%s: %s %s → %s", ch.Topic, ch.Key, ch.Previous, ch.Value)
+	case ChangeTopicCreate:
+		return fmt.Sprintf("created topic %s", ch.Topic)
+	case ChangeTopicDelete:
+		return fmt.Sprintf("deleted topic %s", ch.Topic)
+	case ChangeTopicPartitions:
+		return fmt.Sprintf("%s: partitions %s → %s", ch.Topic, ch.Previous, ch.Value)
+	case ChangeACLCreate:
+		return fmt.Sprintf("created ACL %s", ch.ACL)
+	case ChangeACLDelete:
+		return fmt.Sprintf("deleted ACL %s", ch.ACL)
+	}
+	return string(ch.Kind)
+}
+
+// JournalEntry is one undoable step: a single change, or every change made
+// while a group such as an AI plan was open
+type JournalEntry struct {
+	Time    time.Time
+	Source  string // "user", "ai", ...
+	Label   string
+	Changes []Change
+}
+
+// Journal records the modifications made during a session so the latest can
+// be undone. It is safe for concurrent use.
+type Journal struct {
+	mu      sync.Mutex
+	entries []JournalEntry
+	group   *JournalEntry
+	depth   int
+}
+
+// NewJournal returns an empty journal
+func NewJournal() *Journal {
+	return &Journal{}
+}
+
+// Begin groups the changes recorded until the matching End into a single
+// entry, so they are undone together. Nested groups join the outer one.
+func (j *Journal) Begin(label string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.depth++
+	if j.group == nil {
+		j.group = &JournalEntry{Label: label}
+	}
+}
+
+// End closes the group opened by Begin
+func (j *Journal) End() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.depth == 0 {
+		return
+	}
+	j.depth--
+	if j.depth > 0 {
+		return
+	}
+	if len(j.group.Changes) > 0 {
+		j.append(*j.group)
+	}
+	j.group = nil
+}
+
+// Entries returns the recorded entries, oldest first
+func (j *Journal) Entries() []JournalEntry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return append([]JournalEntry(nil), j.entries...)
+}
+
+func (j *Journal) record(source string, ch Change) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.group != nil {
+		if len(j.group.Changes) == 0 {
+			j.group.Time = time.Now()
+			j.group.Source = source
+		}
+		j.group.Changes = append(j.group.Changes, ch)
+		return
+	}
+	j.append(JournalEntry{Time: time.Now(), Source: source, Label: ch.String(), Changes: []Change{ch}})
+}
+
+func (j *Journal) append(e JournalEntry) {
+	j.entries = append(j.entries, e)
+	if len(j.entries) > maxJournalEntries {
+		j.entries = j.entries[len(j.entries)-maxJournalEntries:]
+	}
+}
+
+func (j *Journal) pop() (JournalEntry, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if len(j.entries) == 0 {
+		return JournalEntry{}, false
+	}
+	e := j.entries[len(j.entries)-1]
+	j.entries = j.entries[:len(j.entries)-1]
+	return e, true
+}
+
+// Journal returns the session journal shared by c and its clones
+func (c *Client) Journal() *Journal {
+	return c.journal
+}
+
+func (c *Client) recordChange(ch Change) {
+	c.recordSession(ch)
+	if c.journal == nil {
+		return
+	}
+	source := c.auditSource
+	if source == "" {
+		source = "user"
+	}
+	c.journal.record(source, ch)
+}
+
+// Undo reverts the most recent journal entry, newest change first, and
+// returns it. Changes Kafka cannot revert, such as added partitions, are
+// reported in the error and dropped. Revertible changes that fail stay in
+// the journal so Undo can be retried.
+func (c *Client) Undo() (JournalEntry, error) {
+	if c.journal == nil {
+		return JournalEntry{}, fmt.Errorf("nothing to undo")
+	}
+	entry, ok := c.journal.pop()
+	if !ok {
+		return JournalEntry{}, fmt.Errorf("nothing to undo")
+	}
+
+	// Reverts are audited but not journalled themselves
+	rc := *c
+	rc.journal = nil
+	rc.auditSource = "undo"
+
+	var errs []error
+	var retry []Change
+	for i := len(entry.Changes) - 1; i >= 0; i-- {
+		ch := entry.Changes[i]
+		if err := rc.revert(ch); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ch, err))
+			if ch.Revertible() {
+				retry = append([]Change{ch}, retry...)
+			}
+		}
+	}
+	if len(retry) > 0 {
+		failed := entry
+		failed.Changes = retry
+		c.journal.mu.Lock()
+		c.journal.append(failed)
+		c.journal.mu.Unlock()
+	}
+
+	logger.Get().WithField("entry", entry.Label).WithField("failed", len(errs)).Info("Undid journal entry")
+	return entry, errors.Join(errs...)
+}
+
+func (c *Client) revert(ch Change) error {
+	switch ch.Kind {
+	case ChangeTopicConfig:
+		return c.restoreTopicConfig(ch)
+	case ChangeTopicCreate:
+		return c.DeleteTopic(ch.Topic)
+	case ChangeACLCreate:
+		return c.DeleteACL(ch.ACL)
+	case ChangeACLDelete:
+		return c.CreateACL(ch.ACL)
+	case ChangeTopicPartitions:
+		return fmt.Errorf("partitions cannot be removed")
+	case ChangeTopicDelete:
+		return fmt.Errorf("deleted topics cannot be restored")
+	}
+	return fmt.Errorf("unknown change %s", ch.Kind)
+}
+
+// restoreTopicConfig puts back the overrides a topic had before a config
+// change. The changed key is deleted when it was not an override, so it
+// falls back to the broker or default value instead of being pinned.
+func (c *Client) restoreTopicConfig(ch Change) error {
+	entries := make(map[string]sarama.IncrementalAlterConfigsEntry, len(ch.Overrides)+1)
+	for key, value := range ch.Overrides {
+		entries[key] = sarama.IncrementalAlterConfigsEntry{Operation: sarama.IncrementalAlterConfigsOperationSet, Value: &value}
+	}
+	if _, ok := ch.Overrides[ch.Key]; !ok {
+		entries[ch.Key] = sarama.IncrementalAlterConfigsEntry{Operation: sarama.IncrementalAlterConfigsOperationDelete}
+	}
+	err := c.admin.IncrementalAlterConfig(sarama.TopicResource, ch.Topic, entries, false)
+	c.audit("topic.config.update", ch.Topic, map[string]string{ch.Key: ch.Value}, ch.Overrides, err)
+	if err != nil {
+		return fmt.Errorf("failed to restore topic config: %w", err)
+	}
+	return nil
+}
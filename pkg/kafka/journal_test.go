@@ -0,0 +1,133 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/IBM/sarama"
+)
+
+func TestJournalGroups(t *testing.T) {
+	j := NewJournal()
+	j.record("user", Change{Kind: ChangeTopicCreate, Topic: "orders"})
+
+	j.Begin("AI plan")
+	j.Begin("nested")
+	j.record("ai", Change{Kind: ChangeTopicConfig, Topic: "a", Key: "retention.ms", Previous: "1", Value: "2"})
+	j.End()
+	j.record("ai", Change{Kind: ChangeTopicConfig, Topic: "b", Key: "retention.ms", Previous: "1", Value: "2"})
+	j.End()
+
+	j.Begin("empty")
+	j.End()
+
+	entries := j.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2: %+v", len(entries), entries)
+	}
+	if entries[0].Label != "created topic orders" || entries[0].Source != "user" {
+		t.Errorf("first entry = %+v", entries[0])
+	}
+	if entries[1].Label != "AI plan" || entries[1].Source != "ai" || len(entries[1].Changes) != 2 {
+		t.Errorf("grouped entry = %+v", entries[1])
+	}
+}
+
+func TestJournalIsBounded(t *testing.T) {
+	j := NewJournal()
+	for i := 0; i < maxJournalEntries+5; i++ {
+		j.record("user", Change{Kind: ChangeTopicCreate, Topic: "t"})
+	}
+	if got := len(j.Entries()); got != maxJournalEntries {
+		t.Errorf("got %d entries, want %d", got, maxJournalEntries)
+	}
+}
+
+func TestUndoDropsIrreversibleChanges(t *testing.T) {
+	c := &Client{journal: NewJournal()}
+	c.recordChange(Change{Kind: ChangeTopicPartitions, Topic: "orders", Previous: "3", Value: "6"})
+
+	entry, err := c.Undo()
+	if err == nil {
+		t.Fatalf("expected an error undoing a partition increase")
+	}
+	if entry.Label != "orders: partitions 3 → 6" {
+		t.Errorf("entry label = %q", entry.Label)
+	}
+	if len(c.Journal().Entries()) != 0 {
+		t.Errorf("irreversible change left in the journal")
+	}
+	if _, err := c.Undo(); err == nil {
+		t.Errorf("expected nothing to undo")
+	}
+}
+
+// fakeConfigAdmin serves topic configs and records the changes made to them
+type fakeConfigAdmin struct {
+	sarama.ClusterAdmin
+	entries     []sarama.ConfigEntry
+	altered     map[string]*string
+	incremental map[string]sarama.IncrementalAlterConfigsEntry
+}
+
+func (f *fakeConfigAdmin) DescribeConfig(resource sarama.ConfigResource) ([]sarama.ConfigEntry, error) {
+	return f.entries, nil
+}
+
+func (f *fakeConfigAdmin) AlterConfig(resourceType sarama.ConfigResourceType, name string, entries map[string]*string, validateOnly bool) error {
+	f.altered = entries
+	return nil
+}
+
+func (f *fakeConfigAdmin) IncrementalAlterConfig(resourceType sarama.ConfigResourceType, name string, entries map[string]sarama.IncrementalAlterConfigsEntry, validateOnly bool) error {
+	f.incremental = entries
+	return nil
+}
+
+func TestUndoTopicConfigRestoresOverrides(t *testing.T) {
+	admin := &fakeConfigAdmin{entries: []sarama.ConfigEntry{
+		{Name: "retention.ms", Value: "604800000", Source: sarama.SourceDefault, Default: true},
+		{Name: "cleanup.policy", Value: "compact", Source: sarama.SourceTopic},
+		{Name: "max.message.bytes", Value: "2097152", Source: sarama.SourceTopic},
+	}}
+	c := &Client{admin: admin, journal: NewJournal()}
+
+	if err := c.UpdateTopicConfig("orders", "retention.ms", "1000"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Undo(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The defaulted key goes back to the default rather than being pinned,
+	// and the overrides the change wiped are set again
+	if op := admin.incremental["retention.ms"].Operation; op != sarama.IncrementalAlterConfigsOperationDelete {
+		t.Errorf("retention.ms operation = %v, want delete", op)
+	}
+	for key, want := range map[string]string{"cleanup.policy": "compact", "max.message.bytes": "2097152"} {
+		entry, ok := admin.incremental[key]
+		if !ok || entry.Operation != sarama.IncrementalAlterConfigsOperationSet || *entry.Value != want {
+			t.Errorf("%s not restored to %s: %+v", key, want, entry)
+		}
+	}
+	if len(admin.incremental) != 3 {
+		t.Errorf("undo changed %d configs, want 3", len(admin.incremental))
+	}
+}
+
+func TestUndoTopicConfigResetsOverride(t *testing.T) {
+	admin := &fakeConfigAdmin{entries: []sarama.ConfigEntry{
+		{Name: "retention.ms", Value: "86400000", Source: sarama.SourceTopic},
+	}}
+	c := &Client{admin: admin, journal: NewJournal()}
+
+	if err := c.UpdateTopicConfig("orders", "retention.ms", "1000"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	entry := admin.incremental["retention.ms"]
+	if entry.Operation != sarama.IncrementalAlterConfigsOperationSet || *entry.Value != "86400000" {
+		t.Errorf("retention.ms = %+v, want the previous override set again", entry)
+	}
+}
@@ -0,0 +1,154 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// KeyLookupOptions bounds a key lookup by record timestamp. Zero times leave
+// that end open, i.e. the whole topic is scanned by default.
+type KeyLookupOptions struct {
+	From time.Time
+	To   time.Time
+
+	// OnProgress, when set, is called after each partition is scanned
+	OnProgress func(progress KeyLookupProgress)
+}
+
+// KeyLookupProgress reports how far a key lookup has got
+type KeyLookupProgress struct {
+	Partitions int   // Partitions to scan
+	Done       int   // Partitions scanned so far
+	Scanned    int64 // Records read so far
+	Matches    int
+}
+
+// KeyLookupResult lists the records found with the key, in offset order
+// within each partition and partitions in ascending order
+type KeyLookupResult struct {
+	Matches []Message
+	Scanned int64
+}
+
+// FindMessagesByKey reads every partition of topic within the time bounds
+// and returns the records whose key equals key. The scan is bounded by the
+// end offsets at the start, so records produced meanwhile are not waited for.
+func (c *Client) FindMessagesByKey(ctx context.Context, topic, key string, opts KeyLookupOptions) (_ *KeyLookupResult, err error) {
+	ctx, span := startSpan(ctx, "FindMessagesByKey", attribute.String("topic", topic))
+	defer func() { endSpan(span, err) }()
+
+	if !opts.From.IsZero() && !opts.To.IsZero() && opts.To.Before(opts.From) {
+		return nil, fmt.Errorf("end time %s is before start time %s", opts.To.Format(time.RFC3339), opts.From.Format(time.RFC3339))
+	}
+
+	saramaClient, err := sarama.NewClient(c.brokers, c.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer: %w", err)
+	}
+	defer saramaClient.Close()
+
+	partitions, err := saramaClient.Partitions(topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partitions: %w", err)
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
+
+	consumer, err := sarama.NewConsumerFromClient(saramaClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer: %w", err)
+	}
+	defer consumer.Close()
+
+	result := &KeyLookupResult{}
+	progress := KeyLookupProgress{Partitions: len(partitions)}
+	for _, partition := range partitions {
+		start, end, err := keyLookupRange(saramaClient, topic, partition, opts)
+		if err != nil {
+			return nil, err
+		}
+		if start < end {
+			if err := scanPartitionForKey(ctx, consumer, topic, partition, start, end, key, opts, result); err != nil {
+				return nil, err
+			}
+		}
+		progress.Done++
+		progress.Scanned = result.Scanned
+		progress.Matches = len(result.Matches)
+		if opts.OnProgress != nil {
+			opts.OnProgress(progress)
+		}
+	}
+	return result, nil
+}
+
+// keyLookupRange resolves the time bounds of a lookup to offsets of one
+// partition. A bound past the last record resolves to the end offset.
+func keyLookupRange(client sarama.Client, topic string, partition int32, opts KeyLookupOptions) (int64, int64, error) {
+	newest, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get newest offset for partition %d: %w", partition, err)
+	}
+	offsetAt := func(t time.Time, open int64) (int64, error) {
+		if t.IsZero() {
+			return open, nil
+		}
+		offset, err := client.GetOffset(topic, partition, t.UnixMilli())
+		if err != nil {
+			return 0, fmt.Errorf("failed to get offset at %s for partition %d: %w", t.Format(time.RFC3339), partition, err)
+		}
+		if offset < 0 {
+			return newest, nil
+		}
+		return offset, nil
+	}
+
+	start, err := offsetAt(opts.From, sarama.OffsetOldest)
+	if err != nil {
+		return 0, 0, err
+	}
+	if start == sarama.OffsetOldest {
+		if start, err = client.GetOffset(topic, partition, sarama.OffsetOldest); err != nil {
+			return 0, 0, fmt.Errorf("failed to get oldest offset for partition %d: %w", partition, err)
+		}
+	}
+	// The offset for To is the first record at or after it, which is kept
+	// in range so a record stamped exactly To is read
+	end, err := offsetAt(opts.To, newest)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !opts.To.IsZero() && end < newest {
+		end++
+	}
+	return start, end, nil
+}
+
+// inRange reports whether t falls within the lookup's time bounds
+func (o KeyLookupOptions) inRange(t time.Time) bool {
+	return (o.From.IsZero() || !t.Before(o.From)) && (o.To.IsZero() || !t.After(o.To))
+}
+
+// scanPartitionForKey reads offsets [start, end) of a partition, adding the
+// records with a matching key and timestamp to result
+func scanPartitionForKey(ctx context.Context, consumer sarama.Consumer, topic string, partition int32, start, end int64, key string, opts KeyLookupOptions, result *KeyLookupResult) error {
+	pc, err := consumer.ConsumePartition(topic, partition, start)
+	if err != nil {
+		return fmt.Errorf("failed to consume partition %d: %w", partition, err)
+	}
+	defer pc.Close()
+
+	return readPartition(ctx, pc, true, func(msg *sarama.ConsumerMessage) bool {
+		result.Scanned++
+		if string(msg.Key) == key && opts.inRange(msg.Timestamp) {
+			result.Matches = append(result.Matches, newMessage(msg))
+		}
+		return msg.Offset+1 < end
+	}, func(err error) error {
+		return fmt.Errorf("failed to read partition %d: %w", partition, err)
+	})
+}
@@ -0,0 +1,81 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+func TestKeyLookupInRange(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	opts := KeyLookupOptions{From: from, To: to}
+
+	tests := []struct {
+		at   time.Time
+		want bool
+	}{
+		{from.Add(-time.Second), false},
+		{from, true},
+		{from.Add(30 * time.Minute), true},
+		{to, true},
+		{to.Add(time.Second), false},
+	}
+	for _, tt := range tests {
+		if got := opts.inRange(tt.at); got != tt.want {
+			t.Errorf("inRange(%s) = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+	if !(KeyLookupOptions{}).inRange(from) {
+		t.Errorf("unbounded lookup rejected a record")
+	}
+}
+
+func TestFindMessagesByKey(t *testing.T) {
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+
+	fetch := sarama.NewMockFetchResponse(t, 1).SetHighWaterMark("orders", 0, 4)
+	for offset, key := range []string{"a", "b", "a", "c"} {
+		fetch.SetMessageWithKey("orders", 0, int64(offset), sarama.StringEncoder(key), sarama.StringEncoder(key+"-value"))
+	}
+
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"ApiVersionsRequest": sarama.NewMockApiVersionsResponse(t),
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader("orders", 0, broker.BrokerID()),
+		"OffsetRequest": sarama.NewMockOffsetResponse(t).
+			SetOffset("orders", 0, sarama.OffsetOldest, 0).
+			SetOffset("orders", 0, sarama.OffsetNewest, 4),
+		"FetchRequest": fetch,
+	})
+
+	client := &Client{brokers: []string{broker.Addr()}, config: sarama.NewConfig()}
+	var progress []KeyLookupProgress
+	result, err := client.FindMessagesByKey(context.Background(), "orders", "a", KeyLookupOptions{
+		OnProgress: func(p KeyLookupProgress) { progress = append(progress, p) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Scanned != 4 {
+		t.Errorf("scanned %d records, want 4", result.Scanned)
+	}
+	if len(result.Matches) != 2 || result.Matches[0].Offset != 0 || result.Matches[1].Offset != 2 {
+		t.Fatalf("matches = %+v, want offsets 0 and 2", result.Matches)
+	}
+	if len(progress) != 1 || progress[0].Done != 1 || progress[0].Matches != 2 {
+		t.Errorf("progress = %+v", progress)
+	}
+}
+
+func TestFindMessagesByKeyRejectsInvertedRange(t *testing.T) {
+	now := time.Now()
+	client := &Client{config: sarama.NewConfig()}
+	if _, err := client.FindMessagesByKey(context.Background(), "orders", "a", KeyLookupOptions{From: now, To: now.Add(-time.Hour)}); err == nil {
+		t.Error("expected an error for an end time before the start time")
+	}
+}
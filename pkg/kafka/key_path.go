@@ -0,0 +1,106 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// KeyPath locates the message key inside a JSON value, written as
+// $.order.id, .order.id or order.id, with [n] for array elements
+type KeyPath struct {
+	raw   string
+	steps []any // string field names and int array indexes
+}
+
+// ParseKeyPath parses a key path such as $.order.id or $.items[0].sku
+func ParseKeyPath(path string) (KeyPath, error) {
+	raw := strings.TrimSpace(path)
+	rest := strings.TrimPrefix(strings.TrimPrefix(raw, "$"), ".")
+	if rest == "" {
+		return KeyPath{}, fmt.Errorf("invalid key path %q: it names no field", path)
+	}
+
+	p := KeyPath{raw: raw}
+	for _, segment := range strings.Split(rest, ".") {
+		name, indexes, _ := strings.Cut(segment, "[")
+		if name == "" && indexes == "" {
+			return KeyPath{}, fmt.Errorf("invalid key path %q: empty field name", path)
+		}
+		if name != "" {
+			p.steps = append(p.steps, name)
+		}
+		if indexes == "" {
+			continue
+		}
+		for _, index := range strings.Split(strings.TrimSuffix(indexes, "]"), "][") {
+			n, err := strconv.Atoi(index)
+			if err != nil || n < 0 || !strings.HasSuffix(segment, "]") {
+				return KeyPath{}, fmt.Errorf("invalid key path %q: bad array index in %q", path, segment)
+			}
+			p.steps = append(p.steps, n)
+		}
+	}
+	return p, nil
+}
+
+// String returns the path as it was given
+func (p KeyPath) String() string {
+	return p.raw
+}
+
+// IsZero reports whether p is unset
+func (p KeyPath) IsZero() bool {
+	return len(p.steps) == 0
+}
+
+// Extract returns the key found at p in a JSON value. Strings are used as
+// they are, other scalars in their JSON form, and objects and arrays as
+// compact JSON. A missing or null field is an error, as producing the
+// message without its key would send it to the wrong partition.
+func (p KeyPath) Extract(value string) (string, error) {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+	var node any
+	if err := decoder.Decode(&node); err != nil {
+		return "", fmt.Errorf("key path %s: value is not JSON: %w", p, err)
+	}
+
+	for _, step := range p.steps {
+		switch step := step.(type) {
+		case string:
+			object, ok := node.(map[string]any)
+			if !ok {
+				return "", fmt.Errorf("key path %s: %q is not in an object", p, step)
+			}
+			if node, ok = object[step]; !ok {
+				return "", fmt.Errorf("key path %s: no field %q", p, step)
+			}
+		case int:
+			array, ok := node.([]any)
+			if !ok || step >= len(array) {
+				return "", fmt.Errorf("key path %s: no array element %d", p, step)
+			}
+			node = array[step]
+		}
+	}
+
+	switch node := node.(type) {
+	case nil:
+		return "", fmt.Errorf("key path %s: the field is null", p)
+	case string:
+		return node, nil
+	case json.Number:
+		return node.String(), nil
+	default:
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(node); err != nil {
+			return "", fmt.Errorf("key path %s: %w", p, err)
+		}
+		return strings.TrimSuffix(buf.String(), "\n"), nil
+	}
+}
@@ -0,0 +1,55 @@
+package kafka
+
+import "testing"
+
+func TestKeyPathExtract(t *testing.T) {
+	value := `{"order":{"id":"o-1","total":12.50,"paid":true,"tags":["a","b"],"customer":{"id":7}},"items":[{"sku":"x"}],"note":null}`
+	tests := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{path: "$.order.id", want: "o-1"},
+		{path: ".order.id", want: "o-1"},
+		{path: "order.id", want: "o-1"},
+		{path: "$.order.total", want: "12.50"},
+		{path: "$.order.paid", want: "true"},
+		{path: "$.order.customer", want: `{"id":7}`},
+		{path: "$.order.tags[1]", want: "b"},
+		{path: "$.items[0].sku", want: "x"},
+		{path: "$.order.missing", wantErr: true},
+		{path: "$.note", wantErr: true},
+		{path: "$.items[3].sku", wantErr: true},
+		{path: "$.order.id.more", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			path, err := ParseKeyPath(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := path.Extract(value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Extract() error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Extract() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseKeyPathInvalid(t *testing.T) {
+	for _, path := range []string{"", "$", "$.", "$.a..b", "$.a[x]", "$.a[-1]", "$.a[0"} {
+		if _, err := ParseKeyPath(path); err == nil {
+			t.Errorf("ParseKeyPath(%q) succeeded", path)
+		}
+	}
+}
+
+func TestKeyPathExtractNotJSON(t *testing.T) {
+	path, _ := ParseKeyPath("$.id")
+	if _, err := path.Extract("plain text"); err == nil {
+		t.Error("Extract() of a non-JSON value succeeded")
+	}
+}
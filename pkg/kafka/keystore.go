@@ -0,0 +1,306 @@
+package kafka
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha1"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf16"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// Magic numbers at the start of Java keystores
+const (
+	jksMagic   = 0xFEEDFEED
+	jceksMagic = 0xCECECECE
+)
+
+// loadKeystore reads the client certificate and key from a PKCS12 or JKS
+// keystore, the formats Kafka's ssl.keystore.location takes. keyPassword
+// protects the key inside a JKS store and defaults to the store password.
+func loadKeystore(path, password, keyPassword string) (tls.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	if keyPassword == "" {
+		keyPassword = password
+	}
+
+	if isJavaKeystore(data) {
+		store, err := decodeJKS(data, password, keyPassword)
+		if err != nil {
+			return tls.Certificate{}, err
+		}
+		if len(store.keys) == 0 {
+			return tls.Certificate{}, fmt.Errorf("keystore %s holds no private key", path)
+		}
+		// Kafka clients use the first key too, unless told an alias
+		return store.keys[0], nil
+	}
+
+	key, cert, chain, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to read keystore %s: %w", path, err)
+	}
+	certificate := tls.Certificate{PrivateKey: key, Leaf: cert, Certificate: [][]byte{cert.Raw}}
+	for _, c := range chain {
+		certificate.Certificate = append(certificate.Certificate, c.Raw)
+	}
+	return certificate, nil
+}
+
+// loadTruststore reads the CA certificates from a PKCS12 or JKS truststore,
+// the formats Kafka's ssl.truststore.location takes
+func loadTruststore(path, password string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []*x509.Certificate
+	if isJavaKeystore(data) {
+		store, err := decodeJKS(data, password, "")
+		if err != nil {
+			return nil, err
+		}
+		certs = store.trusted
+	} else {
+		certs, err = pkcs12.DecodeTrustStore(data, password)
+		if err != nil {
+			// Stores written by openssl rather than keytool lack the Java
+			// trust attribute, but their certificates serve just as well
+			_, cert, chain, chainErr := pkcs12.DecodeChain(data, password)
+			if chainErr != nil {
+				return nil, fmt.Errorf("failed to read truststore %s: %w", path, err)
+			}
+			certs = append(chain, cert)
+		}
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("truststore %s holds no certificates", path)
+	}
+
+	pool := x509.NewCertPool()
+	for _, cert := range certs {
+		pool.AddCert(cert)
+	}
+	return pool, nil
+}
+
+func isJavaKeystore(data []byte) bool {
+	if len(data) < 4 {
+		return false
+	}
+	magic := binary.BigEndian.Uint32(data)
+	return magic == jksMagic || magic == jceksMagic
+}
+
+// jksStore is what kconduit needs from a JKS keystore
+type jksStore struct {
+	keys    []tls.Certificate
+	trusted []*x509.Certificate
+}
+
+// jksReader reads the big-endian fields of a JKS file
+type jksReader struct {
+	r   *bytes.Reader
+	err error
+}
+
+func (r *jksReader) uint16() uint16 {
+	var v uint16
+	if r.err == nil {
+		r.err = binary.Read(r.r, binary.BigEndian, &v)
+	}
+	return v
+}
+
+func (r *jksReader) uint32() uint32 {
+	var v uint32
+	if r.err == nil {
+		r.err = binary.Read(r.r, binary.BigEndian, &v)
+	}
+	return v
+}
+
+func (r *jksReader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n > r.r.Len() {
+		r.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	b := make([]byte, n)
+	_, r.err = io.ReadFull(r.r, b)
+	return b
+}
+
+// count reads the number of entries that follow, each at least size bytes
+// long, failing when the rest of the input cannot hold that many so a
+// corrupted count cannot force a huge allocation
+func (r *jksReader) count(size int) int {
+	n := r.uint32()
+	if r.err == nil && uint64(n)*uint64(size) > uint64(r.r.Len()) {
+		r.err = io.ErrUnexpectedEOF
+	}
+	if r.err != nil {
+		return 0
+	}
+	return int(n)
+}
+
+func (r *jksReader) utf() string {
+	return string(r.bytes(int(r.uint16())))
+}
+
+// decodeJKS parses a JKS keystore, checking its integrity with password and
+// decrypting its keys with keyPassword. Keys are skipped when keyPassword is
+// empty, as for a truststore, and like Java an empty password skips the
+// integrity check.
+func decodeJKS(data []byte, password, keyPassword string) (*jksStore, error) {
+	if binary.BigEndian.Uint32(data) == jceksMagic {
+		return nil, errors.New("JCEKS keystores are not supported; convert it with \"keytool -importkeystore -deststoretype pkcs12\"")
+	}
+	if len(data) < 12+sha1.Size {
+		return nil, errors.New("keystore is truncated")
+	}
+
+	body, digest := data[:len(data)-sha1.Size], data[len(data)-sha1.Size:]
+	if password != "" && !bytes.Equal(jksDigest(body, password), digest) {
+		return nil, errors.New("keystore password is incorrect or the keystore is corrupted")
+	}
+
+	r := &jksReader{r: bytes.NewReader(body[4:])}
+	version := r.uint32()
+	if r.err == nil && version != 1 && version != 2 {
+		return nil, fmt.Errorf("unsupported JKS version %d", version)
+	}
+	// A certificate is at least its length, after its type in version 2
+	certSize := 4
+	if version == 2 {
+		certSize += 2
+	}
+	readCert := func() []byte {
+		if version == 2 {
+			_ = r.utf() // Certificate type, always X.509
+		}
+		return r.bytes(int(r.uint32()))
+	}
+
+	store := &jksStore{}
+	count := r.uint32()
+	for i := uint32(0); i < count && r.err == nil; i++ {
+		tag := r.uint32()
+		alias := r.utf()
+		r.bytes(8) // Creation time
+		switch tag {
+		case 1: // Private key with its certificate chain
+			encrypted := r.bytes(int(r.uint32()))
+			chain := make([][]byte, r.count(certSize))
+			for j := range chain {
+				chain[j] = readCert()
+			}
+			if r.err != nil || keyPassword == "" {
+				continue
+			}
+			key, err := decryptJKSKey(encrypted, keyPassword)
+			if err != nil {
+				return nil, fmt.Errorf("key %q: %w", alias, err)
+			}
+			store.keys = append(store.keys, tls.Certificate{PrivateKey: key, Certificate: chain})
+		case 2: // Trusted certificate
+			raw := readCert()
+			if r.err != nil {
+				break
+			}
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return nil, fmt.Errorf("certificate %q: %w", alias, err)
+			}
+			store.trusted = append(store.trusted, cert)
+		default:
+			return nil, fmt.Errorf("unsupported keystore entry type %d", tag)
+		}
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("keystore is corrupted: %w", r.err)
+	}
+	return store, nil
+}
+
+// jksDigest is the integrity check that ends a JKS file
+func jksDigest(body []byte, password string) []byte {
+	h := sha1.New()
+	h.Write(javaPassword(password))
+	h.Write([]byte("Mighty Aphrodite"))
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// jksKeyProtector identifies the proprietary cipher JKS protects keys with
+var jksKeyProtector = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 42, 2, 17, 1, 1}
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	Data      []byte
+}
+
+// decryptJKSKey reverses the JKS key protector: the key is XORed with a
+// stream of chained SHA-1 digests seeded by a salt, followed by a digest of
+// the password and plain key to check the result
+func decryptJKSKey(der []byte, password string) (crypto.PrivateKey, error) {
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, err
+	}
+	if !info.Algorithm.Algorithm.Equal(jksKeyProtector) {
+		return nil, fmt.Errorf("unsupported key protection %s", info.Algorithm.Algorithm)
+	}
+	data := info.Data
+	if len(data) < 2*sha1.Size {
+		return nil, errors.New("protected key is truncated")
+	}
+
+	salt := data[:sha1.Size]
+	encrypted := data[sha1.Size : len(data)-sha1.Size]
+	check := data[len(data)-sha1.Size:]
+	pw := javaPassword(password)
+
+	plain := make([]byte, len(encrypted))
+	digest := salt
+	for i := 0; i < len(encrypted); i += sha1.Size {
+		sum := sha1.Sum(append(append([]byte{}, pw...), digest...))
+		digest = sum[:]
+		for j := 0; j < sha1.Size && i+j < len(encrypted); j++ {
+			plain[i+j] = encrypted[i+j] ^ digest[j]
+		}
+	}
+
+	sum := sha1.Sum(append(append([]byte{}, pw...), plain...))
+	if !bytes.Equal(sum[:], check) {
+		return nil, errors.New("key password is incorrect")
+	}
+	return x509.ParsePKCS8PrivateKey(plain)
+}
+
+// javaPassword encodes a password the way Java keystores hash it, as
+// big-endian UTF-16
+func javaPassword(password string) []byte {
+	units := utf16.Encode([]rune(password))
+	b := make([]byte, 2*len(units))
+	for i, u := range units {
+		binary.BigEndian.PutUint16(b[2*i:], u)
+	}
+	return b
+}
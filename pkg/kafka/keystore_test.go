@@ -0,0 +1,175 @@
+package kafka
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func testCertificate(t *testing.T) (*ecdsa.PrivateKey, *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "kconduit"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, cert
+}
+
+// encodeJKS writes a version 2 JKS store with one key entry and one trusted
+// certificate, the inverse of decodeJKS
+func encodeJKS(t *testing.T, key *ecdsa.PrivateKey, cert *x509.Certificate, password string) []byte {
+	t.Helper()
+	plain, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pw := javaPassword(password)
+	salt := bytes.Repeat([]byte{7}, sha1.Size)
+	encrypted := make([]byte, len(plain))
+	digest := salt
+	for i := 0; i < len(plain); i += sha1.Size {
+		sum := sha1.Sum(append(append([]byte{}, pw...), digest...))
+		digest = sum[:]
+		for j := 0; j < sha1.Size && i+j < len(plain); j++ {
+			encrypted[i+j] = plain[i+j] ^ digest[j]
+		}
+	}
+	check := sha1.Sum(append(append([]byte{}, pw...), plain...))
+	protected, err := asn1.Marshal(encryptedPrivateKeyInfo{
+		Algorithm: pkix.AlgorithmIdentifier{Algorithm: jksKeyProtector, Parameters: asn1.NullRawValue},
+		Data:      append(append(salt, encrypted...), check[:]...),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	put := func(v any) { _ = binary.Write(&b, binary.BigEndian, v) }
+	utf := func(s string) { put(uint16(len(s))); b.WriteString(s) }
+	put(uint32(jksMagic))
+	put(uint32(2))
+	put(uint32(2))
+
+	put(uint32(1))
+	utf("client")
+	put(uint64(0))
+	put(uint32(len(protected)))
+	b.Write(protected)
+	put(uint32(1))
+	utf("X.509")
+	put(uint32(len(cert.Raw)))
+	b.Write(cert.Raw)
+
+	put(uint32(2))
+	utf("ca")
+	put(uint64(0))
+	utf("X.509")
+	put(uint32(len(cert.Raw)))
+	b.Write(cert.Raw)
+
+	b.Write(jksDigest(b.Bytes(), password))
+	return b.Bytes()
+}
+
+func TestLoadKeystore(t *testing.T) {
+	key, cert := testCertificate(t)
+	dir := t.TempDir()
+
+	p12, err := pkcs12.Modern.Encode(key, cert, nil, "changeit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trust, err := pkcs12.Modern.EncodeTrustStore([]*x509.Certificate{cert}, "changeit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{
+		"client.p12": p12,
+		"trust.p12":  trust,
+		"client.jks": encodeJKS(t, key, cert, "changeit"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{"client.p12", "client.jks"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			got, err := loadKeystore(path, "changeit", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got.Certificate[0], cert.Raw) {
+				t.Error("wrong certificate")
+			}
+			if k, ok := got.PrivateKey.(*ecdsa.PrivateKey); !ok || !k.Equal(key) {
+				t.Error("wrong private key")
+			}
+			if _, err := loadKeystore(path, "wrong", ""); err == nil {
+				t.Error("a wrong password was accepted")
+			}
+		})
+	}
+
+	for _, name := range []string{"trust.p12", "client.p12", "client.jks"} {
+		t.Run("truststore "+name, func(t *testing.T) {
+			pool, err := loadTruststore(filepath.Join(dir, name), "changeit")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := cert.Verify(x509.VerifyOptions{Roots: pool}); err != nil {
+				t.Errorf("certificate not trusted: %v", err)
+			}
+		})
+	}
+}
+
+func TestDecodeJKSCorruptChainCount(t *testing.T) {
+	var b bytes.Buffer
+	put := func(v any) { _ = binary.Write(&b, binary.BigEndian, v) }
+	put(uint32(jksMagic))
+	put(uint32(2))
+	put(uint32(1))
+	put(uint32(1))
+	put(uint16(6))
+	b.WriteString("client")
+	put(uint64(0))
+	put(uint32(0))
+	// Far more certificates than the rest of the file could hold
+	put(uint32(0xffffffff))
+	b.Write(jksDigest(b.Bytes(), "changeit"))
+
+	if _, err := decodeJKS(b.Bytes(), "changeit", "changeit"); err == nil || !strings.Contains(err.Error(), "corrupted") {
+		t.Errorf("err = %v, want the keystore reported as corrupted", err)
+	}
+}
@@ -0,0 +1,114 @@
+package kafka
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// LagReportRow is one partition of a group in a lag report. Committed, End
+// and Lag are nil when unknown, i.e. empty in CSV and null in JSON.
+type LagReportRow struct {
+	Group     string `json:"group" yaml:"group"`
+	Topic     string `json:"topic" yaml:"topic"`
+	Partition int32  `json:"partition" yaml:"partition"`
+	Committed *int64 `json:"committed" yaml:"committed"`
+	End       *int64 `json:"end" yaml:"end"`
+	Lag       *int64 `json:"lag" yaml:"lag"`
+}
+
+// LagReport is the lag of every partition of the reported groups
+type LagReport struct {
+	GeneratedAt time.Time      `json:"generatedAt" yaml:"generatedAt"`
+	Rows        []LagReportRow `json:"rows" yaml:"rows"`
+}
+
+// lagReportColumns is the CSV header of a lag report
+var lagReportColumns = []string{"group", "topic", "partition", "committed", "end", "lag"}
+
+// GetLagReport measures the lag of groups, or of every consumer group when
+// groups is empty, sorted by group, topic and partition
+func (c *Client) GetLagReport(groups []string) (_ *LagReport, err error) {
+	_, span := startSpan(context.Background(), "GetLagReport", attribute.Int("groups", len(groups)))
+	defer func() { endSpan(span, err) }()
+
+	if len(groups) == 0 {
+		listed, err := c.admin.ListConsumerGroups()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list consumer groups: %w", err)
+		}
+		for groupID := range listed {
+			groups = append(groups, groupID)
+		}
+	}
+	groups = append([]string(nil), groups...)
+	sort.Strings(groups)
+
+	report := &LagReport{GeneratedAt: time.Now()}
+	for _, groupID := range groups {
+		partitions, err := c.GetGroupLag(groupID)
+		if err != nil {
+			return nil, err
+		}
+		report.Rows = append(report.Rows, lagReportRows(groupID, partitions)...)
+	}
+	return report, nil
+}
+
+// lagReportRows turns a group's partition lags into report rows
+func lagReportRows(groupID string, partitions []PartitionLag) []LagReportRow {
+	known := func(v int64) *int64 {
+		if v < 0 {
+			return nil
+		}
+		return &v
+	}
+	rows := make([]LagReportRow, len(partitions))
+	for i, p := range partitions {
+		rows[i] = LagReportRow{
+			Group:     groupID,
+			Topic:     p.Topic,
+			Partition: p.Partition,
+			Committed: known(p.Committed),
+			End:       known(p.LogEnd),
+			Lag:       known(p.Lag()),
+		}
+	}
+	return rows
+}
+
+// WriteCSV writes the report as CSV with a header row
+func (r *LagReport) WriteCSV(w io.Writer) error {
+	cell := func(v *int64) string {
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatInt(*v, 10)
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(lagReportColumns); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	for _, row := range r.Rows {
+		record := []string{row.Group, row.Topic, strconv.Itoa(int(row.Partition)), cell(row.Committed), cell(row.End), cell(row.Lag)}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
+
+// Marshal encodes the report as "yaml" or "json"
+func (r *LagReport) Marshal(format string) ([]byte, error) {
+	return encodeFile(r, format)
+}
@@ -0,0 +1,42 @@
+package kafka
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLagReportCSV(t *testing.T) {
+	report := &LagReport{Rows: lagReportRows("orders-service", []PartitionLag{
+		{Topic: "orders", Partition: 0, Committed: 90, LogEnd: 100},
+		{Topic: "orders", Partition: 1, Committed: -1, LogEnd: 40},
+		{Topic: "orders", Partition: 2, Committed: 5, LogEnd: -1},
+	})}
+
+	var sb strings.Builder
+	if err := report.WriteCSV(&sb); err != nil {
+		t.Fatal(err)
+	}
+	want := `group,topic,partition,committed,end,lag
+orders-service,orders,0,90,100,10
+orders-service,orders,1,,40,
+orders-service,orders,2,5,,
+`
+	if sb.String() != want {
+		t.Errorf("WriteCSV() =\n%s\nwant\n%s", sb.String(), want)
+	}
+}
+
+func TestLagReportJSON(t *testing.T) {
+	report := &LagReport{Rows: lagReportRows("g", []PartitionLag{
+		{Topic: "t", Partition: 3, Committed: -1, LogEnd: 40},
+	})}
+	data, err := report.Marshal("json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"group": "g"`, `"partition": 3`, `"committed": null`, `"end": 40`, `"lag": null`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("json output missing %s:\n%s", want, data)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ProbeHeader marks latency probe messages, so consumers of the probe topic
+// can tell them from real traffic
+const ProbeHeader = "kconduit-probe"
+
+// LatencyOptions controls a latency probe
+type LatencyOptions struct {
+	Count    int           // Probes to send, default 10
+	Interval time.Duration // Pause between probes, default 200ms
+	Timeout  time.Duration // How long to wait for the last probe to come back, default 10s
+
+	// OnSample, when set, is called as each probe comes back
+	OnSample func(seq int, sample LatencySample)
+}
+
+// LatencySample is the timing of one probe
+type LatencySample struct {
+	Produce   time.Duration // Until the broker acknowledged the write
+	RoundTrip time.Duration // Until the probe was consumed back
+}
+
+// LatencyPercentiles summarizes a set of durations
+type LatencyPercentiles struct {
+	P50, P90, P99, Max time.Duration
+}
+
+// LatencyResult is the outcome of a latency probe
+type LatencyResult struct {
+	Sent      int
+	Received  int
+	Produce   LatencyPercentiles
+	RoundTrip LatencyPercentiles
+}
+
+// ProbeLatency produces timestamped probe messages to topic while consuming
+// it, and measures how long each takes to be acknowledged and to come back.
+// The topic should be one set aside for probes, since the messages stay in it.
+func (c *Client) ProbeLatency(ctx context.Context, topic string, opts LatencyOptions) (_ *LatencyResult, err error) {
+	ctx, span := startSpan(ctx, "ProbeLatency", attribute.String("topic", topic))
+	defer func() { endSpan(span, err) }()
+
+	if opts.Count <= 0 {
+		opts.Count = 10
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 200 * time.Millisecond
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+
+	saramaClient, err := sarama.NewClient(c.brokers, c.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer: %w", err)
+	}
+	defer saramaClient.Close()
+
+	// Start from the current end of each partition, found before the first
+	// probe is sent so that none can slip past the consumer
+	partitions, err := saramaClient.Partitions(topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partitions: %w", err)
+	}
+	startOffsets := make(map[int32]int64, len(partitions))
+	for _, partition := range partitions {
+		newest, err := saramaClient.GetOffset(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get newest offset for partition %d: %w", partition, err)
+		}
+		startOffsets[partition] = newest
+	}
+	consumer, err := sarama.NewConsumerFromClient(saramaClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer: %w", err)
+	}
+
+	consumeCtx, stopConsuming := context.WithCancel(ctx)
+	messages := make(chan Message, opts.Count)
+	var consumeErr error
+	consumed := make(chan struct{})
+	go func() {
+		consumeErr = c.consumePartitions(consumeCtx, consumer, topic, startOffsets, messages)
+		close(consumed)
+	}()
+	// The consumer is closed before the client it was made from
+	defer func() {
+		stopConsuming()
+		<-consumed
+	}()
+
+	// Probes are told apart from other runs' by a per-run prefix
+	run := strconv.FormatInt(time.Now().UnixNano(), 36) + "-"
+	var (
+		mu         sync.Mutex
+		sentAt     = make(map[string]time.Time)
+		samples    = make(map[string]*LatencySample)
+		produced   []time.Duration
+		roundTrips []time.Duration
+	)
+	allBack := make(chan struct{})
+	// Whichever of the ack and the consumed probe comes second reports it
+	report := func(id string, sample LatencySample) {
+		if opts.OnSample != nil && sample.Produce > 0 && sample.RoundTrip > 0 {
+			seq, _ := strconv.Atoi(id[len(run):])
+			opts.OnSample(seq, sample)
+		}
+	}
+
+	go func() {
+		for {
+			select {
+			case <-consumeCtx.Done():
+				return
+			case msg := <-messages:
+				received := time.Now()
+				id := msg.Headers[ProbeHeader]
+				mu.Lock()
+				start, ok := sentAt[id]
+				if !ok {
+					mu.Unlock()
+					continue
+				}
+				delete(sentAt, id)
+				sample := samples[id]
+				sample.RoundTrip = received.Sub(start)
+				roundTrips = append(roundTrips, sample.RoundTrip)
+				done := len(roundTrips) == opts.Count
+				s := *sample
+				mu.Unlock()
+
+				report(id, s)
+				if done {
+					close(allBack)
+					return
+				}
+			}
+		}
+	}()
+
+	result := &LatencyResult{}
+	for seq := 1; seq <= opts.Count; seq++ {
+		if seq > 1 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(opts.Interval):
+			}
+		}
+
+		id := run + strconv.Itoa(seq)
+		start := time.Now()
+		mu.Lock()
+		sentAt[id] = start
+		samples[id] = &LatencySample{}
+		mu.Unlock()
+
+		value := fmt.Sprintf(`{"probe":%d,"sent":%q}`, seq, start.UTC().Format(time.RFC3339Nano))
+		if _, _, err := c.ProduceMessage(topic, "", value, ProduceOptions{Headers: map[string]string{ProbeHeader: id}}); err != nil {
+			return nil, err
+		}
+		acked := time.Since(start)
+		mu.Lock()
+		samples[id].Produce = acked
+		produced = append(produced, acked)
+		s := *samples[id]
+		mu.Unlock()
+		report(id, s)
+		result.Sent++
+	}
+
+	select {
+	case <-allBack:
+	case <-time.After(opts.Timeout):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-consumed:
+		return nil, consumeErr
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	result.Received = len(roundTrips)
+	result.Produce = latencyPercentiles(produced)
+	result.RoundTrip = latencyPercentiles(roundTrips)
+	return result, nil
+}
+
+// latencyPercentiles uses the nearest-rank method, so every value reported
+// was actually observed
+func latencyPercentiles(durations []time.Duration) LatencyPercentiles {
+	if len(durations) == 0 {
+		return LatencyPercentiles{}
+	}
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	rank := func(p int) time.Duration {
+		i := (p*len(sorted)+99)/100 - 1
+		return sorted[max(i, 0)]
+	}
+	return LatencyPercentiles{P50: rank(50), P90: rank(90), P99: rank(99), Max: sorted[len(sorted)-1]}
+}
@@ -0,0 +1,36 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatencyPercentiles(t *testing.T) {
+	ms := func(values ...int) []time.Duration {
+		var out []time.Duration
+		for _, v := range values {
+			out = append(out, time.Duration(v)*time.Millisecond)
+		}
+		return out
+	}
+	hundred := make([]int, 100)
+	for i := range hundred {
+		hundred[i] = 100 - i
+	}
+
+	tests := []struct {
+		name string
+		in   []time.Duration
+		want LatencyPercentiles
+	}{
+		{"empty", nil, LatencyPercentiles{}},
+		{"one", ms(7), LatencyPercentiles{P50: 7 * time.Millisecond, P90: 7 * time.Millisecond, P99: 7 * time.Millisecond, Max: 7 * time.Millisecond}},
+		{"unsorted", ms(40, 10, 30, 20), LatencyPercentiles{P50: 20 * time.Millisecond, P90: 40 * time.Millisecond, P99: 40 * time.Millisecond, Max: 40 * time.Millisecond}},
+		{"hundred", ms(hundred...), LatencyPercentiles{P50: 50 * time.Millisecond, P90: 90 * time.Millisecond, P99: 99 * time.Millisecond, Max: 100 * time.Millisecond}},
+	}
+	for _, tt := range tests {
+		if got := latencyPercentiles(tt.in); got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
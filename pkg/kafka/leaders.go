@@ -0,0 +1,230 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/IBM/sarama"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// leaderSkewTolerance is how far above its fair share of leaders a
+	// broker may go before it is flagged
+	leaderSkewTolerance = 0.2
+	// leaderImbalanceThreshold mirrors Kafka's
+	// leader.imbalance.per.broker.percentage default: the share of a
+	// broker's preferred partitions it may have lost leadership of
+	leaderImbalanceThreshold = 0.1
+)
+
+// BrokerLeadership counts the partitions a broker leads
+type BrokerLeadership struct {
+	BrokerID  int32
+	Rack      string
+	Leaders   int // Partitions the broker leads
+	Preferred int // Partitions whose preferred (first) replica is the broker
+	Lost      int // Preferred partitions currently led by another broker
+	Replicas  int
+	Share     float64 // Fraction of all leaders
+	Skewed    bool    // Leads well over its fair share
+	Imbalance float64 // Lost / Preferred
+	// Imbalanced is set when Imbalance exceeds what Kafka's automatic
+	// rebalancing tolerates by default
+	Imbalanced bool
+}
+
+// RackLeadership sums the leadership of a rack's brokers
+type RackLeadership struct {
+	Rack    string
+	Brokers int
+	Leaders int
+	Share   float64
+	Skewed  bool
+}
+
+// LeaderBalance describes how partition leadership is spread over the cluster
+type LeaderBalance struct {
+	Brokers    []BrokerLeadership
+	Racks      []RackLeadership // Empty when no broker has a rack
+	Partitions int
+	// NotPreferred lists the partitions led by a replica other than the
+	// preferred one, which a preferred leader election would move back
+	NotPreferred map[string][]int32
+}
+
+// Imbalanced reports whether any broker or rack is flagged
+func (b *LeaderBalance) Imbalanced() bool {
+	for _, broker := range b.Brokers {
+		if broker.Skewed || broker.Imbalanced {
+			return true
+		}
+	}
+	for _, rack := range b.Racks {
+		if rack.Skewed {
+			return true
+		}
+	}
+	return false
+}
+
+// GetLeaderBalance returns the leader distribution per broker and rack
+func (c *Client) GetLeaderBalance() (_ *LeaderBalance, err error) {
+	_, span := startSpan(context.Background(), "GetLeaderBalance")
+	defer func() { endSpan(span, err) }()
+
+	metadata, err := c.controllerMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	racks := make(map[int32]string, len(metadata.Brokers))
+	for _, b := range metadata.Brokers {
+		racks[b.ID()] = b.Rack()
+	}
+	return computeLeaderBalance(racks, metadata.Topics), nil
+}
+
+// controllerMetadata fetches the metadata of every broker and topic from the
+// controller, which has the freshest view of replica assignments
+func (c *Client) controllerMetadata() (*sarama.MetadataResponse, error) {
+	controller, err := c.admin.Controller()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get controller: %w", err)
+	}
+	defer func() {
+		if err := controller.Close(); err != nil {
+			logger.Get().WithError(err).Warn("Failed to close controller connection")
+		}
+	}()
+
+	metadata, err := controller.GetMetadata(&sarama.MetadataRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata: %w", err)
+	}
+	return metadata, nil
+}
+
+func computeLeaderBalance(racks map[int32]string, topics []*sarama.TopicMetadata) *LeaderBalance {
+	balance := &LeaderBalance{NotPreferred: make(map[string][]int32)}
+	brokers := make(map[int32]*BrokerLeadership, len(racks))
+	broker := func(id int32) *BrokerLeadership {
+		if b, ok := brokers[id]; ok {
+			return b
+		}
+		b := &BrokerLeadership{BrokerID: id, Rack: racks[id]}
+		brokers[id] = b
+		return b
+	}
+	for id := range racks {
+		broker(id)
+	}
+
+	leaders := 0
+	for _, topic := range topics {
+		for _, p := range topic.Partitions {
+			balance.Partitions++
+			for _, r := range p.Replicas {
+				broker(r).Replicas++
+			}
+			if p.Leader >= 0 {
+				broker(p.Leader).Leaders++
+				leaders++
+			}
+			if len(p.Replicas) == 0 {
+				continue
+			}
+			preferred := broker(p.Replicas[0])
+			preferred.Preferred++
+			if p.Leader != p.Replicas[0] {
+				preferred.Lost++
+				balance.NotPreferred[topic.Name] = append(balance.NotPreferred[topic.Name], p.ID)
+			}
+		}
+	}
+
+	fair := 0.0
+	if len(brokers) > 0 {
+		fair = 1 / float64(len(brokers))
+	}
+	rackTotals := make(map[string]*RackLeadership)
+	for _, b := range brokers {
+		if leaders > 0 {
+			b.Share = float64(b.Leaders) / float64(leaders)
+		}
+		if b.Preferred > 0 {
+			b.Imbalance = float64(b.Lost) / float64(b.Preferred)
+		}
+		b.Imbalanced = b.Imbalance > leaderImbalanceThreshold
+		// A couple of partitions over is noise on small clusters
+		b.Skewed = b.Share > fair*(1+leaderSkewTolerance) && float64(b.Leaders)-fair*float64(leaders) >= 2
+		balance.Brokers = append(balance.Brokers, *b)
+
+		if b.Rack != "" {
+			r := rackTotals[b.Rack]
+			if r == nil {
+				r = &RackLeadership{Rack: b.Rack}
+				rackTotals[b.Rack] = r
+			}
+			r.Brokers++
+			r.Leaders += b.Leaders
+		}
+	}
+	sort.Slice(balance.Brokers, func(i, j int) bool { return balance.Brokers[i].BrokerID < balance.Brokers[j].BrokerID })
+
+	for _, r := range rackTotals {
+		if leaders > 0 {
+			r.Share = float64(r.Leaders) / float64(leaders)
+		}
+		// Racks are judged against their share of the brokers
+		rackFair := float64(r.Brokers) / float64(len(brokers))
+		r.Skewed = r.Share > rackFair*(1+leaderSkewTolerance) && float64(r.Leaders)-rackFair*float64(leaders) >= 2
+		balance.Racks = append(balance.Racks, *r)
+	}
+	sort.Slice(balance.Racks, func(i, j int) bool { return balance.Racks[i].Rack < balance.Racks[j].Rack })
+
+	for topic := range balance.NotPreferred {
+		sort.Slice(balance.NotPreferred[topic], func(i, j int) bool {
+			return balance.NotPreferred[topic][i] < balance.NotPreferred[topic][j]
+		})
+	}
+	return balance
+}
+
+// ElectPreferredLeaders moves leadership of the given partitions back to
+// their preferred replicas and returns how many elections succeeded
+func (c *Client) ElectPreferredLeaders(partitions map[string][]int32) (_ int, err error) {
+	_, span := startSpan(context.Background(), "ElectLeaders", attribute.Int("topics", len(partitions)))
+	defer func() { endSpan(span, err) }()
+
+	if len(partitions) == 0 {
+		return 0, nil
+	}
+
+	results, err := c.admin.ElectLeaders(sarama.PreferredElection, partitions)
+	c.audit("partitions.elect_leaders", "cluster", nil, partitions, err)
+	if err != nil {
+		return 0, fmt.Errorf("failed to elect preferred leaders: %w", err)
+	}
+
+	elected := 0
+	var failed []string
+	for topic, byPartition := range results {
+		for partition, result := range byPartition {
+			// Already led by the preferred replica is not a failure
+			if result.ErrorCode == sarama.ErrNoError || result.ErrorCode == sarama.ErrElectionNotNeeded {
+				elected++
+				continue
+			}
+			failed = append(failed, fmt.Sprintf("%s-%d: %v", topic, partition, result.ErrorCode))
+		}
+	}
+	logger.Get().WithField("elected", elected).WithField("failed", len(failed)).Info("Ran preferred leader election")
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return elected, fmt.Errorf("election failed for %d partitions: %v", len(failed), failed)
+	}
+	return elected, nil
+}
@@ -0,0 +1,46 @@
+package kafka
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/IBM/sarama"
+)
+
+func TestComputeLeaderBalance(t *testing.T) {
+	racks := map[int32]string{1: "a", 2: "a", 3: "b"}
+	// Broker 1 leads 6 of 9 partitions, two of them preferred on broker 3
+	var partitions []*sarama.PartitionMetadata
+	for i, p := range []struct {
+		leader   int32
+		replicas []int32
+	}{
+		{1, []int32{1, 2, 3}}, {1, []int32{1, 3, 2}}, {1, []int32{1, 2, 3}}, {1, []int32{1, 3, 2}},
+		{1, []int32{3, 1, 2}}, {1, []int32{3, 2, 1}},
+		{2, []int32{2, 3, 1}}, {2, []int32{2, 1, 3}},
+		{3, []int32{3, 1, 2}},
+	} {
+		partitions = append(partitions, &sarama.PartitionMetadata{ID: int32(i), Leader: p.leader, Replicas: p.replicas})
+	}
+	balance := computeLeaderBalance(racks, []*sarama.TopicMetadata{{Name: "orders", Partitions: partitions}})
+
+	if balance.Partitions != 9 {
+		t.Errorf("partitions = %d, want 9", balance.Partitions)
+	}
+	b1, b3 := balance.Brokers[0], balance.Brokers[2]
+	if b1.Leaders != 6 || !b1.Skewed {
+		t.Errorf("broker 1 = %+v, want 6 leaders and skewed", b1)
+	}
+	if b3.Preferred != 3 || b3.Lost != 2 || b3.Skewed {
+		t.Errorf("broker 3 = %+v, want 2 of 3 preferred partitions lost", b3)
+	}
+	if !reflect.DeepEqual(balance.NotPreferred, map[string][]int32{"orders": {4, 5}}) {
+		t.Errorf("not preferred = %v", balance.NotPreferred)
+	}
+	if len(balance.Racks) != 2 || balance.Racks[0].Leaders != 8 || !balance.Racks[0].Skewed {
+		t.Errorf("racks = %+v, want rack a skewed with 8 leaders", balance.Racks)
+	}
+	if !balance.Imbalanced() {
+		t.Errorf("expected the cluster to be reported as imbalanced")
+	}
+}
@@ -0,0 +1,301 @@
+package kafka
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+)
+
+const (
+	// mirrorHeartbeatSample is how many of the newest records of each
+	// heartbeats partition are read to find the flows
+	mirrorHeartbeatSample = 500
+)
+
+// MirrorTopicRole is what MirrorMaker 2 uses one of its internal topics for
+type MirrorTopicRole string
+
+const (
+	MirrorHeartbeats  MirrorTopicRole = "heartbeats"
+	MirrorCheckpoints MirrorTopicRole = "checkpoints"
+	MirrorOffsetSyncs MirrorTopicRole = "offset syncs"
+	MirrorConnect     MirrorTopicRole = "connect state"
+)
+
+// MirrorTopicRoleOf recognizes the topics MirrorMaker 2 creates with its
+// default replication policy, returning an empty role for any other topic
+func MirrorTopicRoleOf(topic string) MirrorTopicRole {
+	switch {
+	case topic == "heartbeats" || strings.HasSuffix(topic, ".heartbeats"):
+		return MirrorHeartbeats
+	case strings.HasSuffix(topic, ".checkpoints.internal"):
+		return MirrorCheckpoints
+	case strings.HasPrefix(topic, "mm2-offset-syncs.") && strings.HasSuffix(topic, ".internal"):
+		return MirrorOffsetSyncs
+	case strings.HasSuffix(topic, ".internal") &&
+		(strings.HasPrefix(topic, "mm2-configs.") || strings.HasPrefix(topic, "mm2-offsets.") || strings.HasPrefix(topic, "mm2-status.")):
+		return MirrorConnect
+	}
+	return ""
+}
+
+// MirrorTopic is an MM2 internal topic found on the cluster
+type MirrorTopic struct {
+	Name string
+	Role MirrorTopicRole
+}
+
+// MirrorFlow is a replication flow seen through its heartbeats. Heartbeats
+// are written to the target's heartbeats topic and replicated further
+// downstream as <alias>.heartbeats, so the age of the newest one tells
+// whether the flow is alive as far as this cluster.
+type MirrorFlow struct {
+	Source        string
+	Target        string
+	Topic         string // Heartbeats topic the flow was seen in
+	LastHeartbeat time.Time
+	RemoteTopics  int // Topics on this cluster named after the source alias
+}
+
+// MirrorCheckpoint is a consumer group offset MM2 translated from the source
+// cluster to this one
+type MirrorCheckpoint struct {
+	Source           string // Alias of the cluster the group consumes on
+	Group            string
+	Topic            string
+	Partition        int32
+	UpstreamOffset   int64
+	DownstreamOffset int64
+	At               time.Time
+}
+
+// MirrorStatus is what kconduit can tell about MirrorMaker 2 from one
+// cluster's internal topics
+type MirrorStatus struct {
+	Topics      []MirrorTopic
+	Flows       []MirrorFlow
+	Checkpoints []MirrorCheckpoint
+}
+
+// GetMirrorStatus finds the MM2 internal topics and reads the replication
+// flows from the heartbeats and the translated group offsets from the
+// checkpoints
+func (c *Client) GetMirrorStatus() (_ *MirrorStatus, err error) {
+	_, span := startSpan(context.Background(), "GetMirrorStatus")
+	defer func() { endSpan(span, err) }()
+
+	client, err := sarama.NewClient(c.brokers, c.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	defer func() {
+		if closeErr := client.Close(); closeErr != nil {
+			logger.Get().WithError(closeErr).Warn("Failed to close MirrorMaker client")
+		}
+	}()
+
+	topics, err := client.Topics()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list topics: %w", err)
+	}
+	sort.Strings(topics)
+
+	status := &MirrorStatus{}
+	flows := make(map[[3]string]*MirrorFlow)
+	checkpoints := make(map[string]MirrorCheckpoint)
+	for _, topic := range topics {
+		role := MirrorTopicRoleOf(topic)
+		if role == "" {
+			continue
+		}
+		status.Topics = append(status.Topics, MirrorTopic{Name: topic, Role: role})
+
+		switch role {
+		case MirrorHeartbeats:
+			err = readMirrorTopic(client, topic, mirrorHeartbeatSample, func(msg *sarama.ConsumerMessage) {
+				source, target, at, err := decodeHeartbeat(msg.Key, msg.Value)
+				if err != nil {
+					logger.Get().WithField("topic", topic).WithError(err).Debug("Skipping undecodable heartbeat")
+					return
+				}
+				key := [3]string{source, target, topic}
+				if f, ok := flows[key]; !ok {
+					flows[key] = &MirrorFlow{Source: source, Target: target, Topic: topic, LastHeartbeat: at}
+				} else if at.After(f.LastHeartbeat) {
+					f.LastHeartbeat = at
+				}
+			})
+		case MirrorCheckpoints:
+			source := strings.TrimSuffix(topic, ".checkpoints.internal")
+			// Checkpoints are compacted, so the whole topic is the latest state
+			err = readMirrorTopic(client, topic, 0, func(msg *sarama.ConsumerMessage) {
+				cp, err := decodeCheckpoint(msg.Key, msg.Value)
+				if err != nil {
+					logger.Get().WithField("topic", topic).WithError(err).Debug("Skipping undecodable checkpoint")
+					return
+				}
+				cp.Source, cp.At = source, msg.Timestamp
+				checkpoints[fmt.Sprintf("%s/%s/%s/%d", source, cp.Group, cp.Topic, cp.Partition)] = cp
+			})
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, f := range flows {
+		for _, topic := range topics {
+			if strings.HasPrefix(topic, f.Source+".") && MirrorTopicRoleOf(topic) == "" {
+				f.RemoteTopics++
+			}
+		}
+		status.Flows = append(status.Flows, *f)
+	}
+	sort.Slice(status.Flows, func(i, j int) bool {
+		a, b := status.Flows[i], status.Flows[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Target != b.Target {
+			return a.Target < b.Target
+		}
+		return a.Topic < b.Topic
+	})
+	for _, cp := range checkpoints {
+		status.Checkpoints = append(status.Checkpoints, cp)
+	}
+	sort.Slice(status.Checkpoints, func(i, j int) bool {
+		a, b := status.Checkpoints[i], status.Checkpoints[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Topic != b.Topic {
+			return a.Topic < b.Topic
+		}
+		return a.Partition < b.Partition
+	})
+	return status, nil
+}
+
+// readMirrorTopic passes the records of every partition of topic up to its
+// current end to fn: the last records of each partition, or all of them
+// when last is 0
+func readMirrorTopic(client sarama.Client, topic string, last int64, fn func(*sarama.ConsumerMessage)) error {
+	partitions, err := client.Partitions(topic)
+	if err != nil {
+		return fmt.Errorf("failed to get partitions of %s: %w", topic, err)
+	}
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		return fmt.Errorf("failed to create consumer: %w", err)
+	}
+	defer func() {
+		if closeErr := consumer.Close(); closeErr != nil {
+			logger.Get().WithError(closeErr).Warn("Failed to close MirrorMaker consumer")
+		}
+	}()
+
+	for _, partition := range partitions {
+		newest, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return fmt.Errorf("failed to get newest offset of %s/%d: %w", topic, partition, err)
+		}
+		oldest, err := client.GetOffset(topic, partition, sarama.OffsetOldest)
+		if err != nil {
+			return fmt.Errorf("failed to get oldest offset of %s/%d: %w", topic, partition, err)
+		}
+		start := oldest
+		if last > 0 {
+			start = lastNOffset(oldest, newest, last)
+		}
+		if start >= newest {
+			continue
+		}
+
+		pc, err := consumer.ConsumePartition(topic, partition, start)
+		if err != nil {
+			return fmt.Errorf("failed to read %s/%d: %w", topic, partition, err)
+		}
+		readPartitionUntil(pc, newest, fn)
+		if err := pc.Close(); err != nil {
+			logger.Get().WithField("topic", topic).WithError(err).Debug("Failed to close partition consumer")
+		}
+	}
+	return nil
+}
+
+// readPartitionUntil passes records to fn until the one before end
+func readPartitionUntil(pc sarama.PartitionConsumer, end int64, fn func(*sarama.ConsumerMessage)) {
+	_ = readPartition(context.Background(), pc, true, func(msg *sarama.ConsumerMessage) bool {
+		fn(msg)
+		return msg.Offset+1 < end
+	}, nil)
+}
+
+// errMirrorRecordShort is returned for MM2 records shorter than their schema
+var errMirrorRecordShort = errors.New("record too short")
+
+// mirrorReader decodes the fields of Kafka's Struct serialization that MM2
+// uses for its records: big-endian integers and int16 length prefixed
+// strings
+type mirrorReader struct {
+	buf []byte
+	err error
+}
+
+func (r *mirrorReader) take(n int) []byte {
+	if r.err != nil || len(r.buf) < n {
+		r.err = errMirrorRecordShort
+		return make([]byte, n)
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *mirrorReader) int16() int16 { return int16(binary.BigEndian.Uint16(r.take(2))) }
+func (r *mirrorReader) int32() int32 { return int32(binary.BigEndian.Uint32(r.take(4))) }
+func (r *mirrorReader) int64() int64 { return int64(binary.BigEndian.Uint64(r.take(8))) }
+
+func (r *mirrorReader) string() string {
+	n := r.int16()
+	if n < 0 {
+		return ""
+	}
+	return string(r.take(int(n)))
+}
+
+// decodeHeartbeat decodes a heartbeat: the key holds the source and target
+// aliases, the value a version header and the time it was emitted
+func decodeHeartbeat(key, value []byte) (source, target string, at time.Time, err error) {
+	k := mirrorReader{buf: key}
+	source, target = k.string(), k.string()
+	v := mirrorReader{buf: value}
+	v.int16() // Version, only 0 exists
+	ms := v.int64()
+	if err := errors.Join(k.err, v.err); err != nil {
+		return "", "", time.Time{}, err
+	}
+	return source, target, time.UnixMilli(ms), nil
+}
+
+// decodeCheckpoint decodes a checkpoint: the key holds the group and
+// partition, the value a version header and the offsets on both clusters
+func decodeCheckpoint(key, value []byte) (MirrorCheckpoint, error) {
+	k := mirrorReader{buf: key}
+	cp := MirrorCheckpoint{Group: k.string(), Topic: k.string(), Partition: k.int32()}
+	v := mirrorReader{buf: value}
+	v.int16() // Version, only 0 exists
+	cp.UpstreamOffset, cp.DownstreamOffset = v.int64(), v.int64()
+	return cp, errors.Join(k.err, v.err)
+}
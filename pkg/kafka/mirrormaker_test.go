@@ -0,0 +1,72 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+// mirrorRecord encodes fields the way Kafka's Struct serialization does
+func mirrorRecord(fields ...any) []byte {
+	var b []byte
+	for _, f := range fields {
+		switch f := f.(type) {
+		case string:
+			b = binary.BigEndian.AppendUint16(b, uint16(len(f)))
+			b = append(b, f...)
+		case int16:
+			b = binary.BigEndian.AppendUint16(b, uint16(f))
+		case int32:
+			b = binary.BigEndian.AppendUint32(b, uint32(f))
+		case int64:
+			b = binary.BigEndian.AppendUint64(b, uint64(f))
+		}
+	}
+	return b
+}
+
+func TestDecodeHeartbeat(t *testing.T) {
+	at := time.UnixMilli(1700000000123)
+	source, target, got, err := decodeHeartbeat(mirrorRecord("primary", "dr"), mirrorRecord(int16(0), at.UnixMilli()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source != "primary" || target != "dr" || !got.Equal(at) {
+		t.Errorf("decodeHeartbeat() = %s, %s, %v", source, target, got)
+	}
+
+	if _, _, _, err := decodeHeartbeat(mirrorRecord("primary"), nil); err == nil {
+		t.Error("expected an error for a truncated heartbeat")
+	}
+}
+
+func TestDecodeCheckpoint(t *testing.T) {
+	cp, err := decodeCheckpoint(mirrorRecord("billing", "primary.orders", int32(3)), mirrorRecord(int16(0), int64(1500), int64(1420), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := MirrorCheckpoint{Group: "billing", Topic: "primary.orders", Partition: 3, UpstreamOffset: 1500, DownstreamOffset: 1420}
+	if cp != want {
+		t.Errorf("decodeCheckpoint() = %+v, want %+v", cp, want)
+	}
+}
+
+func TestMirrorTopicRoleOf(t *testing.T) {
+	tests := []struct {
+		topic string
+		want  MirrorTopicRole
+	}{
+		{"heartbeats", MirrorHeartbeats},
+		{"primary.heartbeats", MirrorHeartbeats},
+		{"primary.checkpoints.internal", MirrorCheckpoints},
+		{"mm2-offset-syncs.dr.internal", MirrorOffsetSyncs},
+		{"mm2-status.primary.internal", MirrorConnect},
+		{"primary.orders", ""},
+		{"orders.internal", ""},
+	}
+	for _, tt := range tests {
+		if got := MirrorTopicRoleOf(tt.topic); got != tt.want {
+			t.Errorf("MirrorTopicRoleOf(%q) = %q, want %q", tt.topic, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package kafka
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/IBM/sarama"
+	"golang.org/x/net/proxy"
+)
+
+// NetworkConfig controls how connections to the brokers are made
+type NetworkConfig struct {
+	Proxy string     // socks5:// or http:// proxy URL
+	SSH   *SSHConfig // Tunnel every broker connection through an SSH bastion
+
+	// BrokerAddresses maps addresses the brokers advertise to ones that can
+	// be reached from here, as host:port or just a host to keep the port
+	BrokerAddresses map[string]string
+}
+
+// configureNetwork points sarama at the dialer the network config asks for.
+// With both a proxy and a bastion, the bastion is reached through the proxy.
+// The returned closer, if any, is closed with the client.
+func configureNetwork(config *sarama.Config, netConfig *NetworkConfig) (io.Closer, error) {
+	if netConfig == nil || (netConfig.Proxy == "" && netConfig.SSH == nil && len(netConfig.BrokerAddresses) == 0) {
+		return nil, nil
+	}
+
+	var dialer proxy.Dialer = &net.Dialer{
+		Timeout:   config.Net.DialTimeout,
+		KeepAlive: config.Net.KeepAlive,
+		LocalAddr: config.Net.LocalAddr,
+	}
+	if netConfig.Proxy != "" {
+		d, err := proxyDialer(netConfig.Proxy, dialer)
+		if err != nil {
+			return nil, err
+		}
+		dialer = d
+	}
+
+	var tunnel io.Closer
+	if netConfig.SSH != nil {
+		client, err := dialSSH(netConfig.SSH, dialer)
+		if err != nil {
+			return nil, err
+		}
+		// Every broker is dialed from the bastion, so the addresses the
+		// cluster advertises work without setting up a forward for each
+		dialer, tunnel = client, client
+	}
+
+	if len(netConfig.BrokerAddresses) > 0 {
+		dialer = newAddressRewriter(dialer, netConfig.BrokerAddresses)
+	}
+	config.Net.Proxy.Enable = true
+	config.Net.Proxy.Dialer = dialer
+	return tunnel, nil
+}
+
+// addressRewriter dials the reachable address in place of an advertised one.
+// TLS still verifies the advertised name, which sarama takes from the broker
+// address rather than the connection.
+type addressRewriter struct {
+	dialer    proxy.Dialer
+	addresses map[string]string // Lower case, as host names are
+}
+
+func newAddressRewriter(dialer proxy.Dialer, addresses map[string]string) *addressRewriter {
+	r := &addressRewriter{dialer: dialer, addresses: make(map[string]string, len(addresses))}
+	for from, to := range addresses {
+		r.addresses[strings.ToLower(from)] = to
+	}
+	return r
+}
+
+func (r *addressRewriter) Dial(network, addr string) (net.Conn, error) {
+	return r.dialer.Dial(network, r.rewrite(addr))
+}
+
+// rewrite returns where addr should be dialed. A mapping for the full
+// host:port wins over one for the host alone.
+func (r *addressRewriter) rewrite(addr string) string {
+	if to, ok := r.addresses[strings.ToLower(addr)]; ok {
+		return to
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	to, ok := r.addresses[strings.ToLower(host)]
+	if !ok {
+		return addr
+	}
+	if _, _, err := net.SplitHostPort(to); err == nil {
+		return to
+	}
+	return net.JoinHostPort(to, port)
+}
+
+// brokerDialer is the dialer sarama would use for the config
+func brokerDialer(config *sarama.Config) proxy.Dialer {
+	if config.Net.Proxy.Enable {
+		return config.Net.Proxy.Dialer
+	}
+	return &net.Dialer{Timeout: config.Net.DialTimeout, KeepAlive: config.Net.KeepAlive, LocalAddr: config.Net.LocalAddr}
+}
+
+// validServerName sets the server name to check the certificate against to
+// the broker's host, as sarama does
+func validServerName(addr string, cfg *tls.Config) *tls.Config {
+	if cfg == nil {
+		cfg = &tls.Config{}
+	}
+	if cfg.ServerName != "" {
+		return cfg
+	}
+	c := cfg.Clone()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		c.ServerName = host
+	}
+	return c
+}
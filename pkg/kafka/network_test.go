@@ -0,0 +1,29 @@
+package kafka
+
+import "testing"
+
+func TestAddressRewriter(t *testing.T) {
+	r := newAddressRewriter(nil, map[string]string{
+		"kafka-0.kafka-headless:9092": "localhost:19092",
+		"Kafka":                       "127.0.0.1",
+		"broker":                      "localhost:29092",
+	})
+
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{"kafka-0.kafka-headless:9092", "localhost:19092"},
+		{"KAFKA-0.kafka-headless:9092", "localhost:19092"},
+		{"kafka-0.kafka-headless:9093", "kafka-0.kafka-headless:9093"},
+		{"kafka:29092", "127.0.0.1:29092"},
+		{"broker:9092", "localhost:29092"},
+		{"other:9092", "other:9092"},
+		{"not-an-address", "not-an-address"},
+	}
+	for _, tt := range tests {
+		if got := r.rewrite(tt.addr); got != tt.want {
+			t.Errorf("rewrite(%q) = %q, want %q", tt.addr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/IBM/sarama"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+)
+
+// GetLogEndOffsets returns the sum of the log end offsets of each
+// non-internal topic's partitions. Sampled twice, the difference is the
+// number of messages produced in between.
+func (c *Client) GetLogEndOffsets() (map[string]int64, error) {
+	offsets, err := c.listOffsets(sarama.OffsetNewest)
+	if err != nil {
+		return nil, err
+	}
+	totals := make(map[string]int64, len(offsets))
+	for topic, partitions := range offsets {
+		for _, offset := range partitions {
+			totals[topic] += offset
+		}
+	}
+	return totals, nil
+}
+
+// GetMessageCounts estimates how many messages each non-internal topic holds
+// as the sum over its partitions of the high minus the low watermark. Records
+// removed by compaction and transaction markers still count, so for those
+// topics the figure is an upper bound.
+func (c *Client) GetMessageCounts() (_ map[string]int64, err error) {
+	_, span := startSpan(context.Background(), "GetMessageCounts")
+	defer func() { endSpan(span, err) }()
+
+	oldest, err := c.listOffsets(sarama.OffsetOldest)
+	if err != nil {
+		return nil, err
+	}
+	newest, err := c.listOffsets(sarama.OffsetNewest)
+	if err != nil {
+		return nil, err
+	}
+	return messageCounts(oldest, newest), nil
+}
+
+// messageCounts sums newest minus oldest per topic, skipping partitions
+// missing from either listing
+func messageCounts(oldest, newest map[string]map[int32]int64) map[string]int64 {
+	counts := make(map[string]int64, len(newest))
+	for topic, partitions := range newest {
+		for partition, high := range partitions {
+			low, ok := oldest[topic][partition]
+			if !ok || high < low {
+				continue
+			}
+			counts[topic] += high - low
+		}
+	}
+	return counts
+}
+
+// listOffsets returns the offset at time (sarama.OffsetNewest or
+// sarama.OffsetOldest) of every partition of every non-internal topic. Each
+// leader is asked once for all of its partitions; partitions without a
+// leader are left out.
+func (c *Client) listOffsets(time int64) (map[string]map[int32]int64, error) {
+	log := logger.Get()
+
+	client, err := sarama.NewClient(c.brokers, c.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	defer func() {
+		if closeErr := client.Close(); closeErr != nil {
+			log.WithError(closeErr).Warn("Failed to close offsets client")
+		}
+	}()
+
+	all, err := client.Topics()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list topics: %w", err)
+	}
+	var topics []string
+	for _, topic := range all {
+		if !strings.HasPrefix(topic, "__") {
+			topics = append(topics, topic)
+		}
+	}
+	return leaderOffsets(client, topics, time)
+}
+
+// leaderOffsets returns the offset at time of every partition of topics,
+// asking each leader once
+func leaderOffsets(client sarama.Client, topics []string, time int64) (map[string]map[int32]int64, error) {
+	log := logger.Get()
+
+	leaders := make(map[int32]*sarama.Broker)
+	requests := make(map[int32]*sarama.OffsetRequest)
+	for _, topic := range topics {
+		partitions, err := client.Partitions(topic)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get partitions of %s: %w", topic, err)
+		}
+		for _, partition := range partitions {
+			leader, err := client.Leader(topic, partition)
+			if err != nil {
+				log.WithField("topic", topic).WithField("partition", partition).WithError(err).Debug("Skipping partition without a leader")
+				continue
+			}
+			req, ok := requests[leader.ID()]
+			if !ok {
+				req = &sarama.OffsetRequest{Version: 1}
+				requests[leader.ID()] = req
+				leaders[leader.ID()] = leader
+			}
+			req.AddBlock(topic, partition, time, 1)
+		}
+	}
+
+	offsets := make(map[string]map[int32]int64)
+	for id, req := range requests {
+		resp, err := leaders[id].GetAvailableOffsets(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list offsets on broker %d: %w", id, err)
+		}
+		for topic, blocks := range resp.Blocks {
+			for partition, block := range blocks {
+				if block.Err != sarama.ErrNoError {
+					log.WithField("topic", topic).WithField("partition", partition).WithError(block.Err).Debug("Failed to get partition offset")
+					continue
+				}
+				if offsets[topic] == nil {
+					offsets[topic] = make(map[int32]int64)
+				}
+				offsets[topic][partition] = block.Offset
+			}
+		}
+	}
+	return offsets, nil
+}
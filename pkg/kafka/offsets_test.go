@@ -0,0 +1,22 @@
+package kafka
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMessageCounts(t *testing.T) {
+	oldest := map[string]map[int32]int64{
+		"orders":  {0: 100, 1: 0},
+		"payment": {0: 5},
+	}
+	newest := map[string]map[int32]int64{
+		"orders":  {0: 150, 1: 20, 2: 30}, // Partition 2 has no low watermark
+		"payment": {0: 5},
+		"empty":   {0: 0},
+	}
+	want := map[string]int64{"orders": 70, "payment": 0}
+	if got := messageCounts(oldest, newest); !reflect.DeepEqual(got, want) {
+		t.Errorf("messageCounts() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,180 @@
+package kafka
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// Acks values of ProducerSettings
+const (
+	AcksAll    = "all"
+	AcksLeader = "1"
+	AcksNone   = "0"
+)
+
+// CompressionCodecs are the compression types ProducerSettings accepts
+var CompressionCodecs = []string{"none", "gzip", "snappy", "lz4", "zstd"}
+
+// ProducerSettings are the options of the producer used for messages sent
+// through a client. The zero value is what kconduit always used: acks=all,
+// no idempotence or compression and no linger.
+type ProducerSettings struct {
+	Acks        string        `mapstructure:"acks"` // all, 1 or 0
+	Idempotence bool          `mapstructure:"idempotence"`
+	Compression string        `mapstructure:"compression"` // One of CompressionCodecs
+	Linger      time.Duration `mapstructure:"linger"`      // How long to wait for more messages before sending a batch
+}
+
+// normalized fills in the defaults and lower-cases names
+func (s ProducerSettings) normalized() ProducerSettings {
+	s.Acks = strings.ToLower(strings.TrimSpace(s.Acks))
+	switch s.Acks {
+	case "", "-1":
+		s.Acks = AcksAll
+	case "leader":
+		s.Acks = AcksLeader
+	}
+	s.Compression = strings.ToLower(strings.TrimSpace(s.Compression))
+	if s.Compression == "" {
+		s.Compression = "none"
+	}
+	return s
+}
+
+// Validate checks the settings are ones sarama accepts
+func (s ProducerSettings) Validate() error {
+	s = s.normalized()
+	if _, err := s.requiredAcks(); err != nil {
+		return err
+	}
+	if _, err := s.codec(); err != nil {
+		return err
+	}
+	if s.Idempotence && s.Acks != AcksAll {
+		return fmt.Errorf("invalid producer settings: idempotence needs acks=all")
+	}
+	if s.Linger < 0 {
+		return fmt.Errorf("invalid producer settings: linger must not be negative")
+	}
+	return nil
+}
+
+// String shows the settings the way Kafka names them
+func (s ProducerSettings) String() string {
+	s = s.normalized()
+	idempotence := "off"
+	if s.Idempotence {
+		idempotence = "on"
+	}
+	return fmt.Sprintf("acks=%s idempotence=%s compression=%s linger=%s", s.Acks, idempotence, s.Compression, s.Linger)
+}
+
+func (s ProducerSettings) requiredAcks() (sarama.RequiredAcks, error) {
+	switch s.Acks {
+	case AcksAll:
+		return sarama.WaitForAll, nil
+	case AcksLeader:
+		return sarama.WaitForLocal, nil
+	case AcksNone:
+		return sarama.NoResponse, nil
+	}
+	return 0, fmt.Errorf("invalid producer settings: acks %q, use all, 1 or 0", s.Acks)
+}
+
+func (s ProducerSettings) codec() (sarama.CompressionCodec, error) {
+	switch s.Compression {
+	case "none":
+		return sarama.CompressionNone, nil
+	case "gzip":
+		return sarama.CompressionGZIP, nil
+	case "snappy":
+		return sarama.CompressionSnappy, nil
+	case "lz4":
+		return sarama.CompressionLZ4, nil
+	case "zstd":
+		return sarama.CompressionZSTD, nil
+	}
+	return 0, fmt.Errorf("invalid producer settings: compression %q, use one of %s", s.Compression, strings.Join(CompressionCodecs, ", "))
+}
+
+// apply sets the settings on a copy of config
+func (s ProducerSettings) apply(config *sarama.Config) (*sarama.Config, error) {
+	s = s.normalized()
+	acks, err := s.requiredAcks()
+	if err != nil {
+		return nil, err
+	}
+	codec, err := s.codec()
+	if err != nil {
+		return nil, err
+	}
+
+	applied := *config
+	applied.Producer.RequiredAcks = acks
+	applied.Producer.Compression = codec
+	applied.Producer.Idempotent = s.Idempotence
+	applied.Producer.Flush.Frequency = s.Linger
+	if s.Idempotence {
+		// Sarama refuses idempotence with more than one request in flight
+		applied.Net.MaxOpenRequests = 1
+	}
+	return &applied, nil
+}
+
+// sharedProducer is the sync producer of a client and all its clones, so
+// new settings take effect for every one of them
+type sharedProducer struct {
+	mu       sync.RWMutex
+	producer sarama.SyncProducer
+	settings ProducerSettings
+}
+
+func (p *sharedProducer) send(msg *sarama.ProducerMessage) (int32, int64, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.producer.SendMessage(msg)
+}
+
+func (p *sharedProducer) close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.producer.Close()
+}
+
+// SetProducerSettings replaces the producer of c and its clones with one
+// using s. Messages in flight finish on the old producer first.
+func (c *Client) SetProducerSettings(s ProducerSettings) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	s = s.normalized()
+	config, err := s.apply(c.config)
+	if err != nil {
+		return err
+	}
+	producer, err := sarama.NewSyncProducer(c.brokers, config)
+	if err != nil {
+		return fmt.Errorf("failed to create producer with %s: %w", s, err)
+	}
+
+	c.producer.mu.Lock()
+	old := c.producer.producer
+	c.producer.producer = producer
+	c.producer.settings = s
+	c.producer.mu.Unlock()
+	if err := old.Close(); err != nil {
+		return fmt.Errorf("failed to close the previous producer: %w", err)
+	}
+	return nil
+}
+
+// ProducerSettings returns the settings messages are produced with
+func (c *Client) ProducerSettings() ProducerSettings {
+	c.producer.mu.RLock()
+	defer c.producer.mu.RUnlock()
+	return c.producer.settings.normalized()
+}
@@ -0,0 +1,62 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+func TestProducerSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings ProducerSettings
+		wantErr  bool
+	}{
+		{name: "defaults", settings: ProducerSettings{}},
+		{name: "leader acks", settings: ProducerSettings{Acks: "leader", Compression: "LZ4"}},
+		{name: "idempotent", settings: ProducerSettings{Acks: "-1", Idempotence: true, Compression: "zstd"}},
+		{name: "idempotence without acks all", settings: ProducerSettings{Acks: "1", Idempotence: true}, wantErr: true},
+		{name: "unknown acks", settings: ProducerSettings{Acks: "2"}, wantErr: true},
+		{name: "unknown compression", settings: ProducerSettings{Compression: "brotli"}, wantErr: true},
+		{name: "negative linger", settings: ProducerSettings{Linger: -time.Second}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.settings.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProducerSettingsApply(t *testing.T) {
+	base := sarama.NewConfig()
+	base.Version = sarama.V2_8_0_0
+	settings := ProducerSettings{Acks: "all", Idempotence: true, Compression: "zstd", Linger: 10 * time.Millisecond}
+
+	config, err := settings.apply(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Producer.RequiredAcks != sarama.WaitForAll || !config.Producer.Idempotent ||
+		config.Producer.Compression != sarama.CompressionZSTD || config.Producer.Flush.Frequency != 10*time.Millisecond {
+		t.Errorf("producer config = %+v", config.Producer)
+	}
+	if config.Net.MaxOpenRequests != 1 {
+		t.Errorf("MaxOpenRequests = %d, want 1 for idempotence", config.Net.MaxOpenRequests)
+	}
+	if base.Producer.Idempotent || base.Producer.Compression != sarama.CompressionNone {
+		t.Error("apply changed the base config")
+	}
+
+	// Sarama accepts the result when creating a producer
+	config.Producer.Return.Successes = true
+	if err := config.Validate(); err != nil {
+		t.Errorf("sarama rejects the config: %v", err)
+	}
+
+	if got := (ProducerSettings{}).String(); got != "acks=all idempotence=off compression=none linger=0s" {
+		t.Errorf("String() = %q", got)
+	}
+}
@@ -0,0 +1,93 @@
+package kafka
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/proxy"
+)
+
+// proxyDialer returns a dialer that connects through the SOCKS5 or HTTP
+// CONNECT proxy at proxyURL, reaching the proxy itself with forward
+func proxyDialer(proxyURL string, forward proxy.Dialer) (proxy.Dialer, error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: expected e.g. socks5://host:1080 or http://host:3128", proxyURL)
+	}
+
+	switch u.Scheme {
+	case "socks5", "socks5h":
+		return proxy.FromURL(u, forward)
+	case "http":
+		return &httpConnectDialer{proxy: u, forward: forward}, nil
+	}
+	return nil, fmt.Errorf("unsupported proxy scheme %q: use socks5 or http", u.Scheme)
+}
+
+// httpConnectDialer tunnels connections through an HTTP proxy with CONNECT
+type httpConnectDialer struct {
+	proxy   *url.URL
+	forward proxy.Dialer
+}
+
+func (d *httpConnectDialer) Dial(network, addr string) (net.Conn, error) {
+	proxyAddr := d.proxy.Host
+	if d.proxy.Port() == "" {
+		proxyAddr = net.JoinHostPort(proxyAddr, "80")
+	}
+	conn, err := d.forward.Dial(network, proxyAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach proxy %s: %w", proxyAddr, err)
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if user := d.proxy.User; user != nil {
+		password, _ := user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s: %w", proxyAddr, err)
+	}
+	// The body of a successful CONNECT is the tunnel itself
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s refused CONNECT to %s: %s", proxyAddr, addr, resp.Status)
+	}
+	if br.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, r: br}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn hands out what the proxy sent after its response before
+// reading from the connection again
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
@@ -0,0 +1,66 @@
+package kafka
+
+import "sort"
+
+// RackSpread describes how a topic's replicas are spread over racks
+type RackSpread struct {
+	Racks int // Distinct racks in the cluster, 0 when none are configured
+	// SingleRack lists partitions with several replicas that all sit in one
+	// rack, so losing that rack takes the partition offline
+	SingleRack []int32
+	// Narrow lists the other partitions that span fewer racks than their
+	// replication factor allows
+	Narrow []int32
+	// Unracked lists partitions with a replica on a broker without a rack
+	Unracked []int32
+}
+
+// CheckRackSpread compares each partition's replica racks with the racks
+// available in the cluster, given the rack of every broker
+func CheckRackSpread(partitions []PartitionInfo, racks map[int32]string) RackSpread {
+	distinct := make(map[string]bool)
+	for _, rack := range racks {
+		if rack != "" {
+			distinct[rack] = true
+		}
+	}
+	spread := RackSpread{Racks: len(distinct)}
+	if spread.Racks == 0 {
+		return spread
+	}
+
+	for _, p := range partitions {
+		replicaRacks := make(map[string]bool, len(p.Replicas))
+		unracked := false
+		for _, r := range p.Replicas {
+			rack := racks[r]
+			if rack == "" {
+				unracked = true
+				continue
+			}
+			replicaRacks[rack] = true
+		}
+		if unracked {
+			spread.Unracked = append(spread.Unracked, p.ID)
+			continue
+		}
+		want := min(len(p.Replicas), spread.Racks)
+		switch {
+		case len(replicaRacks) >= want:
+		case len(replicaRacks) == 1:
+			spread.SingleRack = append(spread.SingleRack, p.ID)
+		default:
+			spread.Narrow = append(spread.Narrow, p.ID)
+		}
+	}
+
+	for _, ids := range [][]int32{spread.SingleRack, spread.Narrow, spread.Unracked} {
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	}
+	return spread
+}
+
+// OK reports whether every partition spans as many racks as it can
+func (s RackSpread) OK() bool {
+	return len(s.SingleRack) == 0 && len(s.Narrow) == 0 && len(s.Unracked) == 0
+}
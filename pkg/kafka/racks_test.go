@@ -0,0 +1,69 @@
+package kafka
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckRackSpread(t *testing.T) {
+	threeRacks := map[int32]string{1: "a", 2: "a", 3: "b", 4: "c"}
+
+	tests := []struct {
+		name       string
+		partitions []PartitionInfo
+		racks      map[int32]string
+		want       RackSpread
+	}{
+		{
+			name:       "no racks configured",
+			partitions: []PartitionInfo{{ID: 0, Replicas: []int32{1, 2}}},
+			racks:      map[int32]string{1: "", 2: ""},
+			want:       RackSpread{},
+		},
+		{
+			name: "spread over racks",
+			partitions: []PartitionInfo{
+				{ID: 0, Replicas: []int32{1, 3, 4}},
+				{ID: 1, Replicas: []int32{2, 3}},
+			},
+			racks: threeRacks,
+			want:  RackSpread{Racks: 3},
+		},
+		{
+			name: "all replicas in one rack",
+			partitions: []PartitionInfo{
+				{ID: 0, Replicas: []int32{1, 2}},
+				{ID: 1, Replicas: []int32{3, 4}},
+			},
+			racks: threeRacks,
+			want:  RackSpread{Racks: 3, SingleRack: []int32{0}},
+		},
+		{
+			name:       "two racks where three are possible",
+			partitions: []PartitionInfo{{ID: 2, Replicas: []int32{1, 2, 3}}},
+			racks:      threeRacks,
+			want:       RackSpread{Racks: 3, Narrow: []int32{2}},
+		},
+		{
+			name:       "single replica is never flagged",
+			partitions: []PartitionInfo{{ID: 0, Replicas: []int32{1}}},
+			racks:      threeRacks,
+			want:       RackSpread{Racks: 3},
+		},
+		{
+			name:       "broker without a rack",
+			partitions: []PartitionInfo{{ID: 0, Replicas: []int32{1, 5}}},
+			racks:      map[int32]string{1: "a", 5: ""},
+			want:       RackSpread{Racks: 1, Unracked: []int32{0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckRackSpread(tt.partitions, tt.racks)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
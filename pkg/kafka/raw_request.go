@@ -0,0 +1,217 @@
+package kafka
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// rawConn is a connection to one broker for the requests sarama has no API
+// for. It negotiates versions and authenticates the way sarama would, but
+// only supports SASL PLAIN.
+type rawConn struct {
+	conn        net.Conn
+	reader      *bufio.Reader
+	timeout     time.Duration
+	formatter   *kmsg.RequestFormatter
+	correlation int32
+	versions    map[int16]int16 // Highest version the broker supports, by API key
+	apiVersions *kmsg.ApiVersionsResponse
+}
+
+// dialRaw connects to the broker at addr with the client's network, TLS
+// and SASL settings
+func dialRaw(config *sarama.Config, addr string) (*rawConn, error) {
+	conn, err := brokerDialer(config).Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if config.Net.TLS.Enable {
+		conn = tls.Client(conn, validServerName(addr, config.Net.TLS.Config))
+	}
+	c := &rawConn{
+		conn:      conn,
+		reader:    bufio.NewReader(conn),
+		timeout:   config.Net.ReadTimeout,
+		formatter: kmsg.NewRequestFormatter(kmsg.FormatterClientID(config.ClientID)),
+		versions:  map[int16]int16{},
+	}
+
+	// Version 0 is understood by every broker and answers with all the
+	// versions it supports, even though it is not flexible
+	versions := kmsg.NewPtrApiVersionsRequest()
+	resp, err := c.send(versions, 0)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to negotiate API versions with %s: %w", addr, err)
+	}
+	apiVersions := resp.(*kmsg.ApiVersionsResponse)
+	if err := kafkaError(apiVersions.ErrorCode); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to negotiate API versions with %s: %w", addr, err)
+	}
+	for _, key := range apiVersions.ApiKeys {
+		c.versions[key.ApiKey] = key.MaxVersion
+	}
+	// Features are only reported from version 3, which needs the client to
+	// name itself
+	if c.versions[versions.Key()] >= 3 {
+		versions.ClientSoftwareName = "kconduit"
+		versions.ClientSoftwareVersion = "unknown"
+		if resp, err = c.Request(versions); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to negotiate API versions with %s: %w", addr, err)
+		}
+		if r := resp.(*kmsg.ApiVersionsResponse); r.ErrorCode == 0 {
+			apiVersions = r
+		}
+	}
+	c.apiVersions = apiVersions
+
+	if config.Net.SASL.Enable {
+		if err := c.authenticate(config); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("SASL authentication with %s failed: %w", addr, err)
+		}
+	}
+	return c, nil
+}
+
+// authenticate runs the SASL handshake and a PLAIN exchange
+func (c *rawConn) authenticate(config *sarama.Config) error {
+	if config.Net.SASL.Mechanism != sarama.SASLTypePlaintext {
+		return fmt.Errorf("%s is not supported for this request, only PLAIN is", config.Net.SASL.Mechanism)
+	}
+
+	handshake := kmsg.NewPtrSASLHandshakeRequest()
+	handshake.Mechanism = sarama.SASLTypePlaintext
+	resp, err := c.Request(handshake)
+	if err != nil {
+		return err
+	}
+	if err := kafkaError(resp.(*kmsg.SASLHandshakeResponse).ErrorCode); err != nil {
+		return err
+	}
+
+	auth := kmsg.NewPtrSASLAuthenticateRequest()
+	auth.SASLAuthBytes = []byte("\x00" + config.Net.SASL.User + "\x00" + config.Net.SASL.Password)
+	resp, err = c.Request(auth)
+	if err != nil {
+		return err
+	}
+	authResp := resp.(*kmsg.SASLAuthenticateResponse)
+	if err := kafkaError(authResp.ErrorCode); err != nil {
+		if authResp.ErrorMessage != nil {
+			return fmt.Errorf("%w: %s", err, *authResp.ErrorMessage)
+		}
+		return err
+	}
+	return nil
+}
+
+// Supports reports whether the broker offers the API at all
+func (c *rawConn) Supports(req kmsg.Request) bool {
+	_, ok := c.versions[req.Key()]
+	return ok
+}
+
+// Request sends req at the highest version both sides support and waits
+// for the response
+func (c *rawConn) Request(req kmsg.Request) (kmsg.Response, error) {
+	brokerMax, ok := c.versions[req.Key()]
+	if !ok {
+		return nil, fmt.Errorf("the broker does not support %s requests", kmsg.NameForKey(req.Key()))
+	}
+	return c.send(req, min(req.MaxVersion(), brokerMax))
+}
+
+func (c *rawConn) send(req kmsg.Request, version int16) (kmsg.Response, error) {
+	req.SetVersion(version)
+	c.correlation++
+	if c.timeout > 0 {
+		_ = c.conn.SetDeadline(time.Now().Add(c.timeout))
+	}
+	if _, err := c.conn.Write(c.formatter.AppendRequest(nil, req, c.correlation)); err != nil {
+		return nil, err
+	}
+
+	var size int32
+	if err := binary.Read(c.reader, binary.BigEndian, &size); err != nil {
+		return nil, err
+	}
+	if size < 4 {
+		return nil, fmt.Errorf("response of %d bytes is too short", size)
+	}
+	body := make([]byte, size)
+	if _, err := io.ReadFull(c.reader, body); err != nil {
+		return nil, err
+	}
+	if got := int32(binary.BigEndian.Uint32(body)); got != c.correlation {
+		return nil, fmt.Errorf("response correlation ID %d does not match request %d", got, c.correlation)
+	}
+
+	resp := req.ResponseKind()
+	body, err := skipResponseHeader(req, body[4:])
+	if err != nil {
+		return nil, err
+	}
+	if err := resp.ReadFrom(body); err != nil {
+		return nil, fmt.Errorf("failed to decode %s response: %w", kmsg.NameForKey(req.Key()), err)
+	}
+	return resp, nil
+}
+
+// skipResponseHeader skips the tagged fields that end a flexible response
+// header. ApiVersions responses never have them, so that clients can read
+// one from a broker of any version.
+func skipResponseHeader(req kmsg.Request, body []byte) ([]byte, error) {
+	if !req.IsFlexible() || req.Key() == kmsg.ApiVersions.Int16() {
+		return body, nil
+	}
+	uvarint := func() (uint64, error) {
+		v, n := binary.Uvarint(body)
+		if n <= 0 {
+			return 0, errors.New("truncated response header")
+		}
+		body = body[n:]
+		return v, nil
+	}
+	tags, err := uvarint()
+	if err != nil {
+		return nil, err
+	}
+	for ; tags > 0; tags-- {
+		if _, err := uvarint(); err != nil {
+			return nil, err
+		}
+		size, err := uvarint()
+		if err != nil {
+			return nil, err
+		}
+		if uint64(len(body)) < size {
+			return nil, errors.New("truncated response header")
+		}
+		body = body[size:]
+	}
+	return body, nil
+}
+
+func (c *rawConn) Close() error {
+	return c.conn.Close()
+}
+
+// kafkaError turns a response error code into an error, nil for none
+func kafkaError(code int16) error {
+	if code == 0 {
+		return nil
+	}
+	return sarama.KError(code)
+}
@@ -0,0 +1,323 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/IBM/sarama"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// PartitionMove changes the replicas of one partition
+type PartitionMove struct {
+	Topic     string
+	Partition int32
+	From      []int32
+	To        []int32
+}
+
+func (m PartitionMove) String() string {
+	return fmt.Sprintf("%s-%d: %v → %v", m.Topic, m.Partition, m.From, m.To)
+}
+
+// ReassignmentPlan is a set of partition moves ready to submit
+type ReassignmentPlan struct {
+	Moves []PartitionMove
+	// assignments holds the current replicas of every partition of the
+	// moved topics: sarama reassigns whole topics, so partitions that stay
+	// put are resubmitted unchanged
+	assignments map[string][][]int32
+}
+
+// Topics returns the moved topics in order
+func (p *ReassignmentPlan) Topics() []string {
+	topics := make([]string, 0, len(p.assignments))
+	for topic := range p.assignments {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+// ReassignmentProgress reports how far a decommission has got
+type ReassignmentProgress struct {
+	Moving    int // Planned partitions the controller is still reassigning
+	Remaining int // Replicas still hosted by the decommissioned broker
+}
+
+// Done reports whether the broker holds no replicas and nothing is moving
+func (p ReassignmentProgress) Done() bool {
+	return p.Moving == 0 && p.Remaining == 0
+}
+
+// PlanDecommission plans moving every replica off brokerID onto the other
+// brokers. The broker is never chosen as a target, so the plan drains it.
+func (c *Client) PlanDecommission(brokerID int32) (_ *ReassignmentPlan, err error) {
+	_, span := startSpan(context.Background(), "PlanDecommission", attribute.Int("broker", int(brokerID)))
+	defer func() { endSpan(span, err) }()
+
+	metadata, err := c.controllerMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	racks := make(map[int32]string, len(metadata.Brokers))
+	for _, b := range metadata.Brokers {
+		racks[b.ID()] = b.Rack()
+	}
+	if _, ok := racks[brokerID]; !ok {
+		return nil, fmt.Errorf("broker %d is not part of the cluster", brokerID)
+	}
+	return planDecommission(brokerID, racks, metadata.Topics)
+}
+
+// planDecommission replaces brokerID in every replica set it belongs to. The
+// replacement keeps the replica's position, so preferred leadership moves to
+// the new broker, and is chosen from brokers outside the replica set,
+// preferring the decommissioned broker's rack so rack spread is kept, then
+// the broker with the fewest replicas.
+func planDecommission(brokerID int32, racks map[int32]string, topics []*sarama.TopicMetadata) (*ReassignmentPlan, error) {
+	load := make(map[int32]int, len(racks))
+	for id := range racks {
+		load[id] = 0
+	}
+	for _, topic := range topics {
+		for _, p := range topic.Partitions {
+			for _, r := range p.Replicas {
+				load[r]++
+			}
+		}
+	}
+
+	var candidates []int32
+	for id := range racks {
+		if id != brokerID {
+			candidates = append(candidates, id)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i] < candidates[j] })
+
+	// Topics are walked in order so the plan is the same every time
+	sorted := make([]*sarama.TopicMetadata, len(topics))
+	copy(sorted, topics)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	plan := &ReassignmentPlan{assignments: make(map[string][][]int32)}
+	var stuck []string
+	for _, topic := range sorted {
+		partitions := make([]*sarama.PartitionMetadata, len(topic.Partitions))
+		copy(partitions, topic.Partitions)
+		sort.Slice(partitions, func(i, j int) bool { return partitions[i].ID < partitions[j].ID })
+
+		for _, p := range partitions {
+			pos := -1
+			for i, r := range p.Replicas {
+				if r == brokerID {
+					pos = i
+				}
+			}
+			if pos < 0 {
+				continue
+			}
+
+			target := int32(-1)
+			for _, id := range candidates {
+				if containsBroker(p.Replicas, id) {
+					continue
+				}
+				if target < 0 || betterTarget(id, target, racks[brokerID], racks, load) {
+					target = id
+				}
+			}
+			if target < 0 {
+				stuck = append(stuck, fmt.Sprintf("%s-%d", topic.Name, p.ID))
+				continue
+			}
+
+			to := make([]int32, len(p.Replicas))
+			copy(to, p.Replicas)
+			to[pos] = target
+			load[target]++
+			load[brokerID]--
+			plan.Moves = append(plan.Moves, PartitionMove{Topic: topic.Name, Partition: p.ID, From: p.Replicas, To: to})
+
+			if _, ok := plan.assignments[topic.Name]; !ok {
+				current := make([][]int32, len(topic.Partitions))
+				for _, tp := range topic.Partitions {
+					if int(tp.ID) < len(current) {
+						current[tp.ID] = tp.Replicas
+					}
+				}
+				plan.assignments[topic.Name] = current
+			}
+		}
+	}
+
+	if len(stuck) > 0 {
+		return nil, fmt.Errorf("no other broker is free to take a replica of %d partitions (%s); add a broker or lower their replication factor first",
+			len(stuck), strings.Join(stuck, ", "))
+	}
+	return plan, nil
+}
+
+// betterTarget reports whether candidate beats current as the new home of a
+// replica leaving a broker in rack
+func betterTarget(candidate, current int32, rack string, racks map[int32]string, load map[int32]int) bool {
+	if rack != "" {
+		candidateSame, currentSame := racks[candidate] == rack, racks[current] == rack
+		if candidateSame != currentSame {
+			return candidateSame
+		}
+	}
+	if load[candidate] != load[current] {
+		return load[candidate] < load[current]
+	}
+	return candidate < current
+}
+
+func containsBroker(replicas []int32, id int32) bool {
+	for _, r := range replicas {
+		if r == id {
+			return true
+		}
+	}
+	return false
+}
+
+// StartReassignment submits the plan to the controller. A positive throttle
+// caps the replication traffic of the moves in bytes/sec. Topics that are
+// already being reassigned are refused, since resubmitting their partitions
+// would replace the running reassignment.
+func (c *Client) StartReassignment(plan *ReassignmentPlan, throttle int64) (err error) {
+	_, span := startSpan(context.Background(), "AlterPartitionReassignments",
+		attribute.Int("partitions", len(plan.Moves)), attribute.Int64("throttle", throttle))
+	defer func() { endSpan(span, err) }()
+
+	if len(plan.Moves) == 0 {
+		return nil
+	}
+
+	ongoing, err := c.ongoingReassignments(plan)
+	if err != nil {
+		return err
+	}
+	if len(ongoing) > 0 {
+		return fmt.Errorf("topics already being reassigned: %s", strings.Join(ongoing, ", "))
+	}
+
+	if throttle > 0 {
+		if err := c.SetReplicationThrottle(throttle, plan.Moves); err != nil {
+			// Part of the throttle may have been set before the failure
+			if cerr := c.ClearReplicationThrottle(plan.Moves); cerr != nil {
+				return fmt.Errorf("%w (%v)", err, cerr)
+			}
+			return err
+		}
+	}
+
+	targets := make(map[string][][]int32, len(plan.assignments))
+	for topic, current := range plan.assignments {
+		assignment := make([][]int32, len(current))
+		copy(assignment, current)
+		targets[topic] = assignment
+	}
+	for _, move := range plan.Moves {
+		targets[move.Topic][move.Partition] = move.To
+	}
+
+	var failed, failedTopics []string
+	for _, topic := range plan.Topics() {
+		if err := c.admin.AlterPartitionReassignments(topic, targets[topic]); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", topic, err))
+			failedTopics = append(failedTopics, topic)
+		}
+	}
+	if throttle > 0 && len(failedTopics) > 0 {
+		// Topics that did not start do not need throttling. The broker rates
+		// stay while any other topic is moving.
+		var brokers []int32
+		if len(failedTopics) == len(plan.Topics()) {
+			brokers, _, _ = throttleTargets(plan.Moves)
+		}
+		if err := c.clearThrottles(brokers, failedTopics); err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+	moves := make([]string, len(plan.Moves))
+	for i, move := range plan.Moves {
+		moves[i] = move.String()
+	}
+	if len(failed) > 0 {
+		err = fmt.Errorf("failed to reassign %d topics: %s", len(failed), strings.Join(failed, "; "))
+	}
+	c.audit("partitions.reassign", "cluster", nil, moves, err)
+	if err != nil {
+		return err
+	}
+
+	logger.Get().WithField("partitions", len(plan.Moves)).WithField("throttle", throttle).Info("Started partition reassignment")
+	return nil
+}
+
+// ongoingReassignments lists the plan's topics with a reassignment running
+func (c *Client) ongoingReassignments(plan *ReassignmentPlan) ([]string, error) {
+	var ongoing []string
+	for _, topic := range plan.Topics() {
+		status, err := c.reassigningPartitions(topic, len(plan.assignments[topic]))
+		if err != nil {
+			return nil, err
+		}
+		if len(status) > 0 {
+			ongoing = append(ongoing, topic)
+		}
+	}
+	return ongoing, nil
+}
+
+// reassigningPartitions returns the partitions of topic being reassigned
+func (c *Client) reassigningPartitions(topic string, partitions int) (map[int32]*sarama.PartitionReplicaReassignmentsStatus, error) {
+	ids := make([]int32, partitions)
+	for i := range ids {
+		ids[i] = int32(i)
+	}
+	status, err := c.admin.ListPartitionReassignments(topic, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reassignments of %s: %w", topic, err)
+	}
+	return status[topic], nil
+}
+
+// DecommissionProgress reports how much of a decommission plan is left
+func (c *Client) DecommissionProgress(brokerID int32, plan *ReassignmentPlan) (_ ReassignmentProgress, err error) {
+	_, span := startSpan(context.Background(), "DecommissionProgress", attribute.Int("broker", int(brokerID)))
+	defer func() { endSpan(span, err) }()
+
+	var progress ReassignmentProgress
+	for _, topic := range plan.Topics() {
+		status, err := c.reassigningPartitions(topic, len(plan.assignments[topic]))
+		if err != nil {
+			return progress, err
+		}
+		for _, move := range plan.Moves {
+			if move.Topic == topic && status[move.Partition] != nil {
+				progress.Moving++
+			}
+		}
+	}
+
+	metadata, err := c.controllerMetadata()
+	if err != nil {
+		return progress, err
+	}
+	for _, topic := range metadata.Topics {
+		for _, p := range topic.Partitions {
+			if containsBroker(p.Replicas, brokerID) {
+				progress.Remaining++
+			}
+		}
+	}
+	return progress, nil
+}
@@ -0,0 +1,145 @@
+package kafka
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/IBM/sarama"
+)
+
+func TestPlanDecommission(t *testing.T) {
+	topics := []*sarama.TopicMetadata{
+		{Name: "orders", Partitions: []*sarama.PartitionMetadata{
+			{ID: 0, Leader: 1, Replicas: []int32{1, 2}},
+			{ID: 1, Leader: 2, Replicas: []int32{2, 3}},
+			{ID: 2, Leader: 3, Replicas: []int32{3, 1}},
+		}},
+		{Name: "audit", Partitions: []*sarama.PartitionMetadata{
+			{ID: 0, Leader: 4, Replicas: []int32{4}},
+		}},
+	}
+
+	t.Run("prefers the same rack", func(t *testing.T) {
+		racks := map[int32]string{1: "a", 2: "b", 3: "c", 4: "a"}
+		plan, err := planDecommission(1, racks, topics)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []PartitionMove{
+			{Topic: "orders", Partition: 0, From: []int32{1, 2}, To: []int32{4, 2}},
+			{Topic: "orders", Partition: 2, From: []int32{3, 1}, To: []int32{3, 4}},
+		}
+		if !reflect.DeepEqual(plan.Moves, want) {
+			t.Errorf("moves = %v, want %v", plan.Moves, want)
+		}
+		if !reflect.DeepEqual(plan.Topics(), []string{"orders"}) {
+			t.Errorf("topics = %v", plan.Topics())
+		}
+	})
+
+	t.Run("spreads over the least loaded brokers", func(t *testing.T) {
+		racks := map[int32]string{1: "", 2: "", 3: "", 4: ""}
+		plan, err := planDecommission(1, racks, topics)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Broker 4 holds one replica against two on 2 and 3, then takes
+		// both moves since neither of its partitions is already there
+		for _, move := range plan.Moves {
+			for _, r := range move.To {
+				if r == 1 {
+					t.Errorf("%v still uses the decommissioned broker", move)
+				}
+			}
+		}
+		if got := plan.Moves[0].To; !reflect.DeepEqual(got, []int32{4, 2}) {
+			t.Errorf("first move = %v", got)
+		}
+	})
+
+	t.Run("no free broker", func(t *testing.T) {
+		racks := map[int32]string{1: "", 2: ""}
+		_, err := planDecommission(1, racks, topics[:1])
+		if err == nil || !strings.Contains(err.Error(), "orders-0") {
+			t.Errorf("err = %v, want orders-0 reported", err)
+		}
+	})
+}
+
+// fakeReassignAdmin fails the reassignment of some topics and records the
+// throttle configs set and deleted
+type fakeReassignAdmin struct {
+	sarama.ClusterAdmin
+	failTopics map[string]bool
+	throttled  map[string]bool // "broker 1", "topic orders"
+}
+
+func (f *fakeReassignAdmin) ListPartitionReassignments(topic string, partitions []int32) (map[string]map[int32]*sarama.PartitionReplicaReassignmentsStatus, error) {
+	return nil, nil
+}
+
+func (f *fakeReassignAdmin) AlterPartitionReassignments(topic string, assignment [][]int32) error {
+	if f.failTopics[topic] {
+		return errors.New("not controller")
+	}
+	return nil
+}
+
+func (f *fakeReassignAdmin) IncrementalAlterConfig(resourceType sarama.ConfigResourceType, name string, entries map[string]sarama.IncrementalAlterConfigsEntry, validateOnly bool) error {
+	key := "topic " + name
+	if resourceType == sarama.BrokerResource {
+		key = "broker " + name
+	}
+	for _, entry := range entries {
+		f.throttled[key] = entry.Operation == sarama.IncrementalAlterConfigsOperationSet
+	}
+	return nil
+}
+
+func TestStartReassignmentClearsThrottleOnFailure(t *testing.T) {
+	topics := []*sarama.TopicMetadata{
+		{Name: "orders", Partitions: []*sarama.PartitionMetadata{{ID: 0, Leader: 1, Replicas: []int32{1, 2}}}},
+		{Name: "audit", Partitions: []*sarama.PartitionMetadata{{ID: 0, Leader: 1, Replicas: []int32{1, 3}}}},
+	}
+	plan, err := planDecommission(1, map[int32]string{1: "", 2: "", 3: "", 4: ""}, topics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	throttled := func(admin *fakeReassignAdmin) []string {
+		var on []string
+		for key, set := range admin.throttled {
+			if set {
+				on = append(on, key)
+			}
+		}
+		sort.Strings(on)
+		return on
+	}
+
+	t.Run("every topic fails", func(t *testing.T) {
+		admin := &fakeReassignAdmin{failTopics: map[string]bool{"orders": true, "audit": true}, throttled: map[string]bool{}}
+		c := &Client{admin: admin}
+		if err := c.StartReassignment(plan, 1024); err == nil {
+			t.Fatal("expected an error")
+		}
+		if on := throttled(admin); len(on) != 0 {
+			t.Errorf("throttle left on %v", on)
+		}
+	})
+
+	t.Run("one topic fails", func(t *testing.T) {
+		admin := &fakeReassignAdmin{failTopics: map[string]bool{"audit": true}, throttled: map[string]bool{}}
+		c := &Client{admin: admin}
+		if err := c.StartReassignment(plan, 1024); err == nil {
+			t.Fatal("expected an error")
+		}
+		// orders is moving, so its throttle and the broker rates stay
+		want := []string{"broker 1", "broker 2", "broker 3", "broker 4", "topic orders"}
+		if on := throttled(admin); !reflect.DeepEqual(on, want) {
+			t.Errorf("throttled = %v, want %v", on, want)
+		}
+	})
+}
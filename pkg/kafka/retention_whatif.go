@@ -0,0 +1,266 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Broker defaults used when a topic does not override them
+const (
+	defaultRetentionMs  = 7 * 24 * 60 * 60 * 1000
+	defaultSegmentBytes = 1 << 30
+)
+
+// RetentionSettings are the topic configs that bound how much data a topic
+// keeps. -1 means unlimited.
+type RetentionSettings struct {
+	RetentionMs    int64
+	RetentionBytes int64 // Per partition
+	SegmentBytes   int64
+}
+
+// RetentionSettingsFromConfigs reads the retention settings out of a topic's
+// configs, falling back to the broker defaults for missing or invalid values
+func RetentionSettingsFromConfigs(configs map[string]string) RetentionSettings {
+	get := func(key string, fallback int64) int64 {
+		if v, err := strconv.ParseInt(configs[key], 10, 64); err == nil {
+			return v
+		}
+		return fallback
+	}
+	return RetentionSettings{
+		RetentionMs:    get("retention.ms", defaultRetentionMs),
+		RetentionBytes: get("retention.bytes", -1),
+		SegmentBytes:   get("segment.bytes", defaultSegmentBytes),
+	}
+}
+
+// PartitionFootprint is how much data one partition holds and how fast it
+// grows
+type PartitionFootprint struct {
+	Partition int32
+	Replicas  int
+	Size      int64 // Bytes of the largest replica
+	LogStart  int64
+	LogEnd    int64
+	LastHour  int64 // Records appended in the last hour
+	// First offset still within the proposed retention.ms, LogStart when
+	// nothing is older
+	RetainedFrom int64
+}
+
+// records returns how many records the partition holds
+func (p PartitionFootprint) records() int64 {
+	return max(p.LogEnd-p.LogStart, 0)
+}
+
+// recordSize returns the average bytes per record, 0 for an empty partition
+func (p PartitionFootprint) recordSize() float64 {
+	if p.records() == 0 {
+		return 0
+	}
+	return float64(p.Size) / float64(p.records())
+}
+
+// bytesPerSecond estimates the produce rate from the last hour's records
+func (p PartitionFootprint) bytesPerSecond() float64 {
+	return float64(p.LastHour) * p.recordSize() / time.Hour.Seconds()
+}
+
+// TopicFootprint is the data a topic holds, per partition
+type TopicFootprint struct {
+	Topic      string
+	Partitions []PartitionFootprint
+}
+
+// GetTopicFootprint measures each partition's size and produce rate, and
+// finds the offsets a retention.ms of retentionMs would keep. A retentionMs
+// of -1 or 0 keeps everything.
+func (c *Client) GetTopicFootprint(topic string, retentionMs int64) (_ *TopicFootprint, err error) {
+	_, span := startSpan(context.Background(), "GetTopicFootprint", attribute.String("topic", topic))
+	defer func() { endSpan(span, err) }()
+
+	details, err := c.GetTopicPartitions(topic)
+	if err != nil {
+		return nil, err
+	}
+	brokerSet := map[int32]bool{}
+	for _, d := range details {
+		for _, id := range d.Replicas {
+			brokerSet[id] = true
+		}
+	}
+	brokerIDs := make([]int32, 0, len(brokerSet))
+	for id := range brokerSet {
+		brokerIDs = append(brokerIDs, id)
+	}
+	logDirs, err := c.admin.DescribeLogDirs(brokerIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe log dirs: %w", err)
+	}
+	sizes := replicaSizes(logDirs, topic)
+
+	client, err := sarama.NewClient(c.brokers, c.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	defer func() {
+		if closeErr := client.Close(); closeErr != nil {
+			logger.Get().WithError(closeErr).Warn("Failed to close footprint client")
+		}
+	}()
+
+	now := time.Now()
+	hourAgo, err := leaderOffsets(client, []string{topic}, now.Add(-time.Hour).UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	var retained map[string]map[int32]int64
+	if retentionMs > 0 {
+		if retained, err = leaderOffsets(client, []string{topic}, now.Add(-time.Duration(retentionMs)*time.Millisecond).UnixMilli()); err != nil {
+			return nil, err
+		}
+	}
+
+	footprint := &TopicFootprint{Topic: topic}
+	for _, d := range details {
+		if d.LogStart < 0 {
+			continue
+		}
+		p := PartitionFootprint{
+			Partition:    d.ID,
+			Replicas:     len(d.Replicas),
+			Size:         sizes[d.ID],
+			LogStart:     d.LogStart,
+			LogEnd:       d.LogEnd,
+			RetainedFrom: d.LogStart,
+		}
+		p.LastHour = d.LogEnd - clampOffset(hourAgo[topic], d)
+		if retained != nil {
+			p.RetainedFrom = clampOffset(retained[topic], d)
+		}
+		footprint.Partitions = append(footprint.Partitions, p)
+	}
+	sort.Slice(footprint.Partitions, func(i, j int) bool {
+		return footprint.Partitions[i].Partition < footprint.Partitions[j].Partition
+	})
+	return footprint, nil
+}
+
+// clampOffset keeps an offset looked up by timestamp within the partition's
+// log. -1 means every record is older, i.e. the log end.
+func clampOffset(offsets map[int32]int64, d PartitionDetail) int64 {
+	offset, ok := offsets[d.ID]
+	if !ok || offset < 0 {
+		return d.LogEnd
+	}
+	return min(max(offset, d.LogStart), d.LogEnd)
+}
+
+// replicaSizes returns the size of the largest replica of each partition of
+// topic. Future replicas being moved between log dirs are left out.
+func replicaSizes(logDirs map[int32][]sarama.DescribeLogDirsResponseDirMetadata, topic string) map[int32]int64 {
+	sizes := map[int32]int64{}
+	for _, dirs := range logDirs {
+		for _, dir := range dirs {
+			for _, t := range dir.Topics {
+				if t.Topic != topic {
+					continue
+				}
+				for _, p := range t.Partitions {
+					if !p.IsTemporary && p.Size > sizes[p.PartitionID] {
+						sizes[p.PartitionID] = p.Size
+					}
+				}
+			}
+		}
+	}
+	return sizes
+}
+
+// RetentionProjection compares the disk use of a topic under its current and
+// proposed retention settings. Sizes are estimates: rates come from the last
+// hour and Kafka deletes whole segments only.
+type RetentionProjection struct {
+	BytesPerSecond float64 // Produce rate over the last hour, one replica
+	Size           int64   // Bytes now, one replica per partition
+	DiskBytes      int64   // Bytes now, all replicas
+	// Steady-state disk use of all replicas, -1 when it grows without bound
+	Current   int64
+	Projected int64
+	// DailyGrowth is how much disk use grows per day while unbounded
+	DailyGrowth int64
+	// Data the proposed settings put past retention, which the broker
+	// deletes at its next retention check
+	DeletedBytes       int64
+	DeletedRecords     int64
+	AffectedPartitions int
+}
+
+// ProjectRetention estimates disk use under the current and proposed
+// settings and how much data the proposed ones would delete straight away.
+// The footprint must have been measured for proposed.RetentionMs.
+func ProjectRetention(f *TopicFootprint, current, proposed RetentionSettings) RetentionProjection {
+	var proj RetentionProjection
+	for _, p := range f.Partitions {
+		proj.BytesPerSecond += p.bytesPerSecond()
+		proj.Size += p.Size
+		proj.DiskBytes += p.Size * int64(p.Replicas)
+		proj.DailyGrowth += int64(p.bytesPerSecond() * (24 * time.Hour).Seconds() * float64(p.Replicas))
+
+		deleted := int64(float64(p.RetainedFrom-p.LogStart) * p.recordSize())
+		if proposed.RetentionBytes >= 0 && p.Size-proposed.RetentionBytes > deleted {
+			deleted = p.Size - proposed.RetentionBytes
+		}
+		if deleted > 0 {
+			proj.DeletedBytes += deleted
+			if size := p.recordSize(); size > 0 {
+				proj.DeletedRecords += int64(math.Ceil(float64(deleted) / size))
+			}
+			proj.AffectedPartitions++
+		}
+	}
+	proj.Current = steadyStateBytes(f, current)
+	proj.Projected = steadyStateBytes(f, proposed)
+	return proj
+}
+
+// steadyStateBytes estimates the disk use of all replicas once retention has
+// caught up with the produce rate: the data within retention plus up to a
+// segment of expired data waiting for its segment to be deleted. It returns
+// -1 when nothing bounds a growing topic.
+func steadyStateBytes(f *TopicFootprint, s RetentionSettings) int64 {
+	var total int64
+	for _, p := range f.Partitions {
+		rate := p.bytesPerSecond()
+		limit := math.Inf(1)
+		if s.RetentionMs > 0 {
+			limit = rate * float64(s.RetentionMs) / 1000
+		}
+		if s.RetentionBytes >= 0 {
+			limit = min(limit, float64(s.RetentionBytes))
+		}
+		if math.IsInf(limit, 1) {
+			if rate > 0 {
+				return -1
+			}
+			// Nothing expires and nothing is written
+			total += p.Size * int64(p.Replicas)
+			continue
+		}
+		slack := limit
+		if s.SegmentBytes > 0 {
+			slack = min(slack, float64(s.SegmentBytes))
+		}
+		total += int64(limit+slack) * int64(p.Replicas)
+	}
+	return total
+}
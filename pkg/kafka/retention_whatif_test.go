@@ -0,0 +1,70 @@
+package kafka
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/IBM/sarama"
+)
+
+func TestRetentionSettingsFromConfigs(t *testing.T) {
+	got := RetentionSettingsFromConfigs(map[string]string{"retention.ms": "3600000", "segment.bytes": "oops"})
+	want := RetentionSettings{RetentionMs: 3600000, RetentionBytes: -1, SegmentBytes: defaultSegmentBytes}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestReplicaSizes(t *testing.T) {
+	logDirs := map[int32][]sarama.DescribeLogDirsResponseDirMetadata{
+		1: {{Topics: []sarama.DescribeLogDirsResponseTopic{
+			{Topic: "orders", Partitions: []sarama.DescribeLogDirsResponsePartition{{PartitionID: 0, Size: 100}, {PartitionID: 1, Size: 50}}},
+			{Topic: "other", Partitions: []sarama.DescribeLogDirsResponsePartition{{PartitionID: 0, Size: 999}}},
+		}}},
+		2: {{Topics: []sarama.DescribeLogDirsResponseTopic{
+			{Topic: "orders", Partitions: []sarama.DescribeLogDirsResponsePartition{{PartitionID: 0, Size: 90}, {PartitionID: 1, Size: 500, IsTemporary: true}}},
+		}}},
+	}
+	want := map[int32]int64{0: 100, 1: 50}
+	if got := replicaSizes(logDirs, "orders"); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestProjectRetention(t *testing.T) {
+	// 3600 records of 1000 bytes in the last hour, i.e. 1000 bytes/s per
+	// partition, with 7200 records in the log
+	partition := func(id int32, retainedFrom int64) PartitionFootprint {
+		return PartitionFootprint{Partition: id, Replicas: 3, Size: 7_200_000, LogStart: 0, LogEnd: 7200, LastHour: 3600, RetainedFrom: retainedFrom}
+	}
+	f := &TopicFootprint{Topic: "orders", Partitions: []PartitionFootprint{partition(0, 3600), partition(1, 0)}}
+	current := RetentionSettings{RetentionMs: 2 * 3600 * 1000, RetentionBytes: -1, SegmentBytes: 1_000_000}
+	proposed := RetentionSettings{RetentionMs: 3600 * 1000, RetentionBytes: 5_000_000, SegmentBytes: 1_000_000}
+
+	got := ProjectRetention(f, current, proposed)
+	want := RetentionProjection{
+		BytesPerSecond: 2000,
+		Size:           14_400_000,
+		DiskBytes:      43_200_000,
+		// 7.2 MB retained plus a 1 MB segment, per replica
+		Current: 2 * 3 * 8_200_000,
+		// An hour is 3.6 MB, under the 5 MB retention.bytes
+		Projected:   2 * 3 * 4_600_000,
+		DailyGrowth: 2 * 3 * 86_400_000,
+		// Partition 0 loses its older half to retention.ms, partition 1
+		// what exceeds retention.bytes
+		DeletedBytes:       3_600_000 + 2_200_000,
+		DeletedRecords:     3600 + 2200,
+		AffectedPartitions: 2,
+	}
+	if got != want {
+		t.Errorf("got  %+v\nwant %+v", got, want)
+	}
+
+	// Measured without a retention.ms, so everything is retained
+	f = &TopicFootprint{Topic: "orders", Partitions: []PartitionFootprint{partition(0, 0), partition(1, 0)}}
+	unlimited := RetentionSettings{RetentionMs: -1, RetentionBytes: -1, SegmentBytes: 1_000_000}
+	if got := ProjectRetention(f, current, unlimited); got.Projected != -1 || got.DeletedBytes != 0 {
+		t.Errorf("unlimited retention: got %+v, want unbounded and nothing deleted", got)
+	}
+}
@@ -0,0 +1,157 @@
+package kafka
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/digitalis-io/kconduit/pkg/logger"
+	"gopkg.in/yaml.v3"
+)
+
+// SessionScript is a recorded session: the changes made through the client,
+// in order, so they can be replayed on another cluster
+type SessionScript struct {
+	Recorded time.Time `yaml:"recorded"`
+	Cluster  string    `yaml:"cluster"` // Brokers the session was recorded on
+	Changes  []Change  `yaml:"changes"`
+}
+
+// SessionRecorder appends every change to a script file, rewriting it after
+// each one so an interrupted session keeps what was done. It is safe for
+// concurrent use.
+type SessionRecorder struct {
+	mu     sync.Mutex
+	path   string
+	script SessionScript
+}
+
+// NewSessionRecorder creates the script file at path, replacing any
+// existing one
+func NewSessionRecorder(path string) (*SessionRecorder, error) {
+	r := &SessionRecorder{path: path, script: SessionScript{Recorded: time.Now().UTC(), Changes: []Change{}}}
+	if err := r.write(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Len returns how many changes were recorded
+func (r *SessionRecorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.script.Changes)
+}
+
+// Path returns the script file
+func (r *SessionRecorder) Path() string {
+	return r.path
+}
+
+func (r *SessionRecorder) record(ch Change) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.script.Changes = append(r.script.Changes, ch)
+	if err := r.write(); err != nil {
+		logger.Get().WithError(err).Error("Failed to write session script")
+	}
+}
+
+func (r *SessionRecorder) write() error {
+	data, err := yaml.Marshal(r.script)
+	if err != nil {
+		return fmt.Errorf("failed to encode session script: %w", err)
+	}
+	if err := os.WriteFile(r.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write session script: %w", err)
+	}
+	return nil
+}
+
+// RecordSession records every later change made through c and its clones,
+// including undos and AI plans, to r
+func (c *Client) RecordSession(r *SessionRecorder) {
+	r.mu.Lock()
+	r.script.Cluster = strings.Join(c.brokers, ",")
+	r.mu.Unlock()
+	c.session = r
+}
+
+func (c *Client) recordSession(ch Change) {
+	if c.session != nil {
+		c.session.record(ch)
+	}
+}
+
+// LoadSessionScript reads a script written by a SessionRecorder
+func LoadSessionScript(path string) (*SessionScript, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session script: %w", err)
+	}
+	var script SessionScript
+	if err := yaml.Unmarshal(data, &script); err != nil {
+		return nil, fmt.Errorf("failed to parse session script: %w", err)
+	}
+	for i, ch := range script.Changes {
+		if err := ch.validate(); err != nil {
+			return nil, fmt.Errorf("change %d: %w", i+1, err)
+		}
+	}
+	return &script, nil
+}
+
+// validate checks a change has what replaying it needs
+func (ch Change) validate() error {
+	switch ch.Kind {
+	case ChangeTopicCreate, ChangeTopicDelete:
+	case ChangeTopicConfig:
+		if ch.Key == "" {
+			return fmt.Errorf("%s needs a key", ch.Kind)
+		}
+	case ChangeTopicPartitions:
+		if _, err := strconv.ParseInt(ch.Value, 10, 32); err != nil {
+			return fmt.Errorf("%s needs the partition count as value", ch.Kind)
+		}
+	case ChangeACLCreate, ChangeACLDelete:
+		if ch.ACL.Principal == "" || ch.ACL.ResourceType == "" {
+			return fmt.Errorf("%s needs an acl", ch.Kind)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown action %q", ch.Kind)
+	}
+	if ch.Topic == "" {
+		return fmt.Errorf("%s needs a topic", ch.Kind)
+	}
+	return nil
+}
+
+// Replay makes a recorded change again
+func (c *Client) Replay(ch Change) error {
+	switch ch.Kind {
+	case ChangeTopicCreate:
+		if len(ch.Configs) > 0 {
+			return c.createTopicLike(TopicSnapshot{Name: ch.Topic, Partitions: ch.Partitions, ReplicationFactor: ch.ReplicationFactor, Configs: ch.Configs})
+		}
+		return c.CreateTopic(ch.Topic, ch.Partitions, ch.ReplicationFactor)
+	case ChangeTopicDelete:
+		return c.DeleteTopic(ch.Topic)
+	case ChangeTopicConfig:
+		return c.UpdateTopicConfig(ch.Topic, ch.Key, ch.Value)
+	case ChangeTopicPartitions:
+		partitions, err := strconv.ParseInt(ch.Value, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid partition count %q", ch.Value)
+		}
+		return c.ModifyTopicPartitions(ch.Topic, int32(partitions))
+	case ChangeACLCreate:
+		return c.CreateACL(ch.ACL)
+	case ChangeACLDelete:
+		return c.DeleteACL(ch.ACL)
+	}
+	return fmt.Errorf("unknown change %s", ch.Kind)
+}
@@ -0,0 +1,68 @@
+package kafka
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSessionRecorderRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.yaml")
+	r, err := NewSessionRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{brokers: []string{"staging:9092"}, journal: NewJournal()}
+	c.RecordSession(r)
+
+	changes := []Change{
+		{Kind: ChangeTopicCreate, Topic: "orders", Partitions: 6, ReplicationFactor: 3, Configs: map[string]string{"cleanup.policy": "compact"}},
+		{Kind: ChangeTopicConfig, Topic: "orders", Key: "retention.ms", Previous: "604800000", Value: "86400000"},
+		{Kind: ChangeTopicPartitions, Topic: "orders", Previous: "6", Value: "12"},
+		{Kind: ChangeACLCreate, ACL: ACL{Principal: "User:alice", Host: "*", Operation: "Read", PermissionType: "Allow", ResourceType: "Topic", ResourceName: "orders", PatternType: "Literal"}},
+	}
+	for _, ch := range changes {
+		c.recordChange(ch)
+	}
+	// Clones without a journal, as used by undo, are still recorded
+	undo := *c
+	undo.journal = nil
+	undo.recordChange(Change{Kind: ChangeTopicDelete, Topic: "orders"})
+	changes = append(changes, Change{Kind: ChangeTopicDelete, Topic: "orders"})
+
+	script, err := LoadSessionScript(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if script.Cluster != "staging:9092" || r.Len() != len(changes) {
+		t.Errorf("cluster = %q, recorded %d", script.Cluster, r.Len())
+	}
+	if !reflect.DeepEqual(script.Changes, changes) {
+		t.Errorf("changes = %+v, want %+v", script.Changes, changes)
+	}
+}
+
+func TestLoadSessionScriptValidates(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+	}{
+		{"unknown action", "changes:\n  - action: topic.rename\n    topic: a\n"},
+		{"missing topic", "changes:\n  - action: topic.delete\n"},
+		{"config without key", "changes:\n  - action: topic.config.update\n    topic: a\n"},
+		{"partitions without count", "changes:\n  - action: topic.partitions.update\n    topic: a\n"},
+		{"acl without principal", "changes:\n  - action: acl.create\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "session.yaml")
+			if err := os.WriteFile(path, []byte(tt.script), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadSessionScript(path); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
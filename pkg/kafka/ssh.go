@@ -0,0 +1,154 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+
+	"github.com/digitalis-io/kconduit/pkg/logger"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"golang.org/x/net/proxy"
+)
+
+// sshKeepAlive is how often an idle tunnel is pinged, so bastions that drop
+// quiet sessions keep it open
+const sshKeepAlive = 30 * time.Second
+
+// SSHConfig reaches brokers that are only accessible through a bastion host
+type SSHConfig struct {
+	Host                  string // Bastion as host or host:port, port 22 by default
+	User                  string // Defaults to the current user
+	KeyFile               string // Private key; the SSH agent and default keys are tried otherwise
+	KeyPassphrase         string
+	KnownHosts            string // Defaults to ~/.ssh/known_hosts
+	InsecureIgnoreHostKey bool   // Skip host key verification
+}
+
+// dialSSH connects to the bastion with dialer
+func dialSSH(cfg *SSHConfig, dialer proxy.Dialer) (*ssh.Client, error) {
+	log := logger.Get()
+
+	addr := cfg.Host
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "22")
+	}
+	username := cfg.User
+	if username == "" {
+		if u, err := user.Current(); err == nil {
+			username = u.Username
+		}
+	}
+
+	auth, err := sshAuthMethods(cfg)
+	if err != nil {
+		return nil, err
+	}
+	hostKeys, err := sshHostKeyCallback(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithFields(map[string]interface{}{"bastion": addr, "user": username}).Info("Opening SSH tunnel")
+	conn, err := dialer.Dial("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SSH bastion %s: %w", addr, err)
+	}
+	// The handshake gets the same time the dial would
+	_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, &ssh.ClientConfig{
+		User:            username,
+		Auth:            auth,
+		HostKeyCallback: hostKeys,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect to SSH bastion %s: %w", addr, err)
+	}
+	_ = conn.SetDeadline(time.Time{})
+	client := ssh.NewClient(sshConn, chans, reqs)
+
+	go func() {
+		ticker := time.NewTicker(sshKeepAlive)
+		defer ticker.Stop()
+		for range ticker.C {
+			if _, _, err := client.SendRequest("keepalive@openssh.com", true, nil); err != nil {
+				log.WithError(err).Debug("SSH tunnel closed")
+				return
+			}
+		}
+	}()
+	return client, nil
+}
+
+// sshAuthMethods offers the configured key, then the SSH agent, then the
+// unencrypted default keys in ~/.ssh
+func sshAuthMethods(cfg *SSHConfig) ([]ssh.AuthMethod, error) {
+	if cfg.KeyFile != "" {
+		signer, err := readSSHKey(cfg.KeyFile, cfg.KeyPassphrase)
+		if err != nil {
+			return nil, err
+		}
+		return []ssh.AuthMethod{ssh.PublicKeys(signer)}, nil
+	}
+
+	var methods []ssh.AuthMethod
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		}
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		var signers []ssh.Signer
+		for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+			if signer, err := readSSHKey(filepath.Join(home, ".ssh", name), ""); err == nil {
+				signers = append(signers, signer)
+			}
+		}
+		if len(signers) > 0 {
+			methods = append(methods, ssh.PublicKeys(signers...))
+		}
+	}
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("no SSH key found; set --ssh-key or start an SSH agent")
+	}
+	return methods, nil
+}
+
+func readSSHKey(path, passphrase string) (ssh.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SSH key: %w", err)
+	}
+	if passphrase != "" {
+		return ssh.ParsePrivateKeyWithPassphrase(data, []byte(passphrase))
+	}
+	signer, err := ssh.ParsePrivateKey(data)
+	if _, ok := err.(*ssh.PassphraseMissingError); ok {
+		return nil, fmt.Errorf("SSH key %s is encrypted; set --ssh-key-passphrase or add it to the SSH agent", path)
+	}
+	return signer, err
+}
+
+func sshHostKeyCallback(cfg *SSHConfig) (ssh.HostKeyCallback, error) {
+	if cfg.InsecureIgnoreHostKey {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+	path := cfg.KnownHosts
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	callback, err := knownhosts.New(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read known hosts: %w", err)
+	}
+	return callback, nil
+}
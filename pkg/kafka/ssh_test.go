@@ -0,0 +1,145 @@
+package kafka
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/pem"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"golang.org/x/crypto/ssh"
+)
+
+// startBastion runs an SSH server that accepts key and forwards direct-tcpip
+// channels, like a bastion host
+func startBastion(t *testing.T, key ssh.PublicKey) string {
+	t.Helper()
+	_, hostPriv, _ := ed25519.GenerateKey(rand.Reader)
+	hostSigner, err := ssh.NewSignerFromKey(hostPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, k ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(k.Marshal()) == string(key.Marshal()) {
+				return nil, nil
+			}
+			return nil, io.EOF
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, chans, reqs, err := ssh.NewServerConn(conn, config)
+				if err != nil {
+					return
+				}
+				go ssh.DiscardRequests(reqs)
+				for ch := range chans {
+					// direct-tcpip payload: host, port, origin host, origin port
+					data := ch.ExtraData()
+					n := binary.BigEndian.Uint32(data)
+					host := string(data[4 : 4+n])
+					port := binary.BigEndian.Uint32(data[4+n:])
+					target, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(port))))
+					if err != nil {
+						_ = ch.Reject(ssh.ConnectionFailed, err.Error())
+						continue
+					}
+					channel, reqs, _ := ch.Accept()
+					go ssh.DiscardRequests(reqs)
+					go func() { _, _ = io.Copy(channel, target); channel.Close() }()
+					go func() { _, _ = io.Copy(target, channel); target.Close() }()
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestSSHTunnel(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	sshPub, _ := ssh.NewPublicKey(pub)
+	bastion := startBastion(t, sshPub)
+
+	// A "broker" that echoes what it receives
+	broker, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer broker.Close()
+	go func() {
+		conn, err := broker.Accept()
+		if err == nil {
+			_, _ = io.Copy(conn, conn)
+		}
+	}()
+
+	config := sarama.NewConfig()
+	tunnel, err := configureNetwork(config, &NetworkConfig{SSH: &SSHConfig{
+		Host:                  bastion,
+		User:                  "kafka",
+		KeyFile:               keyFile,
+		InsecureIgnoreHostKey: true,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tunnel.Close()
+	if !config.Net.Proxy.Enable {
+		t.Fatal("the tunnel is not used to dial brokers")
+	}
+
+	conn, err := config.Net.Proxy.Dialer.Dial("tcp", broker.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, buf); err != nil || string(buf) != "ping" {
+		t.Errorf("read %q, %v through the tunnel, want ping", buf, err)
+	}
+
+	// An empty known_hosts file trusts nothing
+	knownHosts := filepath.Join(t.TempDir(), "known_hosts")
+	if err := os.WriteFile(knownHosts, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = configureNetwork(sarama.NewConfig(), &NetworkConfig{SSH: &SSHConfig{
+		Host:       bastion,
+		KeyFile:    keyFile,
+		KnownHosts: knownHosts,
+	}})
+	if err == nil || !strings.Contains(err.Error(), "key is unknown") {
+		t.Errorf("err = %v, want the unknown host key refused", err)
+	}
+}
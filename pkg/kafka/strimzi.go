@@ -0,0 +1,230 @@
+package kafka
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// strimziAPIVersion is the Strimzi custom resource version exported
+const strimziAPIVersion = "kafka.strimzi.io/v1beta2"
+
+// StrimziOptions places exported custom resources
+type StrimziOptions struct {
+	Kafka          string // Name of the Strimzi Kafka resource, set as the strimzi.io/cluster label
+	Namespace      string // Omitted when empty
+	Authentication string // KafkaUser authentication type: scram-sha-512, tls or tls-external; omitted when empty
+}
+
+type strimziMetadata struct {
+	Name      string            `yaml:"name"`
+	Namespace string            `yaml:"namespace,omitempty"`
+	Labels    map[string]string `yaml:"labels"`
+}
+
+type strimziResource struct {
+	APIVersion string          `yaml:"apiVersion"`
+	Kind       string          `yaml:"kind"`
+	Metadata   strimziMetadata `yaml:"metadata"`
+	Spec       any             `yaml:"spec"`
+}
+
+type strimziTopicSpec struct {
+	TopicName  string            `yaml:"topicName,omitempty"`
+	Partitions int32             `yaml:"partitions"`
+	Replicas   int16             `yaml:"replicas"`
+	Config     map[string]string `yaml:"config,omitempty"`
+}
+
+type strimziUserSpec struct {
+	Authentication *strimziAuthentication `yaml:"authentication,omitempty"`
+	Authorization  strimziAuthorization   `yaml:"authorization"`
+}
+
+type strimziAuthentication struct {
+	Type string `yaml:"type"`
+}
+
+type strimziAuthorization struct {
+	Type string       `yaml:"type"`
+	ACLs []strimziACL `yaml:"acls"`
+}
+
+type strimziACL struct {
+	Resource   strimziACLResource `yaml:"resource"`
+	Operations []string           `yaml:"operations"`
+	Host       string             `yaml:"host"`
+	Type       string             `yaml:"type"`
+}
+
+type strimziACLResource struct {
+	Type        string `yaml:"type"`
+	Name        string `yaml:"name,omitempty"`
+	PatternType string `yaml:"patternType,omitempty"`
+}
+
+// strimziResourceTypes maps Kafka ACL resource types to Strimzi's
+var strimziResourceTypes = map[string]string{
+	"Topic":           "topic",
+	"Group":           "group",
+	"Cluster":         "cluster",
+	"TransactionalId": "transactionalId",
+}
+
+// strimziPatternTypes maps Kafka ACL pattern types to those a KafkaUser
+// accepts
+var strimziPatternTypes = map[string]string{
+	"Literal":  "literal",
+	"Prefixed": "prefix",
+}
+
+var invalidResourceName = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// strimziName turns a Kafka name into a valid Kubernetes resource name.
+// Names that have to change get a hash of the original, as Strimzi's topic
+// operator does, so that different names never collide.
+func strimziName(name string) string {
+	sanitized := strings.Trim(invalidResourceName.ReplaceAllString(strings.ToLower(name), "-"), "-.")
+	if sanitized == name {
+		return name
+	}
+	sum := sha1.Sum([]byte(name))
+	if len(sanitized) > 200 {
+		sanitized = sanitized[:200]
+	}
+	return strings.TrimPrefix(sanitized+"---"+hex.EncodeToString(sum[:]), "---")
+}
+
+func (o StrimziOptions) metadata(name string) strimziMetadata {
+	return strimziMetadata{
+		Name:      name,
+		Namespace: o.Namespace,
+		Labels:    map[string]string{"strimzi.io/cluster": o.Kafka},
+	}
+}
+
+// StrimziTopics describes topics as KafkaTopic resources. Topics whose name
+// is not a valid resource name keep it in spec.topicName.
+func StrimziTopics(topics []TopicSnapshot, opts StrimziOptions) []any {
+	sorted := append([]TopicSnapshot(nil), topics...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	resources := make([]any, 0, len(sorted))
+	for _, t := range sorted {
+		name := strimziName(t.Name)
+		spec := strimziTopicSpec{Partitions: t.Partitions, Replicas: t.ReplicationFactor, Config: t.Configs}
+		if name != t.Name {
+			spec.TopicName = t.Name
+		}
+		resources = append(resources, strimziResource{
+			APIVersion: strimziAPIVersion,
+			Kind:       "KafkaTopic",
+			Metadata:   opts.metadata(name),
+			Spec:       spec,
+		})
+	}
+	return resources
+}
+
+// StrimziUsers describes the ACLs of each User principal as a KafkaUser
+// with simple authorization, merging the operations of ACLs on the same
+// resource. ACLs Strimzi cannot express, on other principal types or
+// resource types, are returned as skipped. Pattern types other than Literal
+// and Prefixed, such as the filter-only Match and Any, are an error.
+func StrimziUsers(acls []ACL, opts StrimziOptions) (resources []any, skipped []ACL, err error) {
+	type ruleKey struct {
+		resource strimziACLResource
+		host     string
+		kind     string
+	}
+	rules := make(map[string]map[ruleKey][]string)
+	for _, acl := range acls {
+		acl = normalizeACL(acl)
+		user, ok := strings.CutPrefix(acl.Principal, "User:")
+		resourceType, supported := strimziResourceTypes[acl.ResourceType]
+		if !ok || !supported || user == "" || user == "*" {
+			skipped = append(skipped, acl)
+			continue
+		}
+
+		resource := strimziACLResource{Type: resourceType}
+		if resourceType != "cluster" {
+			resource.Name = acl.ResourceName
+			patternType, ok := strimziPatternTypes[acl.PatternType]
+			if !ok {
+				return nil, nil, fmt.Errorf("ACL %s: pattern type %q has no KafkaUser equivalent", acl, acl.PatternType)
+			}
+			resource.PatternType = patternType
+		}
+		key := ruleKey{resource: resource, host: acl.Host, kind: strings.ToLower(acl.PermissionType)}
+		if rules[user] == nil {
+			rules[user] = make(map[ruleKey][]string)
+		}
+		rules[user][key] = append(rules[user][key], acl.Operation)
+	}
+
+	users := make([]string, 0, len(rules))
+	for user := range rules {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+
+	for _, user := range users {
+		var userACLs []strimziACL
+		for key, operations := range rules[user] {
+			sort.Strings(operations)
+			userACLs = append(userACLs, strimziACL{Resource: key.resource, Operations: operations, Host: key.host, Type: key.kind})
+		}
+		sort.Slice(userACLs, func(i, j int) bool {
+			a, b := userACLs[i], userACLs[j]
+			return fmt.Sprint(a.Resource, a.Type, a.Host) < fmt.Sprint(b.Resource, b.Type, b.Host)
+		})
+
+		spec := strimziUserSpec{Authorization: strimziAuthorization{Type: "simple", ACLs: userACLs}}
+		if opts.Authentication != "" {
+			spec.Authentication = &strimziAuthentication{Type: opts.Authentication}
+		}
+		resources = append(resources, strimziResource{
+			APIVersion: strimziAPIVersion,
+			Kind:       "KafkaUser",
+			Metadata:   opts.metadata(strimziUserName(user)),
+			Spec:       spec,
+		})
+	}
+	return resources, skipped, nil
+}
+
+// strimziUserName names the KafkaUser of a principal. Strimzi authenticates
+// TLS users as CN=<name>, so only the common name of a distinguished name is
+// kept.
+func strimziUserName(user string) string {
+	for _, part := range strings.Split(user, ",") {
+		if cn, ok := strings.CutPrefix(strings.TrimSpace(part), "CN="); ok {
+			return strimziName(cn)
+		}
+	}
+	return strimziName(user)
+}
+
+// MarshalStrimzi encodes resources as a multi-document YAML stream, ready
+// for kubectl apply -f
+func MarshalStrimzi(resources []any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	for _, r := range resources {
+		if err := enc.Encode(r); err != nil {
+			return nil, fmt.Errorf("failed to encode yaml: %w", err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode yaml: %w", err)
+	}
+	return buf.Bytes(), nil
+}
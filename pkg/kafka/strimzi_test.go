@@ -0,0 +1,70 @@
+package kafka
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStrimziName(t *testing.T) {
+	if got := strimziName("orders.v1"); got != "orders.v1" {
+		t.Errorf("strimziName(orders.v1) = %q, want it unchanged", got)
+	}
+	upper, lower := strimziName("Orders_V1"), strimziName("orders_v1")
+	if !strings.HasPrefix(upper, "orders-v1---") || upper == lower {
+		t.Errorf("strimziName() = %q and %q, want distinct sanitized names", upper, lower)
+	}
+}
+
+func TestMarshalStrimzi(t *testing.T) {
+	opts := StrimziOptions{Kafka: "my-cluster", Namespace: "kafka", Authentication: "scram-sha-512"}
+	topics := StrimziTopics([]TopicSnapshot{
+		{Name: "Payments_V2", Partitions: 3, ReplicationFactor: 3},
+		{Name: "orders", Partitions: 6, ReplicationFactor: 3, Configs: map[string]string{"retention.ms": "604800000"}},
+	}, opts)
+	users, skipped, err := StrimziUsers([]ACL{
+		{Principal: "User:alice", Operation: "Read", ResourceType: "Topic", ResourceName: "orders"},
+		{Principal: "User:alice", Operation: "Describe", ResourceType: "Topic", ResourceName: "orders"},
+		{Principal: "User:alice", Operation: "Read", ResourceType: "Group", ResourceName: "billing-", PatternType: "Prefixed"},
+		{Principal: "Group:ops", Operation: "All", ResourceType: "Cluster", ResourceName: "kafka-cluster"},
+	}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skipped) != 1 || skipped[0].Principal != "Group:ops" {
+		t.Errorf("skipped = %+v, want the Group:ops ACL", skipped)
+	}
+
+	data, err := MarshalStrimzi(append(topics, users...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for _, want := range []string{
+		"kind: KafkaTopic\nmetadata:\n  name: orders\n  namespace: kafka\n  labels:\n    strimzi.io/cluster: my-cluster\nspec:\n  partitions: 6",
+		"topicName: Payments_V2",
+		"retention.ms: \"604800000\"",
+		"kind: KafkaUser\nmetadata:\n  name: alice",
+		"authentication:\n    type: scram-sha-512",
+		"name: billing-\n          patternType: prefix\n        operations:\n          - Read",
+		"operations:\n          - Describe\n          - Read\n        host: '*'\n        type: allow",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "---\n"); n != 2 {
+		t.Errorf("got %d document separators, want 2:\n%s", n, out)
+	}
+	if !strings.Contains(out, "name: orders\n          patternType: literal") {
+		t.Errorf("literal ACL pattern type missing:\n%s", out)
+	}
+}
+
+func TestStrimziUsersRejectsFilterPatternTypes(t *testing.T) {
+	for _, patternType := range []string{"Match", "Any"} {
+		acl := ACL{Principal: "User:alice", Operation: "Read", ResourceType: "Topic", ResourceName: "orders", PatternType: patternType}
+		if _, _, err := StrimziUsers([]ACL{acl}, StrimziOptions{}); err == nil {
+			t.Errorf("pattern type %s was exported", patternType)
+		}
+	}
+}
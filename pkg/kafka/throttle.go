@@ -0,0 +1,314 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/IBM/sarama"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Broker and topic configs Kafka uses to throttle reassignment traffic
+const (
+	leaderThrottleRate        = "leader.replication.throttled.rate"
+	followerThrottleRate      = "follower.replication.throttled.rate"
+	leaderThrottledReplicas   = "leader.replication.throttled.replicas"
+	followerThrottledReplicas = "follower.replication.throttled.replicas"
+)
+
+// throttleTargets works out which configs throttle a set of moves: the rate
+// on every broker involved, the existing replicas of each partition as
+// throttled leaders and the new ones as throttled followers
+func throttleTargets(moves []PartitionMove) (brokers []int32, leaders, followers map[string]string) {
+	involved := make(map[int32]bool)
+	leaderReplicas := make(map[string][]string)
+	followerReplicas := make(map[string][]string)
+	for _, move := range moves {
+		for _, r := range move.From {
+			involved[r] = true
+			leaderReplicas[move.Topic] = append(leaderReplicas[move.Topic], fmt.Sprintf("%d:%d", move.Partition, r))
+		}
+		for _, r := range move.To {
+			involved[r] = true
+			if !containsBroker(move.From, r) {
+				followerReplicas[move.Topic] = append(followerReplicas[move.Topic], fmt.Sprintf("%d:%d", move.Partition, r))
+			}
+		}
+	}
+
+	for id := range involved {
+		brokers = append(brokers, id)
+	}
+	sort.Slice(brokers, func(i, j int) bool { return brokers[i] < brokers[j] })
+
+	join := func(replicas map[string][]string) map[string]string {
+		joined := make(map[string]string, len(replicas))
+		for topic, list := range replicas {
+			joined[topic] = strings.Join(list, ",")
+		}
+		return joined
+	}
+	return brokers, join(leaderReplicas), join(followerReplicas)
+}
+
+// SetReplicationThrottle limits the replication traffic of the given moves
+// to rate bytes/sec on every broker they touch
+func (c *Client) SetReplicationThrottle(rate int64, moves []PartitionMove) (err error) {
+	_, span := startSpan(context.Background(), "SetReplicationThrottle", attribute.Int64("rate", rate))
+	defer func() { endSpan(span, err) }()
+
+	if rate <= 0 {
+		return fmt.Errorf("throttle rate must be positive")
+	}
+	brokers, leaders, followers := throttleTargets(moves)
+	value := strconv.FormatInt(rate, 10)
+	set := func(v string) sarama.IncrementalAlterConfigsEntry {
+		return sarama.IncrementalAlterConfigsEntry{Operation: sarama.IncrementalAlterConfigsOperationSet, Value: &v}
+	}
+
+	for _, id := range brokers {
+		entries := map[string]sarama.IncrementalAlterConfigsEntry{
+			leaderThrottleRate:   set(value),
+			followerThrottleRate: set(value),
+		}
+		if err := c.admin.IncrementalAlterConfig(sarama.BrokerResource, strconv.Itoa(int(id)), entries, false); err != nil {
+			return fmt.Errorf("failed to throttle broker %d: %w", id, err)
+		}
+	}
+	for topic := range leaders {
+		entries := map[string]sarama.IncrementalAlterConfigsEntry{leaderThrottledReplicas: set(leaders[topic])}
+		if f, ok := followers[topic]; ok {
+			entries[followerThrottledReplicas] = set(f)
+		}
+		if err := c.admin.IncrementalAlterConfig(sarama.TopicResource, topic, entries, false); err != nil {
+			return fmt.Errorf("failed to throttle replicas of %s: %w", topic, err)
+		}
+	}
+
+	c.audit("replication.throttle.set", "cluster", nil, map[string]any{"rate": rate, "brokers": brokers}, nil)
+	logger.Get().WithField("rate", rate).WithField("brokers", brokers).Info("Set replication throttle")
+	return nil
+}
+
+// ClearReplicationThrottle removes the throttle set for the given moves
+func (c *Client) ClearReplicationThrottle(moves []PartitionMove) (err error) {
+	_, span := startSpan(context.Background(), "ClearReplicationThrottle")
+	defer func() { endSpan(span, err) }()
+
+	brokers, leaders, _ := throttleTargets(moves)
+	topics := make([]string, 0, len(leaders))
+	for topic := range leaders {
+		topics = append(topics, topic)
+	}
+	return c.clearThrottles(brokers, topics)
+}
+
+// BrokerThrottle is the replication rate limit of one broker in bytes/sec,
+// 0 when unset
+type BrokerThrottle struct {
+	BrokerID int32
+	Leader   int64
+	Follower int64
+}
+
+// TopicThrottle lists the replicas of a topic the throttle applies to, as
+// "partition:broker" pairs or "*" for all of them
+type TopicThrottle struct {
+	Topic     string
+	Leaders   string
+	Followers string
+}
+
+// ReplicationThrottles is every replication throttle set in the cluster
+type ReplicationThrottles struct {
+	Brokers []BrokerThrottle
+	Topics  []TopicThrottle
+}
+
+// Active reports whether any throttle is set
+func (t *ReplicationThrottles) Active() bool {
+	for _, b := range t.Brokers {
+		if b.Leader > 0 || b.Follower > 0 {
+			return true
+		}
+	}
+	return len(t.Topics) > 0
+}
+
+// GetReplicationThrottles reads the throttle rates of every broker and the
+// throttled replicas of every topic
+func (c *Client) GetReplicationThrottles() (_ *ReplicationThrottles, err error) {
+	_, span := startSpan(context.Background(), "GetReplicationThrottles")
+	defer func() { endSpan(span, err) }()
+
+	brokers, err := c.GetBrokers()
+	if err != nil {
+		return nil, err
+	}
+	throttles := &ReplicationThrottles{}
+	for _, b := range brokers {
+		if b.Status != BrokerOnline {
+			// Its configs can only be described by the broker itself
+			continue
+		}
+		entries, err := c.admin.DescribeConfig(sarama.ConfigResource{
+			Type:        sarama.BrokerResource,
+			Name:        strconv.Itoa(int(b.ID)),
+			ConfigNames: []string{leaderThrottleRate, followerThrottleRate},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe broker %d: %w", b.ID, err)
+		}
+		throttle := BrokerThrottle{BrokerID: b.ID}
+		for _, entry := range entries {
+			switch entry.Name {
+			case leaderThrottleRate:
+				throttle.Leader = throttleRate(entry)
+			case followerThrottleRate:
+				throttle.Follower = throttleRate(entry)
+			}
+		}
+		throttles.Brokers = append(throttles.Brokers, throttle)
+	}
+
+	topics, err := c.ListTopics()
+	if err != nil {
+		return nil, err
+	}
+	configs, err := c.describeTopicConfigs(topics, []string{leaderThrottledReplicas, followerThrottledReplicas})
+	if err != nil {
+		return nil, err
+	}
+	for _, topic := range topics {
+		throttle := TopicThrottle{Topic: topic}
+		for _, entry := range configs[topic] {
+			switch entry.Name {
+			case leaderThrottledReplicas:
+				throttle.Leaders = entry.Value
+			case followerThrottledReplicas:
+				throttle.Followers = entry.Value
+			}
+		}
+		if throttle.Leaders != "" || throttle.Followers != "" {
+			throttles.Topics = append(throttles.Topics, throttle)
+		}
+	}
+	return throttles, nil
+}
+
+// throttleRate reads a throttle rate config, which Kafka reports as the
+// largest long when it is unset
+func throttleRate(entry sarama.ConfigEntry) int64 {
+	rate, err := strconv.ParseInt(entry.Value, 10, 64)
+	if err != nil || rate == math.MaxInt64 || rate < 0 {
+		return 0
+	}
+	return rate
+}
+
+// SetBrokerThrottle sets the leader and follower replication rate of the
+// given brokers. The rate only applies to replicas listed in their topics'
+// throttled replicas configs.
+func (c *Client) SetBrokerThrottle(rate int64, brokers []int32) (err error) {
+	_, span := startSpan(context.Background(), "SetBrokerThrottle", attribute.Int64("rate", rate))
+	defer func() { endSpan(span, err) }()
+
+	if rate <= 0 {
+		return fmt.Errorf("throttle rate must be positive")
+	}
+	value := strconv.FormatInt(rate, 10)
+	entries := map[string]sarama.IncrementalAlterConfigsEntry{
+		leaderThrottleRate:   {Operation: sarama.IncrementalAlterConfigsOperationSet, Value: &value},
+		followerThrottleRate: {Operation: sarama.IncrementalAlterConfigsOperationSet, Value: &value},
+	}
+	for _, id := range brokers {
+		if err = c.admin.IncrementalAlterConfig(sarama.BrokerResource, strconv.Itoa(int(id)), entries, false); err != nil {
+			err = fmt.Errorf("failed to throttle broker %d: %w", id, err)
+			break
+		}
+	}
+	c.audit("replication.throttle.set", "cluster", nil, map[string]any{"rate": rate, "brokers": brokers}, err)
+	return err
+}
+
+// ClearReplicationThrottles removes every throttle in throttles
+func (c *Client) ClearReplicationThrottles(throttles *ReplicationThrottles) (err error) {
+	_, span := startSpan(context.Background(), "ClearReplicationThrottles")
+	defer func() { endSpan(span, err) }()
+
+	var brokers []int32
+	for _, b := range throttles.Brokers {
+		if b.Leader > 0 || b.Follower > 0 {
+			brokers = append(brokers, b.BrokerID)
+		}
+	}
+	topics := make([]string, len(throttles.Topics))
+	for i, t := range throttles.Topics {
+		topics[i] = t.Topic
+	}
+	return c.clearThrottles(brokers, topics)
+}
+
+// clearThrottles deletes the throttle rates of brokers and the throttled
+// replicas of topics
+func (c *Client) clearThrottles(brokers []int32, topics []string) error {
+	remove := sarama.IncrementalAlterConfigsEntry{Operation: sarama.IncrementalAlterConfigsOperationDelete}
+
+	var failed []string
+	for _, id := range brokers {
+		entries := map[string]sarama.IncrementalAlterConfigsEntry{
+			leaderThrottleRate:   remove,
+			followerThrottleRate: remove,
+		}
+		if err := c.admin.IncrementalAlterConfig(sarama.BrokerResource, strconv.Itoa(int(id)), entries, false); err != nil {
+			failed = append(failed, fmt.Sprintf("broker %d: %v", id, err))
+		}
+	}
+	for _, topic := range topics {
+		entries := map[string]sarama.IncrementalAlterConfigsEntry{
+			leaderThrottledReplicas:   remove,
+			followerThrottledReplicas: remove,
+		}
+		if err := c.admin.IncrementalAlterConfig(sarama.TopicResource, topic, entries, false); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", topic, err))
+		}
+	}
+	var err error
+	if len(failed) > 0 {
+		err = fmt.Errorf("failed to clear replication throttle: %s", strings.Join(failed, "; "))
+	}
+	c.audit("replication.throttle.clear", "cluster", map[string]any{"brokers": brokers, "topics": topics}, nil, err)
+	return err
+}
+
+// ParseByteRate parses a rate such as "50MB/s", "512KB" or "1048576" into
+// bytes/sec. Units are binary; "0" and "" mean no throttle.
+func ParseByteRate(rate string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(rate))
+	s = strings.TrimSuffix(s, "/S")
+	if s == "" {
+		return 0, nil
+	}
+
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(s, unit.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			multiplier = unit.size
+			break
+		}
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid rate %q: use a number of bytes or KB, MB or GB, e.g. 50MB/s", rate)
+	}
+	return int64(value * float64(multiplier)), nil
+}
@@ -0,0 +1,65 @@
+package kafka
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/IBM/sarama"
+)
+
+func TestThrottleTargets(t *testing.T) {
+	moves := []PartitionMove{
+		{Topic: "orders", Partition: 0, From: []int32{1, 2}, To: []int32{4, 2}},
+		{Topic: "orders", Partition: 2, From: []int32{3, 1}, To: []int32{3, 4}},
+	}
+	brokers, leaders, followers := throttleTargets(moves)
+	if !reflect.DeepEqual(brokers, []int32{1, 2, 3, 4}) {
+		t.Errorf("brokers = %v", brokers)
+	}
+	if leaders["orders"] != "0:1,0:2,2:3,2:1" {
+		t.Errorf("leaders = %q", leaders["orders"])
+	}
+	if followers["orders"] != "0:4,2:4" {
+		t.Errorf("followers = %q", followers["orders"])
+	}
+}
+
+func TestParseByteRate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"0", 0, false},
+		{"1048576", 1 << 20, false},
+		{"50MB/s", 50 << 20, false},
+		{"512 kb", 512 << 10, false},
+		{"1.5G", 3 << 29, false},
+		{"fast", 0, true},
+		{"-1MB", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseByteRate(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseByteRate(%q) = %d, %v; want %d, err %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestThrottleRate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int64
+	}{
+		{"10485760", 10485760},
+		{"9223372036854775807", 0},
+		{"", 0},
+		{"-1", 0},
+	}
+	for _, tt := range tests {
+		if got := throttleRate(sarama.ConfigEntry{Value: tt.value}); got != tt.want {
+			t.Errorf("throttleRate(%q) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package kafka
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+)
+
+// loadClientCertificate loads a PEM client certificate and key, decrypting
+// the key with password when one is given
+func loadClientCertificate(certFile, keyFile, password string) (tls.Certificate, error) {
+	if password == "" {
+		return tls.LoadX509KeyPair(certFile, keyFile)
+	}
+
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	keyPEM, err = decryptKeyPEM(keyPEM, password)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.X509KeyPair(certPEM, keyPEM)
+}
+
+// decryptKeyPEM decrypts a key in the legacy encrypted PEM format that
+// "openssl rsa -aes256" writes. An unencrypted key is returned unchanged.
+func decryptKeyPEM(keyPEM []byte, password string) ([]byte, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("client key is not PEM encoded")
+	}
+	if block.Type == "ENCRYPTED PRIVATE KEY" {
+		return nil, fmt.Errorf("encrypted PKCS#8 client keys are not supported; convert it with \"openssl pkcs8 -topk8 -traditional\" or \"openssl rsa -aes256\"")
+	}
+	//lint:ignore SA1019 legacy PEM encryption is still what most Kafka setups generate
+	if !x509.IsEncryptedPEMBlock(block) {
+		return keyPEM, nil
+	}
+	//lint:ignore SA1019 see above
+	der, err := x509.DecryptPEMBlock(block, []byte(password))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt client key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: block.Type, Bytes: der}), nil
+}
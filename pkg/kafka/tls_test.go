@@ -0,0 +1,41 @@
+package kafka
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+)
+
+func TestDecryptKeyPEM(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der := x509.MarshalPKCS1PrivateKey(key)
+	plain := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: der})
+	//lint:ignore SA1019 the format being tested
+	block, err := x509.EncryptPEMBlock(rand.Reader, "RSA PRIVATE KEY", der, []byte("secret"), x509.PEMCipherAES256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted := pem.EncodeToMemory(block)
+
+	got, err := decryptKeyPEM(encrypted, "secret")
+	if err != nil || !bytes.Equal(got, plain) {
+		t.Errorf("decrypting with the right password = %v, want the plain key", err)
+	}
+	// A wrong password is usually, but not always, caught by the padding check
+	if got, err := decryptKeyPEM(encrypted, "wrong"); err == nil && bytes.Equal(got, plain) {
+		t.Error("decrypting with the wrong password returned the key")
+	}
+	if got, err := decryptKeyPEM(plain, "secret"); err != nil || !bytes.Equal(got, plain) {
+		t.Errorf("an unencrypted key was changed: %v", err)
+	}
+	pkcs8 := pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: []byte{0}})
+	if _, err := decryptKeyPEM(pkcs8, "secret"); err == nil {
+		t.Error("an encrypted PKCS#8 key was accepted")
+	}
+}
@@ -0,0 +1,65 @@
+package kafka
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultTopicCacheTTL is how long the topic list is reused before it is
+// fetched again
+const DefaultTopicCacheTTL = time.Minute
+
+// topicCache holds the last topic list. It sits behind a pointer so clients
+// returned by WithAuditSource share it, and a change made through any of
+// them invalidates it for all.
+type topicCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	topics  []TopicInfo
+	fetched time.Time
+	now     func() time.Time // Overridden in tests
+}
+
+func newTopicCache(ttl time.Duration) *topicCache {
+	return &topicCache{ttl: ttl, now: time.Now}
+}
+
+// get returns the cached topics while they are fresh
+func (t *topicCache) get() ([]TopicInfo, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.ttl <= 0 || t.topics == nil || t.now().Sub(t.fetched) >= t.ttl {
+		return nil, false
+	}
+	return t.topics, true
+}
+
+func (t *topicCache) set(topics []TopicInfo) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.topics = topics
+	t.fetched = t.now()
+}
+
+func (t *topicCache) invalidate() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.topics = nil
+}
+
+func (t *topicCache) setTTL(ttl time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.ttl = ttl
+}
+
+// SetTopicCacheTTL sets how long GetTopicDetails reuses the topic list; zero
+// or less disables the cache
+func (c *Client) SetTopicCacheTTL(ttl time.Duration) {
+	c.topicCache.setTTL(ttl)
+}
+
+// InvalidateTopicCache makes the next GetTopicDetails fetch from the cluster
+func (c *Client) InvalidateTopicCache() {
+	c.topicCache.invalidate()
+}
@@ -0,0 +1,38 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTopicCache(t *testing.T) {
+	now := time.Unix(1000, 0)
+	cache := newTopicCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	topics := []TopicInfo{{Name: "orders"}}
+
+	if _, ok := cache.get(); ok {
+		t.Fatal("empty cache returned topics")
+	}
+	cache.set(topics)
+	if got, ok := cache.get(); !ok || len(got) != 1 {
+		t.Fatalf("fresh cache = %v, %v", got, ok)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := cache.get(); ok {
+		t.Error("expired cache returned topics")
+	}
+
+	cache.set(topics)
+	cache.invalidate()
+	if _, ok := cache.get(); ok {
+		t.Error("invalidated cache returned topics")
+	}
+
+	cache.set(topics)
+	cache.setTTL(0)
+	if _, ok := cache.get(); ok {
+		t.Error("disabled cache returned topics")
+	}
+}
@@ -0,0 +1,102 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IBM/sarama"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// describeConfigsBatchSize caps the topics per DescribeConfigs request so a
+// large cluster doesn't produce one oversized request and response
+const describeConfigsBatchSize = 100
+
+// TopicOverviewConfigs are the configs the topics table shows for every topic
+var TopicOverviewConfigs = []string{"cleanup.policy", "retention.ms", "retention.bytes", "min.insync.replicas"}
+
+// GetTopicConfigs returns every config of each topic, keyed by topic then
+// config name. Topics are described in batches rather than one at a time.
+func (c *Client) GetTopicConfigs(topics []string) (map[string]map[string]string, error) {
+	return c.GetTopicConfigValues(topics, nil)
+}
+
+// GetTopicConfigValues returns the named configs of each topic, keyed by
+// topic then config name; nil names returns them all
+func (c *Client) GetTopicConfigValues(topics, names []string) (_ map[string]map[string]string, err error) {
+	_, span := startSpan(context.Background(), "DescribeTopicConfigs", attribute.Int("topics", len(topics)))
+	defer func() { endSpan(span, err) }()
+
+	entries, err := c.describeTopicConfigs(topics, names)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]map[string]string, len(entries))
+	for topic, configs := range entries {
+		values[topic] = make(map[string]string, len(configs))
+		for _, entry := range configs {
+			values[topic][entry.Name] = entry.Value
+		}
+	}
+	return values, nil
+}
+
+// describeTopicConfigs reads the named configs of many topics, sending one
+// DescribeConfigs request per describeConfigsBatchSize topics. Topics the
+// broker reports an error for are left out.
+func (c *Client) describeTopicConfigs(topics, names []string) (map[string][]sarama.ConfigEntry, error) {
+	configs := make(map[string][]sarama.ConfigEntry, len(topics))
+	if len(topics) == 0 {
+		return configs, nil
+	}
+
+	broker, err := c.admin.Controller()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get controller: %w", err)
+	}
+
+	for _, batch := range chunkStrings(topics, describeConfigsBatchSize) {
+		request := &sarama.DescribeConfigsRequest{}
+		if c.config.Version.IsAtLeast(sarama.V2_0_0_0) {
+			request.Version = 2
+		} else if c.config.Version.IsAtLeast(sarama.V1_1_0_0) {
+			request.Version = 1
+		}
+		for _, topic := range batch {
+			request.Resources = append(request.Resources, &sarama.ConfigResource{
+				Type:        sarama.TopicResource,
+				Name:        topic,
+				ConfigNames: names,
+			})
+		}
+
+		response, err := broker.DescribeConfigs(request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe topic configs: %w", err)
+		}
+		for _, resource := range response.Resources {
+			if resource.ErrorCode != 0 {
+				logger.Get().WithField("topic", resource.Name).WithField("error", resource.ErrorMsg).Debug("Failed to describe topic config")
+				continue
+			}
+			for _, entry := range resource.Configs {
+				configs[resource.Name] = append(configs[resource.Name], *entry)
+			}
+		}
+	}
+	return configs, nil
+}
+
+// chunkStrings splits items into slices of at most size
+func chunkStrings(items []string, size int) [][]string {
+	var chunks [][]string
+	for len(items) > size {
+		chunks = append(chunks, items[:size])
+		items = items[size:]
+	}
+	if len(items) > 0 {
+		chunks = append(chunks, items)
+	}
+	return chunks
+}
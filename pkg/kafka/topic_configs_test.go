@@ -0,0 +1,24 @@
+package kafka
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChunkStrings(t *testing.T) {
+	tests := []struct {
+		items []string
+		size  int
+		want  [][]string
+	}{
+		{nil, 2, nil},
+		{[]string{"a"}, 2, [][]string{{"a"}}},
+		{[]string{"a", "b"}, 2, [][]string{{"a", "b"}}},
+		{[]string{"a", "b", "c", "d", "e"}, 2, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}},
+	}
+	for _, tt := range tests {
+		if got := chunkStrings(tt.items, tt.size); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("chunkStrings(%v, %d) = %v, want %v", tt.items, tt.size, got, tt.want)
+		}
+	}
+}
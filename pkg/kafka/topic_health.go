@@ -0,0 +1,74 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// TopicHealth counts the partitions of a topic that are in trouble
+type TopicHealth struct {
+	Offline         int   // Partitions without a leader
+	UnderReplicated int   // Partitions with replicas out of sync
+	ISR             []int // In-sync replicas of each partition with a leader
+}
+
+// UnderMinISR counts the partitions with a leader but fewer in-sync replicas
+// than minISR, which refuse writes with acks=all
+func (h TopicHealth) UnderMinISR(minISR int) int {
+	count := 0
+	for _, isr := range h.ISR {
+		if isr < minISR {
+			count++
+		}
+	}
+	return count
+}
+
+// Healthy reports whether every partition has a leader and all its replicas
+// in sync
+func (h TopicHealth) Healthy() bool {
+	return h.Offline == 0 && h.UnderReplicated == 0
+}
+
+// GetTopicHealth reads the replication state of many topics from a single
+// metadata request
+func (c *Client) GetTopicHealth(topics []string) (_ map[string]TopicHealth, err error) {
+	_, span := startSpan(context.Background(), "GetTopicHealth", attribute.Int("topics", len(topics)))
+	defer func() { endSpan(span, err) }()
+
+	if len(topics) == 0 {
+		return map[string]TopicHealth{}, nil
+	}
+	metadata, err := c.admin.DescribeTopics(topics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe topics: %w", err)
+	}
+	return topicHealth(metadata), nil
+}
+
+// topicHealth counts the troubled partitions in topic metadata. Topics the
+// broker reports an error for are left out.
+func topicHealth(metadata []*sarama.TopicMetadata) map[string]TopicHealth {
+	health := make(map[string]TopicHealth, len(metadata))
+	for _, topic := range metadata {
+		if topic.Err != sarama.ErrNoError {
+			continue
+		}
+		var h TopicHealth
+		for _, partition := range topic.Partitions {
+			if len(partition.Isr) < len(partition.Replicas) {
+				h.UnderReplicated++
+			}
+			if partition.Leader < 0 {
+				h.Offline++
+			} else {
+				h.ISR = append(h.ISR, len(partition.Isr))
+			}
+		}
+		health[topic.Name] = h
+	}
+	return health
+}
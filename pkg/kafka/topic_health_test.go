@@ -0,0 +1,38 @@
+package kafka
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/IBM/sarama"
+)
+
+func TestTopicHealth(t *testing.T) {
+	metadata := []*sarama.TopicMetadata{
+		{Name: "orders", Partitions: []*sarama.PartitionMetadata{
+			{ID: 0, Leader: 1, Replicas: []int32{1, 2, 3}, Isr: []int32{1, 2, 3}},
+			{ID: 1, Leader: 2, Replicas: []int32{2, 3, 1}, Isr: []int32{2}},
+			{ID: 2, Leader: -1, Replicas: []int32{3, 1, 2}, Isr: []int32{}},
+		}},
+		{Name: "events", Partitions: []*sarama.PartitionMetadata{
+			{ID: 0, Leader: 1, Replicas: []int32{1, 2}, Isr: []int32{1, 2}},
+		}},
+		{Name: "gone", Err: sarama.ErrUnknownTopicOrPartition},
+	}
+
+	got := topicHealth(metadata)
+	want := map[string]TopicHealth{
+		"orders": {Offline: 1, UnderReplicated: 2, ISR: []int{3, 1}},
+		"events": {ISR: []int{2}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	if n := got["orders"].UnderMinISR(2); n != 1 {
+		t.Errorf("UnderMinISR(2) = %d, want 1", n)
+	}
+	if got["orders"].Healthy() || !got["events"].Healthy() {
+		t.Errorf("Healthy() wrong for %+v", got)
+	}
+}
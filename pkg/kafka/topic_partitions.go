@@ -0,0 +1,83 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/IBM/sarama"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// PartitionDetail is the replication state and offset range of one
+// partition
+type PartitionDetail struct {
+	PartitionInfo
+	Offline  []int32 // Replicas on brokers that are down or whose log dir failed
+	LogStart int64   // -1 when the leader could not be asked
+	LogEnd   int64
+}
+
+// UnderReplicated reports whether some replica has fallen out of sync
+func (p PartitionDetail) UnderReplicated() bool {
+	return len(p.ISR) < len(p.Replicas)
+}
+
+// LeaderOffline reports whether the partition has no leader, so it can be
+// neither written nor read
+func (p PartitionDetail) LeaderOffline() bool {
+	return p.Leader < 0
+}
+
+// GetTopicPartitions returns the leader, replicas, ISR, offline replicas and
+// log start and end offsets of each partition of a topic
+func (c *Client) GetTopicPartitions(topic string) (_ []PartitionDetail, err error) {
+	_, span := startSpan(context.Background(), "GetTopicPartitions", attribute.String("topic", topic))
+	defer func() { endSpan(span, err) }()
+
+	client, err := sarama.NewClient(c.brokers, c.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	defer func() {
+		if closeErr := client.Close(); closeErr != nil {
+			logger.Get().WithError(closeErr).Warn("Failed to close partitions client")
+		}
+	}()
+
+	partitions, err := client.Partitions(topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partitions of %s: %w", topic, err)
+	}
+	// Partitions without a leader are left out of the offsets
+	oldest, err := leaderOffsets(client, []string{topic}, sarama.OffsetOldest)
+	if err != nil {
+		return nil, err
+	}
+	newest, err := leaderOffsets(client, []string{topic}, sarama.OffsetNewest)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]PartitionDetail, 0, len(partitions))
+	for _, partition := range partitions {
+		d := PartitionDetail{PartitionInfo: PartitionInfo{ID: partition, Leader: -1}, LogStart: -1, LogEnd: -1}
+		if leader, err := client.Leader(topic, partition); err == nil {
+			d.Leader = leader.ID()
+		}
+		// Errors for unavailable replicas still come with the lists
+		d.Replicas, _ = client.Replicas(topic, partition)
+		d.ISR, _ = client.InSyncReplicas(topic, partition)
+		d.Offline, _ = client.OfflineReplicas(topic, partition)
+		if start, ok := oldest[topic][partition]; ok {
+			d.LogStart = start
+		}
+		if end, ok := newest[topic][partition]; ok {
+			d.LogEnd = end
+		}
+		details = append(details, d)
+	}
+	sort.Slice(details, func(i, j int) bool { return details[i].ID < details[j].ID })
+	return details, nil
+}
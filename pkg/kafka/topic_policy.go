@@ -0,0 +1,149 @@
+package kafka
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+)
+
+// minISRConfig is the topic config TopicPolicy.MinInsyncReplicas governs
+const minISRConfig = "min.insync.replicas"
+
+// TopicPolicy holds the defaults and guardrails for topics created or
+// resized through a client, set per cluster. Zero fields impose nothing.
+type TopicPolicy struct {
+	MinReplicationFactor int16             `mapstructure:"min_replication_factor"`
+	MinInsyncReplicas    int               `mapstructure:"min_insync_replicas"` // Set on new topics, and the lowest min.insync.replicas allowed
+	MinPartitions        int32             `mapstructure:"min_partitions"`
+	MaxPartitions        int32             `mapstructure:"max_partitions"`
+	Configs              map[string]string `mapstructure:"configs"` // Default configs of new topics
+}
+
+// Validate checks the policy is consistent
+func (p TopicPolicy) Validate() error {
+	if p.MinReplicationFactor < 0 || p.MinInsyncReplicas < 0 || p.MinPartitions < 0 || p.MaxPartitions < 0 {
+		return fmt.Errorf("invalid topic_policy: limits must not be negative")
+	}
+	if p.MaxPartitions > 0 && p.MinPartitions > p.MaxPartitions {
+		return fmt.Errorf("invalid topic_policy: min_partitions %d is above max_partitions %d", p.MinPartitions, p.MaxPartitions)
+	}
+	if value, ok := p.Configs[minISRConfig]; ok {
+		if err := p.checkMinISR(value, 0); err != nil {
+			return fmt.Errorf("invalid topic_policy configs: %w", err)
+		}
+	}
+	return nil
+}
+
+// String summarises the limits, empty when there are none
+func (p TopicPolicy) String() string {
+	var parts []string
+	if p.MinReplicationFactor > 0 {
+		parts = append(parts, fmt.Sprintf("replication factor ≥ %d", p.MinReplicationFactor))
+	}
+	if p.MinInsyncReplicas > 0 {
+		parts = append(parts, fmt.Sprintf("%s ≥ %d", minISRConfig, p.MinInsyncReplicas))
+	}
+	switch {
+	case p.MinPartitions > 0 && p.MaxPartitions > 0:
+		parts = append(parts, fmt.Sprintf("%d–%d partitions", p.MinPartitions, p.MaxPartitions))
+	case p.MinPartitions > 0:
+		parts = append(parts, fmt.Sprintf("partitions ≥ %d", p.MinPartitions))
+	case p.MaxPartitions > 0:
+		parts = append(parts, fmt.Sprintf("partitions ≤ %d", p.MaxPartitions))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// DefaultPartitions is the partition count of a new topic when none is given
+func (p TopicPolicy) DefaultPartitions() int32 {
+	return max(1, p.MinPartitions)
+}
+
+// DefaultReplicationFactor is the replication factor of a new topic when
+// none is given
+func (p TopicPolicy) DefaultReplicationFactor() int16 {
+	return max(1, p.MinReplicationFactor)
+}
+
+// newTopic applies the policy to a topic about to be created: unset
+// partitions and replication factor take the defaults, the default configs
+// are added under the given ones, and the result is checked against the
+// limits
+func (p TopicPolicy) newTopic(partitions int32, replicationFactor int16, configs map[string]string) (int32, int16, map[string]string, error) {
+	if partitions < 1 {
+		partitions = p.DefaultPartitions()
+	}
+	if replicationFactor < 1 {
+		replicationFactor = p.DefaultReplicationFactor()
+	}
+	merged := maps.Clone(p.Configs)
+	if merged == nil {
+		merged = make(map[string]string)
+	}
+	maps.Copy(merged, configs)
+	if _, ok := merged[minISRConfig]; !ok && p.MinInsyncReplicas > 0 {
+		merged[minISRConfig] = strconv.Itoa(p.MinInsyncReplicas)
+	}
+	if len(merged) == 0 {
+		merged = nil
+	}
+
+	if p.MinReplicationFactor > 0 && replicationFactor < p.MinReplicationFactor {
+		return 0, 0, nil, fmt.Errorf("topic policy: replication factor %d is below the minimum of %d", replicationFactor, p.MinReplicationFactor)
+	}
+	if p.MinPartitions > 0 && partitions < p.MinPartitions {
+		return 0, 0, nil, fmt.Errorf("topic policy: %d partitions is below the minimum of %d", partitions, p.MinPartitions)
+	}
+	if err := p.checkMaxPartitions(partitions); err != nil {
+		return 0, 0, nil, err
+	}
+	if value, ok := merged[minISRConfig]; ok {
+		if err := p.checkMinISR(value, replicationFactor); err != nil {
+			return 0, 0, nil, err
+		}
+	}
+	return partitions, replicationFactor, merged, nil
+}
+
+// checkMaxPartitions checks a partition count against the maximum. Only
+// new topics are held to the minimum, so an older topic below it can still
+// grow.
+func (p TopicPolicy) checkMaxPartitions(partitions int32) error {
+	if p.MaxPartitions > 0 && partitions > p.MaxPartitions {
+		return fmt.Errorf("topic policy: %d partitions is above the maximum of %d", partitions, p.MaxPartitions)
+	}
+	return nil
+}
+
+// checkMinISR checks a min.insync.replicas value against the required one
+// and, when known, the replication factor it has to fit in
+func (p TopicPolicy) checkMinISR(value string, replicationFactor int16) error {
+	if p.MinInsyncReplicas == 0 {
+		return nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return fmt.Errorf("topic policy: invalid %s %q", minISRConfig, value)
+	}
+	if n < p.MinInsyncReplicas {
+		return fmt.Errorf("topic policy: %s %d is below the required %d", minISRConfig, n, p.MinInsyncReplicas)
+	}
+	if replicationFactor > 0 && n > int(replicationFactor) {
+		return fmt.Errorf("topic policy: %s %d is above the replication factor %d, so acks=all writes would always fail",
+			minISRConfig, n, replicationFactor)
+	}
+	return nil
+}
+
+// SetTopicPolicy applies p to every topic later created or resized through
+// c and its clones
+func (c *Client) SetTopicPolicy(p TopicPolicy) {
+	c.topicPolicy = p
+}
+
+// TopicPolicy returns the policy set with SetTopicPolicy
+func (c *Client) TopicPolicy() TopicPolicy {
+	return c.topicPolicy
+}
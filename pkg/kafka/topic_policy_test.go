@@ -0,0 +1,90 @@
+package kafka
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTopicPolicyNewTopic(t *testing.T) {
+	policy := TopicPolicy{
+		MinReplicationFactor: 3,
+		MinInsyncReplicas:    2,
+		MinPartitions:        3,
+		MaxPartitions:        60,
+		Configs:              map[string]string{"compression.type": "zstd"},
+	}
+	tests := []struct {
+		name              string
+		partitions        int32
+		replicationFactor int16
+		configs           map[string]string
+		wantPartitions    int32
+		wantRF            int16
+		wantConfigs       map[string]string
+		wantErr           bool
+	}{
+		{
+			name:           "defaults",
+			wantPartitions: 3, wantRF: 3,
+			wantConfigs: map[string]string{"compression.type": "zstd", "min.insync.replicas": "2"},
+		},
+		{
+			name:       "given values win",
+			partitions: 12, replicationFactor: 4,
+			configs:        map[string]string{"compression.type": "lz4", "min.insync.replicas": "3"},
+			wantPartitions: 12, wantRF: 4,
+			wantConfigs: map[string]string{"compression.type": "lz4", "min.insync.replicas": "3"},
+		},
+		{name: "replication factor too low", partitions: 6, replicationFactor: 2, wantErr: true},
+		{name: "too few partitions", partitions: 1, wantErr: true},
+		{name: "too many partitions", partitions: 100, wantErr: true},
+		{name: "min isr too low", configs: map[string]string{"min.insync.replicas": "1"}, wantErr: true},
+		{name: "min isr above replication factor", configs: map[string]string{"min.insync.replicas": "4"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			partitions, rf, configs, err := policy.newTopic(tt.partitions, tt.replicationFactor, tt.configs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if partitions != tt.wantPartitions || rf != tt.wantRF || !reflect.DeepEqual(configs, tt.wantConfigs) {
+				t.Errorf("got %d, %d, %v; want %d, %d, %v", partitions, rf, configs, tt.wantPartitions, tt.wantRF, tt.wantConfigs)
+			}
+		})
+	}
+
+	// The policy's own configs are not changed by a topic's
+	if policy.Configs["compression.type"] != "zstd" || len(policy.Configs) != 1 {
+		t.Errorf("policy configs changed: %v", policy.Configs)
+	}
+}
+
+func TestTopicPolicyEmpty(t *testing.T) {
+	partitions, rf, configs, err := TopicPolicy{}.newTopic(0, 0, nil)
+	if err != nil || partitions != 1 || rf != 1 || configs != nil {
+		t.Errorf("got %d, %d, %v, %v; want 1, 1, nil, nil", partitions, rf, configs, err)
+	}
+}
+
+func TestTopicPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  TopicPolicy
+		wantErr bool
+	}{
+		{name: "empty", policy: TopicPolicy{}},
+		{name: "min above max", policy: TopicPolicy{MinPartitions: 10, MaxPartitions: 5}, wantErr: true},
+		{name: "negative", policy: TopicPolicy{MinReplicationFactor: -1}, wantErr: true},
+		{name: "default configs break the policy", policy: TopicPolicy{MinInsyncReplicas: 2, Configs: map[string]string{"min.insync.replicas": "1"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
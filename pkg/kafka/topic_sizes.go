@@ -0,0 +1,148 @@
+package kafka
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/IBM/sarama"
+)
+
+// GetTopicSizes returns the bytes each topic occupies on the given brokers'
+// disks, summed over all partitions and replicas
+func (c *Client) GetTopicSizes(brokerIDs []int32) (map[string]int64, error) {
+	logDirs, err := c.admin.DescribeLogDirs(brokerIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe log dirs: %w", err)
+	}
+
+	sizes := make(map[string]int64)
+	for _, dirs := range logDirs {
+		for _, dir := range dirs {
+			for _, topic := range dir.Topics {
+				for _, partition := range topic.Partitions {
+					sizes[topic.Topic] += partition.Size
+				}
+			}
+		}
+	}
+	return sizes, nil
+}
+
+// LogDirInfo describes one log directory of a broker
+type LogDirInfo struct {
+	Path       string
+	Error      string // Set when the broker could not read the directory
+	Size       int64  // Bytes used by all replicas in the directory
+	Partitions []LogDirPartition
+}
+
+// LogDirPartition is a replica stored in a log directory
+type LogDirPartition struct {
+	Topic     string
+	Partition int32
+	Size      int64
+	OffsetLag int64 // How far a future replica trails the current one
+	Future    bool  // Replica being moved into this directory
+}
+
+// GetBrokerLogDirs returns a broker's log directories with their replicas,
+// largest first, to diagnose disk pressure
+func (c *Client) GetBrokerLogDirs(brokerID int32) ([]LogDirInfo, error) {
+	logDirs, err := c.admin.DescribeLogDirs([]int32{brokerID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe log dirs: %w", err)
+	}
+	return summarizeLogDirs(logDirs[brokerID]), nil
+}
+
+func summarizeLogDirs(dirs []sarama.DescribeLogDirsResponseDirMetadata) []LogDirInfo {
+	infos := make([]LogDirInfo, 0, len(dirs))
+	for _, dir := range dirs {
+		info := LogDirInfo{Path: dir.Path}
+		if dir.ErrorCode != sarama.ErrNoError {
+			info.Error = dir.ErrorCode.Error()
+		}
+		for _, topic := range dir.Topics {
+			for _, p := range topic.Partitions {
+				info.Size += p.Size
+				info.Partitions = append(info.Partitions, LogDirPartition{
+					Topic:     topic.Topic,
+					Partition: p.PartitionID,
+					Size:      p.Size,
+					OffsetLag: p.OffsetLag,
+					Future:    p.IsTemporary,
+				})
+			}
+		}
+		sort.Slice(info.Partitions, func(i, j int) bool {
+			a, b := info.Partitions[i], info.Partitions[j]
+			if a.Size != b.Size {
+				return a.Size > b.Size
+			}
+			if a.Topic != b.Topic {
+				return a.Topic < b.Topic
+			}
+			return a.Partition < b.Partition
+		})
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Path < infos[j].Path })
+	return infos
+}
+
+// BrokerDiskUsage totals a broker's log dirs and ranks what uses the space
+type BrokerDiskUsage struct {
+	Size     int64 // Bytes used by all replicas in all log dirs
+	Replicas int
+	Topics   []TopicDiskUsage // Largest first
+	Largest  []DirPartition   // Largest replicas across all log dirs first
+}
+
+// TopicDiskUsage is the space a topic's replicas take on one broker
+type TopicDiskUsage struct {
+	Topic    string
+	Size     int64
+	Replicas int
+}
+
+// DirPartition is a replica and the log dir it is stored in
+type DirPartition struct {
+	LogDirPartition
+	Path string
+}
+
+// SummarizeBrokerDisk aggregates the log dirs of one broker by topic and
+// ranks its replicas by size
+func SummarizeBrokerDisk(dirs []LogDirInfo) BrokerDiskUsage {
+	var usage BrokerDiskUsage
+	topics := map[string]*TopicDiskUsage{}
+	for _, dir := range dirs {
+		usage.Size += dir.Size
+		for _, p := range dir.Partitions {
+			usage.Replicas++
+			usage.Largest = append(usage.Largest, DirPartition{LogDirPartition: p, Path: dir.Path})
+			t, ok := topics[p.Topic]
+			if !ok {
+				t = &TopicDiskUsage{Topic: p.Topic}
+				topics[p.Topic] = t
+			}
+			t.Size += p.Size
+			t.Replicas++
+		}
+	}
+
+	for _, t := range topics {
+		usage.Topics = append(usage.Topics, *t)
+	}
+	sort.Slice(usage.Topics, func(i, j int) bool {
+		a, b := usage.Topics[i], usage.Topics[j]
+		if a.Size != b.Size {
+			return a.Size > b.Size
+		}
+		return a.Topic < b.Topic
+	})
+	sort.SliceStable(usage.Largest, func(i, j int) bool {
+		return usage.Largest[i].Size > usage.Largest[j].Size
+	})
+	return usage
+}
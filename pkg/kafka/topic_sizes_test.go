@@ -0,0 +1,62 @@
+package kafka
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/IBM/sarama"
+)
+
+func TestSummarizeLogDirs(t *testing.T) {
+	got := summarizeLogDirs([]sarama.DescribeLogDirsResponseDirMetadata{
+		{Path: "/data/b", ErrorCode: sarama.ErrKafkaStorageError},
+		{Path: "/data/a", Topics: []sarama.DescribeLogDirsResponseTopic{
+			{Topic: "orders", Partitions: []sarama.DescribeLogDirsResponsePartition{
+				{PartitionID: 0, Size: 100},
+				{PartitionID: 1, Size: 300, OffsetLag: 5, IsTemporary: true},
+			}},
+			{Topic: "events", Partitions: []sarama.DescribeLogDirsResponsePartition{
+				{PartitionID: 0, Size: 100},
+			}},
+		}},
+	})
+
+	want := []LogDirInfo{
+		{Path: "/data/a", Size: 500, Partitions: []LogDirPartition{
+			{Topic: "orders", Partition: 1, Size: 300, OffsetLag: 5, Future: true},
+			{Topic: "events", Partition: 0, Size: 100},
+			{Topic: "orders", Partition: 0, Size: 100},
+		}},
+		{Path: "/data/b", Error: sarama.ErrKafkaStorageError.Error()},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestSummarizeBrokerDisk(t *testing.T) {
+	usage := SummarizeBrokerDisk([]LogDirInfo{
+		{Path: "/data/a", Size: 500, Partitions: []LogDirPartition{
+			{Topic: "orders", Partition: 1, Size: 300},
+			{Topic: "events", Partition: 0, Size: 150},
+			{Topic: "orders", Partition: 0, Size: 50},
+		}},
+		{Path: "/data/b", Size: 400, Partitions: []LogDirPartition{
+			{Topic: "events", Partition: 1, Size: 400},
+		}},
+	})
+
+	if usage.Size != 900 || usage.Replicas != 4 {
+		t.Errorf("got %d bytes in %d replicas, want 900 in 4", usage.Size, usage.Replicas)
+	}
+	wantTopics := []TopicDiskUsage{
+		{Topic: "events", Size: 550, Replicas: 2},
+		{Topic: "orders", Size: 350, Replicas: 2},
+	}
+	if !reflect.DeepEqual(usage.Topics, wantTopics) {
+		t.Errorf("topics = %+v, want %+v", usage.Topics, wantTopics)
+	}
+	if first := usage.Largest[0]; first.Topic != "events" || first.Partition != 1 || first.Path != "/data/b" {
+		t.Errorf("largest replica = %+v, want events-1 in /data/b", first)
+	}
+}
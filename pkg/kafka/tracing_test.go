@@ -0,0 +1,40 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	_, ok := startSpan(context.Background(), "ListTopics")
+	endSpan(ok, nil)
+	_, failed := startSpan(context.Background(), "DeleteTopic", attribute.String("topic", "orders"))
+	endSpan(failed, errors.New("not authorized"))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	if spans[0].Name() != "kafka.ListTopics" || spans[0].Status().Code != codes.Unset {
+		t.Errorf("first span = %s %v", spans[0].Name(), spans[0].Status())
+	}
+	if spans[1].Status().Code != codes.Error || spans[1].Status().Description != "not authorized" {
+		t.Errorf("failed span status = %v", spans[1].Status())
+	}
+	if len(spans[1].Attributes()) != 1 || spans[1].Attributes()[0].Value.AsString() != "orders" {
+		t.Errorf("failed span attributes = %v", spans[1].Attributes())
+	}
+}
@@ -0,0 +1,169 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Transaction is the state a coordinator holds for one transactional ID
+type Transaction struct {
+	TransactionalID string
+	State           string // Empty, Ongoing, PrepareCommit, PrepareAbort, CompleteCommit, CompleteAbort...
+	ProducerID      int64
+	ProducerEpoch   int16
+	Timeout         time.Duration
+	Started         time.Time          // Zero unless a transaction is in progress
+	Partitions      map[string][]int32 // Written to by the transaction in progress
+	Coordinator     int32
+}
+
+// Active reports whether the transaction is still open, holding back the
+// last stable offset of the partitions it wrote to
+func (t Transaction) Active() bool {
+	switch t.State {
+	case "Ongoing", "PrepareCommit", "PrepareAbort", "PrepareEpochFence":
+		return true
+	}
+	return false
+}
+
+// Overdue reports whether an open transaction has run past its timeout,
+// which the coordinator should have aborted it at
+func (t Transaction) Overdue(now time.Time) bool {
+	return t.Active() && !t.Started.IsZero() && t.Timeout > 0 && now.Sub(t.Started) > t.Timeout
+}
+
+// PartitionCount returns how many partitions the transaction wrote to
+func (t Transaction) PartitionCount() int {
+	n := 0
+	for _, partitions := range t.Partitions {
+		n += len(partitions)
+	}
+	return n
+}
+
+// ListTransactions returns every transactional ID the brokers coordinate,
+// described in full. Each broker only knows the IDs it coordinates, so all
+// of them are asked. Needs Kafka 3.0 or later.
+func (c *Client) ListTransactions() (_ []Transaction, err error) {
+	_, span := startSpan(context.Background(), "ListTransactions")
+	defer func() { endSpan(span, err) }()
+
+	brokers, _, err := c.admin.DescribeCluster()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list brokers: %w", err)
+	}
+
+	var transactions []Transaction
+	for _, b := range brokers {
+		found, err := c.brokerTransactions(b)
+		if err != nil {
+			return nil, fmt.Errorf("broker %d: %w", b.ID(), err)
+		}
+		transactions = append(transactions, found...)
+	}
+	sort.Slice(transactions, func(i, j int) bool {
+		return transactions[i].TransactionalID < transactions[j].TransactionalID
+	})
+	return transactions, nil
+}
+
+// brokerTransactions lists and describes the transactions one broker
+// coordinates
+func (c *Client) brokerTransactions(b *sarama.Broker) ([]Transaction, error) {
+	conn, err := dialRaw(c.config, b.Addr())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	list := kmsg.NewPtrListTransactionsRequest()
+	if !conn.Supports(list) {
+		return nil, fmt.Errorf("listing transactions needs Kafka 3.0 or later")
+	}
+	resp, err := conn.Request(list)
+	if err != nil {
+		return nil, err
+	}
+	listed := resp.(*kmsg.ListTransactionsResponse)
+	if err := kafkaError(listed.ErrorCode); err != nil {
+		return nil, err
+	}
+	if len(listed.TransactionStates) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(listed.TransactionStates))
+	for i, state := range listed.TransactionStates {
+		ids[i] = state.TransactionalID
+	}
+	return describeTransactions(conn, b.ID(), ids)
+}
+
+// DescribeTransaction asks the coordinator of id for its transaction state
+func (c *Client) DescribeTransaction(id string) (_ *Transaction, err error) {
+	_, span := startSpan(context.Background(), "DescribeTransaction", attribute.String("transactional.id", id))
+	defer func() { endSpan(span, err) }()
+
+	saramaClient, err := sarama.NewClient(c.brokers, c.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	defer saramaClient.Close()
+
+	coordinator, err := saramaClient.TransactionCoordinator(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the coordinator for %s: %w", id, err)
+	}
+	conn, err := dialRaw(c.config, coordinator.Addr())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	transactions, err := describeTransactions(conn, coordinator.ID(), []string{id})
+	if err != nil {
+		return nil, err
+	}
+	return &transactions[0], nil
+}
+
+// describeTransactions describes ids, which conn's broker must coordinate
+func describeTransactions(conn *rawConn, coordinator int32, ids []string) ([]Transaction, error) {
+	req := kmsg.NewPtrDescribeTransactionsRequest()
+	req.TransactionalIDs = ids
+	resp, err := conn.Request(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []Transaction
+	for _, state := range resp.(*kmsg.DescribeTransactionsResponse).TransactionStates {
+		if err := kafkaError(state.ErrorCode); err != nil {
+			return nil, fmt.Errorf("failed to describe %s: %w", state.TransactionalID, err)
+		}
+		t := Transaction{
+			TransactionalID: state.TransactionalID,
+			State:           state.State,
+			ProducerID:      state.ProducerID,
+			ProducerEpoch:   state.ProducerEpoch,
+			Timeout:         time.Duration(state.TimeoutMillis) * time.Millisecond,
+			Partitions:      make(map[string][]int32, len(state.Topics)),
+			Coordinator:     coordinator,
+		}
+		if state.StartTimestamp >= 0 {
+			t.Started = time.UnixMilli(state.StartTimestamp)
+		}
+		for _, topic := range state.Topics {
+			t.Partitions[topic.Topic] = append(t.Partitions[topic.Topic], topic.Partitions...)
+		}
+		transactions = append(transactions, t)
+	}
+	return transactions, nil
+}
@@ -0,0 +1,126 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// serveTransactions answers ApiVersions, ListTransactions and
+// DescribeTransactions for one open transaction on a single connection
+func serveTransactions(t *testing.T, listener net.Listener) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	for {
+		var size int32
+		if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+			return
+		}
+		req := make([]byte, size)
+		if _, err := io.ReadFull(conn, req); err != nil {
+			return
+		}
+		key := int16(binary.BigEndian.Uint16(req))
+		version := int16(binary.BigEndian.Uint16(req[2:]))
+
+		var resp kmsg.Response
+		switch key {
+		case kmsg.ApiVersions.Int16():
+			r := kmsg.NewPtrApiVersionsResponse()
+			for _, k := range []int16{18, 65, 66} {
+				r.ApiKeys = append(r.ApiKeys, kmsg.ApiVersionsResponseApiKey{ApiKey: k, MaxVersion: 1})
+			}
+			resp = r
+		case kmsg.ListTransactions.Int16():
+			r := kmsg.NewPtrListTransactionsResponse()
+			r.TransactionStates = []kmsg.ListTransactionsResponseTransactionState{
+				{TransactionalID: "payments", ProducerID: 7, TransactionState: "Ongoing"},
+			}
+			resp = r
+		case kmsg.DescribeTransactions.Int16():
+			r := kmsg.NewPtrDescribeTransactionsResponse()
+			r.TransactionStates = []kmsg.DescribeTransactionsResponseTransactionState{{
+				TransactionalID: "payments",
+				State:           "Ongoing",
+				TimeoutMillis:   60000,
+				StartTimestamp:  time.Now().Add(-2 * time.Minute).UnixMilli(),
+				ProducerID:      7,
+				ProducerEpoch:   3,
+				Topics: []kmsg.DescribeTransactionsResponseTransactionStateTopic{
+					{Topic: "orders", Partitions: []int32{0, 2}},
+				},
+			}}
+			resp = r
+		default:
+			t.Errorf("unexpected request key %d", key)
+			return
+		}
+		resp.SetVersion(version)
+
+		out := binary.BigEndian.AppendUint32(nil, binary.BigEndian.Uint32(req[4:]))
+		if resp.IsFlexible() && key != kmsg.ApiVersions.Int16() {
+			out = append(out, 0)
+		}
+		out = resp.AppendTo(out)
+		conn.Write(binary.BigEndian.AppendUint32(nil, uint32(len(out))))
+		conn.Write(out)
+	}
+}
+
+func TestBrokerTransactions(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go serveTransactions(t, listener)
+
+	c := &Client{config: sarama.NewConfig()}
+	transactions, err := c.brokerTransactions(sarama.NewBroker(listener.Addr().String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transactions) != 1 {
+		t.Fatalf("got %d transactions, want 1", len(transactions))
+	}
+	tx := transactions[0]
+	if tx.TransactionalID != "payments" || tx.ProducerID != 7 || tx.ProducerEpoch != 3 {
+		t.Errorf("transaction = %+v", tx)
+	}
+	if tx.PartitionCount() != 2 || len(tx.Partitions["orders"]) != 2 {
+		t.Errorf("partitions = %v, want orders 0 and 2", tx.Partitions)
+	}
+	if !tx.Overdue(time.Now()) {
+		t.Error("a transaction open for twice its timeout should be overdue")
+	}
+}
+
+func TestTransactionOverdue(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		tx   Transaction
+		want bool
+	}{
+		{"within timeout", Transaction{State: "Ongoing", Started: now.Add(-time.Second), Timeout: time.Minute}, false},
+		{"past timeout", Transaction{State: "Ongoing", Started: now.Add(-2 * time.Minute), Timeout: time.Minute}, true},
+		{"completed", Transaction{State: "CompleteCommit", Started: now.Add(-2 * time.Minute), Timeout: time.Minute}, false},
+		{"not started", Transaction{State: "Empty", Timeout: time.Minute}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tx.Overdue(now); got != tt.want {
+				t.Errorf("Overdue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package loadgen
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
+)
+
+// Config describes a load generation run. The run stops after Count messages
+// or after Duration, whichever comes first; at least one of them must be set.
+type Config struct {
+	KeyTemplate   string
+	ValueTemplate string
+	Count         int64
+	Duration      time.Duration
+	Rate          int // Target messages per second, 0 for as fast as possible
+}
+
+// Stats is a snapshot of a run's progress
+type Stats struct {
+	Sent    int64
+	Errors  int64
+	Bytes   int64
+	Elapsed time.Duration
+	LastErr error
+	Done    bool
+}
+
+// MessagesPerSec returns the average send rate
+func (s Stats) MessagesPerSec() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Sent) / s.Elapsed.Seconds()
+}
+
+// BytesPerSec returns the average throughput of keys and values
+func (s Stats) BytesPerSec() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Bytes) / s.Elapsed.Seconds()
+}
+
+// SendFunc produces a single message
+type SendFunc func(key, value string) error
+
+// TemplateData is available to key and value templates as "."
+type TemplateData struct {
+	Seq int64 // Sequence number starting at 1
+}
+
+// templateFuncs are the helpers available in templates
+var templateFuncs = template.FuncMap{
+	"uuid":       newUUID,
+	"now":        func() string { return time.Now().UTC().Format(time.RFC3339Nano) },
+	"unixMilli":  func() int64 { return time.Now().UnixMilli() },
+	"randInt":    randInt,
+	"randString": randString,
+}
+
+// Runner executes a Config and exposes live Stats
+type Runner struct {
+	config Config
+	key    *template.Template
+	value  *template.Template
+
+	sent   atomic.Int64
+	errors atomic.Int64
+	bytes  atomic.Int64
+
+	mu      sync.Mutex
+	started time.Time
+	ended   time.Time
+	lastErr error
+}
+
+// NewRunner validates the config and parses its templates
+func NewRunner(config Config) (*Runner, error) {
+	if config.Count <= 0 && config.Duration <= 0 {
+		return nil, fmt.Errorf("either a message count or a duration is required")
+	}
+	if config.Rate < 0 {
+		return nil, fmt.Errorf("rate must not be negative")
+	}
+
+	key, err := template.New("key").Funcs(templateFuncs).Parse(config.KeyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key template: %w", err)
+	}
+	value, err := template.New("value").Funcs(templateFuncs).Parse(config.ValueTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value template: %w", err)
+	}
+
+	return &Runner{config: config, key: key, value: value}, nil
+}
+
+// Run sends messages until the configured count or duration is reached or ctx
+// is cancelled. Send errors are counted rather than aborting the run.
+func (r *Runner) Run(ctx context.Context, send SendFunc) Stats {
+	if r.config.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.config.Duration)
+		defer cancel()
+	}
+
+	r.mu.Lock()
+	r.started = time.Now()
+	r.mu.Unlock()
+
+	var ticker *time.Ticker
+	if r.config.Rate > 0 {
+		if interval := time.Second / time.Duration(r.config.Rate); interval > 0 {
+			ticker = time.NewTicker(interval)
+			defer ticker.Stop()
+		}
+	}
+
+	for seq := int64(1); r.config.Count <= 0 || seq <= r.config.Count; seq++ {
+		if ticker != nil {
+			select {
+			case <-ctx.Done():
+				return r.finish()
+			case <-ticker.C:
+			}
+		} else if ctx.Err() != nil {
+			return r.finish()
+		}
+
+		key, value, err := r.Render(seq)
+		if err == nil {
+			err = send(key, value)
+		}
+		if err != nil {
+			r.errors.Add(1)
+			r.mu.Lock()
+			r.lastErr = err
+			r.mu.Unlock()
+			continue
+		}
+		r.sent.Add(1)
+		r.bytes.Add(int64(len(key) + len(value)))
+	}
+
+	return r.finish()
+}
+
+// Render executes the key and value templates for a sequence number
+func (r *Runner) Render(seq int64) (string, string, error) {
+	data := TemplateData{Seq: seq}
+
+	var key, value bytes.Buffer
+	if err := r.key.Execute(&key, data); err != nil {
+		return "", "", fmt.Errorf("key template: %w", err)
+	}
+	if err := r.value.Execute(&value, data); err != nil {
+		return "", "", fmt.Errorf("value template: %w", err)
+	}
+	return key.String(), value.String(), nil
+}
+
+// Stats returns the current progress
+func (r *Runner) Stats() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := Stats{
+		Sent:    r.sent.Load(),
+		Errors:  r.errors.Load(),
+		Bytes:   r.bytes.Load(),
+		LastErr: r.lastErr,
+		Done:    !r.ended.IsZero(),
+	}
+	switch {
+	case r.started.IsZero():
+	case stats.Done:
+		stats.Elapsed = r.ended.Sub(r.started)
+	default:
+		stats.Elapsed = time.Since(r.started)
+	}
+	return stats
+}
+
+func (r *Runner) finish() Stats {
+	r.mu.Lock()
+	r.ended = time.Now()
+	r.mu.Unlock()
+	return r.Stats()
+}
+
+// newUUID returns a random (version 4) UUID
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// randInt returns a random integer in [min, max]
+func randInt(min, max int64) (int64, error) {
+	if max < min {
+		return 0, fmt.Errorf("randInt: max %d is less than min %d", max, min)
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(max-min+1))
+	if err != nil {
+		return 0, err
+	}
+	return min + n.Int64(), nil
+}
+
+const randAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// randString returns n random alphanumeric characters
+func randString(n int) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		idx, err := rand.Int(rand.Reader, big.NewInt(int64(len(randAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(randAlphabet[idx.Int64()])
+	}
+	return sb.String(), nil
+}
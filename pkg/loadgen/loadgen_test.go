@@ -0,0 +1,90 @@
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunnerCount(t *testing.T) {
+	runner, err := NewRunner(Config{
+		KeyTemplate:   "key-{{.Seq}}",
+		ValueTemplate: `{"id":"{{uuid}}","seq":{{.Seq}},"n":{{randInt 1 6}}}`,
+		Count:         5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	stats := runner.Run(context.Background(), func(key, value string) error {
+		keys = append(keys, key)
+		if !strings.Contains(value, `"seq":`+strings.TrimPrefix(key, "key-")) {
+			t.Errorf("value %q does not carry the sequence of key %q", value, key)
+		}
+		return nil
+	})
+
+	if stats.Sent != 5 || stats.Errors != 0 || !stats.Done {
+		t.Errorf("stats = %+v, want 5 sent, 0 errors, done", stats)
+	}
+	if strings.Join(keys, ",") != "key-1,key-2,key-3,key-4,key-5" {
+		t.Errorf("keys = %v", keys)
+	}
+}
+
+func TestRunnerCountsErrors(t *testing.T) {
+	runner, err := NewRunner(Config{ValueTemplate: "x", Count: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	stats := runner.Run(context.Background(), func(key, value string) error {
+		calls++
+		if calls%2 == 0 {
+			return errors.New("broker unavailable")
+		}
+		return nil
+	})
+
+	if stats.Sent != 2 || stats.Errors != 2 || stats.LastErr == nil {
+		t.Errorf("stats = %+v, want 2 sent and 2 errors", stats)
+	}
+}
+
+func TestRunnerDurationAndRate(t *testing.T) {
+	runner, err := NewRunner(Config{ValueTemplate: "x", Duration: 200 * time.Millisecond, Rate: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := runner.Run(context.Background(), func(key, value string) error { return nil })
+
+	// 50 msg/s for 200ms is about 10 messages; allow for scheduling jitter
+	if stats.Sent < 5 || stats.Sent > 12 {
+		t.Errorf("sent %d messages, want about 10", stats.Sent)
+	}
+}
+
+func TestNewRunnerValidation(t *testing.T) {
+	if _, err := NewRunner(Config{ValueTemplate: "x"}); err == nil {
+		t.Error("expected an error without count or duration")
+	}
+	if _, err := NewRunner(Config{ValueTemplate: "{{.Missing", Count: 1}); err == nil {
+		t.Error("expected an error for an invalid template")
+	}
+}
+
+func TestNewUUID(t *testing.T) {
+	id, err := newUUID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(id) {
+		t.Errorf("newUUID() = %q, not a version 4 UUID", id)
+	}
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// bufferSize is the number of recent entries kept for the in-app log viewer
+const bufferSize = 1000
+
+// Entry is a log entry kept in memory
+type Entry struct {
+	Time    time.Time
+	Level   logrus.Level
+	Message string
+	Fields  logrus.Fields
+}
+
+// ringBuffer is a logrus hook keeping the most recent entries, whatever the
+// logger's output is
+type ringBuffer struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+	full    bool
+}
+
+var recent = &ringBuffer{entries: make([]Entry, bufferSize)}
+
+func (b *ringBuffer) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (b *ringBuffer) Fire(e *logrus.Entry) error {
+	fields := make(logrus.Fields, len(e.Data))
+	for k, v := range e.Data {
+		fields[k] = v
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entries[b.next] = Entry{Time: e.Time, Level: e.Level, Message: e.Message, Fields: fields}
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+	return nil
+}
+
+func (b *ringBuffer) snapshot() []Entry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.full {
+		return append([]Entry(nil), b.entries[:b.next]...)
+	}
+	return append(append([]Entry(nil), b.entries[b.next:]...), b.entries[:b.next]...)
+}
+
+// Recent returns the most recent log entries, oldest first
+func Recent() []Entry {
+	return recent.snapshot()
+}
@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestRingBuffer(t *testing.T) {
+	b := &ringBuffer{entries: make([]Entry, 3)}
+	if got := b.snapshot(); len(got) != 0 {
+		t.Fatalf("empty buffer returned %d entries", len(got))
+	}
+
+	for i := 1; i <= 5; i++ {
+		if err := b.Fire(&logrus.Entry{Level: logrus.InfoLevel, Message: fmt.Sprintf("m%d", i), Data: logrus.Fields{"i": i}}); err != nil {
+			t.Fatal(err)
+		}
+		got := b.snapshot()
+		if want := min(i, 3); len(got) != want {
+			t.Fatalf("after %d entries got %d, want %d", i, len(got), want)
+		}
+		if last := got[len(got)-1].Message; last != fmt.Sprintf("m%d", i) {
+			t.Errorf("after %d entries newest is %s", i, last)
+		}
+	}
+
+	got := b.snapshot()
+	if got[0].Message != "m3" || got[0].Fields["i"] != 3 {
+		t.Errorf("oldest entry = %+v, want m3", got[0])
+	}
+}
@@ -59,6 +59,9 @@ func Init(level, logFile string) error {
 			FullTimestamp:   true,
 			TimestampFormat: "2006-01-02 15:04:05",
 		})
+
+		// Keep recent entries for the log viewer, even when output is discarded
+		Log.AddHook(recent)
 	})
 	return err
 }
@@ -0,0 +1,249 @@
+// Package profiles converts the connection settings of other Kafka tools
+// into kconduit cluster profiles and adds them to the config file
+package profiles
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrExists is returned by AddToConfig when the profile is already defined
+var ErrExists = errors.New("already exists")
+
+// Profile is a cluster profile, keyed like the config file's top level
+type Profile struct {
+	Brokers              string `yaml:"brokers"`
+	SASLEnabled          bool   `yaml:"sasl_enabled,omitempty"`
+	SASLMechanism        string `yaml:"sasl_mechanism,omitempty"`
+	SASLProtocol         string `yaml:"sasl_protocol,omitempty"`
+	SASLUsername         string `yaml:"sasl_username,omitempty"`
+	SASLPassword         string `yaml:"sasl_password,omitempty"`
+	TLSEnabled           bool   `yaml:"tls_enabled,omitempty"`
+	TLSCACert            string `yaml:"tls_ca_cert,omitempty"`
+	TLSClientCert        string `yaml:"tls_client_cert,omitempty"`
+	TLSClientKey         string `yaml:"tls_client_key,omitempty"`
+	TLSClientKeyPassword string `yaml:"tls_client_key_password,omitempty"`
+	TLSKeystore          string `yaml:"tls_keystore,omitempty"`
+	TLSKeystorePassword  string `yaml:"tls_keystore_password,omitempty"`
+	TLSSkipVerify        bool   `yaml:"tls_skip_verify,omitempty"`
+}
+
+// FromKcat reads a librdkafka properties file, as used by kcat. Settings
+// kconduit has no equivalent for are returned as warnings.
+func FromKcat(data []byte) (Profile, []string, error) {
+	props := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return Profile{}, nil, fmt.Errorf("invalid line %q, expected key=value", line)
+		}
+		props[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return Profile{}, nil, err
+	}
+
+	var p Profile
+	var warnings []string
+	for key, value := range props {
+		switch key {
+		case "bootstrap.servers", "metadata.broker.list":
+			p.Brokers = value
+		case "security.protocol":
+			switch strings.ToUpper(value) {
+			case "SSL":
+				p.TLSEnabled = true
+			case "SASL_PLAINTEXT", "SASL_SSL":
+				p.SASLEnabled = true
+				p.SASLProtocol = strings.ToUpper(value)
+			case "PLAINTEXT":
+			default:
+				warnings = append(warnings, fmt.Sprintf("unsupported security.protocol %s", value))
+			}
+		case "sasl.mechanisms", "sasl.mechanism":
+			mechanism, err := saslMechanism(value)
+			if err != nil {
+				warnings = append(warnings, err.Error())
+				continue
+			}
+			p.SASLMechanism = mechanism
+		case "sasl.username":
+			p.SASLUsername = value
+		case "sasl.password":
+			p.SASLPassword = value
+		case "ssl.ca.location":
+			p.TLSCACert = value
+		case "ssl.certificate.location":
+			p.TLSClientCert = value
+		case "ssl.key.location":
+			p.TLSClientKey = value
+		case "ssl.key.password":
+			p.TLSClientKeyPassword = value
+		case "ssl.keystore.location":
+			p.TLSKeystore = value
+		case "ssl.keystore.password":
+			p.TLSKeystorePassword = value
+		case "enable.ssl.certificate.verification":
+			p.TLSSkipVerify = value == "false"
+		default:
+			warnings = append(warnings, fmt.Sprintf("%s is not supported and was left out", key))
+		}
+	}
+	sort.Strings(warnings)
+	if p.Brokers == "" {
+		return Profile{}, warnings, fmt.Errorf("no bootstrap.servers found")
+	}
+	return p, warnings, nil
+}
+
+// saslMechanism checks a SASL mechanism name is one kconduit supports
+func saslMechanism(name string) (string, error) {
+	switch m := strings.ToUpper(name); m {
+	case "PLAIN", "SCRAM-SHA-256", "SCRAM-SHA-512":
+		return m, nil
+	default:
+		return "", fmt.Errorf("unsupported SASL mechanism %s", name)
+	}
+}
+
+// kafkactlConfig is the part of a kafkactl config file describing how to
+// connect
+type kafkactlConfig struct {
+	Contexts map[string]struct {
+		Brokers []string `yaml:"brokers"`
+		TLS     struct {
+			Enabled  bool   `yaml:"enabled"`
+			CA       string `yaml:"ca"`
+			Cert     string `yaml:"cert"`
+			CertKey  string `yaml:"certKey"`
+			Insecure bool   `yaml:"insecure"`
+		} `yaml:"tls"`
+		SASL struct {
+			Enabled   bool   `yaml:"enabled"`
+			Username  string `yaml:"username"`
+			Password  string `yaml:"password"`
+			Mechanism string `yaml:"mechanism"`
+		} `yaml:"sasl"`
+	} `yaml:"contexts"`
+}
+
+// kafkactlMechanisms maps kafkactl's SASL mechanism names to kconduit's
+var kafkactlMechanisms = map[string]string{
+	"":             "PLAIN",
+	"plaintext":    "PLAIN",
+	"scram-sha256": "SCRAM-SHA-256",
+	"scram-sha512": "SCRAM-SHA-512",
+}
+
+// FromKafkactl reads the contexts of a kafkactl config file, one profile
+// each. Contexts that cannot be converted are returned as warnings.
+func FromKafkactl(data []byte) (map[string]Profile, []string, error) {
+	var config kafkactlConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse kafkactl config: %w", err)
+	}
+	if len(config.Contexts) == 0 {
+		return nil, nil, fmt.Errorf("no contexts found")
+	}
+
+	profiles := make(map[string]Profile, len(config.Contexts))
+	var warnings []string
+	for name, ctx := range config.Contexts {
+		if len(ctx.Brokers) == 0 {
+			warnings = append(warnings, fmt.Sprintf("context %s has no brokers and was left out", name))
+			continue
+		}
+		p := Profile{
+			Brokers:       strings.Join(ctx.Brokers, ","),
+			TLSEnabled:    ctx.TLS.Enabled,
+			TLSCACert:     ctx.TLS.CA,
+			TLSClientCert: ctx.TLS.Cert,
+			TLSClientKey:  ctx.TLS.CertKey,
+			TLSSkipVerify: ctx.TLS.Insecure,
+		}
+		if ctx.SASL.Enabled {
+			mechanism, ok := kafkactlMechanisms[strings.ToLower(ctx.SASL.Mechanism)]
+			if !ok {
+				warnings = append(warnings, fmt.Sprintf("context %s uses unsupported SASL mechanism %s and was left out", name, ctx.SASL.Mechanism))
+				continue
+			}
+			p.SASLEnabled, p.SASLMechanism = true, mechanism
+			p.SASLUsername, p.SASLPassword = ctx.SASL.Username, ctx.SASL.Password
+			p.SASLProtocol = "SASL_PLAINTEXT"
+			if ctx.TLS.Enabled {
+				p.SASLProtocol = "SASL_SSL"
+			}
+		}
+		profiles[name] = p
+	}
+	sort.Strings(warnings)
+	return profiles, warnings, nil
+}
+
+// AddToConfig adds a profile under clusters in a YAML config file, keeping
+// the rest of the file and its comments. An existing profile of the same
+// name is only replaced when overwrite is set.
+func AddToConfig(config []byte, name string, p Profile, overwrite bool) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(config, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("config file is not a YAML mapping")
+	}
+
+	var profile yaml.Node
+	if err := profile.Encode(p); err != nil {
+		return nil, err
+	}
+
+	clusters := mappingValue(root, "clusters")
+	if clusters == nil {
+		clusters = &yaml.Node{Kind: yaml.MappingNode}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "clusters"}, clusters)
+	}
+	if existing := mappingValue(clusters, name); existing != nil {
+		if !overwrite {
+			return nil, fmt.Errorf("cluster %s %w", name, ErrExists)
+		}
+		*existing = profile
+	} else {
+		clusters.Content = append(clusters.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, &profile)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// mappingValue returns the value of key in a mapping node, nil when absent
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
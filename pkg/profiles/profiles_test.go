@@ -0,0 +1,150 @@
+package profiles
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestFromKcat(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		want     Profile
+		warnings int
+		wantErr  bool
+	}{
+		{
+			name:  "plaintext",
+			input: "# local\nbootstrap.servers=localhost:9092\n",
+			want:  Profile{Brokers: "localhost:9092"},
+		},
+		{
+			name: "scram over tls",
+			input: `bootstrap.servers = b1:9093,b2:9093
+security.protocol=SASL_SSL
+sasl.mechanisms=SCRAM-SHA-512
+sasl.username=alice
+sasl.password=secret
+ssl.ca.location=/etc/ca.pem
+enable.ssl.certificate.verification=false`,
+			want: Profile{Brokers: "b1:9093,b2:9093", SASLEnabled: true, SASLProtocol: "SASL_SSL", SASLMechanism: "SCRAM-SHA-512",
+				SASLUsername: "alice", SASLPassword: "secret", TLSCACert: "/etc/ca.pem", TLSSkipVerify: true},
+		},
+		{
+			name:  "mutual tls",
+			input: "metadata.broker.list=b1:9093\nsecurity.protocol=ssl\nssl.certificate.location=c.pem\nssl.key.location=k.pem\nssl.key.password=pw",
+			want:  Profile{Brokers: "b1:9093", TLSEnabled: true, TLSClientCert: "c.pem", TLSClientKey: "k.pem", TLSClientKeyPassword: "pw"},
+		},
+		{
+			name:     "unsupported settings",
+			input:    "bootstrap.servers=b1:9092\nsasl.mechanisms=GSSAPI\nclient.id=kcat",
+			want:     Profile{Brokers: "b1:9092"},
+			warnings: 2,
+		},
+		{name: "no brokers", input: "security.protocol=SSL", wantErr: true},
+		{name: "invalid line", input: "bootstrap.servers", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warnings, err := FromKcat([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			if len(warnings) != tt.warnings {
+				t.Errorf("warnings = %v, want %d", warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestFromKafkactl(t *testing.T) {
+	input := `contexts:
+  local:
+    brokers:
+      - localhost:9092
+  prod:
+    brokers: [b1:9093, b2:9093]
+    tls:
+      enabled: true
+      ca: /etc/ca.pem
+    sasl:
+      enabled: true
+      username: alice
+      password: secret
+      mechanism: scram-sha512
+  oauth:
+    brokers: [b3:9093]
+    sasl:
+      enabled: true
+      mechanism: oauth
+current-context: local
+`
+	got, warnings, err := FromKafkactl([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]Profile{
+		"local": {Brokers: "localhost:9092"},
+		"prod": {Brokers: "b1:9093,b2:9093", TLSEnabled: true, TLSCACert: "/etc/ca.pem", SASLEnabled: true,
+			SASLMechanism: "SCRAM-SHA-512", SASLProtocol: "SASL_SSL", SASLUsername: "alice", SASLPassword: "secret"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "oauth") {
+		t.Errorf("warnings = %v, want one about the oauth context", warnings)
+	}
+}
+
+func TestAddToConfig(t *testing.T) {
+	existing := []byte("# my settings\nbrokers: localhost:9092\nclusters:\n  dev:\n    brokers: dev:9092\n")
+
+	out, err := AddToConfig(existing, "prod", Profile{Brokers: "b1:9093", TLSEnabled: true}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "# my settings") {
+		t.Errorf("comment lost:\n%s", out)
+	}
+	var config struct {
+		Brokers  string             `yaml:"brokers"`
+		Clusters map[string]Profile `yaml:"clusters"`
+	}
+	if err := yaml.Unmarshal(out, &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Brokers != "localhost:9092" || config.Clusters["dev"].Brokers != "dev:9092" ||
+		config.Clusters["prod"] != (Profile{Brokers: "b1:9093", TLSEnabled: true}) {
+		t.Errorf("unexpected config:\n%s", out)
+	}
+
+	if _, err := AddToConfig(out, "dev", Profile{Brokers: "other:9092"}, false); !errors.Is(err, ErrExists) {
+		t.Errorf("adding an existing profile: err = %v, want ErrExists", err)
+	}
+	out, err = AddToConfig(out, "dev", Profile{Brokers: "other:9092"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "other:9092") || strings.Contains(string(out), "dev:9092") {
+		t.Errorf("profile not replaced:\n%s", out)
+	}
+
+	out, err = AddToConfig(nil, "local", Profile{Brokers: "localhost:9092"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "clusters:\n  local:\n    brokers: localhost:9092\n" {
+		t.Errorf("new config = %q", out)
+	}
+}
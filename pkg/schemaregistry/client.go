@@ -0,0 +1,154 @@
+package schemaregistry
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/digitalis-io/kconduit/pkg/logger"
+	"github.com/linkedin/goavro/v2"
+)
+
+// ErrSubjectNotFound is returned when the registry has no schema for a subject
+var ErrSubjectNotFound = errors.New("subject not found")
+
+// magicByte starts every Confluent wire format payload
+const magicByte = 0
+
+// Schema is a registered schema version
+type Schema struct {
+	Subject    string `json:"subject"`
+	ID         int    `json:"id"`
+	Version    int    `json:"version"`
+	Schema     string `json:"schema"`
+	SchemaType string `json:"schemaType"` // Empty means AVRO
+}
+
+// Client talks to a Confluent compatible Schema Registry
+type Client struct {
+	baseURL    string
+	username   string
+	password   string
+	httpClient *http.Client
+
+	mu     sync.Mutex
+	codecs map[int]*goavro.Codec
+}
+
+// NewClient creates a registry client. username and password are optional.
+func NewClient(baseURL, username, password string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		username:   username,
+		password:   password,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		codecs:     make(map[int]*goavro.Codec),
+	}
+}
+
+// ValueSubject returns the subject of a topic's value schema using the
+// default TopicNameStrategy
+func ValueSubject(topic string) string {
+	return topic + "-value"
+}
+
+// LatestSchema fetches the latest version registered for subject
+func (c *Client) LatestSchema(subject string) (*Schema, error) {
+	endpoint := fmt.Sprintf("%s/subjects/%s/versions/latest", c.baseURL, url.PathEscape(subject))
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json")
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema registry response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s: %w", subject, ErrSubjectNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("schema registry returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var schema Schema
+	if err := json.Unmarshal(body, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse schema registry response: %w", err)
+	}
+
+	logger.Get().WithFields(logger.Fields{
+		"subject": schema.Subject,
+		"id":      schema.ID,
+		"version": schema.Version,
+	}).Debug("Fetched latest schema")
+
+	return &schema, nil
+}
+
+// IsAvro reports whether the schema can be used with EncodeAvro
+func (s *Schema) IsAvro() bool {
+	return s.SchemaType == "" || strings.EqualFold(s.SchemaType, "AVRO")
+}
+
+// EncodeAvro converts Avro JSON text into the Confluent wire format: a zero
+// magic byte, the 4 byte big-endian schema id, then the Avro binary payload.
+// Unions must use the Avro JSON encoding, e.g. {"string": "value"}.
+func (c *Client) EncodeAvro(schema *Schema, jsonText []byte) ([]byte, error) {
+	if !schema.IsAvro() {
+		return nil, fmt.Errorf("schema %d is %s, not AVRO", schema.ID, schema.SchemaType)
+	}
+
+	codec, err := c.codec(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	native, _, err := codec.NativeFromTextual(jsonText)
+	if err != nil {
+		return nil, fmt.Errorf("value does not match schema %d: %w", schema.ID, err)
+	}
+
+	header := make([]byte, 5)
+	header[0] = magicByte
+	binary.BigEndian.PutUint32(header[1:], uint32(schema.ID))
+
+	payload, err := codec.BinaryFromNative(header, native)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode avro: %w", err)
+	}
+	return payload, nil
+}
+
+// codec returns a cached codec for the schema
+func (c *Client) codec(schema *Schema) (*goavro.Codec, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if codec, ok := c.codecs[schema.ID]; ok {
+		return codec, nil
+	}
+	codec, err := goavro.NewCodec(schema.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid avro schema %d: %w", schema.ID, err)
+	}
+	c.codecs[schema.ID] = codec
+	return codec, nil
+}
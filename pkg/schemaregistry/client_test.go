@@ -0,0 +1,67 @@
+package schemaregistry
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+const userSchema = `{
+	"type": "record",
+	"name": "User",
+	"fields": [
+		{"name": "id", "type": "long"},
+		{"name": "country", "type": ["null", "string"], "default": null}
+	]
+}`
+
+func TestEncodeAvro(t *testing.T) {
+	client := NewClient("http://localhost:8081", "", "")
+	schema := &Schema{Subject: "users-value", ID: 42, Version: 1, Schema: userSchema}
+
+	payload, err := client.EncodeAvro(schema, []byte(`{"id": 7, "country": {"string": "DE"}}`))
+	if err != nil {
+		t.Fatalf("EncodeAvro failed: %v", err)
+	}
+
+	if payload[0] != magicByte {
+		t.Errorf("magic byte = %d, want %d", payload[0], magicByte)
+	}
+	if id := binary.BigEndian.Uint32(payload[1:5]); id != 42 {
+		t.Errorf("schema id = %d, want 42", id)
+	}
+
+	codec, err := goavro.NewCodec(userSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	native, rest, err := codec.NativeFromBinary(payload[5:])
+	if err != nil {
+		t.Fatalf("payload does not decode: %v", err)
+	}
+	if len(rest) != 0 {
+		t.Errorf("unexpected trailing bytes: %v", rest)
+	}
+	record := native.(map[string]interface{})
+	if record["id"] != int64(7) {
+		t.Errorf("id = %v, want 7", record["id"])
+	}
+	if country := record["country"].(map[string]interface{})["string"]; country != "DE" {
+		t.Errorf("country = %v, want DE", country)
+	}
+}
+
+func TestEncodeAvroRejectsInvalidInput(t *testing.T) {
+	client := NewClient("http://localhost:8081", "", "")
+	schema := &Schema{ID: 1, Schema: userSchema}
+
+	if _, err := client.EncodeAvro(schema, []byte(`{"id": "not a number"}`)); err == nil {
+		t.Error("expected an error for a value that does not match the schema")
+	}
+
+	jsonSchema := &Schema{ID: 2, Schema: `{}`, SchemaType: "JSON"}
+	if _, err := client.EncodeAvro(jsonSchema, []byte(`{}`)); err == nil {
+		t.Error("expected an error for a non-Avro schema")
+	}
+}
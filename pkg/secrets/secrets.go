@@ -0,0 +1,95 @@
+// Package secrets resolves credentials given as references, so passwords
+// and API keys need not appear in flags, environment or config files
+package secrets
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// Reference prefixes understood by Resolve
+const (
+	keyringPrefix = "keyring:"
+	commandPrefix = "cmd:"
+	filePrefix    = "file:"
+)
+
+// keyringGet and runCommand are replaced in tests
+var (
+	keyringGet = keyring.Get
+	runCommand = func(command string) ([]byte, error) {
+		shell, flag := "sh", "-c"
+		if runtime.GOOS == "windows" {
+			shell, flag = "cmd", "/C"
+		}
+		cmd := exec.Command(shell, flag, command)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil && stderr.Len() > 0 {
+			return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return out, err
+	}
+)
+
+// IsReference reports whether value names a secret rather than holding it
+func IsReference(value string) bool {
+	for _, prefix := range []string{keyringPrefix, commandPrefix, filePrefix} {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve returns the secret value refers to:
+//
+//	keyring:<service>/<user>  the password stored in the OS keyring
+//	cmd:<command>             the output of a shell command, e.g. cmd:pass show kafka/prod
+//	file:<path>               the contents of a file
+//
+// Trailing newlines are trimmed from command output and files. Any other
+// value is returned unchanged, so plaintext secrets keep working.
+func Resolve(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, keyringPrefix):
+		ref := strings.TrimPrefix(value, keyringPrefix)
+		service, user, ok := strings.Cut(ref, "/")
+		if !ok || service == "" || user == "" {
+			return "", fmt.Errorf("keyring reference %q must be keyring:<service>/<user>", value)
+		}
+		secret, err := keyringGet(service, user)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s/%s from the keyring: %w", service, user, err)
+		}
+		return secret, nil
+
+	case strings.HasPrefix(value, commandPrefix):
+		command := strings.TrimSpace(strings.TrimPrefix(value, commandPrefix))
+		if command == "" {
+			return "", fmt.Errorf("empty secret command")
+		}
+		out, err := runCommand(command)
+		if err != nil {
+			// The command line may itself be sensitive, so only its name is shown
+			return "", fmt.Errorf("secret command %q failed: %w", strings.Fields(command)[0], err)
+		}
+		return strings.TrimRight(string(out), "\r\n"), nil
+
+	case strings.HasPrefix(value, filePrefix):
+		path := strings.TrimPrefix(value, filePrefix)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file: %w", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+	return value, nil
+}
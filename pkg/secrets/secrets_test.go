@@ -0,0 +1,58 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	keyringGet = func(service, user string) (string, error) {
+		if service == "kafka" && user == "prod" {
+			return "from-keyring", nil
+		}
+		return "", errors.New("secret not found in keyring")
+	}
+	runCommand = func(command string) ([]byte, error) {
+		if command == "pass show kafka/prod" {
+			return []byte("from-command\n"), nil
+		}
+		return nil, errors.New("exit status 1")
+	}
+
+	path := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		value   string
+		want    string
+		wantErr string
+	}{
+		{value: "plaintext", want: "plaintext"},
+		{value: "", want: ""},
+		{value: "keyring:kafka/prod", want: "from-keyring"},
+		{value: "keyring:kafka", wantErr: "keyring:<service>/<user>"},
+		{value: "keyring:kafka/dev", wantErr: "not found"},
+		{value: "cmd:pass show kafka/prod", want: "from-command"},
+		{value: "cmd:pass show kafka/dev", wantErr: `"pass" failed`},
+		{value: "cmd:", wantErr: "empty"},
+		{value: "file:" + path, want: "from-file"},
+		{value: "file:/does/not/exist", wantErr: "secret file"},
+	}
+	for _, tt := range tests {
+		got, err := Resolve(tt.value)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Resolve(%q) error = %v, want %q", tt.value, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Resolve(%q) = %q, %v, want %q", tt.value, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Package topictags keeps the stars and tags users put on topics in a local
+// file, per cluster, so they survive restarts
+package topictags
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Marks are what a user put on one topic
+type Marks struct {
+	Starred bool     `yaml:"starred,omitempty"`
+	Tags    []string `yaml:"tags,omitempty"`
+}
+
+func (m Marks) empty() bool {
+	return !m.Starred && len(m.Tags) == 0
+}
+
+// Store holds the marks of every topic, by cluster and topic name. Changes
+// are written to the file straight away. It is safe for concurrent use.
+type Store struct {
+	mu       sync.Mutex
+	path     string
+	clusters map[string]map[string]Marks
+}
+
+// DefaultPath is topic-tags.yaml next to the default config file
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the user config directory: %w", err)
+	}
+	return filepath.Join(dir, "kconduit", "topic-tags.yaml"), nil
+}
+
+// Load reads the store at path; a missing file is an empty store
+func Load(path string) (*Store, error) {
+	s := &Store{path: path, clusters: make(map[string]map[string]Marks)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read topic tags: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &s.clusters); err != nil {
+		return nil, fmt.Errorf("failed to parse topic tags %s: %w", path, err)
+	}
+	if s.clusters == nil {
+		s.clusters = make(map[string]map[string]Marks)
+	}
+	return s, nil
+}
+
+// Get returns the marks of a topic
+func (s *Store) Get(cluster, topic string) Marks {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.clusters[cluster][topic]
+}
+
+// ToggleStar stars or unstars a topic and reports whether it is now starred
+func (s *Store) ToggleStar(cluster, topic string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	marks := s.clusters[cluster][topic]
+	marks.Starred = !marks.Starred
+	return marks.Starred, s.set(cluster, topic, marks)
+}
+
+// SetTags replaces the tags of a topic, as cleaned up by ParseTags
+func (s *Store) SetTags(cluster, topic string, tags []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	marks := s.clusters[cluster][topic]
+	marks.Tags = ParseTags(strings.Join(tags, ","))
+	return s.set(cluster, topic, marks)
+}
+
+// Tags lists every tag used on a cluster, sorted
+func (s *Store) Tags(cluster string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var tags []string
+	for _, marks := range s.clusters[cluster] {
+		for _, tag := range marks.Tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// HasStarred reports whether any topic of the cluster is starred
+func (s *Store) HasStarred(cluster string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, marks := range s.clusters[cluster] {
+		if marks.Starred {
+			return true
+		}
+	}
+	return false
+}
+
+// set stores marks and saves the file; s.mu must be held
+func (s *Store) set(cluster, topic string, marks Marks) error {
+	if s.clusters[cluster] == nil {
+		s.clusters[cluster] = make(map[string]Marks)
+	}
+	if marks.empty() {
+		delete(s.clusters[cluster], topic)
+		if len(s.clusters[cluster]) == 0 {
+			delete(s.clusters, cluster)
+		}
+	} else {
+		s.clusters[cluster][topic] = marks
+	}
+
+	data, err := yaml.Marshal(s.clusters)
+	if err != nil {
+		return fmt.Errorf("failed to encode topic tags: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to save topic tags: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to save topic tags: %w", err)
+	}
+	return nil
+}
+
+// ParseTags splits tags typed by a user on commas and spaces, dropping a
+// leading # and duplicates, and sorts them
+func ParseTags(input string) []string {
+	var tags []string
+	for _, field := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' }) {
+		tag := strings.TrimPrefix(field, "#")
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,73 @@
+package topictags
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"", nil},
+		{"billing", []string{"billing"}},
+		{"#team-a, billing  #team-a", []string{"billing", "team-a"}},
+		{" , #", nil},
+	}
+	for _, tt := range tests {
+		if got := ParseTags(tt.input); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseTags(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kconduit", "topic-tags.yaml")
+	s, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if starred, err := s.ToggleStar("prod:9092", "orders"); err != nil || !starred {
+		t.Fatalf("ToggleStar() = %v, %v", starred, err)
+	}
+	if err := s.SetTags("prod:9092", "orders", []string{"team-a", "#billing"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetTags("prod:9092", "payments", []string{"billing"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetTags("staging:9092", "orders", []string{"scratch"}); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Marks{Starred: true, Tags: []string{"billing", "team-a"}}
+	if got := reloaded.Get("prod:9092", "orders"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Get() = %+v, want %+v", got, want)
+	}
+	if got := reloaded.Tags("prod:9092"); !reflect.DeepEqual(got, []string{"billing", "team-a"}) {
+		t.Errorf("Tags() = %v", got)
+	}
+	if !reloaded.HasStarred("prod:9092") || reloaded.HasStarred("staging:9092") {
+		t.Errorf("HasStarred() is wrong")
+	}
+
+	// Clearing every mark removes the topic and then the cluster
+	if err := reloaded.SetTags("staging:9092", "orders", nil); err != nil {
+		t.Fatal(err)
+	}
+	if tags := reloaded.Tags("staging:9092"); len(tags) != 0 {
+		t.Errorf("staging tags not cleared: %v", tags)
+	}
+	if _, ok := reloaded.clusters["staging:9092"]; ok {
+		data, _ := os.ReadFile(path)
+		t.Errorf("empty cluster kept:\n%s", data)
+	}
+}
@@ -0,0 +1,209 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+// aclFilter matches ACLs against a query of space separated terms. A term may
+// be scoped with principal:, resource: or op:, otherwise it matches any of
+// those fields. All terms must match, comparisons are case-insensitive
+// substrings.
+type aclFilter struct {
+	principals []string
+	resources  []string
+	operations []string
+	any        []string
+}
+
+// parseACLFilter builds a filter from a query such as "principal:alice op:write"
+func parseACLFilter(query string) aclFilter {
+	var f aclFilter
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		key, value, ok := strings.Cut(term, ":")
+		if ok && value != "" {
+			switch key {
+			case "principal", "p":
+				f.principals = append(f.principals, value)
+				continue
+			case "resource", "r":
+				f.resources = append(f.resources, value)
+				continue
+			case "op", "operation", "o":
+				f.operations = append(f.operations, value)
+				continue
+			}
+		}
+		// Unknown prefixes such as "User:alice" are plain terms
+		f.any = append(f.any, term)
+	}
+	return f
+}
+
+// empty reports whether the filter matches everything
+func (f aclFilter) empty() bool {
+	return len(f.principals)+len(f.resources)+len(f.operations)+len(f.any) == 0
+}
+
+func (f aclFilter) matches(acl kafka.ACL) bool {
+	principal := strings.ToLower(acl.Principal)
+	resource := strings.ToLower(acl.ResourceType + ":" + acl.ResourceName)
+	operation := strings.ToLower(acl.Operation)
+
+	for _, t := range f.principals {
+		if !strings.Contains(principal, t) {
+			return false
+		}
+	}
+	for _, t := range f.resources {
+		if !strings.Contains(resource, t) {
+			return false
+		}
+	}
+	for _, t := range f.operations {
+		if !strings.Contains(operation, t) {
+			return false
+		}
+	}
+	for _, t := range f.any {
+		if !strings.Contains(principal, t) && !strings.Contains(resource, t) && !strings.Contains(operation, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterACLs returns the ACLs matching query, preserving order
+func filterACLs(acls []kafka.ACL, query string) []kafka.ACL {
+	f := parseACLFilter(query)
+	if f.empty() {
+		return acls
+	}
+	var filtered []kafka.ACL
+	for _, acl := range acls {
+		if f.matches(acl) {
+			filtered = append(filtered, acl)
+		}
+	}
+	return filtered
+}
+
+// principalSummary collapses all ACLs of one principal
+type principalSummary struct {
+	principal  string
+	count      int
+	allow      int
+	deny       int
+	resources  []string // Distinct "Type:Name" entries, sorted
+	operations []string // Distinct operations, sorted
+}
+
+// groupACLsByPrincipal summarizes ACLs per principal, sorted by principal
+func groupACLsByPrincipal(acls []kafka.ACL) []principalSummary {
+	type sets struct {
+		resources  map[string]bool
+		operations map[string]bool
+	}
+
+	byPrincipal := make(map[string]*principalSummary)
+	distinct := make(map[string]sets)
+	for _, acl := range acls {
+		s, ok := byPrincipal[acl.Principal]
+		if !ok {
+			s = &principalSummary{principal: acl.Principal}
+			byPrincipal[acl.Principal] = s
+			distinct[acl.Principal] = sets{resources: make(map[string]bool), operations: make(map[string]bool)}
+		}
+		s.count++
+		if strings.EqualFold(acl.PermissionType, "Deny") {
+			s.deny++
+		} else {
+			s.allow++
+		}
+		distinct[acl.Principal].resources[acl.ResourceType+":"+acl.ResourceName] = true
+		distinct[acl.Principal].operations[acl.Operation] = true
+	}
+
+	summaries := make([]principalSummary, 0, len(byPrincipal))
+	for principal, s := range byPrincipal {
+		s.resources = sortedKeys(distinct[principal].resources)
+		s.operations = sortedKeys(distinct[principal].operations)
+		summaries = append(summaries, *s)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].principal < summaries[j].principal })
+	return summaries
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// summarizeList joins up to max items and notes how many were left out
+func summarizeList(items []string, max int) string {
+	if len(items) <= max {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s +%d more", strings.Join(items[:max], ", "), len(items)-max)
+}
+
+var aclColumns = []table.Column{
+	{Title: "Principal", Width: 20},
+	{Title: "Resource Type", Width: 15},
+	{Title: "Resource", Width: 25},
+	{Title: "Pattern", Width: 10},
+	{Title: "Operation", Width: 15},
+	{Title: "Permission", Width: 10},
+	{Title: "Host", Width: 15},
+}
+
+var aclGroupColumns = []table.Column{
+	{Title: "Principal", Width: 25},
+	{Title: "ACLs", Width: 6},
+	{Title: "Allow/Deny", Width: 10},
+	{Title: "Resources", Width: 40},
+	{Title: "Operations", Width: 30},
+}
+
+// aclTableRows returns the columns and rows for the ACL table given the
+// current filter and grouping
+func aclTableRows(acls []kafka.ACL, query string, grouped bool) ([]table.Column, []table.Row) {
+	acls = filterACLs(acls, query)
+
+	if grouped {
+		summaries := groupACLsByPrincipal(acls)
+		rows := make([]table.Row, len(summaries))
+		for i, s := range summaries {
+			rows[i] = table.Row{
+				s.principal,
+				fmt.Sprintf("%d", s.count),
+				fmt.Sprintf("%d/%d", s.allow, s.deny),
+				summarizeList(s.resources, 3),
+				summarizeList(s.operations, 4),
+			}
+		}
+		return aclGroupColumns, rows
+	}
+
+	rows := make([]table.Row, len(acls))
+	for i, acl := range acls {
+		rows[i] = table.Row{
+			acl.Principal,
+			acl.ResourceType,
+			acl.ResourceName,
+			acl.PatternType,
+			acl.Operation,
+			acl.PermissionType,
+			acl.Host,
+		}
+	}
+	return aclColumns, rows
+}
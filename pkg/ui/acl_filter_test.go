@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+var testACLs = []kafka.ACL{
+	{Principal: "User:alice", ResourceType: "Topic", ResourceName: "orders", Operation: "Read", PermissionType: "Allow"},
+	{Principal: "User:alice", ResourceType: "Topic", ResourceName: "orders", Operation: "Write", PermissionType: "Allow"},
+	{Principal: "User:alice", ResourceType: "Group", ResourceName: "billing", Operation: "Read", PermissionType: "Deny"},
+	{Principal: "User:bob", ResourceType: "Topic", ResourceName: "payments", Operation: "Describe", PermissionType: "Allow"},
+}
+
+func TestFilterACLs(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"", []int{0, 1, 2, 3}},
+		{"principal:alice", []int{0, 1, 2}},
+		{"User:bob", []int{3}},
+		{"resource:topic:orders op:write", []int{1}},
+		{"r:group", []int{2}},
+		{"alice READ", []int{0, 2}},
+		{"op:alter", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var want []kafka.ACL
+			for _, i := range tt.want {
+				want = append(want, testACLs[i])
+			}
+			if got := filterACLs(testACLs, tt.query); !reflect.DeepEqual(got, want) {
+				t.Errorf("filterACLs(%q) = %v, want %v", tt.query, got, want)
+			}
+		})
+	}
+}
+
+func TestGroupACLsByPrincipal(t *testing.T) {
+	got := groupACLsByPrincipal(testACLs)
+	want := []principalSummary{
+		{
+			principal:  "User:alice",
+			count:      3,
+			allow:      2,
+			deny:       1,
+			resources:  []string{"Group:billing", "Topic:orders"},
+			operations: []string{"Read", "Write"},
+		},
+		{
+			principal:  "User:bob",
+			count:      1,
+			allow:      1,
+			resources:  []string{"Topic:payments"},
+			operations: []string{"Describe"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("groupACLsByPrincipal() = %+v, want %+v", got, want)
+	}
+}
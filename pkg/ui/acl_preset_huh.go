@@ -0,0 +1,295 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+)
+
+// ACLPresetHuhModel grants a producer, consumer or admin role in one step by
+// expanding it into the individual ACLs
+type ACLPresetHuhModel struct {
+	client   *kafka.Client
+	form     *huh.Form
+	creating bool
+	spinner  spinner.Model
+	err      error
+	width    int
+	height   int
+
+	preset  kafka.ACLPreset
+	confirm bool
+}
+
+var roleOptions = []huh.Option[kafka.ACLRole]{
+	huh.NewOption("📤 Producer - Write, Describe, Create on the topic", kafka.ACLRoleProducer),
+	huh.NewOption("📥 Consumer - Read, Describe on the topic and Read on the group", kafka.ACLRoleConsumer),
+	huh.NewOption("🛠️  Admin - All on the topic and group", kafka.ACLRoleAdmin),
+}
+
+func NewACLPresetHuhModel(client *kafka.Client) *ACLPresetHuhModel {
+	m := &ACLPresetHuhModel{
+		client: client,
+		preset: kafka.ACLPreset{
+			Role:        kafka.ACLRoleConsumer,
+			Host:        "*",
+			PatternType: "Literal",
+		},
+	}
+
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	m.spinner = s
+
+	m.buildForm()
+	return m
+}
+
+func (m *ACLPresetHuhModel) buildForm() {
+	theme := huh.ThemeCharm()
+	theme.Focused.Title = theme.Focused.Title.Foreground(lipgloss.Color("205"))
+	theme.Focused.SelectedOption = theme.Focused.SelectedOption.Foreground(lipgloss.Color("205"))
+
+	isRole := func(roles ...kafka.ACLRole) func() bool {
+		return func() bool {
+			for _, r := range roles {
+				if m.preset.Role == r {
+					return false
+				}
+			}
+			return true
+		}
+	}
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[kafka.ACLRole]().
+				Title("Role").
+				Options(roleOptions...).
+				Value(&m.preset.Role),
+
+			huh.NewInput().
+				Title("Principal").
+				Description("User principal (e.g., User:alice)").
+				Placeholder("User:alice").
+				Value(&m.preset.Principal).
+				Validate(validatePrincipal),
+
+			huh.NewInput().
+				Title("Host").
+				Description("Client host (* for all hosts)").
+				Value(&m.preset.Host).
+				Validate(requiredValue("host")),
+
+			huh.NewInput().
+				Title("Topic").
+				Placeholder("orders").
+				Value(&m.preset.Topic).
+				Validate(requiredValue("topic")),
+
+			huh.NewSelect[string]().
+				Title("Pattern Type").
+				Description("Applies to the topic, group and transactional id").
+				Options(patternTypes[:2]...).
+				Value(&m.preset.PatternType),
+		),
+
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Consumer Group").
+				Placeholder("orders-service").
+				Value(&m.preset.Group).
+				Validate(requiredValue("consumer group")),
+		).WithHideFunc(isRole(kafka.ACLRoleConsumer)),
+
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Consumer Group").
+				Description("Optional, leave empty to grant on the topic only").
+				Value(&m.preset.Group),
+		).WithHideFunc(isRole(kafka.ACLRoleAdmin)),
+
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Transactional ID").
+				Description("Optional, grants Write and Describe for transactional producers").
+				Value(&m.preset.TransactionalID),
+
+			huh.NewConfirm().
+				Title("Idempotent producer?").
+				Description("Grants IdempotentWrite on the cluster, only needed before Kafka 2.8").
+				Value(&m.preset.Idempotent),
+		).WithHideFunc(isRole(kafka.ACLRoleProducer)),
+
+		huh.NewGroup(
+			huh.NewNote().
+				Title("ACLs to create").
+				DescriptionFunc(m.preview, &m.preset),
+
+			huh.NewConfirm().
+				Title("Create these ACLs?").
+				Affirmative("✅ Create ACLs").
+				Negative("❌ Cancel").
+				Value(&m.confirm),
+		),
+	)
+
+	m.form = m.form.
+		WithTheme(theme).
+		WithShowHelp(true).
+		WithShowErrors(true).
+		WithWidth(m.width - 4)
+}
+
+// requiredValue returns a validator rejecting empty input
+func requiredValue(name string) func(string) error {
+	return func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return fmt.Errorf("%s cannot be empty", name)
+		}
+		return nil
+	}
+}
+
+// preview lists the ACLs the preset expands to
+func (m *ACLPresetHuhModel) preview() string {
+	acls, err := kafka.ExpandACLPreset(m.preset)
+	if err != nil {
+		return fmt.Sprintf("❌ %v", err)
+	}
+	var sb strings.Builder
+	for _, acl := range acls {
+		sb.WriteString(fmt.Sprintf("• Allow %s on %s:%s (%s) for %s from %s\n",
+			acl.Operation, acl.ResourceType, acl.ResourceName, acl.PatternType, acl.Principal, acl.Host))
+	}
+	return sb.String()
+}
+
+func (m *ACLPresetHuhModel) Init() tea.Cmd {
+	return m.form.Init()
+}
+
+func (m *ACLPresetHuhModel) createACLs() tea.Cmd {
+	preset := m.preset
+	return func() tea.Msg {
+		acls, err := kafka.ExpandACLPreset(preset)
+		if err != nil {
+			return aclCreatedMsg{err: err}
+		}
+
+		logger.Get().WithFields(logger.Fields{
+			"role":      preset.Role,
+			"principal": preset.Principal,
+			"count":     len(acls),
+		}).Info("Creating ACLs from role preset")
+
+		var errors []string
+		for _, acl := range acls {
+			if err := m.client.CreateACL(acl); err != nil {
+				errors = append(errors, fmt.Sprintf("%s %s:%s: %v", acl.Operation, acl.ResourceType, acl.ResourceName, err))
+			}
+		}
+		if len(errors) > 0 {
+			return aclCreatedMsg{err: fmt.Errorf("failed to create %d of %d ACLs: %s", len(errors), len(acls), strings.Join(errors, "; "))}
+		}
+		return aclCreatedMsg{}
+	}
+}
+
+func (m *ACLPresetHuhModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		if m.form != nil {
+			m.form = m.form.WithWidth(m.width - 4)
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			if !m.creating {
+				return m, func() tea.Msg { return ViewChangedMsg{View: ACLsTab} }
+			}
+		case "ctrl+c":
+			return m, tea.Quit
+		}
+
+	case aclCreatedMsg:
+		m.creating = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, reportError("grant role", msg.err)
+		}
+		return m, func() tea.Msg { return ViewChangedMsg{View: ACLsTab} }
+
+	case spinner.TickMsg:
+		if m.creating {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+	}
+
+	if m.creating {
+		return m, nil
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+		if m.form.State == huh.StateCompleted {
+			if !m.confirm {
+				return m, func() tea.Msg { return ViewChangedMsg{View: ACLsTab} }
+			}
+			m.creating = true
+			return m, tea.Batch(m.spinner.Tick, m.createACLs())
+		}
+	}
+
+	return m, cmd
+}
+
+func (m *ACLPresetHuhModel) View() string {
+	if m.creating {
+		return lipgloss.NewStyle().
+			Padding(2, 4).
+			Render(fmt.Sprintf("%s Granting %s role to %s...", m.spinner.View(), m.preset.Role, m.preset.Principal))
+	}
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("205")).
+		MarginBottom(1).
+		Padding(0, 2)
+
+	var errorView string
+	if m.err != nil {
+		errorView = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Bold(true).
+			Padding(1, 2).
+			Render(fmt.Sprintf("❌ Error: %v\nACLs that were created are kept, check the ACL list before retrying.", m.err))
+	}
+
+	helpText := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(0, 2).
+		Render("Use Tab/Shift+Tab to navigate • Enter to continue • Esc to cancel")
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		titleStyle.Render("🎭 Grant ACL Role"),
+		m.form.View(),
+		errorView,
+		helpText,
+	)
+}
@@ -1,101 +1,21 @@
 package ui
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"strings"
-	"time"
 
+	"github.com/digitalis-io/kconduit/pkg/ai"
 	"github.com/digitalis-io/kconduit/pkg/kafka"
 	"github.com/digitalis-io/kconduit/pkg/logger"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
-type AIProvider int
-
-const (
-	OpenAI AIProvider = iota
-	Gemini
-	Anthropic
-	Ollama
-)
-
-const aiSystemPrompt = `You are a Kafka assistant. Convert natural language commands into specific Kafka operations.
-
-For creating topics, respond with JSON:
-{"action": "create_topic", "name": "topic-name", "partitions": 3, "replication_factor": 1, "configs": {"compression.type": "gzip"}}
-
-For modifying topic partitions, respond with JSON:
-{"action": "modify_partitions", "topic": "topic-name", "partitions": 10}
-
-For modifying partitions on ALL topics, respond with JSON:
-{"action": "modify_all_partitions", "partitions": 100}
-
-For modifying topic configurations (like compression, retention, etc.), respond with JSON:
-{"action": "modify_config", "topic": "topic-name", "configs": {"compression.type": "snappy", "retention.ms": "86400000"}}
-
-For modifying configurations on ALL topics, respond with JSON:
-{"action": "modify_all_configs", "configs": {"compression.type": "gzip", "retention.ms": "604800000"}}
-
-For modifying configurations on topics matching a pattern, respond with JSON:
-{"action": "modify_matching_configs", "pattern": "starts_with:random", "configs": {"compression.type": "lz4"}}
-or
-{"action": "modify_matching_configs", "pattern": "contains:events", "configs": {"retention.ms": "86400000"}}
-or
-{"action": "modify_matching_configs", "pattern": "ends_with:log", "configs": {"compression.type": "gzip"}}
-
-For querying consumer groups (find groups with lag, list groups, etc.), respond with JSON:
-{"action": "query_consumer_groups", "filter": {"lag_greater_than": 10}}
-or
-{"action": "query_consumer_groups", "filter": {"group_id_contains": "my-group"}}
-or
-{"action": "query_consumer_groups", "filter": {"state": "Stable"}}
-
-For querying topics (list topics with specific configurations), respond with JSON:
-{"action": "query_topics", "filter": {"compression": "none"}}
-or
-{"action": "query_topics", "filter": {"partitions_greater_than": 10}}
-or
-{"action": "query_topics", "filter": {"name_contains": "events"}}
-or
-{"action": "query_topics", "filter": {"replication_factor": 3}}
-
-For ACL operations:
-
-To create an ACL, respond with JSON:
-{"action": "create_acl", "principal": "User:alice", "host": "*", "resource_type": "Topic", "resource_name": "my-topic", "pattern_type": "Literal", "operation": "Read", "permission_type": "Allow"}
-
-To create multiple ACLs at once:
-{"action": "create_acls", "acls": [{"principal": "User:alice", "host": "*", "resource_type": "Topic", "resource_name": "my-topic", "pattern_type": "Literal", "operation": "Read", "permission_type": "Allow"}, {"principal": "User:alice", "host": "*", "resource_type": "Topic", "resource_name": "my-topic", "pattern_type": "Literal", "operation": "Write", "permission_type": "Allow"}]}
-
-To delete an ACL, respond with JSON:
-{"action": "delete_acl", "principal": "User:alice", "host": "*", "resource_type": "Topic", "resource_name": "my-topic", "pattern_type": "Literal", "operation": "Read", "permission_type": "Allow"}
-
-To query/list ACLs, respond with JSON:
-{"action": "query_acls", "filter": {"principal": "User:alice"}}
-or
-{"action": "query_acls", "filter": {"resource_type": "Topic", "resource_name": "my-topic"}}
-or
-{"action": "query_acls", "filter": {}} (for all ACLs)
-
-Valid resource types: Topic, Group, Cluster, TransactionalId
-Valid operations: Read, Write, Create, Delete, Alter, Describe, ClusterAction, DescribeConfigs, AlterConfigs, IdempotentWrite, All
-Valid permission types: Allow, Deny
-Valid pattern types: Literal, Prefixed
-
-Always respond with ONLY the appropriate JSON for the requested operation. Do NOT include explanations, markdown formatting, or multiple JSON blocks. Return a single, clean JSON object that can be directly executed.
-
-If it requires multiple steps, ensure they are in the right order and all necessary fields are included.
-
-Refuse to perform any actions that are not related to Kafka. Never delete anything.`
-
 type AIConfig struct {
 	OpenAIKey      string
 	OpenAIModel    string
@@ -111,7 +31,10 @@ type AIAssistantModel struct {
 	client       *kafka.Client
 	textarea     textarea.Model
 	viewport     viewport.Model
-	provider     AIProvider
+	providers    []AIProvider      // Built-in providers followed by registered ones
+	provider     int               // Index of the selected provider
+	models       map[string]string // Model chosen per provider, when not its default
+	modelPicker  *aiModelPicker    // Open while a model is being chosen
 	config       AIConfig
 	processing   bool
 	response     string
@@ -119,6 +42,19 @@ type AIAssistantModel struct {
 	width        int
 	height       int
 	showResponse bool
+	cancelQuery  context.CancelFunc // Aborts the in-flight query
+	stream       <-chan tea.Msg     // Messages of the in-flight query
+	history      []aiTurn           // Conversation so far, oldest first
+	executor     *ai.Executor
+	policy       ai.Policy
+	lastUsage    aiUsage         // Tokens of the latest query
+	sessionUsage aiUsage         // Tokens of every query in this session
+	executing    bool            // A plan is running
+	steps        []ai.Step       // Progress of the running plan
+	execStream   <-chan tea.Msg  // Progress messages of the running plan
+	pendingACLs  []ai.ACLRequest // Proposed once the running plan finishes
+	pendingPlan  []ai.Command    // Previewed plan waiting to be confirmed
+	spinner      spinner.Model
 }
 
 func NewAIAssistantModel(client *kafka.Client, aiEngine string, aiModel string) AIAssistantModel {
@@ -162,39 +98,56 @@ func NewAIAssistantModel(client *kafka.Client, aiEngine string, aiModel string)
 		}
 	}
 
+	providers := append(builtinAIProviders(config), registeredAIProviders()...)
+	models := map[string]string{}
+
 	// Determine provider based on aiEngine parameter or available keys
-	defaultProvider := OpenAI
+	defaultProvider := providerOpenAI
 	if aiEngine != "" {
-		switch aiEngine {
-		case "openai":
-			defaultProvider = OpenAI
-		case "gemini":
-			defaultProvider = Gemini
-		case "anthropic":
-			defaultProvider = Anthropic
-		case "ollama":
-			defaultProvider = Ollama
+		for i, p := range providers {
+			if strings.EqualFold(p.Name(), aiEngine) {
+				defaultProvider = i
+				if aiModel != "" {
+					models[p.Name()] = aiModel
+				}
+			}
 		}
 	} else {
 		// Fall back to auto-detection based on available keys
 		if config.OpenAIKey == "" && config.GeminiKey != "" {
-			defaultProvider = Gemini
+			defaultProvider = providerGemini
 		} else if config.OpenAIKey == "" && config.GeminiKey == "" && config.AnthropicKey != "" {
-			defaultProvider = Anthropic
+			defaultProvider = providerAnthropic
 		} else if config.OpenAIKey == "" && config.GeminiKey == "" && config.AnthropicKey == "" {
-			defaultProvider = Ollama
+			defaultProvider = providerOllama
 		}
 	}
 
+	sp := spinner.New()
+	sp.Spinner = spinner.Dot
+	sp.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+
+	client = client.WithAuditSource("ai")
 	return AIAssistantModel{
-		client:   client,
-		textarea: ta,
-		viewport: vp,
-		provider: defaultProvider,
-		config:   config,
+		client:    client,
+		textarea:  ta,
+		viewport:  vp,
+		providers: providers,
+		provider:  defaultProvider,
+		models:    models,
+		config:    config,
+		executor:  ai.NewExecutor(client, ai.Policy{}),
+		spinner:   sp,
 	}
 }
 
+// WithPolicy limits the actions the assistant offers and runs
+func (m AIAssistantModel) WithPolicy(policy ai.Policy) AIAssistantModel {
+	m.policy = policy
+	m.executor = ai.NewExecutor(m.client, policy)
+	return m
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -258,10 +211,33 @@ type AIResponseMsg struct {
 func (m AIAssistantModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
+	if key, ok := msg.(tea.KeyMsg); ok && m.modelPicker != nil {
+		return m.updateModelPicker(key)
+	}
+	if key, ok := msg.(tea.KeyMsg); ok && m.pendingPlan != nil {
+		if model, cmd, handled := m.updatePlanConfirmation(key); handled {
+			return model, cmd
+		}
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.Type {
 		case tea.KeyEsc:
+			if m.executing {
+				// Actions already under way are not interrupted
+				return m, nil
+			}
+			if m.processing {
+				// Abort the request but keep what has streamed so far
+				m.cancel()
+				m.processing = false
+				m.history = append(m.history, aiTurn{role: aiRoleNote, content: strings.TrimSpace(m.response + "\n\n⏹ Request cancelled")})
+				m.response = ""
+				m.refreshConversation()
+				m.showResponse = true
+				return m, nil
+			}
 			if m.showResponse {
 				// Go back to input
 				m.showResponse = false
@@ -272,49 +248,175 @@ func (m AIAssistantModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, ReturnToListView
 
 		case tea.KeyCtrlC:
+			m.cancel()
+			// A plan waiting for confirmation is not applied on return
+			m.pendingPlan = nil
+			m.pendingACLs = nil
 			return m, ReturnToListView
 
+		case tea.KeyCtrlL:
+			if !m.processing && !m.executing {
+				// Start a new conversation
+				m.history = nil
+				m.pendingPlan = nil
+				m.pendingACLs = nil
+				m.response = ""
+				m.err = nil
+				m.refreshConversation()
+				m.showResponse = false
+				m.textarea.Focus()
+				return m, textarea.Blink
+			}
+
 		case tea.KeyEnter:
 			if !m.processing && !m.showResponse {
 				if m.textarea.Value() != "" {
 					m.processing = true
-					query := m.textarea.Value()
-					return m, m.processAIQuery(query)
+					m.response = ""
+					m.history = append(m.history, aiTurn{role: aiRoleUser, content: m.textarea.Value()})
+					m.textarea.Reset()
+					return m, m.processAIQuery()
 				}
 			}
 
 		case tea.KeyTab:
 			// Cycle through providers
-			switch m.provider {
-			case OpenAI:
-				m.provider = Gemini
-			case Gemini:
-				m.provider = Anthropic
-			case Anthropic:
-				m.provider = Ollama
-			case Ollama:
-				m.provider = OpenAI
+			m.provider = (m.provider + 1) % len(m.providers)
+			return m, nil
+
+		case tea.KeyCtrlO:
+			if !m.processing {
+				provider := m.providers[m.provider]
+				m.modelPicker = newAIModelPicker(provider.Name(), m.getCurrentModel(), m.height)
+				return m, listAIModels(provider)
 			}
+		}
+
+	case aiModelsMsg:
+		if m.modelPicker != nil && m.modelPicker.provider == msg.provider {
+			m.modelPicker.setModels(msg)
+		}
+		return m, nil
+
+	case aiChunkMsg:
+		if msg.stream != m.stream {
+			// Left over from a cancelled query
 			return m, nil
 		}
+		m.response += msg.text
+		m.refreshConversation()
+		m.showResponse = true
+		return m, waitForAI(m.stream)
 
-	case AIResponseMsg:
+	case aiStreamDoneMsg:
+		if msg.stream != m.stream {
+			return m, nil
+		}
+		m.cancel()
 		m.processing = false
+		m.response = ""
+		if !msg.usage.empty() {
+			m.lastUsage = msg.usage
+			m.sessionUsage = m.sessionUsage.add(msg.usage)
+		}
 		if msg.err != nil {
 			m.err = msg.err
-			m.response = fmt.Sprintf("Error: %v", msg.err)
+			m.history = append(m.history, aiTurn{role: aiRoleNote, content: fmt.Sprintf("Error: %v", msg.err)})
+			cmds = append(cmds, reportError("ai", msg.err))
 		} else {
-			// Format the response with proper wrapping
-			m.response = wrapText(msg.response, m.viewport.Width-4)
 			m.err = nil
+			content := msg.response
+			for _, call := range msg.calls {
+				content = strings.TrimSpace(content + "\n" + ai.DescribeCall(call.Name, call.Arguments))
+			}
+			m.history = append(m.history, aiTurn{role: aiRoleAssistant, content: content})
+			if msg.local {
+				m.history = append(m.history, aiTurn{role: aiRoleNote, content: "⚡ Understood locally, no AI provider was used"})
+			}
 			// Try to execute the command
-			if cmd := m.parseAndExecuteCommand(msg.response); cmd != nil {
+			var cmd tea.Cmd
+			switch {
+			case msg.readOnly:
+			case msg.tools:
+				// Only the tools called are run, text is just an answer
+				cmd = m.executeToolCalls(msg.calls)
+			default:
+				cmd = m.parseAndExecuteCommand(msg.response)
+			}
+			if cmd != nil {
 				cmds = append(cmds, cmd)
 			}
 		}
-		m.viewport.SetContent(m.response)
+		m.refreshConversation()
 		m.showResponse = true
-		m.viewport.GotoTop()
+		return m, tea.Batch(cmds...)
+
+	case aiACLProposalMsg:
+		var sb strings.Builder
+		sb.WriteString("📝 Opened the Create ACL form to review and confirm:")
+		for _, r := range msg.requests {
+			sb.WriteString(fmt.Sprintf("\n  • %s %s %s on %s %s", r.Principal, r.PermissionType, strings.Join(r.Operations, ","), r.ResourceType, r.ResourceName))
+		}
+		m.history = append(m.history, aiTurn{role: aiRoleResult, content: sb.String()})
+		m.refreshConversation()
+		return m, nil
+
+	case aiPreviewMsg:
+		m.history = append(m.history, aiTurn{role: aiRoleResult, content: msg.preview})
+		if msg.err != nil {
+			m.err = msg.err
+			cmds = append(cmds, reportError("ai action", msg.err))
+		} else {
+			m.pendingPlan = msg.commands
+			m.pendingACLs = msg.requests
+		}
+		m.refreshConversation()
+		m.showResponse = true
+		return m, tea.Batch(cmds...)
+
+	case aiStepMsg:
+		if msg.stream != m.execStream {
+			return m, nil
+		}
+		if msg.step.Done && len(m.steps) > 0 {
+			m.steps[len(m.steps)-1] = msg.step
+		} else {
+			m.steps = append(m.steps, msg.step)
+		}
+		m.refreshConversation()
+		m.showResponse = true
+		return m, waitForAI(m.execStream)
+
+	case spinner.TickMsg:
+		if !m.executing {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		m.refreshConversation()
+		return m, cmd
+
+	case AIResponseMsg:
+		// Outcome of executing the assistant's actions
+		m.executing = false
+		m.steps = nil
+		m.execStream = nil
+		if len(m.pendingACLs) > 0 {
+			requests := m.pendingACLs
+			m.pendingACLs = nil
+			cmds = append(cmds, func() tea.Msg { return aiACLProposalMsg{requests: requests} })
+		}
+		m.err = msg.err
+		result := msg.response
+		if result == "" && msg.err != nil {
+			result = fmt.Sprintf("Error: %v", msg.err)
+		}
+		m.history = append(m.history, aiTurn{role: aiRoleResult, content: result})
+		m.refreshConversation()
+		m.showResponse = true
+		if msg.err != nil {
+			cmds = append(cmds, reportError("ai action", msg.err))
+		}
 		return m, tea.Batch(cmds...)
 
 	case tea.WindowSizeMsg:
@@ -332,6 +434,7 @@ func (m AIAssistantModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.viewport.Height > 40 {
 			m.viewport.Height = 40 // Cap max height
 		}
+		m.refreshConversation()
 	}
 
 	// Update the appropriate component
@@ -360,6 +463,9 @@ func (m AIAssistantModel) View() string {
 
 	providerText := m.getProviderName()
 	modelText := m.getCurrentModel()
+	if modelText == "" {
+		modelText = "provider default"
+	}
 	title := titleStyle.Render("🤖 AI Assistant")
 	s.WriteString(title)
 	s.WriteString("\n\n")
@@ -388,19 +494,33 @@ func (m AIAssistantModel) View() string {
 		statusColor = lipgloss.Color("214") // orange
 	}
 
-	providerInfo := lipgloss.NewStyle().Foreground(statusColor).Render(
-		fmt.Sprintf("%s Provider: %s\n   Model: %s\n   Status: %s",
-			statusIcon, providerText, modelText, apiKeyStatus))
+	info := fmt.Sprintf("%s Provider: %s\n   Model: %s\n   Status: %s",
+		statusIcon, providerText, modelText, apiKeyStatus)
+	if !m.providerReady() {
+		info += "\n   Offline: creating topics, changing retention and lag queries still work"
+	}
+	if m.policy.Restricted() {
+		info += fmt.Sprintf("\n   Policy: %d of %d actions allowed", len(m.policy.AllowedSpecs()), len(ai.Specs()))
+	}
+	if strings.TrimSpace(m.policy.Instructions) != "" {
+		info += "\n   Instructions: from ai_policy in the config file"
+	}
+	if !m.sessionUsage.empty() {
+		info += fmt.Sprintf("\n   Tokens: last %s, session %s", m.lastUsage, m.sessionUsage)
+	}
+	providerInfo := lipgloss.NewStyle().Foreground(statusColor).Render(info)
 
 	s.WriteString(providerStyle.Render(providerInfo))
 	s.WriteString("\n\n")
 
-	// Show input or response
-	if m.showResponse {
+	// Show the model picker, input or response
+	if m.modelPicker != nil {
+		s.WriteString(m.modelPicker.View())
+	} else if m.showResponse {
 		responseStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("252")).
 			Bold(true)
-		s.WriteString(responseStyle.Render("📝 Response:"))
+		s.WriteString(responseStyle.Render("💬 Conversation:"))
 
 		// Add scroll indicators if needed
 		if m.viewport.TotalLineCount() > m.viewport.Height {
@@ -419,7 +539,18 @@ func (m AIAssistantModel) View() string {
 
 		helpStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("241"))
-		s.WriteString(helpStyle.Render("Press ESC to enter a new query, or Ctrl+C to exit"))
+		if m.pendingPlan != nil {
+			confirmStyle := lipgloss.NewStyle().
+				Foreground(lipgloss.Color("220")).
+				Bold(true)
+			s.WriteString(confirmStyle.Render("Press y to apply these changes or n to cancel"))
+		} else if m.executing {
+			s.WriteString(helpStyle.Render("⏳ Running the actions... steps already started are not cancelled"))
+		} else if m.processing {
+			s.WriteString(helpStyle.Render("🔄 Receiving response... Press ESC to cancel"))
+		} else {
+			s.WriteString(helpStyle.Render("Press ESC to ask a follow-up, Ctrl+L to start over, or Ctrl+C to exit"))
+		}
 	} else {
 		s.WriteString(m.textarea.View())
 		s.WriteString("\n\n")
@@ -428,14 +559,17 @@ func (m AIAssistantModel) View() string {
 			processingStyle := lipgloss.NewStyle().
 				Foreground(lipgloss.Color("220")).
 				Bold(true)
-			s.WriteString(processingStyle.Render("🔄 Processing your request..."))
+			s.WriteString(processingStyle.Render("🔄 Processing your request... (ESC to cancel)"))
 		} else {
 			// Help text with better formatting
 			helpStyle := lipgloss.NewStyle().
 				Foreground(lipgloss.Color("241"))
 
 			availableProviders := m.getAvailableProviders()
-			helpText := fmt.Sprintf("Enter: Send | Tab: Switch provider (%s) | ESC: Exit", availableProviders)
+			helpText := fmt.Sprintf("Enter: Send | Tab: Switch provider (%s) | Ctrl+O: Choose model | ESC: Exit", availableProviders)
+			if len(m.history) > 0 {
+				helpText = fmt.Sprintf("💬 %d messages in this conversation | Ctrl+L: New conversation\n", len(m.history)) + helpText
+			}
 			s.WriteString(helpStyle.Render(helpText))
 		}
 	}
@@ -444,1347 +578,358 @@ func (m AIAssistantModel) View() string {
 }
 
 func (m AIAssistantModel) getProviderName() string {
-	switch m.provider {
-	case OpenAI:
-		return "OpenAI"
-	case Gemini:
-		return "Gemini"
-	case Anthropic:
-		return "Anthropic"
-	case Ollama:
-		return "Ollama"
-	default:
-		return "Unknown"
-	}
+	return m.providers[m.provider].Name()
 }
 
 func (m AIAssistantModel) getCurrentModel() string {
-	switch m.provider {
-	case OpenAI:
-		return m.config.OpenAIModel
-	case Gemini:
-		return m.config.GeminiModel
-	case Anthropic:
-		return m.config.AnthropicModel
-	case Ollama:
-		return m.config.OllamaModel
-	default:
-		return "unknown"
+	p := m.providers[m.provider]
+	if model := m.models[p.Name()]; model != "" {
+		return model
 	}
-}
-
-func (m AIAssistantModel) getAPIKeyStatus() string {
-	switch m.provider {
-	case OpenAI:
-		if m.config.OpenAIKey != "" {
-			return "Configured"
-		}
-		return "API key not set (OPENAI_API_KEY)"
-	case Gemini:
-		if m.config.GeminiKey != "" {
-			return "Configured"
-		}
-		return "API key not set (GEMINI_API_KEY)"
-	case Anthropic:
-		if m.config.AnthropicKey != "" {
-			return "Configured"
-		}
-		return "API key not set (ANTHROPIC_API_KEY)"
-	case Ollama:
-		// Check if Ollama is running by attempting a simple request
-		return "Local (no API key needed)"
-	default:
-		return "Unknown"
+	if models := p.Models(); len(models) > 0 {
+		return models[0]
 	}
+	// Left to the provider
+	return ""
 }
 
-func (m AIAssistantModel) getAvailableProviders() string {
-	var available []string
-
-	if m.config.OpenAIKey != "" {
-		available = append(available, "OpenAI✓")
-	} else {
-		available = append(available, "OpenAI")
-	}
+// updateModelPicker handles keys while the model picker is open
+func (m AIAssistantModel) updateModelPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlO:
+		m.modelPicker = nil
+		return m, nil
+	case tea.KeyCtrlC:
+		m.modelPicker = nil
+		m.pendingPlan = nil
+		m.pendingACLs = nil
+		return m, ReturnToListView
+	}
+	if model, ok := m.modelPicker.update(msg); ok {
+		m.models[m.modelPicker.provider] = model
+		m.modelPicker = nil
+	}
+	return m, nil
+}
 
-	if m.config.GeminiKey != "" {
-		available = append(available, "Gemini✓")
-	} else {
-		available = append(available, "Gemini")
-	}
+func (m AIAssistantModel) getAPIKeyStatus() string {
+	status, _ := providerSetup(m.providers[m.provider])
+	return status
+}
 
-	if m.config.AnthropicKey != "" {
-		available = append(available, "Anthropic✓")
-	} else {
-		available = append(available, "Anthropic")
+// providerSetup returns a provider's state and whether it can be queried.
+// Providers that need no setting up are always ready.
+func providerSetup(p AIProvider) (string, bool) {
+	if s, ok := p.(aiProviderSetup); ok {
+		return s.Setup()
 	}
+	return "Configured", true
+}
 
-	available = append(available, "Ollama")
-
-	// Highlight current provider
-	for i, p := range available {
-		switch m.provider {
-		case OpenAI:
-			if strings.HasPrefix(p, "OpenAI") {
-				available[i] = "[" + p + "]"
-			}
-		case Gemini:
-			if strings.HasPrefix(p, "Gemini") {
-				available[i] = "[" + p + "]"
-			}
-		case Anthropic:
-			if strings.HasPrefix(p, "Anthropic") {
-				available[i] = "[" + p + "]"
-			}
-		case Ollama:
-			if p == "Ollama" {
-				available[i] = "[" + p + "]"
+func (m AIAssistantModel) getAvailableProviders() string {
+	available := make([]string, len(m.providers))
+	for i, p := range m.providers {
+		available[i] = p.Name()
+		if _, needsKey := p.(*ollamaProvider); !needsKey {
+			if _, ready := providerSetup(p); ready {
+				available[i] += "✓"
 			}
 		}
+		// Highlight current provider
+		if i == m.provider {
+			available[i] = "[" + available[i] + "]"
+		}
 	}
-
 	return strings.Join(available, " → ")
 }
 
-func (m *AIAssistantModel) processAIQuery(query string) tea.Cmd {
-	return func() tea.Msg {
-		var response string
-		var err error
-
-		switch m.provider {
-		case OpenAI:
-			response, err = m.queryOpenAI(query)
-		case Gemini:
-			response, err = m.queryGemini(query)
-		case Anthropic:
-			response, err = m.queryAnthropic(query)
-		case Ollama:
-			response, err = m.queryOllama(query)
-		default:
-			err = fmt.Errorf("unsupported AI provider")
-		}
-
-		return AIResponseMsg{response: response, err: err}
-	}
+// processAIQuery sends the conversation in the background and streams the
+// reply as aiChunkMsgs followed by an aiStreamDoneMsg with the full text
+func (m *AIAssistantModel) processAIQuery() tea.Cmd {
+	// Common requests are understood without a provider or network access.
+	// With instructions of its own the policy needs the model to apply them,
+	// so the local rules are then only a fallback.
+	request := m.history[len(m.history)-1].content
+	localFirst := strings.TrimSpace(m.policy.Instructions) == "" || !m.providerReady()
+	if response, ok := ai.ParseLocal(request); ok && localFirst {
+		return m.finishQuery(aiStreamDoneMsg{response: response, local: true})
+	}
+	if !m.providerReady() {
+		return m.finishQuery(aiStreamDoneMsg{err: fmt.Errorf("%s. Without a provider only simple requests are understood, e.g. %q",
+			m.getAPIKeyStatus(), strings.Join(ai.LocalExamples, `", "`))})
+	}
+
+	// Providers with native tool calling are given the actions as tools,
+	// others are shown them as JSON to reply with
+	msgs := conversationMessages(m.history)
+	if _, ok := m.providers[m.provider].(aiToolCaller); ok {
+		return m.startQuery(false, ai.Tools(m.policy), func() (string, []AIMessage, error) {
+			return ai.ToolSystemPrompt(m.policy) + "\n\n" + clusterContext(m.client), msgs, nil
+		})
+	}
+	return m.startQuery(false, nil, func() (string, []AIMessage, error) {
+		return ai.SystemPrompt(m.policy) + "\n\n" + clusterContext(m.client), msgs, nil
+	})
 }
 
-func (m *AIAssistantModel) queryOpenAI(query string) (string, error) {
-	if m.config.OpenAIKey == "" {
-		return "", fmt.Errorf("openAI API key not configured; set OPENAI_API_KEY environment variable")
-	}
-
-	requestBody := map[string]interface{}{
-		"model": m.config.OpenAIModel,
-		"messages": []map[string]string{
-			{"role": "system", "content": aiSystemPrompt},
-			{"role": "user", "content": query},
-		},
-		"temperature": 0.3,
-	}
-
-	jsonBody, err := json.Marshal(requestBody)
-	if err != nil {
-		return "", err
-	}
-
-	req, err := http.NewRequest("POST", "https://api.openai.com/v1/chat/completions", bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return "", err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+m.config.OpenAIKey)
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			// Body close errors are typically safe to ignore in HTTP clients
-			_ = err
-		}
-	}()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
-	var result map[string]interface{}
-	if err := json.Unmarshal(body, &result); err != nil {
-		return "", err
-	}
-
-	choices, ok := result["choices"].([]interface{})
-	if !ok || len(choices) == 0 {
-		return "", fmt.Errorf("unexpected API response format")
-	}
-
-	firstChoice := choices[0].(map[string]interface{})
-	message := firstChoice["message"].(map[string]interface{})
-	content := message["content"].(string)
-
-	return content, nil
+// Explain asks the model to explain a topic's configuration and suggest
+// tuning. The answer is shown read-only; nothing in it is executed.
+func (m AIAssistantModel) Explain(topic string) (AIAssistantModel, tea.Cmd) {
+	m.cancel()
+	m.processing = true
+	m.response = ""
+	m.history = append(m.history, aiTurn{role: aiRoleUser, content: fmt.Sprintf("Explain the configuration of topic %s", topic)})
+	m.refreshConversation()
+	m.showResponse = true
+
+	client := m.client
+	cmd := m.startQuery(true, nil, func() (string, []AIMessage, error) {
+		cfg, err := client.GetTopicConfig(topic)
+		if err != nil {
+			return "", nil, err
+		}
+		return ai.ExplainPrompt, []AIMessage{{Role: "user", Content: ai.ExplainRequest(cfg)}}, nil
+	})
+	return m, cmd
 }
 
-func (m *AIAssistantModel) queryGemini(query string) (string, error) {
-	if m.config.GeminiKey == "" {
-		return "", fmt.Errorf("gemini API key not configured; set GEMINI_API_KEY environment variable")
-	}
-
-	fullPrompt := aiSystemPrompt + "\n\nUser: " + query
-
-	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent?key=%s",
-		m.config.GeminiModel, m.config.GeminiKey)
-
-	requestBody := map[string]interface{}{
-		"contents": []map[string]interface{}{
-			{
-				"parts": []map[string]string{
-					{"text": fullPrompt},
-				},
-			},
-		},
-		"generationConfig": map[string]interface{}{
-			"temperature":     0.3,
-			"maxOutputTokens": 2048,
-		},
-	}
-
-	jsonBody, err := json.Marshal(requestBody)
-	if err != nil {
-		return "", err
-	}
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return "", err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			// Body close errors are typically safe to ignore in HTTP clients
-			_ = err
-		}
+// startQuery runs prepare and then the query in the background, streaming
+// the reply as aiChunkMsgs followed by an aiStreamDoneMsg. tools are the
+// actions offered to a provider with tool calling.
+func (m *AIAssistantModel) startQuery(readOnly bool, tools []ai.Tool, prepare func() (string, []AIMessage, error)) tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := make(chan tea.Msg)
+	m.cancelQuery = cancel
+	m.stream = stream
+
+	go func() {
+		defer close(stream)
+		send := func(msg tea.Msg) {
+			select {
+			case stream <- msg:
+			case <-ctx.Done():
+			}
+		}
+		onChunk := func(text string) {
+			send(aiChunkMsg{text: text, stream: stream})
+		}
+
+		system, msgs, err := prepare()
+		if err != nil {
+			send(aiStreamDoneMsg{err: err, stream: stream})
+			return
+		}
+
+		provider := m.providers[m.provider]
+		reply, err := provider.Stream(ctx, AIRequest{Model: m.getCurrentModel(), System: system, Messages: msgs, Tools: tools}, onChunk)
+		usage := reply.usage()
+		if !usage.empty() {
+			usage = priceUsage(provider, m.getCurrentModel(), usage)
+		}
+		send(aiStreamDoneMsg{
+			response: reply.Text,
+			calls:    reply.Calls,
+			tools:    len(tools) > 0,
+			usage:    usage,
+			err:      err,
+			stream:   stream,
+			readOnly: readOnly,
+		})
 	}()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("gemini API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
-	var result map[string]interface{}
-	if err := json.Unmarshal(body, &result); err != nil {
-		return "", err
-	}
-
-	// Parse Gemini response
-	candidates, ok := result["candidates"].([]interface{})
-	if !ok || len(candidates) == 0 {
-		return "", fmt.Errorf("unexpected Gemini API response format")
-	}
-
-	firstCandidate := candidates[0].(map[string]interface{})
-	content, ok := firstCandidate["content"].(map[string]interface{})
-	if !ok {
-		return "", fmt.Errorf("unexpected Gemini response structure")
-	}
-
-	parts, ok := content["parts"].([]interface{})
-	if !ok || len(parts) == 0 {
-		return "", fmt.Errorf("no content parts in Gemini response")
-	}
-
-	firstPart := parts[0].(map[string]interface{})
-	text, ok := firstPart["text"].(string)
-	if !ok {
-		return "", fmt.Errorf("no text in Gemini response part")
-	}
-
-	return text, nil
+	return waitForAI(stream)
 }
 
-func (m *AIAssistantModel) queryAnthropic(query string) (string, error) {
-	if m.config.AnthropicKey == "" {
-		return "", fmt.Errorf("anthropic API key not configured; set ANTHROPIC_API_KEY environment variable")
-	}
-
-	requestBody := map[string]interface{}{
-		"model":      m.config.AnthropicModel,
-		"max_tokens": 2048,
-		"messages": []map[string]string{
-			{"role": "user", "content": query},
-		},
-		"system":      aiSystemPrompt,
-		"temperature": 0.3,
-	}
-
-	jsonBody, err := json.Marshal(requestBody)
-	if err != nil {
-		return "", err
-	}
-
-	req, err := http.NewRequest("POST", "https://api.anthropic.com/v1/messages", bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return "", err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", m.config.AnthropicKey)
-	req.Header.Set("anthropic-version", "2023-06-01")
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			// Body close errors are typically safe to ignore in HTTP clients
-			_ = err
-		}
-	}()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("anthropic API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
-	var result map[string]interface{}
-	if err := json.Unmarshal(body, &result); err != nil {
-		return "", err
-	}
-
-	// Parse Anthropic response
-	content, ok := result["content"].([]interface{})
-	if !ok || len(content) == 0 {
-		return "", fmt.Errorf("unexpected Anthropic API response format")
-	}
-
-	firstContent := content[0].(map[string]interface{})
-	text, ok := firstContent["text"].(string)
-	if !ok {
-		return "", fmt.Errorf("no text in Anthropic response")
-	}
-
-	return text, nil
+// finishQuery completes a query straight away with done, as if it had been
+// streamed
+func (m *AIAssistantModel) finishQuery(done aiStreamDoneMsg) tea.Cmd {
+	stream := make(chan tea.Msg, 1)
+	done.stream = stream
+	stream <- done
+	close(stream)
+	m.cancelQuery = nil
+	m.stream = stream
+	return waitForAI(stream)
 }
 
-func (m *AIAssistantModel) queryOllama(query string) (string, error) {
-	requestBody := map[string]interface{}{
-		"model":  m.config.OllamaModel,
-		"prompt": aiSystemPrompt + "\n\nUser: " + query + "\n\nAssistant:",
-		"stream": false,
-	}
-
-	jsonBody, err := json.Marshal(requestBody)
-	if err != nil {
-		return "", err
-	}
-
-	req, err := http.NewRequest("POST", m.config.OllamaURL+"/api/generate", bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return "", err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{Timeout: 60 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to connect to Ollama. Make sure it's running: %w", err)
-	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			// Body close errors are typically safe to ignore in HTTP clients
-			_ = err
-		}
-	}()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("ollama request failed with status %d: %s", resp.StatusCode, string(body))
-	}
+// providerReady reports whether the selected provider can be queried. Ollama
+// needs no API key, so it is assumed to be running.
+func (m AIAssistantModel) providerReady() bool {
+	_, ready := providerSetup(m.providers[m.provider])
+	return ready
+}
 
-	var result map[string]interface{}
-	if err := json.Unmarshal(body, &result); err != nil {
-		return "", err
+// cancel aborts the in-flight query, if any
+func (m *AIAssistantModel) cancel() {
+	if m.cancelQuery != nil {
+		m.cancelQuery()
+		m.cancelQuery = nil
 	}
+	m.stream = nil
+}
 
-	response, ok := result["response"].(string)
-	if !ok {
-		return "", fmt.Errorf("unexpected Ollama response format")
+// refreshConversation redraws the conversation and scrolls to the latest turn
+func (m *AIAssistantModel) refreshConversation() {
+	content := renderConversation(m.history, m.response, m.viewport.Width-4)
+	if m.executing {
+		content += "\n\n" + m.progressView(m.viewport.Width-4)
 	}
-
-	return response, nil
+	m.viewport.SetContent(content)
+	m.viewport.GotoBottom()
 }
 
-func (m *AIAssistantModel) executeMultipleCommands(commands []map[string]interface{}) tea.Cmd {
-	log := logger.Get()
-	
-	return func() tea.Msg {
-		var responses []string
-		
-		for i, command := range commands {
-			action, ok := command["action"].(string)
-			if !ok {
-				continue
-			}
-			
-			log.WithField("action", action).WithField("step", i+1).Info("Executing command")
-			
-			// Execute each command synchronously
-			var result string
-			var err error
-			
-			switch action {
-			case "modify_partitions":
-				topic, _ := command["topic"].(string)
-				partitions, _ := command["partitions"].(float64)
-				
-				if topic != "" && partitions > 0 {
-					err = m.client.ModifyTopicPartitions(topic, int32(partitions))
-					if err != nil {
-						result = fmt.Sprintf("❌ Failed to modify partitions for %s: %v", topic, err)
-					} else {
-						result = fmt.Sprintf("✅ Successfully increased partitions for '%s' to %d", topic, int(partitions))
-					}
-				}
-				
-			case "modify_config":
-				topic, _ := command["topic"].(string)
-				configs, _ := command["configs"].(map[string]interface{})
-				
-				if topic != "" && configs != nil {
-					var configChanges []string
-					var configErrors []string
-					
-					for key, value := range configs {
-						if strValue, ok := value.(string); ok {
-							if err := m.client.UpdateTopicConfig(topic, key, strValue); err != nil {
-								configErrors = append(configErrors, fmt.Sprintf("%s: %v", key, err))
-							} else {
-								configChanges = append(configChanges, fmt.Sprintf("%s=%s", key, strValue))
-							}
-						}
-					}
-					
-					if len(configErrors) > 0 {
-						result = fmt.Sprintf("⚠️ Partially updated '%s'. Success: %s, Failed: %s", 
-							topic, strings.Join(configChanges, ", "), strings.Join(configErrors, ", "))
-					} else if len(configChanges) > 0 {
-						result = fmt.Sprintf("✅ Successfully updated '%s': %s", topic, strings.Join(configChanges, ", "))
-					}
-				}
-				
-			case "create_topic":
-				name, _ := command["name"].(string)
-				partitions, _ := command["partitions"].(float64)
-				replicationFactor, _ := command["replication_factor"].(float64)
-				
-				if name != "" {
-					err = m.client.CreateTopic(name, int32(partitions), int16(replicationFactor))
-					if err != nil {
-						result = fmt.Sprintf("❌ Failed to create topic %s: %v", name, err)
-					} else {
-						result = fmt.Sprintf("✅ Successfully created topic '%s'", name)
-						
-						// Apply configs if any
-						if configs, ok := command["configs"].(map[string]interface{}); ok {
-							for key, value := range configs {
-								if strValue, ok := value.(string); ok {
-									if err := m.client.UpdateTopicConfig(name, key, strValue); err != nil {
-										// Log error but continue processing other configs
-										fmt.Printf("Failed to update config %s for topic %s: %v\n", key, name, err)
-									}
-								}
-							}
-						}
-					}
-				}
-				
-			case "create_acl":
-				principal, _ := command["principal"].(string)
-				host, _ := command["host"].(string)
-				resourceType, _ := command["resource_type"].(string)
-				resourceName, _ := command["resource_name"].(string)
-				patternType, _ := command["pattern_type"].(string)
-				operation, _ := command["operation"].(string)
-				permissionType, _ := command["permission_type"].(string)
-				
-				if principal != "" && resourceType != "" && resourceName != "" {
-					acl := kafka.ACL{
-						Principal:      principal,
-						Host:           host,
-						ResourceType:   resourceType,
-						ResourceName:   resourceName,
-						PatternType:    patternType,
-						Operation:      operation,
-						PermissionType: permissionType,
-					}
-					
-					err = m.client.CreateACL(acl)
-					if err != nil {
-						result = fmt.Sprintf("❌ Failed to create ACL: %v", err)
-					} else {
-						result = fmt.Sprintf("✅ Created ACL: %s on %s %s (%s %s)", 
-							principal, resourceType, resourceName, operation, permissionType)
-					}
-				}
-				
-			case "delete_acl":
-				principal, _ := command["principal"].(string)
-				host, _ := command["host"].(string)
-				resourceType, _ := command["resource_type"].(string)
-				resourceName, _ := command["resource_name"].(string)
-				patternType, _ := command["pattern_type"].(string)
-				operation, _ := command["operation"].(string)
-				permissionType, _ := command["permission_type"].(string)
-				
-				if principal != "" && resourceType != "" && resourceName != "" {
-					acl := kafka.ACL{
-						Principal:      principal,
-						Host:           host,
-						ResourceType:   resourceType,
-						ResourceName:   resourceName,
-						PatternType:    patternType,
-						Operation:      operation,
-						PermissionType: permissionType,
-					}
-					
-					err = m.client.DeleteACL(acl)
-					if err != nil {
-						result = fmt.Sprintf("❌ Failed to delete ACL: %v", err)
-					} else {
-						result = fmt.Sprintf("✅ Deleted ACL: %s on %s %s (%s %s)", 
-							principal, resourceType, resourceName, operation, permissionType)
-					}
-				}
-			}
-			
-			if result != "" {
-				responses = append(responses, fmt.Sprintf("Step %d: %s", i+1, result))
-			}
-		}
-		
-		// Combine all responses
-		finalResponse := strings.Join(responses, "\n")
-		if finalResponse == "" {
-			finalResponse = "No actions were executed"
-		}
-		
-		return AIResponseMsg{
-			response: finalResponse,
-			err:      nil,
+// progressView shows the finished steps of the running plan and the one
+// under way
+func (m AIAssistantModel) progressView(width int) string {
+	if len(m.steps) == 0 {
+		return m.spinner.View() + " Starting..."
+	}
+	var parts []string
+	for _, step := range m.steps {
+		if step.Done {
+			parts = append(parts, wrapText(step.Result, width))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s Step %d of %d: running %s...", m.spinner.View(), step.Index+1, step.Total, step.Name))
 		}
 	}
+	return strings.Join(parts, "\n")
 }
 
+// parseAndExecuteCommand runs the actions found in a model response, for
+// providers shown the actions as JSON
 func (m *AIAssistantModel) parseAndExecuteCommand(response string) tea.Cmd {
-	log := logger.Get()
-
-	// Remove markdown code block markers if present
-	response = strings.ReplaceAll(response, "```json", "")
-	response = strings.ReplaceAll(response, "```", "")
-
-	// Find all JSON objects in the response
-	var commands []map[string]interface{}
-	remaining := response
-
-	for {
-		start := strings.Index(remaining, "{")
-		if start == -1 {
-			break
-		}
-
-		// Find the matching closing brace
-		end := -1
-		braceCount := 0
-		for i := start; i < len(remaining); i++ {
-			if remaining[i] == '{' {
-				braceCount++
-			} else if remaining[i] == '}' {
-				braceCount--
-				if braceCount == 0 {
-					end = i
-					break
-				}
-			}
-		}
-
-		if end == -1 {
-			break
-		}
-
-		jsonStr := remaining[start : end+1]
-		var command map[string]interface{}
-		if err := json.Unmarshal([]byte(jsonStr), &command); err == nil {
-			if _, ok := command["action"]; ok {
-				commands = append(commands, command)
-			}
+	commands, err := ai.Parse(response)
+	if err != nil {
+		return func() tea.Msg {
+			return AIResponseMsg{response: fmt.Sprintf("❌ %v", err), err: err}
 		}
-
-		remaining = remaining[end+1:]
 	}
-
-	// If no commands found, return nil
 	if len(commands) == 0 {
-		log.Debug("No valid JSON commands found in AI response")
+		logger.Get().Debug("No valid JSON commands found in AI response")
 		return nil
 	}
+	return m.executeCommands(commands)
+}
 
-	// Log the commands found
-	log.WithField("count", len(commands)).Info("Found JSON commands in AI response")
-
-	// If there are multiple commands, execute them all in sequence
-	if len(commands) > 1 {
-		return m.executeMultipleCommands(commands)
-	}
-
-	// Single command execution
-	command := commands[0]
-	action, ok := command["action"].(string)
-	if !ok {
-		log.Debug("No action field found in JSON command")
+// executeToolCalls runs the actions the model called as tools
+func (m *AIAssistantModel) executeToolCalls(calls []AIToolCall) tea.Cmd {
+	if len(calls) == 0 {
 		return nil
 	}
-
-	log.WithField("action", action).Info("Executing AI command")
-
-	switch action {
-	case "create_topic":
-		name, _ := command["name"].(string)
-		partitions, _ := command["partitions"].(float64)
-		replicationFactor, _ := command["replication_factor"].(float64)
-
-		if name != "" {
-			// Execute the topic creation
-			return func() tea.Msg {
-				err := m.client.CreateTopic(name, int32(partitions), int16(replicationFactor))
-				if err != nil {
-					return AIResponseMsg{
-						response: fmt.Sprintf("Failed to create topic: %v", err),
-						err:      err,
-					}
-				}
-
-				// Apply configurations if any
-				if configs, ok := command["configs"].(map[string]interface{}); ok {
-					for key, value := range configs {
-						if strValue, ok := value.(string); ok {
-							if err := m.client.UpdateTopicConfig(name, key, strValue); err != nil {
-								log.WithError(err).Warn("Failed to apply config")
-							}
-						}
-					}
-				}
-
-				return AIResponseMsg{
-					response: fmt.Sprintf("✅ Successfully created topic '%s' with %d partitions and replication factor %d",
-						name, int(partitions), int(replicationFactor)),
-					err: nil,
-				}
-			}
-		}
-
-	case "modify_partitions":
-		topic, _ := command["topic"].(string)
-		partitions, _ := command["partitions"].(float64)
-
-		if topic != "" && partitions > 0 {
-			return func() tea.Msg {
-				err := m.client.ModifyTopicPartitions(topic, int32(partitions))
-				if err != nil {
-					return AIResponseMsg{
-						response: fmt.Sprintf("❌ Failed to modify partitions: %v", err),
-						err:      err,
-					}
-				}
-
-				return AIResponseMsg{
-					response: fmt.Sprintf("✅ Successfully increased partitions for topic '%s' to %d",
-						topic, int(partitions)),
-					err: nil,
-				}
-			}
-		}
-
-	case "modify_config":
-		topic, _ := command["topic"].(string)
-		configs, _ := command["configs"].(map[string]interface{})
-
-		if topic != "" && configs != nil {
-			return func() tea.Msg {
-				var configChanges []string
-				var errors []string
-
-				for key, value := range configs {
-					if strValue, ok := value.(string); ok {
-						if err := m.client.UpdateTopicConfig(topic, key, strValue); err != nil {
-							errors = append(errors, fmt.Sprintf("%s: %v", key, err))
-							log.WithError(err).WithField("config", key).Warn("Failed to apply config")
-						} else {
-							configChanges = append(configChanges, fmt.Sprintf("%s=%s", key, strValue))
-						}
-					}
-				}
-
-				if len(errors) > 0 {
-					return AIResponseMsg{
-						response: fmt.Sprintf("⚠️ Partially updated topic '%s'.\nSuccessful: %s\nFailed: %s",
-							topic,
-							strings.Join(configChanges, ", "),
-							strings.Join(errors, ", ")),
-						err: nil,
-					}
-				}
-
-				return AIResponseMsg{
-					response: fmt.Sprintf("✅ Successfully updated configuration for topic '%s':\n%s",
-						topic, strings.Join(configChanges, "\n")),
-					err: nil,
-				}
-			}
-		}
-
-	case "modify_all_partitions":
-		partitions, _ := command["partitions"].(float64)
-
-		if partitions > 0 {
-			return func() tea.Msg {
-				// Get all topics
-				topics, err := m.client.GetTopicDetails()
-				if err != nil {
-					return AIResponseMsg{
-						response: fmt.Sprintf("❌ Failed to fetch topics: %v", err),
-						err:      err,
-					}
-				}
-
-				var successes []string
-				var failures []string
-
-				for _, topic := range topics {
-					// Only increase partitions (Kafka doesn't allow decreasing)
-					if topic.Partitions < int(partitions) {
-						err := m.client.ModifyTopicPartitions(topic.Name, int32(partitions))
-						if err != nil {
-							failures = append(failures, fmt.Sprintf("%s: %v", topic.Name, err))
-							log.WithField("topic", topic.Name).WithError(err).Warn("Failed to modify partitions")
-						} else {
-							successes = append(successes, fmt.Sprintf("%s (%d→%d)", topic.Name, topic.Partitions, int(partitions)))
-						}
-					} else {
-						// Skip topics that already have enough partitions
-						log.WithField("topic", topic.Name).Debug("Topic already has sufficient partitions")
-					}
-				}
-
-				// Format response
-				var response strings.Builder
-				if len(successes) > 0 {
-					response.WriteString(fmt.Sprintf("✅ Successfully updated %d topic(s):\n", len(successes)))
-					for _, s := range successes {
-						response.WriteString(fmt.Sprintf("  • %s\n", s))
-					}
-				}
-
-				if len(failures) > 0 {
-					if len(successes) > 0 {
-						response.WriteString("\n")
-					}
-					response.WriteString(fmt.Sprintf("❌ Failed to update %d topic(s):\n", len(failures)))
-					for _, f := range failures {
-						response.WriteString(fmt.Sprintf("  • %s\n", f))
-					}
-				}
-
-				if len(successes) == 0 && len(failures) == 0 {
-					response.WriteString(fmt.Sprintf("ℹ️ All topics already have %d or more partitions", int(partitions)))
-				}
-
-				return AIResponseMsg{
-					response: response.String(),
-					err:      nil,
-				}
-			}
-		}
-
-	case "modify_matching_configs":
-		pattern, _ := command["pattern"].(string)
-		configs, _ := command["configs"].(map[string]interface{})
-
-		if pattern != "" && configs != nil {
+	commands := make([]ai.Command, 0, len(calls))
+	for _, call := range calls {
+		cmd, err := ai.DecodeCall(call.Name, call.Arguments)
+		if err != nil {
 			return func() tea.Msg {
-				// Parse the pattern
-				var matchFunc func(string) bool
-				if strings.HasPrefix(pattern, "starts_with:") {
-					prefix := strings.TrimPrefix(pattern, "starts_with:")
-					matchFunc = func(name string) bool {
-						return strings.HasPrefix(name, prefix)
-					}
-				} else if strings.HasPrefix(pattern, "contains:") {
-					substr := strings.TrimPrefix(pattern, "contains:")
-					matchFunc = func(name string) bool {
-						return strings.Contains(name, substr)
-					}
-				} else if strings.HasPrefix(pattern, "ends_with:") {
-					suffix := strings.TrimPrefix(pattern, "ends_with:")
-					matchFunc = func(name string) bool {
-						return strings.HasSuffix(name, suffix)
-					}
-				} else {
-					// Default to exact match
-					matchFunc = func(name string) bool {
-						return name == pattern
-					}
-				}
-
-				// Get all topics
-				topics, err := m.client.GetTopicDetails()
-				if err != nil {
-					return AIResponseMsg{
-						response: fmt.Sprintf("❌ Failed to fetch topics: %v", err),
-						err:      err,
-					}
-				}
-
-				var topicResults []string
-				var topicErrors []string
-				var matchedCount int
-
-				for _, topic := range topics {
-					// Check if topic matches the pattern
-					if !matchFunc(topic.Name) {
-						continue
-					}
-					
-					matchedCount++
-					var configChanges []string
-					var configErrors []string
-
-					for key, value := range configs {
-						if strValue, ok := value.(string); ok {
-							if err := m.client.UpdateTopicConfig(topic.Name, key, strValue); err != nil {
-								configErrors = append(configErrors, fmt.Sprintf("%s: %v", key, err))
-								log.WithField("topic", topic.Name).WithField("config", key).WithError(err).Warn("Failed to apply config")
-							} else {
-								configChanges = append(configChanges, fmt.Sprintf("%s=%s", key, strValue))
-							}
-						}
-					}
-
-					if len(configChanges) > 0 {
-						topicResults = append(topicResults, fmt.Sprintf("%s: %s", topic.Name, strings.Join(configChanges, ", ")))
-					}
-
-					if len(configErrors) > 0 {
-						topicErrors = append(topicErrors, fmt.Sprintf("%s: %s", topic.Name, strings.Join(configErrors, ", ")))
-					}
-				}
-
-				// Format response
-				var response strings.Builder
-				if matchedCount == 0 {
-					response.WriteString(fmt.Sprintf("ℹ️ No topics found matching pattern '%s'\n", pattern))
-				} else {
-					if len(topicResults) > 0 {
-						response.WriteString(fmt.Sprintf("✅ Successfully updated configuration for %d topic(s) matching '%s':\n", len(topicResults), pattern))
-						for _, result := range topicResults {
-							response.WriteString(fmt.Sprintf("  • %s\n", result))
-						}
-					}
-
-					if len(topicErrors) > 0 {
-						if len(topicResults) > 0 {
-							response.WriteString("\n")
-						}
-						response.WriteString(fmt.Sprintf("❌ Failed to update configuration for %d topic(s):\n", len(topicErrors)))
-						for _, err := range topicErrors {
-							response.WriteString(fmt.Sprintf("  • %s\n", err))
-						}
-					}
-				}
-
-				return AIResponseMsg{
-					response: response.String(),
-					err:      nil,
-				}
+				return AIResponseMsg{response: fmt.Sprintf("❌ %v", err), err: err}
 			}
 		}
+		commands = append(commands, cmd)
+	}
+	return m.executeCommands(commands)
+}
 
-	case "modify_all_configs":
-		configs, _ := command["configs"].(map[string]interface{})
-
-		if configs != nil {
-			return func() tea.Msg {
-				// Get all topics
-				topics, err := m.client.GetTopicDetails()
-				if err != nil {
-					return AIResponseMsg{
-						response: fmt.Sprintf("❌ Failed to fetch topics: %v", err),
-						err:      err,
-					}
-				}
-
-				var topicResults []string
-				var topicErrors []string
-
-				for _, topic := range topics {
-					var configChanges []string
-					var configErrors []string
-
-					for key, value := range configs {
-						if strValue, ok := value.(string); ok {
-							if err := m.client.UpdateTopicConfig(topic.Name, key, strValue); err != nil {
-								configErrors = append(configErrors, fmt.Sprintf("%s: %v", key, err))
-								log.WithField("topic", topic.Name).WithField("config", key).WithError(err).Warn("Failed to apply config")
-							} else {
-								configChanges = append(configChanges, fmt.Sprintf("%s=%s", key, strValue))
-							}
-						}
-					}
-
-					if len(configChanges) > 0 {
-						topicResults = append(topicResults, fmt.Sprintf("%s: %s", topic.Name, strings.Join(configChanges, ", ")))
-					}
-
-					if len(configErrors) > 0 {
-						topicErrors = append(topicErrors, fmt.Sprintf("%s: %s", topic.Name, strings.Join(configErrors, ", ")))
-					}
-				}
-
-				// Format response
-				var response strings.Builder
-				if len(topicResults) > 0 {
-					response.WriteString(fmt.Sprintf("✅ Successfully updated configuration for %d topic(s):\n", len(topicResults)))
-					for _, result := range topicResults {
-						response.WriteString(fmt.Sprintf("  • %s\n", result))
-					}
-				}
-
-				if len(topicErrors) > 0 {
-					if len(topicResults) > 0 {
-						response.WriteString("\n")
-					}
-					response.WriteString(fmt.Sprintf("❌ Failed to update configuration for %d topic(s):\n", len(topicErrors)))
-					for _, err := range topicErrors {
-						response.WriteString(fmt.Sprintf("  • %s\n", err))
-					}
-				}
-
-				return AIResponseMsg{
-					response: response.String(),
-					err:      nil,
-				}
-			}
-		}
-
-	case "query_consumer_groups":
-		filter, _ := command["filter"].(map[string]interface{})
-
+// executeCommands checks a plan against the policy and runs it, sending ACLs
+// to the Create ACL form instead
+func (m *AIAssistantModel) executeCommands(commands []ai.Command) tea.Cmd {
+	if err := m.executor.Check(commands); err != nil {
 		return func() tea.Msg {
-			// Get all consumer groups
-			groups, err := m.client.GetConsumerGroups()
-			if err != nil {
-				return AIResponseMsg{
-					response: fmt.Sprintf("❌ Failed to fetch consumer groups: %v", err),
-					err:      err,
-				}
-			}
-
-			// Apply filters
-			var filteredGroups []kafka.ConsumerGroupInfo
-			for _, group := range groups {
-				include := true
-
-				// Check lag filter
-				if lagThreshold, ok := filter["lag_greater_than"].(float64); ok {
-					if group.ConsumerLag <= int64(lagThreshold) {
-						include = false
-					}
-				}
-
-				// Check group ID filter
-				if groupIDContains, ok := filter["group_id_contains"].(string); ok {
-					if !strings.Contains(group.GroupID, groupIDContains) {
-						include = false
-					}
-				}
-
-				// Check state filter
-				if state, ok := filter["state"].(string); ok {
-					if !strings.EqualFold(group.State, state) {
-						include = false
-					}
-				}
-
-				if include {
-					filteredGroups = append(filteredGroups, group)
-				}
-			}
-
-			// Format response
-			if len(filteredGroups) == 0 {
-				return AIResponseMsg{
-					response: "No consumer groups found matching the criteria.",
-					err:      nil,
-				}
-			}
-
-			var responseText strings.Builder
-			responseText.WriteString(fmt.Sprintf("Found %d consumer group(s):\n\n", len(filteredGroups)))
-
-			for _, group := range filteredGroups {
-				responseText.WriteString(fmt.Sprintf("📊 Group: %s\n", group.GroupID))
-				responseText.WriteString(fmt.Sprintf("   • State: %s\n", group.State))
-				responseText.WriteString(fmt.Sprintf("   • Members: %d\n", group.NumMembers))
-				responseText.WriteString(fmt.Sprintf("   • Topics: %d\n", group.NumTopics))
-				responseText.WriteString(fmt.Sprintf("   • Total Lag: %d\n", group.ConsumerLag))
-				if len(group.Topics) > 0 && len(group.Topics) <= 5 {
-					responseText.WriteString(fmt.Sprintf("   • Consuming: %s\n", strings.Join(group.Topics, ", ")))
-				} else if len(group.Topics) > 5 {
-					responseText.WriteString(fmt.Sprintf("   • Consuming: %s, ... (%d total)\n",
-						strings.Join(group.Topics[:5], ", "), len(group.Topics)))
-				}
-				responseText.WriteString("\n")
-			}
-
-			return AIResponseMsg{
-				response: responseText.String(),
-				err:      nil,
-			}
+			return AIResponseMsg{response: fmt.Sprintf("🚫 Refused: %v", err), err: err}
 		}
+	}
 
-	case "query_topics":
-		filter, _ := command["filter"].(map[string]interface{})
+	// ACLs go through the Create ACL form to be confirmed like manual ones
+	requests, rest := ai.SplitACLRequests(commands)
+	if len(rest) == 0 {
+		return func() tea.Msg { return aiACLProposalMsg{requests: requests} }
+	}
 
+	// Plans changing many topics at once wait for the user to confirm the
+	// exact changes
+	if ai.NeedsPreview(rest) {
+		executor := m.executor
 		return func() tea.Msg {
-			// Get all topics with their configurations
-			topics, err := m.client.GetTopicDetails()
-			if err != nil {
-				return AIResponseMsg{
-					response: fmt.Sprintf("❌ Failed to fetch topics: %v", err),
-					err:      err,
-				}
-			}
-
-			// We'll need to fetch configs for filtering
-			var filteredTopics []kafka.TopicInfo
-
-			for _, topic := range topics {
-				include := true
-
-				// Check name filter
-				if nameContains, ok := filter["name_contains"].(string); ok {
-					if !strings.Contains(topic.Name, nameContains) {
-						include = false
-					}
-				}
-
-				// Check partitions filter
-				if partitionsGT, ok := filter["partitions_greater_than"].(float64); ok {
-					if topic.Partitions <= int(partitionsGT) {
-						include = false
-					}
-				}
-
-				// Check replication factor filter
-				if rf, ok := filter["replication_factor"].(float64); ok {
-					if topic.ReplicationFactor != int(rf) {
-						include = false
-					}
-				}
-
-				// Check compression filter - need to fetch topic config
-				if compression, ok := filter["compression"].(string); ok {
-					config, err := m.client.GetTopicConfig(topic.Name)
-					if err == nil && config != nil {
-						compressionType := config.Configs["compression.type"]
-						// "none" in query means no compression or producer (default)
-						if compression == "none" {
-							if compressionType != "" && compressionType != "producer" && compressionType != "none" {
-								include = false
-							}
-						} else if compressionType != compression {
-							include = false
-						}
-					}
-				}
-
-				if include {
-					filteredTopics = append(filteredTopics, topic)
-				}
-			}
-
-			// Format response
-			if len(filteredTopics) == 0 {
-				return AIResponseMsg{
-					response: "No topics found matching the criteria.",
-					err:      nil,
-				}
-			}
-
-			var responseText strings.Builder
-			responseText.WriteString(fmt.Sprintf("Found %d topic(s) matching criteria:\n\n", len(filteredTopics)))
-
-			for _, topic := range filteredTopics {
-				responseText.WriteString(fmt.Sprintf("📋 Topic: %s\n", topic.Name))
-				responseText.WriteString(fmt.Sprintf("   • Partitions: %d\n", topic.Partitions))
-				responseText.WriteString(fmt.Sprintf("   • Replication Factor: %d\n", topic.ReplicationFactor))
-
-				// Get compression info if requested
-				if _, hasCompressionFilter := filter["compression"]; hasCompressionFilter {
-					config, err := m.client.GetTopicConfig(topic.Name)
-					if err == nil && config != nil {
-						compressionType := config.Configs["compression.type"]
-						if compressionType == "" || compressionType == "producer" {
-							responseText.WriteString("   • Compression: none (using producer default)\n")
-						} else {
-							responseText.WriteString(fmt.Sprintf("   • Compression: %s\n", compressionType))
-						}
-					}
-				}
-				responseText.WriteString("\n")
-			}
-
-			return AIResponseMsg{
-				response: responseText.String(),
-				err:      nil,
-			}
-		}
-
-	case "create_acl":
-		principal, _ := command["principal"].(string)
-		host, _ := command["host"].(string)
-		resourceType, _ := command["resource_type"].(string)
-		resourceName, _ := command["resource_name"].(string)
-		patternType, _ := command["pattern_type"].(string)
-		operation, _ := command["operation"].(string)
-		permissionType, _ := command["permission_type"].(string)
-
-		if principal != "" && resourceType != "" && resourceName != "" {
-			return func() tea.Msg {
-				acl := kafka.ACL{
-					Principal:      principal,
-					Host:           host,
-					ResourceType:   resourceType,
-					ResourceName:   resourceName,
-					PatternType:    patternType,
-					Operation:      operation,
-					PermissionType: permissionType,
-				}
-
-				err := m.client.CreateACL(acl)
-				if err != nil {
-					return AIResponseMsg{
-						response: fmt.Sprintf("❌ Failed to create ACL: %v", err),
-						err:      err,
-					}
-				}
-
-				return AIResponseMsg{
-					response: fmt.Sprintf("✅ Successfully created ACL:\n• Principal: %s\n• Resource: %s %s\n• Operation: %s %s\n• Host: %s",
-						principal, resourceType, resourceName, operation, permissionType, host),
-					err: nil,
-				}
-			}
-		}
-
-	case "create_acls":
-		aclsData, _ := command["acls"].([]interface{})
-		
-		if len(aclsData) > 0 {
-			return func() tea.Msg {
-				var created []string
-				var errors []string
-
-				for _, aclData := range aclsData {
-					aclMap, ok := aclData.(map[string]interface{})
-					if !ok {
-						continue
-					}
-
-					acl := kafka.ACL{
-						Principal:      aclMap["principal"].(string),
-						Host:           aclMap["host"].(string),
-						ResourceType:   aclMap["resource_type"].(string),
-						ResourceName:   aclMap["resource_name"].(string),
-						PatternType:    aclMap["pattern_type"].(string),
-						Operation:      aclMap["operation"].(string),
-						PermissionType: aclMap["permission_type"].(string),
-					}
-
-					err := m.client.CreateACL(acl)
-					if err != nil {
-						errors = append(errors, fmt.Sprintf("%s %s: %v", acl.Operation, acl.PermissionType, err))
-					} else {
-						created = append(created, fmt.Sprintf("%s %s", acl.Operation, acl.PermissionType))
-					}
-				}
-
-				var responseText strings.Builder
-				if len(created) > 0 {
-					responseText.WriteString(fmt.Sprintf("✅ Successfully created %d ACL(s):\n", len(created)))
-					for _, c := range created {
-						responseText.WriteString(fmt.Sprintf("  • %s\n", c))
-					}
-				}
-				if len(errors) > 0 {
-					responseText.WriteString(fmt.Sprintf("\n❌ Failed to create %d ACL(s):\n", len(errors)))
-					for _, e := range errors {
-						responseText.WriteString(fmt.Sprintf("  • %s\n", e))
-					}
-				}
-
-				return AIResponseMsg{
-					response: responseText.String(),
-					err:      nil,
-				}
-			}
+			preview, err := executor.Preview(rest)
+			return aiPreviewMsg{commands: rest, requests: requests, preview: preview, err: err}
 		}
+	}
+	return m.runCommands(rest, requests)
+}
 
-	case "delete_acl":
-		principal, _ := command["principal"].(string)
-		host, _ := command["host"].(string)
-		resourceType, _ := command["resource_type"].(string)
-		resourceName, _ := command["resource_name"].(string)
-		patternType, _ := command["pattern_type"].(string)
-		operation, _ := command["operation"].(string)
-		permissionType, _ := command["permission_type"].(string)
-
-		if principal != "" && resourceType != "" && resourceName != "" {
-			return func() tea.Msg {
-				acl := kafka.ACL{
-					Principal:      principal,
-					Host:           host,
-					ResourceType:   resourceType,
-					ResourceName:   resourceName,
-					PatternType:    patternType,
-					Operation:      operation,
-					PermissionType: permissionType,
-				}
-
-				err := m.client.DeleteACL(acl)
-				if err != nil {
-					return AIResponseMsg{
-						response: fmt.Sprintf("❌ Failed to delete ACL: %v", err),
-						err:      err,
-					}
-				}
-
-				return AIResponseMsg{
-					response: fmt.Sprintf("✅ Successfully deleted ACL:\n• Principal: %s\n• Resource: %s %s\n• Operation: %s %s",
-						principal, resourceType, resourceName, operation, permissionType),
-					err: nil,
-				}
-			}
-		}
-
-	case "query_acls":
-		filter, _ := command["filter"].(map[string]interface{})
-
-		return func() tea.Msg {
-			// Get all ACLs
-			acls, err := m.client.ListACLs()
-			if err != nil {
-				return AIResponseMsg{
-					response: fmt.Sprintf("❌ Failed to fetch ACLs: %v", err),
-					err:      err,
-				}
-			}
-
-			// Filter ACLs based on criteria
-			var filteredACLs []kafka.ACL
-			for _, acl := range acls {
-				include := true
-
-				// Check filters
-				if principal, ok := filter["principal"].(string); ok && principal != "" {
-					if !strings.Contains(strings.ToLower(acl.Principal), strings.ToLower(principal)) {
-						include = false
-					}
-				}
-
-				if resourceType, ok := filter["resource_type"].(string); ok && resourceType != "" {
-					if !strings.EqualFold(acl.ResourceType, resourceType) {
-						include = false
-					}
-				}
-
-				if resourceName, ok := filter["resource_name"].(string); ok && resourceName != "" {
-					if !strings.Contains(strings.ToLower(acl.ResourceName), strings.ToLower(resourceName)) {
-						include = false
-					}
-				}
-
-				if operation, ok := filter["operation"].(string); ok && operation != "" {
-					if !strings.EqualFold(acl.Operation, operation) {
-						include = false
-					}
-				}
-
-				if include {
-					filteredACLs = append(filteredACLs, acl)
-				}
-			}
+// runCommands runs a checked plan, proposing its ACL requests once it is done
+func (m *AIAssistantModel) runCommands(rest []ai.Command, requests []ai.ACLRequest) tea.Cmd {
+	// The plan runs in the background, reporting each step as it goes. The
+	// stream holds every message, so the plan finishes even if the
+	// assistant is left meanwhile.
+	stream := make(chan tea.Msg, 2*len(rest)+1)
+	m.executing = true
+	m.steps = nil
+	m.execStream = stream
+	m.pendingACLs = requests
+	executor := m.executor
+	journal := m.client.Journal()
+	go func() {
+		defer close(stream)
+		// The whole plan is undone as one step
+		if journal != nil {
+			journal.Begin("AI: " + commandNames(rest))
+		}
+		result, err := executor.ExecuteWithProgress(rest, func(step ai.Step) {
+			stream <- aiStepMsg{step: step, stream: stream}
+		})
+		if journal != nil {
+			journal.End()
+		}
+		stream <- AIResponseMsg{response: result, err: err}
+	}()
+	return tea.Batch(waitForAI(stream), m.spinner.Tick)
+}
 
-			// Format response
-			var responseText strings.Builder
-			if len(filteredACLs) == 0 {
-				responseText.WriteString("No ACLs found matching the criteria.")
-			} else {
-				responseText.WriteString(fmt.Sprintf("Found %d ACL(s):\n\n", len(filteredACLs)))
+// updatePlanConfirmation applies or drops the previewed plan. It reports
+// false for keys left to the conversation, such as scrolling.
+func (m AIAssistantModel) updatePlanConfirmation(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "y", "Y":
+		plan, requests := m.pendingPlan, m.pendingACLs
+		m.pendingPlan = nil
+		cmd := m.runCommands(plan, requests)
+		m.refreshConversation()
+		return m, cmd, true
+	case "n", "N", "esc":
+		m.pendingPlan = nil
+		m.pendingACLs = nil
+		m.history = append(m.history, aiTurn{role: aiRoleNote, content: "✋ Cancelled, nothing was changed"})
+		m.refreshConversation()
+		return m, nil, true
+	}
+	return m, nil, false
+}
 
-				// Group ACLs by resource for better readability
-				resourceMap := make(map[string][]kafka.ACL)
-				for _, acl := range filteredACLs {
-					key := fmt.Sprintf("%s:%s", acl.ResourceType, acl.ResourceName)
-					resourceMap[key] = append(resourceMap[key], acl)
-				}
+// aiPreviewMsg carries the preview of a plan changing many topics at once
+type aiPreviewMsg struct {
+	commands []ai.Command
+	requests []ai.ACLRequest
+	preview  string
+	err      error
+}
 
-				for resource, aclList := range resourceMap {
-					parts := strings.Split(resource, ":")
-					responseText.WriteString(fmt.Sprintf("📋 %s: %s\n", parts[0], parts[1]))
-					for _, acl := range aclList {
-						responseText.WriteString(fmt.Sprintf("  • %s → %s %s (from %s)\n", 
-							acl.Principal, acl.Operation, acl.PermissionType, acl.Host))
-					}
-					responseText.WriteString("\n")
-				}
-			}
+// aiStepMsg reports the progress of a running plan
+type aiStepMsg struct {
+	step   ai.Step
+	stream <-chan tea.Msg
+}
 
-			return AIResponseMsg{
-				response: responseText.String(),
-				err:      nil,
-			}
-		}
+// commandNames lists the actions of a plan
+func commandNames(commands []ai.Command) string {
+	names := make([]string, len(commands))
+	for i, cmd := range commands {
+		names[i] = cmd.Name
 	}
+	return strings.Join(names, ", ")
+}
 
-	return nil
+// aiACLProposalMsg asks the main model to open the Create ACL form for each
+// ACL request the assistant proposed
+type aiACLProposalMsg struct {
+	requests []ai.ACLRequest
 }
@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+)
+
+const (
+	// aiHistoryLimit is the number of past turns sent back to the model
+	aiHistoryLimit = 20
+	// aiContextMaxTopics caps the topics listed in the cluster summary
+	aiContextMaxTopics = 200
+	// aiContextMaxGroups caps the consumer groups listed in the cluster summary
+	aiContextMaxGroups = 100
+)
+
+// aiRole identifies who produced a turn of the conversation
+type aiRole string
+
+const (
+	aiRoleUser      aiRole = "user"
+	aiRoleAssistant aiRole = "assistant"
+	aiRoleResult    aiRole = "result" // Outcome of executing the assistant's actions
+	aiRoleNote      aiRole = "note"   // Errors and cancellations, shown but never sent
+)
+
+// aiTurn is one entry of the conversation
+type aiTurn struct {
+	role    aiRole
+	content string
+}
+
+// AIMessage is a chat message in the shape the provider APIs expect
+type AIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// conversationMessages turns the most recent history into alternating
+// user/assistant messages. Execution results are reported back as user
+// messages so the model knows what its previous actions did.
+func conversationMessages(history []aiTurn) []AIMessage {
+	if len(history) > aiHistoryLimit {
+		history = history[len(history)-aiHistoryLimit:]
+	}
+
+	var msgs []AIMessage
+	for _, t := range history {
+		var msg AIMessage
+		switch t.role {
+		case aiRoleUser:
+			msg = AIMessage{Role: "user", Content: t.content}
+		case aiRoleAssistant:
+			msg = AIMessage{Role: "assistant", Content: t.content}
+		case aiRoleResult:
+			msg = AIMessage{Role: "user", Content: "Result of the previous action:\n" + t.content}
+		default:
+			continue
+		}
+		if n := len(msgs); n > 0 && msgs[n-1].Role == msg.Role {
+			msgs[n-1].Content += "\n\n" + msg.Content
+			continue
+		}
+		msgs = append(msgs, msg)
+	}
+
+	// Providers expect the conversation to open with the user
+	for len(msgs) > 0 && msgs[0].Role != "user" {
+		msgs = msgs[1:]
+	}
+	return msgs
+}
+
+// transcriptPrompt flattens a conversation into a single prompt for
+// providers that take plain text
+func transcriptPrompt(system string, msgs []AIMessage) string {
+	var sb strings.Builder
+	sb.WriteString(system)
+	for _, msg := range msgs {
+		role := "User"
+		if msg.Role == "assistant" {
+			role = "Assistant"
+		}
+		sb.WriteString(fmt.Sprintf("\n\n%s: %s", role, msg.Content))
+	}
+	sb.WriteString("\n\nAssistant:")
+	return sb.String()
+}
+
+// clusterContext fetches topics and consumer groups and summarises them for
+// the system prompt. Failures only cost the model some context.
+func clusterContext(client *kafka.Client) string {
+	log := logger.Get()
+
+	topics, err := client.GetTopicDetails()
+	if err != nil {
+		log.WithError(err).Warn("Failed to fetch topics for AI context")
+	}
+	groups, err := client.GetConsumerGroups()
+	if err != nil {
+		log.WithError(err).Warn("Failed to fetch consumer groups for AI context")
+	}
+	return summarizeCluster(topics, groups)
+}
+
+// summarizeCluster renders a compact description of the cluster, one line
+// per topic and group, so the model can resolve the names users refer to
+func summarizeCluster(topics []kafka.TopicInfo, groups []kafka.ConsumerGroupInfo) string {
+	var user []kafka.TopicInfo
+	for _, t := range topics {
+		if !strings.HasPrefix(t.Name, "__") {
+			user = append(user, t)
+		}
+	}
+	sort.Slice(user, func(i, j int) bool { return user[i].Name < user[j].Name })
+
+	var sb strings.Builder
+	sb.WriteString("Current cluster state. Use it to resolve topic and consumer group names the user mentions.\n")
+	sb.WriteString(fmt.Sprintf("Topics (%d):\n", len(user)))
+	for i, t := range user {
+		if i == aiContextMaxTopics {
+			sb.WriteString(fmt.Sprintf("- ... and %d more\n", len(user)-i))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("- %s partitions=%d replication=%d\n", t.Name, t.Partitions, t.ReplicationFactor))
+	}
+
+	sorted := append([]kafka.ConsumerGroupInfo(nil), groups...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].GroupID < sorted[j].GroupID })
+	sb.WriteString(fmt.Sprintf("Consumer groups (%d):\n", len(sorted)))
+	for i, g := range sorted {
+		if i == aiContextMaxGroups {
+			sb.WriteString(fmt.Sprintf("- ... and %d more\n", len(sorted)-i))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("- %s state=%s lag=%d topics=%s\n", g.GroupID, g.State, g.ConsumerLag, strings.Join(g.Topics, ",")))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// renderConversation formats the history followed by the reply that is
+// still streaming in
+func renderConversation(history []aiTurn, pending string, width int) string {
+	youStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("86"))
+	aiStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	noteStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	var parts []string
+	for _, t := range history {
+		text := wrapText(t.content, width)
+		switch t.role {
+		case aiRoleUser:
+			parts = append(parts, youStyle.Render("🧑 You")+"\n"+text)
+		case aiRoleAssistant:
+			parts = append(parts, aiStyle.Render("🤖 Assistant")+"\n"+text)
+		case aiRoleResult:
+			parts = append(parts, text)
+		default:
+			parts = append(parts, noteStyle.Render(text))
+		}
+	}
+	if pending != "" {
+		parts = append(parts, aiStyle.Render("🤖 Assistant")+"\n"+wrapText(pending, width))
+	}
+	return strings.Join(parts, "\n\n")
+}
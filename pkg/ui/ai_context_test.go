@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+func TestConversationMessages(t *testing.T) {
+	tests := []struct {
+		name    string
+		history []aiTurn
+		want    []AIMessage
+	}{
+		{
+			name: "results are reported as user messages",
+			history: []aiTurn{
+				{role: aiRoleUser, content: "list topics"},
+				{role: aiRoleAssistant, content: `{"action":"query_topics"}`},
+				{role: aiRoleResult, content: "orders"},
+				{role: aiRoleUser, content: "add partitions to it"},
+			},
+			want: []AIMessage{
+				{Role: "user", Content: "list topics"},
+				{Role: "assistant", Content: `{"action":"query_topics"}`},
+				{Role: "user", Content: "Result of the previous action:\norders\n\nadd partitions to it"},
+			},
+		},
+		{
+			name: "notes are left out",
+			history: []aiTurn{
+				{role: aiRoleUser, content: "hi"},
+				{role: aiRoleNote, content: "Error: timeout"},
+				{role: aiRoleUser, content: "hi again"},
+			},
+			want: []AIMessage{
+				{Role: "user", Content: "hi\n\nhi again"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := conversationMessages(tt.history); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConversationMessagesStartsWithUser(t *testing.T) {
+	var history []aiTurn
+	for i := 0; i < aiHistoryLimit; i++ {
+		history = append(history, aiTurn{role: aiRoleUser, content: "q"}, aiTurn{role: aiRoleAssistant, content: "a"})
+	}
+	history = append(history, aiTurn{role: aiRoleUser, content: "last"})
+
+	got := conversationMessages(history)
+	if got[0].Role != "user" {
+		t.Errorf("first message role = %q, want user", got[0].Role)
+	}
+	if last := got[len(got)-1]; last.Content != "last" {
+		t.Errorf("last message = %q, want %q", last.Content, "last")
+	}
+}
+
+func TestSummarizeCluster(t *testing.T) {
+	topics := []kafka.TopicInfo{
+		{Name: "payments", Partitions: 6, ReplicationFactor: 3},
+		{Name: "__consumer_offsets", Partitions: 50, ReplicationFactor: 3},
+		{Name: "orders", Partitions: 12, ReplicationFactor: 3},
+	}
+	groups := []kafka.ConsumerGroupInfo{
+		{GroupID: "billing", State: "Stable", ConsumerLag: 42, Topics: []string{"orders", "payments"}},
+	}
+
+	got := summarizeCluster(topics, groups)
+	for _, want := range []string{
+		"Topics (2):\n- orders partitions=12 replication=3\n- payments partitions=6 replication=3\n",
+		"Consumer groups (1):\n- billing state=Stable lag=42 topics=orders,payments",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "__consumer_offsets") {
+		t.Errorf("summary lists internal topics:\n%s", got)
+	}
+}
@@ -0,0 +1,305 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// aiModelListTimeout bounds how long a provider may take to list its models
+const aiModelListTimeout = 15 * time.Second
+
+// aiModelLister is implemented by providers that can ask their API which
+// models are available, beyond the configured ones Models returns
+type aiModelLister interface {
+	ListModels(ctx context.Context) ([]string, error)
+}
+
+// aiModelsMsg carries the models a provider listed for the model picker
+type aiModelsMsg struct {
+	provider string
+	models   []string
+	err      error
+}
+
+// listAIModels asks the provider for its models in the background. The
+// configured ones come first, so they can be picked even when listing fails.
+func listAIModels(p AIProvider) tea.Cmd {
+	return func() tea.Msg {
+		models := slices.Clone(p.Models())
+		var err error
+		if lister, ok := p.(aiModelLister); ok {
+			ctx, cancel := context.WithTimeout(context.Background(), aiModelListTimeout)
+			defer cancel()
+			var listed []string
+			listed, err = lister.ListModels(ctx)
+			slices.Sort(listed)
+			for _, model := range listed {
+				if !slices.Contains(models, model) {
+					models = append(models, model)
+				}
+			}
+		}
+		return aiModelsMsg{provider: p.Name(), models: models, err: err}
+	}
+}
+
+// getAIJSON fetches url and decodes its JSON body into out
+func getAIJSON(ctx context.Context, url string, headers map[string]string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := (&http.Client{Transport: aiTransport}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(data))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("unexpected API response format: %w", err)
+	}
+	return nil
+}
+
+// openAIChatModel reports whether an OpenAI model id is a chat model, as
+// /models also lists embedding, speech and image models
+func openAIChatModel(id string) bool {
+	if !strings.HasPrefix(id, "gpt-") && !strings.HasPrefix(id, "chatgpt-") &&
+		!strings.HasPrefix(id, "o1") && !strings.HasPrefix(id, "o3") && !strings.HasPrefix(id, "o4") {
+		return false
+	}
+	for _, other := range []string{"audio", "realtime", "tts", "transcribe", "image", "search", "instruct"} {
+		if strings.Contains(id, other) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *openAIProvider) ListModels(ctx context.Context) ([]string, error) {
+	if p.key == "" {
+		return nil, fmt.Errorf("openAI API key not configured; set OPENAI_API_KEY environment variable")
+	}
+	var result struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := getAIJSON(ctx, "https://api.openai.com/v1/models", map[string]string{"Authorization": "Bearer " + p.key}, &result); err != nil {
+		return nil, err
+	}
+	var models []string
+	for _, m := range result.Data {
+		if openAIChatModel(m.ID) {
+			models = append(models, m.ID)
+		}
+	}
+	return models, nil
+}
+
+func (p *geminiProvider) ListModels(ctx context.Context) ([]string, error) {
+	if p.key == "" {
+		return nil, fmt.Errorf("gemini API key not configured; set GEMINI_API_KEY environment variable")
+	}
+	var result struct {
+		Models []struct {
+			Name    string   `json:"name"`
+			Methods []string `json:"supportedGenerationMethods"`
+		} `json:"models"`
+	}
+	endpoint := "https://generativelanguage.googleapis.com/v1beta/models?pageSize=1000&key=" + url.QueryEscape(p.key)
+	if err := getAIJSON(ctx, endpoint, nil, &result); err != nil {
+		return nil, err
+	}
+	var models []string
+	for _, m := range result.Models {
+		if slices.Contains(m.Methods, "generateContent") {
+			models = append(models, strings.TrimPrefix(m.Name, "models/"))
+		}
+	}
+	return models, nil
+}
+
+func (p *anthropicProvider) ListModels(ctx context.Context) ([]string, error) {
+	if p.key == "" {
+		return nil, fmt.Errorf("anthropic API key not configured; set ANTHROPIC_API_KEY environment variable")
+	}
+	var result struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	headers := map[string]string{"x-api-key": p.key, "anthropic-version": "2023-06-01"}
+	if err := getAIJSON(ctx, "https://api.anthropic.com/v1/models?limit=1000", headers, &result); err != nil {
+		return nil, err
+	}
+	models := make([]string, len(result.Data))
+	for i, m := range result.Data {
+		models[i] = m.ID
+	}
+	return models, nil
+}
+
+// ListModels returns the models pulled into the local Ollama
+func (p *ollamaProvider) ListModels(ctx context.Context) ([]string, error) {
+	var result struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := getAIJSON(ctx, strings.TrimRight(p.url, "/")+"/api/tags", nil, &result); err != nil {
+		return nil, err
+	}
+	models := make([]string, len(result.Models))
+	for i, m := range result.Models {
+		models[i] = m.Name
+	}
+	return models, nil
+}
+
+// aiModelPicker is the AI assistant's menu for choosing the selected
+// provider's model. Typing narrows the list.
+type aiModelPicker struct {
+	provider string
+	current  string
+	models   []string
+	filter   string
+	cursor   int
+	offset   int
+	height   int
+	loading  bool
+	err      error
+}
+
+func newAIModelPicker(provider, current string, height int) *aiModelPicker {
+	return &aiModelPicker{provider: provider, current: current, loading: true, height: max(height-16, 5)}
+}
+
+// visible returns the models matching the filter
+func (p *aiModelPicker) visible() []string {
+	if p.filter == "" {
+		return p.models
+	}
+	var models []string
+	for _, m := range p.models {
+		if strings.Contains(strings.ToLower(m), strings.ToLower(p.filter)) {
+			models = append(models, m)
+		}
+	}
+	return models
+}
+
+// setModels fills the list once the provider has answered, with the cursor
+// on the model in use
+func (p *aiModelPicker) setModels(msg aiModelsMsg) {
+	p.loading = false
+	p.models = msg.models
+	p.err = msg.err
+	p.cursor = max(slices.Index(p.models, p.current), 0)
+	p.scroll()
+}
+
+// update handles a key, returning the chosen model and true once one is
+// picked with Enter
+func (p *aiModelPicker) update(msg tea.KeyMsg) (string, bool) {
+	visible := p.visible()
+	switch msg.Type {
+	case tea.KeyUp:
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case tea.KeyDown:
+		if p.cursor < len(visible)-1 {
+			p.cursor++
+		}
+	case tea.KeyPgUp:
+		p.cursor = max(p.cursor-p.height, 0)
+	case tea.KeyPgDown:
+		p.cursor = max(min(p.cursor+p.height, len(visible)-1), 0)
+	case tea.KeyEnter:
+		if p.cursor < len(visible) {
+			return visible[p.cursor], true
+		}
+	case tea.KeyBackspace:
+		if p.filter != "" {
+			p.filter = p.filter[:len(p.filter)-1]
+			p.cursor = 0
+		}
+	case tea.KeyRunes:
+		p.filter += string(msg.Runes)
+		p.cursor = 0
+	}
+	p.scroll()
+	return "", false
+}
+
+func (p *aiModelPicker) scroll() {
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	} else if p.cursor >= p.offset+p.height {
+		p.offset = p.cursor - p.height + 1
+	}
+}
+
+func (p *aiModelPicker) View() string {
+	var sb strings.Builder
+
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("86"))
+	focusedStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+
+	sb.WriteString(labelStyle.Render(fmt.Sprintf("🧠 %s Models", p.provider)))
+	if p.filter != "" {
+		sb.WriteString(dimStyle.Render("  filter: " + p.filter))
+	}
+	sb.WriteString("\n\n")
+
+	if p.loading {
+		sb.WriteString(dimStyle.Render("Asking "+p.provider+" for its models...") + "\n\n")
+	}
+	if p.err != nil {
+		sb.WriteString(errorStyle.Render(fmt.Sprintf("Could not list models: %v", p.err)) + "\n\n")
+	}
+
+	visible := p.visible()
+	if !p.loading && len(visible) == 0 {
+		sb.WriteString(dimStyle.Render("No models match.") + "\n\n")
+	}
+	end := min(p.offset+p.height, len(visible))
+	for i := p.offset; i < end; i++ {
+		line := visible[i]
+		if line == p.current {
+			line += " (in use)"
+		}
+		if i == p.cursor {
+			sb.WriteString(focusedStyle.Render("> "+line) + "\n")
+		} else {
+			sb.WriteString("  " + line + "\n")
+		}
+	}
+	if len(visible) > p.height {
+		sb.WriteString(dimStyle.Render(fmt.Sprintf("  %d-%d of %d", p.offset+1, end, len(visible))) + "\n")
+	}
+	sb.WriteString("\n")
+
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Italic(true)
+	sb.WriteString(helpStyle.Render("↑/↓: Select • Type: Filter • Enter: Use model • Esc: Cancel"))
+	return sb.String()
+}
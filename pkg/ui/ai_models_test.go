@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestOpenAIChatModel(t *testing.T) {
+	for id, want := range map[string]bool{
+		"gpt-4o":                 true,
+		"gpt-4o-mini-2024-07-18": true,
+		"o3-mini":                true,
+		"gpt-4o-realtime":        false,
+		"text-embedding-3-small": false,
+		"whisper-1":              false,
+		"dall-e-3":               false,
+	} {
+		if got := openAIChatModel(id); got != want {
+			t.Errorf("openAIChatModel(%q) = %v, want %v", id, got, want)
+		}
+	}
+}
+
+func TestOllamaListModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tags" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"models":[{"name":"mistral:latest"},{"name":"llama3:8b"}]}`))
+	}))
+	defer server.Close()
+
+	p := &ollamaProvider{url: server.URL + "/", model: "llama2"}
+	msg := listAIModels(p)().(aiModelsMsg)
+	if msg.err != nil {
+		t.Fatal(msg.err)
+	}
+	// Configured model first, then the listed ones sorted
+	want := []string{"llama2", "llama3:8b", "mistral:latest"}
+	if !reflect.DeepEqual(msg.models, want) {
+		t.Errorf("models = %q, want %q", msg.models, want)
+	}
+
+	if _, err := (&ollamaProvider{url: "http://127.0.0.1:1"}).ListModels(context.Background()); err == nil {
+		t.Error("expected an error when Ollama is not running")
+	}
+}
+
+func TestAssistantModelPicker(t *testing.T) {
+	m := NewAIAssistantModel(nil, "ollama", "")
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	m = model.(AIAssistantModel)
+	if m.modelPicker == nil {
+		t.Fatal("Ctrl+O did not open the model picker")
+	}
+
+	model, _ = m.Update(aiModelsMsg{provider: "Ollama", models: []string{"llama2", "llama3:8b", "mistral:latest"}})
+	m = model.(AIAssistantModel)
+	for _, key := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("mis")},
+		{Type: tea.KeyEnter},
+	} {
+		model, _ = m.Update(key)
+		m = model.(AIAssistantModel)
+	}
+	if m.modelPicker != nil {
+		t.Error("picker still open after choosing a model")
+	}
+	if got := m.getCurrentModel(); got != "mistral:latest" {
+		t.Errorf("model = %s, want mistral:latest", got)
+	}
+}
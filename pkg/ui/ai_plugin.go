@@ -0,0 +1,335 @@
+package ui
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/digitalis-io/kconduit/pkg/logger"
+)
+
+// AIPluginConfig describes a provider run as a subprocess, such as a bridge
+// to an internal LLM gateway. kconduit talks JSON-RPC 2.0 to it over stdin
+// and stdout, one message per line.
+type AIPluginConfig struct {
+	Name    string            `mapstructure:"name" yaml:"name"`
+	Command string            `mapstructure:"command" yaml:"command"`
+	Args    []string          `mapstructure:"args" yaml:"args"`
+	Env     map[string]string `mapstructure:"env" yaml:"env"`
+	// Models offered, the default first. Empty asks the plugin.
+	Models []string `mapstructure:"models" yaml:"models"`
+}
+
+// AIPlugin is an AIProvider served by a subprocess. The process is started
+// on first use and kept running; it is restarted if it exits.
+//
+// Requests are "models", "query" and "stream", with params {model, system,
+// messages} for the last two. A stream sends "chunk" notifications with
+// params {id, text} before its result, and is abandoned early with a
+// "cancel" notification with params {id}. Query and stream results are
+// {text, usage: {prompt_tokens, completion_tokens}}; models returns a list
+// of names.
+type AIPlugin struct {
+	config AIPluginConfig
+
+	mu      sync.Mutex // Guards everything below
+	stdin   io.WriteCloser
+	cmd     *exec.Cmd
+	nextID  int64
+	pending map[int64]*pluginCall
+	models  []string // Reported by the plugin, nil until known
+	listing bool     // Models have been asked for
+}
+
+// pluginCall is a request waiting for its result
+type pluginCall struct {
+	onChunk func(string)
+	done    chan pluginResponse
+}
+
+// pluginMessage is a request or notification sent to the plugin
+type pluginMessage struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      *int64 `json:"id,omitempty"`
+	Method  string `json:"method,omitempty"`
+	Params  any    `json:"params,omitempty"`
+}
+
+// pluginResponse is a message received from the plugin
+type pluginResponse struct {
+	ID     *int64          `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *pluginError    `json:"error"`
+}
+
+type pluginError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *pluginError) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+type pluginChunk struct {
+	ID   int64  `json:"id"`
+	Text string `json:"text"`
+}
+
+type pluginQuery struct {
+	Model    string      `json:"model"`
+	System   string      `json:"system"`
+	Messages []AIMessage `json:"messages"`
+}
+
+type pluginReply struct {
+	Text  string `json:"text"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// NewAIPlugin returns a provider for the plugin without starting it
+func NewAIPlugin(config AIPluginConfig) (*AIPlugin, error) {
+	if config.Name == "" {
+		return nil, fmt.Errorf("AI plugin needs a name")
+	}
+	if config.Command == "" {
+		return nil, fmt.Errorf("AI plugin %s needs a command", config.Name)
+	}
+	return &AIPlugin{config: config}, nil
+}
+
+func (p *AIPlugin) Name() string { return p.config.Name }
+
+// Models returns the configured models, or else those the plugin reports.
+// They are asked for in the background on the first call, as the assistant
+// lists models while drawing; until they arrive the plugin picks its own.
+func (p *AIPlugin) Models() []string {
+	if len(p.config.Models) > 0 {
+		return p.config.Models
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.models == nil && !p.listing {
+		p.listing = true
+		go p.listModels()
+	}
+	return p.models
+}
+
+func (p *AIPlugin) listModels() {
+	ctx, cancel := context.WithTimeout(context.Background(), aiModelListTimeout)
+	defer cancel()
+	models, err := p.ListModels(ctx)
+	if err != nil {
+		logger.Get().WithError(err).Warnf("Failed to list models of AI plugin %s", p.config.Name)
+	}
+	if models == nil {
+		models = []string{}
+	}
+	p.mu.Lock()
+	p.models = models
+	p.mu.Unlock()
+}
+
+// ListModels asks the plugin for its models
+func (p *AIPlugin) ListModels(ctx context.Context) ([]string, error) {
+	raw, err := p.call(ctx, "models", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var models []string
+	if err := json.Unmarshal(raw, &models); err != nil {
+		return nil, fmt.Errorf("invalid models from AI plugin %s: %w", p.config.Name, err)
+	}
+	return models, nil
+}
+
+func (p *AIPlugin) Query(ctx context.Context, req AIRequest) (AIReply, error) {
+	return p.request(ctx, "query", req, nil)
+}
+
+func (p *AIPlugin) Stream(ctx context.Context, req AIRequest, onChunk func(string)) (AIReply, error) {
+	return p.request(ctx, "stream", req, onChunk)
+}
+
+func (p *AIPlugin) request(ctx context.Context, method string, req AIRequest, onChunk func(string)) (AIReply, error) {
+	raw, err := p.call(ctx, method, pluginQuery{Model: req.Model, System: req.System, Messages: req.Messages}, onChunk)
+	if err != nil {
+		return AIReply{}, err
+	}
+	var reply pluginReply
+	if err := json.Unmarshal(raw, &reply); err != nil {
+		return AIReply{}, fmt.Errorf("invalid reply from AI plugin %s: %w", p.config.Name, err)
+	}
+	return AIReply{Text: reply.Text, PromptTokens: reply.Usage.PromptTokens, CompletionTokens: reply.Usage.CompletionTokens}, nil
+}
+
+// call sends a request and waits for its result, passing chunk
+// notifications for it to onChunk
+func (p *AIPlugin) call(ctx context.Context, method string, params any, onChunk func(string)) (json.RawMessage, error) {
+	p.mu.Lock()
+	if err := p.start(); err != nil {
+		p.mu.Unlock()
+		return nil, err
+	}
+	p.nextID++
+	id := p.nextID
+	c := &pluginCall{onChunk: onChunk, done: make(chan pluginResponse, 1)}
+	p.pending[id] = c
+	err := p.send(pluginMessage{ID: &id, Method: method, Params: params})
+	if err != nil {
+		delete(p.pending, id)
+	}
+	p.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to AI plugin %s: %w", p.config.Name, err)
+	}
+
+	select {
+	case resp := <-c.done:
+		if resp.Error != nil {
+			return nil, fmt.Errorf("AI plugin %s: %w", p.config.Name, resp.Error)
+		}
+		return resp.Result, nil
+	case <-ctx.Done():
+		p.mu.Lock()
+		delete(p.pending, id)
+		_ = p.send(pluginMessage{Method: "cancel", Params: map[string]int64{"id": id}})
+		p.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// start runs the plugin unless it is already running. Callers hold p.mu.
+func (p *AIPlugin) start() error {
+	if p.cmd != nil {
+		return nil
+	}
+	cmd := exec.Command(p.config.Command, p.config.Args...)
+	cmd.Env = os.Environ()
+	for k, v := range p.config.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to start AI plugin %s: %w", p.config.Name, err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to start AI plugin %s: %w", p.config.Name, err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("failed to start AI plugin %s: %w", p.config.Name, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start AI plugin %s: %w", p.config.Name, err)
+	}
+	p.cmd = cmd
+	p.stdin = stdin
+	p.pending = map[int64]*pluginCall{}
+
+	// The terminal belongs to the UI, so the plugin's stderr goes to the log
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			logger.Get().Infof("AI plugin %s: %s", p.config.Name, scanner.Text())
+		}
+	}()
+	go p.read(cmd, stdout)
+	return nil
+}
+
+// read dispatches the plugin's messages until it exits, then fails the
+// requests still waiting so the next call starts it again
+func (p *AIPlugin) read(cmd *exec.Cmd, stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var msg pluginResponse
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			logger.Get().WithError(err).Warnf("Invalid message from AI plugin %s", p.config.Name)
+			continue
+		}
+		p.dispatch(msg)
+	}
+
+	err := cmd.Wait()
+	if err == nil {
+		err = errors.New("exited")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for id, c := range p.pending {
+		c.done <- pluginResponse{Error: &pluginError{Code: -32000, Message: fmt.Sprintf("plugin stopped: %v", err)}}
+		delete(p.pending, id)
+	}
+	if p.cmd == cmd {
+		p.cmd = nil
+		p.stdin = nil
+	}
+}
+
+func (p *AIPlugin) dispatch(msg pluginResponse) {
+	if msg.ID == nil {
+		if msg.Method != "chunk" {
+			return
+		}
+		var chunk pluginChunk
+		if err := json.Unmarshal(msg.Params, &chunk); err != nil {
+			return
+		}
+		p.mu.Lock()
+		c := p.pending[chunk.ID]
+		p.mu.Unlock()
+		if c != nil && c.onChunk != nil {
+			c.onChunk(chunk.Text)
+		}
+		return
+	}
+	p.mu.Lock()
+	c := p.pending[*msg.ID]
+	delete(p.pending, *msg.ID)
+	p.mu.Unlock()
+	if c != nil {
+		c.done <- msg
+	}
+}
+
+// send writes one message. Callers hold p.mu.
+func (p *AIPlugin) send(msg pluginMessage) error {
+	if p.stdin == nil {
+		return errors.New("plugin is not running")
+	}
+	msg.JSONRPC = "2.0"
+	line, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = p.stdin.Write(append(line, '\n'))
+	return err
+}
+
+// Close stops the plugin by closing its stdin, on which it is expected to
+// exit
+func (p *AIPlugin) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stdin == nil {
+		return nil
+	}
+	err := p.stdin.Close()
+	p.stdin = nil
+	return err
+}
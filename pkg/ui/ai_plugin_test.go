@@ -0,0 +1,172 @@
+package ui
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestMain lets the test binary act as an AI plugin when run by newTestPlugin
+func TestMain(m *testing.M) {
+	if os.Getenv("KCONDUIT_TEST_AI_PLUGIN") == "1" {
+		runTestPlugin()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runTestPlugin answers "models", echoes the last message back in two
+// chunks, fails requests for the model "broken" and never answers requests
+// for the model "slow"
+func runTestPlugin() {
+	scanner := bufio.NewScanner(os.Stdin)
+	out := json.NewEncoder(os.Stdout)
+	for scanner.Scan() {
+		var req struct {
+			ID     int64       `json:"id"`
+			Method string      `json:"method"`
+			Params pluginQuery `json:"params"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			continue
+		}
+		switch {
+		case req.Method == "cancel":
+		case req.Method == "models":
+			_ = out.Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": []string{"small", "large"}})
+		case req.Params.Model == "broken":
+			_ = out.Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "error": map[string]any{"code": -32603, "message": "gateway unavailable"}})
+		case req.Params.Model == "slow":
+		default:
+			text := req.Params.Messages[len(req.Params.Messages)-1].Content
+			if req.Method == "stream" {
+				for _, chunk := range []string{text[:len(text)/2], text[len(text)/2:]} {
+					_ = out.Encode(map[string]any{"jsonrpc": "2.0", "method": "chunk", "params": map[string]any{"id": req.ID, "text": chunk}})
+				}
+			}
+			_ = out.Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": map[string]any{
+				"text":  text,
+				"usage": map[string]int{"prompt_tokens": 10, "completion_tokens": 5},
+			}})
+		}
+	}
+}
+
+func newTestPlugin(t *testing.T) *AIPlugin {
+	t.Helper()
+	p, err := NewAIPlugin(AIPluginConfig{
+		Name:    "gateway",
+		Command: os.Args[0],
+		Args:    []string{"-test.run=^$"},
+		Env:     map[string]string{"KCONDUIT_TEST_AI_PLUGIN": "1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = p.Close() })
+	return p
+}
+
+func TestAIPluginStream(t *testing.T) {
+	p := newTestPlugin(t)
+	req := AIRequest{Model: "small", Messages: []AIMessage{{Role: "user", Content: "list topics"}}}
+
+	var chunks []string
+	reply, err := p.Stream(context.Background(), req, func(s string) { chunks = append(chunks, s) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := AIReply{Text: "list topics", PromptTokens: 10, CompletionTokens: 5}
+	if !reflect.DeepEqual(reply, want) {
+		t.Errorf("reply = %+v, want %+v", reply, want)
+	}
+	if !reflect.DeepEqual(chunks, []string{"list ", "topics"}) {
+		t.Errorf("chunks = %q", chunks)
+	}
+
+	reply, err = p.Query(context.Background(), req)
+	if err != nil || !reflect.DeepEqual(reply, want) {
+		t.Errorf("Query = %+v, %v, want %+v", reply, err, want)
+	}
+}
+
+func TestAIPluginErrors(t *testing.T) {
+	p := newTestPlugin(t)
+	msgs := []AIMessage{{Role: "user", Content: "hi"}}
+
+	_, err := p.Query(context.Background(), AIRequest{Model: "broken", Messages: msgs})
+	if err == nil || err.Error() != "AI plugin gateway: gateway unavailable (code -32603)" {
+		t.Errorf("error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := p.Stream(ctx, AIRequest{Model: "slow", Messages: msgs}, func(string) {}); err != context.DeadlineExceeded {
+		t.Errorf("cancelled stream error = %v", err)
+	}
+
+	// Still usable after both
+	if reply, err := p.Query(context.Background(), AIRequest{Messages: msgs}); err != nil || reply.Text != "hi" {
+		t.Errorf("Query after errors = %+v, %v", reply, err)
+	}
+}
+
+func TestAIPluginModels(t *testing.T) {
+	p := newTestPlugin(t)
+	deadline := time.Now().Add(5 * time.Second)
+	for p.Models() == nil && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := p.Models(); !reflect.DeepEqual(got, []string{"small", "large"}) {
+		t.Errorf("Models() = %q", got)
+	}
+
+	configured, _ := NewAIPlugin(AIPluginConfig{Name: "gateway", Command: "unused", Models: []string{"internal-1"}})
+	if got := configured.Models(); !reflect.DeepEqual(got, []string{"internal-1"}) {
+		t.Errorf("configured Models() = %q", got)
+	}
+}
+
+func TestAIPluginConfigRequired(t *testing.T) {
+	if _, err := NewAIPlugin(AIPluginConfig{Command: "x"}); err == nil {
+		t.Error("expected an error without a name")
+	}
+	if _, err := NewAIPlugin(AIPluginConfig{Name: "x"}); err == nil {
+		t.Error("expected an error without a command")
+	}
+}
+
+func TestAssistantSelectsRegisteredProvider(t *testing.T) {
+	p, err := NewAIPlugin(AIPluginConfig{Name: "Gateway", Command: "unused", Models: []string{"internal-1", "internal-2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	RegisterAIProvider(p)
+	t.Cleanup(func() {
+		aiProvidersMu.Lock()
+		aiProviders = nil
+		aiProvidersMu.Unlock()
+	})
+
+	m := NewAIAssistantModel(nil, "gateway", "")
+	if got := m.getProviderName(); got != "Gateway" {
+		t.Fatalf("provider = %s, want Gateway", got)
+	}
+	if got := m.getCurrentModel(); got != "internal-1" {
+		t.Errorf("model = %s, want internal-1", got)
+	}
+
+	m = NewAIAssistantModel(nil, "gateway", "internal-3")
+	if got := m.getCurrentModel(); got != "internal-3" {
+		t.Errorf("model from --ai-model = %s, want internal-3", got)
+	}
+	if !m.providerReady() {
+		t.Error("a plugin without setup should be ready")
+	}
+}
@@ -0,0 +1,575 @@
+package ui
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/digitalis-io/kconduit/pkg/ai"
+)
+
+// AIProvider is a backend the AI assistant sends conversations to. The
+// built-in ones are OpenAI, Gemini, Anthropic and Ollama; others, such as
+// an internal LLM gateway, are added with RegisterAIProvider.
+type AIProvider interface {
+	// Name identifies the provider in the assistant and for --ai-engine
+	Name() string
+	// Models lists the models the provider offers, the default one first
+	Models() []string
+	// Query sends the conversation and returns the whole reply
+	Query(ctx context.Context, req AIRequest) (AIReply, error)
+	// Stream sends the conversation, calling onChunk with each piece of the
+	// reply as it arrives, and returns the whole reply
+	Stream(ctx context.Context, req AIRequest, onChunk func(string)) (AIReply, error)
+}
+
+// aiProviderSetup is implemented by providers that need setting up, such as
+// an API key, before they can be queried
+type aiProviderSetup interface {
+	// Setup describes the provider's state and whether it can be queried
+	Setup() (status string, ready bool)
+}
+
+// AIRequest is a conversation sent to a provider
+type AIRequest struct {
+	Model    string // Empty for the provider's default
+	System   string
+	Messages []AIMessage
+	// Tools the model may call, for providers with native tool calling.
+	// Others are given the actions in the system prompt instead.
+	Tools []ai.Tool
+}
+
+// AIReply is a provider's answer and the tokens it took, zero when the
+// provider does not report them
+type AIReply struct {
+	Text             string
+	Calls            []AIToolCall // Tools the model called, in order
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// AIToolCall is a tool called by the model with its JSON arguments
+type AIToolCall struct {
+	Name      string
+	Arguments json.RawMessage
+}
+
+// aiToolCaller is implemented by providers that honour AIRequest.Tools
+type aiToolCaller interface {
+	toolCalling()
+}
+
+func (r AIReply) usage() aiUsage {
+	return aiUsage{PromptTokens: r.PromptTokens, CompletionTokens: r.CompletionTokens}
+}
+
+var (
+	aiProvidersMu sync.Mutex
+	aiProviders   []AIProvider
+)
+
+// RegisterAIProvider makes a provider available to the AI assistant after
+// the built-in ones. Registering a name twice replaces the first provider.
+func RegisterAIProvider(p AIProvider) {
+	aiProvidersMu.Lock()
+	defer aiProvidersMu.Unlock()
+	for i, existing := range aiProviders {
+		if strings.EqualFold(existing.Name(), p.Name()) {
+			aiProviders[i] = p
+			return
+		}
+	}
+	aiProviders = append(aiProviders, p)
+}
+
+// registeredAIProviders returns the providers added with RegisterAIProvider
+func registeredAIProviders() []AIProvider {
+	aiProvidersMu.Lock()
+	defer aiProvidersMu.Unlock()
+	return append([]AIProvider(nil), aiProviders...)
+}
+
+// Indexes of the built-in providers, in the order builtinAIProviders
+// returns them
+const (
+	providerOpenAI = iota
+	providerGemini
+	providerAnthropic
+	providerOllama
+)
+
+// builtinAIProviders creates the built-in providers from the configuration
+func builtinAIProviders(config AIConfig) []AIProvider {
+	return []AIProvider{
+		&openAIProvider{key: config.OpenAIKey, model: config.OpenAIModel},
+		&geminiProvider{key: config.GeminiKey, model: config.GeminiModel},
+		&anthropicProvider{key: config.AnthropicKey, model: config.AnthropicModel},
+		&ollamaProvider{url: config.OllamaURL, model: config.OllamaModel},
+	}
+}
+
+// keySetup reports whether an API key is set, naming its variable if not
+func keySetup(key, env string) (string, bool) {
+	if key != "" {
+		return "Configured", true
+	}
+	return fmt.Sprintf("API key not set (%s)", env), false
+}
+
+// defaultModel returns model, or fallback when it is empty
+func defaultModel(model, fallback string) string {
+	if model == "" {
+		return fallback
+	}
+	return model
+}
+
+type openAIProvider struct {
+	key   string
+	model string
+}
+
+func (p *openAIProvider) Name() string          { return "OpenAI" }
+func (p *openAIProvider) Models() []string      { return []string{p.model} }
+func (p *openAIProvider) Setup() (string, bool) { return keySetup(p.key, "OPENAI_API_KEY") }
+
+func (p *openAIProvider) Query(ctx context.Context, req AIRequest) (AIReply, error) {
+	return p.Stream(ctx, req, func(string) {})
+}
+
+func (p *openAIProvider) Stream(ctx context.Context, req AIRequest, onChunk func(string)) (AIReply, error) {
+	var usage aiUsage
+	text, calls, err := p.stream(ctx, defaultModel(req.Model, p.model), req.System, req.Messages, req.Tools, onChunk, &usage)
+	return AIReply{Text: text, Calls: calls, PromptTokens: usage.PromptTokens, CompletionTokens: usage.CompletionTokens}, err
+}
+
+func (p *openAIProvider) toolCalling() {}
+
+type geminiProvider struct {
+	key   string
+	model string
+}
+
+func (p *geminiProvider) Name() string          { return "Gemini" }
+func (p *geminiProvider) Models() []string      { return []string{p.model} }
+func (p *geminiProvider) Setup() (string, bool) { return keySetup(p.key, "GEMINI_API_KEY") }
+
+func (p *geminiProvider) Query(ctx context.Context, req AIRequest) (AIReply, error) {
+	var usage aiUsage
+	text, err := p.query(ctx, defaultModel(req.Model, p.model), req.System, req.Messages, &usage)
+	return AIReply{Text: text, PromptTokens: usage.PromptTokens, CompletionTokens: usage.CompletionTokens}, err
+}
+
+// Stream delivers the whole reply as one chunk, as it is requested without
+// streaming
+func (p *geminiProvider) Stream(ctx context.Context, req AIRequest, onChunk func(string)) (AIReply, error) {
+	reply, err := p.Query(ctx, req)
+	if err == nil {
+		onChunk(reply.Text)
+	}
+	return reply, err
+}
+
+type anthropicProvider struct {
+	key   string
+	model string
+}
+
+func (p *anthropicProvider) Name() string          { return "Anthropic" }
+func (p *anthropicProvider) Models() []string      { return []string{p.model} }
+func (p *anthropicProvider) Setup() (string, bool) { return keySetup(p.key, "ANTHROPIC_API_KEY") }
+
+func (p *anthropicProvider) Query(ctx context.Context, req AIRequest) (AIReply, error) {
+	return p.Stream(ctx, req, func(string) {})
+}
+
+func (p *anthropicProvider) Stream(ctx context.Context, req AIRequest, onChunk func(string)) (AIReply, error) {
+	var usage aiUsage
+	text, calls, err := p.stream(ctx, defaultModel(req.Model, p.model), req.System, req.Messages, req.Tools, onChunk, &usage)
+	return AIReply{Text: text, Calls: calls, PromptTokens: usage.PromptTokens, CompletionTokens: usage.CompletionTokens}, err
+}
+
+func (p *anthropicProvider) toolCalling() {}
+
+type ollamaProvider struct {
+	url   string
+	model string
+}
+
+func (p *ollamaProvider) Name() string     { return "Ollama" }
+func (p *ollamaProvider) Models() []string { return []string{p.model} }
+
+// Setup needs nothing, Ollama runs locally without an API key
+func (p *ollamaProvider) Setup() (string, bool) { return "Local (no API key needed)", true }
+
+func (p *ollamaProvider) Query(ctx context.Context, req AIRequest) (AIReply, error) {
+	return p.Stream(ctx, req, func(string) {})
+}
+
+func (p *ollamaProvider) Stream(ctx context.Context, req AIRequest, onChunk func(string)) (AIReply, error) {
+	var usage aiUsage
+	text, err := p.stream(ctx, defaultModel(req.Model, p.model), req.System, req.Messages, onChunk, &usage)
+	return AIReply{Text: text, PromptTokens: usage.PromptTokens, CompletionTokens: usage.CompletionTokens}, err
+}
+
+func (p *openAIProvider) stream(ctx context.Context, model, system string, msgs []AIMessage, tools []ai.Tool, onChunk func(string), usage *aiUsage) (string, []AIToolCall, error) {
+	if p.key == "" {
+		return "", nil, fmt.Errorf("openAI API key not configured; set OPENAI_API_KEY environment variable")
+	}
+
+	requestBody := map[string]interface{}{
+		"model":       model,
+		"messages":    append([]AIMessage{{Role: "system", Content: system}}, msgs...),
+		"temperature": 0.3,
+		"stream":      true,
+		// Usage arrives in a final chunk with no choices
+		"stream_options": map[string]bool{"include_usage": true},
+	}
+	if len(tools) > 0 {
+		var functions []map[string]any
+		for _, t := range tools {
+			functions = append(functions, map[string]any{
+				"type":     "function",
+				"function": map[string]any{"name": t.Name, "description": t.Description, "parameters": t.Parameters},
+			})
+		}
+		requestBody["tools"] = functions
+	}
+
+	headers := map[string]string{"Authorization": "Bearer " + p.key}
+	resp, err := postAIRequest(ctx, "https://api.openai.com/v1/chat/completions", headers, requestBody)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+
+	var full strings.Builder
+	var calls toolCallBuilder
+	err = readServerSentEvents(resp.Body, func(data string) error {
+		if data == "[DONE]" {
+			return nil
+		}
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Content   string `json:"content"`
+					ToolCalls []struct {
+						Index    int `json:"index"`
+						Function struct {
+							Name      string `json:"name"`
+							Arguments string `json:"arguments"`
+						} `json:"function"`
+					} `json:"tool_calls"`
+				} `json:"delta"`
+			} `json:"choices"`
+			Usage *struct {
+				PromptTokens     int `json:"prompt_tokens"`
+				CompletionTokens int `json:"completion_tokens"`
+			} `json:"usage"`
+		}
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("unexpected API response format: %w", err)
+		}
+		if chunk.Usage != nil {
+			usage.PromptTokens = chunk.Usage.PromptTokens
+			usage.CompletionTokens = chunk.Usage.CompletionTokens
+		}
+		if len(chunk.Choices) == 0 {
+			return nil
+		}
+		delta := chunk.Choices[0].Delta
+		if delta.Content != "" {
+			full.WriteString(delta.Content)
+			onChunk(delta.Content)
+		}
+		// Arguments arrive in pieces, the name with the first
+		for _, tc := range delta.ToolCalls {
+			calls.add(tc.Index, tc.Function.Name, tc.Function.Arguments)
+		}
+		return nil
+	})
+	if err != nil {
+		return full.String(), nil, err
+	}
+	return full.String(), calls.calls(), nil
+}
+
+// toolCallBuilder assembles tool calls streamed in pieces, keyed by their
+// position in the reply
+type toolCallBuilder struct {
+	order []int
+	names map[int]string
+	args  map[int]*strings.Builder
+}
+
+func (b *toolCallBuilder) add(index int, name, args string) {
+	if b.names == nil {
+		b.names = map[int]string{}
+		b.args = map[int]*strings.Builder{}
+	}
+	if _, ok := b.args[index]; !ok {
+		b.order = append(b.order, index)
+		b.args[index] = &strings.Builder{}
+	}
+	if name != "" {
+		b.names[index] = name
+	}
+	b.args[index].WriteString(args)
+}
+
+// calls returns the assembled calls in order. Empty arguments become {}.
+func (b *toolCallBuilder) calls() []AIToolCall {
+	var calls []AIToolCall
+	for _, i := range b.order {
+		args := strings.TrimSpace(b.args[i].String())
+		if args == "" {
+			args = "{}"
+		}
+		calls = append(calls, AIToolCall{Name: b.names[i], Arguments: json.RawMessage(args)})
+	}
+	return calls
+}
+
+func (p *geminiProvider) query(ctx context.Context, model, system string, msgs []AIMessage, usage *aiUsage) (string, error) {
+	if p.key == "" {
+		return "", fmt.Errorf("gemini API key not configured; set GEMINI_API_KEY environment variable")
+	}
+
+	// Gemini calls the assistant "model" and has no system role here, so the
+	// instructions lead the first user message
+	var contents []map[string]interface{}
+	for i, msg := range msgs {
+		role, text := "user", msg.Content
+		if msg.Role == "assistant" {
+			role = "model"
+		}
+		if i == 0 {
+			text = system + "\n\nUser: " + text
+		}
+		contents = append(contents, map[string]interface{}{
+			"role":  role,
+			"parts": []map[string]string{{"text": text}},
+		})
+	}
+
+	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent?key=%s",
+		model, p.key)
+
+	requestBody := map[string]interface{}{
+		"contents": contents,
+		"generationConfig": map[string]interface{}{
+			"temperature":     0.3,
+			"maxOutputTokens": 2048,
+		},
+	}
+
+	jsonBody, err := json.Marshal(requestBody)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second, Transport: aiTransport}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			// Body close errors are typically safe to ignore in HTTP clients
+			_ = err
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("gemini API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", err
+	}
+
+	if meta, ok := result["usageMetadata"].(map[string]interface{}); ok {
+		prompt, _ := meta["promptTokenCount"].(float64)
+		candidates, _ := meta["candidatesTokenCount"].(float64)
+		usage.PromptTokens = int(prompt)
+		usage.CompletionTokens = int(candidates)
+	}
+
+	// Parse Gemini response
+	candidates, ok := result["candidates"].([]interface{})
+	if !ok || len(candidates) == 0 {
+		return "", fmt.Errorf("unexpected Gemini API response format")
+	}
+
+	firstCandidate := candidates[0].(map[string]interface{})
+	content, ok := firstCandidate["content"].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("unexpected Gemini response structure")
+	}
+
+	parts, ok := content["parts"].([]interface{})
+	if !ok || len(parts) == 0 {
+		return "", fmt.Errorf("no content parts in Gemini response")
+	}
+
+	firstPart := parts[0].(map[string]interface{})
+	text, ok := firstPart["text"].(string)
+	if !ok {
+		return "", fmt.Errorf("no text in Gemini response part")
+	}
+
+	return text, nil
+}
+
+func (p *anthropicProvider) stream(ctx context.Context, model, system string, msgs []AIMessage, tools []ai.Tool, onChunk func(string), usage *aiUsage) (string, []AIToolCall, error) {
+	if p.key == "" {
+		return "", nil, fmt.Errorf("anthropic API key not configured; set ANTHROPIC_API_KEY environment variable")
+	}
+
+	requestBody := map[string]interface{}{
+		"model":       model,
+		"max_tokens":  2048,
+		"messages":    msgs,
+		"system":      system,
+		"temperature": 0.3,
+		"stream":      true,
+	}
+	if len(tools) > 0 {
+		var defs []map[string]any
+		for _, t := range tools {
+			defs = append(defs, map[string]any{"name": t.Name, "description": t.Description, "input_schema": t.Parameters})
+		}
+		requestBody["tools"] = defs
+	}
+
+	headers := map[string]string{
+		"x-api-key":         p.key,
+		"anthropic-version": "2023-06-01",
+	}
+	resp, err := postAIRequest(ctx, "https://api.anthropic.com/v1/messages", headers, requestBody)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+
+	var full strings.Builder
+	var calls toolCallBuilder
+	err = readServerSentEvents(resp.Body, func(data string) error {
+		var event struct {
+			Type         string `json:"type"`
+			Index        int    `json:"index"`
+			ContentBlock struct {
+				Type string `json:"type"`
+				Name string `json:"name"`
+			} `json:"content_block"`
+			Delta struct {
+				Type        string `json:"type"`
+				Text        string `json:"text"`
+				PartialJSON string `json:"partial_json"`
+			} `json:"delta"`
+			Message struct {
+				Usage struct {
+					InputTokens int `json:"input_tokens"`
+				} `json:"usage"`
+			} `json:"message"`
+			Usage struct {
+				OutputTokens int `json:"output_tokens"`
+			} `json:"usage"`
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return fmt.Errorf("unexpected Anthropic API response format: %w", err)
+		}
+		switch event.Type {
+		case "message_start":
+			usage.PromptTokens = event.Message.Usage.InputTokens
+		case "message_delta":
+			// Carries the cumulative output count
+			usage.CompletionTokens = event.Usage.OutputTokens
+		case "content_block_start":
+			if event.ContentBlock.Type == "tool_use" {
+				calls.add(event.Index, event.ContentBlock.Name, "")
+			}
+		case "content_block_delta":
+			if event.Delta.Type == "input_json_delta" {
+				calls.add(event.Index, "", event.Delta.PartialJSON)
+				return nil
+			}
+			full.WriteString(event.Delta.Text)
+			onChunk(event.Delta.Text)
+		case "error":
+			return fmt.Errorf("anthropic API error: %s", event.Error.Message)
+		}
+		return nil
+	})
+	if err != nil {
+		return full.String(), nil, err
+	}
+	return full.String(), calls.calls(), nil
+}
+
+func (p *ollamaProvider) stream(ctx context.Context, model, system string, msgs []AIMessage, onChunk func(string), usage *aiUsage) (string, error) {
+	requestBody := map[string]interface{}{
+		"model":  model,
+		"prompt": transcriptPrompt(system, msgs),
+		"stream": true,
+	}
+
+	resp, err := postAIRequest(ctx, p.url+"/api/generate", nil, requestBody)
+	if err != nil {
+		if ctx.Err() == nil {
+			err = fmt.Errorf("failed to connect to Ollama. Make sure it's running: %w", err)
+		}
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	// Ollama streams one JSON object per line
+	var full strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var chunk struct {
+			Response        string `json:"response"`
+			Error           string `json:"error"`
+			PromptEvalCount int    `json:"prompt_eval_count"` // Set on the final line
+			EvalCount       int    `json:"eval_count"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &chunk); err != nil {
+			return full.String(), fmt.Errorf("unexpected Ollama response format: %w", err)
+		}
+		if chunk.Error != "" {
+			return full.String(), fmt.Errorf("ollama error: %s", chunk.Error)
+		}
+		full.WriteString(chunk.Response)
+		onChunk(chunk.Response)
+		if chunk.EvalCount > 0 {
+			usage.PromptTokens = chunk.PromptEvalCount
+			usage.CompletionTokens = chunk.EvalCount
+		}
+	}
+	return full.String(), scanner.Err()
+}
@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// aiChunkMsg is a piece of a streamed AI response
+type aiChunkMsg struct {
+	text   string
+	stream <-chan tea.Msg
+}
+
+// aiStreamDoneMsg ends a streamed response with the full text
+type aiStreamDoneMsg struct {
+	response string
+	calls    []AIToolCall // Actions requested through tool calling
+	tools    bool         // Actions were offered as tools, not in the prompt
+	usage    aiUsage
+	err      error
+	stream   <-chan tea.Msg
+	readOnly bool // Display the response without executing its actions
+	local    bool // Understood by ai.ParseLocal, no provider was asked
+}
+
+// aiTransport carries requests to the AI providers. Nil means
+// http.DefaultTransport, which honors HTTP_PROXY and HTTPS_PROXY.
+var aiTransport http.RoundTripper
+
+// SetAIProxy sends requests to the AI providers through the HTTP, HTTPS or
+// SOCKS5 proxy at proxyURL
+func SetAIProxy(proxyURL string) error {
+	u, err := url.Parse(proxyURL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid AI proxy URL %q: expected e.g. http://host:3128 or socks5://host:1080", proxyURL)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(u)
+	aiTransport = transport
+	return nil
+}
+
+// waitForAI delivers the next message of a streamed response. A closed
+// stream yields nil, which Bubble Tea ignores.
+func waitForAI(stream <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-stream
+	}
+}
+
+// postAIRequest sends a JSON request and fails on non-200 responses. There is
+// no client timeout since streams stay open while tokens arrive; ctx cancels.
+func postAIRequest(ctx context.Context, url string, headers map[string]string, body interface{}) (*http.Response, error) {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := (&http.Client{Transport: aiTransport}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(data))
+	}
+	return resp, nil
+}
+
+// readServerSentEvents calls onData with the payload of every "data:" line
+func readServerSentEvents(r io.Reader, onData func(data string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		if err := onData(strings.TrimSpace(strings.TrimPrefix(line, "data:"))); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
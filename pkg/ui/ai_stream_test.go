@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalis-io/kconduit/pkg/ai"
+)
+
+func TestReadServerSentEvents(t *testing.T) {
+	input := "event: message_start\ndata: {\"a\":1}\n\n: keep-alive\ndata:{\"b\":2}\n\ndata: [DONE]\n"
+
+	var got []string
+	err := readServerSentEvents(strings.NewReader(input), func(data string) error {
+		got = append(got, data)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`{"a":1}`, `{"b":2}`, "[DONE]"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestToolCallBuilder(t *testing.T) {
+	var b toolCallBuilder
+	b.add(1, "create_topic", `{"name":`)
+	b.add(3, "query_topics", "")
+	b.add(1, "", ` "orders"}`)
+
+	want := []AIToolCall{
+		{Name: "create_topic", Arguments: []byte(`{"name": "orders"}`)},
+		{Name: "query_topics", Arguments: []byte(`{}`)},
+	}
+	if got := b.calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("calls = %+v, want %+v", got, want)
+	}
+}
+
+func TestAssistantShowsPlanProgress(t *testing.T) {
+	m := NewAIAssistantModel(nil, "ollama", "")
+	stream := make(chan tea.Msg)
+	m.executing = true
+	m.execStream = stream
+	m.pendingACLs = []ai.ACLRequest{{Principal: "User:alice"}}
+
+	update := func(msg tea.Msg) tea.Cmd {
+		model, cmd := m.Update(msg)
+		m = model.(AIAssistantModel)
+		return cmd
+	}
+
+	update(aiStepMsg{step: ai.Step{Index: 0, Total: 2, Name: "create_topic"}, stream: stream})
+	if got := m.progressView(80); !strings.Contains(got, "Step 1 of 2: running create_topic") {
+		t.Errorf("progress = %q", got)
+	}
+	update(aiStepMsg{step: ai.Step{Index: 0, Total: 2, Name: "create_topic", Done: true, Result: "Step 1: ✅ Created orders"}, stream: stream})
+	update(aiStepMsg{step: ai.Step{Index: 1, Total: 2, Name: "modify_config"}, stream: stream})
+	got := m.progressView(80)
+	if !strings.Contains(got, "Step 1: ✅ Created orders") || !strings.Contains(got, "Step 2 of 2: running modify_config") {
+		t.Errorf("progress = %q", got)
+	}
+
+	// Esc does not abandon a running plan
+	update(tea.KeyMsg{Type: tea.KeyEsc})
+	if !m.executing || !m.showResponse {
+		t.Fatal("Esc left the running plan")
+	}
+
+	cmd := update(AIResponseMsg{response: "Step 1: ✅ Created orders\nStep 2: ✅ Updated orders"})
+	if m.executing || m.steps != nil {
+		t.Error("plan still shown as running after its result")
+	}
+	if cmd == nil {
+		t.Fatal("no command to propose the ACLs")
+	}
+	if _, ok := cmd().(aiACLProposalMsg); !ok {
+		t.Error("ACLs were not proposed once the plan finished")
+	}
+}
+
+func TestAssistantConfirmsPreviewedPlan(t *testing.T) {
+	m := NewAIAssistantModel(nil, "ollama", "")
+	update := func(msg tea.Msg) tea.Cmd {
+		model, cmd := m.Update(msg)
+		m = model.(AIAssistantModel)
+		return cmd
+	}
+
+	plan := []ai.Command{{Name: "modify_all_partitions", Action: &ai.ModifyAllPartitions{Partitions: 6}}}
+	update(aiPreviewMsg{commands: plan, requests: []ai.ACLRequest{{Principal: "User:alice"}}, preview: "🔍 modify_all_partitions would change 1 topic(s)"})
+	if m.pendingPlan == nil || !strings.Contains(m.View(), "Press y to apply") {
+		t.Fatal("previewed plan is not waiting for confirmation")
+	}
+
+	// Scrolling leaves the plan waiting
+	update(tea.KeyMsg{Type: tea.KeyDown})
+	if m.pendingPlan == nil {
+		t.Fatal("scrolling dropped the plan")
+	}
+
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if m.pendingPlan != nil || m.pendingACLs != nil || m.executing {
+		t.Error("plan still pending after n")
+	}
+	if last := m.history[len(m.history)-1]; !strings.Contains(last.content, "nothing was changed") {
+		t.Errorf("last turn = %q", last.content)
+	}
+
+	update(aiPreviewMsg{preview: "🚫 Refused: too many topics", err: fmt.Errorf("too many topics")})
+	if m.pendingPlan != nil {
+		t.Error("a refused plan is waiting for confirmation")
+	}
+
+	// Leaving the assistant drops the plan, so coming back cannot apply it
+	update(aiPreviewMsg{commands: plan, requests: []ai.ACLRequest{{Principal: "User:alice"}}, preview: "🔍 modify_all_partitions would change 1 topic(s)"})
+	update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if m.pendingPlan != nil || m.pendingACLs != nil {
+		t.Error("plan still pending after ctrl+c")
+	}
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// aiUsage counts the tokens of one or more queries and their estimated cost
+type aiUsage struct {
+	PromptTokens     int
+	CompletionTokens int
+	Cost             float64 // USD, for the priced queries only
+	Unpriced         int     // Queries whose model has no known price
+}
+
+func (u aiUsage) add(o aiUsage) aiUsage {
+	return aiUsage{
+		PromptTokens:     u.PromptTokens + o.PromptTokens,
+		CompletionTokens: u.CompletionTokens + o.CompletionTokens,
+		Cost:             u.Cost + o.Cost,
+		Unpriced:         u.Unpriced + o.Unpriced,
+	}
+}
+
+func (u aiUsage) empty() bool {
+	return u.PromptTokens == 0 && u.CompletionTokens == 0
+}
+
+// String renders the counts, e.g. "1,204 in / 87 out (~$0.0004)"
+func (u aiUsage) String() string {
+	s := fmt.Sprintf("%s in / %s out", groupThousands(u.PromptTokens), groupThousands(u.CompletionTokens))
+	switch {
+	case u.Unpriced > 0 && u.Cost > 0:
+		s += fmt.Sprintf(" (~$%.4f + unpriced)", u.Cost)
+	case u.Unpriced > 0:
+		s += " (cost unknown)"
+	default:
+		s += fmt.Sprintf(" (~$%.4f)", u.Cost)
+	}
+	return s
+}
+
+// modelPrice is the list price of a model in USD per million tokens
+type modelPrice struct {
+	prefix string
+	input  float64
+	output float64
+}
+
+// modelPrices are matched by longest prefix, so dated model versions share
+// their family's price. They are estimates; providers change prices.
+var modelPrices = []modelPrice{
+	{"gpt-3.5-turbo", 0.50, 1.50},
+	{"gpt-4", 30, 60},
+	{"gpt-4-turbo", 10, 30},
+	{"gpt-4o", 2.50, 10},
+	{"gpt-4o-mini", 0.15, 0.60},
+	{"gpt-4.1", 2, 8},
+	{"gpt-4.1-mini", 0.40, 1.60},
+	{"gpt-4.1-nano", 0.10, 0.40},
+	{"o3-mini", 1.10, 4.40},
+	{"gemini-1.5-flash", 0.075, 0.30},
+	{"gemini-1.5-pro", 1.25, 5},
+	{"gemini-2.0-flash", 0.10, 0.40},
+	{"gemini-2.5-flash", 0.30, 2.50},
+	{"gemini-2.5-pro", 1.25, 10},
+	{"claude-3-haiku", 0.25, 1.25},
+	{"claude-3-5-haiku", 0.80, 4},
+	{"claude-3-5-sonnet", 3, 15},
+	{"claude-3-7-sonnet", 3, 15},
+	{"claude-sonnet-4", 3, 15},
+	{"claude-3-opus", 15, 75},
+	{"claude-opus-4", 15, 75},
+}
+
+// priceUsage fills in the estimated cost of a query. Local Ollama models
+// cost nothing.
+func priceUsage(provider AIProvider, model string, u aiUsage) aiUsage {
+	if _, ok := provider.(*ollamaProvider); ok {
+		u.Cost = 0
+		return u
+	}
+	var best *modelPrice
+	for i, p := range modelPrices {
+		if strings.HasPrefix(model, p.prefix) && (best == nil || len(p.prefix) > len(best.prefix)) {
+			best = &modelPrices[i]
+		}
+	}
+	if best == nil {
+		u.Unpriced = 1
+		return u
+	}
+	u.Cost = (float64(u.PromptTokens)*best.input + float64(u.CompletionTokens)*best.output) / 1e6
+	return u
+}
+
+// groupThousands formats n with comma separators
+func groupThousands(n int) string {
+	s := fmt.Sprintf("%d", n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
@@ -0,0 +1,30 @@
+package ui
+
+import "testing"
+
+func TestPriceUsage(t *testing.T) {
+	u := aiUsage{PromptTokens: 1_000_000, CompletionTokens: 100_000}
+	tests := []struct {
+		name     string
+		provider AIProvider
+		model    string
+		want     string
+	}{
+		{"longest prefix wins", &openAIProvider{}, "gpt-4o-mini-2024-07-18", "1,000,000 in / 100,000 out (~$0.2100)"},
+		{"dated version", &anthropicProvider{}, "claude-3-haiku-20240307", "1,000,000 in / 100,000 out (~$0.3750)"},
+		{"ollama is free", &ollamaProvider{}, "llama2", "1,000,000 in / 100,000 out (~$0.0000)"},
+		{"unknown model", &openAIProvider{}, "my-finetune", "1,000,000 in / 100,000 out (cost unknown)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := priceUsage(tt.provider, tt.model, u).String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	session := priceUsage(&openAIProvider{}, "gpt-4o", u).add(priceUsage(&openAIProvider{}, "my-finetune", u))
+	if got, want := session.String(), "2,000,000 in / 200,000 out (~$3.5000 + unpriced)"; got != want {
+		t.Errorf("session = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/digitalis-io/kconduit/pkg/alerts"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+)
+
+// alertSnapshotMsg carries the cluster state alert rules are evaluated against
+type alertSnapshotMsg struct {
+	snapshot alerts.Snapshot
+	err      error
+}
+
+type alertsNotifiedMsg struct {
+	err error
+}
+
+// checkAlerts fetches everything the alert rules need. The consumer groups
+// double as the lag history sample. When the brokers cannot be listed the
+// snapshot marks the cluster unreachable so broker alerts still fire.
+func checkAlerts(client *kafka.Client) tea.Cmd {
+	return func() tea.Msg {
+		brokers, err := client.GetBrokers()
+		if err != nil {
+			return alertSnapshotMsg{snapshot: alerts.Snapshot{Unreachable: true}, err: err}
+		}
+		groups, err := client.GetConsumerGroups()
+		if err != nil {
+			return alertSnapshotMsg{err: err}
+		}
+		stats, err := client.GetClusterStats()
+		if err != nil {
+			return alertSnapshotMsg{err: err}
+		}
+		return alertSnapshotMsg{snapshot: alerts.Snapshot{Groups: groups, Stats: stats, Brokers: brokers}}
+	}
+}
+
+func notifyAlerts(notifier *alerts.Notifier, started, resolved []alerts.Violation) tea.Cmd {
+	return func() tea.Msg {
+		return alertsNotifiedMsg{err: notifier.Notify(started, resolved)}
+	}
+}
+
+// WithAlerts evaluates the given rules on every background sample
+func (m Model) WithAlerts(cfg *alerts.Config) Model {
+	m.alertEvaluator = alerts.NewEvaluator(cfg.Rules)
+	if len(cfg.AllWebhooks()) > 0 || cfg.Desktop {
+		m.alertNotifier = alerts.NewNotifier(cfg)
+	}
+	return m
+}
+
+// evaluateAlerts applies a snapshot and returns the notification command, if any
+func (m *Model) evaluateAlerts(snapshot alerts.Snapshot) tea.Cmd {
+	firing, started, resolved := m.alertEvaluator.Evaluate(snapshot)
+	m.activeAlerts = firing
+	for _, v := range started {
+		logger.Get().WithField("rule", v.Rule).Warn("Alert firing: " + v.Message)
+	}
+	for _, v := range resolved {
+		logger.Get().WithField("rule", v.Rule).Info("Alert resolved: " + v.Message)
+	}
+	if m.alertNotifier == nil || (len(started) == 0 && len(resolved) == 0) {
+		return nil
+	}
+	return notifyAlerts(m.alertNotifier, started, resolved)
+}
+
+// alertingGroups returns the consumer groups with a firing lag alert
+func (m Model) alertingGroups() map[string]bool {
+	groups := make(map[string]bool)
+	for _, v := range m.activeAlerts {
+		if v.Type == alerts.RuleConsumerLag {
+			groups[v.Subject] = true
+		}
+	}
+	return groups
+}
+
+// renderAlertBanner lists firing alerts under the tab bar
+func (m Model) renderAlertBanner() string {
+	if len(m.activeAlerts) == 0 {
+		return ""
+	}
+	msgs := make([]string, len(m.activeAlerts))
+	for i, v := range m.activeAlerts {
+		msgs[i] = v.Message
+	}
+	style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("196"))
+	return style.Render(fmt.Sprintf("🚨 %d alert(s): %s", len(m.activeAlerts), strings.Join(msgs, " · ")))
+}
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ASCIIOnly replaces emoji and box-drawing characters with ASCII in
+// everything the UI renders, for terminals that cannot display them
+var ASCIIOnly bool
+
+// asciiReplacer maps the symbols the UI uses to ASCII stand-ins. The layout
+// is measured before they are swapped in, so each stand-in is padded or cut
+// to the width of its symbol and borders and columns stay aligned.
+var asciiReplacer = newWidthReplacer(
+	// Status icons
+	"✅", "ok", "✓", "+", "❌", "x", "⚠️", "!", "⚠", "!", "🚫", "no", "🚨", "!!",
+	"ℹ️", "i", "ℹ", "i", "💡", "i", "⏳", "..", "⏸", "=", "⏹", "#", "▶", ">",
+	// Arrows and punctuation
+	"→", ">", "←", "<", "↑", "^", "↓", "v", "↶", "<", "↩", "<",
+	"…", ".", "⋯", ".", "•", "*", "·", "-", "−", "-", "≤", "<", "≥", ">",
+	// Box drawing, from lipgloss borders and the UI's own rules
+	"─", "-", "━", "-", "═", "=", "│", "|", "┃", "|", "║", "|",
+	"╭", "+", "╮", "+", "╰", "+", "╯", "+", "┌", "+", "┐", "+", "└", "+", "┘", "+",
+	"├", "+", "┤", "+", "┬", "+", "┴", "+", "┼", "+",
+	// Bars and sparklines
+	"█", "#", "░", ".", "▁", "_", "▂", "_", "▃", "-", "▄", "-", "▅", "=", "▆", "=", "▇", "#",
+)
+
+// newWidthReplacer is strings.NewReplacer with every replacement fitted to
+// the display width of what it replaces
+func newWidthReplacer(pairs ...string) *strings.Replacer {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		pairs[i+1] = fitWidth(pairs[i+1], ansi.StringWidth(pairs[i]))
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// fitWidth pads or cuts an ASCII string to width columns
+func fitWidth(s string, width int) string {
+	if len(s) >= width {
+		return s[:width]
+	}
+	return s + strings.Repeat(" ", width-len(s))
+}
+
+// emojiPresentation is the variation selector asking for a symbol to be
+// drawn as an emoji, which can make it two columns wide
+const emojiPresentation = "\uFE0F"
+
+// ASCII returns s with the UI's symbols replaced by ASCII when ASCIIOnly is
+// set. Symbols without a stand-in become "*", padded to their width; letters
+// are kept, so topic names and message contents are shown as they are.
+// Every replacement takes the columns of the symbol it replaces, so s can
+// be a finished layout.
+func ASCII(s string) string {
+	if !ASCIIOnly {
+		return s
+	}
+	s = asciiReplacer.Replace(s)
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case s[i:i+size] == emojiPresentation:
+			// Left over without a symbol, it takes no columns
+		case r >= 0x80 && isPictograph(r):
+			symbol := s[i : i+size]
+			if strings.HasPrefix(s[i+size:], emojiPresentation) {
+				symbol += emojiPresentation
+			}
+			sb.WriteString(fitWidth("*", ansi.StringWidth(symbol)))
+			size = len(symbol)
+		default:
+			sb.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return sb.String()
+}
+
+// isPictograph reports whether r is an emoji or drawing symbol rather than
+// text
+func isPictograph(r rune) bool {
+	switch {
+	case r >= 0x1F000: // Emoji blocks
+		return true
+	case r >= 0x2190 && r <= 0x2BFF: // Arrows, shapes, dingbats, misc symbols
+		return true
+	}
+	return unicode.Is(unicode.So, r)
+}
+
+// DetectASCII guesses whether the terminal lacks emoji support: the Linux
+// console never has it, and a locale that is set but not UTF-8 cannot
+// encode it
+func DetectASCII() bool {
+	if os.Getenv("TERM") == "linux" {
+		return true
+	}
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			v = strings.ToLower(v)
+			return !strings.Contains(v, "utf-8") && !strings.Contains(v, "utf8")
+		}
+	}
+	return false
+}
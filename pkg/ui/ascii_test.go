@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestASCII(t *testing.T) {
+	ASCIIOnly = true
+	defer func() { ASCIIOnly = false }()
+
+	tests := []struct {
+		in, want string
+	}{
+		{"✅ All Online", "ok All Online"},
+		{"⚠️  3 partitions", "!   3 partitions"},
+		{"⚠ lag", "! lag"},
+		{"╭──╮\n│ä │\n╰──╯", "+--+\n|ä |\n+--+"},
+		{"📁 orders → café", "*  orders > café"},
+		{"☀️ and ☀", "*  and *"},
+		{"▁▄█", "_-#"},
+		{"\x1b[1m✓\x1b[0m", "\x1b[1m+\x1b[0m"},
+	}
+	for _, tt := range tests {
+		got := ASCII(tt.in)
+		if got != tt.want {
+			t.Errorf("ASCII(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		// Layouts are measured before the swap, so widths must not change
+		if ansi.StringWidth(got) != ansi.StringWidth(tt.in) {
+			t.Errorf("ASCII(%q) is %d columns wide, want %d", tt.in, ansi.StringWidth(got), ansi.StringWidth(tt.in))
+		}
+	}
+}
+
+func TestASCIIKeepsBoxAligned(t *testing.T) {
+	ASCIIOnly = true
+	defer func() { ASCIIOnly = false }()
+
+	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Render("✅ ok\n🚨 alert\n⚠️ lag")
+	for _, line := range strings.Split(ASCII(box), "\n") {
+		if w := ansi.StringWidth(line); w != lipgloss.Width(box) {
+			t.Errorf("line %q is %d columns wide, want %d", line, w, lipgloss.Width(box))
+		}
+	}
+}
+
+func TestDetectASCII(t *testing.T) {
+	tests := []struct {
+		term, lcAll, lang string
+		want              bool
+	}{
+		{"xterm-256color", "", "en_US.UTF-8", false},
+		{"xterm-256color", "", "en_US.utf8", false},
+		{"xterm-256color", "C", "en_US.UTF-8", true},
+		{"linux", "", "en_US.UTF-8", true},
+		{"xterm", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Setenv("TERM", tt.term)
+		t.Setenv("LC_ALL", tt.lcAll)
+		t.Setenv("LC_CTYPE", "")
+		t.Setenv("LANG", tt.lang)
+		if got := DetectASCII(); got != tt.want {
+			t.Errorf("DetectASCII() with TERM=%q LC_ALL=%q LANG=%q = %v, want %v", tt.term, tt.lcAll, tt.lang, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// BinaryFormat selects how non-text payloads are rendered
+type BinaryFormat int
+
+const (
+	BinaryHex BinaryFormat = iota
+	BinaryBase64
+)
+
+func (f BinaryFormat) String() string {
+	if f == BinaryBase64 {
+		return "base64"
+	}
+	return "hex"
+}
+
+// Next returns the other format, used by the toggle key
+func (f BinaryFormat) Next() BinaryFormat {
+	if f == BinaryHex {
+		return BinaryBase64
+	}
+	return BinaryHex
+}
+
+// isBinary reports whether s cannot be printed safely as text, i.e. it is not
+// valid UTF-8 or contains control characters other than common whitespace
+func isBinary(s string) bool {
+	if !utf8.ValidString(s) {
+		return true
+	}
+	for _, r := range s {
+		if unicode.IsControl(r) && r != '\n' && r != '\r' && r != '\t' {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeBinary renders data in the given format on a single line
+func encodeBinary(data string, format BinaryFormat) string {
+	if format == BinaryBase64 {
+		return base64.StdEncoding.EncodeToString([]byte(data))
+	}
+	return hex.EncodeToString([]byte(data))
+}
+
+// binaryPreviewBytes limits how much of a binary payload is encoded for a
+// table cell, the table truncates anyway and rows are rebuilt frequently
+const binaryPreviewBytes = 64
+
+// displayPayload returns s unchanged when it is text, otherwise a one-line
+// encoded preview prefixed with its size so it can go in a table cell
+func displayPayload(s string, format BinaryFormat) string {
+	if !isBinary(s) {
+		return s
+	}
+	preview := s
+	if len(preview) > binaryPreviewBytes {
+		preview = preview[:binaryPreviewBytes]
+	}
+	return fmt.Sprintf("[%d bytes %s] %s", len(s), format, encodeBinary(preview, format))
+}
+
+// renderBinaryBlock renders a binary payload for the detail view: a classic
+// hex dump with offsets and ASCII column, or base64 wrapped to width
+func renderBinaryBlock(s string, format BinaryFormat, width int) string {
+	if format == BinaryHex {
+		return strings.TrimRight(hex.Dump([]byte(s)), "\n")
+	}
+	encoded := encodeBinary(s, BinaryBase64)
+	if width <= 0 {
+		return encoded
+	}
+	var lines []string
+	for len(encoded) > width {
+		lines = append(lines, encoded[:width])
+		encoded = encoded[width:]
+	}
+	lines = append(lines, encoded)
+	return strings.Join(lines, "\n")
+}
+
+// copyablePayload returns text payloads as-is and binary ones encoded, since
+// raw bytes cannot be pasted meaningfully
+func copyablePayload(s string, format BinaryFormat) string {
+	if !isBinary(s) {
+		return s
+	}
+	return encodeBinary(s, format)
+}
@@ -0,0 +1,27 @@
+package ui
+
+import "testing"
+
+func TestIsBinary(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+		name     string
+	}{
+		{"hello world", false, "plain text"},
+		{"{\n\t\"a\": 1\r\n}", false, "text with whitespace"},
+		{"héllo 🌍", false, "multibyte UTF-8"},
+		{"", false, "empty"},
+		{"\x00\x01\x02", true, "control bytes"},
+		{"abc\x1b[31m", true, "terminal escape sequence"},
+		{"\xff\xfe", true, "invalid UTF-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := isBinary(tt.input); result != tt.expected {
+				t.Errorf("isBinary(%q) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+// BrokerLoggersModel shows the log4j loggers of one broker and changes
+// their levels at runtime
+type BrokerLoggersModel struct {
+	client    *kafka.Client
+	broker    kafka.BrokerInfo
+	loggers   []kafka.BrokerLogger
+	table     table.Model
+	filter    textinput.Model
+	filtering bool
+	picking   bool // Choosing a level for the selected logger
+	level     int  // Index into kafka.LogLevels while picking
+	loading   bool
+	result    string
+	err       error
+	width     int
+	height    int
+}
+
+func NewBrokerLoggersModel(client *kafka.Client, broker kafka.BrokerInfo) BrokerLoggersModel {
+	t := table.New(
+		table.WithColumns([]table.Column{
+			{Title: "Logger", Width: 70},
+			{Title: "Level", Width: 8},
+		}),
+		table.WithRows([]table.Row{}),
+		table.WithFocused(true),
+		table.WithHeight(15),
+	)
+
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		BorderBottom(true).
+		Bold(false)
+	s.Selected = s.Selected.
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Bold(false)
+	t.SetStyles(s)
+
+	ti := textinput.New()
+	ti.Placeholder = "e.g. kafka.request"
+	ti.CharLimit = 100
+	ti.Width = 40
+
+	return BrokerLoggersModel{client: client, broker: broker, table: t, filter: ti, loading: true}
+}
+
+type brokerLoggersMsg struct {
+	loggers []kafka.BrokerLogger
+	err     error
+}
+
+type brokerLoggerSetMsg struct {
+	result string
+	err    error
+}
+
+func fetchBrokerLoggers(client *kafka.Client, brokerID int32) tea.Cmd {
+	return func() tea.Msg {
+		loggers, err := client.GetBrokerLoggers(brokerID)
+		return brokerLoggersMsg{loggers: loggers, err: err}
+	}
+}
+
+func setBrokerLoggerLevel(client *kafka.Client, brokerID int32, name, level string) tea.Cmd {
+	return func() tea.Msg {
+		err := client.SetBrokerLoggerLevel(brokerID, name, level)
+		result := fmt.Sprintf("Set %s to %s", name, level)
+		if level == "" {
+			result = fmt.Sprintf("Reset %s to the root logger's level", name)
+		}
+		return brokerLoggerSetMsg{result: result, err: err}
+	}
+}
+
+func (m BrokerLoggersModel) Init() tea.Cmd {
+	return fetchBrokerLoggers(m.client, m.broker.ID)
+}
+
+func (m *BrokerLoggersModel) updateRows() {
+	filter := strings.ToLower(m.filter.Value())
+	var rows []table.Row
+	for _, l := range m.loggers {
+		if filter == "" || strings.Contains(strings.ToLower(l.Name), filter) {
+			rows = append(rows, table.Row{l.Name, l.Level})
+		}
+	}
+	setRowsKeepingCursor(&m.table, rows, firstColumn)
+}
+
+func (m BrokerLoggersModel) Update(msg tea.Msg) (BrokerLoggersModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case brokerLoggersMsg:
+		m.loading = false
+		m.err = msg.err
+		if msg.err == nil {
+			m.loggers = msg.loggers
+			m.updateRows()
+		}
+		return m, nil
+
+	case brokerLoggerSetMsg:
+		m.err = msg.err
+		m.result = ""
+		if msg.err != nil {
+			return m, reportError("broker loggers", msg.err)
+		}
+		m.result = msg.result
+		m.loading = true
+		return m, fetchBrokerLoggers(m.client, m.broker.ID)
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.table.SetHeight(max(msg.Height-12, 5))
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.filtering {
+			switch msg.String() {
+			case "esc":
+				m.filtering = false
+				m.filter.Blur()
+				m.filter.SetValue("")
+				m.updateRows()
+				return m, nil
+			case "enter":
+				m.filtering = false
+				m.filter.Blur()
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.filter, cmd = m.filter.Update(msg)
+			m.updateRows()
+			return m, cmd
+		}
+
+		if m.picking {
+			switch msg.String() {
+			case "left", "h":
+				m.level = max(m.level-1, 0)
+			case "right", "l":
+				m.level = min(m.level+1, len(kafka.LogLevels)-1)
+			case "esc":
+				m.picking = false
+			case "enter":
+				m.picking = false
+				m.result, m.err = "", nil
+				return m, setBrokerLoggerLevel(m.client, m.broker.ID, m.table.SelectedRow()[0], kafka.LogLevels[m.level])
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "esc", "q":
+			return m, ReturnToListView
+		case "r":
+			m.loading = true
+			return m, fetchBrokerLoggers(m.client, m.broker.ID)
+		case "/":
+			m.filtering = true
+			return m, m.filter.Focus()
+		case "enter":
+			if row := m.table.SelectedRow(); row != nil {
+				m.picking = true
+				m.level = max(slices.Index(kafka.LogLevels, row[1]), 0)
+			}
+			return m, nil
+		case "d":
+			if row := m.table.SelectedRow(); row != nil {
+				m.result, m.err = "", nil
+				return m, setBrokerLoggerLevel(m.client, m.broker.ID, row[0], "")
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+func (m BrokerLoggersModel) View() string {
+	var s strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Padding(0, 1)
+	okStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("46"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("229")).Background(lipgloss.Color("57")).Padding(0, 1)
+
+	s.WriteString(titleStyle.Render(fmt.Sprintf("📜 Loggers of broker %d (%s)", m.broker.ID, m.broker.Host)))
+	s.WriteString("\n\n")
+
+	if m.err != nil {
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(fmt.Sprintf("❌ %v", m.err)))
+		s.WriteString("\n\n")
+	}
+	if m.result != "" {
+		s.WriteString(okStyle.Render("✅ " + m.result))
+		s.WriteString("\n\n")
+	}
+
+	if m.loggers == nil {
+		if m.loading {
+			s.WriteString("Loading loggers...")
+		}
+		s.WriteString("\n\n" + dimStyle.Render("r: Refresh | Esc: Back"))
+		return s.String()
+	}
+
+	if m.filtering || m.filter.Value() != "" {
+		s.WriteString("Filter: " + m.filter.View())
+		s.WriteString("\n\n")
+	}
+	s.WriteString(m.table.View())
+	s.WriteString("\n\n")
+
+	if m.picking {
+		s.WriteString("Level for " + m.table.SelectedRow()[0] + ": ")
+		for i, level := range kafka.LogLevels {
+			if i == m.level {
+				s.WriteString(selectedStyle.Render(level))
+			} else {
+				s.WriteString(" " + level + " ")
+			}
+		}
+		s.WriteString("\n\n" + dimStyle.Render("←/→: Choose | Enter: Apply | Esc: Cancel"))
+		return s.String()
+	}
+
+	s.WriteString(dimStyle.Render("Levels set here last until the broker restarts"))
+	s.WriteString("\n")
+	s.WriteString(dimStyle.Render("Enter: Set level | d: Reset to root level | /: Filter | r: Refresh | Esc: Back"))
+	return s.String()
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+)
+
+type clipboardCopiedMsg struct {
+	what string
+	err  error
+}
+
+// copyToClipboard writes text to the system clipboard. When no native clipboard
+// is available (e.g. over SSH) it falls back to an OSC52 escape sequence, which
+// most modern terminals forward to the local clipboard.
+func copyToClipboard(what, text string) tea.Cmd {
+	return func() tea.Msg {
+		err := clipboard.WriteAll(text)
+		if err == nil {
+			return clipboardCopiedMsg{what: what}
+		}
+		logger.Get().WithError(err).Debug("Native clipboard unavailable, falling back to OSC52")
+
+		seq := osc52.New(text)
+		if os.Getenv("TMUX") != "" {
+			seq = seq.Tmux()
+		} else if strings.HasPrefix(os.Getenv("TERM"), "screen") {
+			seq = seq.Screen()
+		}
+		if _, err := seq.WriteTo(os.Stderr); err != nil {
+			return clipboardCopiedMsg{what: what, err: err}
+		}
+		return clipboardCopiedMsg{what: what}
+	}
+}
+
+// messageRecord is the JSON shape used when copying a whole message
+type messageRecord struct {
+	Topic     string            `json:"topic"`
+	Partition int32             `json:"partition"`
+	Offset    int64             `json:"offset"`
+	Timestamp time.Time         `json:"timestamp"`
+	Key       string            `json:"key"`
+	Value     string            `json:"value"`
+	Encoding  string            `json:"encoding,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+}
+
+// messageToJSON renders the full record as indented JSON
+func messageToJSON(msg kafka.Message) (string, error) {
+	record := messageRecord{
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Timestamp: msg.Timestamp,
+		Key:       msg.Key,
+		Value:     msg.Value,
+		Headers:   msg.Headers,
+	}
+	// JSON strings cannot carry arbitrary bytes, so binary keys and values are base64 encoded
+	if isBinary(msg.Key) || isBinary(msg.Value) {
+		record.Key = encodeBinary(msg.Key, BinaryBase64)
+		record.Value = encodeBinary(msg.Value, BinaryBase64)
+		record.Encoding = "base64"
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -3,15 +3,20 @@ package ui
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/IBM/sarama"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+	"github.com/itchyny/gojq"
 )
 
 type ConsumerMode int
@@ -20,16 +25,45 @@ const (
 	ModeNormal ConsumerMode = iota
 	ModeOffsetDialog
 	ModeSearch
+	ModeDetail
+	ModeAsk // Asking the AI assistant about the buffered messages
 )
 
+// headerFilterPrefix marks a search term that matches message headers rather
+// than the key/value, e.g. "header:trace-id" or "header:source=billing".
+const headerFilterPrefix = "header:"
+
+// MaxConsumerMessages caps how many messages the consumer view retains. Older
+// messages are dropped once the limit is reached; 0 disables the limit.
+var MaxConsumerMessages = 10000
+
 type OffsetOption int
 
 const (
 	OffsetOldest OffsetOption = iota
 	OffsetNewest
 	OffsetSpecific
+	OffsetLastN
+	OffsetTimestamp
+)
+
+// offsetOptionCount is the number of entries in the offset dialog
+const offsetOptionCount = 5
+
+// DialogFocus tracks which section of the offset dialog receives input
+type DialogFocus int
+
+const (
+	FocusStartPosition DialogFocus = iota
+	FocusPartitions
+	FocusEndOffset
+	FocusEndTime
+	FocusGroup
+	FocusIsolation
 )
 
+const dialogFocusCount = 6
+
 type ConsumerModel struct {
 	topic        string
 	topicInfo    *kafka.TopicInfo
@@ -51,13 +85,57 @@ type ConsumerModel struct {
 	offsetOption   OffsetOption
 	offsetInput    textinput.Model
 	startOffset    int64
+	lastNInput     textinput.Model
+	lastN          int64
+	startTimeInput textinput.Model
+	// Optional end boundary, consumption stops once every partition reaches it
+	endOffsetInput textinput.Model
+	endTimeInput   textinput.Model
+	bounded        bool
+	finished       bool
+	partitionReads map[int32]int64
+	// Partition selection, empty means all partitions
+	partitionInput textinput.Model
+	partitions     []int32
+	dialogFocus    DialogFocus
+	// Consumer group mode, enabled when a group id is entered
+	groupInput    textinput.Model
+	groupConsumer *kafka.GroupConsumer
+	groupEvent    *kafka.GroupEvent
+	rebalances    int
+	// Transaction visibility
+	isolation    sarama.IsolationLevel
+	skippedTotal int64
+	// Throughput over a sliding window
+	rates rateTracker
 	// New fields for search
 	searchInput     textinput.Model
 	searchTerm      string
 	searchResults   []int
+	searchQuery     *gojq.Code
 	currentMatch    int
 	filteredIndices []int
 	showFiltered    bool
+	// Message detail view
+	detailViewport viewport.Model
+	detailIndex    int
+	detailMessage  kafka.Message
+	detailPayload  decodedPayload
+	binaryFormat   BinaryFormat
+	// Detected value format, parallel to messages
+	payloads []decodedPayload
+	// JSON fields shown as columns in place of the value, with each
+	// message's extracted values kept parallel to messages
+	jsonColumns  []string
+	fieldValues  [][]string
+	columnPicker *JSONColumnsModel
+	// AI questions about the buffered messages
+	assistant AIAssistantModel
+	question  *messageQuestion
+	// Ring buffer bookkeeping
+	maxMessages int
+	dropped     int
+	statusMsg   string
 }
 
 func NewConsumerModel(topic string, client *kafka.Client) ConsumerModel {
@@ -71,7 +149,9 @@ func NewConsumerModel(topic string, client *kafka.Client) ConsumerModel {
 		{Title: "Part", Width: 5},
 		{Title: "Offset", Width: 10},
 		{Title: "Key", Width: 20},
+		{Title: "Format", Width: 10},
 		{Title: "Value", Width: 50},
+		{Title: "Headers", Width: 20},
 		{Title: "Size", Width: 8},
 	}
 
@@ -112,9 +192,33 @@ func NewConsumerModel(topic string, client *kafka.Client) ConsumerModel {
 	offsetInput.Placeholder = "Enter offset number (e.g., 100)"
 	offsetInput.CharLimit = 20
 
+	lastNInput := textinput.New()
+	lastNInput.Placeholder = "Number of messages per partition (e.g., 100)"
+	lastNInput.CharLimit = 10
+
+	startTimeInput := textinput.New()
+	startTimeInput.Placeholder = "Timestamp (e.g., -1h, 2024-01-31 09:00:00, Unix ms)"
+	startTimeInput.CharLimit = 40
+
+	endOffsetInput := textinput.New()
+	endOffsetInput.Placeholder = "None (last offset to read in each partition)"
+	endOffsetInput.CharLimit = 20
+
+	endTimeInput := textinput.New()
+	endTimeInput.Placeholder = "None (e.g., now, -30m, 2024-01-31 10:00:00)"
+	endTimeInput.CharLimit = 40
+
+	partitionInput := textinput.New()
+	partitionInput.Placeholder = "All partitions (e.g., 0 or 0,2,5-7)"
+	partitionInput.CharLimit = 100
+
+	groupInput := textinput.New()
+	groupInput.Placeholder = "None (e.g., kconduit-debug)"
+	groupInput.CharLimit = 255
+
 	searchInput := textinput.New()
-	searchInput.Placeholder = "Search messages..."
-	searchInput.CharLimit = 100
+	searchInput.Placeholder = "Search messages... (header:key=value, or jq:.field == \"x\" for JSON values)"
+	searchInput.CharLimit = 500
 
 	return ConsumerModel{
 		topic:           topic,
@@ -132,10 +236,19 @@ func NewConsumerModel(topic string, client *kafka.Client) ConsumerModel {
 		mode:            ModeOffsetDialog,
 		offsetOption:    OffsetNewest,
 		offsetInput:     offsetInput,
+		lastNInput:      lastNInput,
+		startTimeInput:  startTimeInput,
+		endOffsetInput:  endOffsetInput,
+		endTimeInput:    endTimeInput,
+		partitionReads:  make(map[int32]int64),
+		partitionInput:  partitionInput,
+		groupInput:      groupInput,
 		searchInput:     searchInput,
+		detailViewport:  viewport.New(80, 20),
 		searchResults:   []int{},
 		filteredIndices: []int{},
 		startOffset:     sarama.OffsetNewest,
+		maxMessages:     MaxConsumerMessages,
 	}
 }
 
@@ -147,10 +260,13 @@ type consumerErrorMsg struct {
 	err error
 }
 
-func consumeMessages(ctx context.Context, client *kafka.Client, topic string, messageChan chan kafka.Message, offset int64) tea.Cmd {
+// consumeFinishedMsg is sent when bounded consumption reached its end
+type consumeFinishedMsg struct{}
+
+func consumeMessages(ctx context.Context, client *kafka.Client, topic string, messageChan chan kafka.Message, opts kafka.ConsumeOptions) tea.Cmd {
 	return func() tea.Msg {
 		go func() {
-			err := client.ConsumeMessagesWithOffset(ctx, topic, messageChan, offset)
+			err := client.ConsumeMessagesWithOptions(ctx, topic, messageChan, opts)
 			if err != nil && ctx.Err() == nil {
 				// Only report error if context wasn't cancelled
 				messageChan <- kafka.Message{} // Send empty message to signal error
@@ -160,6 +276,76 @@ func consumeMessages(ctx context.Context, client *kafka.Client, topic string, me
 	}
 }
 
+// consumeBounded reads up to the end boundary in opts and reports when every
+// partition reached it
+func consumeBounded(ctx context.Context, client *kafka.Client, topic string, messageChan chan kafka.Message, opts kafka.ConsumeOptions) tea.Cmd {
+	return func() tea.Msg {
+		err := client.ConsumeMessagesWithOptions(ctx, topic, messageChan, opts)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return consumerErrorMsg{err: err}
+		}
+		return consumeFinishedMsg{}
+	}
+}
+
+type groupConsumerStartedMsg struct {
+	consumer *kafka.GroupConsumer
+	err      error
+}
+
+type groupEventMsg struct {
+	event kafka.GroupEvent
+}
+
+type groupOffsetCommittedMsg struct {
+	partition int32
+	offset    int64
+	err       error
+}
+
+func startGroupConsumer(client *kafka.Client, groupID string, initialOffset int64, isolation sarama.IsolationLevel) tea.Cmd {
+	return func() tea.Msg {
+		consumer, err := client.NewGroupConsumer(groupID, initialOffset, isolation)
+		return groupConsumerStartedMsg{consumer: consumer, err: err}
+	}
+}
+
+func consumeGroup(ctx context.Context, consumer *kafka.GroupConsumer, topic string, messageChan chan kafka.Message) tea.Cmd {
+	return func() tea.Msg {
+		go func() {
+			err := consumer.Consume(ctx, topic, messageChan)
+			if err != nil && ctx.Err() == nil {
+				logger.Get().WithError(err).Error("Consumer group stopped")
+				messageChan <- kafka.Message{} // Send empty message to signal error
+			}
+		}()
+		return nil
+	}
+}
+
+func waitForGroupEvent(consumer *kafka.GroupConsumer) tea.Cmd {
+	return func() tea.Msg {
+		event, ok := <-consumer.Events()
+		if !ok {
+			// The consumer has stopped
+			return nil
+		}
+		return groupEventMsg{event: event}
+	}
+}
+
+func commitGroupOffset(consumer *kafka.GroupConsumer, msg kafka.Message) tea.Cmd {
+	return func() tea.Msg {
+		// The committed offset is the next message the group will read
+		offset := msg.Offset + 1
+		err := consumer.Commit(msg.Topic, msg.Partition, offset)
+		return groupOffsetCommittedMsg{partition: msg.Partition, offset: offset, err: err}
+	}
+}
+
 func waitForMessage(messageChan chan kafka.Message) tea.Cmd {
 	return func() tea.Msg {
 		msg := <-messageChan
@@ -167,6 +353,13 @@ func waitForMessage(messageChan chan kafka.Message) tea.Cmd {
 	}
 }
 
+// WithAssistant lets the view ask the AI assistant about its messages, with
+// the assistant's provider and model
+func (m ConsumerModel) WithAssistant(assistant AIAssistantModel) ConsumerModel {
+	m.assistant = assistant
+	return m
+}
+
 func (m ConsumerModel) Init() tea.Cmd {
 	// Start with offset dialog, don't consume yet
 	return textinput.Blink
@@ -175,6 +368,14 @@ func (m ConsumerModel) Init() tea.Cmd {
 func (m ConsumerModel) Update(msg tea.Msg) (ConsumerModel, tea.Cmd) {
 	var cmds []tea.Cmd
 
+	// Keep the throughput display ticking regardless of the current mode
+	if _, ok := msg.(rateTickMsg); ok {
+		if m.ctx.Err() != nil {
+			return m, nil
+		}
+		return m, rateTick()
+	}
+
 	// Handle offset dialog mode
 	if m.mode == ModeOffsetDialog {
 		switch msg := msg.(type) {
@@ -183,24 +384,30 @@ func (m ConsumerModel) Update(msg tea.Msg) (ConsumerModel, tea.Cmd) {
 			case "esc":
 				m.cancel()
 				return m, ReturnToListView
-			case "tab", "down", "j":
+			case "tab":
+				// Move between start position, partition filter and group id
+				m.dialogFocus = DialogFocus((int(m.dialogFocus) + 1) % dialogFocusCount)
+				cmds = append(cmds, m.focusOffsetInputs())
+			case "shift+tab":
+				m.dialogFocus = DialogFocus((int(m.dialogFocus) + dialogFocusCount - 1) % dialogFocusCount)
+				cmds = append(cmds, m.focusOffsetInputs())
+			case "down", "j":
 				// Move to next offset option
-				m.offsetOption = OffsetOption((int(m.offsetOption) + 1) % 3)
-				if m.offsetOption == OffsetSpecific {
-					m.offsetInput.Focus()
-					cmds = append(cmds, textinput.Blink)
-				} else {
-					m.offsetInput.Blur()
+				m.offsetOption = OffsetOption((int(m.offsetOption) + 1) % offsetOptionCount)
+				cmds = append(cmds, m.focusOffsetInputs())
+			case "left", "right", " ":
+				if m.dialogFocus == FocusIsolation {
+					if m.isolation == sarama.ReadCommitted {
+						m.isolation = sarama.ReadUncommitted
+					} else {
+						m.isolation = sarama.ReadCommitted
+					}
+					return m, nil
 				}
-			case "shift+tab", "up", "k":
+			case "up", "k":
 				// Move to previous offset option
-				m.offsetOption = OffsetOption((int(m.offsetOption) + 2) % 3)
-				if m.offsetOption == OffsetSpecific {
-					m.offsetInput.Focus()
-					cmds = append(cmds, textinput.Blink)
-				} else {
-					m.offsetInput.Blur()
-				}
+				m.offsetOption = OffsetOption((int(m.offsetOption) + offsetOptionCount - 1) % offsetOptionCount)
+				cmds = append(cmds, m.focusOffsetInputs())
 			case "enter":
 				// Start consuming with selected offset
 				switch m.offsetOption {
@@ -215,19 +422,107 @@ func (m ConsumerModel) Update(msg tea.Msg) (ConsumerModel, tea.Cmd) {
 						m.err = fmt.Errorf("invalid offset number: %s", m.offsetInput.Value())
 						return m, nil
 					}
+				case OffsetLastN:
+					n, err := strconv.ParseInt(m.lastNInput.Value(), 10, 64)
+					if err != nil || n <= 0 {
+						m.err = fmt.Errorf("invalid message count: %s", m.lastNInput.Value())
+						return m, nil
+					}
+					m.lastN = n
+				case OffsetTimestamp:
+					if strings.TrimSpace(m.startTimeInput.Value()) == "" {
+						m.err = fmt.Errorf("enter the timestamp to start from")
+						return m, nil
+					}
+				}
+				startTime, err := kafka.ParseTimestamp(m.startTimeInput.Value())
+				if err != nil && m.offsetOption == OffsetTimestamp {
+					m.err = err
+					return m, nil
+				}
+				var endOffset int64
+				if value := strings.TrimSpace(m.endOffsetInput.Value()); value != "" {
+					last, err := strconv.ParseInt(value, 10, 64)
+					if err != nil || last < 0 {
+						m.err = fmt.Errorf("invalid end offset: %s", value)
+						return m, nil
+					}
+					// The input is the last offset to read, the option is exclusive
+					endOffset = last + 1
+				}
+				endTime, err := kafka.ParseTimestamp(m.endTimeInput.Value())
+				if err != nil {
+					m.err = fmt.Errorf("end time: %w", err)
+					return m, nil
+				}
+				partitions, err := parsePartitionList(m.partitionInput.Value(), m.partitionCount())
+				if err != nil {
+					m.err = err
+					return m, nil
 				}
+				m.partitions = partitions
+				m.err = nil
+
+				if groupID := strings.TrimSpace(m.groupInput.Value()); groupID != "" {
+					if m.offsetOption != OffsetOldest && m.offsetOption != OffsetNewest {
+						m.err = fmt.Errorf("consumer group mode only supports the Oldest or Latest start position")
+						return m, nil
+					}
+					if len(partitions) > 0 {
+						m.err = fmt.Errorf("partitions are assigned by the group coordinator in consumer group mode")
+						return m, nil
+					}
+					if endOffset > 0 || !endTime.IsZero() {
+						m.err = fmt.Errorf("an end offset or time cannot be used in consumer group mode")
+						return m, nil
+					}
+					m.mode = ModeNormal
+					m.consuming = true
+					return m, tea.Batch(startGroupConsumer(m.client, groupID, m.startOffset, m.isolation), rateTick())
+				}
+
 				m.mode = ModeNormal
 				m.consuming = true
-				cmds = append(cmds, consumeMessages(m.ctx, m.client, m.topic, m.messageChan, m.startOffset))
+				opts := kafka.ConsumeOptions{
+					StartOffset:    m.startOffset,
+					LastN:          m.lastN,
+					Partitions:     m.partitions,
+					EndOffset:      endOffset,
+					EndTime:        endTime,
+					IsolationLevel: m.isolation,
+				}
+				if m.offsetOption == OffsetTimestamp {
+					opts.StartTime = startTime
+				}
+				m.bounded = opts.Bounded()
+				if m.bounded {
+					cmds = append(cmds, consumeBounded(m.ctx, m.client, m.topic, m.messageChan, opts))
+				} else {
+					cmds = append(cmds, consumeMessages(m.ctx, m.client, m.topic, m.messageChan, opts))
+				}
+				cmds = append(cmds, rateTick())
 				cmds = append(cmds, waitForMessage(m.messageChan))
 			}
 		}
 		// Update text input if focused
-		if m.offsetOption == OffsetSpecific {
-			var cmd tea.Cmd
+		var cmd tea.Cmd
+		switch {
+		case m.dialogFocus == FocusPartitions:
+			m.partitionInput, cmd = m.partitionInput.Update(msg)
+		case m.dialogFocus == FocusGroup:
+			m.groupInput, cmd = m.groupInput.Update(msg)
+		case m.dialogFocus == FocusEndOffset:
+			m.endOffsetInput, cmd = m.endOffsetInput.Update(msg)
+		case m.dialogFocus == FocusEndTime:
+			m.endTimeInput, cmd = m.endTimeInput.Update(msg)
+		case m.offsetOption == OffsetTimestamp:
+			m.startTimeInput, cmd = m.startTimeInput.Update(msg)
+		case m.offsetOption == OffsetSpecific:
 			m.offsetInput, cmd = m.offsetInput.Update(msg)
-			cmds = append(cmds, cmd)
+		case m.offsetOption == OffsetLastN:
+			m.lastNInput, cmd = m.lastNInput.Update(msg)
 		}
+		cmds = append(cmds, cmd)
 		return m, tea.Batch(cmds...)
 	}
 
@@ -243,8 +538,25 @@ func (m ConsumerModel) Update(msg tea.Msg) (ConsumerModel, tea.Cmd) {
 				m.showFiltered = false
 				m.updateTable()
 			case "enter":
-				m.searchTerm = m.searchInput.Value()
+				term := m.searchInput.Value()
+				m.searchQuery = nil
+				m.statusMsg = ""
+				if strings.HasPrefix(strings.ToLower(term), jqFilterPrefix) {
+					code, err := compileMessageQuery(term[len(jqFilterPrefix):])
+					if err != nil {
+						m.statusMsg = fmt.Sprintf("❌ %v", err)
+						return m, nil
+					}
+					// Expressions are meant to narrow the view, so filter straight away
+					m.searchQuery = code
+					m.showFiltered = true
+				}
+				m.searchTerm = term
 				m.performSearch()
+				m.updateTable()
+				if m.showFiltered && len(m.filteredIndices) == 0 {
+					m.statusMsg = "No messages match the filter"
+				}
 				m.mode = ModeNormal
 				m.searchInput.Blur()
 				if len(m.searchResults) > 0 {
@@ -259,21 +571,154 @@ func (m ConsumerModel) Update(msg tea.Msg) (ConsumerModel, tea.Cmd) {
 		return m, tea.Batch(cmds...)
 	}
 
+	// Handle message detail mode
+	if m.mode == ModeDetail {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch msg.String() {
+			case "q", "esc", "enter":
+				m.mode = ModeNormal
+				m.statusMsg = ""
+				return m, nil
+			case "v":
+				m.statusMsg = ""
+				return m, copyToClipboard("value", copyableValue(m.detailMessage.Value, m.detailPayload, m.binaryFormat))
+			case "K":
+				m.statusMsg = ""
+				return m, copyToClipboard("key", copyablePayload(m.detailMessage.Key, m.binaryFormat))
+			case "J":
+				m.statusMsg = ""
+				record, err := messageToJSON(m.detailMessage)
+				if err != nil {
+					m.statusMsg = fmt.Sprintf("❌ Failed to encode message: %v", err)
+					return m, nil
+				}
+				return m, copyToClipboard("record", record)
+			case "x":
+				// Switch binary payloads between hex and base64
+				m.binaryFormat = m.binaryFormat.Next()
+				m.detailViewport.SetContent(m.renderMessageDetail(m.detailMessage, m.detailPayload))
+				m.updateTable()
+				return m, nil
+			}
+		case clipboardCopiedMsg:
+			if msg.err != nil {
+				m.statusMsg = fmt.Sprintf("❌ Failed to copy %s: %v", msg.what, msg.err)
+			} else {
+				m.statusMsg = fmt.Sprintf("✅ Copied %s to clipboard", msg.what)
+			}
+			return m, nil
+		case tea.WindowSizeMsg:
+			m.width = msg.Width
+			m.height = msg.Height
+			m.resizeDetailViewport()
+		case messageReceivedMsg:
+			// Keep buffering in the background while the detail view is open
+			m.appendMessage(msg.message)
+			return m, waitForMessage(m.messageChan)
+		}
+		var cmd tea.Cmd
+		m.detailViewport, cmd = m.detailViewport.Update(msg)
+		return m, cmd
+	}
+
+	// Handle the AI question panel, messages keep buffering behind it
+	if m.mode == ModeAsk {
+		if cmd, handled := m.updateQuestion(msg); handled {
+			return m, cmd
+		}
+	}
+
+	// Handle the JSON column picker
+	if m.columnPicker != nil {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			if msg.Type == tea.KeyEsc {
+				m.columnPicker = nil
+				return m, nil
+			}
+			picker, cmd := m.columnPicker.Update(msg)
+			m.columnPicker = &picker
+			return m, cmd
+		case jsonColumnsChosenMsg:
+			m.columnPicker = nil
+			m.setJSONColumns(msg.paths)
+			return m, nil
+		case tea.WindowSizeMsg:
+			picker, _ := m.columnPicker.Update(msg)
+			m.columnPicker = &picker
+		}
+	}
+
 	// Normal mode
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
+		case "enter":
+			// Open the detail view for the selected message
+			if idx := m.selectedMessageIndex(); idx >= 0 {
+				// Keep a copy, the buffer may rotate while the detail view is open
+				m.detailIndex = idx + m.dropped
+				m.detailMessage = m.messages[idx]
+				m.detailPayload = m.payloads[idx]
+				m.mode = ModeDetail
+				m.resizeDetailViewport()
+				m.detailViewport.SetContent(m.renderMessageDetail(m.detailMessage, m.detailPayload))
+				m.detailViewport.GotoTop()
+				return m, nil
+			}
 		case "q", "esc":
 			m.cancel()
 			m.consuming = false
+			if m.question != nil {
+				m.question.cancel()
+			}
 			return m, ReturnToListView
+		case "C":
+			// Commit the selected message in consumer group mode
+			if m.groupConsumer == nil {
+				m.statusMsg = "⚠️  Offsets can only be committed in consumer group mode"
+			} else if idx := m.selectedMessageIndex(); idx >= 0 {
+				return m, commitGroupOffset(m.groupConsumer, m.messages[idx])
+			}
 		case "c":
 			// Clear messages
 			m.messages = []kafka.Message{}
+			m.payloads = nil
+			m.fieldValues = nil
+			m.dropped = 0
+			m.skippedTotal = 0
+			m.partitionReads = make(map[int32]int64)
+			m.rates.reset()
 			m.totalBytes = 0
 			m.searchResults = []int{}
 			m.filteredIndices = []int{}
 			m.updateTable()
+		case "x":
+			// Switch binary payloads between hex and base64
+			m.binaryFormat = m.binaryFormat.Next()
+			m.updateTable()
+		case "F":
+			// Pick JSON fields to show as columns
+			picker := NewJSONColumnsModel(m.payloads, m.jsonColumns)
+			picker, _ = picker.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+			m.columnPicker = &picker
+		case "a":
+			// Ask the AI assistant about the buffered messages
+			if len(m.messages) == 0 {
+				m.statusMsg = "⚠️  No messages to ask about yet"
+				return m, nil
+			}
+			if m.questionMessageCount() == 0 {
+				m.statusMsg = "⚠️  No messages match the filter"
+				return m, nil
+			}
+			if m.question == nil {
+				m.question = newMessageQuestion(m.assistant)
+			}
+			m.mode = ModeAsk
+			m.question.resize(m.width, m.messageTable.Height())
+			return m, m.question.input.Focus()
 		case "p":
 			// Pause/Resume consumption
 			m.consuming = !m.consuming
@@ -303,32 +748,59 @@ func (m ConsumerModel) Update(msg tea.Msg) (ConsumerModel, tea.Cmd) {
 		}
 
 	case messageReceivedMsg:
-		if msg.message.Topic != "" && m.consuming {
-			m.messages = append(m.messages, msg.message)
-			// Calculate message size
-			m.totalBytes += int64(len(msg.message.Key) + len(msg.message.Value))
-			// Check if new message matches search
-			if m.searchTerm != "" {
-				if m.messageMatches(msg.message, m.searchTerm) {
-					m.searchResults = append(m.searchResults, len(m.messages)-1)
-				}
-			}
-			m.updateTable()
-			if !m.showFiltered && len(m.messages) > 0 {
-				// Auto-scroll to bottom (select last row)
-				m.messageTable.SetCursor(len(m.tableRows) - 1)
-			}
+		if m.appendMessage(msg.message) && !m.showFiltered {
+			// Auto-scroll to bottom (select last row)
+			m.messageTable.SetCursor(len(m.tableRows) - 1)
 		}
 		// Continue waiting for more messages
 		cmds = append(cmds, waitForMessage(m.messageChan))
 
+	case consumeFinishedMsg:
+		m.finished = true
+		m.statusMsg = "🏁 Reached the end boundary: " + formatPartitionReads(m.partitionReads)
+
 	case consumerErrorMsg:
 		m.err = msg.err
+		cmds = append(cmds, reportError("consume", msg.err))
+
+	case groupConsumerStartedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			m.consuming = false
+			return m, reportError("consume", msg.err)
+		}
+		m.groupConsumer = msg.consumer
+		cmds = append(cmds, consumeGroup(m.ctx, m.groupConsumer, m.topic, m.messageChan))
+		cmds = append(cmds, waitForMessage(m.messageChan))
+		cmds = append(cmds, waitForGroupEvent(m.groupConsumer))
+
+	case groupEventMsg:
+		event := msg.event
+		m.groupEvent = &event
+		switch event.Type {
+		case kafka.GroupEventAssigned:
+			m.rebalances++
+			m.partitions = event.Claims[m.topic]
+			m.statusMsg = fmt.Sprintf("🔄 Rebalance: assigned %d partition(s) in generation %d", len(m.partitions), event.GenerationID)
+		case kafka.GroupEventRevoked:
+			m.partitions = nil
+			m.statusMsg = fmt.Sprintf("🔄 Rebalance: partitions revoked from generation %d", event.GenerationID)
+		case kafka.GroupEventError:
+			m.statusMsg = fmt.Sprintf("❌ Consumer group error: %v", event.Err)
+		}
+		cmds = append(cmds, waitForGroupEvent(m.groupConsumer))
+
+	case groupOffsetCommittedMsg:
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("❌ Commit failed: %v", msg.err)
+		} else {
+			m.statusMsg = fmt.Sprintf("✅ Committed offset %d for partition %d", msg.offset, msg.partition)
+		}
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		headerHeight := 12 // Header + search bar
+		headerHeight := 16 // Header, topic details incl. partitions/isolation/throughput, search bar
 		footerHeight := 3
 
 		tableHeight := msg.Height - headerHeight - footerHeight
@@ -338,6 +810,9 @@ func (m ConsumerModel) Update(msg tea.Msg) (ConsumerModel, tea.Cmd) {
 
 		// Adjust column widths based on screen width
 		m.adjustColumnWidths(msg.Width)
+		if m.question != nil {
+			m.question.resize(msg.Width, m.messageTable.Height())
+		}
 		m.ready = true
 		m.updateTable()
 	}
@@ -350,6 +825,77 @@ func (m ConsumerModel) Update(msg tea.Msg) (ConsumerModel, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// appendMessage buffers a received message and refreshes the table. It returns
+// false when the message was dropped (paused or an empty error signal).
+func (m *ConsumerModel) appendMessage(msg kafka.Message) bool {
+	if msg.Topic == "" || !m.consuming {
+		return false
+	}
+	m.messages = append(m.messages, msg)
+	payload := detectPayload(msg.Value)
+	m.payloads = append(m.payloads, payload)
+	m.fieldValues = append(m.fieldValues, extractJSONFields(payload, m.jsonColumns))
+	// Calculate message size
+	m.totalBytes += int64(len(msg.Key) + len(msg.Value))
+	m.skippedTotal += msg.Skipped
+	m.partitionReads[msg.Partition]++
+	m.rates.add(msg.Partition, int64(len(msg.Key)+len(msg.Value)), time.Now())
+	// Check if new message matches search
+	if m.searchTerm != "" && m.messageMatches(msg, m.searchTerm) {
+		m.searchResults = append(m.searchResults, len(m.messages)-1)
+		m.filteredIndices = append(m.filteredIndices, len(m.messages)-1)
+	}
+	if m.maxMessages > 0 && len(m.messages) > m.maxMessages {
+		m.dropOldest(len(m.messages) - m.maxMessages)
+	}
+	m.updateTable()
+	return true
+}
+
+// dropOldest removes the n oldest messages from the buffer and shifts the
+// search and filter indices so they keep pointing at the same messages
+func (m *ConsumerModel) dropOldest(n int) {
+	m.messages = m.messages[n:]
+	m.payloads = m.payloads[n:]
+	m.fieldValues = m.fieldValues[n:]
+	m.dropped += n
+	// Matches are in buffer order, so those dropped were before the current
+	// one, which keeps pointing at the same message while it is buffered
+	matches := len(m.searchResults)
+	m.searchResults = shiftIndices(m.searchResults, n)
+	m.filteredIndices = shiftIndices(m.filteredIndices, n)
+	m.currentMatch = max(m.currentMatch-(matches-len(m.searchResults)), 0)
+	if m.currentMatch >= len(m.searchResults) {
+		m.currentMatch = 0
+	}
+}
+
+// shiftIndices subtracts n from each index, discarding those that fall off the front
+func shiftIndices(indices []int, n int) []int {
+	shifted := indices[:0]
+	for _, idx := range indices {
+		if idx >= n {
+			shifted = append(shifted, idx-n)
+		}
+	}
+	return shifted
+}
+
+// selectedMessageIndex maps the table cursor back to an index in m.messages
+func (m *ConsumerModel) selectedMessageIndex() int {
+	cursor := m.messageTable.Cursor()
+	if m.showFiltered {
+		if cursor < 0 || cursor >= len(m.filteredIndices) {
+			return -1
+		}
+		return m.filteredIndices[cursor]
+	}
+	if cursor < 0 || cursor >= len(m.messages) {
+		return -1
+	}
+	return cursor
+}
+
 func (m *ConsumerModel) performSearch() {
 	m.searchResults = []int{}
 	m.filteredIndices = []int{}
@@ -367,10 +913,62 @@ func (m *ConsumerModel) performSearch() {
 }
 
 func (m *ConsumerModel) messageMatches(msg kafka.Message, searchTerm string) bool {
+	if m.searchQuery != nil {
+		return queryMatches(m.searchQuery, msg)
+	}
+	if strings.HasPrefix(strings.ToLower(searchTerm), headerFilterPrefix) {
+		return headersMatch(msg.Headers, searchTerm[len(headerFilterPrefix):])
+	}
+
 	searchLower := strings.ToLower(searchTerm)
-	return strings.Contains(strings.ToLower(msg.Key), searchLower) ||
+	if strings.Contains(strings.ToLower(msg.Key), searchLower) ||
 		strings.Contains(strings.ToLower(msg.Value), searchLower) ||
-		strings.Contains(strings.ToLower(msg.Topic), searchLower)
+		strings.Contains(strings.ToLower(msg.Topic), searchLower) {
+		return true
+	}
+	for k, v := range msg.Headers {
+		if strings.Contains(strings.ToLower(k), searchLower) || strings.Contains(strings.ToLower(v), searchLower) {
+			return true
+		}
+	}
+	return false
+}
+
+// headersMatch checks a "key" or "key=value" expression against message headers.
+// Keys are matched case-insensitively; values are matched as a substring.
+func headersMatch(headers map[string]string, expr string) bool {
+	key, value, hasValue := strings.Cut(strings.TrimSpace(expr), "=")
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return false
+	}
+	for k, v := range headers {
+		if !strings.EqualFold(k, key) {
+			continue
+		}
+		if !hasValue || strings.Contains(strings.ToLower(v), strings.ToLower(strings.TrimSpace(value))) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatHeaders renders headers as a compact, stable "k=v, k=v" list
+func formatHeaders(headers map[string]string, format BinaryFormat) string {
+	if len(headers) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%s", k, displayPayload(headers[k], format)))
+	}
+	return strings.Join(parts, ", ")
 }
 
 func (m *ConsumerModel) scrollToMessage(index int) {
@@ -405,18 +1003,26 @@ func (m *ConsumerModel) adjustColumnWidths(totalWidth int) {
 	offsetCol := 10
 	sizeCol := 8
 
-	// Remaining space for key and value
-	remainingWidth := totalWidth - numCol - timestampCol - partCol - offsetCol - sizeCol - 10 // padding
+	// Remaining space for key, value and headers
+	remainingWidth := totalWidth - numCol - timestampCol - partCol - offsetCol - sizeCol - 12 // padding
 
-	keyCol := remainingWidth / 4       // 25% for key
-	valueCol := remainingWidth * 3 / 4 // 75% for value
+	keyCol := remainingWidth / 5        // 20% for key
+	formatCol := remainingWidth / 10    // 10% for the detected format
+	valueCol := remainingWidth * 5 / 10 // 50% for value
+	headersCol := remainingWidth / 5    // 20% for headers
 
 	if keyCol < 10 {
 		keyCol = 10
 	}
+	if formatCol < 8 {
+		formatCol = 8
+	}
 	if valueCol < 20 {
 		valueCol = 20
 	}
+	if headersCol < 10 {
+		headersCol = 10
+	}
 
 	columns := []table.Column{
 		{Title: "#", Width: numCol},
@@ -424,9 +1030,21 @@ func (m *ConsumerModel) adjustColumnWidths(totalWidth int) {
 		{Title: "Part", Width: partCol},
 		{Title: "Offset", Width: offsetCol},
 		{Title: "Key", Width: keyCol},
-		{Title: "Value", Width: valueCol},
-		{Title: "Size", Width: sizeCol},
+		{Title: "Format", Width: formatCol},
 	}
+	if len(m.jsonColumns) == 0 {
+		columns = append(columns, table.Column{Title: "Value", Width: valueCol})
+	} else {
+		// The chosen fields share the value column's space
+		fieldCol := max(valueCol/len(m.jsonColumns), 8)
+		for _, path := range m.jsonColumns {
+			columns = append(columns, table.Column{Title: path, Width: fieldCol})
+		}
+	}
+	columns = append(columns,
+		table.Column{Title: "Headers", Width: headersCol},
+		table.Column{Title: "Size", Width: sizeCol},
+	)
 
 	m.messageTable.SetColumns(columns)
 }
@@ -435,7 +1053,7 @@ func (m *ConsumerModel) updateTable() {
 	m.tableRows = []table.Row{}
 	indices := []int{}
 
-	if m.showFiltered && len(m.filteredIndices) > 0 {
+	if m.showFiltered {
 		indices = append(indices, m.filteredIndices...)
 	} else {
 		for i := range m.messages {
@@ -459,36 +1077,255 @@ func (m *ConsumerModel) updateTable() {
 			}
 		}
 
-		row := m.formatMessageRow(msg, idx+1, isSearchResult)
+		row := m.formatMessageRow(msg, m.payloads[idx], m.fieldValues[idx], m.dropped+idx+1, isSearchResult)
 		m.tableRows = append(m.tableRows, row)
 	}
 
 	m.messageTable.SetRows(m.tableRows)
 }
 
-func (m *ConsumerModel) formatMessageRow(msg kafka.Message, num int, isSearchResult bool) table.Row {
+func (m *ConsumerModel) formatMessageRow(msg kafka.Message, payload decodedPayload, fields []string, num int, isSearchResult bool) table.Row {
 	// Format timestamp
 	timestamp := msg.Timestamp.Format("2006-01-02 15:04:05")
 
 	// Truncate and clean value for table display
-	value := strings.ReplaceAll(msg.Value, "\n", " ")
+	value := strings.ReplaceAll(displayPayload(payload.Body, m.binaryFormat), "\n", " ")
 	value = strings.ReplaceAll(value, "\t", " ")
 
 	// Calculate message size
 	msgSize := len(msg.Key) + len(msg.Value)
 	sizeStr := formatBytes(int64(msgSize))
 
-	return table.Row{
+	row := table.Row{
 		fmt.Sprintf("%d", num),
 		timestamp,
 		fmt.Sprintf("%d", msg.Partition),
-		fmt.Sprintf("%d", msg.Offset),
-		msg.Key,
-		value,
-		sizeStr,
+		formatOffset(msg),
+		displayPayload(msg.Key, m.binaryFormat),
+		payload.Label(),
 	}
+	if len(m.jsonColumns) == 0 {
+		row = append(row, value)
+	} else {
+		for _, field := range fields {
+			row = append(row, strings.ReplaceAll(field, "\n", " "))
+		}
+	}
+	return append(row, formatHeaders(msg.Headers, m.binaryFormat), sizeStr)
+}
+
+// setJSONColumns replaces the value column with the given JSON fields, or
+// restores it when paths is empty, and extracts them from every message
+func (m *ConsumerModel) setJSONColumns(paths []string) {
+	m.jsonColumns = paths
+	m.fieldValues = make([][]string, len(m.messages))
+	for i, p := range m.payloads {
+		m.fieldValues[i] = extractJSONFields(p, paths)
+	}
+	// Rows must match the column count, so drop them before the columns change
+	m.messageTable.SetRows(nil)
+	m.adjustColumnWidths(m.width)
+	m.updateTable()
+}
+
+func (m *ConsumerModel) resizeDetailViewport() {
+	width := m.width - 4
+	if width < 40 {
+		width = 40
+	}
+	height := m.height - 6
+	if height < 5 {
+		height = 5
+	}
+	m.detailViewport.Width = width
+	m.detailViewport.Height = height
 }
 
+// renderMessageDetail renders the full record: metadata, key, value and headers
+func (m ConsumerModel) renderMessageDetail(msg kafka.Message, payload decodedPayload) string {
+	var sb strings.Builder
+
+	labelStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("86"))
+
+	sectionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("213"))
+
+	width := m.detailViewport.Width - 2
+
+	sb.WriteString(labelStyle.Render("Topic:     ") + msg.Topic + "\n")
+	sb.WriteString(labelStyle.Render("Partition: ") + fmt.Sprintf("%d", msg.Partition) + "\n")
+	sb.WriteString(labelStyle.Render("Offset:    ") + fmt.Sprintf("%d", msg.Offset) + "\n")
+	if msg.Skipped > 0 {
+		sb.WriteString(labelStyle.Render("Skipped:   ") + fmt.Sprintf("%d offset(s) before this message were not delivered (transaction markers, aborted or compacted records)", msg.Skipped) + "\n")
+	}
+	sb.WriteString(labelStyle.Render("Timestamp: ") + msg.Timestamp.Format("2006-01-02 15:04:05.000") + "\n")
+	sb.WriteString(labelStyle.Render("Size:      ") + formatBytes(int64(len(msg.Key)+len(msg.Value))) + "\n\n")
+
+	sb.WriteString(sectionStyle.Render("Key") + "\n")
+	if msg.Key == "" {
+		sb.WriteString("(none)\n\n")
+	} else if isBinary(msg.Key) {
+		sb.WriteString(sectionStyle.Render(fmt.Sprintf("(binary, %s)", m.binaryFormat)) + "\n")
+		sb.WriteString(renderBinaryBlock(msg.Key, m.binaryFormat, width) + "\n\n")
+	} else {
+		sb.WriteString(wrapText(msg.Key, width) + "\n\n")
+	}
+
+	sb.WriteString(sectionStyle.Render("Value") + "\n")
+	switch {
+	case payload.Kind == PayloadEmpty:
+		sb.WriteString("(none)\n\n")
+	case payload.Textual():
+		body := payload.Body
+		if payload.Kind != PayloadText {
+			body = indentJSON(body)
+		}
+		if payload.Kind != PayloadText || payload.Gzip {
+			sb.WriteString(sectionStyle.Render(fmt.Sprintf("(%s)", payload.Label())) + "\n")
+		}
+		sb.WriteString(wrapText(body, width) + "\n\n")
+	default:
+		sb.WriteString(sectionStyle.Render(fmt.Sprintf("(%s, %s)", payload.Label(), m.binaryFormat)) + "\n")
+		sb.WriteString(renderBinaryBlock(payload.Body, m.binaryFormat, width) + "\n\n")
+	}
+
+	sb.WriteString(sectionStyle.Render(fmt.Sprintf("Headers (%d)", len(msg.Headers))) + "\n")
+	if len(msg.Headers) == 0 {
+		sb.WriteString("(none)\n")
+	} else {
+		keys := make([]string, 0, len(msg.Headers))
+		for k := range msg.Headers {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			sb.WriteString(fmt.Sprintf("  %s: %s\n", labelStyle.Render(k), copyablePayload(msg.Headers[k], m.binaryFormat)))
+		}
+	}
+
+	return sb.String()
+}
+
+func (m ConsumerModel) viewDetail() string {
+	var sb strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Padding(0, 1)
+
+	sb.WriteString(headerStyle.Render(fmt.Sprintf("📄 Message #%d", m.detailIndex+1)))
+	sb.WriteString("\n\n")
+	sb.WriteString(m.detailViewport.View())
+	sb.WriteString("\n")
+
+	if m.statusMsg != "" {
+		sb.WriteString(m.statusMsg)
+		sb.WriteString("\n")
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Italic(true)
+	sb.WriteString(helpStyle.Render("↑/↓: Scroll | v: Copy value | K: Copy key | J: Copy as JSON | x: Hex/Base64 | Esc/Enter: Back to messages"))
+
+	return sb.String()
+}
+
+// focusOffsetInputs focuses the partition filter or the text input belonging
+// to the selected offset option
+func (m *ConsumerModel) focusOffsetInputs() tea.Cmd {
+	m.offsetInput.Blur()
+	m.lastNInput.Blur()
+	m.startTimeInput.Blur()
+	m.partitionInput.Blur()
+	m.endOffsetInput.Blur()
+	m.endTimeInput.Blur()
+	m.groupInput.Blur()
+	switch m.dialogFocus {
+	case FocusPartitions:
+		m.partitionInput.Focus()
+		return textinput.Blink
+	case FocusEndOffset:
+		m.endOffsetInput.Focus()
+		return textinput.Blink
+	case FocusEndTime:
+		m.endTimeInput.Focus()
+		return textinput.Blink
+	case FocusGroup:
+		m.groupInput.Focus()
+		return textinput.Blink
+	}
+	switch m.offsetOption {
+	case OffsetSpecific:
+		m.offsetInput.Focus()
+		return textinput.Blink
+	case OffsetLastN:
+		m.lastNInput.Focus()
+		return textinput.Blink
+	case OffsetTimestamp:
+		m.startTimeInput.Focus()
+		return textinput.Blink
+	}
+	return nil
+}
+
+// partitionCount returns the number of partitions of the topic, or 0 if unknown
+func (m ConsumerModel) partitionCount() int {
+	if m.topicInfo == nil {
+		return 0
+	}
+	return m.topicInfo.Partitions
+}
+
+// parsePartitionList parses a partition selection such as "0,2,5-7". An empty
+// string selects all partitions and returns nil. When count is known, each
+// partition is checked against the topic's partition range.
+func parsePartitionList(input string, count int) ([]int32, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, nil
+	}
+
+	seen := make(map[int32]bool)
+	var partitions []int32
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		from, to := part, part
+		if i := strings.Index(part, "-"); i > 0 {
+			from, to = part[:i], part[i+1:]
+		}
+		start, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil || start < 0 {
+			return nil, fmt.Errorf("invalid partition: %s", part)
+		}
+		end, err := strconv.Atoi(strings.TrimSpace(to))
+		if err != nil || end < start {
+			return nil, fmt.Errorf("invalid partition range: %s", part)
+		}
+		if count > 0 && end >= count {
+			return nil, fmt.Errorf("partition %d does not exist (topic has %d partitions)", end, count)
+		}
+
+		for p := start; p <= end; p++ {
+			if !seen[int32(p)] {
+				seen[int32(p)] = true
+				partitions = append(partitions, int32(p))
+			}
+		}
+	}
+
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
+	return partitions, nil
+}
 
 func (m ConsumerModel) viewOffsetDialog() string {
 	var sb strings.Builder
@@ -531,6 +1368,8 @@ func (m ConsumerModel) viewOffsetDialog() string {
 		{OffsetOldest, "Oldest", "Start from the beginning of the topic"},
 		{OffsetNewest, "Latest", "Start from new messages only"},
 		{OffsetSpecific, "Specific Offset", "Start from a specific offset number"},
+		{OffsetLastN, "Last N Messages", "Start N messages before the end of each partition"},
+		{OffsetTimestamp, "From Timestamp", "Start at the first message at or after a time"},
 	}
 
 	for _, opt := range options {
@@ -545,16 +1384,85 @@ func (m ConsumerModel) viewOffsetDialog() string {
 
 		// Show input field if this option is selected
 		if m.offsetOption == opt.option {
-			if opt.option == OffsetSpecific {
+			switch opt.option {
+			case OffsetSpecific:
 				sb.WriteString("    ")
 				sb.WriteString(m.offsetInput.View())
 				sb.WriteString("\n")
+			case OffsetLastN:
+				sb.WriteString("    ")
+				sb.WriteString(m.lastNInput.View())
+				sb.WriteString("\n")
+			case OffsetTimestamp:
+				sb.WriteString("    ")
+				sb.WriteString(m.startTimeInput.View())
+				sb.WriteString("\n")
 			}
 		}
 	}
 
 	sb.WriteString("\n")
 
+	// Partition filter
+	partitionLabel := "Partitions"
+	if m.topicInfo != nil {
+		partitionLabel = fmt.Sprintf("Partitions (0-%d)", m.topicInfo.Partitions-1)
+	}
+	if m.dialogFocus == FocusPartitions {
+		sb.WriteString(selectedStyle.Render("▶ " + partitionLabel))
+	} else {
+		sb.WriteString(labelStyle.Render("  " + partitionLabel))
+	}
+	sb.WriteString("\n    ")
+	sb.WriteString(m.partitionInput.View())
+	sb.WriteString("\n\n")
+
+	// Optional end boundary
+	endFields := []struct {
+		focus DialogFocus
+		label string
+		input textinput.Model
+	}{
+		{FocusEndOffset, "End Offset", m.endOffsetInput},
+		{FocusEndTime, "End Time  ", m.endTimeInput},
+	}
+	for _, field := range endFields {
+		if m.dialogFocus == field.focus {
+			sb.WriteString(selectedStyle.Render("▶ " + field.label))
+		} else {
+			sb.WriteString(labelStyle.Render("  " + field.label))
+		}
+		sb.WriteString(" " + field.input.View() + "\n")
+	}
+	sb.WriteString("    Stop each partition there and report the records read\n\n")
+
+	// Consumer group id
+	if m.dialogFocus == FocusGroup {
+		sb.WriteString(selectedStyle.Render("▶ Consumer Group"))
+	} else {
+		sb.WriteString(labelStyle.Render("  Consumer Group"))
+	}
+	sb.WriteString(" - Join a group and commit offsets manually\n    ")
+	sb.WriteString(m.groupInput.View())
+	sb.WriteString("\n\n")
+
+	// Isolation level
+	if m.dialogFocus == FocusIsolation {
+		sb.WriteString(selectedStyle.Render("▶ Isolation Level"))
+	} else {
+		sb.WriteString(labelStyle.Render("  Isolation Level"))
+	}
+	sb.WriteString(" - ←/→ to toggle\n    ")
+	for _, level := range []sarama.IsolationLevel{sarama.ReadUncommitted, sarama.ReadCommitted} {
+		if m.isolation == level {
+			sb.WriteString(selectedStyle.Render("(•) " + isolationLevelName(level)))
+		} else {
+			sb.WriteString(labelStyle.Render("( ) " + isolationLevelName(level)))
+		}
+		sb.WriteString("  ")
+	}
+	sb.WriteString("\n\n")
+
 	// Error display
 	if m.err != nil {
 		errorStyle := lipgloss.NewStyle().
@@ -567,7 +1475,7 @@ func (m ConsumerModel) viewOffsetDialog() string {
 		Foreground(lipgloss.Color("241")).
 		Italic(true)
 
-	helpText := "↑/↓ or Tab: Navigate | Enter: Start | Esc: Cancel"
+	helpText := "↑/↓: Start position | Tab: Next field | Enter: Start | Esc: Cancel"
 	sb.WriteString(helpStyle.Render(helpText))
 
 	// Center the dialog
@@ -579,6 +1487,9 @@ func (m ConsumerModel) View() string {
 	if m.mode == ModeOffsetDialog {
 		return m.viewOffsetDialog()
 	}
+	if m.mode == ModeDetail {
+		return m.viewDetail()
+	}
 
 	var sb strings.Builder
 
@@ -631,20 +1542,73 @@ func (m ConsumerModel) View() string {
 	}
 
 	tableContent.WriteString(labelStyle.Render("Messages Received:"))
-	tableContent.WriteString(valueStyle.Render(fmt.Sprintf(" %d", len(m.messages))) + "\n")
+	receivedText := fmt.Sprintf(" %d", len(m.messages)+m.dropped)
+	if m.dropped > 0 {
+		receivedText += fmt.Sprintf(" (showing last %d, %d dropped)", len(m.messages), m.dropped)
+	}
+	tableContent.WriteString(valueStyle.Render(receivedText) + "\n")
 
 	tableContent.WriteString(labelStyle.Render("Total Bytes:      "))
 	tableContent.WriteString(valueStyle.Render(formatBytes(m.totalBytes)) + "\n")
 
+	msgRate, byteRate, partitionRates := m.rates.rates(time.Now())
+	tableContent.WriteString(labelStyle.Render("Throughput:       "))
+	tableContent.WriteString(valueStyle.Render(fmt.Sprintf("%.1f msg/s • %s/s", msgRate, formatBytes(int64(byteRate)))) + "\n")
+	if len(partitionRates) > 1 {
+		parts := make([]string, 0, len(partitionRates))
+		for _, pr := range partitionRates {
+			parts = append(parts, fmt.Sprintf("P%d %.1f/s %s/s", pr.partition, pr.msgsPerSec, formatBytes(int64(pr.bytesPerSec))))
+		}
+		tableContent.WriteString(labelStyle.Render("  Per Partition:  "))
+		tableContent.WriteString(valueStyle.Render(truncateString(strings.Join(parts, " | "), m.width-30)) + "\n")
+	}
+
 	tableContent.WriteString(labelStyle.Render("Start Offset:     "))
 	offsetText := "Latest"
-	if m.startOffset == sarama.OffsetOldest {
+	if m.lastN > 0 {
+		offsetText = fmt.Sprintf("Last %d per partition", m.lastN)
+	} else if m.startOffset == sarama.OffsetOldest {
 		offsetText = "Oldest"
 	} else if m.startOffset >= 0 {
 		offsetText = fmt.Sprintf("%d", m.startOffset)
 	}
 	tableContent.WriteString(valueStyle.Render(offsetText) + "\n")
 
+	if m.groupConsumer != nil {
+		tableContent.WriteString(labelStyle.Render("Consumer Group:   "))
+		groupText := m.groupConsumer.GroupID()
+		if m.groupEvent != nil && m.groupEvent.MemberID != "" {
+			groupText += fmt.Sprintf(" (generation %d, %d rebalances)", m.groupEvent.GenerationID, m.rebalances)
+		}
+		tableContent.WriteString(valueStyle.Render(groupText) + "\n")
+	}
+
+	tableContent.WriteString(labelStyle.Render("Partitions:       "))
+	partitionText := "All"
+	if m.groupConsumer != nil {
+		partitionText = "Waiting for assignment"
+	}
+	if len(m.partitions) > 0 {
+		parts := make([]string, len(m.partitions))
+		for i, p := range m.partitions {
+			parts[i] = strconv.Itoa(int(p))
+		}
+		partitionText = strings.Join(parts, ",")
+	}
+	tableContent.WriteString(valueStyle.Render(partitionText) + "\n")
+
+	tableContent.WriteString(labelStyle.Render("Isolation:        "))
+	isolationText := isolationLevelName(m.isolation)
+	if m.skippedTotal > 0 {
+		isolationText += fmt.Sprintf(" (%d offsets skipped: markers/aborted)", m.skippedTotal)
+	}
+	tableContent.WriteString(valueStyle.Render(isolationText) + "\n")
+
+	if m.bounded {
+		tableContent.WriteString(labelStyle.Render("Read per Part.:   "))
+		tableContent.WriteString(valueStyle.Render(formatPartitionReads(m.partitionReads)) + "\n")
+	}
+
 	if m.searchTerm != "" {
 		tableContent.WriteString(labelStyle.Render("Search Results:   "))
 		tableContent.WriteString(valueStyle.Render(fmt.Sprintf("%d matches", len(m.searchResults))) + "\n")
@@ -653,6 +1617,8 @@ func (m ConsumerModel) View() string {
 	tableContent.WriteString(labelStyle.Render("Status:           "))
 	if m.err != nil {
 		tableContent.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("❌ Error"))
+	} else if m.finished {
+		tableContent.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("46")).Render("🏁 Finished"))
 	} else if !m.consuming {
 		tableContent.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("220")).Render("⏸️  Paused"))
 	} else if len(m.messages) == 0 {
@@ -673,7 +1639,11 @@ func (m ConsumerModel) View() string {
 	}
 
 	// Message table
-	if len(m.messages) == 0 && !m.consuming {
+	if m.mode == ModeAsk {
+		sb.WriteString(m.question.View(m.questionMessageCount()))
+	} else if m.columnPicker != nil {
+		sb.WriteString(m.columnPicker.View())
+	} else if len(m.messages) == 0 && !m.consuming {
 		// Show a placeholder when not consuming
 		emptyStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("241")).
@@ -691,18 +1661,82 @@ func (m ConsumerModel) View() string {
 		Foreground(lipgloss.Color("241")).
 		Italic(true)
 
-	footer := "↑/↓: Navigate | /: Search | n/N: Next/Prev | f: Filter | p: Pause | c: Clear | q: Back"
+	footer := "↑/↓: Navigate | Enter: Details | /: Search | n/N: Next/Prev | f: Filter | F: JSON columns | a: Ask AI | p: Pause | c: Clear | q: Back"
+	if m.mode == ModeAsk {
+		footer = "Enter: Ask | ↑/↓: Scroll answers | Esc: Back to messages"
+		if m.question.asking {
+			footer = "🔄 Waiting for the answer... Esc: Cancel"
+		}
+	} else if m.groupConsumer != nil {
+		footer = "C: Commit | " + footer
+	}
 	if m.searchTerm != "" && len(m.searchResults) > 0 {
 		footer = fmt.Sprintf("[Match %d/%d] ", m.currentMatch+1, len(m.searchResults)) + footer
 	}
 	if m.showFiltered {
-		footer = "[FILTERED] " + footer
+		if len(m.filteredIndices) == 0 {
+			footer = "[FILTERED: no matches] " + footer
+		} else {
+			footer = "[FILTERED] " + footer
+		}
 	}
 	sb.WriteString(helpStyle.Render(footer))
+	if m.statusMsg != "" {
+		sb.WriteString("\n" + m.statusMsg)
+	}
 
 	return sb.String()
 }
 
+// isolationLevelName returns the Kafka config name of an isolation level
+func isolationLevelName(level sarama.IsolationLevel) string {
+	if level == sarama.ReadCommitted {
+		return "read_committed"
+	}
+	return "read_uncommitted"
+}
+
+// formatPartitionReads lists the records read from each partition in
+// partition order, e.g. "P0 12, P1 30 (42 total)"
+func formatPartitionReads(reads map[int32]int64) string {
+	if len(reads) == 0 {
+		return "none"
+	}
+	partitions := make([]int32, 0, len(reads))
+	var total int64
+	for p, n := range reads {
+		partitions = append(partitions, p)
+		total += n
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
+	parts := make([]string, len(partitions))
+	for i, p := range partitions {
+		parts[i] = fmt.Sprintf("P%d %d", p, reads[p])
+	}
+	return fmt.Sprintf("%s (%d total)", strings.Join(parts, ", "), total)
+}
+
+// formatOffset renders the offset column, flagging messages that follow
+// undelivered offsets such as transaction markers with a "⋯" prefix
+func formatOffset(msg kafka.Message) string {
+	if msg.Skipped > 0 {
+		return fmt.Sprintf("⋯%d", msg.Offset)
+	}
+	return fmt.Sprintf("%d", msg.Offset)
+}
+
+// truncateString shortens s to at most width runes, marking the cut with "…"
+func truncateString(s string, width int) string {
+	runes := []rune(s)
+	if width <= 0 || len(runes) <= width {
+		return s
+	}
+	if width == 1 {
+		return "…"
+	}
+	return string(runes[:width-1]) + "…"
+}
+
 // Helper function to format bytes
 func formatBytes(bytes int64) string {
 	const unit = 1024
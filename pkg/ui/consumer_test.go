@@ -0,0 +1,286 @@
+package ui
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+func TestShiftIndices(t *testing.T) {
+	tests := []struct {
+		name    string
+		indices []int
+		n       int
+		want    []int
+	}{
+		{"nothing dropped", []int{0, 3}, 0, []int{0, 3}},
+		{"all shifted", []int{2, 5}, 2, []int{0, 3}},
+		{"first falls off the front", []int{0, 1, 4}, 1, []int{0, 3}},
+		{"index equal to n becomes first", []int{3}, 3, []int{0}},
+		{"index just below n is dropped", []int{2}, 3, []int{}},
+		{"everything falls off", []int{0, 1}, 5, []int{}},
+		{"empty", nil, 2, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := shiftIndices(append([]int(nil), tt.indices...), tt.n)
+			if len(got) != len(tt.want) || (len(got) > 0 && !reflect.DeepEqual(got, tt.want)) {
+				t.Errorf("shiftIndices(%v, %d) = %v, want %v", tt.indices, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDropOldest(t *testing.T) {
+	m := ConsumerModel{}
+	for i := 0; i < 5; i++ {
+		m.messages = append(m.messages, kafka.Message{Offset: int64(i)})
+		m.payloads = append(m.payloads, decodedPayload{})
+		m.fieldValues = append(m.fieldValues, nil)
+	}
+	m.searchResults = []int{1, 2, 4}
+	m.filteredIndices = []int{1, 2, 4}
+	m.currentMatch = 2
+
+	m.dropOldest(2)
+
+	if len(m.messages) != 3 || m.messages[0].Offset != 2 || len(m.payloads) != 3 || len(m.fieldValues) != 3 {
+		t.Fatalf("buffer = %+v, want offsets 2-4", m.messages)
+	}
+	if m.dropped != 2 {
+		t.Errorf("dropped = %d, want 2", m.dropped)
+	}
+	// Message 1 fell off; offsets 2 and 4 are now at 0 and 2
+	if !reflect.DeepEqual(m.searchResults, []int{0, 2}) || !reflect.DeepEqual(m.filteredIndices, []int{0, 2}) {
+		t.Errorf("indices = %v / %v, want [0 2]", m.searchResults, m.filteredIndices)
+	}
+	for _, idx := range m.filteredIndices {
+		if offset := m.messages[idx].Offset; offset != 2 && offset != 4 {
+			t.Errorf("index %d points at offset %d", idx, offset)
+		}
+	}
+	// The current match still points at offset 4
+	if m.currentMatch != 1 {
+		t.Errorf("currentMatch = %d, want 1", m.currentMatch)
+	}
+
+	// Dropping the current match moves it to the first one left
+	m.currentMatch = 0
+	m.dropOldest(1)
+	if m.currentMatch != 0 || !reflect.DeepEqual(m.searchResults, []int{1}) {
+		t.Errorf("after dropping the current match: currentMatch = %d, results %v", m.currentMatch, m.searchResults)
+	}
+}
+
+func TestParsePartitionList(t *testing.T) {
+	tests := []struct {
+		input   string
+		count   int
+		want    []int32
+		wantErr bool
+	}{
+		{"", 6, nil, false},
+		{"   ", 6, nil, false},
+		{"3", 6, []int32{3}, false},
+		{"0-3", 6, []int32{0, 1, 2, 3}, false},
+		{"5,0,2", 6, []int32{0, 2, 5}, false},
+		{"0-2,1,2-3", 6, []int32{0, 1, 2, 3}, false},
+		{" 0 , 2 - 4 ,", 6, []int32{0, 2, 3, 4}, false},
+		{"4-4", 6, []int32{4}, false},
+		{"3-1", 6, nil, true},
+		{"-1", 6, nil, true},
+		{"a", 6, nil, true},
+		{"1-x", 6, nil, true},
+		{"6", 6, nil, true},
+		{"4-9", 6, nil, true},
+		{"40", 0, []int32{40}, false}, // Partition count unknown
+	}
+	for _, tt := range tests {
+		got, err := parsePartitionList(tt.input, tt.count)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parsePartitionList(%q, %d) error = %v, wantErr %v", tt.input, tt.count, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parsePartitionList(%q, %d) = %v, want %v", tt.input, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestHeadersMatch(t *testing.T) {
+	headers := map[string]string{"Trace-Id": "abc-123", "source": "billing"}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"trace-id", true},
+		{"TRACE-ID=abc", true},
+		{" source = BILL ", true},
+		{"source=orders", false},
+		{"missing", false},
+		{"=abc", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := headersMatch(headers, tt.expr); got != tt.want {
+			t.Errorf("headersMatch(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestMessageMatchesHeaders(t *testing.T) {
+	m := &ConsumerModel{}
+	msg := kafka.Message{Topic: "orders", Key: "k1", Value: "{}", Headers: map[string]string{"tenant": "acme"}}
+	tests := []struct {
+		term string
+		want bool
+	}{
+		{"header:tenant=acme", true},
+		{"HEADER:tenant", true},
+		{"header:tenant=other", false},
+		// Plain searches look at header keys and values too
+		{"acme", true},
+		{"TENANT", true},
+		{"nowhere", false},
+	}
+	for _, tt := range tests {
+		if got := m.messageMatches(msg, tt.term); got != tt.want {
+			t.Errorf("messageMatches(%q) = %v, want %v", tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestFormatHeaders(t *testing.T) {
+	if got := formatHeaders(nil, BinaryHex); got != "" {
+		t.Errorf("formatHeaders(nil) = %q, want empty", got)
+	}
+	got := formatHeaders(map[string]string{"b": "2", "a": "1"}, BinaryHex)
+	if got != "a=1, b=2" {
+		t.Errorf("formatHeaders = %q, want sorted pairs", got)
+	}
+}
+
+// newTestConsumer returns a consuming model with the given messages buffered
+func newTestConsumer(messages ...kafka.Message) ConsumerModel {
+	m := ConsumerModel{
+		ctx:            context.Background(),
+		mode:           ModeNormal,
+		consuming:      true,
+		messageTable:   table.New(),
+		partitionReads: make(map[int32]int64),
+		detailViewport: viewport.New(80, 20),
+	}
+	m.adjustColumnWidths(120)
+	for _, msg := range messages {
+		m.appendMessage(msg)
+	}
+	return m
+}
+
+func TestAppendMessage(t *testing.T) {
+	m := newTestConsumer()
+	if !m.appendMessage(kafka.Message{Topic: "orders", Partition: 1, Key: "k", Value: "v"}) {
+		t.Fatal("message was dropped while consuming")
+	}
+	if m.appendMessage(kafka.Message{}) {
+		t.Error("empty error signal was buffered")
+	}
+	m.consuming = false
+	if m.appendMessage(kafka.Message{Topic: "orders"}) {
+		t.Error("message was buffered while paused")
+	}
+	if len(m.messages) != 1 || m.partitionReads[1] != 1 || m.totalBytes != 2 {
+		t.Errorf("messages = %d, reads = %v, bytes = %d", len(m.messages), m.partitionReads, m.totalBytes)
+	}
+}
+
+func TestClearResetsCounts(t *testing.T) {
+	m := newTestConsumer(
+		kafka.Message{Topic: "orders", Partition: 0, Value: "first", Skipped: 2},
+		kafka.Message{Topic: "orders", Partition: 1, Value: "second"},
+	)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if len(m.messages) != 0 || len(m.partitionReads) != 0 || m.skippedTotal != 0 || m.totalBytes != 0 {
+		t.Fatalf("messages = %d, reads = %v, skipped = %d, bytes = %d after clear",
+			len(m.messages), m.partitionReads, m.skippedTotal, m.totalBytes)
+	}
+	m.appendMessage(kafka.Message{Topic: "orders", Partition: 1, Value: "third"})
+	if got := formatPartitionReads(m.partitionReads); got != formatPartitionReads(map[int32]int64{1: 1}) {
+		t.Errorf("reads after clear = %s, want only the new message", got)
+	}
+}
+
+func TestDetailViewKeys(t *testing.T) {
+	m := newTestConsumer(
+		kafka.Message{Topic: "orders", Offset: 0, Value: "first"},
+		kafka.Message{Topic: "orders", Offset: 1, Value: "second"},
+	)
+	m.messageTable.SetCursor(1)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != ModeDetail {
+		t.Fatalf("mode = %v after enter, want the detail view", m.mode)
+	}
+	if m.detailIndex != 1 || m.detailMessage.Value != "second" {
+		t.Errorf("detail shows message %d %q, want the selected one", m.detailIndex, m.detailMessage.Value)
+	}
+
+	// Messages keep buffering behind the detail view, which keeps its copy
+	m.maxMessages = 2
+	m, _ = m.Update(messageReceivedMsg{message: kafka.Message{Topic: "orders", Offset: 2, Value: "third"}})
+	if m.mode != ModeDetail || len(m.messages) != 2 || m.dropped != 1 {
+		t.Errorf("mode = %v, buffered = %d, dropped = %d", m.mode, len(m.messages), m.dropped)
+	}
+	if m.detailMessage.Value != "second" {
+		t.Errorf("detail message changed to %q", m.detailMessage.Value)
+	}
+
+	for _, key := range []tea.KeyMsg{
+		{Type: tea.KeyEsc},
+		{Type: tea.KeyRunes, Runes: []rune("q")},
+		{Type: tea.KeyEnter},
+	} {
+		m.mode = ModeDetail
+		m.statusMsg = "✅ Copied value to clipboard"
+		m, _ = m.Update(key)
+		if m.mode != ModeNormal || m.statusMsg != "" {
+			t.Errorf("%s: mode = %v, status = %q, want back to the table", key, m.mode, m.statusMsg)
+		}
+	}
+}
+
+func TestFilterWithoutMatches(t *testing.T) {
+	m := newTestConsumer(
+		kafka.Message{Topic: "orders", Value: `{"status":"OK"}`},
+		kafka.Message{Topic: "orders", Value: `{"status":"OK"}`},
+	)
+	m.searchInput = textinput.New()
+	m.searchInput.SetValue(`jq:.status == "FAILED"`)
+	m.mode = ModeSearch
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.showFiltered || len(m.filteredIndices) != 0 {
+		t.Fatalf("showFiltered = %v, matches = %v", m.showFiltered, m.filteredIndices)
+	}
+	// The filter stays on with nothing to show rather than showing everything
+	if rows := len(m.messageTable.Rows()); rows != 0 {
+		t.Errorf("table shows %d rows, want none", rows)
+	}
+	if m.statusMsg == "" || !strings.Contains(m.View(), "no matches") {
+		t.Errorf("status = %q, want no matches reported", m.statusMsg)
+	}
+	if idx := m.selectedMessageIndex(); idx != -1 {
+		t.Errorf("selected message %d, want none", idx)
+	}
+	if n := m.questionMessageCount(); n != 0 {
+		t.Errorf("a question would be asked about %d messages, want none", n)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/digitalis-io/kconduit/pkg/ai"
 	"github.com/digitalis-io/kconduit/pkg/kafka"
 	"github.com/digitalis-io/kconduit/pkg/logger"
 	"github.com/charmbracelet/bubbles/spinner"
@@ -31,6 +32,7 @@ type CreateACLHuhModel struct {
 	operations     []string
 	permissionType string
 	confirm        bool
+	proposed       bool // Prefilled from an AI assistant request
 }
 
 var (
@@ -93,6 +95,23 @@ func NewCreateACLHuhModel(client *kafka.Client) *CreateACLHuhModel {
 	return m
 }
 
+// newProposedACLModel opens the form prefilled with ACLs the AI assistant
+// proposed, so they are reviewed and confirmed like manual ones. They are
+// still audited as the assistant's.
+func newProposedACLModel(client *kafka.Client, req ai.ACLRequest) *CreateACLHuhModel {
+	m := NewCreateACLHuhModel(client.WithAuditSource("ai"))
+	m.principal = req.Principal
+	m.host = req.Host
+	m.resourceType = req.ResourceType
+	m.resourceName = req.ResourceName
+	m.patternType = req.PatternType
+	m.operations = req.Operations
+	m.permissionType = req.PermissionType
+	m.proposed = true
+	m.buildForm()
+	return m
+}
+
 func (m *CreateACLHuhModel) buildForm() {
 	theme := huh.ThemeCharm()
 	theme.Focused.Title = theme.Focused.Title.Foreground(lipgloss.Color("205"))
@@ -177,6 +196,11 @@ func (m *CreateACLHuhModel) buildForm() {
 
 // Validation methods
 func (m *CreateACLHuhModel) validatePrincipal(s string) error {
+	return validatePrincipal(s)
+}
+
+// validatePrincipal checks for a User: or Group: principal with a name
+func validatePrincipal(s string) error {
 	if s == "" {
 		return fmt.Errorf("principal cannot be empty")
 	}
@@ -320,12 +344,12 @@ func (m *CreateACLHuhModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.err = msg.err
 			m.success = false
 			// Don't rebuild form, just return to preserve state
-			return m, nil
+			return m, reportError("create acl", msg.err)
 		}
 		m.success = true
 		log.Info("ACL(s) created successfully, returning to ACLs tab")
 		return m, tea.Batch(
-			tea.Println("✅ ACL(s) created successfully!"),
+			showToast(toastSuccess, "ACL(s) created"),
 			func() tea.Msg { return ViewChangedMsg{View: ACLsTab} },
 		)
 
@@ -419,6 +443,9 @@ func (m *CreateACLHuhModel) View() string {
 		Padding(0, 2)
 
 	title := titleStyle.Render("🔐 Create Access Control List")
+	if m.proposed {
+		title = titleStyle.Render("🔐 Create Access Control List (proposed by the AI assistant)")
+	}
 
 	// Error display
 	var errorView string
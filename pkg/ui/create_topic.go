@@ -13,10 +13,10 @@ import (
 
 type CreateTopicModel struct {
 	client     *kafka.Client
+	policy     kafka.TopicPolicy
 	inputs     []textinput.Model
 	focusIndex int
 	err        error
-	successMsg string
 	width      int
 	height     int
 }
@@ -41,8 +41,11 @@ var (
 func NewCreateTopicModel(client *kafka.Client) CreateTopicModel {
 	m := CreateTopicModel{
 		client: client,
+		policy: client.TopicPolicy(),
 		inputs: make([]textinput.Model, 3),
 	}
+	defaultPartitions := strconv.Itoa(int(m.policy.DefaultPartitions()))
+	defaultReplication := strconv.Itoa(int(m.policy.DefaultReplicationFactor()))
 
 	var t textinput.Model
 	for i := range m.inputs {
@@ -59,12 +62,12 @@ func NewCreateTopicModel(client *kafka.Client) CreateTopicModel {
 			t.TextStyle = focusedStyle
 			t.CharLimit = 255
 		case partitionsIdx:
-			t.Prompt = "Number of partitions (default: 1): "
-			t.Placeholder = "1"
+			t.Prompt = "Number of partitions (default: " + defaultPartitions + "): "
+			t.Placeholder = defaultPartitions
 			t.CharLimit = 5
 		case replicationIdx:
-			t.Prompt = "Replication factor (default: 1): "
-			t.Placeholder = "1"
+			t.Prompt = "Replication factor (default: " + defaultReplication + "): "
+			t.Placeholder = defaultReplication
 			t.CharLimit = 3
 		}
 
@@ -144,17 +147,16 @@ func (m CreateTopicModel) Update(msg tea.Msg) (CreateTopicModel, tea.Cmd) {
 	case topicCreatedMsg:
 		if msg.err != nil {
 			m.err = msg.err
-			m.successMsg = ""
-		} else {
-			m.err = nil
-			m.successMsg = fmt.Sprintf("✓ Topic '%s' created successfully!", msg.name)
-			// Clear inputs
-			for i := range m.inputs {
-				m.inputs[i].SetValue("")
-			}
-			m.focusIndex = 0
-			return m.updateFocus()
+			return m, reportError("create topic", msg.err)
+		}
+		m.err = nil
+		// Clear inputs, ready for the next topic
+		for i := range m.inputs {
+			m.inputs[i].SetValue("")
 		}
+		m.focusIndex = 0
+		model, cmd := m.updateFocus()
+		return model, tea.Batch(cmd, showToast(toastSuccess, fmt.Sprintf("Topic '%s' created", msg.name)))
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -173,9 +175,9 @@ func (m *CreateTopicModel) createTopic() (CreateTopicModel, tea.Cmd) {
 		return *m, nil
 	}
 
-	// Parse partitions
+	// Parse partitions; left empty, the client applies the topic policy default
 	partitionsStr := m.inputs[partitionsIdx].Value()
-	partitions := int32(1)
+	partitions := int32(0)
 	if partitionsStr != "" {
 		if p, err := strconv.ParseInt(partitionsStr, 10, 32); err == nil && p > 0 {
 			partitions = int32(p)
@@ -187,7 +189,7 @@ func (m *CreateTopicModel) createTopic() (CreateTopicModel, tea.Cmd) {
 
 	// Parse replication factor
 	replicationStr := m.inputs[replicationIdx].Value()
-	replication := int16(1)
+	replication := int16(0)
 	if replicationStr != "" {
 		if r, err := strconv.ParseInt(replicationStr, 10, 16); err == nil && r > 0 {
 			replication = int16(r)
@@ -237,6 +239,10 @@ func (m CreateTopicModel) View() string {
 
 	sb.WriteString(titleStyle.Render("🎯 Create New Topic"))
 	sb.WriteString("\n\n")
+	if limits := m.policy.String(); limits != "" {
+		sb.WriteString(helpStyle.Render("Cluster policy: " + limits))
+		sb.WriteString("\n\n")
+	}
 
 	// Input fields
 	for i := range m.inputs {
@@ -255,19 +261,13 @@ func (m CreateTopicModel) View() string {
 	sb.WriteString(*button)
 	sb.WriteString("\n\n")
 
-	// Error or success message
+	// Error message
 	if m.err != nil {
 		errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
 		sb.WriteString(errorStyle.Render(fmt.Sprintf("❌ Error: %v", m.err)))
 		sb.WriteString("\n")
 	}
 
-	if m.successMsg != "" {
-		successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("46"))
-		sb.WriteString(successStyle.Render(m.successMsg))
-		sb.WriteString("\n")
-	}
-
 	// Help
 	sb.WriteString("\n")
 	sb.WriteString(helpStyle.Render("Tab: Navigate fields • Enter: Next/Create • Esc: Cancel"))
@@ -0,0 +1,300 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+)
+
+const (
+	// dashboardInterval is how often the dashboard refreshes while it is shown
+	dashboardInterval = 10 * time.Second
+	// dashboardRateSamples is the length of the throughput sparkline
+	dashboardRateSamples = 30
+	// dashboardProbes is how many latency probes each refresh sends
+	dashboardProbes = 5
+)
+
+type dashboardTickMsg struct{}
+
+// dashboardSnapshot is the cluster state behind one dashboard refresh
+type dashboardSnapshot struct {
+	at          time.Time
+	brokers     []kafka.BrokerInfo
+	topics      []kafka.TopicInfo
+	stats       *kafka.ClusterStats
+	groups      []kafka.ConsumerGroupInfo
+	endOffsets  int64 // Sum of all log end offsets, for the produce rate
+	haveOffsets bool
+	features    *kafka.ClusterFeatures
+}
+
+type dashboardMsg struct {
+	snapshot *dashboardSnapshot
+	err      error
+}
+
+func dashboardTick() tea.Cmd {
+	return tea.Tick(dashboardInterval, func(time.Time) tea.Msg {
+		return dashboardTickMsg{}
+	})
+}
+
+func fetchDashboard(client *kafka.Client) tea.Cmd {
+	return func() tea.Msg {
+		s := &dashboardSnapshot{at: time.Now()}
+		var err error
+		if s.brokers, err = client.GetBrokers(); err != nil {
+			return dashboardMsg{err: err}
+		}
+		if s.topics, err = client.GetTopicDetails(); err != nil {
+			return dashboardMsg{err: err}
+		}
+		if s.stats, err = client.GetClusterStats(); err != nil {
+			return dashboardMsg{err: err}
+		}
+		if s.groups, err = client.GetConsumerGroups(); err != nil {
+			return dashboardMsg{err: err}
+		}
+		// The rate is left out rather than failing the whole dashboard
+		if offsets, err := client.GetLogEndOffsets(); err != nil {
+			logger.Get().WithError(err).Warn("Failed to get log end offsets")
+		} else {
+			for _, o := range offsets {
+				s.endOffsets += o
+			}
+			s.haveOffsets = true
+		}
+		if s.features, err = client.GetClusterFeatures(); err != nil {
+			logger.Get().WithError(err).Warn("Failed to get cluster features")
+		}
+		return dashboardMsg{snapshot: s}
+	}
+}
+
+type latencyProbeMsg struct {
+	result *kafka.LatencyResult
+	err    error
+}
+
+// probeLatency measures the produce and consume round trip through topic
+func probeLatency(client *kafka.Client, topic string) tea.Cmd {
+	return func() tea.Msg {
+		result, err := client.ProbeLatency(context.Background(), topic, kafka.LatencyOptions{
+			Count:    dashboardProbes,
+			Interval: 100 * time.Millisecond,
+			Timeout:  5 * time.Second,
+		})
+		return latencyProbeMsg{result: result, err: err}
+	}
+}
+
+// refreshDashboard fetches the cluster state, and probes the latency too
+// when a probe topic is set
+func (m Model) refreshDashboard() tea.Cmd {
+	if m.dashboard.probeTopic == "" {
+		return fetchDashboard(m.client)
+	}
+	return tea.Batch(fetchDashboard(m.client), probeLatency(m.client, m.dashboard.probeTopic))
+}
+
+// WithLatencyProbe shows the round trip latency through topic on the dashboard
+func (m Model) WithLatencyProbe(topic string) Model {
+	m.dashboard.probeTopic = topic
+	return m
+}
+
+// dashboard holds the latest snapshot and the produce rate derived from
+// consecutive snapshots
+type dashboard struct {
+	current *dashboardSnapshot
+	rate    float64 // Messages/sec between the last two snapshots
+	hasRate bool
+	rates   []int64 // Recent rates, for the sparkline
+	err     error
+
+	probeTopic string // Topic latency probes go through, empty for none
+	latency    *kafka.LatencyResult
+	latencyErr error
+}
+
+// record stores a snapshot and updates the rate
+func (d *dashboard) record(s *dashboardSnapshot) {
+	prev := d.current
+	d.current = s
+	d.err = nil
+	if prev == nil || !prev.haveOffsets || !s.haveOffsets {
+		d.hasRate = false
+		return
+	}
+	elapsed := s.at.Sub(prev.at).Seconds()
+	produced := s.endOffsets - prev.endOffsets
+	if elapsed <= 0 || produced < 0 {
+		// Deleted topics make the sum go backwards
+		d.hasRate = false
+		return
+	}
+	d.rate = float64(produced) / elapsed
+	d.hasRate = true
+	d.rates = append(d.rates, int64(d.rate))
+	if len(d.rates) > dashboardRateSamples {
+		d.rates = d.rates[len(d.rates)-dashboardRateSamples:]
+	}
+}
+
+func (m Model) renderDashboardView() string {
+	d := m.dashboard
+	if d.err != nil && d.current == nil {
+		return fmt.Sprintf("Error: %v\n\nPress 'r' to retry", d.err)
+	}
+	if d.current == nil {
+		return "Loading cluster overview..."
+	}
+	s := d.current
+
+	offlineBrokers := 0
+	for _, b := range s.brokers {
+		if b.Status != "Online" {
+			offlineBrokers++
+		}
+	}
+	partitions := 0
+	for _, t := range s.topics {
+		partitions += t.Partitions
+	}
+	var totalLag int64
+	for _, g := range s.groups {
+		totalLag += g.ConsumerLag
+	}
+
+	rate := "measuring..."
+	if !s.haveOffsets {
+		rate = "unavailable"
+	} else if d.hasRate {
+		rate = fmt.Sprintf("%.1f", d.rate)
+	}
+
+	okColor, warnColor, badColor := lipgloss.Color("46"), lipgloss.Color("214"), lipgloss.Color("196")
+	tiles := []string{
+		dashboardTile("Brokers", fmt.Sprintf("%d", len(s.brokers)), pick(offlineBrokers > 0, badColor, okColor),
+			pick(offlineBrokers > 0, fmt.Sprintf("%d offline", offlineBrokers), "all online")),
+		dashboardTile("Topics", fmt.Sprintf("%d", len(s.topics)), lipgloss.Color("229"), ""),
+		dashboardTile("Partitions", fmt.Sprintf("%d", partitions), lipgloss.Color("229"),
+			fmt.Sprintf("%d replicas", s.stats.TotalReplicas)),
+		dashboardTile("Under-replicated", fmt.Sprintf("%d", s.stats.UnderReplicatedPartitions),
+			pick(s.stats.UnderReplicatedPartitions > 0, warnColor, okColor),
+			pick(s.stats.OfflinePartitions > 0, fmt.Sprintf("%d offline", s.stats.OfflinePartitions), "")),
+		dashboardTile("Consumer groups", fmt.Sprintf("%d", len(s.groups)), lipgloss.Color("229"), ""),
+		dashboardTile("Total lag", fmt.Sprintf("%d", totalLag), pick(totalLag > 0, warnColor, okColor), "messages"),
+		dashboardTile("Messages/sec", rate, lipgloss.Color("229"), sparkline(d.rates)),
+	}
+	if d.probeTopic != "" {
+		tiles = append(tiles, latencyTile(d.latency, d.latencyErr))
+	}
+
+	perRow := 4
+	if m.width > 0 && m.width < 4*dashboardTileWidth+8 {
+		perRow = 2
+	}
+	var rows []string
+	for i := 0; i < len(tiles); i += perRow {
+		end := i + perRow
+		if end > len(tiles) {
+			end = len(tiles)
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, tiles[i:end]...))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(lipgloss.JoinVertical(lipgloss.Left, rows...))
+	sb.WriteString("\n")
+	if s.features != nil && len(s.features.Features) > 0 {
+		sb.WriteString(renderFeatures(s.features))
+		sb.WriteString("\n")
+	}
+	updated := fmt.Sprintf("Updated %s, refreshing every %s", s.at.Format("15:04:05"), dashboardInterval)
+	if d.err != nil {
+		updated += fmt.Sprintf(" (last refresh failed: %v)", d.err)
+	}
+	sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(updated))
+	return sb.String()
+}
+
+// renderFeatures lists the cluster's feature flags, naming metadata.version
+// levels after their release
+func renderFeatures(f *kafka.ClusterFeatures) string {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	var sb strings.Builder
+	title := "Feature flags"
+	if f.Epoch >= 0 {
+		title += fmt.Sprintf(" (epoch %d)", f.Epoch)
+	}
+	sb.WriteString(headerStyle.Render(title))
+	sb.WriteString("\n")
+	for _, feature := range f.Features {
+		finalized := "not finalized"
+		if feature.IsFinalized {
+			finalized = fmt.Sprintf("%d", feature.Finalized)
+			if feature.Name == kafka.MetadataVersionFeature {
+				finalized += " (" + kafka.MetadataVersionName(feature.Finalized) + ")"
+			}
+		}
+		supported := ""
+		if feature.IsSupported {
+			supported = fmt.Sprintf("broker %d supports %d-%d", f.Broker, feature.MinSupported, feature.MaxSupported)
+		}
+		sb.WriteString(fmt.Sprintf("  %-28s %-22s %s\n", feature.Name, finalized, dimStyle.Render(supported)))
+	}
+	return sb.String()
+}
+
+// latencyTile shows the median round trip of the last probe
+func latencyTile(result *kafka.LatencyResult, err error) string {
+	const label = "Round trip p50"
+	switch {
+	case err != nil:
+		return dashboardTile(label, "failed", lipgloss.Color("196"), truncateString(err.Error(), dashboardTileWidth-2))
+	case result == nil:
+		return dashboardTile(label, "probing...", lipgloss.Color("229"), "")
+	case result.Received == 0:
+		return dashboardTile(label, "no reply", lipgloss.Color("196"), fmt.Sprintf("%d probes lost", result.Sent))
+	}
+	detail := fmt.Sprintf("p99 %s", result.RoundTrip.P99.Round(100*time.Microsecond))
+	if lost := result.Sent - result.Received; lost > 0 {
+		detail += fmt.Sprintf(", %d lost", lost)
+	}
+	return dashboardTile(label, result.RoundTrip.P50.Round(100*time.Microsecond).String(),
+		pick(result.Received < result.Sent, lipgloss.Color("214"), lipgloss.Color("46")), detail)
+}
+
+const dashboardTileWidth = 24
+
+// dashboardTile renders one metric as a bordered box
+func dashboardTile(label, value string, color lipgloss.Color, detail string) string {
+	style := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		Padding(0, 1).
+		Width(dashboardTileWidth)
+
+	content := lipgloss.NewStyle().Foreground(lipgloss.Color("246")).Render(label) + "\n" +
+		lipgloss.NewStyle().Bold(true).Foreground(color).Render(value) + "\n" +
+		lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(detail)
+	return style.Render(content)
+}
+
+// pick returns a if cond holds and b otherwise
+func pick[T any](cond bool, a, b T) T {
+	if cond {
+		return a
+	}
+	return b
+}
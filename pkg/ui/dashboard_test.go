@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+func TestDashboardRate(t *testing.T) {
+	start := time.Unix(1000, 0)
+	var d dashboard
+
+	d.record(&dashboardSnapshot{at: start, endOffsets: 500, haveOffsets: true})
+	if d.hasRate {
+		t.Fatalf("rate after a single snapshot")
+	}
+
+	d.record(&dashboardSnapshot{at: start.Add(10 * time.Second), endOffsets: 1500, haveOffsets: true})
+	if !d.hasRate || d.rate != 100 {
+		t.Errorf("rate = %v (%v), want 100", d.rate, d.hasRate)
+	}
+
+	// A deleted topic makes the total shrink
+	d.record(&dashboardSnapshot{at: start.Add(20 * time.Second), endOffsets: 200, haveOffsets: true})
+	if d.hasRate {
+		t.Errorf("rate reported after offsets went backwards")
+	}
+	if len(d.rates) != 1 {
+		t.Errorf("rates = %v, want one sample", d.rates)
+	}
+}
+
+func TestLatencyTile(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *kafka.LatencyResult
+		err    error
+		want   string
+	}{
+		{"pending", nil, nil, "probing..."},
+		{"failed", nil, errors.New("unknown topic"), "unknown topic"},
+		{"lost", &kafka.LatencyResult{Sent: 5}, nil, "5 probes lost"},
+		{"ok", &kafka.LatencyResult{Sent: 5, Received: 4, RoundTrip: kafka.LatencyPercentiles{
+			P50: 3 * time.Millisecond, P99: 12 * time.Millisecond}}, nil, "p99 12ms, 1 lost"},
+	}
+	for _, tt := range tests {
+		if got := latencyTile(tt.result, tt.err); !strings.Contains(got, tt.want) {
+			t.Errorf("%s: tile does not show %q:\n%s", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,349 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+const (
+	// decommissionPollInterval is how often a running decommission is checked
+	decommissionPollInterval = 5 * time.Second
+	// decommissionShownMoves is how many planned moves are listed
+	decommissionShownMoves = 15
+	// defaultDecommissionThrottle keeps a drain from saturating the network
+	defaultDecommissionThrottle = "50MB/s"
+)
+
+type decommissionStage int
+
+const (
+	decommissionPlanning decommissionStage = iota
+	decommissionReview
+	decommissionConfirm
+	decommissionSubmitting
+	decommissionTracking
+	decommissionDone
+)
+
+// DecommissionModel walks through draining every replica off a broker:
+// plan the moves, pick a throttle, submit and track until the broker is empty
+type DecommissionModel struct {
+	client        *kafka.Client
+	broker        kafka.BrokerInfo
+	stage         decommissionStage
+	plan          *kafka.ReassignmentPlan
+	throttleInput textinput.Model
+	throttle      int64
+	progress      kafka.ReassignmentProgress
+	hasProgress   bool
+	started       time.Time
+	finished      time.Time
+	notice        string // Non-fatal problems, such as failing to clear the throttle
+	err           error
+	width         int
+	height        int
+}
+
+func NewDecommissionModel(client *kafka.Client, broker kafka.BrokerInfo) DecommissionModel {
+	ti := textinput.New()
+	ti.Placeholder = "e.g. 50MB/s, 0 for none"
+	ti.SetValue(defaultDecommissionThrottle)
+	ti.CharLimit = 20
+	ti.Width = 20
+	ti.Focus()
+
+	return DecommissionModel{
+		client:        client,
+		broker:        broker,
+		throttleInput: ti,
+	}
+}
+
+type decommissionPlanMsg struct {
+	plan *kafka.ReassignmentPlan
+	err  error
+}
+
+type decommissionStartedMsg struct {
+	err error
+}
+
+type decommissionProgressMsg struct {
+	progress kafka.ReassignmentProgress
+	err      error
+}
+
+type decommissionPollMsg struct{}
+
+type throttleClearedMsg struct {
+	err error
+}
+
+func planDecommission(client *kafka.Client, brokerID int32) tea.Cmd {
+	return func() tea.Msg {
+		plan, err := client.PlanDecommission(brokerID)
+		return decommissionPlanMsg{plan: plan, err: err}
+	}
+}
+
+func startDecommission(client *kafka.Client, plan *kafka.ReassignmentPlan, throttle int64) tea.Cmd {
+	return func() tea.Msg {
+		return decommissionStartedMsg{err: client.StartReassignment(plan, throttle)}
+	}
+}
+
+func checkDecommission(client *kafka.Client, brokerID int32, plan *kafka.ReassignmentPlan) tea.Cmd {
+	return func() tea.Msg {
+		progress, err := client.DecommissionProgress(brokerID, plan)
+		return decommissionProgressMsg{progress: progress, err: err}
+	}
+}
+
+func clearDecommissionThrottle(client *kafka.Client, plan *kafka.ReassignmentPlan) tea.Cmd {
+	return func() tea.Msg {
+		return throttleClearedMsg{err: client.ClearReplicationThrottle(plan.Moves)}
+	}
+}
+
+// releaseDecommissionThrottle clears the throttle of a decommission left
+// while it runs. The view is gone by then, so failures go to the error history.
+func releaseDecommissionThrottle(client *kafka.Client, plan *kafka.ReassignmentPlan) tea.Cmd {
+	return func() tea.Msg {
+		if err := client.ClearReplicationThrottle(plan.Moves); err != nil {
+			return ErrorMsg{Source: "replication throttle", Err: err}
+		}
+		return nil
+	}
+}
+
+func decommissionPoll() tea.Cmd {
+	return tea.Tick(decommissionPollInterval, func(time.Time) tea.Msg {
+		return decommissionPollMsg{}
+	})
+}
+
+func (m DecommissionModel) Init() tea.Cmd {
+	return planDecommission(m.client, m.broker.ID)
+}
+
+func (m DecommissionModel) Update(msg tea.Msg) (DecommissionModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case decommissionPlanMsg:
+		m.plan, m.err = msg.plan, msg.err
+		if m.err == nil {
+			m.stage = decommissionReview
+		}
+		return m, textinput.Blink
+
+	case decommissionStartedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			m.stage = decommissionReview
+			return m, nil
+		}
+		m.stage = decommissionTracking
+		m.started = time.Now()
+		return m, checkDecommission(m.client, m.broker.ID, m.plan)
+
+	case decommissionPollMsg:
+		if m.stage != decommissionTracking {
+			return m, nil
+		}
+		return m, checkDecommission(m.client, m.broker.ID, m.plan)
+
+	case decommissionProgressMsg:
+		if m.stage != decommissionTracking {
+			return m, nil
+		}
+		// A failed check is retried on the next poll
+		m.err = msg.err
+		if msg.err == nil {
+			m.progress, m.hasProgress = msg.progress, true
+			// Polling stops once the planned moves are done, even when the
+			// broker gained replicas since, as they are not part of the plan
+			if msg.progress.Moving == 0 {
+				m.stage = decommissionDone
+				m.finished = time.Now()
+				if m.throttle > 0 {
+					return m, clearDecommissionThrottle(m.client, m.plan)
+				}
+				return m, nil
+			}
+		}
+		return m, decommissionPoll()
+
+	case throttleClearedMsg:
+		if msg.err != nil {
+			m.notice = fmt.Sprintf("The throttle is still set: %v", msg.err)
+		}
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m DecommissionModel) handleKey(msg tea.KeyMsg) (DecommissionModel, tea.Cmd) {
+	switch m.stage {
+	case decommissionReview:
+		switch msg.String() {
+		case "esc":
+			return m, ReturnToListView
+		case "enter":
+			throttle, err := kafka.ParseByteRate(m.throttleInput.Value())
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
+			if len(m.plan.Moves) == 0 {
+				return m, nil
+			}
+			m.throttle, m.err = throttle, nil
+			m.stage = decommissionConfirm
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.throttleInput, cmd = m.throttleInput.Update(msg)
+		return m, cmd
+
+	case decommissionConfirm:
+		switch msg.String() {
+		case "y", "Y":
+			m.stage = decommissionSubmitting
+			return m, startDecommission(m.client, m.plan, m.throttle)
+		case "n", "N", "esc":
+			m.stage = decommissionReview
+		}
+		return m, nil
+
+	case decommissionSubmitting:
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		if m.stage == decommissionTracking && m.throttle > 0 {
+			return m, tea.Batch(ReturnToListView, releaseDecommissionThrottle(m.client, m.plan))
+		}
+		return m, ReturnToListView
+	case "r":
+		if m.stage == decommissionPlanning && m.err != nil {
+			m.err = nil
+			return m, planDecommission(m.client, m.broker.ID)
+		}
+	}
+	return m, nil
+}
+
+func (m DecommissionModel) View() string {
+	var s strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Padding(0, 1)
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Bold(true)
+	okStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("46"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+
+	s.WriteString(titleStyle.Render(fmt.Sprintf("🚚 Decommission Broker %d (%s)", m.broker.ID, m.broker.Host)))
+	s.WriteString("\n\n")
+
+	if m.err != nil {
+		s.WriteString(errorStyle.Render(fmt.Sprintf("❌ %v", m.err)))
+		s.WriteString("\n\n")
+	}
+
+	if m.stage == decommissionPlanning {
+		if m.err == nil {
+			s.WriteString("Planning replica moves...")
+			s.WriteString("\n\n" + dimStyle.Render("Esc: Back"))
+		} else {
+			s.WriteString(dimStyle.Render("r: Retry | Esc: Back"))
+		}
+		return s.String()
+	}
+
+	moves := m.plan.Moves
+	if len(moves) == 0 {
+		s.WriteString(okStyle.Render(fmt.Sprintf("✅ Broker %d holds no replicas; it can be shut down", m.broker.ID)))
+		s.WriteString("\n\n" + dimStyle.Render("Esc: Back"))
+		return s.String()
+	}
+
+	s.WriteString(headerStyle.Render(fmt.Sprintf("%d partitions of %d topics move off broker %d", len(moves), len(m.plan.Topics()), m.broker.ID)))
+	s.WriteString("\n")
+	shown := moves
+	if len(shown) > decommissionShownMoves {
+		shown = shown[:decommissionShownMoves]
+	}
+	for _, move := range shown {
+		s.WriteString("  " + move.String() + "\n")
+	}
+	if hidden := len(moves) - len(shown); hidden > 0 {
+		s.WriteString(dimStyle.Render(fmt.Sprintf("  … %d more", hidden)) + "\n")
+	}
+	s.WriteString("\n")
+
+	switch m.stage {
+	case decommissionReview:
+		s.WriteString("Replication throttle: " + m.throttleInput.View())
+		s.WriteString("\n\n")
+		s.WriteString(dimStyle.Render("Enter: Start | Esc: Back"))
+
+	case decommissionConfirm:
+		throttle := "without a throttle"
+		if m.throttle > 0 {
+			throttle = fmt.Sprintf("throttled to %s/s", formatBytes(m.throttle))
+		}
+		s.WriteString(warnStyle.Render(fmt.Sprintf("Reassign %d partitions %s? (y/n)", len(moves), throttle)))
+
+	case decommissionSubmitting:
+		s.WriteString("Submitting reassignments...")
+
+	case decommissionTracking:
+		if m.hasProgress {
+			done := len(moves) - m.progress.Moving
+			s.WriteString(fmt.Sprintf("%s %d/%d partitions moved, %d replicas left on broker %d",
+				shareBar(float64(done)/float64(len(moves))), done, len(moves), m.progress.Remaining, m.broker.ID))
+		} else {
+			s.WriteString("Checking progress...")
+		}
+		s.WriteString("\n\n")
+		leave := "Esc: Back (the reassignment continues)"
+		if m.throttle > 0 {
+			leave = "Esc: Back (the reassignment continues without the throttle)"
+		}
+		s.WriteString(dimStyle.Render(fmt.Sprintf("Running for %s, checking every %s | %s",
+			time.Since(m.started).Round(time.Second), decommissionPollInterval, leave)))
+
+	case decommissionDone:
+		elapsed := m.finished.Sub(m.started).Round(time.Second)
+		if m.progress.Remaining > 0 {
+			s.WriteString(warnStyle.Render(fmt.Sprintf("⚠️  The planned moves are done after %s but broker %d gained %d replicas since; start another decommission to move them",
+				elapsed, m.broker.ID, m.progress.Remaining)))
+		} else {
+			s.WriteString(okStyle.Render(fmt.Sprintf("✅ Broker %d is empty after %s; it can be shut down", m.broker.ID, elapsed)))
+		}
+		if m.notice != "" {
+			s.WriteString("\n" + warnStyle.Render("⚠️  "+m.notice))
+		}
+		s.WriteString("\n\n" + dimStyle.Render("Esc: Back"))
+	}
+	return s.String()
+}
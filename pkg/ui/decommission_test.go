@@ -0,0 +1,28 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+func TestDecommissionStopsPollingOncePlannedMovesAreDone(t *testing.T) {
+	m := DecommissionModel{stage: decommissionTracking, plan: &kafka.ReassignmentPlan{}}
+
+	m, cmd := m.Update(decommissionProgressMsg{progress: kafka.ReassignmentProgress{Moving: 2, Remaining: 5}})
+	if m.stage != decommissionTracking || cmd == nil {
+		t.Fatalf("stage = %v, want tracking with another poll", m.stage)
+	}
+
+	// The broker gained replicas the plan does not move, so waiting for it
+	// to empty would poll forever
+	m, cmd = m.Update(decommissionProgressMsg{progress: kafka.ReassignmentProgress{Remaining: 1}})
+	if m.stage != decommissionDone || cmd != nil {
+		t.Errorf("stage = %v, cmd = %v, want done without polling", m.stage, cmd)
+	}
+
+	m.stage, m.throttle = decommissionTracking, 1024
+	if m, cmd = m.Update(decommissionProgressMsg{}); m.stage != decommissionDone || cmd == nil {
+		t.Error("the throttle was not cleared when the moves finished")
+	}
+}
@@ -2,7 +2,6 @@ package ui
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/digitalis-io/kconduit/pkg/kafka"
 	"github.com/digitalis-io/kconduit/pkg/logger"
@@ -60,7 +59,7 @@ func (m *DeleteACLModel) buildForm() {
 						"Pattern: %s\n"+
 						"Operation: %s\n"+
 						"Permission: %s\n\n"+
-						"⚠️  This action cannot be undone!",
+						"You can recreate it from the Undo view (u).",
 					m.acl.Principal,
 					m.acl.Host,
 					m.acl.ResourceType,
@@ -152,18 +151,15 @@ func (m *DeleteACLModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.err = msg.err
 			m.success = false
 			// Show error but don't return to list yet
-			return m, nil
+			return m, reportError("delete acl", msg.err)
 		}
 		// Set success first, then clear deleting flag to avoid brief error display
 		m.success = true
 		m.deleting = false
 		log.Info("ACL deleted successfully, returning to ACLs tab")
-		// Add a small delay before returning to see the success message
 		return m, tea.Batch(
-			tea.Println("✅ ACL deleted successfully!"),
-			tea.Tick(500*time.Millisecond, func(t time.Time) tea.Msg {
-				return ViewChangedMsg{View: ACLsTab}
-			}),
+			showToast(toastSuccess, "ACL deleted"),
+			func() tea.Msg { return ViewChangedMsg{View: ACLsTab} },
 		)
 
 	case spinner.TickMsg:
@@ -114,10 +114,10 @@ func (m DeleteTopicModel) Update(msg tea.Msg) (DeleteTopicModel, tea.Cmd) {
 	case topicDeletedMsg:
 		if msg.err != nil {
 			m.err = msg.err
-			return m, nil
+			return m, reportError("delete topic", msg.err)
 		}
 		// Success - return to list view
-		return m, ReturnToListView
+		return m, tea.Batch(ReturnToListView, showToast(toastSuccess, fmt.Sprintf("Topic '%s' deleted", msg.topicName)))
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -21,6 +21,9 @@ type EditACLHuhModel struct {
 	success     bool
 	width       int
 	height      int
+	// Set after a partial failure to offer retry or rollback
+	result      *kafka.ACLReplaceResult
+	rollingBack bool
 
 	// Form fields
 	principal      string
@@ -79,7 +82,7 @@ func (m *EditACLHuhModel) buildForm() {
 		huh.NewGroup(
 			huh.NewNote().
 				Title("✏️  Edit ACL").
-				Description(fmt.Sprintf("Editing ACL for %s on %s %s\n⚠️  This will delete the existing ACL and create new ACL(s) with the updated values.",
+				Description(fmt.Sprintf("Editing ACL for %s on %s %s\nNew ACL(s) are created and verified before the existing ACL is deleted, so the principal keeps its access if anything fails.",
 					m.originalACL.Principal,
 					m.originalACL.ResourceType,
 					m.originalACL.ResourceName)),
@@ -150,24 +153,19 @@ func (m EditACLHuhModel) Init() tea.Cmd {
 }
 
 type aclUpdatedMsg struct {
+	result *kafka.ACLReplaceResult
+	err    error
+}
+
+type aclRolledBackMsg struct {
 	err error
 }
 
 func (m EditACLHuhModel) updateACLs() tea.Msg {
-	// First delete the original ACL
-	err := m.client.DeleteACL(m.originalACL)
-	if err != nil {
-		// Log but don't fail - the ACL might have already been deleted
-		// TODO: Consider showing a warning to the user about deletion failure
-		_ = err // Explicitly ignore the error as we want to continue with creation
-	}
-
-	// Create new ACLs for each selected operation
-	var errors []string
-	successCount := 0
-
+	// One replacement ACL per selected operation
+	var replacements []kafka.ACL
 	for _, operation := range m.operations {
-		acl := kafka.ACL{
+		replacements = append(replacements, kafka.ACL{
 			Principal:      m.principal,
 			Host:           m.host,
 			ResourceType:   m.resourceType,
@@ -175,21 +173,31 @@ func (m EditACLHuhModel) updateACLs() tea.Msg {
 			PatternType:    m.patternType,
 			Operation:      operation,
 			PermissionType: m.permissionType,
-		}
+		})
+	}
+
+	result, err := m.client.ReplaceACL(m.originalACL, replacements)
+	return aclUpdatedMsg{result: result, err: err}
+}
 
-		err := m.client.CreateACL(acl)
-		if err != nil {
-			errors = append(errors, fmt.Sprintf("%s: %v", operation, err))
-		} else {
-			successCount++
+// rollback deletes the replacement ACLs created by a failed update, leaving
+// only the original
+func (m EditACLHuhModel) rollback() tea.Msg {
+	var errors []string
+	for _, acl := range m.result.Created {
+		if err := m.client.DeleteACL(acl); err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", acl.Operation, err))
 		}
 	}
-
 	if len(errors) > 0 {
-		return aclUpdatedMsg{err: fmt.Errorf("failed to create %d ACLs: %s", len(errors), strings.Join(errors, "; "))}
+		return aclRolledBackMsg{err: fmt.Errorf("failed to roll back %d ACLs: %s", len(errors), strings.Join(errors, "; "))}
 	}
+	return aclRolledBackMsg{}
+}
 
-	return aclUpdatedMsg{err: nil}
+// canRollBack reports whether a failed update left new ACLs next to the original
+func (m EditACLHuhModel) canRollBack() bool {
+	return m.result != nil && len(m.result.Created) > 0 && !m.result.OriginalDeleted
 }
 
 // Validation methods
@@ -254,17 +262,54 @@ func (m EditACLHuhModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 
+		// Recovery options after a partial failure
+		if m.result != nil && !m.updating {
+			switch msg.String() {
+			case "r":
+				m.updating = true
+				m.err = nil
+				return m, tea.Batch(m.spinner.Tick, m.updateACLs)
+			case "b":
+				if m.canRollBack() {
+					m.updating = true
+					m.rollingBack = true
+					m.err = nil
+					return m, tea.Batch(m.spinner.Tick, m.rollback)
+				}
+			}
+			return m, nil
+		}
+
 	case aclUpdatedMsg:
 		m.updating = false
 		if msg.err != nil {
 			m.err = msg.err
+			// A retry leaves alone the ACLs earlier attempts created, so they
+			// are carried over for the rollback
+			if m.result != nil && msg.result != nil {
+				msg.result.Created = append(append([]kafka.ACL(nil), m.result.Created...), msg.result.Created...)
+			}
+			m.result = msg.result
 			m.success = false
 			// Don't rebuild form, just return to preserve state
-			return m, nil
+			return m, reportError("edit acl", msg.err)
 		}
+		m.result = nil
 		m.success = true
 		return m, tea.Batch(
-			tea.Println("✅ ACL(s) updated successfully!"),
+			showToast(toastSuccess, "ACL(s) updated"),
+			func() tea.Msg { return ViewChangedMsg{View: ACLsTab} },
+		)
+
+	case aclRolledBackMsg:
+		m.updating = false
+		m.rollingBack = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, reportError("edit acl", msg.err)
+		}
+		return m, tea.Batch(
+			showToast(toastInfo, "Rolled back, the original ACL is unchanged"),
 			func() tea.Msg { return ViewChangedMsg{View: ACLsTab} },
 		)
 
@@ -307,16 +352,26 @@ func (m EditACLHuhModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m EditACLHuhModel) View() string {
+	if m.rollingBack {
+		return lipgloss.NewStyle().
+			Padding(2, 4).
+			Render(fmt.Sprintf("%s Rolling back %d new ACL(s)...", m.spinner.View(), len(m.result.Created)))
+	}
+
 	if m.updating {
 		return lipgloss.NewStyle().
 			Padding(2, 4).
-			Render(fmt.Sprintf("%s Updating ACL(s)...\n\nDeleting original: %s %s on %s %s\nCreating new: %s operations",
+			Render(fmt.Sprintf("%s Updating ACL(s)...\n\nCreating new: %s operations\nThen deleting original: %s %s on %s %s",
 				m.spinner.View(),
+				strings.Join(m.operations, ", "),
 				m.originalACL.Operation,
 				m.originalACL.PermissionType,
 				m.originalACL.ResourceType,
-				m.originalACL.ResourceName,
-				strings.Join(m.operations, ", ")))
+				m.originalACL.ResourceName))
+	}
+
+	if m.result != nil {
+		return m.recoveryView()
 	}
 
 	if m.success {
@@ -354,3 +409,44 @@ func (m EditACLHuhModel) View() string {
 		helpText,
 	)
 }
+
+// recoveryView explains a partial failure and the available recovery options
+func (m EditACLHuhModel) recoveryView() string {
+	var sb strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("214"))
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196"))
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Italic(true)
+
+	sb.WriteString(titleStyle.Render("⚠️  ACL update did not complete") + "\n\n")
+	if m.err != nil {
+		sb.WriteString(errorStyle.Render(fmt.Sprintf("❌ %v", m.err)) + "\n\n")
+	}
+
+	for _, acl := range m.result.Created {
+		sb.WriteString(fmt.Sprintf("  ✅ created  %s\n", acl))
+	}
+	for _, acl := range m.result.Failed {
+		sb.WriteString(fmt.Sprintf("  ❌ missing  %s\n", acl))
+	}
+	if m.result.OriginalDeleted {
+		sb.WriteString(fmt.Sprintf("  🗑️  deleted  %s\n", m.originalACL))
+	} else {
+		sb.WriteString(fmt.Sprintf("  🔒 kept     %s\n", m.originalACL))
+	}
+	sb.WriteString("\n")
+
+	help := "r: Retry the update"
+	if m.canRollBack() {
+		help += " • b: Roll back (delete the new ACLs, keep the original)"
+	}
+	help += " • Esc: Keep the current state"
+	sb.WriteString(helpStyle.Render(help))
+
+	return lipgloss.NewStyle().Padding(1, 2).Render(sb.String())
+}
@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+func TestEditACLRetryKeepsCreatedForRollback(t *testing.T) {
+	read := kafka.ACL{Principal: "User:alice", Operation: "Read", ResourceType: "Topic", ResourceName: "orders"}
+	write := kafka.ACL{Principal: "User:alice", Operation: "Write", ResourceType: "Topic", ResourceName: "orders"}
+	m := EditACLHuhModel{updating: true}
+
+	model, _ := m.Update(aclUpdatedMsg{
+		result: &kafka.ACLReplaceResult{Stage: kafka.ACLReplaceCreate, Created: []kafka.ACL{read}, Failed: []kafka.ACL{write}},
+		err:    errors.New("failed to create 1 of 2 ACLs"),
+	})
+	m = model.(EditACLHuhModel)
+
+	// The retry sees read as existing and only creates write, then fails to
+	// delete the original
+	m.updating = true
+	model, _ = m.Update(aclUpdatedMsg{
+		result: &kafka.ACLReplaceResult{Stage: kafka.ACLReplaceVerify, Created: []kafka.ACL{write}},
+		err:    errors.New("new ACLs are not listed"),
+	})
+	m = model.(EditACLHuhModel)
+
+	if !reflect.DeepEqual(m.result.Created, []kafka.ACL{read, write}) {
+		t.Errorf("created = %v, want both attempts' ACLs", m.result.Created)
+	}
+	if !m.canRollBack() {
+		t.Error("rollback not offered")
+	}
+}
@@ -3,7 +3,6 @@ package ui
 import (
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/digitalis-io/kconduit/pkg/kafka"
 	"github.com/digitalis-io/kconduit/pkg/logger"
@@ -22,9 +21,11 @@ type EditConfigModel struct {
 	form         *huh.Form
 	submitted    bool
 	err          error
+	retention    kafka.RetentionSettings // The topic's current retention settings
+	whatIf       *retentionWhatIf        // Shown before a retention change is applied
 }
 
-func NewEditConfigModel(client *kafka.Client, topicName, configKey, currentValue string) *EditConfigModel {
+func NewEditConfigModel(client *kafka.Client, topicName, configKey, currentValue string, configs map[string]string) *EditConfigModel {
 	// Create a new model
 	model := &EditConfigModel{
 		client:       client,
@@ -32,6 +33,7 @@ func NewEditConfigModel(client *kafka.Client, topicName, configKey, currentValue
 		configKey:    configKey,
 		currentValue: currentValue,
 		newValue:     "", // Start with empty string
+		retention:    kafka.RetentionSettingsFromConfigs(configs),
 	}
 
 	// Create input field based on the config key type
@@ -83,7 +85,7 @@ func NewEditConfigModel(client *kafka.Client, topicName, configKey, currentValue
 		case "compression.type":
 			options = append(options,
 				huh.NewOption("producer", "producer"),
-				huh.NewOption("none", "none"),
+				huh.NewOption("uncompressed", "uncompressed"),
 				huh.NewOption("gzip", "gzip"),
 				huh.NewOption("snappy", "snappy"),
 				huh.NewOption("lz4", "lz4"),
@@ -105,28 +107,19 @@ func NewEditConfigModel(client *kafka.Client, topicName, configKey, currentValue
 		// Numeric fields use text input with validation
 		description := fmt.Sprintf("Current value: %s", currentValue)
 		
-		// Add help text for time-based fields
-		if strings.HasSuffix(configKey, ".ms") {
-			description += "\n💡 Tip: You can use formats like 1h, 1d, 7d, 1w (will convert to milliseconds)"
+		// Add help text for time and size fields
+		if kafka.ConfigTakesDuration(configKey) {
+			description += "\n💡 Tip: You can use formats like 30s, 1h, 7d, 2w (converted to milliseconds)"
+		} else if kafka.ConfigTakesSize(configKey) {
+			description += "\n💡 Tip: You can use formats like 512KiB, 100MiB, 1GiB (converted to bytes)"
 		}
-		
+
 		input = huh.NewInput().
 			Title(fmt.Sprintf("Edit %s", configKey)).
 			Description(description).
 			Placeholder(currentValue).
 			Value(&model.newValue).
-			Validate(func(s string) error {
-				// Allow -1 for unlimited values
-				if s == "-1" {
-					return nil
-				}
-				// Allow empty to keep current value
-				if s == "" {
-					return nil
-				}
-				// More validation could be added here
-				return nil
-			})
+			Validate(model.validate)
 
 	default:
 		// Default text input for other fields
@@ -134,7 +127,8 @@ func NewEditConfigModel(client *kafka.Client, topicName, configKey, currentValue
 			Title(fmt.Sprintf("Edit %s", configKey)).
 			Description(fmt.Sprintf("Current value: %s", currentValue)).
 			Placeholder(currentValue).
-			Value(&model.newValue)
+			Value(&model.newValue).
+			Validate(model.validate)
 	}
 
 	model.form = huh.NewForm(
@@ -144,6 +138,16 @@ func NewEditConfigModel(client *kafka.Client, topicName, configKey, currentValue
 	return model
 }
 
+// validate checks a typed value against the config's type and range, so the
+// form shows the problem inline. Empty keeps the current value.
+func (m *EditConfigModel) validate(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	_, err := kafka.NormalizeTopicConfig(m.configKey, s)
+	return err
+}
+
 func (m *EditConfigModel) Init() tea.Cmd {
 	return m.form.Init()
 }
@@ -151,6 +155,10 @@ func (m *EditConfigModel) Init() tea.Cmd {
 func (m *EditConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	log := logger.Get()
 	
+	if m.whatIf != nil {
+		return m.updateWhatIf(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch s := msg.String(); s {
@@ -179,39 +187,34 @@ func (m *EditConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			
 			// If newValue is empty, it means user didn't change anything (for text inputs)
 			// or pressed enter without selecting (for selects)
-			if m.newValue == "" || m.newValue == m.currentValue {
-				// No change, just return
+			if strings.TrimSpace(m.newValue) == "" {
 				log.Debug("No change detected, returning to list view")
 				return m, ReturnToListView
 			}
-
-			// Apply the configuration change to Kafka
-			log.WithFields(map[string]interface{}{
-				"topic":    m.topicName,
-				"key":      m.configKey,
-				"oldValue": m.currentValue,
-				"newValue": m.newValue,
-			}).Info("Applying configuration change")
-			
-			err := m.client.UpdateTopicConfig(m.topicName, m.configKey, m.newValue)
+			// Send durations and sizes such as 7d or 1GiB as the plain numbers
+			// the broker expects
+			normalized, err := kafka.NormalizeTopicConfig(m.configKey, m.newValue)
 			if err != nil {
-				m.err = err
-				log.WithError(err).Error("Failed to update configuration")
-				// Show error for longer so user can read it
-				return m, tea.Sequence(
-					tea.Tick(5*time.Second, func(t time.Time) tea.Msg {
-						return SwitchToListViewMsg{}
-					}),
+				return m, tea.Batch(
+					reportError("edit config", err),
+					showToast(toastError, err.Error()),
+					ReturnToListView,
 				)
 			}
-			m.submitted = true
-			log.Info("Configuration updated successfully")
-			// Show success message for a bit longer
-			return m, tea.Sequence(
-				tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
-					return SwitchToListViewMsg{}
-				}),
-			)
+			m.newValue = normalized
+			if m.newValue == m.currentValue {
+				// No change, just return
+				log.Debug("No change detected, returning to list view")
+				return m, ReturnToListView
+			}
+
+			// Show what a retention change does to disk use and existing data first
+			if proposed, ok := proposedRetention(m.retention, m.configKey, m.newValue); ok {
+				m.whatIf = &retentionWhatIf{current: m.retention, proposed: proposed}
+				return m, fetchRetentionWhatIf(m.client, m.topicName, m.retention, proposed)
+			}
+
+			return m, m.apply()
 		case huh.StateAborted:
 			// User cancelled, return to list view
 			return m, ReturnToListView
@@ -221,6 +224,54 @@ func (m *EditConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// apply sets the new value on the topic
+func (m *EditConfigModel) apply() tea.Cmd {
+	log := logger.Get()
+
+	// Apply the configuration change to Kafka
+	log.WithFields(map[string]interface{}{
+		"topic":    m.topicName,
+		"key":      m.configKey,
+		"oldValue": m.currentValue,
+		"newValue": m.newValue,
+	}).Info("Applying configuration change")
+	
+	err := m.client.UpdateTopicConfig(m.topicName, m.configKey, m.newValue)
+	if err != nil {
+		log.WithError(err).Error("Failed to update configuration")
+		return tea.Batch(
+			reportError("edit config", err),
+			showToast(toastError, fmt.Sprintf("Failed to set %s: %v", m.configKey, err)),
+			func() tea.Msg { return SwitchToListViewMsg{} },
+		)
+	}
+	m.submitted = true
+	log.Info("Configuration updated successfully")
+	return tea.Batch(
+		showToast(toastSuccess, fmt.Sprintf("%s set to %s on '%s'", m.configKey, m.newValue, m.topicName)),
+		func() tea.Msg { return SwitchToListViewMsg{} },
+	)
+}
+
+// updateWhatIf waits for the retention projection, then applies the change
+// on Enter or drops it on Esc
+func (m *EditConfigModel) updateWhatIf(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case retentionWhatIfMsg:
+		m.whatIf.projection, m.whatIf.err = msg.projection, msg.err
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return m, ReturnToListView
+		case "enter":
+			if m.whatIf.projection != nil || m.whatIf.err != nil {
+				return m, m.apply()
+			}
+		}
+	}
+	return m, nil
+}
+
 func (m *EditConfigModel) View() string {
 	if m.err != nil {
 		errorStyle := lipgloss.NewStyle().
@@ -248,5 +299,9 @@ func (m *EditConfigModel) View() string {
 		return "\n" + successStyle.Render(content)
 	}
 
+	if m.whatIf != nil {
+		return "\n" + m.whatIf.View(m.topicName) + "\n"
+	}
+
 	return fmt.Sprintf("\n%s\n", m.form.View())
 }
@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxErrorHistory is how many errors are kept before the oldest are dropped
+const maxErrorHistory = 200
+
+// ErrorMsg records an error in the error history, whatever view is active
+type ErrorMsg struct {
+	Source string
+	Err    error
+}
+
+// reportError returns a command that records err under source
+func reportError(source string, err error) tea.Cmd {
+	return func() tea.Msg {
+		return ErrorMsg{Source: source, Err: err}
+	}
+}
+
+type errorEntry struct {
+	at     time.Time
+	source string
+	err    error
+}
+
+// errorHistory keeps the session's errors, so one that a later refresh
+// clears can still be looked at
+type errorHistory struct {
+	entries []errorEntry
+	seen    int // How many entries were there when the list was last opened
+	now     func() time.Time
+}
+
+func newErrorHistory() *errorHistory {
+	return &errorHistory{now: time.Now}
+}
+
+func (h *errorHistory) add(source string, err error) {
+	if err == nil {
+		return
+	}
+	h.entries = append(h.entries, errorEntry{at: h.now(), source: source, err: err})
+	if over := len(h.entries) - maxErrorHistory; over > 0 {
+		h.entries = h.entries[over:]
+		h.seen = max(h.seen-over, 0)
+	}
+}
+
+// unseen is how many errors arrived since the list was last opened
+func (h *errorHistory) unseen() int {
+	return len(h.entries) - h.seen
+}
+
+func (h *errorHistory) markSeen() {
+	h.seen = len(h.entries)
+}
+
+func (h *errorHistory) clear() {
+	h.entries = nil
+	h.seen = 0
+}
+
+// recordError adds err to the history, for the fetches the main model makes
+func (m *Model) recordError(source string, err error) {
+	m.errors.add(source, err)
+}
+
+// ErrorsModel lists the session's errors, newest first
+type ErrorsModel struct {
+	history *errorHistory
+	offset  int
+	width   int
+	height  int
+}
+
+func NewErrorsModel(history *errorHistory, width, height int) ErrorsModel {
+	history.markSeen()
+	return ErrorsModel{history: history, width: width, height: height}
+}
+
+func (m ErrorsModel) Update(msg tea.Msg) (ErrorsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			return m, ReturnToListView
+		case "up", "k":
+			if m.offset > 0 {
+				m.offset--
+			}
+		case "down", "j":
+			if m.offset < len(m.history.entries)-1 {
+				m.offset++
+			}
+		case "c":
+			m.history.clear()
+			m.offset = 0
+		}
+	}
+	return m, nil
+}
+
+func (m ErrorsModel) View() string {
+	var s strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Padding(0, 1)
+	sourceStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	entries := m.history.entries
+	s.WriteString(titleStyle.Render(fmt.Sprintf("🧾 Error History (%d)", len(entries))))
+	s.WriteString("\n\n")
+
+	if len(entries) == 0 {
+		s.WriteString(dimStyle.Render("No errors this session"))
+		s.WriteString("\n\n" + dimStyle.Render("Esc: Back"))
+		return s.String()
+	}
+
+	// Title, blank line and help take four lines
+	rows := m.height - 4
+	if rows < 1 {
+		rows = 1
+	}
+	width := m.width - 30
+	if width < 20 {
+		width = 80
+	}
+	for i := len(entries) - 1 - m.offset; i >= 0 && rows > 0; i-- {
+		e := entries[i]
+		s.WriteString(dimStyle.Render(e.at.Format("15:04:05")) + "  ")
+		s.WriteString(sourceStyle.Render(fmt.Sprintf("%-14s", e.source)) + "  ")
+		s.WriteString(errorStyle.Render(truncateString(strings.ReplaceAll(e.err.Error(), "\n", " "), width)))
+		s.WriteString("\n")
+		rows--
+	}
+
+	s.WriteString("\n" + dimStyle.Render("↑/↓: Scroll | c: Clear | Esc: Back"))
+	return s.String()
+}
@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestErrorHistory(t *testing.T) {
+	h := newErrorHistory()
+	h.now = func() time.Time { return time.Unix(0, 0) }
+
+	h.add("topics", nil)
+	if len(h.entries) != 0 {
+		t.Fatalf("a nil error was recorded")
+	}
+
+	h.add("topics", errors.New("first"))
+	h.markSeen()
+	h.add("brokers", errors.New("second"))
+	if got := h.unseen(); got != 1 {
+		t.Errorf("unseen = %d, want 1", got)
+	}
+
+	for i := 0; i < maxErrorHistory; i++ {
+		h.add("dashboard", fmt.Errorf("error %d", i))
+	}
+	if len(h.entries) != maxErrorHistory {
+		t.Errorf("kept %d entries, want %d", len(h.entries), maxErrorHistory)
+	}
+	if got := h.entries[0].err.Error(); got != "error 0" {
+		t.Errorf("oldest = %q, want the two earliest dropped", got)
+	}
+	if got := h.unseen(); got != maxErrorHistory {
+		t.Errorf("unseen after dropping = %d, want %d", got, maxErrorHistory)
+	}
+}
@@ -0,0 +1,341 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+const (
+	// groupMatrixCellWidth caps the width of a topic column in the assignment
+	// matrix
+	groupMatrixCellWidth = 24
+	// groupDetailPollInterval is how often the group is described again to
+	// catch rebalances
+	groupDetailPollInterval = 5 * time.Second
+	// groupRebalanceHistory is how many observed rebalances are kept
+	groupRebalanceHistory = 10
+)
+
+// observedRebalance is a membership change seen between two polls
+type observedRebalance struct {
+	at     time.Time
+	reason string
+}
+
+// GroupDetailModel shows which partitions each member of a consumer group
+// was assigned, and the rebalances seen while it is open
+type GroupDetailModel struct {
+	client     *kafka.Client
+	groupID    string
+	group      *kafka.GroupDescription
+	rebalances []observedRebalance // Newest first
+	loading    bool
+	err        error
+	viewport   viewport.Model
+	width      int
+	height     int
+}
+
+func NewGroupDetailModel(client *kafka.Client, groupID string, width, height int) GroupDetailModel {
+	m := GroupDetailModel{
+		client:   client,
+		groupID:  groupID,
+		loading:  true,
+		viewport: viewport.New(100, 20),
+	}
+	m.resize(width, height)
+	return m
+}
+
+type groupDetailMsg struct {
+	group *kafka.GroupDescription
+	err   error
+}
+
+type groupDetailPollMsg struct{}
+
+func fetchGroupDetail(client *kafka.Client, groupID string) tea.Cmd {
+	return func() tea.Msg {
+		group, err := client.DescribeGroup(groupID)
+		return groupDetailMsg{group: group, err: err}
+	}
+}
+
+func groupDetailPoll() tea.Cmd {
+	return tea.Tick(groupDetailPollInterval, func(time.Time) tea.Msg {
+		return groupDetailPollMsg{}
+	})
+}
+
+func (m GroupDetailModel) Init() tea.Cmd {
+	return tea.Batch(fetchGroupDetail(m.client, m.groupID), groupDetailPoll())
+}
+
+func (m GroupDetailModel) Update(msg tea.Msg) (GroupDetailModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case groupDetailMsg:
+		m.loading = false
+		m.err = msg.err
+		if msg.err == nil {
+			m.observe(msg.group)
+			m.group = msg.group
+		}
+		m.refresh()
+		return m, nil
+	case groupDetailPollMsg:
+		return m, tea.Batch(fetchGroupDetail(m.client, m.groupID), groupDetailPoll())
+	case tea.WindowSizeMsg:
+		m.resize(msg.Width, msg.Height)
+		m.refresh()
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			return m, ReturnToListView
+		case "r":
+			m.loading = true
+			return m, fetchGroupDetail(m.client, m.groupID)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+func (m *GroupDetailModel) resize(width, height int) {
+	m.width, m.height = width, height
+	if width > 4 {
+		m.viewport.Width = width - 4
+	}
+	if height > 11 {
+		m.viewport.Height = height - 11
+	}
+}
+
+// observe records a rebalance when the group's membership or generation
+// changed since the last poll
+func (m *GroupDetailModel) observe(group *kafka.GroupDescription) {
+	if m.group == nil {
+		return
+	}
+	reason, ok := group.RebalanceReason(m.group)
+	if !ok {
+		return
+	}
+	m.rebalances = append([]observedRebalance{{at: time.Now(), reason: reason}}, m.rebalances...)
+	if len(m.rebalances) > groupRebalanceHistory {
+		m.rebalances = m.rebalances[:groupRebalanceHistory]
+	}
+}
+
+func (m *GroupDetailModel) refresh() {
+	if m.group != nil {
+		m.viewport.SetContent(renderAssignmentMatrix(m.group) + "\n\n" +
+			renderGroupMembers(m.group) + "\n\n" + renderRebalances(m.rebalances))
+	}
+}
+
+// formatPartitionRanges lists sorted partitions with runs collapsed, such
+// as 0-3,7
+func formatPartitionRanges(partitions []int32) string {
+	var parts []string
+	for i := 0; i < len(partitions); {
+		j := i
+		for j+1 < len(partitions) && partitions[j+1] == partitions[j]+1 {
+			j++
+		}
+		if j > i {
+			parts = append(parts, fmt.Sprintf("%d-%d", partitions[i], partitions[j]))
+		} else {
+			parts = append(parts, strconv.Itoa(int(partitions[i])))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// renderAssignmentMatrix draws a row per member and a column per topic,
+// each cell holding the partitions of the topic the member was assigned.
+// Members without partitions are shown in red, and the busiest members of
+// an unevenly spread topic in orange.
+func renderAssignmentMatrix(g *kafka.GroupDescription) string {
+	if len(g.Members) == 0 {
+		return "The group has no active members."
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	topics := g.Topics()
+	busiest := make(map[string]int, len(topics))
+	widths := make([]int, len(topics))
+	for i, topic := range topics {
+		widths[i] = len(topic)
+		if g.Unbalanced(topic) {
+			widths[i] += 2
+		}
+		for _, member := range g.Members {
+			busiest[topic] = max(busiest[topic], len(member.Assigned[topic]))
+			widths[i] = max(widths[i], len(formatPartitionRanges(member.Assigned[topic])))
+		}
+		widths[i] = min(widths[i], groupMatrixCellWidth)
+	}
+
+	memberWidth := 0
+	names := make([]string, len(g.Members))
+	for i, member := range g.Members {
+		names[i] = truncateString(member.ClientID+" "+member.ClientHost, 40)
+		memberWidth = max(memberWidth, len(names[i]))
+	}
+
+	var sb strings.Builder
+	header := fmt.Sprintf("%-*s  %5s", memberWidth, "Member", "Total")
+	for i, topic := range topics {
+		if g.Unbalanced(topic) {
+			topic = "⚠ " + topic
+		}
+		header += fmt.Sprintf("  %-*s", widths[i], truncateString(topic, widths[i]))
+	}
+	sb.WriteString(headerStyle.Render(header))
+	sb.WriteString("\n")
+
+	for i, member := range g.Members {
+		total := member.PartitionCount()
+		if total == 0 {
+			sb.WriteString(errorStyle.Render(fmt.Sprintf("%-*s  %5d  no partitions", memberWidth, names[i], 0)))
+			sb.WriteString("\n")
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%-*s  %5d", memberWidth, names[i], total))
+		for j, topic := range topics {
+			cell := fmt.Sprintf("%-*s", widths[j], truncateString(formatPartitionRanges(member.Assigned[topic]), widths[j]))
+			if g.Unbalanced(topic) && len(member.Assigned[topic]) == busiest[topic] {
+				cell = warnStyle.Render(cell)
+			}
+			sb.WriteString("  " + cell)
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(dimStyle.Render("⚠ marks topics where one member holds two or more partitions more than another"))
+	return sb.String()
+}
+
+// renderGroupMembers lists how each member joined: its static instance ID,
+// the generation it joined in and its rack
+func renderGroupMembers(g *kafka.GroupDescription) string {
+	if len(g.Members) == 0 {
+		return ""
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	var sb strings.Builder
+	sb.WriteString(headerStyle.Render(fmt.Sprintf("%-40s  %-24s  %10s  %-10s  %s",
+		"Member ID", "Instance ID", "Generation", "Rack", "Client")))
+	sb.WriteString("\n")
+	for _, member := range g.Members {
+		instance := dimStyle.Render(fmt.Sprintf("%-24s", "dynamic"))
+		if member.Static() {
+			instance = fmt.Sprintf("%-24s", truncateString(member.InstanceID, 24))
+		}
+		generation := "-"
+		if member.Generation >= 0 {
+			generation = strconv.Itoa(int(member.Generation))
+		}
+		rack := member.Rack
+		if rack == "" {
+			rack = "-"
+		}
+		sb.WriteString(fmt.Sprintf("%-40s  %s  %10s  %-10s  %s\n", truncateString(member.MemberID, 40),
+			instance, generation, truncateString(rack, 10), member.ClientID+" "+member.ClientHost))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// renderRebalances lists the rebalances seen since the view was opened.
+// Kafka keeps no rebalance history, so nothing before that is known.
+func renderRebalances(rebalances []observedRebalance) string {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	var sb strings.Builder
+	sb.WriteString(headerStyle.Render("Rebalances seen"))
+	sb.WriteString("\n")
+	if len(rebalances) == 0 {
+		sb.WriteString(dimStyle.Render(fmt.Sprintf("None since the view was opened; the group is polled every %s", groupDetailPollInterval)))
+		return sb.String()
+	}
+	for _, r := range rebalances {
+		sb.WriteString(fmt.Sprintf("%s  %s\n", r.at.Format("15:04:05"), r.reason))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// formatGroupTimeouts names the coordinator and describes its session
+// timeout bounds
+func formatGroupTimeouts(g *kafka.GroupDescription) string {
+	if g.Timeouts == nil {
+		return fmt.Sprintf("Coordinator %s, session timeout bounds unavailable", g.CoordinatorLabel())
+	}
+	return fmt.Sprintf("Coordinator %s allows session timeouts of %s to %s, initial rebalance delay %s",
+		g.CoordinatorLabel(), g.Timeouts.MinSession, g.Timeouts.MaxSession, g.Timeouts.InitialRebalanceDelay)
+}
+
+func (m GroupDetailModel) View() string {
+	var s strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Padding(0, 1)
+	s.WriteString(titleStyle.Render("👥 Group " + m.groupID))
+	s.WriteString("\n\n")
+
+	switch {
+	case m.loading && m.group == nil:
+		s.WriteString("Loading group members...")
+	case m.err != nil:
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(fmt.Sprintf("Error: %v", m.err)))
+	default:
+		g := m.group
+		assignor := g.Assignor
+		if assignor == "" {
+			assignor = "-"
+		}
+		static := 0
+		for _, member := range g.Members {
+			if member.Static() {
+				static++
+			}
+		}
+		state := g.State
+		if state == "PreparingRebalance" || state == "CompletingRebalance" {
+			state = lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Bold(true).Render(state)
+		}
+		s.WriteString(fmt.Sprintf("State %s, %d members (%d static), assignor %s", state, len(g.Members), static, assignor))
+		if generation := g.Generation(); generation >= 0 {
+			s.WriteString(fmt.Sprintf(", generation %d", generation))
+		}
+		s.WriteString("\n")
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(formatGroupTimeouts(g)))
+		s.WriteString("\n\n")
+		s.WriteString(m.viewport.View())
+	}
+	s.WriteString("\n\n")
+	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("↑/↓: Scroll | r: Refresh | Esc: Back"))
+	return s.String()
+}
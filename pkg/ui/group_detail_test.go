@@ -0,0 +1,20 @@
+package ui
+
+import "testing"
+
+func TestFormatPartitionRanges(t *testing.T) {
+	tests := []struct {
+		partitions []int32
+		want       string
+	}{
+		{nil, ""},
+		{[]int32{4}, "4"},
+		{[]int32{0, 1, 2, 3}, "0-3"},
+		{[]int32{0, 1, 2, 7, 9, 10}, "0-2,7,9-10"},
+	}
+	for _, tt := range tests {
+		if got := formatPartitionRanges(tt.partitions); got != tt.want {
+			t.Errorf("formatPartitionRanges(%v) = %q, want %q", tt.partitions, got, tt.want)
+		}
+	}
+}
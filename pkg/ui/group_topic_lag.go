@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+// topicLagPrefix marks the rows listing an expanded group's lag per topic
+const topicLagPrefix = "  └ "
+
+// groupTopicLagMsg carries the per-topic lag of an expanded group
+type groupTopicLagMsg struct {
+	groupID string
+	topics  []kafka.TopicLag
+	err     error
+}
+
+func fetchGroupTopicLag(client *kafka.Client, groupID string) tea.Cmd {
+	return func() tea.Msg {
+		partitions, err := client.GetGroupLag(groupID)
+		if err != nil {
+			return groupTopicLagMsg{groupID: groupID, err: err}
+		}
+		return groupTopicLagMsg{groupID: groupID, topics: kafka.TopicLags(partitions)}
+	}
+}
+
+// topicLagRows builds the rows shown under an expanded group, one per topic
+// it has committed offsets for. A nil topics is still being fetched.
+func topicLagRows(topics []kafka.TopicLag) []table.Row {
+	if topics == nil {
+		return []table.Row{{topicLagPrefix + "loading...", "", "", "", "", "", ""}}
+	}
+	if len(topics) == 0 {
+		return []table.Row{{topicLagPrefix + "no committed offsets", "", "", "", "", "", ""}}
+	}
+	rows := make([]table.Row, len(topics))
+	for i, t := range topics {
+		lag := fmt.Sprintf("%d", t.Lag)
+		if t.Unknown > 0 {
+			lag += fmt.Sprintf(" (+%d ?)", t.Unknown)
+		}
+		rows[i] = table.Row{
+			topicLagPrefix + t.Topic,
+			"",
+			fmt.Sprintf("%d part.", t.Partitions),
+			lag,
+			"",
+			"",
+			"",
+		}
+	}
+	return rows
+}
+
+// consumerRowKey identifies a Consumer Groups row by its group and first
+// cell, as the same topic can be listed under several expanded groups
+func consumerRowKey(group string, row table.Row) string {
+	return group + "\x00" + firstColumn(row)
+}
+
+// selectedGroup returns the group of the selected Consumer Groups row, the
+// owning group for a topic row
+func (m Model) selectedGroup() (string, bool) {
+	cursor := m.consumersTable.Cursor()
+	if cursor < 0 || cursor >= len(m.groupRowOwners) {
+		return "", false
+	}
+	return m.groupRowOwners[cursor], true
+}
+
+// toggleGroupTopics expands or collapses the per-topic lag of the selected
+// group, fetching it when expanding
+func (m *Model) toggleGroupTopics(expand bool) tea.Cmd {
+	groupID, ok := m.selectedGroup()
+	if !ok {
+		return nil
+	}
+	_, expanded := m.groupTopicLags[groupID]
+	switch {
+	case expand && !expanded:
+		if m.groupTopicLags == nil {
+			m.groupTopicLags = map[string][]kafka.TopicLag{}
+		}
+		m.groupTopicLags[groupID] = nil
+		m.refreshConsumerGroupRows()
+		return fetchGroupTopicLag(m.client, groupID)
+	case !expand && expanded:
+		delete(m.groupTopicLags, groupID)
+		// Keep the cursor on the group, not a topic row that is now gone
+		m.selectConsumerRow(consumerRowKey(groupID, table.Row{groupID}))
+	}
+	return nil
+}
+
+// refreshGroupTopicLags refetches the per-topic lag of every expanded group
+func (m Model) refreshGroupTopicLags() tea.Cmd {
+	var cmds []tea.Cmd
+	for groupID := range m.groupTopicLags {
+		cmds = append(cmds, fetchGroupTopicLag(m.client, groupID))
+	}
+	return tea.Batch(cmds...)
+}
+
+// refreshConsumerGroupRows rebuilds the Consumer Groups table and keeps the
+// cursor on the selected group or topic row
+func (m *Model) refreshConsumerGroupRows() {
+	selected := ""
+	if groupID, ok := m.selectedGroup(); ok {
+		selected = consumerRowKey(groupID, m.consumersTable.SelectedRow())
+	}
+	m.selectConsumerRow(selected)
+}
+
+// selectConsumerRow rebuilds the Consumer Groups table and moves the cursor
+// to the row with the given key, if it is still there
+func (m *Model) selectConsumerRow(key string) {
+	rows, groups := m.consumerGroupRows()
+	m.consumersTable.SetRows(rows)
+	m.groupRowOwners = groups
+	for i, row := range rows {
+		if consumerRowKey(groups[i], row) == key {
+			moveCursor(&m.consumersTable, i)
+			return
+		}
+	}
+	if len(rows) > 0 {
+		moveCursor(&m.consumersTable, min(m.consumersTable.Cursor(), len(rows)-1))
+	}
+}
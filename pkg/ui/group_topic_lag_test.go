@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+func TestGroupTopicRows(t *testing.T) {
+	columns := make([]table.Column, 7)
+	for i := range columns {
+		columns[i] = table.Column{Title: "C", Width: 10}
+	}
+	m := Model{
+		consumersTable: table.New(table.WithColumns(columns), table.WithHeight(10)),
+		consumerGroups: []kafka.ConsumerGroupInfo{{GroupID: "a"}, {GroupID: "b"}, {GroupID: "c"}},
+		lagHistory:     newLagHistory(),
+	}
+	m.refreshConsumerGroupRows()
+	m.consumersTable.SetCursor(1)
+
+	if cmd := m.toggleGroupTopics(true); cmd == nil {
+		t.Fatal("expanding a group should fetch its topic lag")
+	}
+	if got := len(m.consumersTable.Rows()); got != 4 {
+		t.Fatalf("got %d rows while loading, want 4", got)
+	}
+
+	m.groupTopicLags["b"] = []kafka.TopicLag{
+		{Topic: "orders", Partitions: 3, Lag: 2000000, Unknown: 1},
+		{Topic: "audit", Partitions: 1, Lag: 5},
+	}
+	m.refreshConsumerGroupRows()
+	rows := m.consumersTable.Rows()
+	wantFirst := []string{"a", "b", topicLagPrefix + "orders", topicLagPrefix + "audit", "c"}
+	if len(rows) != len(wantFirst) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(wantFirst))
+	}
+	for i, want := range wantFirst {
+		if rows[i][0] != want {
+			t.Errorf("row %d = %q, want %q", i, rows[i][0], want)
+		}
+	}
+	if rows[2][3] != "2000000 (+1 ?)" {
+		t.Errorf("orders lag = %q", rows[2][3])
+	}
+
+	// Topic rows act for their group
+	m.consumersTable.SetCursor(3)
+	if group, _ := m.selectedGroup(); group != "b" {
+		t.Errorf("selected group of a topic row = %q, want b", group)
+	}
+
+	m.toggleGroupTopics(false)
+	if got := len(m.consumersTable.Rows()); got != 3 {
+		t.Fatalf("got %d rows after collapsing, want 3", got)
+	}
+	if row := m.consumersTable.SelectedRow(); row[0] != "b" {
+		t.Errorf("selected %q after collapsing, want b", row[0])
+	}
+}
@@ -0,0 +1,334 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+const (
+	// groupWatchInterval is how often a watched group's lag is sampled
+	groupWatchInterval = 5 * time.Second
+	// groupWatchHistory is how many total lag samples the chart keeps
+	groupWatchHistory = 240
+	// groupWatchChartHeight is the number of rows of the lag chart
+	groupWatchChartHeight = 8
+	// groupWatchRateWindow is how many recent samples the consumption rate
+	// is measured over
+	groupWatchRateWindow = 12
+)
+
+// lagSample is a group's total lag at one point in time
+type lagSample struct {
+	at  time.Time
+	lag int64
+}
+
+// GroupWatchModel samples one consumer group's per-partition lag every few
+// seconds and charts the total over time, to follow a backfill or an
+// incident live
+type GroupWatchModel struct {
+	client     *kafka.Client
+	groupID    string
+	partitions []kafka.PartitionLag
+	samples    []lagSample
+	history    map[string][]int64 // Recent lag of each partition, by topic/partition
+	loading    bool
+	err        error
+	viewport   viewport.Model
+	width      int
+	height     int
+}
+
+func NewGroupWatchModel(client *kafka.Client, groupID string, width, height int) GroupWatchModel {
+	m := GroupWatchModel{
+		client:   client,
+		groupID:  groupID,
+		history:  make(map[string][]int64),
+		loading:  true,
+		viewport: viewport.New(100, 20),
+	}
+	m.resize(width, height)
+	return m
+}
+
+type groupLagMsg struct {
+	partitions []kafka.PartitionLag
+	at         time.Time
+	err        error
+}
+
+type groupWatchTickMsg struct{}
+
+func fetchGroupLag(client *kafka.Client, groupID string) tea.Cmd {
+	return func() tea.Msg {
+		partitions, err := client.GetGroupLag(groupID)
+		return groupLagMsg{partitions: partitions, at: time.Now(), err: err}
+	}
+}
+
+func groupWatchTick() tea.Cmd {
+	return tea.Tick(groupWatchInterval, func(time.Time) tea.Msg {
+		return groupWatchTickMsg{}
+	})
+}
+
+func (m GroupWatchModel) Init() tea.Cmd {
+	return tea.Batch(fetchGroupLag(m.client, m.groupID), groupWatchTick())
+}
+
+func (m GroupWatchModel) Update(msg tea.Msg) (GroupWatchModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case groupLagMsg:
+		m.loading = false
+		m.err = msg.err
+		if msg.err != nil {
+			return m, reportError("group watch", msg.err)
+		}
+		m.record(msg.partitions, msg.at)
+		m.refresh()
+		return m, nil
+	case groupWatchTickMsg:
+		return m, tea.Batch(fetchGroupLag(m.client, m.groupID), groupWatchTick())
+	case tea.WindowSizeMsg:
+		m.resize(msg.Width, msg.Height)
+		m.refresh()
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			return m, ReturnToListView
+		case "r":
+			m.loading = true
+			return m, fetchGroupLag(m.client, m.groupID)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// record keeps a sample of the total lag and of each partition's lag
+func (m *GroupWatchModel) record(partitions []kafka.PartitionLag, at time.Time) {
+	m.partitions = partitions
+	m.samples = append(m.samples, lagSample{at: at, lag: totalLag(partitions)})
+	if len(m.samples) > groupWatchHistory {
+		m.samples = m.samples[len(m.samples)-groupWatchHistory:]
+	}
+	for _, p := range partitions {
+		if p.Lag() < 0 {
+			continue
+		}
+		key := partitionKey(p)
+		s := append(m.history[key], p.Lag())
+		if len(s) > lagHistorySize {
+			s = s[len(s)-lagHistorySize:]
+		}
+		m.history[key] = s
+	}
+}
+
+func partitionKey(p kafka.PartitionLag) string {
+	return p.Topic + "/" + strconv.Itoa(int(p.Partition))
+}
+
+// totalLag sums the lag of the partitions whose lag is known
+func totalLag(partitions []kafka.PartitionLag) int64 {
+	var total int64
+	for _, p := range partitions {
+		if lag := p.Lag(); lag > 0 {
+			total += lag
+		}
+	}
+	return total
+}
+
+// lagRate returns how fast the lag changed, in messages per second, over
+// the last groupWatchRateWindow samples. Negative means the group is
+// catching up.
+func lagRate(samples []lagSample) (float64, bool) {
+	if len(samples) < 2 {
+		return 0, false
+	}
+	first := samples[max(len(samples)-groupWatchRateWindow, 0)]
+	last := samples[len(samples)-1]
+	elapsed := last.at.Sub(first.at).Seconds()
+	if elapsed <= 0 {
+		return 0, false
+	}
+	return float64(last.lag-first.lag) / elapsed, true
+}
+
+func (m *GroupWatchModel) resize(width, height int) {
+	m.width, m.height = width, height
+	if width > 4 {
+		m.viewport.Width = width - 4
+	}
+	if height > 10 {
+		m.viewport.Height = height - 10
+	}
+}
+
+func (m *GroupWatchModel) refresh() {
+	if len(m.samples) == 0 {
+		return
+	}
+	values := make([]int64, len(m.samples))
+	for i, s := range m.samples {
+		values[i] = s.lag
+	}
+	m.viewport.SetContent(renderLagChart(values, m.viewport.Width, groupWatchChartHeight) + "\n\n" +
+		renderPartitionLag(m.partitions, m.history))
+}
+
+// renderLagChart draws values as vertical bars scaled from zero to their
+// maximum, the newest on the right, with as many as fit in width
+func renderLagChart(values []int64, width, height int) string {
+	var peak int64
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+	label := strconv.FormatInt(peak, 10)
+	if columns := width - len(label) - 2; columns > 0 && len(values) > columns {
+		values = values[len(values)-columns:]
+	}
+
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	barStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+
+	var sb strings.Builder
+	for row := 0; row < height; row++ {
+		axis := ""
+		switch row {
+		case 0:
+			axis = label
+		case height - 1:
+			axis = "0"
+		}
+		sb.WriteString(dimStyle.Render(fmt.Sprintf("%*s │", len(label), axis)))
+
+		// Each row covers eight eighths of a block, counted from the bottom
+		base := int64(height-1-row) * 8
+		var bars strings.Builder
+		for _, v := range values {
+			var fill int64
+			if peak > 0 {
+				fill = v * int64(height) * 8 / peak
+			}
+			switch {
+			case fill >= base+8:
+				bars.WriteRune(sparkBlocks[len(sparkBlocks)-1])
+			case fill > base:
+				bars.WriteRune(sparkBlocks[fill-base-1])
+			default:
+				bars.WriteRune(' ')
+			}
+		}
+		sb.WriteString(barStyle.Render(bars.String()))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(dimStyle.Render(fmt.Sprintf("%*s └%s", len(label), "", strings.Repeat("─", len(values)))))
+	return sb.String()
+}
+
+// renderPartitionLag lists each partition's offsets and lag with a
+// sparkline of its recent lag
+func renderPartitionLag(partitions []kafka.PartitionLag, history map[string][]int64) string {
+	if len(partitions) == 0 {
+		return "The group has no committed offsets."
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+
+	topicWidth := len("Topic")
+	for _, p := range partitions {
+		topicWidth = max(topicWidth, min(len(p.Topic), 40))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(headerStyle.Render(fmt.Sprintf("%-*s  %9s  %12s  %12s  %10s  %s",
+		topicWidth, "Topic", "Partition", "Committed", "Log end", "Lag", "Trend")))
+	for _, p := range partitions {
+		committed, end, lag := "-", "-", "-"
+		if p.Committed >= 0 {
+			committed = strconv.FormatInt(p.Committed, 10)
+		}
+		if p.LogEnd >= 0 {
+			end = strconv.FormatInt(p.LogEnd, 10)
+		}
+		if p.Lag() >= 0 {
+			lag = strconv.FormatInt(p.Lag(), 10)
+		}
+		s := history[partitionKey(p)]
+		line := fmt.Sprintf("%-*s  %9d  %12s  %12s  %10s  %s", topicWidth, truncateString(p.Topic, topicWidth),
+			p.Partition, committed, end, lag, sparkline(s)+" "+trendArrow(s))
+		// Growing lag is what to look at during an incident
+		if trendArrow(s) == "↑" {
+			line = warnStyle.Render(line)
+		}
+		sb.WriteString("\n" + line)
+	}
+	return sb.String()
+}
+
+// formatLagRate describes the lag trend and, while it shrinks, when the
+// group should catch up
+func formatLagRate(samples []lagSample) string {
+	rate, ok := lagRate(samples)
+	if !ok {
+		return "measuring rate..."
+	}
+	switch {
+	case rate < 0:
+		s := fmt.Sprintf("↓ catching up at %.1f msg/s", -rate)
+		if lag := samples[len(samples)-1].lag; lag > 0 {
+			eta := time.Duration(float64(lag) / -rate * float64(time.Second)).Round(time.Second)
+			s += fmt.Sprintf(", caught up in ~%s", eta)
+		}
+		return s
+	case rate > 0:
+		return fmt.Sprintf("↑ falling behind at %.1f msg/s", rate)
+	default:
+		return "→ steady"
+	}
+}
+
+func (m GroupWatchModel) View() string {
+	var s strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Padding(0, 1)
+	s.WriteString(titleStyle.Render("📈 Watching " + m.groupID))
+	s.WriteString("\n\n")
+
+	switch {
+	case m.loading && len(m.samples) == 0:
+		s.WriteString("Loading lag...")
+	case m.err != nil && len(m.samples) == 0:
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(fmt.Sprintf("Error: %v", m.err)))
+	default:
+		last := m.samples[len(m.samples)-1]
+		s.WriteString(fmt.Sprintf("Total lag %d across %d partitions  %s", last.lag, len(m.partitions), formatLagRate(m.samples)))
+		dim := fmt.Sprintf("  (sampled every %s, last at %s)", groupWatchInterval, last.at.Format("15:04:05"))
+		if m.err != nil {
+			dim = fmt.Sprintf("  (last sample failed: %v)", m.err)
+		}
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(dim))
+		s.WriteString("\n\n")
+		s.WriteString(m.viewport.View())
+	}
+	s.WriteString("\n\n")
+	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("↑/↓: Scroll | r: Sample now | Esc: Back"))
+	return s.String()
+}
@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatLagRate(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	samples := func(lags ...int64) []lagSample {
+		s := make([]lagSample, len(lags))
+		for i, lag := range lags {
+			s[i] = lagSample{at: start.Add(time.Duration(i) * 10 * time.Second), lag: lag}
+		}
+		return s
+	}
+
+	tests := []struct {
+		name     string
+		samples  []lagSample
+		expected string
+	}{
+		{"single sample", samples(100), "measuring rate..."},
+		{"draining", samples(1000, 800, 600), "↓ catching up at 20.0 msg/s, caught up in ~30s"},
+		{"drained", samples(100, 0), "↓ catching up at 10.0 msg/s"},
+		{"growing", samples(0, 50), "↑ falling behind at 5.0 msg/s"},
+		{"steady", samples(10, 10), "→ steady"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := formatLagRate(tt.samples); result != tt.expected {
+				t.Errorf("formatLagRate() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
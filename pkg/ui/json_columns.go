@@ -0,0 +1,268 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// fieldSampleSize is how many of the most recent JSON values are inspected
+// when inferring fields
+const fieldSampleSize = 500
+
+// maxFieldDepth stops nested objects from flooding the field list, deeper
+// objects are offered as a single column holding their JSON
+const maxFieldDepth = 3
+
+// jsonField is a field path seen in sampled JSON values
+type jsonField struct {
+	Path  string
+	Count int
+	// Types lists the JSON types seen at Path, e.g. "string" or "number"
+	Types []string
+}
+
+// inferJSONFields collects the leaf fields of the last sample JSON object
+// payloads, most common first. It also returns how many payloads were JSON
+// objects so the counts can be shown as a share of the sample.
+func inferJSONFields(payloads []decodedPayload, sample int) ([]jsonField, int) {
+	start := max(len(payloads)-sample, 0)
+	fields := map[string]*jsonField{}
+	objects := 0
+	for _, p := range payloads[start:] {
+		if p.Kind != PayloadJSON && p.Kind != PayloadJSONSchema {
+			continue
+		}
+		var doc map[string]any
+		if err := json.Unmarshal([]byte(p.Body), &doc); err != nil {
+			continue
+		}
+		objects++
+		seen := map[string]bool{}
+		walkJSONFields("", doc, 1, func(path string, v any) {
+			if seen[path] {
+				return
+			}
+			seen[path] = true
+			f, ok := fields[path]
+			if !ok {
+				f = &jsonField{Path: path}
+				fields[path] = f
+			}
+			f.Count++
+			if t := jsonTypeName(v); !slices.Contains(f.Types, t) {
+				f.Types = append(f.Types, t)
+			}
+		})
+	}
+
+	result := make([]jsonField, 0, len(fields))
+	for _, f := range fields {
+		sort.Strings(f.Types)
+		result = append(result, *f)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Path < result[j].Path
+	})
+	return result, objects
+}
+
+// walkJSONFields calls fn for every leaf under obj with its jq style path
+func walkJSONFields(prefix string, obj map[string]any, depth int, fn func(path string, v any)) {
+	for k, v := range obj {
+		path := prefix + "." + k
+		if nested, ok := v.(map[string]any); ok && depth < maxFieldDepth && len(nested) > 0 {
+			walkJSONFields(path, nested, depth+1, fn)
+			continue
+		}
+		fn(path, v)
+	}
+}
+
+func jsonTypeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	}
+	return "object"
+}
+
+// extractJSONFields returns the values at paths in a JSON payload, one per
+// path, empty where the field is missing or the payload is not a JSON object
+func extractJSONFields(p decodedPayload, paths []string) []string {
+	values := make([]string, len(paths))
+	if p.Kind != PayloadJSON && p.Kind != PayloadJSONSchema {
+		return values
+	}
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(p.Body), &doc); err != nil {
+		return values
+	}
+	for i, path := range paths {
+		if v, ok := lookupJSONPath(doc, path); ok {
+			values[i] = formatJSONValue(v)
+		}
+	}
+	return values
+}
+
+// lookupJSONPath resolves a ".a.b" path against a decoded object
+func lookupJSONPath(doc map[string]any, path string) (any, bool) {
+	var cur any = doc
+	for _, part := range strings.Split(strings.TrimPrefix(path, "."), ".") {
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = obj[part]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// formatJSONValue renders strings unquoted and everything else as JSON
+func formatJSONValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// JSONColumnsModel picks which inferred JSON fields the consumer shows as
+// table columns in place of the raw value
+type JSONColumnsModel struct {
+	fields   []jsonField
+	sampled  int
+	selected map[string]bool
+	cursor   int
+	offset   int
+	height   int
+}
+
+// jsonColumnsChosenMsg carries the picked field paths, empty restores the
+// raw value column
+type jsonColumnsChosenMsg struct {
+	paths []string
+}
+
+func NewJSONColumnsModel(payloads []decodedPayload, current []string) JSONColumnsModel {
+	fields, sampled := inferJSONFields(payloads, fieldSampleSize)
+	selected := make(map[string]bool, len(current))
+	for _, path := range current {
+		selected[path] = true
+	}
+	return JSONColumnsModel{fields: fields, sampled: sampled, selected: selected, height: 15}
+}
+
+// chosen returns the selected paths in field list order
+func (m JSONColumnsModel) chosen() []string {
+	var paths []string
+	for _, f := range m.fields {
+		if m.selected[f.Path] {
+			paths = append(paths, f.Path)
+		}
+	}
+	return paths
+}
+
+func (m JSONColumnsModel) Update(msg tea.Msg) (JSONColumnsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.fields)-1 {
+				m.cursor++
+			}
+		case " ":
+			if m.cursor < len(m.fields) {
+				path := m.fields[m.cursor].Path
+				m.selected[path] = !m.selected[path]
+			}
+		case "c":
+			m.selected = map[string]bool{}
+		case "enter":
+			paths := m.chosen()
+			return m, func() tea.Msg { return jsonColumnsChosenMsg{paths: paths} }
+		}
+	case tea.WindowSizeMsg:
+		m.height = max(msg.Height-12, 5)
+	}
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if m.cursor >= m.offset+m.height {
+		m.offset = m.cursor - m.height + 1
+	}
+	return m, nil
+}
+
+func (m JSONColumnsModel) View() string {
+	var sb strings.Builder
+
+	labelStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("86"))
+
+	focusedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("205"))
+
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	sb.WriteString(labelStyle.Render("🧩 JSON Columns") + "\n\n")
+
+	if len(m.fields) == 0 {
+		sb.WriteString(dimStyle.Render("No JSON object values received yet.") + "\n\n")
+	} else {
+		sb.WriteString(dimStyle.Render(fmt.Sprintf("Fields seen in the last %d JSON value(s), most common first:", m.sampled)) + "\n\n")
+		end := min(m.offset+m.height, len(m.fields))
+		for i := m.offset; i < end; i++ {
+			f := m.fields[i]
+			check := "[ ]"
+			if m.selected[f.Path] {
+				check = "[x]"
+			}
+			line := fmt.Sprintf("%s %-30s %3d%%  %s", check, f.Path, f.Count*100/m.sampled, strings.Join(f.Types, "|"))
+			if i == m.cursor {
+				sb.WriteString(focusedStyle.Render("> "+line) + "\n")
+			} else {
+				sb.WriteString("  " + line + "\n")
+			}
+		}
+		if len(m.fields) > m.height {
+			sb.WriteString(dimStyle.Render(fmt.Sprintf("  %d-%d of %d", m.offset+1, end, len(m.fields))) + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Italic(true)
+	sb.WriteString(helpStyle.Render("↑/↓: Select • Space: Toggle column • c: Clear • Enter: Apply • Esc: Cancel"))
+
+	return sb.String()
+}
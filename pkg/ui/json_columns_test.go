@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInferJSONFields(t *testing.T) {
+	payloads := []decodedPayload{
+		detectPayload(`{"status":"ok","amount":10,"customer":{"id":"c1"}}`),
+		detectPayload(`{"status":"failed","amount":2.5}`),
+		detectPayload("not json"),
+		detectPayload(`{"status":null}`),
+		detectPayload(`[1,2,3]`),
+	}
+
+	fields, sampled := inferJSONFields(payloads, fieldSampleSize)
+	if sampled != 3 {
+		t.Fatalf("sampled = %d, want 3", sampled)
+	}
+	want := []jsonField{
+		{Path: ".status", Count: 3, Types: []string{"null", "string"}},
+		{Path: ".amount", Count: 2, Types: []string{"number"}},
+		{Path: ".customer.id", Count: 1, Types: []string{"string"}},
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("fields = %+v, want %+v", fields, want)
+	}
+
+	// Only the most recent payloads are sampled
+	if _, sampled := inferJSONFields(payloads, 2); sampled != 1 {
+		t.Errorf("sampled with limit 2 = %d, want 1", sampled)
+	}
+}
+
+func TestInferJSONFieldsDepth(t *testing.T) {
+	fields, _ := inferJSONFields([]decodedPayload{detectPayload(`{"a":{"b":{"c":{"d":1}}}}`)}, fieldSampleSize)
+	if len(fields) != 1 || fields[0].Path != ".a.b.c" || fields[0].Types[0] != "object" {
+		t.Errorf("fields = %+v, want .a.b.c as an object", fields)
+	}
+}
+
+func TestExtractJSONFields(t *testing.T) {
+	p := detectPayload(`{"status":"ok","amount":10.5,"customer":{"id":"c1"},"tags":["a"]}`)
+	got := extractJSONFields(p, []string{".status", ".amount", ".customer.id", ".tags", ".missing", ".status.nested"})
+	want := []string{"ok", "10.5", "c1", `["a"]`, "", ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extractJSONFields = %q, want %q", got, want)
+	}
+
+	if got := extractJSONFields(detectPayload("text"), []string{".status"}); got[0] != "" {
+		t.Errorf("non-JSON payload gave %q", got[0])
+	}
+}
@@ -0,0 +1,284 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+const (
+	keyLookupKeyField = iota
+	keyLookupFromField
+	keyLookupToField
+	keyLookupFieldCount
+)
+
+// KeyLookupModel scans a topic for every record with a given key, optionally
+// between two timestamps, and lists them in offset order
+type KeyLookupModel struct {
+	client   *kafka.Client
+	topic    string
+	inputs   []textinput.Model
+	focus    int
+	cancel   context.CancelFunc
+	events   chan tea.Msg
+	scanning bool
+	progress kafka.KeyLookupProgress
+	result   *kafka.KeyLookupResult
+	key      string
+	err      error
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+// Lookup messages carry their events channel, so those of a stopped scan
+// are told apart from the current one
+type keyLookupProgressMsg struct {
+	events   chan tea.Msg
+	progress kafka.KeyLookupProgress
+}
+
+type keyLookupDoneMsg struct {
+	events chan tea.Msg
+	result *kafka.KeyLookupResult
+	err    error
+}
+
+func NewKeyLookupModel(client *kafka.Client, topic string, width, height int) KeyLookupModel {
+	key := textinput.New()
+	key.Placeholder = "Exact message key"
+	key.CharLimit = 1000
+	key.Focus()
+
+	from := textinput.New()
+	from.Placeholder = "Topic start (e.g. -24h, 2024-01-31 09:00:00)"
+	from.CharLimit = 40
+
+	to := textinput.New()
+	to.Placeholder = "Topic end (e.g. now, -1h)"
+	to.CharLimit = 40
+
+	m := KeyLookupModel{
+		client:   client,
+		topic:    topic,
+		inputs:   []textinput.Model{key, from, to},
+		viewport: viewport.New(100, 20),
+	}
+	m.resize(width, height)
+	return m
+}
+
+// startKeyLookup runs the lookup in the background, sending progress and
+// then the result on events
+func startKeyLookup(ctx context.Context, client *kafka.Client, topic, key string, opts kafka.KeyLookupOptions, events chan tea.Msg) tea.Cmd {
+	opts.OnProgress = func(p kafka.KeyLookupProgress) {
+		// Progress is best effort, a slow UI only misses intermediate updates
+		select {
+		case events <- keyLookupProgressMsg{events: events, progress: p}:
+		default:
+		}
+	}
+	go func() {
+		result, err := client.FindMessagesByKey(ctx, topic, key, opts)
+		events <- keyLookupDoneMsg{events: events, result: result, err: err}
+	}()
+	return waitForKeyLookup(events)
+}
+
+func waitForKeyLookup(events chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-events
+	}
+}
+
+func (m KeyLookupModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m KeyLookupModel) Update(msg tea.Msg) (KeyLookupModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case keyLookupProgressMsg:
+		if msg.events != m.events {
+			return m, waitForKeyLookup(msg.events)
+		}
+		m.progress = msg.progress
+		return m, waitForKeyLookup(m.events)
+	case keyLookupDoneMsg:
+		if msg.events != m.events || errors.Is(msg.err, context.Canceled) {
+			return m, nil
+		}
+		m.scanning = false
+		m.cancel()
+		m.result, m.err = msg.result, msg.err
+		if msg.err != nil {
+			return m, reportError("find by key", msg.err)
+		}
+		m.viewport.SetContent(renderKeyLookupMatches(m.result.Matches))
+		m.viewport.GotoTop()
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.resize(msg.Width, msg.Height)
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			if m.scanning {
+				// Stop the scan, keeping the form to adjust and retry
+				m.cancel()
+				m.scanning = false
+				m.events = nil
+				return m, nil
+			}
+			if m.result != nil {
+				m.result = nil
+				return m, m.focusInputs()
+			}
+			return m, ReturnToListView
+		case "tab", "down":
+			if m.result == nil && !m.scanning {
+				m.focus = (m.focus + 1) % keyLookupFieldCount
+				return m, m.focusInputs()
+			}
+		case "shift+tab", "up":
+			if m.result == nil && !m.scanning {
+				m.focus = (m.focus + keyLookupFieldCount - 1) % keyLookupFieldCount
+				return m, m.focusInputs()
+			}
+		case "enter":
+			if m.result == nil && !m.scanning {
+				return m.start()
+			}
+		}
+	}
+
+	if m.result != nil {
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	}
+	if m.scanning {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+	return m, cmd
+}
+
+// start validates the form and begins scanning
+func (m KeyLookupModel) start() (KeyLookupModel, tea.Cmd) {
+	m.err = nil
+	key := m.inputs[keyLookupKeyField].Value()
+	if key == "" {
+		m.err = fmt.Errorf("enter the key to look for")
+		return m, nil
+	}
+	from, err := kafka.ParseTimestamp(m.inputs[keyLookupFromField].Value())
+	if err != nil {
+		m.err = fmt.Errorf("start: %w", err)
+		return m, nil
+	}
+	to, err := kafka.ParseTimestamp(m.inputs[keyLookupToField].Value())
+	if err != nil {
+		m.err = fmt.Errorf("end: %w", err)
+		return m, nil
+	}
+
+	for i := range m.inputs {
+		m.inputs[i].Blur()
+	}
+	var ctx context.Context
+	ctx, m.cancel = context.WithCancel(context.Background())
+	m.events = make(chan tea.Msg, 1)
+	m.scanning = true
+	m.key = key
+	m.progress = kafka.KeyLookupProgress{}
+	return m, startKeyLookup(ctx, m.client, m.topic, key, kafka.KeyLookupOptions{From: from, To: to}, m.events)
+}
+
+func (m *KeyLookupModel) focusInputs() tea.Cmd {
+	for i := range m.inputs {
+		m.inputs[i].Blur()
+	}
+	return m.inputs[m.focus].Focus()
+}
+
+func (m *KeyLookupModel) resize(width, height int) {
+	m.width, m.height = width, height
+	if width > 4 {
+		m.viewport.Width = width - 4
+	}
+	if height > 10 {
+		m.viewport.Height = height - 10
+	}
+}
+
+// renderKeyLookupMatches lists the matching records, one line each
+func renderKeyLookupMatches(matches []kafka.Message) string {
+	if len(matches) == 0 {
+		return "No messages with this key."
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+
+	var sb strings.Builder
+	sb.WriteString(headerStyle.Render(fmt.Sprintf("%9s  %12s  %-23s  %-12s  %s", "Partition", "Offset", "Timestamp", "Format", "Value")))
+	sb.WriteString("\n")
+	for _, msg := range matches {
+		payload := detectPayload(msg.Value)
+		value := strings.ReplaceAll(displayPayload(payload.Body, BinaryHex), "\n", " ")
+		sb.WriteString(fmt.Sprintf("%9d  %12d  %-23s  %-12s  %s\n", msg.Partition, msg.Offset,
+			msg.Timestamp.Format("2006-01-02 15:04:05.000"), truncateString(payload.Label(), 12), truncateString(value, 120)))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (m KeyLookupModel) View() string {
+	var s strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Padding(0, 1)
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("86"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	s.WriteString(titleStyle.Render("🔑 Find by key in " + m.topic))
+	s.WriteString("\n\n")
+
+	help := "Tab: Next field | Enter: Search | Esc: Back"
+	switch {
+	case m.scanning:
+		s.WriteString(fmt.Sprintf("Scanning for key %q... %d/%d partitions, %d records read, %d found",
+			m.key, m.progress.Done, m.progress.Partitions, m.progress.Scanned, m.progress.Matches))
+		help = "Esc: Stop"
+	case m.result != nil:
+		s.WriteString(fmt.Sprintf("%d message(s) with key %q", len(m.result.Matches), m.key))
+		s.WriteString(dimStyle.Render(fmt.Sprintf("  (%d records read)", m.result.Scanned)))
+		s.WriteString("\n\n")
+		s.WriteString(m.viewport.View())
+		help = "↑/↓: Scroll | Esc: New search"
+	default:
+		labels := []string{"Key:   ", "Start: ", "End:   "}
+		for i, input := range m.inputs {
+			s.WriteString(labelStyle.Render(labels[i]) + input.View() + "\n")
+		}
+		s.WriteString("\n")
+		s.WriteString(dimStyle.Render("Leave start and end empty to scan the whole topic. Times are Unix milliseconds, RFC3339, \"2006-01-02 15:04:05\" or relative like -2h."))
+		if m.err != nil {
+			s.WriteString("\n\n")
+			s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(fmt.Sprintf("Error: %v", m.err)))
+		}
+	}
+	s.WriteString("\n\n")
+	s.WriteString(dimStyle.Render(help))
+	return s.String()
+}
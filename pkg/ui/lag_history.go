@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+)
+
+const (
+	// lagSampleInterval is how often consumer group lag is sampled in the background
+	lagSampleInterval = 30 * time.Second
+	// lagHistorySize is the number of samples kept per group, one sparkline cell each
+	lagHistorySize = 20
+)
+
+type lagSampleTickMsg struct{}
+
+type lagSampleMsg struct {
+	groups []kafka.ConsumerGroupInfo
+	err    error
+}
+
+func lagSampleTick() tea.Cmd {
+	return tea.Tick(lagSampleInterval, func(time.Time) tea.Msg {
+		return lagSampleTickMsg{}
+	})
+}
+
+func sampleLag(client *kafka.Client) tea.Cmd {
+	return func() tea.Msg {
+		groups, err := client.GetConsumerGroups()
+		return lagSampleMsg{groups: groups, err: err}
+	}
+}
+
+// recordLagSample shows the groups of a background sample and adds them to
+// the lag history. Only the periodic sample records, so the samples stay
+// lagSampleInterval apart however often the list is refreshed.
+func (m *Model) recordLagSample(groups []kafka.ConsumerGroupInfo) tea.Cmd {
+	m.lagHistory.record(groups)
+	m.consumerGroups = groups
+	m.refreshConsumerGroupRows()
+	return m.refreshGroupTopicLags()
+}
+
+// lagHistory keeps the most recent total lag samples of each consumer group
+type lagHistory struct {
+	samples map[string][]int64
+}
+
+func newLagHistory() *lagHistory {
+	return &lagHistory{samples: make(map[string][]int64)}
+}
+
+// record appends a sample for every group and forgets groups that are gone
+func (h *lagHistory) record(groups []kafka.ConsumerGroupInfo) {
+	seen := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		seen[g.GroupID] = true
+		s := append(h.samples[g.GroupID], g.ConsumerLag)
+		if len(s) > lagHistorySize {
+			s = s[len(s)-lagHistorySize:]
+		}
+		h.samples[g.GroupID] = s
+	}
+	for id := range h.samples {
+		if !seen[id] {
+			delete(h.samples, id)
+		}
+	}
+	logger.Get().WithField("groups", len(groups)).Debug("Recorded consumer group lag samples")
+}
+
+// trend renders a group's sparkline followed by a trend arrow
+func (h *lagHistory) trend(groupID string) string {
+	s := h.samples[groupID]
+	return sparkline(s) + " " + trendArrow(s)
+}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline scales values between their min and max onto block characters
+func sparkline(values []int64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+
+	var sb strings.Builder
+	for _, v := range values {
+		idx := 0
+		if hi > lo {
+			idx = int((v - lo) * int64(len(sparkBlocks)-1) / (hi - lo))
+		}
+		sb.WriteRune(sparkBlocks[idx])
+	}
+	return sb.String()
+}
+
+// trendArrow compares the latest sample with the previous one: ↑ growing,
+// ↓ draining, → unchanged
+func trendArrow(values []int64) string {
+	if len(values) < 2 {
+		return ""
+	}
+	last, prev := values[len(values)-1], values[len(values)-2]
+	switch {
+	case last > prev:
+		return "↑"
+	case last < prev:
+		return "↓"
+	default:
+		return "→"
+	}
+}
@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []int64
+		expected string
+	}{
+		{"empty", nil, ""},
+		{"flat", []int64{5, 5, 5}, "▁▁▁"},
+		{"rising", []int64{0, 7, 14}, "▁▄█"},
+		{"falling", []int64{100, 50, 0}, "█▄▁"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := sparkline(tt.values); result != tt.expected {
+				t.Errorf("sparkline(%v) = %q, want %q", tt.values, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTrendArrow(t *testing.T) {
+	tests := []struct {
+		values   []int64
+		expected string
+	}{
+		{[]int64{10}, ""},
+		{[]int64{10, 20}, "↑"},
+		{[]int64{20, 10}, "↓"},
+		{[]int64{10, 10}, "→"},
+	}
+
+	for _, tt := range tests {
+		if result := trendArrow(tt.values); result != tt.expected {
+			t.Errorf("trendArrow(%v) = %q, want %q", tt.values, result, tt.expected)
+		}
+	}
+}
+
+func TestLagHistoryRecord(t *testing.T) {
+	h := newLagHistory()
+	for i := 0; i < lagHistorySize+5; i++ {
+		h.record([]kafka.ConsumerGroupInfo{{GroupID: "a", ConsumerLag: int64(i)}, {GroupID: "b"}})
+	}
+	if got := len(h.samples["a"]); got != lagHistorySize {
+		t.Errorf("kept %d samples, want %d", got, lagHistorySize)
+	}
+	if last := h.samples["a"][lagHistorySize-1]; last != lagHistorySize+4 {
+		t.Errorf("latest sample = %d, want %d", last, lagHistorySize+4)
+	}
+
+	h.record([]kafka.ConsumerGroupInfo{{GroupID: "a"}})
+	if _, ok := h.samples["b"]; ok {
+		t.Error("samples of a deleted group were kept")
+	}
+}
+
+func TestLagHistoryRecordsBackgroundSamplesOnly(t *testing.T) {
+	columns := make([]table.Column, 7)
+	for i := range columns {
+		columns[i] = table.Column{Title: "C", Width: 10}
+	}
+	m := Model{
+		consumersTable: table.New(table.WithColumns(columns), table.WithHeight(10)),
+		lagHistory:     newLagHistory(),
+	}
+	update := func(msg tea.Msg) {
+		model, _ := m.Update(msg)
+		m = model.(Model)
+	}
+	groups := func(lag int64) []kafka.ConsumerGroupInfo {
+		return []kafka.ConsumerGroupInfo{{GroupID: "billing", ConsumerLag: lag}}
+	}
+
+	update(lagSampleMsg{groups: groups(10)})
+	// Refreshing the list shows the new lag without adding a sample
+	update(consumerGroupsMsg{groups: groups(15)})
+	update(consumerGroupsMsg{groups: groups(18)})
+	if got := m.lagHistory.samples["billing"]; !reflect.DeepEqual(got, []int64{10}) {
+		t.Errorf("samples after refreshes = %v, want [10]", got)
+	}
+	if m.consumerGroups[0].ConsumerLag != 18 {
+		t.Errorf("refresh not shown, lag = %d", m.consumerGroups[0].ConsumerLag)
+	}
+
+	update(lagSampleMsg{groups: groups(20)})
+	if got := m.lagHistory.samples["billing"]; !reflect.DeepEqual(got, []int64{10, 20}) {
+		t.Errorf("samples = %v, want [10 20]", got)
+	}
+}
@@ -0,0 +1,221 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+// leaderBarWidth is the width of the share bars
+const leaderBarWidth = 30
+
+// LeaderBalanceModel reports how partition leadership is spread over brokers
+// and racks and can run a preferred leader election
+type LeaderBalanceModel struct {
+	client     *kafka.Client
+	balance    *kafka.LeaderBalance
+	loading    bool
+	confirming bool // Waiting for y/n before the election
+	electing   bool
+	result     string
+	err        error
+	width      int
+	height     int
+}
+
+func NewLeaderBalanceModel(client *kafka.Client) LeaderBalanceModel {
+	return LeaderBalanceModel{client: client, loading: true}
+}
+
+type leaderBalanceMsg struct {
+	balance *kafka.LeaderBalance
+	err     error
+}
+
+type leadersElectedMsg struct {
+	elected int
+	err     error
+}
+
+func fetchLeaderBalance(client *kafka.Client) tea.Cmd {
+	return func() tea.Msg {
+		balance, err := client.GetLeaderBalance()
+		return leaderBalanceMsg{balance: balance, err: err}
+	}
+}
+
+func electPreferredLeaders(client *kafka.Client, partitions map[string][]int32) tea.Cmd {
+	return func() tea.Msg {
+		elected, err := client.ElectPreferredLeaders(partitions)
+		return leadersElectedMsg{elected: elected, err: err}
+	}
+}
+
+func (m LeaderBalanceModel) Init() tea.Cmd {
+	return fetchLeaderBalance(m.client)
+}
+
+func (m LeaderBalanceModel) Update(msg tea.Msg) (LeaderBalanceModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case leaderBalanceMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.balance = msg.balance
+		return m, nil
+
+	case leadersElectedMsg:
+		m.electing = false
+		m.err = msg.err
+		if msg.elected > 0 || msg.err == nil {
+			m.result = fmt.Sprintf("Elected the preferred leader of %d partitions", msg.elected)
+		}
+		m.loading = true
+		return m, fetchLeaderBalance(m.client)
+
+	case tea.KeyMsg:
+		if m.confirming {
+			switch msg.String() {
+			case "y", "Y":
+				m.confirming = false
+				m.electing = true
+				m.result, m.err = "", nil
+				return m, electPreferredLeaders(m.client, m.balance.NotPreferred)
+			case "n", "N", "esc":
+				m.confirming = false
+			}
+			return m, nil
+		}
+		switch msg.String() {
+		case "esc", "q":
+			return m, ReturnToListView
+		case "r":
+			m.loading = true
+			return m, fetchLeaderBalance(m.client)
+		case "e":
+			if m.balance != nil && len(m.balance.NotPreferred) > 0 && !m.electing {
+				m.confirming = true
+			}
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+	return m, nil
+}
+
+// notPreferredCount counts the partitions an election would move
+func notPreferredCount(b *kafka.LeaderBalance) int {
+	n := 0
+	for _, partitions := range b.NotPreferred {
+		n += len(partitions)
+	}
+	return n
+}
+
+// shareBar renders share as a bar of leaderBarWidth cells
+func shareBar(share float64) string {
+	filled := int(share*leaderBarWidth + 0.5)
+	return strings.Repeat("█", filled) + strings.Repeat("░", leaderBarWidth-filled)
+}
+
+func (m LeaderBalanceModel) View() string {
+	var s strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Padding(0, 1)
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Bold(true)
+	okStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("46"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	s.WriteString(titleStyle.Render("⚖️  Leader Balance"))
+	s.WriteString("\n\n")
+
+	if m.err != nil {
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(fmt.Sprintf("❌ %v", m.err)))
+		s.WriteString("\n\n")
+	}
+	if m.result != "" {
+		s.WriteString(okStyle.Render("✅ " + m.result))
+		s.WriteString("\n\n")
+	}
+
+	b := m.balance
+	if b == nil {
+		if m.loading {
+			s.WriteString("Loading partition leadership...")
+		}
+		s.WriteString("\n\n" + dimStyle.Render("Esc: Back"))
+		return s.String()
+	}
+
+	s.WriteString(headerStyle.Render(fmt.Sprintf("Leaders per broker (%d partitions)", b.Partitions)))
+	s.WriteString("\n")
+	for _, br := range b.Brokers {
+		rack := br.Rack
+		if rack == "" {
+			rack = "-"
+		}
+		line := fmt.Sprintf("  %4d  %-10s %s %5.1f%%  %5d leaders  %5d replicas", br.BrokerID, rack, shareBar(br.Share), br.Share*100, br.Leaders, br.Replicas)
+		var flags []string
+		if br.Skewed {
+			flags = append(flags, "over its fair share")
+		}
+		if br.Lost > 0 {
+			flags = append(flags, fmt.Sprintf("not leading %d of %d preferred", br.Lost, br.Preferred))
+		}
+		if br.Skewed || br.Imbalanced {
+			line = warnStyle.Render(line + "  ⚠️ " + strings.Join(flags, ", "))
+		} else if len(flags) > 0 {
+			line += dimStyle.Render("  " + strings.Join(flags, ", "))
+		}
+		s.WriteString(line + "\n")
+	}
+
+	if len(b.Racks) > 0 {
+		s.WriteString("\n")
+		s.WriteString(headerStyle.Render("Leaders per rack"))
+		s.WriteString("\n")
+		for _, r := range b.Racks {
+			line := fmt.Sprintf("  %-16s %s %5.1f%%  %5d leaders on %d brokers", r.Rack, shareBar(r.Share), r.Share*100, r.Leaders, r.Brokers)
+			if r.Skewed {
+				line = warnStyle.Render(line + "  ⚠️ over its fair share")
+			}
+			s.WriteString(line + "\n")
+		}
+	}
+
+	s.WriteString("\n")
+	pending := notPreferredCount(b)
+	switch {
+	case !b.Imbalanced() && pending == 0:
+		s.WriteString(okStyle.Render("✅ Leadership is balanced"))
+	case pending == 0:
+		s.WriteString(warnStyle.Render("Every partition is led by its preferred replica; the skew comes from the replica assignment itself, which an election cannot fix"))
+	default:
+		s.WriteString(fmt.Sprintf("%d partitions are not led by their preferred replica", pending))
+	}
+	s.WriteString("\n\n")
+
+	switch {
+	case m.confirming:
+		s.WriteString(warnStyle.Render(fmt.Sprintf("Run a preferred leader election for %d partitions? (y/n)", pending)))
+	case m.electing:
+		s.WriteString("Electing preferred leaders...")
+	case pending > 0:
+		s.WriteString(dimStyle.Render("e: Elect preferred leaders | r: Refresh | Esc: Back"))
+	default:
+		s.WriteString(dimStyle.Render("r: Refresh | Esc: Back"))
+	}
+	return s.String()
+}
@@ -0,0 +1,240 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/digitalis-io/kconduit/pkg/loadgen"
+)
+
+const (
+	genCountField = iota
+	genDurationField
+	genRateField
+	genKeyField
+	genValueField
+	genFieldCount
+)
+
+// LoadGeneratorModel is the producer's "generate" mode: it sends templated
+// messages at a target rate and reports throughput and errors
+type LoadGeneratorModel struct {
+	topic      string
+	client     *kafka.Client
+	headers    map[string]string
+	inputs     []textinput.Model
+	focusIndex int
+	err        error
+	runner     *loadgen.Runner
+	cancel     context.CancelFunc
+	stats      loadgen.Stats
+}
+
+type generatorTickMsg struct{}
+
+type generatorDoneMsg struct {
+	stats loadgen.Stats
+}
+
+func NewLoadGeneratorModel(topic string, client *kafka.Client, headers map[string]string) LoadGeneratorModel {
+	fields := []struct {
+		placeholder string
+		value       string
+	}{
+		genCountField:    {"Number of messages (0 = until duration ends)", "1000"},
+		genDurationField: {"Duration, e.g. 30s or 5m (empty = until count is reached)", ""},
+		genRateField:     {"Messages per second (0 = as fast as possible)", "100"},
+		genKeyField:      {"Key template", "key-{{.Seq}}"},
+		genValueField:    {"Value template", `{"id":"{{uuid}}","seq":{{.Seq}},"ts":"{{now}}"}`},
+	}
+
+	inputs := make([]textinput.Model, genFieldCount)
+	for i, f := range fields {
+		ti := textinput.New()
+		ti.Placeholder = f.placeholder
+		ti.SetValue(f.value)
+		ti.CharLimit = 2048
+		ti.Width = 80
+		inputs[i] = ti
+	}
+	inputs[genCountField].Focus()
+
+	return LoadGeneratorModel{
+		topic:   topic,
+		client:  client,
+		headers: headers,
+		inputs:  inputs,
+	}
+}
+
+func generatorTick() tea.Cmd {
+	return tea.Tick(250*time.Millisecond, func(time.Time) tea.Msg {
+		return generatorTickMsg{}
+	})
+}
+
+func runGenerator(ctx context.Context, runner *loadgen.Runner, client *kafka.Client, topic string, headers map[string]string) tea.Cmd {
+	return func() tea.Msg {
+		stats := runner.Run(ctx, func(key, value string) error {
+			_, _, err := client.ProduceMessage(topic, key, value, kafka.ProduceOptions{Headers: headers})
+			return err
+		})
+		return generatorDoneMsg{stats: stats}
+	}
+}
+
+// Running reports whether a run is in progress
+func (m LoadGeneratorModel) Running() bool {
+	return m.runner != nil && !m.stats.Done
+}
+
+// config builds a loadgen.Config from the form
+func (m LoadGeneratorModel) config() (loadgen.Config, error) {
+	var config loadgen.Config
+
+	if v := strings.TrimSpace(m.inputs[genCountField].Value()); v != "" {
+		count, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || count < 0 {
+			return config, fmt.Errorf("invalid message count: %s", v)
+		}
+		config.Count = count
+	}
+	if v := strings.TrimSpace(m.inputs[genDurationField].Value()); v != "" {
+		duration, err := time.ParseDuration(v)
+		if err != nil || duration < 0 {
+			return config, fmt.Errorf("invalid duration: %s", v)
+		}
+		config.Duration = duration
+	}
+	if v := strings.TrimSpace(m.inputs[genRateField].Value()); v != "" {
+		rate, err := strconv.Atoi(v)
+		if err != nil || rate < 0 {
+			return config, fmt.Errorf("invalid rate: %s", v)
+		}
+		config.Rate = rate
+	}
+	config.KeyTemplate = m.inputs[genKeyField].Value()
+	config.ValueTemplate = m.inputs[genValueField].Value()
+	return config, nil
+}
+
+func (m LoadGeneratorModel) Update(msg tea.Msg) (LoadGeneratorModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case generatorTickMsg:
+		if m.Running() {
+			m.stats = m.runner.Stats()
+			return m, generatorTick()
+		}
+		return m, nil
+
+	case generatorDoneMsg:
+		m.stats = msg.stats
+		m.cancel = nil
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.Running() {
+			if msg.Type == tea.KeyEsc || msg.Type == tea.KeyCtrlC {
+				m.cancel()
+			}
+			return m, nil
+		}
+
+		switch msg.Type {
+		case tea.KeyTab, tea.KeyDown:
+			return m, m.setFocus(m.focusIndex + 1)
+		case tea.KeyShiftTab, tea.KeyUp:
+			return m, m.setFocus(m.focusIndex - 1)
+		case tea.KeyEnter, tea.KeyCtrlS:
+			config, err := m.config()
+			if err == nil {
+				m.runner, err = loadgen.NewRunner(config)
+			}
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
+			m.err = nil
+			m.stats = loadgen.Stats{}
+			ctx, cancel := context.WithCancel(context.Background())
+			m.cancel = cancel
+			return m, tea.Batch(runGenerator(ctx, m.runner, m.client, m.topic, m.headers), generatorTick())
+		}
+	}
+
+	var cmd tea.Cmd
+	m.inputs[m.focusIndex], cmd = m.inputs[m.focusIndex].Update(msg)
+	return m, cmd
+}
+
+func (m *LoadGeneratorModel) setFocus(index int) tea.Cmd {
+	m.inputs[m.focusIndex].Blur()
+	m.focusIndex = (index + genFieldCount) % genFieldCount
+	return m.inputs[m.focusIndex].Focus()
+}
+
+func (m LoadGeneratorModel) View() string {
+	var sb strings.Builder
+
+	labelStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("86"))
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("229"))
+
+	sb.WriteString(labelStyle.Render("⚡ Load Generator") + "\n\n")
+
+	labels := []string{
+		genCountField:    "Count:    ",
+		genDurationField: "Duration: ",
+		genRateField:     "Rate/sec: ",
+		genKeyField:      "Key:      ",
+		genValueField:    "Value:    ",
+	}
+	for i, input := range m.inputs {
+		sb.WriteString(labelStyle.Render(labels[i]) + input.View() + "\n")
+	}
+	sb.WriteString("\n")
+
+	if m.runner != nil {
+		status := "🔄 Running"
+		if m.stats.Done {
+			status = "✅ Finished"
+		}
+		sb.WriteString(labelStyle.Render("Status:   ") + valueStyle.Render(status) + "\n")
+		sb.WriteString(labelStyle.Render("Sent:     ") + valueStyle.Render(fmt.Sprintf("%d (%s)", m.stats.Sent, formatBytes(m.stats.Bytes))) + "\n")
+		sb.WriteString(labelStyle.Render("Elapsed:  ") + valueStyle.Render(m.stats.Elapsed.Truncate(100*time.Millisecond).String()) + "\n")
+		sb.WriteString(labelStyle.Render("Rate:     ") + valueStyle.Render(fmt.Sprintf("%.1f msg/s • %s/s", m.stats.MessagesPerSec(), formatBytes(int64(m.stats.BytesPerSec())))) + "\n")
+		errorsText := fmt.Sprintf("%d", m.stats.Errors)
+		if m.stats.LastErr != nil {
+			errorsText += fmt.Sprintf(" (last: %v)", m.stats.LastErr)
+		}
+		sb.WriteString(labelStyle.Render("Errors:   ") + valueStyle.Render(errorsText) + "\n")
+	}
+
+	if m.err != nil {
+		sb.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true).Render(fmt.Sprintf("❌ Error: %v", m.err)) + "\n")
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Italic(true)
+	sb.WriteString("\n")
+	if m.Running() {
+		sb.WriteString(helpStyle.Render("Esc: Stop"))
+	} else {
+		sb.WriteString(helpStyle.Render("Tab/↑/↓: Switch fields • Enter: Start • Esc: Back to composer"))
+		sb.WriteString("\n")
+		sb.WriteString(helpStyle.Render("Templates: {{.Seq}} {{uuid}} {{now}} {{unixMilli}} {{randInt 1 100}} {{randString 8}}"))
+	}
+
+	return sb.String()
+}
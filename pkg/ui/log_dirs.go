@@ -0,0 +1,210 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+// logDirTopPartitions is how many replicas are listed per directory unless
+// all are requested
+const logDirTopPartitions = 10
+
+// LogDirsModel browses the log directories of one broker
+type LogDirsModel struct {
+	client   *kafka.Client
+	broker   kafka.BrokerInfo
+	dirs     []kafka.LogDirInfo
+	loading  bool
+	showAll  bool // List every replica instead of the largest
+	err      error
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+func NewLogDirsModel(client *kafka.Client, broker kafka.BrokerInfo, width, height int) LogDirsModel {
+	m := LogDirsModel{
+		client:   client,
+		broker:   broker,
+		loading:  true,
+		viewport: viewport.New(100, 20),
+	}
+	m.resize(width, height)
+	return m
+}
+
+type logDirsMsg struct {
+	dirs []kafka.LogDirInfo
+	err  error
+}
+
+func fetchLogDirs(client *kafka.Client, brokerID int32) tea.Cmd {
+	return func() tea.Msg {
+		dirs, err := client.GetBrokerLogDirs(brokerID)
+		return logDirsMsg{dirs: dirs, err: err}
+	}
+}
+
+func (m LogDirsModel) Init() tea.Cmd {
+	return fetchLogDirs(m.client, m.broker.ID)
+}
+
+func (m LogDirsModel) Update(msg tea.Msg) (LogDirsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case logDirsMsg:
+		m.loading = false
+		m.dirs, m.err = msg.dirs, msg.err
+		m.refresh()
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.resize(msg.Width, msg.Height)
+		m.refresh()
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			return m, ReturnToListView
+		case "r":
+			m.loading = true
+			return m, fetchLogDirs(m.client, m.broker.ID)
+		case "a":
+			m.showAll = !m.showAll
+			m.refresh()
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+func (m *LogDirsModel) resize(width, height int) {
+	m.width, m.height = width, height
+	if width > 4 {
+		m.viewport.Width = width - 4
+	}
+	if height > 8 {
+		m.viewport.Height = height - 8
+	}
+}
+
+func (m *LogDirsModel) refresh() {
+	limit := logDirTopPartitions
+	if m.showAll {
+		limit = 0
+	}
+	content := renderLogDirs(m.dirs, limit)
+	if len(m.dirs) > 0 {
+		content = renderBrokerDisk(kafka.SummarizeBrokerDisk(m.dirs), logDirTopPartitions) + "\n\n" + content
+	}
+	m.viewport.SetContent(content)
+}
+
+// renderBrokerDisk shows the broker's total disk use and its largest topics
+// and replicas across all directories
+func renderBrokerDisk(usage kafka.BrokerDiskUsage, limit int) string {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("86"))
+
+	share := func(size int64) float64 {
+		if usage.Size == 0 {
+			return 0
+		}
+		return float64(size) / float64(usage.Size) * 100
+	}
+
+	var sb strings.Builder
+	sb.WriteString(headerStyle.Render(fmt.Sprintf("Total %s in %d replicas", formatBytes(usage.Size), usage.Replicas)) + "\n")
+	if usage.Replicas == 0 {
+		return strings.TrimSuffix(sb.String(), "\n")
+	}
+
+	sb.WriteString("\n" + headerStyle.Render("Largest topics") + "\n")
+	for _, t := range usage.Topics[:min(limit, len(usage.Topics))] {
+		sb.WriteString(fmt.Sprintf("  %10s %5.1f%%  %s (%d replicas)\n", formatBytes(t.Size), share(t.Size), t.Topic, t.Replicas))
+	}
+
+	sb.WriteString("\n" + headerStyle.Render("Largest topic-partitions") + "\n")
+	for _, p := range usage.Largest[:min(limit, len(usage.Largest))] {
+		sb.WriteString(fmt.Sprintf("  %10s %5.1f%%  %s-%d  %s\n", formatBytes(p.Size), share(p.Size), p.Topic, p.Partition, p.Path))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// renderLogDirs lists each directory with its size and its largest replicas;
+// limit 0 lists them all
+func renderLogDirs(dirs []kafka.LogDirInfo, limit int) string {
+	if len(dirs) == 0 {
+		return "No log directories reported."
+	}
+
+	pathStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("229"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+
+	var sb strings.Builder
+	for i, dir := range dirs {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fmt.Sprintf("%s  %s in %d replicas\n", pathStyle.Render(dir.Path), formatBytes(dir.Size), len(dir.Partitions)))
+		if dir.Error != "" {
+			sb.WriteString(errorStyle.Render("  ❌ "+dir.Error) + "\n")
+			continue
+		}
+
+		shown := dir.Partitions
+		if limit > 0 && len(shown) > limit {
+			shown = shown[:limit]
+		}
+		for _, p := range shown {
+			share := 0.0
+			if dir.Size > 0 {
+				share = float64(p.Size) / float64(dir.Size) * 100
+			}
+			line := fmt.Sprintf("  %10s %5.1f%%  %s-%d", formatBytes(p.Size), share, p.Topic, p.Partition)
+			if p.Future {
+				line += fmt.Sprintf(" (moving in, %d behind)", p.OffsetLag)
+			}
+			sb.WriteString(line + "\n")
+		}
+		if hidden := len(dir.Partitions) - len(shown); hidden > 0 {
+			sb.WriteString(dimStyle.Render(fmt.Sprintf("  … %d smaller replicas", hidden)) + "\n")
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (m LogDirsModel) View() string {
+	var s strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Padding(0, 1)
+	s.WriteString(titleStyle.Render(fmt.Sprintf("💾 Log Dirs - Broker %d (%s)", m.broker.ID, m.broker.Host)))
+	s.WriteString("\n\n")
+
+	switch {
+	case m.loading && m.dirs == nil:
+		s.WriteString("Loading log directories...")
+	case m.err != nil:
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(fmt.Sprintf("Error: %v", m.err)))
+	default:
+		s.WriteString(m.viewport.View())
+	}
+	s.WriteString("\n\n")
+
+	help := "↑/↓: Scroll | a: Show all replicas | r: Refresh | Esc: Back"
+	if m.showAll {
+		help = fmt.Sprintf("↑/↓: Scroll | a: Show top %d | r: Refresh | Esc: Back", logDirTopPartitions)
+	}
+	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(help))
+	return s.String()
+}
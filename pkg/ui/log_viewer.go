@@ -0,0 +1,161 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// logRefreshInterval is how often the log viewer picks up new entries
+const logRefreshInterval = time.Second
+
+// logViewerLevels are the minimum levels the viewer cycles through
+var logViewerLevels = []logrus.Level{logrus.DebugLevel, logrus.InfoLevel, logrus.WarnLevel, logrus.ErrorLevel}
+
+type logRefreshMsg struct{}
+
+// LogViewerModel tails kconduit's own log inside the TUI
+type LogViewerModel struct {
+	viewport viewport.Model
+	minLevel int  // Index into logViewerLevels
+	follow   bool // Keep the newest entry in view
+	width    int
+	height   int
+}
+
+func NewLogViewerModel(width, height int) LogViewerModel {
+	m := LogViewerModel{
+		viewport: viewport.New(100, 20),
+		follow:   true,
+	}
+	m.resize(width, height)
+	m.refresh()
+	return m
+}
+
+func logRefreshTick() tea.Cmd {
+	return tea.Tick(logRefreshInterval, func(time.Time) tea.Msg {
+		return logRefreshMsg{}
+	})
+}
+
+func (m LogViewerModel) Init() tea.Cmd {
+	return logRefreshTick()
+}
+
+func (m LogViewerModel) Update(msg tea.Msg) (LogViewerModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case logRefreshMsg:
+		m.refresh()
+		return m, logRefreshTick()
+	case tea.WindowSizeMsg:
+		m.resize(msg.Width, msg.Height)
+		m.refresh()
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "L":
+			return m, func() tea.Msg { return SwitchToListViewMsg{} }
+		case "l":
+			m.minLevel = (m.minLevel + 1) % len(logViewerLevels)
+			m.refresh()
+			return m, nil
+		case "f":
+			m.follow = !m.follow
+			if m.follow {
+				m.viewport.GotoBottom()
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	// Scrolling up pauses following until the user returns to the bottom
+	m.follow = m.viewport.AtBottom()
+	return m, cmd
+}
+
+func (m *LogViewerModel) resize(width, height int) {
+	m.width, m.height = width, height
+	if width > 4 {
+		m.viewport.Width = width - 4
+	}
+	if height > 8 {
+		m.viewport.Height = height - 8
+	}
+}
+
+// refresh reloads the entries at or above the selected level
+func (m *LogViewerModel) refresh() {
+	content := renderLogEntries(logger.Recent(), logViewerLevels[m.minLevel])
+	m.viewport.SetContent(content)
+	if m.follow {
+		m.viewport.GotoBottom()
+	}
+}
+
+var logLevelColors = map[logrus.Level]string{
+	logrus.TraceLevel: "241",
+	logrus.DebugLevel: "241",
+	logrus.InfoLevel:  "86",
+	logrus.WarnLevel:  "214",
+	logrus.ErrorLevel: "196",
+	logrus.FatalLevel: "196",
+	logrus.PanicLevel: "196",
+}
+
+// renderLogEntries formats entries at or above minLevel, one per line.
+// logrus levels grow more verbose as their value increases.
+func renderLogEntries(entries []logger.Entry, minLevel logrus.Level) string {
+	var sb strings.Builder
+	for _, e := range entries {
+		if e.Level > minLevel {
+			continue
+		}
+		level := lipgloss.NewStyle().Foreground(lipgloss.Color(logLevelColors[e.Level])).
+			Render(fmt.Sprintf("%-5s", strings.ToUpper(e.Level.String())))
+		sb.WriteString(fmt.Sprintf("%s %s %s", e.Time.Format("15:04:05"), level, e.Message))
+
+		keys := make([]string, 0, len(e.Fields))
+		for k := range e.Fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			sb.WriteString(fmt.Sprintf(" %s=%v", k, e.Fields[k]))
+		}
+		sb.WriteString("\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (m LogViewerModel) View() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Italic(true)
+
+	follow := "off"
+	if m.follow {
+		follow = "on"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(titleStyle.Render("📜 Application Log"))
+	sb.WriteString(fmt.Sprintf("  level ≥ %s · follow %s\n\n", strings.ToUpper(logViewerLevels[m.minLevel].String()), follow))
+	if m.viewport.TotalLineCount() == 0 || strings.TrimSpace(m.viewport.View()) == "" {
+		sb.WriteString("No log entries at this level yet. Raise verbosity with --log-level debug.\n")
+	} else {
+		sb.WriteString(m.viewport.View())
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	sb.WriteString(helpStyle.Render("↑/↓/PgUp/PgDn: Scroll | l: Cycle level | f: Follow | Esc: Close"))
+	return sb.String()
+}
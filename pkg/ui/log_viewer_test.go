@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/digitalis-io/kconduit/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+func TestRenderLogEntries(t *testing.T) {
+	at := time.Date(2025, 1, 2, 10, 30, 0, 0, time.UTC)
+	entries := []logger.Entry{
+		{Time: at, Level: logrus.DebugLevel, Message: "Constructed ACL filter"},
+		{Time: at, Level: logrus.InfoLevel, Message: "Deleting topic", Fields: logrus.Fields{"topic": "orders", "broker": 1}},
+		{Time: at, Level: logrus.ErrorLevel, Message: "Failed to delete topic"},
+	}
+
+	got := renderLogEntries(entries, logrus.InfoLevel)
+	lines := strings.Split(got, "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), got)
+	}
+	if !strings.Contains(lines[0], "10:30:00") || !strings.HasSuffix(lines[0], "Deleting topic broker=1 topic=orders") {
+		t.Errorf("unexpected info line %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "ERROR") {
+		t.Errorf("unexpected error line %q", lines[1])
+	}
+
+	if got := renderLogEntries(entries, logrus.ErrorLevel); strings.Count(got, "\n") != 0 || !strings.Contains(got, "Failed") {
+		t.Errorf("error level should only keep the error entry, got %q", got)
+	}
+}
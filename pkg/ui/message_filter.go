@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/itchyny/gojq"
+)
+
+// jqFilterPrefix marks a search term that is evaluated as a jq expression over
+// the JSON decoded message value, e.g. `jq:.user.country == "DE"`.
+const jqFilterPrefix = "jq:"
+
+// jqEvalTimeout bounds a single evaluation so runaway expressions such as
+// `repeat(.)` cannot freeze the consumer view
+const jqEvalTimeout = 100 * time.Millisecond
+
+// jqVariables are exposed to expressions alongside the decoded value
+var jqVariables = []string{"$key", "$headers", "$partition", "$offset"}
+
+// compileMessageQuery parses and compiles a jq expression for message filtering
+func compileMessageQuery(expr string) (*gojq.Code, error) {
+	query, err := gojq.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jq expression: %w", err)
+	}
+	code, err := gojq.Compile(query, gojq.WithVariables(jqVariables))
+	if err != nil {
+		return nil, fmt.Errorf("invalid jq expression: %w", err)
+	}
+	return code, nil
+}
+
+// queryMatches reports whether the expression yields a truthy result for the
+// message. Values that are not valid JSON never match.
+func queryMatches(code *gojq.Code, msg kafka.Message) bool {
+	var value any
+	if err := json.Unmarshal([]byte(msg.Value), &value); err != nil {
+		return false
+	}
+
+	headers := make(map[string]any, len(msg.Headers))
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), jqEvalTimeout)
+	defer cancel()
+
+	iter := code.RunWithContext(ctx, value, msg.Key, headers, int(msg.Partition), int(msg.Offset))
+	for {
+		result, ok := iter.Next()
+		if !ok {
+			return false
+		}
+		switch result := result.(type) {
+		case error:
+			return false
+		case nil:
+			continue
+		case bool:
+			if result {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+}
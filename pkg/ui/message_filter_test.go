@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+func TestQueryMatches(t *testing.T) {
+	msg := kafka.Message{
+		Key:       "user-42",
+		Value:     `{"user":{"country":"DE","age":31},"tags":["a","b"]}`,
+		Partition: 3,
+		Offset:    100,
+		Headers:   map[string]string{"source": "billing"},
+	}
+
+	tests := []struct {
+		expr     string
+		value    string
+		expected bool
+		name     string
+	}{
+		{`.user.country == "DE"`, "", true, "equality match"},
+		{`.user.country == "FR"`, "", false, "equality mismatch"},
+		{`.user.age > 30`, "", true, "numeric comparison"},
+		{`.tags | index("b") != null`, "", true, "array lookup"},
+		{`.missing`, "", false, "missing field is null"},
+		{`.user`, "", true, "non-null value is truthy"},
+		{`$key == "user-42" and $partition == 3`, "", true, "message variables"},
+		{`$headers.source == "billing"`, "", true, "header variable"},
+		{`.user.country == "DE"`, "not json", false, "non-JSON value never matches"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := compileMessageQuery(tt.expr)
+			if err != nil {
+				t.Fatalf("compileMessageQuery(%q) failed: %v", tt.expr, err)
+			}
+			m := msg
+			if tt.value != "" {
+				m.Value = tt.value
+			}
+			if result := queryMatches(code, m); result != tt.expected {
+				t.Errorf("queryMatches(%q) = %v, want %v", tt.expr, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCompileMessageQueryInvalid(t *testing.T) {
+	if _, err := compileMessageQuery(`.user.country ==`); err == nil {
+		t.Error("expected an error for an incomplete expression")
+	}
+}
@@ -0,0 +1,190 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/digitalis-io/kconduit/pkg/ai"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+// messageQuestion is the consumer view's panel for asking the AI assistant
+// about the buffered messages, e.g. "how many have status=FAILED?". Answers
+// are only displayed; nothing in them is executed.
+type messageQuestion struct {
+	assistant AIAssistantModel // Provides the configured provider and model
+	input     textinput.Model
+	viewport  viewport.Model
+	history   []aiTurn // Questions and answers, oldest first
+	response  string   // Answer streamed so far
+	asking    bool
+}
+
+func newMessageQuestion(assistant AIAssistantModel) *messageQuestion {
+	input := textinput.New()
+	input.Placeholder = "Ask about these messages, e.g. how many have status=FAILED? or summarize the error types"
+	input.CharLimit = 500
+	input.Focus()
+	return &messageQuestion{
+		assistant: assistant,
+		input:     input,
+		viewport:  viewport.New(80, 10),
+	}
+}
+
+// resize fits the panel into the space the message table takes
+func (q *messageQuestion) resize(width, height int) {
+	q.input.Width = max(width-6, 20)
+	q.viewport.Width = max(width-2, 20)
+	q.viewport.Height = max(height-3, 3)
+	q.refresh()
+}
+
+// refresh redraws the answers and scrolls to the latest
+func (q *messageQuestion) refresh() {
+	q.viewport.SetContent(renderConversation(q.history, q.response, q.viewport.Width-4))
+	q.viewport.GotoBottom()
+}
+
+// ask sends the question in the input along with the messages. Each question
+// stands alone, as the buffered messages change between them.
+func (q *messageQuestion) ask(topic string, messages []kafka.Message) tea.Cmd {
+	question := strings.TrimSpace(q.input.Value())
+	if question == "" || q.asking {
+		return nil
+	}
+	q.input.Reset()
+	q.asking = true
+	q.response = ""
+	q.history = append(q.history, aiTurn{role: aiRoleUser, content: fmt.Sprintf("%s (%d messages)", question, len(messages))})
+	q.refresh()
+	return q.assistant.startQuery(true, nil, func() (string, []AIMessage, error) {
+		return ai.QueryMessagesPrompt, []AIMessage{{Role: "user", Content: ai.QueryMessagesRequest(topic, messages, question)}}, nil
+	})
+}
+
+// cancel aborts the question in flight, keeping what has streamed so far
+func (q *messageQuestion) cancel() {
+	if !q.asking {
+		return
+	}
+	q.assistant.cancel()
+	q.asking = false
+	q.history = append(q.history, aiTurn{role: aiRoleNote, content: strings.TrimSpace(q.response + "\n\n⏹ Request cancelled")})
+	q.response = ""
+	q.refresh()
+}
+
+// questionFiltered reports whether questions are asked about the filtered
+// view rather than every buffered message
+func (m ConsumerModel) questionFiltered() bool {
+	return m.showFiltered
+}
+
+// questionMessageCount returns how many messages a question is asked about
+func (m ConsumerModel) questionMessageCount() int {
+	if m.questionFiltered() {
+		return len(m.filteredIndices)
+	}
+	return len(m.messages)
+}
+
+// questionMessages returns the messages a question is asked about: those in
+// the filtered view when it is shown, otherwise all buffered ones, with
+// decoded payloads in place of the raw values
+func (m ConsumerModel) questionMessages() []kafka.Message {
+	indices := m.filteredIndices
+	if !m.questionFiltered() {
+		indices = make([]int, len(m.messages))
+		for i := range indices {
+			indices[i] = i
+		}
+	}
+	messages := make([]kafka.Message, 0, len(indices))
+	for _, idx := range indices {
+		if idx >= len(m.messages) {
+			continue
+		}
+		msg := m.messages[idx]
+		msg.Key = copyablePayload(msg.Key, m.binaryFormat)
+		msg.Value = copyableValue(msg.Value, m.payloads[idx], m.binaryFormat)
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+// updateQuestion handles the question panel's keys and answers. It reports
+// false for messages the rest of the consumer view still has to handle.
+func (m *ConsumerModel) updateQuestion(msg tea.Msg) (tea.Cmd, bool) {
+	q := m.question
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			if q.asking {
+				q.cancel()
+				return nil, true
+			}
+			m.mode = ModeNormal
+			q.input.Blur()
+			return nil, true
+		case "enter":
+			return q.ask(m.topic, m.questionMessages()), true
+		case "up", "down", "pgup", "pgdown":
+			var cmd tea.Cmd
+			q.viewport, cmd = q.viewport.Update(msg)
+			return cmd, true
+		}
+		var cmd tea.Cmd
+		q.input, cmd = q.input.Update(msg)
+		return cmd, true
+
+	case aiChunkMsg:
+		if msg.stream != q.assistant.stream {
+			// Left over from a cancelled question
+			return nil, true
+		}
+		q.response += msg.text
+		q.refresh()
+		return waitForAI(q.assistant.stream), true
+
+	case aiStreamDoneMsg:
+		if msg.stream != q.assistant.stream {
+			return nil, true
+		}
+		q.assistant.cancel()
+		q.asking = false
+		q.response = ""
+		var cmd tea.Cmd
+		if msg.err != nil {
+			q.history = append(q.history, aiTurn{role: aiRoleNote, content: fmt.Sprintf("Error: %v", msg.err)})
+			cmd = reportError("ai", msg.err)
+		} else {
+			q.history = append(q.history, aiTurn{role: aiRoleAssistant, content: msg.response})
+		}
+		q.refresh()
+		return cmd, true
+	}
+	return nil, false
+}
+
+func (q *messageQuestion) View(messages int) string {
+	var sb strings.Builder
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("86"))
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Italic(true)
+
+	sb.WriteString(titleStyle.Render(fmt.Sprintf("🤖 Ask %s about %d message(s): ", q.assistant.getProviderName(), messages)))
+	sb.WriteString(q.input.View())
+	sb.WriteString("\n\n")
+	if len(q.history) == 0 {
+		sb.WriteString(helpStyle.Render("Answers are worked out by the model from the most recent messages that fit in one request, so check counts that matter."))
+	} else {
+		sb.WriteString(q.viewport.View())
+	}
+	return sb.String()
+}
@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+func TestQuestionMessages(t *testing.T) {
+	m := ConsumerModel{}
+	for i, value := range []string{`{"status":"OK"}`, `{"status":"FAILED"}`, "\x00\x01binary"} {
+		m.messages = append(m.messages, kafka.Message{Offset: int64(i), Value: value})
+		m.payloads = append(m.payloads, detectPayload(value))
+	}
+
+	got := m.questionMessages()
+	if len(got) != 3 || m.questionMessageCount() != 3 {
+		t.Fatalf("got %d messages, want all 3", len(got))
+	}
+	if got[1].Value != `{"status":"FAILED"}` {
+		t.Errorf("textual value = %q, want it unchanged", got[1].Value)
+	}
+	if got[2].Value == m.messages[2].Value {
+		t.Error("binary value should be encoded as text")
+	}
+
+	m.showFiltered = true
+	m.filteredIndices = []int{1}
+	got = m.questionMessages()
+	if len(got) != 1 || got[0].Offset != 1 || m.questionMessageCount() != 1 {
+		t.Errorf("filtered view should only ask about message 1, got %+v", got)
+	}
+}
@@ -0,0 +1,205 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+const (
+	// mirrorHeartbeatLate is the heartbeat age after which a flow is shown
+	// as lagging; MM2 emits one every second by default
+	mirrorHeartbeatLate = time.Minute
+	// mirrorHeartbeatStale is the age after which a flow is shown as down
+	mirrorHeartbeatStale = 5 * time.Minute
+)
+
+// MirrorModel shows the MirrorMaker 2 replication flows, their heartbeat
+// liveness and the consumer group offsets MM2 checkpointed on this cluster
+type MirrorModel struct {
+	client   *kafka.Client
+	status   *kafka.MirrorStatus
+	fetched  time.Time
+	loading  bool
+	err      error
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+func NewMirrorModel(client *kafka.Client, width, height int) MirrorModel {
+	m := MirrorModel{
+		client:   client,
+		loading:  true,
+		viewport: viewport.New(100, 20),
+	}
+	m.resize(width, height)
+	return m
+}
+
+type mirrorStatusMsg struct {
+	status *kafka.MirrorStatus
+	at     time.Time
+	err    error
+}
+
+func fetchMirrorStatus(client *kafka.Client) tea.Cmd {
+	return func() tea.Msg {
+		status, err := client.GetMirrorStatus()
+		return mirrorStatusMsg{status: status, at: time.Now(), err: err}
+	}
+}
+
+func (m MirrorModel) Init() tea.Cmd {
+	return fetchMirrorStatus(m.client)
+}
+
+func (m MirrorModel) Update(msg tea.Msg) (MirrorModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case mirrorStatusMsg:
+		m.loading = false
+		m.err = msg.err
+		if msg.err != nil {
+			return m, reportError("mirrormaker", msg.err)
+		}
+		m.status, m.fetched = msg.status, msg.at
+		m.refresh()
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.resize(msg.Width, msg.Height)
+		m.refresh()
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			return m, ReturnToListView
+		case "r":
+			m.loading = true
+			return m, fetchMirrorStatus(m.client)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+func (m *MirrorModel) resize(width, height int) {
+	m.width, m.height = width, height
+	if width > 4 {
+		m.viewport.Width = width - 4
+	}
+	if height > 8 {
+		m.viewport.Height = height - 8
+	}
+}
+
+func (m *MirrorModel) refresh() {
+	if m.status != nil {
+		m.viewport.SetContent(renderMirrorStatus(m.status, m.fetched))
+	}
+}
+
+// heartbeatHealth names how long ago a flow's last heartbeat was, coloured
+// by whether the flow looks alive
+func heartbeatHealth(last, now time.Time) string {
+	age := now.Sub(last).Round(time.Second)
+	text := fmt.Sprintf("%s ago", max(age, 0))
+	switch {
+	case age >= mirrorHeartbeatStale:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(text + "  stale")
+	case age >= mirrorHeartbeatLate:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render(text + "  late")
+	default:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("46")).Render(text)
+	}
+}
+
+// renderMirrorStatus lists the flows, the checkpointed group offsets and the
+// internal topics MM2 keeps on this cluster
+func renderMirrorStatus(status *kafka.MirrorStatus, now time.Time) string {
+	if len(status.Topics) == 0 {
+		return "No MirrorMaker 2 topics found on this cluster."
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	var sb strings.Builder
+	sb.WriteString(headerStyle.Render("Replication flows"))
+	sb.WriteString("\n")
+	if len(status.Flows) == 0 {
+		sb.WriteString(dimStyle.Render("No heartbeats found"))
+		sb.WriteString("\n")
+	} else {
+		sb.WriteString(dimStyle.Render(fmt.Sprintf("%-32s  %-28s  %13s  %s", "Flow", "Seen in", "Remote topics", "Last heartbeat")))
+		sb.WriteString("\n")
+		for _, f := range status.Flows {
+			flow := fmt.Sprintf("%s → %s", f.Source, f.Target)
+			sb.WriteString(fmt.Sprintf("%-32s  %-28s  %13d  %s\n", truncateString(flow, 32), truncateString(f.Topic, 28),
+				f.RemoteTopics, heartbeatHealth(f.LastHeartbeat, now)))
+		}
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(headerStyle.Render("Checkpointed consumer offsets"))
+	sb.WriteString("\n")
+	if len(status.Checkpoints) == 0 {
+		sb.WriteString(dimStyle.Render("No checkpoints found"))
+		sb.WriteString("\n")
+	} else {
+		sb.WriteString(dimStyle.Render(fmt.Sprintf("%-12s  %-28s  %-28s  %9s  %12s  %12s  %s",
+			"From", "Group", "Topic", "Partition", "Upstream", "Downstream", "Checkpointed")))
+		sb.WriteString("\n")
+		for _, cp := range status.Checkpoints {
+			age := "-"
+			if !cp.At.IsZero() {
+				age = fmt.Sprintf("%s ago", max(now.Sub(cp.At).Round(time.Second), 0))
+			}
+			sb.WriteString(fmt.Sprintf("%-12s  %-28s  %-28s  %9d  %12d  %12d  %s\n", truncateString(cp.Source, 12),
+				truncateString(cp.Group, 28), truncateString(cp.Topic, 28), cp.Partition, cp.UpstreamOffset, cp.DownstreamOffset, age))
+		}
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(headerStyle.Render("Internal topics"))
+	sb.WriteString("\n")
+	for _, t := range status.Topics {
+		sb.WriteString(fmt.Sprintf("%-48s  %s\n", t.Name, dimStyle.Render(string(t.Role))))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (m MirrorModel) View() string {
+	var s strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Padding(0, 1)
+	s.WriteString(titleStyle.Render("🪞 MirrorMaker 2"))
+	s.WriteString("\n\n")
+
+	switch {
+	case m.loading && m.status == nil:
+		s.WriteString("Reading MirrorMaker 2 topics...")
+	case m.err != nil && m.status == nil:
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(fmt.Sprintf("Error: %v", m.err)))
+	default:
+		s.WriteString(m.viewport.View())
+	}
+	s.WriteString("\n\n")
+
+	help := "↑/↓: Scroll | r: Refresh | Esc: Back"
+	if !m.fetched.IsZero() {
+		help += " | as of " + m.fetched.Format("15:04:05")
+	}
+	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(help))
+	return s.String()
+}
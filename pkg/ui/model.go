@@ -1,14 +1,22 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/digitalis-io/kconduit/pkg/ai"
+	"github.com/digitalis-io/kconduit/pkg/alerts"
 	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/digitalis-io/kconduit/pkg/logger"
+	"github.com/digitalis-io/kconduit/pkg/schemaregistry"
+	"github.com/digitalis-io/kconduit/pkg/topictags"
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -26,6 +34,21 @@ const (
 	CreateACLView
 	EditACLView
 	DeleteACLView
+	ACLPresetView
+	LogView
+	UndoView
+	LogDirsView
+	LeaderBalanceView
+	DecommissionView
+	ThrottlesView
+	ErrorsView
+	TransactionsView
+	BrokerLoggersView
+	TopicDetailView
+	GroupDetailView
+	GroupWatchView
+	MirrorView
+	KeyLookupView
 )
 
 type TabView int
@@ -35,6 +58,7 @@ const (
 	TopicsTab
 	ConsumerGroupsTab
 	ACLsTab
+	DashboardTab
 )
 
 type Model struct {
@@ -49,10 +73,13 @@ type Model struct {
 	consumerGroups   []kafka.ConsumerGroupInfo
 	acls             []kafka.ACL
 	topicConfig      *kafka.TopicConfig
+	configNonDefault bool // Config pane hides configs left at their default
 	clusterStats     *kafka.ClusterStats
 	err              error
 	loading          bool
 	loadingConfig    bool
+	configFetchSeq   int                // Bumped on every selection, so only the last one is fetched
+	cancelConfig     context.CancelFunc // Cancels the config fetch in flight
 	width            int
 	height           int
 	mode             ViewMode
@@ -62,22 +89,68 @@ type Model struct {
 	createACLModel   *CreateACLHuhModel
 	editACLModel     EditACLHuhModel
 	deleteACLModel   *DeleteACLModel
+	aclPresetModel   *ACLPresetHuhModel
+	logViewerModel   LogViewerModel
+	lagHistory       *lagHistory
+	groupTopicLags   map[string][]kafka.TopicLag // Expanded groups' lag per topic, nil while it is fetched
+	groupRowOwners   []string                    // Group of each Consumer Groups row
+	alertEvaluator   *alerts.Evaluator
+	alertNotifier    *alerts.Notifier
+	activeAlerts     []alerts.Violation
 	editConfigModel  *EditConfigModel
 	aiAssistantModel AIAssistantModel
 	deleteTopicModel DeleteTopicModel
+	undoModel        UndoModel
+	logDirsModel     LogDirsModel
+	leaderBalance    LeaderBalanceModel
+	decommission     DecommissionModel
+	throttles        ThrottlesModel
+	transactions     TransactionsModel
+	brokerLoggers    BrokerLoggersModel
+	topicDetail      TopicDetailModel
+	groupDetail      GroupDetailModel
+	groupWatch       GroupWatchModel
+	mirror           MirrorModel
+	keyLookup        KeyLookupModel
 	selectedTopic    string
 	activeTab        TabView
 	focusedPanel     int // 0: topics list, 1: config table (when in Topics tab)
 	aiEngine         string
 	aiModel          string
+	aiPolicy         ai.Policy
+	aclProposals     []ai.ACLRequest // AI proposed ACLs still to be reviewed
+	schemaRegistry   *schemaregistry.Client
+	aclFilterInput   textinput.Model
+	aclFiltering     bool // Filter input has focus
+	topicTags        *topictags.Store
+	topicTagsCluster string // Key of this cluster's topics in topicTags
+	topicTagFilter   string // Starred topics, a tag, or empty for all topics
+	topicTagInput    textinput.Model
+	topicTagging     bool   // Tag input has focus
+	topicTagTarget   string // Topic whose tags are being edited
+	aclGrouped       bool   // One summary row per principal
+	dashboard        dashboard
+	messageCounts    map[string]int64             // Estimated messages per topic, filled in after the topics
+	topicOverview    map[string]map[string]string // kafka.TopicOverviewConfigs of every topic
+	topicHealth      map[string]kafka.TopicHealth // Replication state of every topic
+	toasts           toasts
+	errors           *errorHistory
+	errorsModel      ErrorsModel
+	disconnected     bool // A background fetch failed to reach the cluster
 }
 
 func NewModel(client *kafka.Client, aiEngine string, aiModel string) Model {
 	// Topics table
 	topicsColumns := []table.Column{
-		{Title: "Topic Name", Width: 30},
-		{Title: "Parts", Width: 8},
-		{Title: "RF", Width: 4},
+		{Title: "Topic Name", Width: 28},
+		{Title: "Parts", Width: 6},
+		{Title: "RF", Width: 3},
+		{Title: "Messages", Width: 12},
+		{Title: "Policy", Width: 8},
+		{Title: "Retention", Width: 10},
+		{Title: "MinISR", Width: 6},
+		{Title: "Health", Width: 14},
+		{Title: "Tags", Width: 14},
 	}
 
 	topicsTable := table.New(
@@ -92,10 +165,11 @@ func NewModel(client *kafka.Client, aiEngine string, aiModel string) Model {
 		{Title: "Host", Width: 20},
 		{Title: "Port", Width: 6},
 		{Title: "Status", Width: 8},
-		{Title: "Version", Width: 8},
+		{Title: "Version", Width: 20},
 		{Title: "Roles", Width: 20},
 		{Title: "Rack", Width: 10},
 		{Title: "Log Dirs", Width: 10},
+		{Title: "Disk Used", Width: 10},
 	}
 
 	brokersTable := table.New(
@@ -154,8 +228,9 @@ func NewModel(client *kafka.Client, aiEngine string, aiModel string) Model {
 		{Title: "Members", Width: 8},
 		{Title: "Topics", Width: 7},
 		{Title: "Lag", Width: 10},
-		{Title: "Coordinator", Width: 12},
+		{Title: "Coordinator", Width: 22},
 		{Title: "State", Width: 10},
+		{Title: "Lag Trend", Width: lagHistorySize + 2},
 	}
 
 	consumersTable := table.New(
@@ -165,6 +240,12 @@ func NewModel(client *kafka.Client, aiEngine string, aiModel string) Model {
 	)
 	consumersTable.SetStyles(s)
 
+	aclFilterInput := textinput.New()
+	aclFilterInput.Prompt = "/ "
+	aclFilterInput.Placeholder = "principal:alice resource:orders op:write"
+	aclFilterInput.CharLimit = 200
+	aclFilterInput.Width = 60
+
 	return Model{
 		topicsTable:    topicsTable,
 		brokersTable:   brokersTable,
@@ -176,9 +257,24 @@ func NewModel(client *kafka.Client, aiEngine string, aiModel string) Model {
 		activeTab:      BrokersTab,
 		aiEngine:       aiEngine,
 		aiModel:        aiModel,
+		aclFilterInput: aclFilterInput,
+		lagHistory:     newLagHistory(),
+		errors:         newErrorHistory(),
 	}
 }
 
+// WithAIPolicy restricts the actions the AI assistant may run
+func (m Model) WithAIPolicy(policy ai.Policy) Model {
+	m.aiPolicy = policy
+	return m
+}
+
+// WithSchemaRegistry enables Schema Registry aware producing
+func (m Model) WithSchemaRegistry(registry *schemaregistry.Client) Model {
+	m.schemaRegistry = registry
+	return m
+}
+
 type tickMsg struct{}
 
 type topicsMsg struct {
@@ -202,10 +298,22 @@ type consumerGroupsMsg struct {
 }
 
 type topicConfigMsg struct {
+	topic  string
 	config *kafka.TopicConfig
 	err    error
 }
 
+// topicConfigDueMsg fires once the selection has settled for
+// topicConfigDebounce
+type topicConfigDueMsg struct {
+	seq   int
+	topic string
+}
+
+// topicConfigDebounce is how long the topic selection must stay put before
+// its config is fetched, so scrolling through the list fetches only once
+const topicConfigDebounce = 150 * time.Millisecond
+
 type aclsMsg struct {
 	acls []kafka.ACL
 	err  error
@@ -222,6 +330,50 @@ func fetchTopics(client *kafka.Client) tea.Cmd {
 	}
 }
 
+type messageCountsMsg struct {
+	counts map[string]int64
+	err    error
+}
+
+func fetchMessageCounts(client *kafka.Client) tea.Cmd {
+	return func() tea.Msg {
+		counts, err := client.GetMessageCounts()
+		return messageCountsMsg{counts: counts, err: err}
+	}
+}
+
+type topicOverviewMsg struct {
+	configs map[string]map[string]string
+	err     error
+}
+
+func fetchTopicOverview(client *kafka.Client, topics []kafka.TopicInfo) tea.Cmd {
+	names := make([]string, len(topics))
+	for i, t := range topics {
+		names[i] = t.Name
+	}
+	return func() tea.Msg {
+		configs, err := client.GetTopicConfigValues(names, kafka.TopicOverviewConfigs)
+		return topicOverviewMsg{configs: configs, err: err}
+	}
+}
+
+type topicHealthMsg struct {
+	health map[string]kafka.TopicHealth
+	err    error
+}
+
+func fetchTopicHealth(client *kafka.Client, topics []kafka.TopicInfo) tea.Cmd {
+	names := make([]string, len(topics))
+	for i, t := range topics {
+		names[i] = t.Name
+	}
+	return func() tea.Msg {
+		health, err := client.GetTopicHealth(names)
+		return topicHealthMsg{health: health, err: err}
+	}
+}
+
 func fetchBrokers(client *kafka.Client) tea.Cmd {
 	return func() tea.Msg {
 		brokers, err := client.GetBrokers()
@@ -251,20 +403,114 @@ func fetchACLs(client *kafka.Client) tea.Cmd {
 }
 
 func fetchTopicConfig(client *kafka.Client, topicName string) tea.Cmd {
+	return fetchTopicConfigContext(context.Background(), client, topicName)
+}
+
+func fetchTopicConfigContext(ctx context.Context, client *kafka.Client, topicName string) tea.Cmd {
 	return func() tea.Msg {
-		config, err := client.GetTopicConfig(topicName)
-		return topicConfigMsg{config: config, err: err}
+		config, err := client.GetTopicConfigContext(ctx, topicName)
+		return topicConfigMsg{topic: topicName, config: config, err: err}
+	}
+}
+
+// scheduleTopicConfig fetches topic's config once the selection settles,
+// cancelling any fetch for an earlier selection
+func (m *Model) scheduleTopicConfig(topic string) tea.Cmd {
+	m.configFetchSeq++
+	if m.cancelConfig != nil {
+		m.cancelConfig()
+		m.cancelConfig = nil
 	}
+	seq := m.configFetchSeq
+	return tea.Tick(topicConfigDebounce, func(time.Time) tea.Msg {
+		return topicConfigDueMsg{seq: seq, topic: topic}
+	})
 }
 
 func (m Model) Init() tea.Cmd {
 	// Add a small delay to allow connection to establish
-	return tea.Tick(time.Millisecond*100, func(t time.Time) tea.Msg {
-		return tickMsg{}
-	})
+	cmds := []tea.Cmd{
+		tea.Tick(time.Millisecond*100, func(t time.Time) tea.Msg {
+			return tickMsg{}
+		}),
+		lagSampleTick(),
+		dashboardTick(),
+	}
+	if m.alertEvaluator != nil {
+		cmds = append(cmds, checkAlerts(m.client))
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Lag sampling runs in the background whatever view is active
+	switch msg := msg.(type) {
+	case ToastMsg:
+		return m, m.toasts.push(msg)
+	case toastExpiredMsg:
+		m.toasts.expire(msg.id)
+		return m, nil
+	case ErrorMsg:
+		m.recordError(msg.Source, msg.Err)
+		if m.mode == ErrorsView {
+			m.errors.markSeen()
+		}
+		return m, nil
+	case lagSampleTickMsg:
+		if m.alertEvaluator != nil {
+			return m, tea.Batch(checkAlerts(m.client), lagSampleTick())
+		}
+		return m, tea.Batch(sampleLag(m.client), lagSampleTick())
+	case lagSampleMsg:
+		cmd := m.connectionChanged(msg.err)
+		if msg.err != nil {
+			logger.Get().WithError(msg.err).Debug("Failed to sample consumer group lag")
+			m.recordError("lag sampling", msg.err)
+			return m, cmd
+		}
+		return m, tea.Batch(cmd, m.recordLagSample(msg.groups))
+	case alertSnapshotMsg:
+		if msg.err != nil {
+			logger.Get().WithError(msg.err).Warn("Failed to check alert rules")
+			m.recordError("alerts", msg.err)
+			cmd := m.connectionChanged(msg.err)
+			if msg.snapshot.Unreachable {
+				cmd = tea.Batch(cmd, m.evaluateAlerts(msg.snapshot))
+			}
+			return m, cmd
+		}
+		cmd := tea.Batch(m.connectionChanged(nil), m.evaluateAlerts(msg.snapshot))
+		return m, tea.Batch(cmd, m.recordLagSample(msg.snapshot.Groups))
+	case alertsNotifiedMsg:
+		if msg.err != nil {
+			logger.Get().WithError(msg.err).Warn("Failed to send alert notifications")
+			m.recordError("alerts", msg.err)
+		}
+		return m, nil
+	case dashboardTickMsg:
+		// Only refresh while the dashboard is on screen
+		if m.mode == ListView && m.activeTab == DashboardTab {
+			return m, tea.Batch(m.refreshDashboard(), dashboardTick())
+		}
+		return m, dashboardTick()
+	case dashboardMsg:
+		if msg.err != nil {
+			logger.Get().WithError(msg.err).Warn("Failed to refresh dashboard")
+			m.recordError("dashboard", msg.err)
+			m.dashboard.err = msg.err
+			return m, m.connectionChanged(msg.err)
+		}
+		m.dashboard.record(msg.snapshot)
+		return m, m.connectionChanged(nil)
+	case latencyProbeMsg:
+		if msg.err != nil {
+			logger.Get().WithError(msg.err).Warn("Latency probe failed")
+			m.recordError("latency probe", msg.err)
+		}
+		m.dashboard.latency, m.dashboard.latencyErr = msg.result, msg.err
+		return m, nil
+	}
+
 	switch m.mode {
 	case ProducerView:
 		return m.updateProducerView(msg)
@@ -284,6 +530,36 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateEditACLView(msg)
 	case DeleteACLView:
 		return m.updateDeleteACLView(msg)
+	case ACLPresetView:
+		return m.updateACLPresetView(msg)
+	case LogView:
+		return m.updateLogView(msg)
+	case UndoView:
+		return m.updateUndoView(msg)
+	case LogDirsView:
+		return m.updateLogDirsView(msg)
+	case LeaderBalanceView:
+		return m.updateLeaderBalanceView(msg)
+	case DecommissionView:
+		return m.updateDecommissionView(msg)
+	case ThrottlesView:
+		return m.updateThrottlesView(msg)
+	case TransactionsView:
+		return m.updateTransactionsView(msg)
+	case BrokerLoggersView:
+		return m.updateBrokerLoggersView(msg)
+	case TopicDetailView:
+		return m.updateTopicDetailView(msg)
+	case KeyLookupView:
+		return m.updateKeyLookupView(msg)
+	case GroupDetailView:
+		return m.updateGroupDetailView(msg)
+	case GroupWatchView:
+		return m.updateGroupWatchView(msg)
+	case MirrorView:
+		return m.updateMirrorView(msg)
+	case ErrorsView:
+		return m.updateErrorsView(msg)
 	default:
 		return m.updateListView(msg)
 	}
@@ -298,9 +574,33 @@ func (m Model) updateListView(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, tea.Batch(fetchTopics(m.client), fetchBrokers(m.client))
 
 	case tea.KeyMsg:
+		if m.activeTab == ACLsTab && m.aclFiltering {
+			return m.updateACLFilter(msg)
+		}
+		if m.activeTab == TopicsTab && m.topicTagging {
+			return m.updateTopicTagInput(msg)
+		}
+
 		switch s := msg.String(); s {
 		case "q", "ctrl+c":
 			return m, tea.Quit
+		case "/":
+			if m.activeTab == ACLsTab && m.aclTable != nil {
+				m.aclFiltering = true
+				return m, m.aclFilterInput.Focus()
+			}
+		case "g":
+			if m.activeTab == ACLsTab && m.aclTable != nil {
+				m.aclGrouped = !m.aclGrouped
+				m.refreshACLTable()
+				return m, nil
+			}
+		case "esc":
+			if m.activeTab == ACLsTab && m.aclTable != nil && m.aclFilterInput.Value() != "" {
+				m.aclFilterInput.SetValue("")
+				m.refreshACLTable()
+				return m, nil
+			}
 		case "tab":
 			// In Topics tab, switch between topics list and config table
 			if m.activeTab == TopicsTab && m.topicConfig != nil {
@@ -335,6 +635,9 @@ func (m Model) updateListView(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.activeTab = ACLsTab
 				return m, fetchACLs(m.client)
 			case ACLsTab:
+				m.activeTab = DashboardTab
+				return m, m.refreshDashboard()
+			case DashboardTab:
 				m.activeTab = BrokersTab
 				m.brokersTable.Focus()
 				return m, fetchBrokers(m.client)
@@ -361,7 +664,8 @@ func (m Model) updateListView(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch m.activeTab {
 			case BrokersTab:
 				m.brokersTable.Blur()
-				m.activeTab = ACLsTab
+				m.activeTab = DashboardTab
+				return m, m.refreshDashboard()
 			case TopicsTab:
 				m.topicsTable.Blur()
 				m.configTable.Blur()
@@ -378,6 +682,9 @@ func (m Model) updateListView(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.activeTab = ConsumerGroupsTab
 				m.consumersTable.Focus()
 				return m, fetchConsumerGroups(m.client)
+			case DashboardTab:
+				m.activeTab = ACLsTab
+				return m, fetchACLs(m.client)
 			}
 			// Trigger refresh when switching tabs
 			return m, tea.Batch(fetchTopics(m.client), fetchBrokers(m.client))
@@ -421,7 +728,27 @@ func (m Model) updateListView(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			m.activeTab = ACLsTab
 			return m, fetchACLs(m.client)
+		case "5":
+			// Switch to Dashboard tab
+			switch m.activeTab {
+			case BrokersTab:
+				m.brokersTable.Blur()
+			case TopicsTab:
+				m.topicsTable.Blur()
+				m.configTable.Blur()
+			case ConsumerGroupsTab:
+				m.consumersTable.Blur()
+			}
+			m.activeTab = DashboardTab
+			return m, m.refreshDashboard()
 		case "r", "R":
+			if m.activeTab == DashboardTab {
+				return m, m.refreshDashboard()
+			}
+			// R refetches the topic list instead of reusing the cached one
+			if msg.String() == "R" {
+				m.client.InvalidateTopicCache()
+			}
 			m.loading = true
 			switch m.activeTab {
 			case ACLsTab:
@@ -443,14 +770,90 @@ func (m Model) updateListView(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.mode = CreateTopicView
 				return m, m.createTopicModel.Init()
 			}
+		case "T":
+			if m.activeTab == ACLsTab {
+				// Grant a role preset
+				m.aclPresetModel = NewACLPresetHuhModel(m.client)
+				m.mode = ACLPresetView
+				return m, m.aclPresetModel.Init()
+			}
+		case "u":
+			// Review and revert this session's changes
+			m.undoModel = NewUndoModel(m.client)
+			m.mode = UndoView
+			return m, m.undoModel.Init()
+		case "b":
+			if m.activeTab == BrokersTab {
+				// Leader distribution and preferred leader election
+				m.leaderBalance = NewLeaderBalanceModel(m.client)
+				m.mode = LeaderBalanceView
+				return m, m.leaderBalance.Init()
+			}
+		case "t":
+			if m.activeTab == BrokersTab {
+				// Replication throttles for reassignments
+				m.throttles = NewThrottlesModel(m.client)
+				m.mode = ThrottlesView
+				return m, m.throttles.Init()
+			}
+		case "l":
+			if m.activeTab == BrokersTab && len(m.brokers) > 0 {
+				// Runtime log4j levels of the selected broker
+				if broker := m.brokers[m.brokersTable.Cursor()]; broker.Status != kafka.BrokerOnline {
+					return m, showToast(toastError, "Broker is "+strings.ToLower(broker.Status))
+				}
+				m.brokerLoggers = NewBrokerLoggersModel(m.client, m.brokers[m.brokersTable.Cursor()])
+				m.mode = BrokerLoggersView
+				return m, m.brokerLoggers.Init()
+			}
+		case "right", "left":
+			if m.activeTab == ConsumerGroupsTab && len(m.consumerGroups) > 0 && !m.loading && m.err == nil {
+				// Show or hide the selected group's lag per topic
+				return m, m.toggleGroupTopics(msg.String() == "right")
+			}
+		case "w":
+			if m.activeTab == ConsumerGroupsTab && len(m.consumerGroups) > 0 && !m.loading && m.err == nil {
+				// Follow the selected group's lag live
+				if groupID, ok := m.selectedGroup(); ok {
+					m.groupWatch = NewGroupWatchModel(m.client, groupID, m.width, m.height)
+					m.mode = GroupWatchView
+					return m, m.groupWatch.Init()
+				}
+			}
+		case "X":
+			// Open and hung transactions
+			m.transactions = NewTransactionsModel(m.client)
+			m.mode = TransactionsView
+			return m, m.transactions.Init()
+		case "M":
+			// MirrorMaker 2 flows and checkpoints
+			m.mirror = NewMirrorModel(m.client, m.width, m.height)
+			m.mode = MirrorView
+			return m, m.mirror.Init()
+		case "!":
+			m.errorsModel = NewErrorsModel(m.errors, m.width, m.height)
+			m.mode = ErrorsView
+			return m, nil
+		case "L":
+			m.logViewerModel = NewLogViewerModel(m.width, m.height)
+			m.mode = LogView
+			return m, m.logViewerModel.Init()
 		case "A", "a":
 			// Open AI Assistant
-			m.aiAssistantModel = NewAIAssistantModel(m.client, m.aiEngine, m.aiModel)
+			m.ensureAIAssistant()
 			m.mode = AIAssistantView
 			return m, m.aiAssistantModel.Init()
 		case "D", "d":
-			// Delete topic or ACL depending on active tab
-			if m.activeTab == TopicsTab && len(m.topics) > 0 && !m.loading && m.err == nil {
+			// Delete topic or ACL depending on active tab; on the Brokers
+			// tab, move every replica off the selected broker
+			if m.activeTab == BrokersTab && len(m.brokers) > 0 {
+				if m.brokers[m.brokersTable.Cursor()].ID < 0 {
+					return m, showToast(toastError, "Broker ID unknown: it is only listed as an unreachable bootstrap server")
+				}
+				m.decommission = NewDecommissionModel(m.client, m.brokers[m.brokersTable.Cursor()])
+				m.mode = DecommissionView
+				return m, m.decommission.Init()
+			} else if m.activeTab == TopicsTab && len(m.topics) > 0 && !m.loading && m.err == nil {
 				selectedRow := m.topicsTable.SelectedRow()
 				if len(selectedRow) > 0 {
 					m.selectedTopic = selectedRow[0]
@@ -458,7 +861,7 @@ func (m Model) updateListView(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.mode = DeleteTopicView
 					return m, m.deleteTopicModel.Init()
 				}
-			} else if m.activeTab == ACLsTab && len(m.acls) > 0 && !m.loading && m.err == nil {
+			} else if m.activeTab == ACLsTab && !m.aclGrouped && len(m.acls) > 0 && !m.loading && m.err == nil {
 				// Delete ACL
 				selectedRow := m.aclTable.SelectedRow()
 				if len(selectedRow) >= 7 {
@@ -477,16 +880,67 @@ func (m Model) updateListView(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, m.deleteACLModel.Init()
 				}
 			}
+		case "s":
+			if m.activeTab == TopicsTab && len(m.topics) > 0 {
+				return m.toggleTopicStar()
+			}
+		case "#":
+			if m.activeTab == TopicsTab && len(m.topics) > 0 {
+				return m.startTopicTagging()
+			}
+		case "f":
+			if m.activeTab == TopicsTab && len(m.topics) > 0 {
+				return m.cycleTopicTagFilter()
+			}
 		case "p", "P":
 			if m.activeTab == TopicsTab && len(m.topics) > 0 && !m.loading && m.err == nil {
 				selectedRow := m.topicsTable.SelectedRow()
 				if len(selectedRow) > 0 {
 					m.selectedTopic = selectedRow[0]
-					m.producerModel = NewProducerModel(m.selectedTopic, m.client)
+					m.producerModel = NewProducerModel(m.selectedTopic, m.client, m.schemaRegistry)
 					m.mode = ProducerView
 					return m, m.producerModel.Init()
 				}
 			}
+		case "K":
+			// Find every message with a key in the selected topic
+			if m.activeTab == TopicsTab && len(m.topics) > 0 && !m.loading && m.err == nil {
+				selectedRow := m.topicsTable.SelectedRow()
+				if len(selectedRow) > 0 {
+					m.keyLookup = NewKeyLookupModel(m.client, selectedRow[0], m.width, m.height)
+					m.mode = KeyLookupView
+					return m, m.keyLookup.Init()
+				}
+			}
+		case "i":
+			// Partition health and offsets of the selected topic
+			if m.activeTab == TopicsTab && len(m.topics) > 0 && !m.loading && m.err == nil {
+				selectedRow := m.topicsTable.SelectedRow()
+				if len(selectedRow) > 0 {
+					m.topicDetail = NewTopicDetailModel(m.client, selectedRow[0], m.width, m.height)
+					m.mode = TopicDetailView
+					return m, m.topicDetail.Init()
+				}
+			}
+		case "x":
+			// Ask the AI assistant to explain the selected topic's config
+			if m.activeTab == TopicsTab && len(m.topics) > 0 && !m.loading && m.err == nil {
+				selectedRow := m.topicsTable.SelectedRow()
+				if len(selectedRow) > 0 {
+					m.ensureAIAssistant()
+					var cmd tea.Cmd
+					m.aiAssistantModel, cmd = m.aiAssistantModel.Explain(selectedRow[0])
+					m.mode = AIAssistantView
+					return m, tea.Batch(m.aiAssistantModel.Init(), cmd)
+				}
+			}
+		case "o":
+			if m.activeTab == TopicsTab && m.topicConfig != nil {
+				// Switch the config pane between all configs and non-default ones
+				m.configNonDefault = !m.configNonDefault
+				m.updateConfigTable()
+				return m, nil
+			}
 		case "e", "E":
 			// Edit config value or ACL
 			if m.activeTab == TopicsTab && m.focusedPanel == 1 && m.topicConfig != nil {
@@ -498,12 +952,12 @@ func (m Model) updateListView(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 					// Get the actual raw value from the config map
 					if rawValue, exists := m.topicConfig.Configs[configKey]; exists {
-						m.editConfigModel = NewEditConfigModel(m.client, m.selectedTopic, configKey, rawValue)
+						m.editConfigModel = NewEditConfigModel(m.client, m.selectedTopic, configKey, rawValue, m.topicConfig.Configs)
 						m.mode = EditConfigView
 						return m, m.editConfigModel.Init()
 					}
 				}
-			} else if m.activeTab == ACLsTab && m.aclTable != nil && len(m.acls) > 0 {
+			} else if m.activeTab == ACLsTab && !m.aclGrouped && m.aclTable != nil && len(m.acls) > 0 {
 				// Edit ACL
 				selectedRow := m.aclTable.SelectedRow()
 				if len(selectedRow) >= 7 {
@@ -523,14 +977,43 @@ func (m Model) updateListView(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 		case "enter":
+			if m.activeTab == ConsumerGroupsTab && len(m.consumerGroups) > 0 && !m.loading && m.err == nil {
+				// Members of the selected group and their partitions
+				if groupID, ok := m.selectedGroup(); ok {
+					m.groupDetail = NewGroupDetailModel(m.client, groupID, m.width, m.height)
+					m.mode = GroupDetailView
+					return m, m.groupDetail.Init()
+				}
+			}
+			if m.activeTab == BrokersTab && len(m.brokers) > 0 && !m.loading && m.err == nil {
+				// Browse the selected broker's log dirs
+				if i := m.brokersTable.Cursor(); i >= 0 && i < len(m.brokers) {
+					if m.brokers[i].Status != kafka.BrokerOnline {
+						return m, showToast(toastError, "Broker is "+strings.ToLower(m.brokers[i].Status))
+					}
+					m.logDirsModel = NewLogDirsModel(m.client, m.brokers[i], m.width, m.height)
+					m.mode = LogDirsView
+					return m, m.logDirsModel.Init()
+				}
+			}
 			if m.activeTab == TopicsTab && len(m.topics) > 0 && !m.loading && m.err == nil {
 				selectedRow := m.topicsTable.SelectedRow()
 				if len(selectedRow) > 0 {
 					m.selectedTopic = selectedRow[0]
-					m.consumerModel = NewConsumerModel(m.selectedTopic, m.client)
+					m.ensureAIAssistant()
+					m.consumerModel = NewConsumerModel(m.selectedTopic, m.client).WithAssistant(m.aiAssistantModel)
 					m.mode = ConsumerView
 					return m, m.consumerModel.Init()
 				}
+			} else if m.activeTab == ACLsTab && m.aclGrouped && m.aclTable != nil {
+				// Drill into the ACLs of the selected principal
+				selectedRow := m.aclTable.SelectedRow()
+				if len(selectedRow) > 0 {
+					m.aclFilterInput.SetValue("principal:" + selectedRow[0])
+					m.aclGrouped = false
+					m.refreshACLTable()
+					return m, nil
+				}
 			}
 		}
 
@@ -538,20 +1021,15 @@ func (m Model) updateListView(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.loading = false
 		if msg.err != nil {
 			m.err = msg.err
+			m.recordError("topics", msg.err)
 			return m, nil
 		}
 		m.topics = msg.topics
 		m.err = nil
-
-		rows := make([]table.Row, len(m.topics))
-		for i, topic := range m.topics {
-			rows[i] = table.Row{
-				topic.Name,
-				fmt.Sprintf("%d", topic.Partitions),
-				fmt.Sprintf("%d", topic.ReplicationFactor),
-			}
-		}
-		m.topicsTable.SetRows(rows)
+		m.refreshTopicsTable()
+		// Counting messages takes two offset requests per broker, so the
+		// table is shown first and the column filled in when they return
+		countsCmd := tea.Batch(fetchMessageCounts(m.client), fetchTopicOverview(m.client, m.topics), fetchTopicHealth(m.client, m.topics))
 
 		// If we have topics and we're on the topics tab, select the first one
 		if len(m.topics) > 0 && m.activeTab == TopicsTab {
@@ -563,12 +1041,55 @@ func (m Model) updateListView(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if len(selectedRow) > 0 {
 				topicName := selectedRow[0]
 				m.selectedTopic = topicName
-				return m, fetchTopicConfig(m.client, topicName)
+				return m, tea.Batch(fetchTopicConfig(m.client, topicName), countsCmd)
 			}
 		}
+		cmds = append(cmds, countsCmd)
+
+	case messageCountsMsg:
+		if msg.err != nil {
+			logger.Get().WithError(msg.err).Warn("Failed to estimate topic message counts")
+			m.recordError("message counts", msg.err)
+			return m, nil
+		}
+		m.messageCounts = msg.counts
+		m.refreshTopicsTable()
+
+	case topicOverviewMsg:
+		if msg.err != nil {
+			logger.Get().WithError(msg.err).Warn("Failed to fetch topic configs for the topics table")
+			m.recordError("topic configs", msg.err)
+			return m, nil
+		}
+		m.topicOverview = msg.configs
+		m.refreshTopicsTable()
+
+	case topicHealthMsg:
+		if msg.err != nil {
+			logger.Get().WithError(msg.err).Warn("Failed to fetch topic health for the topics table")
+			m.recordError("topic health", msg.err)
+			return m, nil
+		}
+		m.topicHealth = msg.health
+		m.refreshTopicsTable()
+
+	case topicConfigDueMsg:
+		if msg.seq != m.configFetchSeq || msg.topic != m.selectedTopic {
+			return m, nil
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		m.cancelConfig = cancel
+		return m, fetchTopicConfigContext(ctx, m.client, msg.topic)
 
 	case topicConfigMsg:
+		// Replies for a topic that is no longer selected are stale
+		if msg.topic != m.selectedTopic {
+			return m, nil
+		}
 		m.loadingConfig = false
+		if msg.err != nil && !errors.Is(msg.err, context.Canceled) {
+			m.recordError("topic config", msg.err)
+		}
 		if msg.err == nil {
 			m.topicConfig = msg.config
 			// Update config table with the configuration
@@ -579,6 +1100,7 @@ func (m Model) updateListView(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.loading = false
 		if msg.err != nil {
 			m.err = msg.err
+			m.recordError("brokers", msg.err)
 			return m, nil
 		}
 		m.brokers = msg.brokers
@@ -605,59 +1127,77 @@ func (m Model) updateListView(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if broker.LogDirCount > 0 {
 				logDirs = fmt.Sprintf("%d", broker.LogDirCount)
 			}
+			diskUsed := "-"
+			if broker.LogDirCount > 0 {
+				diskUsed = formatBytes(broker.DiskUsed)
+			}
+
+			// Offline brokers may be known only by ID or only by address
+			id, host, port := fmt.Sprintf("%d", broker.ID), broker.Host, fmt.Sprintf("%d", broker.Port)
+			if broker.ID < 0 {
+				id = "-"
+			}
+			if host == "" {
+				host, port = "-", "-"
+			}
 
 			rows[i] = table.Row{
-				fmt.Sprintf("%d", broker.ID),
-				broker.Host,
-				fmt.Sprintf("%d", broker.Port),
+				id,
+				host,
+				port,
 				broker.Status,
 				version,
 				role,
 				rack,
 				logDirs,
+				diskUsed,
 			}
 		}
-		m.brokersTable.SetRows(rows)
+		setRowsKeepingCursor(&m.brokersTable, rows, firstColumn)
 		// Also fetch cluster stats when brokers are loaded
 		return m, fetchClusterStats(m.client)
 
 	case clusterStatsMsg:
 		if msg.err == nil {
 			m.clusterStats = msg.stats
+		} else {
+			// Not critical enough to replace the view, but kept for review
+			m.recordError("cluster stats", msg.err)
 		}
-		// Don't set error here as it's not critical
 
 	case consumerGroupsMsg:
 		m.loading = false
 		if msg.err != nil {
 			m.err = msg.err
+			m.recordError("consumer groups", msg.err)
 			return m, nil
 		}
 		m.consumerGroups = msg.groups
 		m.err = nil
+		m.refreshConsumerGroupRows()
+		cmds = append(cmds, m.refreshGroupTopicLags())
 
-		rows := make([]table.Row, len(m.consumerGroups))
-		for i, group := range m.consumerGroups {
-			lag := fmt.Sprintf("%d", group.ConsumerLag)
-			if group.ConsumerLag == 0 {
-				lag = "0"
-			}
-
-			rows[i] = table.Row{
-				group.GroupID,
-				fmt.Sprintf("%d", group.NumMembers),
-				fmt.Sprintf("%d", group.NumTopics),
-				lag,
-				group.Coordinator,
-				group.State,
-			}
+	case groupTopicLagMsg:
+		if _, expanded := m.groupTopicLags[msg.groupID]; !expanded {
+			return m, nil
 		}
-		m.consumersTable.SetRows(rows)
+		if msg.err != nil {
+			delete(m.groupTopicLags, msg.groupID)
+			m.refreshConsumerGroupRows()
+			return m, reportError("group topic lag", msg.err)
+		}
+		if msg.topics == nil {
+			msg.topics = []kafka.TopicLag{}
+		}
+		m.groupTopicLags[msg.groupID] = msg.topics
+		m.refreshConsumerGroupRows()
+		return m, nil
 
 	case aclsMsg:
 		m.loading = false
 		if msg.err != nil {
 			m.err = msg.err
+			m.recordError("acls", msg.err)
 			return m, nil
 		}
 		m.acls = msg.acls
@@ -665,15 +1205,6 @@ func (m Model) updateListView(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Create ACL table if not already created
 		if m.aclTable == nil {
-			aclColumns := []table.Column{
-				{Title: "Principal", Width: 20},
-				{Title: "Resource Type", Width: 15},
-				{Title: "Resource", Width: 25},
-				{Title: "Pattern", Width: 10},
-				{Title: "Operation", Width: 15},
-				{Title: "Permission", Width: 10},
-				{Title: "Host", Width: 15},
-			}
 			t := table.New(
 				table.WithColumns(aclColumns),
 				table.WithFocused(true),
@@ -694,19 +1225,7 @@ func (m Model) updateListView(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.aclTable = &t
 		}
 
-		rows := make([]table.Row, len(m.acls))
-		for i, acl := range m.acls {
-			rows[i] = table.Row{
-				acl.Principal,
-				acl.ResourceType,
-				acl.ResourceName,
-				acl.PatternType,
-				acl.Operation,
-				acl.PermissionType,
-				acl.Host,
-			}
-		}
-		m.aclTable.SetRows(rows)
+		m.refreshACLTable()
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -747,7 +1266,7 @@ func (m Model) updateListView(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if len(oldRow) > 0 && len(newRow) > 0 && oldRow[0] != newRow[0] {
 				m.selectedTopic = newRow[0]
 				m.loadingConfig = true
-				cmds = append(cmds, cmd, fetchTopicConfig(m.client, newRow[0]))
+				cmds = append(cmds, cmd, m.scheduleTopicConfig(newRow[0]))
 			} else {
 				cmds = append(cmds, cmd)
 			}
@@ -772,6 +1291,84 @@ func (m Model) updateListView(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// consumerGroupRows builds the Consumer Groups table rows, including each
+// group's lag sparkline and the topic rows of expanded groups. It also
+// returns the group each row belongs to.
+func (m Model) consumerGroupRows() ([]table.Row, []string) {
+	alerting := m.alertingGroups()
+	rows := make([]table.Row, 0, len(m.consumerGroups))
+	groups := make([]string, 0, len(m.consumerGroups))
+	for _, group := range m.consumerGroups {
+		lag := fmt.Sprintf("%d", group.ConsumerLag)
+		if group.ConsumerLag == 0 {
+			lag = "0"
+		}
+		if alerting[group.GroupID] {
+			lag = "🚨 " + lag
+		}
+
+		rows = append(rows, table.Row{
+			group.GroupID,
+			fmt.Sprintf("%d", group.NumMembers),
+			fmt.Sprintf("%d", group.NumTopics),
+			lag,
+			group.Coordinator,
+			group.State,
+			m.lagHistory.trend(group.GroupID),
+		})
+		groups = append(groups, group.GroupID)
+		if topics, expanded := m.groupTopicLags[group.GroupID]; expanded {
+			for _, row := range topicLagRows(topics) {
+				rows = append(rows, row)
+				groups = append(groups, group.GroupID)
+			}
+		}
+	}
+	return rows, groups
+}
+
+// updateACLFilter edits the ACL filter, applying it as the user types
+func (m Model) updateACLFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		m.aclFiltering = false
+		m.aclFilterInput.Blur()
+		return m, nil
+	case "esc":
+		m.aclFiltering = false
+		m.aclFilterInput.Blur()
+		m.aclFilterInput.SetValue("")
+		m.refreshACLTable()
+		return m, nil
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+
+	var cmd tea.Cmd
+	m.aclFilterInput, cmd = m.aclFilterInput.Update(msg)
+	m.refreshACLTable()
+	return m, cmd
+}
+
+// refreshACLTable rebuilds the ACL table from the loaded ACLs, the filter
+// and the grouping mode
+func (m *Model) refreshACLTable() {
+	if m.aclTable == nil {
+		return
+	}
+	columns, rows := aclTableRows(m.acls, m.aclFilterInput.Value(), m.aclGrouped)
+	key := rowKey(wholeRow)
+	if m.aclGrouped {
+		key = firstColumn
+	}
+	selected := m.aclTable.SelectedRow()
+	// Clear rows first, they may have fewer cells than the new columns
+	m.aclTable.SetRows(nil)
+	m.aclTable.SetColumns(columns)
+	m.aclTable.SetRows(rows)
+	keepCursorOn(m.aclTable, selected, key)
+}
+
 func (m Model) updateProducerView(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
@@ -829,6 +1426,9 @@ func (m Model) updateCreateACLView(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case ViewChangedMsg:
 		if msg.View == ACLsTab {
+			if len(m.aclProposals) > 0 {
+				return m.openProposedACL()
+			}
 			m.mode = ListView
 			m.activeTab = ACLsTab
 			m.loading = true
@@ -843,6 +1443,25 @@ func (m Model) updateCreateACLView(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// ensureAIAssistant creates the AI assistant on first use. Later it is kept
+// so the conversation carries on.
+func (m *Model) ensureAIAssistant() {
+	if m.aiAssistantModel.executor == nil {
+		m.aiAssistantModel = NewAIAssistantModel(m.client, m.aiEngine, m.aiModel).WithPolicy(m.aiPolicy)
+	}
+}
+
+// openProposedACL shows the next ACL the AI assistant proposed in the
+// Create ACL form
+func (m Model) openProposedACL() (tea.Model, tea.Cmd) {
+	req := m.aclProposals[0]
+	m.aclProposals = m.aclProposals[1:]
+	m.createACLModel = newProposedACLModel(m.client, req)
+	m.mode = CreateACLView
+	m.createACLModel.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	return m, m.createACLModel.Init()
+}
+
 func (m Model) updateEditACLView(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
@@ -879,6 +1498,37 @@ func (m Model) updateDeleteACLView(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m Model) updateACLPresetView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ViewChangedMsg:
+		if msg.View == ACLsTab {
+			m.mode = ListView
+			m.activeTab = ACLsTab
+			m.loading = true
+			return m, fetchACLs(m.client)
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+	_, cmd := m.aclPresetModel.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateLogView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case SwitchToListViewMsg:
+		m.mode = ListView
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+	var cmd tea.Cmd
+	m.logViewerModel, cmd = m.logViewerModel.Update(msg)
+	return m, cmd
+}
+
 func (m Model) updateEditConfigView(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
@@ -909,6 +1559,12 @@ func (m Model) updateAIAssistantView(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.loading = true
 		return m, fetchTopics(m.client)
 
+	case aiACLProposalMsg:
+		updatedModel, _ := m.aiAssistantModel.Update(msg)
+		m.aiAssistantModel = updatedModel.(AIAssistantModel)
+		m.aclProposals = append(m.aclProposals, msg.requests...)
+		return m.openProposedACL()
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -941,7 +1597,216 @@ func (m Model) updateDeleteTopicView(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m Model) updateUndoView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case SwitchToListViewMsg:
+		// Reload whatever the undone changes may have touched
+		m.mode = ListView
+		m.loading = true
+		cmds := []tea.Cmd{fetchTopics(m.client)}
+		if m.activeTab == ACLsTab {
+			cmds = append(cmds, fetchACLs(m.client))
+		}
+		if m.selectedTopic != "" {
+			cmds = append(cmds, fetchTopicConfig(m.client, m.selectedTopic))
+		}
+		return m, tea.Batch(cmds...)
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	var cmd tea.Cmd
+	m.undoModel, cmd = m.undoModel.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateLogDirsView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case SwitchToListViewMsg:
+		m.mode = ListView
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	var cmd tea.Cmd
+	m.logDirsModel, cmd = m.logDirsModel.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateLeaderBalanceView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case SwitchToListViewMsg:
+		m.mode = ListView
+		return m, fetchBrokers(m.client)
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	var cmd tea.Cmd
+	m.leaderBalance, cmd = m.leaderBalance.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateDecommissionView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case SwitchToListViewMsg:
+		m.mode = ListView
+		return m, tea.Batch(fetchBrokers(m.client), fetchClusterStats(m.client))
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	var cmd tea.Cmd
+	m.decommission, cmd = m.decommission.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateThrottlesView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case SwitchToListViewMsg:
+		m.mode = ListView
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	var cmd tea.Cmd
+	m.throttles, cmd = m.throttles.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateTransactionsView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case SwitchToListViewMsg:
+		m.mode = ListView
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	var cmd tea.Cmd
+	m.transactions, cmd = m.transactions.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateBrokerLoggersView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case SwitchToListViewMsg:
+		m.mode = ListView
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	var cmd tea.Cmd
+	m.brokerLoggers, cmd = m.brokerLoggers.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateTopicDetailView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case SwitchToListViewMsg:
+		m.mode = ListView
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	var cmd tea.Cmd
+	m.topicDetail, cmd = m.topicDetail.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateKeyLookupView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case SwitchToListViewMsg:
+		m.mode = ListView
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	var cmd tea.Cmd
+	m.keyLookup, cmd = m.keyLookup.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateGroupDetailView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case SwitchToListViewMsg:
+		m.mode = ListView
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	var cmd tea.Cmd
+	m.groupDetail, cmd = m.groupDetail.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateGroupWatchView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case SwitchToListViewMsg:
+		m.mode = ListView
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	var cmd tea.Cmd
+	m.groupWatch, cmd = m.groupWatch.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateMirrorView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case SwitchToListViewMsg:
+		m.mode = ListView
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	var cmd tea.Cmd
+	m.mirror, cmd = m.mirror.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateErrorsView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case SwitchToListViewMsg:
+		m.mode = ListView
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	var cmd tea.Cmd
+	m.errorsModel, cmd = m.errorsModel.Update(msg)
+	return m, cmd
+}
+
 func (m Model) View() string {
+	return ASCII(m.toasts.overlay(m.view(), m.width))
+}
+
+func (m Model) view() string {
 	switch m.mode {
 	case ProducerView:
 		return m.producerModel.View()
@@ -955,12 +1820,42 @@ func (m Model) View() string {
 		return m.editACLModel.View()
 	case DeleteACLView:
 		return m.deleteACLModel.View()
+	case ACLPresetView:
+		return m.aclPresetModel.View()
+	case LogView:
+		return m.logViewerModel.View()
 	case EditConfigView:
 		return m.editConfigModel.View()
 	case AIAssistantView:
 		return m.aiAssistantModel.View()
 	case DeleteTopicView:
 		return m.deleteTopicModel.View()
+	case UndoView:
+		return m.undoModel.View()
+	case LogDirsView:
+		return m.logDirsModel.View()
+	case LeaderBalanceView:
+		return m.leaderBalance.View()
+	case DecommissionView:
+		return m.decommission.View()
+	case ThrottlesView:
+		return m.throttles.View()
+	case TransactionsView:
+		return m.transactions.View()
+	case BrokerLoggersView:
+		return m.brokerLoggers.View()
+	case TopicDetailView:
+		return m.topicDetail.View()
+	case KeyLookupView:
+		return m.keyLookup.View()
+	case GroupDetailView:
+		return m.groupDetail.View()
+	case GroupWatchView:
+		return m.groupWatch.View()
+	case MirrorView:
+		return m.mirror.View()
+	case ErrorsView:
+		return m.errorsModel.View()
 	default:
 		return m.listView()
 	}
@@ -973,6 +1868,10 @@ func (m Model) listView() string {
 	tabBar := m.renderTabBar()
 	sb.WriteString(tabBar)
 	sb.WriteString("\n\n")
+	if banner := m.renderAlertBanner(); banner != "" {
+		sb.WriteString(banner)
+		sb.WriteString("\n\n")
+	}
 
 	if m.loading {
 		sb.WriteString("Loading...")
@@ -996,6 +1895,8 @@ func (m Model) listView() string {
 		content = m.renderConsumerGroupsView()
 	case ACLsTab:
 		content = m.renderACLsView()
+	case DashboardTab:
+		content = m.renderDashboardView()
 	}
 
 	sb.WriteString(content)
@@ -1009,7 +1910,7 @@ func (m Model) listView() string {
 }
 
 func (m Model) renderTabBar() string {
-	tabs := []string{"Brokers", "Topics", "Consumer Groups", "ACLs"}
+	tabs := []string{"Brokers", "Topics", "Consumer Groups", "ACLs", "Dashboard"}
 
 	activeTabStyle := lipgloss.NewStyle().
 		Bold(true).
@@ -1039,6 +1940,10 @@ func (m Model) renderTabBar() string {
 		Foreground(lipgloss.Color("229"))
 
 	title := titleStyle.Render("🚀 KConduit - Kafka Management")
+	if n := m.errors.unseen(); n > 0 {
+		badgeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Bold(true)
+		title += "  " + badgeStyle.Render(fmt.Sprintf("⚠️  %d new errors (!)", n))
+	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, title, tabBar)
 }
@@ -1145,6 +2050,36 @@ func (m Model) renderBrokersView() string {
 	}
 	infoContent.WriteString("\n\n")
 
+	// Brokers per rack
+	rackBrokers := make(map[string]int)
+	unracked := 0
+	for _, broker := range m.brokers {
+		if broker.Rack != "" {
+			rackBrokers[broker.Rack]++
+		} else {
+			unracked++
+		}
+	}
+	if len(rackBrokers) > 0 {
+		rackNames := make([]string, 0, len(rackBrokers))
+		for rack := range rackBrokers {
+			rackNames = append(rackNames, rack)
+		}
+		sort.Strings(rackNames)
+		infoContent.WriteString(labelStyle.Render("Racks: "))
+		infoContent.WriteString(valueStyle.Render(fmt.Sprintf("%d", len(rackNames))))
+		for _, rack := range rackNames {
+			infoContent.WriteString("\n")
+			infoContent.WriteString(labelStyle.Render(fmt.Sprintf("  %s: ", rack)))
+			infoContent.WriteString(valueStyle.Render(fmt.Sprintf("%d brokers", rackBrokers[rack])))
+		}
+		if unracked > 0 {
+			infoContent.WriteString("\n")
+			infoContent.WriteString(errorStyle.Render(fmt.Sprintf("  ⚠️  %d without a rack", unracked)))
+		}
+		infoContent.WriteString("\n\n")
+	}
+
 	// Replica Status
 	infoContent.WriteString(titleStyle.Render("📈 Replica Status"))
 	infoContent.WriteString("\n\n")
@@ -1234,7 +2169,11 @@ func (m Model) renderTopicsView() string {
 	}
 
 	// Join panels horizontally
-	return lipgloss.JoinHorizontal(lipgloss.Top, topicsView, " ", configView)
+	panels := lipgloss.JoinHorizontal(lipgloss.Top, topicsView, " ", configView)
+	if status := m.renderTopicTagStatus(); status != "" {
+		return status + "\n" + panels
+	}
+	return panels
 }
 
 // updateConfigTable populates the config table with topic configuration
@@ -1253,6 +2192,9 @@ func (m *Model) updateConfigTable() {
 		if strings.HasPrefix(k, "confluent.") || strings.HasPrefix(k, "leader.") || strings.HasPrefix(k, "follower.") {
 			continue
 		}
+		if m.configNonDefault && m.topicConfig.IsDefault(k) {
+			continue
+		}
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
@@ -1261,6 +2203,10 @@ func (m *Model) updateConfigTable() {
 	for _, key := range keys {
 		val := m.topicConfig.Configs[key]
 		formattedVal := m.formatConfigValue(key, val)
+		// Say where each override is set when only overrides are listed
+		if source := m.topicConfig.Sources[key]; m.configNonDefault && source != "" {
+			formattedVal += " (" + source + ")"
+		}
 		rows = append(rows, table.Row{key, formattedVal})
 	}
 
@@ -1309,7 +2255,25 @@ func (m Model) renderTopicConfig() string {
 	infoStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
 	sb.WriteString(infoStyle.Render(fmt.Sprintf("Partitions: %d | Replication: %d",
 		m.topicConfig.Partitions, m.topicConfig.ReplicationFactor)))
-	sb.WriteString("\n\n")
+	sb.WriteString("\n")
+	if count, ok := m.messageCounts[m.topicConfig.Name]; ok {
+		sb.WriteString(infoStyle.Render(fmt.Sprintf("Messages: ~%s (high − low watermark)", groupThousands(int(count)))))
+		sb.WriteString("\n")
+		if strings.Contains(m.topicConfig.Configs["cleanup.policy"], "compact") {
+			sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render(
+				"⚠️  Compacted: records removed by compaction still count, so this is an upper bound"))
+			sb.WriteString("\n")
+		}
+	}
+	if racks := m.renderRackSpread(); racks != "" {
+		sb.WriteString(racks)
+		sb.WriteString("\n")
+	}
+	if m.configNonDefault {
+		sb.WriteString(infoStyle.Render("Only configs set on the topic or brokers (o: show all)"))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
 
 	// Render the Bubble Tea table
 	sb.WriteString(m.configTable.View())
@@ -1317,6 +2281,100 @@ func (m Model) renderTopicConfig() string {
 	return sb.String()
 }
 
+// refreshTopicsTable rebuilds the topic rows from m.topics and the message
+// counts fetched so far
+func (m *Model) refreshTopicsTable() {
+	rows := make([]table.Row, 0, len(m.topics))
+	for _, topic := range m.topics {
+		if !m.topicShown(topic.Name) {
+			continue
+		}
+		messages := "…"
+		if m.messageCounts != nil {
+			messages = "-"
+			if count, ok := m.messageCounts[topic.Name]; ok {
+				messages = "~" + groupThousands(int(count))
+				// Compaction leaves gaps, so the estimate is only an upper bound
+				if strings.Contains(m.topicOverview[topic.Name]["cleanup.policy"], "compact") {
+					messages = "≤" + groupThousands(int(count))
+				}
+			}
+		}
+		policy, retention, minISR := "…", "…", "…"
+		if m.topicOverview != nil {
+			configs := m.topicOverview[topic.Name]
+			policy = pick(configs["cleanup.policy"] != "", configs["cleanup.policy"], "-")
+			retention = m.formatRetention(configs["retention.ms"], configs["retention.bytes"])
+			minISR = pick(configs["min.insync.replicas"] != "", configs["min.insync.replicas"], "-")
+		}
+		rows = append(rows, table.Row{
+			topic.Name,
+			fmt.Sprintf("%d", topic.Partitions),
+			fmt.Sprintf("%d", topic.ReplicationFactor),
+			messages,
+			policy,
+			retention,
+			minISR,
+			m.topicHealthCell(topic.Name),
+			m.topicTagsCell(topic.Name),
+		})
+	}
+	setRowsKeepingCursor(&m.topicsTable, rows, firstColumn)
+}
+
+// formatRetention combines the time and size retention of a topic, e.g.
+// "7d" or "7d/1.0GB"
+func (m Model) formatRetention(ms, bytes string) string {
+	if ms == "" {
+		return "-"
+	}
+	retention := m.formatConfigValue("retention.ms", ms)
+	if bytes != "" && bytes != "-1" {
+		retention += "/" + m.formatConfigValue("retention.bytes", bytes)
+	}
+	return retention
+}
+
+// renderRackSpread summarises whether the selected topic's replicas span the
+// cluster's racks; it is empty when no broker has a rack
+func (m Model) renderRackSpread() string {
+	racks := make(map[int32]string, len(m.brokers))
+	for _, b := range m.brokers {
+		racks[b.ID] = b.Rack
+	}
+	spread := kafka.CheckRackSpread(m.topicConfig.PartitionDetails, racks)
+	if spread.Racks == 0 {
+		return ""
+	}
+
+	okStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	if spread.OK() {
+		return okStyle.Render(fmt.Sprintf("Racks: ✅ replicas span %d racks", spread.Racks))
+	}
+
+	var lines []string
+	if len(spread.SingleRack) > 0 {
+		lines = append(lines, warnStyle.Render(fmt.Sprintf("⚠️  All replicas in one rack: %s", partitionList(spread.SingleRack))))
+	}
+	if len(spread.Narrow) > 0 {
+		lines = append(lines, warnStyle.Render(fmt.Sprintf("⚠️  Fewer racks than replicas allow: %s", partitionList(spread.Narrow))))
+	}
+	if len(spread.Unracked) > 0 {
+		lines = append(lines, warnStyle.Render(fmt.Sprintf("⚠️  Replicas on brokers without a rack: %s", partitionList(spread.Unracked))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// partitionList formats partition IDs as "p0, p3, p7", eliding long lists
+func partitionList(ids []int32) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprintf("p%d", id)
+	}
+	return summarizeList(parts, 8)
+}
+
 // formatConfigValue formats config values to be human-readable
 func (m Model) formatConfigValue(key, value string) string {
 	// Convert milliseconds to human readable
@@ -1398,6 +2456,23 @@ func (m Model) renderACLsView() string {
 	sb.WriteString(titleStyle.Render("🔐 Access Control Lists (ACLs)"))
 	sb.WriteString("\n\n")
 
+	// Filter and grouping status
+	if m.aclTable != nil && len(m.acls) > 0 {
+		statusStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("86"))
+		if m.aclFiltering || m.aclFilterInput.Value() != "" {
+			sb.WriteString(m.aclFilterInput.View())
+			sb.WriteString("\n")
+		}
+		shown := len(filterACLs(m.acls, m.aclFilterInput.Value()))
+		status := fmt.Sprintf("Showing %d of %d ACLs", shown, len(m.acls))
+		if m.aclGrouped {
+			status += fmt.Sprintf(" • grouped into %d principals", len(m.aclTable.Rows()))
+		}
+		sb.WriteString(statusStyle.Render(status))
+		sb.WriteString("\n\n")
+	}
+
 	// Render ACL table
 	if m.aclTable != nil {
 		if len(m.acls) == 0 {
@@ -1424,22 +2499,35 @@ func (m Model) renderACLsView() string {
 }
 
 func (m Model) getHelpText() string {
-	baseHelp := "→/←: Switch tabs | 1-4: Jump to tab | r: Refresh | A: AI Assistant | q: Quit"
+	baseHelp := "→/←: Switch tabs | 1-5: Jump to tab | r/R: Refresh/Reload | A: AI Assistant | u: Undo | X: Transactions | M: MirrorMaker | L: Logs | !: Errors | q: Quit"
 
 	switch m.activeTab {
+	case BrokersTab:
+		return baseHelp + " | Enter: Log dirs | b: Leader balance | D: Decommission | t: Throttles | l: Loggers"
 	case TopicsTab:
+		if m.topicTagging {
+			return "Type tags, comma separated | Enter: Save | Esc: Cancel"
+		}
 		if m.topicConfig != nil {
 			if m.focusedPanel == 1 {
-				return baseHelp + " | Tab: Switch panel | e: Edit Config | Enter: Consume | P: Produce | D: Delete Topic"
+				return baseHelp + " | Tab: Switch panel | e: Edit Config | o: Non-default only | x: Explain | i: Partitions | K: Find by key | Enter: Consume | P: Produce | D: Delete Topic"
 			}
-			return baseHelp + " | Tab: Switch panel | Enter: Consume | P: Produce | C: Create Topic | D: Delete Topic"
+			return baseHelp + " | Tab: Switch panel | o: Non-default only | Enter: Consume | P: Produce | i: Partitions | K: Find by key | x: Explain | C: Create Topic | D: Delete Topic" + m.topicTagsHelp()
 		}
-		return baseHelp + " | Enter: Consume | P: Produce | C: Create Topic | D: Delete Topic"
+		return baseHelp + " | Enter: Consume | P: Produce | i: Partitions | K: Find by key | C: Create Topic | D: Delete Topic" + m.topicTagsHelp()
+	case ConsumerGroupsTab:
+		return baseHelp + " | Enter: Members | →/←: Topic lag | w: Watch lag"
 	case ACLsTab:
+		if m.aclFiltering {
+			return "Type to filter (principal:, resource:, op:) | Enter: Apply | Esc: Clear"
+		}
+		if m.aclGrouped {
+			return baseHelp + " | /: Filter | g: Ungroup | Enter: Show principal's ACLs | C: Create ACL | T: Grant role"
+		}
 		if len(m.acls) > 0 {
-			return baseHelp + " | C: Create ACL | e: Edit ACL | D: Delete ACL"
+			return baseHelp + " | /: Filter | g: Group by principal | C: Create ACL | T: Grant role | e: Edit ACL | D: Delete ACL"
 		}
-		return baseHelp + " | C: Create ACL"
+		return baseHelp + " | C: Create ACL | T: Grant role"
 	default:
 		return baseHelp
 	}
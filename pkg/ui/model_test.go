@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+func TestTopicConfigDebounce(t *testing.T) {
+	m := Model{}
+	cancelled := false
+	m.cancelConfig = func() { cancelled = true }
+
+	// Scrolling past events onto orders supersedes the first schedule and
+	// cancels the fetch that was in flight
+	m.scheduleTopicConfig("events")
+	if !cancelled || m.cancelConfig != nil {
+		t.Error("fetch in flight was not cancelled by a new selection")
+	}
+	m.scheduleTopicConfig("orders")
+	m.selectedTopic = "orders"
+
+	stale := []topicConfigDueMsg{
+		{seq: 1, topic: "events"},
+		{seq: 1, topic: "orders"},
+		{seq: 2, topic: "events"},
+	}
+	for _, msg := range stale {
+		updated, cmd := m.Update(msg)
+		if cmd != nil || updated.(Model).cancelConfig != nil {
+			t.Errorf("superseded %+v started a fetch", msg)
+		}
+	}
+
+	updated, cmd := m.Update(topicConfigDueMsg{seq: 2, topic: "orders"})
+	if cmd == nil || updated.(Model).cancelConfig == nil {
+		t.Error("settled selection was not fetched")
+	}
+}
+
+func TestTopicsTableOverviewColumns(t *testing.T) {
+	columns := make([]table.Column, 9)
+	for i := range columns {
+		columns[i] = table.Column{Title: "C", Width: 10}
+	}
+	m := Model{
+		topicsTable: table.New(table.WithColumns(columns), table.WithHeight(10)),
+		topics: []kafka.TopicInfo{
+			{Name: "orders", Partitions: 3, ReplicationFactor: 3},
+			{Name: "state", Partitions: 1, ReplicationFactor: 3},
+			{Name: "legacy", Partitions: 1, ReplicationFactor: 1},
+		},
+		messageCounts: map[string]int64{"orders": 1200, "state": 40},
+	}
+	cells := func() [][]string {
+		var got [][]string
+		for _, row := range m.topicsTable.Rows() {
+			got = append(got, row[3:7])
+		}
+		return got
+	}
+
+	// Until the configs arrive the columns show they are loading
+	m.refreshTopicsTable()
+	if got := cells()[0]; !reflect.DeepEqual(got, []string{"~1,200", "…", "…", "…"}) {
+		t.Errorf("before configs = %q", got)
+	}
+
+	m.topicOverview = map[string]map[string]string{
+		"orders": {"cleanup.policy": "delete", "retention.ms": "604800000", "retention.bytes": "1073741824", "min.insync.replicas": "2"},
+		"state":  {"cleanup.policy": "compact", "retention.ms": "-1", "retention.bytes": "-1", "min.insync.replicas": "1"},
+	}
+	m.refreshTopicsTable()
+	want := [][]string{
+		{"~1,200", "delete", "7d/1.0GB", "2"},
+		// Compaction makes the count an upper bound
+		{"≤40", "compact", "unlimited", "1"},
+		{"-", "-", "-", "-"},
+	}
+	if got := cells(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
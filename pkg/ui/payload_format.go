@@ -0,0 +1,232 @@
+package ui
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PayloadKind is the rendering picked for a message payload by detectPayload
+type PayloadKind int
+
+const (
+	PayloadEmpty PayloadKind = iota
+	PayloadText
+	PayloadJSON
+	PayloadBinary
+	// Confluent wire format: magic byte 0 and a 4 byte schema id. Avro and
+	// Protobuf bodies cannot be decoded without the schema, they are labelled
+	// and shown as binary.
+	PayloadAvro
+	PayloadProtobuf
+	PayloadJSONSchema
+)
+
+func (k PayloadKind) String() string {
+	switch k {
+	case PayloadText:
+		return "text"
+	case PayloadJSON:
+		return "json"
+	case PayloadBinary:
+		return "binary"
+	case PayloadAvro:
+		return "avro"
+	case PayloadProtobuf:
+		return "protobuf"
+	case PayloadJSONSchema:
+		return "json-schema"
+	}
+	return ""
+}
+
+// maxGunzipBytes caps how much of a gzip payload is inflated, anything larger
+// is shown as the compressed bytes
+const maxGunzipBytes = 1 << 20
+
+// gzipMagic starts every gzip stream: ID1, ID2 and the deflate method
+var gzipMagic = []byte{0x1f, 0x8b, 0x08}
+
+// decodedPayload is a payload with its detected format and the bytes that
+// should be rendered, i.e. without framing and decompressed
+type decodedPayload struct {
+	Kind     PayloadKind
+	Gzip     bool
+	SchemaID int32
+	Body     string
+}
+
+// Label names the detected format for the table and detail view, e.g. "json",
+// "gzip/json" or "avro #12"
+func (p decodedPayload) Label() string {
+	label := p.Kind.String()
+	if p.SchemaID > 0 {
+		label = fmt.Sprintf("%s #%d", label, p.SchemaID)
+	}
+	if p.Gzip {
+		label = "gzip/" + label
+	}
+	return label
+}
+
+// Textual reports whether Body can be printed as-is
+func (p decodedPayload) Textual() bool {
+	return p.Kind == PayloadText || p.Kind == PayloadJSON || p.Kind == PayloadJSONSchema
+}
+
+// detectPayload works out how to render s: gzip streams are inflated, the
+// Confluent schema registry framing is recognised by its magic byte, and the
+// rest is classified as JSON, UTF-8 text or binary
+func detectPayload(s string) decodedPayload {
+	if s == "" {
+		return decodedPayload{Kind: PayloadEmpty}
+	}
+	if strings.HasPrefix(s, string(gzipMagic)) {
+		if inflated, ok := gunzip(s); ok {
+			p := detectPayload(inflated)
+			p.Gzip = true
+			return p
+		}
+	}
+	if p, ok := detectConfluent(s); ok {
+		return p
+	}
+	return decodedPayload{Kind: classifyText(s), Body: s}
+}
+
+// classifyText picks JSON, text or binary for an unframed payload
+func classifyText(s string) PayloadKind {
+	if isBinary(s) {
+		return PayloadBinary
+	}
+	if looksLikeJSON(s) {
+		return PayloadJSON
+	}
+	return PayloadText
+}
+
+// looksLikeJSON only accepts objects and arrays, a bare number or quoted
+// string reads better as plain text
+func looksLikeJSON(s string) bool {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return false
+	}
+	return json.Valid([]byte(trimmed))
+}
+
+// gunzip inflates s, failing on corrupt streams and ones over maxGunzipBytes
+func gunzip(s string) (string, bool) {
+	r, err := gzip.NewReader(strings.NewReader(s))
+	if err != nil {
+		return "", false
+	}
+	defer r.Close()
+	data, err := io.ReadAll(io.LimitReader(r, maxGunzipBytes+1))
+	if err != nil || len(data) > maxGunzipBytes {
+		return "", false
+	}
+	return string(data), true
+}
+
+// detectConfluent recognises the schema registry wire format. The framing is
+// the same for every schema type, so the body decides: JSON means JSON Schema,
+// a well-formed message index list followed by protobuf fields means Protobuf
+// and anything else is assumed to be Avro.
+func detectConfluent(s string) (decodedPayload, bool) {
+	if len(s) < 5 || s[0] != 0 {
+		return decodedPayload{}, false
+	}
+	id := int32(binary.BigEndian.Uint32([]byte(s[1:5])))
+	if id <= 0 {
+		return decodedPayload{}, false
+	}
+	body := s[5:]
+	if !isBinary(body) && looksLikeJSON(body) {
+		return decodedPayload{Kind: PayloadJSONSchema, SchemaID: id, Body: body}, true
+	}
+	if rest, ok := skipMessageIndexes([]byte(body)); ok && len(rest) > 0 && isProtobufMessage(rest) {
+		return decodedPayload{Kind: PayloadProtobuf, SchemaID: id, Body: string(rest)}, true
+	}
+	return decodedPayload{Kind: PayloadAvro, SchemaID: id, Body: body}, true
+}
+
+// skipMessageIndexes strips the Protobuf message index list that follows the
+// schema id: a zig-zag varint count then that many zig-zag varints, with a
+// single 0 byte as shorthand for the first message
+func skipMessageIndexes(b []byte) ([]byte, bool) {
+	count, n := binary.Varint(b)
+	if n <= 0 || count < 0 || count > 16 {
+		return nil, false
+	}
+	b = b[n:]
+	for i := int64(0); i < count; i++ {
+		idx, n := binary.Varint(b)
+		if n <= 0 || idx < 0 {
+			return nil, false
+		}
+		b = b[n:]
+	}
+	return b, true
+}
+
+// isProtobufMessage reports whether b parses cleanly as protobuf wire format
+// fields. It is a heuristic, random bytes rarely survive the tag checks.
+func isProtobufMessage(b []byte) bool {
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 || tag>>3 == 0 {
+			return false
+		}
+		b = b[n:]
+		switch tag & 7 {
+		case 0:
+			if _, n = binary.Uvarint(b); n <= 0 {
+				return false
+			}
+			b = b[n:]
+		case 1:
+			if len(b) < 8 {
+				return false
+			}
+			b = b[8:]
+		case 2:
+			size, n := binary.Uvarint(b)
+			if n <= 0 || size > uint64(len(b)-n) {
+				return false
+			}
+			b = b[n+int(size):]
+		case 5:
+			if len(b) < 4 {
+				return false
+			}
+			b = b[4:]
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// indentJSON pretty prints a JSON document for the detail view, returning s
+// unchanged if it does not parse
+func indentJSON(s string) string {
+	var out bytes.Buffer
+	if err := json.Indent(&out, []byte(strings.TrimSpace(s)), "", "  "); err != nil {
+		return s
+	}
+	return out.String()
+}
+
+// copyableValue copies decoded text such as inflated gzip JSON as-is, and the
+// original bytes encoded otherwise so framing is preserved
+func copyableValue(raw string, payload decodedPayload, format BinaryFormat) string {
+	if payload.Textual() {
+		return payload.Body
+	}
+	return copyablePayload(raw, format)
+}
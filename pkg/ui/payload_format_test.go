@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+)
+
+func gzipString(t *testing.T, s string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestDetectPayload(t *testing.T) {
+	framed := func(id byte, body string) string {
+		return string([]byte{0, 0, 0, 0, id}) + body
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		label string
+		body  string
+	}{
+		{"empty", "", "", ""},
+		{"plain text", "hello world", "text", "hello world"},
+		{"json object", `{"a":1}`, "json", `{"a":1}`},
+		{"bare number is text", "42", "text", "42"},
+		{"invalid utf-8", "\xff\xfe", "binary", "\xff\xfe"},
+		{"gzip json", gzipString(t, `{"a":1}`), "gzip/json", `{"a":1}`},
+		{"gzip text", gzipString(t, "hello"), "gzip/text", "hello"},
+		{"json schema", framed(7, `{"a":1}`), "json-schema #7", `{"a":1}`},
+		// index shorthand 0, then field 1 varint 150
+		{"protobuf", framed(3, "\x00\x08\x96\x01"), "protobuf #3", "\x08\x96\x01"},
+		// a negative message index rules out protobuf
+		{"avro", framed(12, "\x02\x7f\x01"), "avro #12", "\x02\x7f\x01"},
+		{"zero schema id is binary", framed(0, "abc"), "binary", framed(0, "abc")},
+		{"too short for framing", "\x00\x00\x01", "binary", "\x00\x00\x01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := detectPayload(tt.input)
+			if p.Label() != tt.label {
+				t.Errorf("label = %q, want %q", p.Label(), tt.label)
+			}
+			if p.Body != tt.body {
+				t.Errorf("body = %q, want %q", p.Body, tt.body)
+			}
+		})
+	}
+}
+
+func TestDetectPayloadCorruptGzip(t *testing.T) {
+	input := "\x1f\x8b\x08garbage"
+	p := detectPayload(input)
+	if p.Gzip || p.Kind != PayloadBinary {
+		t.Errorf("got %+v, want plain binary", p)
+	}
+}
+
+func TestCopyableValue(t *testing.T) {
+	raw := gzipString(t, `{"a":1}`)
+	if got := copyableValue(raw, detectPayload(raw), BinaryHex); got != `{"a":1}` {
+		t.Errorf("gzip json copied as %q", got)
+	}
+	avro := "\x00\x00\x00\x00\x01\x02"
+	if got := copyableValue(avro, detectPayload(avro), BinaryHex); got != "000000000102" {
+		t.Errorf("avro copied as %q", got)
+	}
+}
@@ -1,10 +1,12 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/digitalis-io/kconduit/pkg/kafka"
+	"github.com/digitalis-io/kconduit/pkg/schemaregistry"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -12,26 +14,64 @@ import (
 )
 
 type ProducerModel struct {
-	topic       string
-	topicInfo   *kafka.TopicInfo
-	client      *kafka.Client
-	keyInput    textinput.Model
-	valueInput  textarea.Model
-	focusIndex  int
-	err         error
-	successMsg  string
-	width       int
-	height      int
-	msgCount    int
-}
-
-func NewProducerModel(topic string, client *kafka.Client) ProducerModel {
+	topic      string
+	topicInfo  *kafka.TopicInfo
+	client     *kafka.Client
+	keyInput   textinput.Model
+	tsInput    textinput.Model
+	valueInput textarea.Model
+	focusIndex int
+	err        error
+	successMsg string
+	width      int
+	height     int
+	msgCount   int
+	headers    []headerInput
+	// keyFromValue makes the key input a JSON path into the value
+	keyFromValue bool
+	// Schema Registry support, Avro encoding is used when the topic has a value schema
+	registry    *schemaregistry.Client
+	valueSchema *schemaregistry.Schema
+	schemaErr   error
+	avroEnabled bool
+	// Load generator, shown instead of the composer when set
+	generator *LoadGeneratorModel
+	// Producer settings panel, also shown instead of the composer
+	settingsPanel *ProducerSettingsModel
+}
+
+// headerInput is one editable header row in the producer form
+type headerInput struct {
+	key   textinput.Model
+	value textinput.Model
+}
+
+func newHeaderInput(width int) headerInput {
+	k := textinput.New()
+	k.Placeholder = "Header name"
+	k.CharLimit = 256
+	k.Width = width / 3
+
+	v := textinput.New()
+	v.Placeholder = "Header value"
+	v.CharLimit = 4096
+	v.Width = width - width/3 - 4
+
+	return headerInput{key: k, value: v}
+}
+
+func NewProducerModel(topic string, client *kafka.Client, registry *schemaregistry.Client) ProducerModel {
 	ki := textinput.New()
 	ki.Placeholder = "Message key (optional, press Enter to skip)"
 	ki.Focus()
 	ki.CharLimit = 256
 	ki.Width = 50
 
+	tsi := textinput.New()
+	tsi.Placeholder = "Timestamp (optional): Unix ms, 2006-01-02 15:04:05, RFC3339 or -1h"
+	tsi.CharLimit = 64
+	tsi.Width = 50
+
 	vi := textarea.New()
 	vi.Placeholder = "Enter your message here...\n\nPress Ctrl+S to send, Esc to go back"
 	vi.CharLimit = 10000
@@ -56,64 +96,278 @@ func NewProducerModel(topic string, client *kafka.Client) ProducerModel {
 		topicInfo:  topicInfo,
 		client:     client,
 		keyInput:   ki,
+		tsInput:    tsi,
 		valueInput: vi,
 		focusIndex: 0,
 		msgCount:   0,
+		registry:   registry,
+	}
+}
+
+type valueSchemaMsg struct {
+	schema *schemaregistry.Schema
+	err    error
+}
+
+func fetchValueSchema(registry *schemaregistry.Client, topic string) tea.Cmd {
+	return func() tea.Msg {
+		schema, err := registry.LatestSchema(schemaregistry.ValueSubject(topic))
+		return valueSchemaMsg{schema: schema, err: err}
 	}
 }
 
 type messageSentMsg struct {
-	err error
+	partition int32
+	offset    int64
+	err       error
 }
 
-func sendMessage(client *kafka.Client, topic, key, value string) tea.Cmd {
+func sendMessage(client *kafka.Client, topic, key, value string, opts kafka.ProduceOptions) tea.Cmd {
 	return func() tea.Msg {
-		err := client.ProduceMessage(topic, key, value)
-		return messageSentMsg{err: err}
+		partition, offset, err := client.ProduceMessage(topic, key, value, opts)
+		return messageSentMsg{partition: partition, offset: offset, err: err}
+	}
+}
+
+// Focus order is key, timestamp, then each header's name and value, then the message value
+const (
+	producerKeyField = iota
+	producerTimestampField
+	producerHeaderStart
+)
+
+func (m ProducerModel) valueFocusIndex() int {
+	return producerHeaderStart + len(m.headers)*2
+}
+
+// focusedHeader returns the header row that has focus and whether its value,
+// rather than its name, is focused
+func (m ProducerModel) focusedHeader() (row int, value bool, ok bool) {
+	if m.focusIndex < producerHeaderStart || m.focusIndex >= m.valueFocusIndex() {
+		return 0, false, false
 	}
+	offset := m.focusIndex - producerHeaderStart
+	return offset / 2, offset%2 == 1, true
+}
+
+// setFocus moves focus to index, wrapping around the form
+func (m *ProducerModel) setFocus(index int) tea.Cmd {
+	count := m.valueFocusIndex() + 1
+	m.focusIndex = (index%count + count) % count
+
+	m.keyInput.Blur()
+	m.tsInput.Blur()
+	m.valueInput.Blur()
+	for i := range m.headers {
+		m.headers[i].key.Blur()
+		m.headers[i].value.Blur()
+	}
+
+	switch {
+	case m.focusIndex == producerKeyField:
+		return m.keyInput.Focus()
+	case m.focusIndex == producerTimestampField:
+		return m.tsInput.Focus()
+	case m.focusIndex == m.valueFocusIndex():
+		return m.valueInput.Focus()
+	default:
+		row, value, _ := m.focusedHeader()
+		if value {
+			return m.headers[row].value.Focus()
+		}
+		return m.headers[row].key.Focus()
+	}
+}
+
+// headerMap collects the non-empty header rows
+func (m ProducerModel) headerMap() (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, h := range m.headers {
+		name := strings.TrimSpace(h.key.Value())
+		if name == "" {
+			if h.value.Value() != "" {
+				return nil, fmt.Errorf("header value %q has no name", h.value.Value())
+			}
+			continue
+		}
+		if _, exists := headers[name]; exists {
+			return nil, fmt.Errorf("duplicate header %q", name)
+		}
+		headers[name] = h.value.Value()
+	}
+	return headers, nil
 }
 
 func (m ProducerModel) Init() tea.Cmd {
+	if m.registry != nil {
+		return tea.Batch(textinput.Blink, fetchValueSchema(m.registry, m.topic))
+	}
 	return textinput.Blink
 }
 
 func (m ProducerModel) Update(msg tea.Msg) (ProducerModel, tea.Cmd) {
 	var cmds []tea.Cmd
 
+	if m.generator != nil {
+		if key, ok := msg.(tea.KeyMsg); ok && key.Type == tea.KeyEsc && !m.generator.Running() {
+			m.generator = nil
+			return m, m.setFocus(m.focusIndex)
+		}
+		generator, cmd := m.generator.Update(msg)
+		m.generator = &generator
+		return m, cmd
+	}
+
+	if m.settingsPanel != nil {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			if msg.Type == tea.KeyEsc && !m.settingsPanel.applying {
+				m.settingsPanel = nil
+				return m, m.setFocus(m.focusIndex)
+			}
+		case producerSettingsAppliedMsg:
+			if msg.err == nil {
+				m.settingsPanel = nil
+				m.err = nil
+				m.successMsg = "✓ Producing with " + msg.settings.String()
+				return m, m.setFocus(m.focusIndex)
+			}
+		}
+		panel, cmd := m.settingsPanel.Update(msg)
+		m.settingsPanel = &panel
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.Type {
 		case tea.KeyEsc:
 			return m, ReturnToListView
 
-		case tea.KeyTab, tea.KeyEnter:
-			if m.focusIndex == 0 {
-				m.focusIndex = 1
-				m.keyInput.Blur()
-				cmds = append(cmds, m.valueInput.Focus())
+		case tea.KeyTab:
+			return m, m.setFocus(m.focusIndex + 1)
+
+		case tea.KeyShiftTab:
+			return m, m.setFocus(m.focusIndex - 1)
+
+		case tea.KeyEnter:
+			// Enter inserts a newline in the value, elsewhere it moves on
+			if m.focusIndex != m.valueFocusIndex() {
+				return m, m.setFocus(m.focusIndex + 1)
+			}
+
+		case tea.KeyCtrlA:
+			// Add a header row and focus its name
+			m.headers = append(m.headers, newHeaderInput(m.keyInput.Width))
+			return m, m.setFocus(m.valueFocusIndex() - 2)
+
+		case tea.KeyCtrlD:
+			// Remove the header row that has focus
+			if row, _, ok := m.focusedHeader(); ok {
+				m.headers = append(m.headers[:row], m.headers[row+1:]...)
+				return m, m.setFocus(producerHeaderStart + row*2 - 1)
+			}
+
+		case tea.KeyCtrlG:
+			// Switch to the load generator, reusing the headers from the form
+			headers, err := m.headerMap()
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
+			generator := NewLoadGeneratorModel(m.topic, m.client, headers)
+			m.generator = &generator
+			return m, textinput.Blink
+
+		case tea.KeyCtrlR:
+			// Change acks, idempotence, compression and linger
+			panel := NewProducerSettingsModel(m.client)
+			m.settingsPanel = &panel
+			return m, nil
+
+		case tea.KeyCtrlO:
+			// Switch the key input between a literal key and a path into the value
+			m.keyFromValue = !m.keyFromValue
+			m.keyInput.SetValue("")
+			if m.keyFromValue {
+				m.keyInput.Placeholder = "JSON path of the key in the value, e.g. $.order.id"
+			} else {
+				m.keyInput.Placeholder = "Message key (optional, press Enter to skip)"
+			}
+			return m, m.setFocus(producerKeyField)
+
+		case tea.KeyCtrlE:
+			// Toggle Avro serialization when a value schema is registered
+			if m.valueSchema != nil && m.valueSchema.IsAvro() {
+				m.avroEnabled = !m.avroEnabled
 			}
 
 		case tea.KeyCtrlS:
 			if m.valueInput.Value() != "" {
+				headers, err := m.headerMap()
+				if err != nil {
+					m.err = err
+					m.successMsg = ""
+					return m, nil
+				}
+				timestamp, err := kafka.ParseTimestamp(m.tsInput.Value())
+				if err != nil {
+					m.err = err
+					m.successMsg = ""
+					return m, nil
+				}
 				key := m.keyInput.Value()
 				value := m.valueInput.Value()
-				return m, sendMessage(m.client, m.topic, key, value)
+				if m.keyFromValue && strings.TrimSpace(key) != "" {
+					path, err := kafka.ParseKeyPath(key)
+					if err == nil {
+						key, err = path.Extract(value)
+					}
+					if err != nil {
+						m.err = err
+						m.successMsg = ""
+						return m, nil
+					}
+				}
+				if m.avroEnabled {
+					encoded, err := m.registry.EncodeAvro(m.valueSchema, []byte(value))
+					if err != nil {
+						m.err = err
+						m.successMsg = ""
+						return m, nil
+					}
+					value = string(encoded)
+				}
+				return m, sendMessage(m.client, m.topic, key, value, kafka.ProduceOptions{Headers: headers, Timestamp: timestamp})
+			}
+		}
+
+	case valueSchemaMsg:
+		if msg.err != nil {
+			if !errors.Is(msg.err, schemaregistry.ErrSubjectNotFound) {
+				m.schemaErr = msg.err
 			}
+		} else {
+			m.valueSchema = msg.schema
+			m.avroEnabled = msg.schema.IsAvro()
 		}
 
 	case messageSentMsg:
 		if msg.err != nil {
 			m.err = msg.err
 			m.successMsg = ""
+			cmds = append(cmds, reportError("produce", msg.err))
 		} else {
 			m.err = nil
 			m.msgCount++
-			m.successMsg = fmt.Sprintf("✓ Message sent successfully! (Total sent: %d)", m.msgCount)
-			m.keyInput.SetValue("")
+			m.successMsg = fmt.Sprintf("✓ Message sent to partition %d at offset %d! (Total sent: %d)", msg.partition, msg.offset, m.msgCount)
+			// Headers and timestamp are kept, they usually stay the same across
+			// messages, and so is a key path
+			if !m.keyFromValue {
+				m.keyInput.SetValue("")
+			}
 			m.valueInput.SetValue("")
-			m.focusIndex = 0
-			m.keyInput.Focus()
-			m.valueInput.Blur()
+			cmds = append(cmds, m.setFocus(0))
 		}
 
 	case tea.WindowSizeMsg:
@@ -125,7 +379,12 @@ func (m ProducerModel) Update(msg tea.Msg) (ProducerModel, tea.Cmd) {
 			inputWidth = 100 // Cap max width for readability
 		}
 		m.keyInput.Width = inputWidth
+		m.tsInput.Width = inputWidth
 		m.valueInput.SetWidth(inputWidth)
+		for i := range m.headers {
+			m.headers[i].key.Width = inputWidth / 3
+			m.headers[i].value.Width = inputWidth - inputWidth/3 - 4
+		}
 		// Adjust height accounting for the new table and headers
 		valueHeight := msg.Height - 25
 		if valueHeight < 5 {
@@ -136,15 +395,24 @@ func (m ProducerModel) Update(msg tea.Msg) (ProducerModel, tea.Cmd) {
 		m.valueInput.SetHeight(valueHeight)
 	}
 
-	if m.focusIndex == 0 {
-		var cmd tea.Cmd
+	var cmd tea.Cmd
+	switch {
+	case m.focusIndex == producerKeyField:
 		m.keyInput, cmd = m.keyInput.Update(msg)
-		cmds = append(cmds, cmd)
-	} else {
-		var cmd tea.Cmd
+	case m.focusIndex == producerTimestampField:
+		m.tsInput, cmd = m.tsInput.Update(msg)
+	case m.focusIndex == m.valueFocusIndex():
 		m.valueInput, cmd = m.valueInput.Update(msg)
-		cmds = append(cmds, cmd)
+	default:
+		row, value, _ := m.focusedHeader()
+		h := &m.headers[row]
+		if value {
+			h.value, cmd = h.value.Update(msg)
+		} else {
+			h.key, cmd = h.key.Update(msg)
+		}
 	}
+	cmds = append(cmds, cmd)
 
 	return m, tea.Batch(cmds...)
 }
@@ -190,6 +458,29 @@ func (m ProducerModel) View() string {
 		tableContent.WriteString(valueStyle.Render(fmt.Sprintf("%d", m.topicInfo.ReplicationFactor)) + "\n")
 	}
 	
+	tableContent.WriteString(labelStyle.Render("Producer:         "))
+	tableContent.WriteString(valueStyle.Render(m.client.ProducerSettings().String()) + "\n")
+
+	if m.registry != nil {
+		tableContent.WriteString(labelStyle.Render("Value Schema:     "))
+		switch {
+		case m.schemaErr != nil:
+			tableContent.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("❌ Registry error") + "\n")
+		case m.valueSchema == nil:
+			tableContent.WriteString(valueStyle.Render("None (raw)") + "\n")
+		default:
+			schemaType := m.valueSchema.SchemaType
+			if schemaType == "" {
+				schemaType = "AVRO"
+			}
+			encoding := "raw"
+			if m.avroEnabled {
+				encoding = "Avro encoded"
+			}
+			tableContent.WriteString(valueStyle.Render(fmt.Sprintf("%s id %d v%d (%s)", schemaType, m.valueSchema.ID, m.valueSchema.Version, encoding)) + "\n")
+		}
+	}
+
 	tableContent.WriteString(labelStyle.Render("Messages Sent:    "))
 	tableContent.WriteString(valueStyle.Render(fmt.Sprintf("%d", m.msgCount)) + "\n")
 	
@@ -210,13 +501,40 @@ func (m ProducerModel) View() string {
 		Bold(true).
 		Foreground(lipgloss.Color("86"))
 
+	if m.generator != nil {
+		sb.WriteString(m.generator.View())
+		return sb.String()
+	}
+	if m.settingsPanel != nil {
+		sb.WriteString(m.settingsPanel.View())
+		return sb.String()
+	}
+
 	sb.WriteString(inputHeaderStyle.Render("📨 Message Composer"))
 	sb.WriteString("\n\n")
 
-	sb.WriteString(labelStyle.Render("Key:") + "\n")
+	if m.keyFromValue {
+		sb.WriteString(labelStyle.Render("Key (path in the JSON value):") + "\n")
+	} else {
+		sb.WriteString(labelStyle.Render("Key:") + "\n")
+	}
 	sb.WriteString(m.keyInput.View())
 	sb.WriteString("\n\n")
 
+	sb.WriteString(labelStyle.Render("Timestamp:") + "\n")
+	sb.WriteString(m.tsInput.View())
+	sb.WriteString("\n\n")
+
+	sb.WriteString(labelStyle.Render(fmt.Sprintf("Headers (%d):", len(m.headers))) + "\n")
+	if len(m.headers) == 0 {
+		sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("No headers, press Ctrl+A to add one"))
+		sb.WriteString("\n")
+	}
+	for _, h := range m.headers {
+		sb.WriteString(h.key.View() + " = " + h.value.View() + "\n")
+	}
+	sb.WriteString("\n")
+
 	sb.WriteString(labelStyle.Render("Value:") + "\n")
 	sb.WriteString(m.valueInput.View())
 	sb.WriteString("\n\n")
@@ -243,7 +561,11 @@ func (m ProducerModel) View() string {
 	helpStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("241")).
 		Italic(true)
-	sb.WriteString(helpStyle.Render("Tab: Switch fields • Ctrl+S: Send message • Esc: Back to topics"))
+	help := "Tab/Shift+Tab: Switch fields • Ctrl+A: Add header • Ctrl+D: Remove header • Ctrl+O: Key from value • Ctrl+S: Send message • Ctrl+G: Load generator • Ctrl+R: Producer settings • Esc: Back to topics"
+	if m.valueSchema != nil && m.valueSchema.IsAvro() {
+		help = "Ctrl+E: Toggle Avro • " + help
+	}
+	sb.WriteString(helpStyle.Render(help))
 
 	return sb.String()
 }
@@ -0,0 +1,173 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+const (
+	settingsAcksField = iota
+	settingsIdempotenceField
+	settingsCompressionField
+	settingsLingerField
+	settingsFieldCount
+)
+
+var (
+	acksOptions   = []string{kafka.AcksAll, kafka.AcksLeader, kafka.AcksNone}
+	lingerOptions = []time.Duration{0, 5 * time.Millisecond, 10 * time.Millisecond, 50 * time.Millisecond,
+		100 * time.Millisecond, 500 * time.Millisecond, time.Second}
+)
+
+// ProducerSettingsModel is the producer's settings panel: it changes the
+// acks, idempotence, compression and linger messages are sent with
+type ProducerSettingsModel struct {
+	client     *kafka.Client
+	settings   kafka.ProducerSettings
+	focusIndex int
+	applying   bool
+	err        error
+}
+
+type producerSettingsAppliedMsg struct {
+	settings kafka.ProducerSettings
+	err      error
+}
+
+func NewProducerSettingsModel(client *kafka.Client) ProducerSettingsModel {
+	return ProducerSettingsModel{client: client, settings: client.ProducerSettings()}
+}
+
+func applyProducerSettings(client *kafka.Client, settings kafka.ProducerSettings) tea.Cmd {
+	return func() tea.Msg {
+		err := client.SetProducerSettings(settings)
+		return producerSettingsAppliedMsg{settings: settings, err: err}
+	}
+}
+
+// step moves the option of the focused setting by delta, wrapping around.
+// Idempotence needs acks=all, so each is adjusted when the other changes.
+func (m *ProducerSettingsModel) step(delta int) {
+	s := &m.settings
+	switch m.focusIndex {
+	case settingsAcksField:
+		s.Acks = cycleOption(acksOptions, s.Acks, delta)
+		if s.Acks != kafka.AcksAll {
+			s.Idempotence = false
+		}
+	case settingsIdempotenceField:
+		s.Idempotence = !s.Idempotence
+		if s.Idempotence {
+			s.Acks = kafka.AcksAll
+		}
+	case settingsCompressionField:
+		s.Compression = cycleOption(kafka.CompressionCodecs, s.Compression, delta)
+	case settingsLingerField:
+		s.Linger = cycleOption(lingerOptions, s.Linger, delta)
+	}
+}
+
+// cycleOption returns the option delta places from current; a current value
+// that is not an option moves to the first one
+func cycleOption[T comparable](options []T, current T, delta int) T {
+	i := slices.Index(options, current)
+	if i < 0 {
+		return options[0]
+	}
+	n := len(options)
+	return options[((i+delta)%n+n)%n]
+}
+
+func (m ProducerSettingsModel) Update(msg tea.Msg) (ProducerSettingsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.applying {
+			return m, nil
+		}
+		switch msg.String() {
+		case "up", "shift+tab":
+			m.focusIndex = (m.focusIndex + settingsFieldCount - 1) % settingsFieldCount
+		case "down", "tab":
+			m.focusIndex = (m.focusIndex + 1) % settingsFieldCount
+		case "left":
+			m.step(-1)
+		case "right", " ":
+			m.step(1)
+		case "enter":
+			m.applying = true
+			m.err = nil
+			return m, applyProducerSettings(m.client, m.settings)
+		}
+
+	case producerSettingsAppliedMsg:
+		m.applying = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, reportError("producer settings", msg.err)
+		}
+	}
+	return m, nil
+}
+
+func (m ProducerSettingsModel) View() string {
+	var sb strings.Builder
+
+	labelStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("86"))
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("229"))
+
+	focusedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("205"))
+
+	sb.WriteString(labelStyle.Render("⚙️ Producer Settings") + "\n\n")
+
+	idempotence := "off"
+	if m.settings.Idempotence {
+		idempotence = "on"
+	}
+	rows := []struct{ label, value string }{
+		settingsAcksField:        {"Acks:        ", m.settings.Acks},
+		settingsIdempotenceField: {"Idempotence: ", idempotence},
+		settingsCompressionField: {"Compression: ", m.settings.Compression},
+		settingsLingerField:      {"Linger:      ", m.settings.Linger.String()},
+	}
+	for i, row := range rows {
+		if i == m.focusIndex {
+			sb.WriteString(focusedStyle.Render("> "+row.label) + focusedStyle.Render("◀ "+row.value+" ▶") + "\n")
+		} else {
+			sb.WriteString("  " + labelStyle.Render(row.label) + valueStyle.Render(row.value) + "\n")
+		}
+	}
+	sb.WriteString("\n")
+
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	sb.WriteString(dimStyle.Render("Applies to every message produced in this session, including the load generator and AI test data.") + "\n")
+	if m.settings.Linger > 0 {
+		sb.WriteString(dimStyle.Render(fmt.Sprintf("Each message waits up to %s before it is sent.", m.settings.Linger)) + "\n")
+	}
+	sb.WriteString("\n")
+
+	if m.applying {
+		sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("220")).Render("🔄 Reconnecting the producer...") + "\n\n")
+	}
+	if m.err != nil {
+		sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true).Render(fmt.Sprintf("❌ Error: %v", m.err)) + "\n\n")
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Italic(true)
+	sb.WriteString(helpStyle.Render("↑/↓: Select • ←/→: Change • Enter: Apply • Esc: Back to composer"))
+
+	return sb.String()
+}
@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"sort"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// rateWindow is the sliding window over which consumer throughput is averaged
+const rateWindow = 5 * time.Second
+
+type rateTickMsg time.Time
+
+// rateTick refreshes the throughput display so rates decay when traffic stops
+func rateTick() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return rateTickMsg(t)
+	})
+}
+
+// rateCounts accumulates messages and bytes
+type rateCounts struct {
+	msgs  int64
+	bytes int64
+}
+
+// rateBucket holds the counts for one second
+type rateBucket struct {
+	second      int64
+	total       rateCounts
+	byPartition map[int32]rateCounts
+}
+
+// partitionRate is the throughput of a single partition
+type partitionRate struct {
+	partition   int32
+	msgsPerSec  float64
+	bytesPerSec float64
+}
+
+// rateTracker computes messages/sec and bytes/sec over a sliding window using
+// one bucket per second, so memory stays constant regardless of throughput
+type rateTracker struct {
+	buckets []rateBucket
+}
+
+// add records a message of the given size received at t
+func (r *rateTracker) add(partition int32, bytes int64, t time.Time) {
+	second := t.Unix()
+	if n := len(r.buckets); n == 0 || r.buckets[n-1].second != second {
+		r.buckets = append(r.buckets, rateBucket{second: second, byPartition: make(map[int32]rateCounts)})
+		r.prune(t)
+	}
+	b := &r.buckets[len(r.buckets)-1]
+	b.total.msgs++
+	b.total.bytes += bytes
+	c := b.byPartition[partition]
+	c.msgs++
+	c.bytes += bytes
+	b.byPartition[partition] = c
+}
+
+// prune drops buckets that fell out of the window
+func (r *rateTracker) prune(now time.Time) {
+	oldest := now.Add(-rateWindow).Unix()
+	i := 0
+	for i < len(r.buckets) && r.buckets[i].second <= oldest {
+		i++
+	}
+	r.buckets = r.buckets[i:]
+}
+
+// rates returns overall and per-partition throughput averaged over the window
+// ending at now. Partitions are sorted by id.
+func (r *rateTracker) rates(now time.Time) (msgsPerSec, bytesPerSec float64, partitions []partitionRate) {
+	r.prune(now)
+
+	secs := rateWindow.Seconds()
+	var total rateCounts
+	byPartition := make(map[int32]rateCounts)
+	for _, b := range r.buckets {
+		total.msgs += b.total.msgs
+		total.bytes += b.total.bytes
+		for p, c := range b.byPartition {
+			acc := byPartition[p]
+			acc.msgs += c.msgs
+			acc.bytes += c.bytes
+			byPartition[p] = acc
+		}
+	}
+
+	for p, c := range byPartition {
+		partitions = append(partitions, partitionRate{
+			partition:   p,
+			msgsPerSec:  float64(c.msgs) / secs,
+			bytesPerSec: float64(c.bytes) / secs,
+		})
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].partition < partitions[j].partition })
+
+	return float64(total.msgs) / secs, float64(total.bytes) / secs, partitions
+}
+
+// reset clears all recorded traffic
+func (r *rateTracker) reset() {
+	r.buckets = nil
+}
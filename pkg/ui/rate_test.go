@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRateTracker(t *testing.T) {
+	start := time.Unix(1000, 0)
+	var r rateTracker
+	r.add(0, 100, start)
+	r.add(1, 50, start.Add(300*time.Millisecond))
+	r.add(0, 200, start.Add(2*time.Second))
+
+	msgs, bytes, partitions := r.rates(start.Add(2 * time.Second))
+	if msgs != 0.6 || bytes != 70 {
+		t.Errorf("rates = %v msg/s, %v B/s, want 0.6 and 70", msgs, bytes)
+	}
+	want := []partitionRate{{partition: 0, msgsPerSec: 0.4, bytesPerSec: 60}, {partition: 1, msgsPerSec: 0.2, bytesPerSec: 10}}
+	if !reflect.DeepEqual(partitions, want) {
+		t.Errorf("partitions = %+v, want %+v", partitions, want)
+	}
+
+	// Five seconds on, the first second has left the window
+	msgs, bytes, partitions = r.rates(start.Add(5 * time.Second))
+	if msgs != 0.2 || bytes != 40 {
+		t.Errorf("rates = %v msg/s, %v B/s, want 0.2 and 40", msgs, bytes)
+	}
+	if len(partitions) != 1 || partitions[0].partition != 0 {
+		t.Errorf("partitions = %+v, want only partition 0", partitions)
+	}
+
+	// Rates decay to nothing once traffic stops
+	msgs, bytes, partitions = r.rates(start.Add(7 * time.Second))
+	if msgs != 0 || bytes != 0 || partitions != nil {
+		t.Errorf("rates after traffic stopped = %v, %v, %+v", msgs, bytes, partitions)
+	}
+	if len(r.buckets) != 0 {
+		t.Errorf("%d buckets kept after they left the window", len(r.buckets))
+	}
+}
+
+func TestRateTrackerKeepsOneBucketPerSecond(t *testing.T) {
+	start := time.Unix(1000, 0)
+	var r rateTracker
+	for i := 0; i < 100; i++ {
+		r.add(int32(i%3), 10, start.Add(time.Duration(i)*100*time.Millisecond))
+	}
+	if len(r.buckets) > int(rateWindow.Seconds())+1 {
+		t.Errorf("%d buckets for a %v window", len(r.buckets), rateWindow)
+	}
+
+	r.reset()
+	if msgs, _, _ := r.rates(start.Add(10 * time.Second)); msgs != 0 {
+		t.Errorf("rate after reset = %v", msgs)
+	}
+}
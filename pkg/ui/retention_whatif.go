@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+// retentionWhatIf is the projection shown before a retention change is
+// applied, so its effect on disk use and existing data can be checked
+type retentionWhatIf struct {
+	current    kafka.RetentionSettings
+	proposed   kafka.RetentionSettings
+	projection *kafka.RetentionProjection
+	err        error
+}
+
+type retentionWhatIfMsg struct {
+	projection *kafka.RetentionProjection
+	err        error
+}
+
+// proposedRetention returns the topic's retention settings with key set to
+// value, or false when key does not affect retention or value is not a number
+func proposedRetention(current kafka.RetentionSettings, key, value string) (kafka.RetentionSettings, bool) {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return current, false
+	}
+	proposed := current
+	switch key {
+	case "retention.ms":
+		proposed.RetentionMs = n
+	case "retention.bytes":
+		proposed.RetentionBytes = n
+	case "segment.bytes":
+		proposed.SegmentBytes = n
+	default:
+		return current, false
+	}
+	return proposed, true
+}
+
+func fetchRetentionWhatIf(client *kafka.Client, topic string, current, proposed kafka.RetentionSettings) tea.Cmd {
+	return func() tea.Msg {
+		footprint, err := client.GetTopicFootprint(topic, proposed.RetentionMs)
+		if err != nil {
+			return retentionWhatIfMsg{err: err}
+		}
+		projection := kafka.ProjectRetention(footprint, current, proposed)
+		return retentionWhatIfMsg{projection: &projection}
+	}
+}
+
+// formatRetentionMs renders a retention.ms value as a duration
+func formatRetentionMs(ms int64) string {
+	if ms < 0 {
+		return "unlimited"
+	}
+	d := time.Duration(ms) * time.Millisecond
+	if d >= 24*time.Hour && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return d.String()
+}
+
+// formatRetentionBytes renders a byte limit, -1 being unlimited
+func formatRetentionBytes(b int64) string {
+	if b < 0 {
+		return "unlimited"
+	}
+	return formatBytes(b)
+}
+
+// formatSteadyState renders a projected disk use, -1 growing without bound
+func formatSteadyState(b, dailyGrowth int64) string {
+	if b < 0 {
+		return fmt.Sprintf("unbounded, growing %s/day", formatBytes(dailyGrowth))
+	}
+	return formatBytes(b)
+}
+
+func (w *retentionWhatIf) View(topic string) string {
+	var sb strings.Builder
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("86"))
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	warnStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("208"))
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Italic(true)
+
+	sb.WriteString(titleStyle.Render("📐 Retention what-if for "+topic) + "\n\n")
+
+	switch {
+	case w.err != nil:
+		sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(fmt.Sprintf("Could not measure the topic: %v", w.err)) + "\n\n")
+		sb.WriteString(helpStyle.Render("Enter: Apply anyway • Esc: Cancel"))
+		return sb.String()
+	case w.projection == nil:
+		sb.WriteString("Measuring topic size and produce rate...\n\n")
+		sb.WriteString(helpStyle.Render("Esc: Cancel"))
+		return sb.String()
+	}
+
+	p := w.projection
+	row := func(label, current, proposed string) {
+		sb.WriteString(fmt.Sprintf("%s %-22s %s\n", labelStyle.Render(fmt.Sprintf("%-18s", label)), current, proposed))
+	}
+	sb.WriteString(fmt.Sprintf("Size now:      %s (%s on disk with replicas)\n", formatBytes(p.Size), formatBytes(p.DiskBytes)))
+	sb.WriteString(fmt.Sprintf("Produce rate:  %s/s over the last hour\n\n", formatBytes(int64(p.BytesPerSecond))))
+	row("", "Current", "Proposed")
+	row("retention.ms", formatRetentionMs(w.current.RetentionMs), formatRetentionMs(w.proposed.RetentionMs))
+	row("retention.bytes", formatRetentionBytes(w.current.RetentionBytes), formatRetentionBytes(w.proposed.RetentionBytes))
+	row("segment.bytes", formatBytes(w.current.SegmentBytes), formatBytes(w.proposed.SegmentBytes))
+	row("Projected disk use", formatSteadyState(p.Current, p.DailyGrowth), formatSteadyState(p.Projected, p.DailyGrowth))
+	sb.WriteString("\n")
+
+	if p.DeletedBytes > 0 {
+		sb.WriteString(warnStyle.Render(fmt.Sprintf("⚠ About %s (%d records) in %d partition(s) is past the new retention and will be deleted at the broker's next retention check, within minutes.",
+			formatBytes(p.DeletedBytes), p.DeletedRecords, p.AffectedPartitions)) + "\n\n")
+	}
+	sb.WriteString(labelStyle.Render("Estimates: the rate is taken from the last hour and Kafka only deletes whole segments.") + "\n\n")
+	sb.WriteString(helpStyle.Render("Enter: Apply • Esc: Cancel"))
+	return sb.String()
+}
@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+func TestProposedRetention(t *testing.T) {
+	current := kafka.RetentionSettings{RetentionMs: 604800000, RetentionBytes: -1, SegmentBytes: 1 << 30}
+
+	got, ok := proposedRetention(current, "retention.bytes", "1073741824")
+	want := kafka.RetentionSettings{RetentionMs: 604800000, RetentionBytes: 1 << 30, SegmentBytes: 1 << 30}
+	if !ok || got != want {
+		t.Errorf("retention.bytes: got %+v, %v, want %+v", got, ok, want)
+	}
+	if _, ok := proposedRetention(current, "cleanup.policy", "compact"); ok {
+		t.Error("cleanup.policy should not trigger a retention what-if")
+	}
+	if _, ok := proposedRetention(current, "retention.ms", "soon"); ok {
+		t.Error("a non-numeric value should not trigger a retention what-if")
+	}
+}
+
+func TestFormatRetentionMs(t *testing.T) {
+	tests := map[int64]string{
+		-1:        "unlimited",
+		604800000: "7d",
+		5400000:   "1h30m0s",
+	}
+	for ms, want := range tests {
+		if got := formatRetentionMs(ms); got != want {
+			t.Errorf("formatRetentionMs(%d) = %q, want %q", ms, got, want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+)
+
+// rowKey identifies a table row across refreshes
+type rowKey func(table.Row) string
+
+// firstColumn keys rows by their first cell, such as a topic or group name
+func firstColumn(row table.Row) string {
+	if len(row) == 0 {
+		return ""
+	}
+	return row[0]
+}
+
+// wholeRow keys rows by every cell, for tables without a unique column
+func wholeRow(row table.Row) string {
+	return strings.Join(row, "\x00")
+}
+
+// setRowsKeepingCursor replaces the rows of t and keeps the cursor on the
+// row that was selected before, wherever the refresh moved it
+func setRowsKeepingCursor(t *table.Model, rows []table.Row, key rowKey) {
+	selected := t.SelectedRow()
+	t.SetRows(rows)
+	keepCursorOn(t, selected, key)
+}
+
+// keepCursorOn moves the cursor to the row matching selected. When that row
+// is gone the cursor stays at its index, or the last row if the table shrank.
+// The cursor is moved rather than set, so the table scrolls as little as it
+// does for a key press instead of jumping.
+func keepCursorOn(t *table.Model, selected table.Row, key rowKey) {
+	rows := t.Rows()
+	if len(rows) == 0 {
+		return
+	}
+
+	target := min(t.Cursor(), len(rows)-1)
+	if selected != nil {
+		if i := rowIndex(rows, key, key(selected)); i >= 0 {
+			target = i
+		}
+	}
+
+	moveCursor(t, target)
+}
+
+// moveCursor moves the cursor to row target by stepping, like a key press
+func moveCursor(t *table.Model, target int) {
+	switch cursor := t.Cursor(); {
+	case target > cursor:
+		t.MoveDown(target - cursor)
+	case target < cursor:
+		t.MoveUp(cursor - target)
+	}
+}
+
+// rowIndex returns the index of the row whose key is want, or -1
+func rowIndex(rows []table.Row, key rowKey, want string) int {
+	for i, row := range rows {
+		if key(row) == want {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+)
+
+func TestSetRowsKeepingCursor(t *testing.T) {
+	rows := func(names ...string) []table.Row {
+		r := make([]table.Row, len(names))
+		for i, name := range names {
+			r[i] = table.Row{name, "1"}
+		}
+		return r
+	}
+
+	tests := []struct {
+		name   string
+		before []table.Row
+		cursor int
+		after  []table.Row
+		want   string
+	}{
+		{"row moved down", rows("b", "c", "d"), 1, rows("a", "b", "c", "d"), "c"},
+		{"row moved up", rows("a", "b", "c", "d"), 3, rows("b", "c", "d"), "d"},
+		{"row removed", rows("a", "b", "c"), 1, rows("a", "c"), "c"},
+		{"table shrank", rows("a", "b", "c"), 2, rows("x"), "x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tbl := table.New(table.WithColumns([]table.Column{{Title: "Name", Width: 5}, {Title: "N", Width: 2}}), table.WithHeight(5))
+			tbl.SetRows(tt.before)
+			tbl.SetCursor(tt.cursor)
+
+			setRowsKeepingCursor(&tbl, tt.after, firstColumn)
+			if got := firstColumn(tbl.SelectedRow()); got != tt.want {
+				t.Errorf("selected %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
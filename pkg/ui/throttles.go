@@ -0,0 +1,242 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+// ThrottlesModel shows and changes the replication throttles that keep
+// reassignments from saturating the network
+type ThrottlesModel struct {
+	client     *kafka.Client
+	throttles  *kafka.ReplicationThrottles
+	loading    bool
+	editing    bool // Entering a rate for every broker
+	rateInput  textinput.Model
+	confirming bool // Waiting for y/n before clearing
+	result     string
+	err        error
+	width      int
+	height     int
+}
+
+func NewThrottlesModel(client *kafka.Client) ThrottlesModel {
+	ti := textinput.New()
+	ti.Placeholder = "e.g. 50MB/s"
+	ti.CharLimit = 20
+	ti.Width = 20
+
+	return ThrottlesModel{client: client, loading: true, rateInput: ti}
+}
+
+type throttlesMsg struct {
+	throttles *kafka.ReplicationThrottles
+	err       error
+}
+
+type throttleChangedMsg struct {
+	result string
+	err    error
+}
+
+func fetchThrottles(client *kafka.Client) tea.Cmd {
+	return func() tea.Msg {
+		throttles, err := client.GetReplicationThrottles()
+		return throttlesMsg{throttles: throttles, err: err}
+	}
+}
+
+func setBrokerThrottle(client *kafka.Client, rate int64, brokers []int32) tea.Cmd {
+	return func() tea.Msg {
+		err := client.SetBrokerThrottle(rate, brokers)
+		return throttleChangedMsg{result: fmt.Sprintf("Throttled %d brokers to %s/s", len(brokers), formatBytes(rate)), err: err}
+	}
+}
+
+func clearThrottles(client *kafka.Client, throttles *kafka.ReplicationThrottles) tea.Cmd {
+	return func() tea.Msg {
+		err := client.ClearReplicationThrottles(throttles)
+		return throttleChangedMsg{result: "Cleared all replication throttles", err: err}
+	}
+}
+
+func (m ThrottlesModel) Init() tea.Cmd {
+	return fetchThrottles(m.client)
+}
+
+func (m ThrottlesModel) Update(msg tea.Msg) (ThrottlesModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case throttlesMsg:
+		m.loading = false
+		m.err = msg.err
+		if msg.err == nil {
+			m.throttles = msg.throttles
+		}
+		return m, nil
+
+	case throttleChangedMsg:
+		m.err = msg.err
+		m.result = ""
+		if msg.err == nil {
+			m.result = msg.result
+		}
+		m.loading = true
+		return m, fetchThrottles(m.client)
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.editing {
+			switch msg.String() {
+			case "esc":
+				m.editing = false
+				m.rateInput.Blur()
+				return m, nil
+			case "enter":
+				rate, err := kafka.ParseByteRate(m.rateInput.Value())
+				if err != nil {
+					m.err = err
+					return m, nil
+				}
+				if rate == 0 {
+					m.err = fmt.Errorf("enter a rate above zero, or press c to clear the throttles")
+					return m, nil
+				}
+				m.editing = false
+				m.rateInput.Blur()
+				m.result, m.err = "", nil
+				brokers := make([]int32, len(m.throttles.Brokers))
+				for i, b := range m.throttles.Brokers {
+					brokers[i] = b.BrokerID
+				}
+				return m, setBrokerThrottle(m.client, rate, brokers)
+			}
+			var cmd tea.Cmd
+			m.rateInput, cmd = m.rateInput.Update(msg)
+			return m, cmd
+		}
+
+		if m.confirming {
+			switch msg.String() {
+			case "y", "Y":
+				m.confirming = false
+				m.result, m.err = "", nil
+				return m, clearThrottles(m.client, m.throttles)
+			case "n", "N", "esc":
+				m.confirming = false
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "esc", "q":
+			return m, ReturnToListView
+		case "r":
+			m.loading = true
+			return m, fetchThrottles(m.client)
+		case "s":
+			if m.throttles != nil && len(m.throttles.Brokers) > 0 {
+				m.editing = true
+				m.rateInput.SetValue("")
+				return m, m.rateInput.Focus()
+			}
+		case "c":
+			if m.throttles != nil && m.throttles.Active() {
+				m.confirming = true
+			}
+		}
+	}
+	return m, nil
+}
+
+// formatRate renders a throttle rate, 0 meaning none
+func formatRate(rate int64) string {
+	if rate == 0 {
+		return "-"
+	}
+	return formatBytes(rate) + "/s"
+}
+
+func (m ThrottlesModel) View() string {
+	var s strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Padding(0, 1)
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Bold(true)
+	okStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("46"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	s.WriteString(titleStyle.Render("🚦 Replication Throttles"))
+	s.WriteString("\n\n")
+
+	if m.err != nil {
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(fmt.Sprintf("❌ %v", m.err)))
+		s.WriteString("\n\n")
+	}
+	if m.result != "" {
+		s.WriteString(okStyle.Render("✅ " + m.result))
+		s.WriteString("\n\n")
+	}
+
+	t := m.throttles
+	if t == nil {
+		if m.loading {
+			s.WriteString("Loading throttles...")
+		}
+		s.WriteString("\n\n" + dimStyle.Render("r: Refresh | Esc: Back"))
+		return s.String()
+	}
+
+	s.WriteString(headerStyle.Render("Broker rates"))
+	s.WriteString("\n")
+	s.WriteString(dimStyle.Render(fmt.Sprintf("  %6s  %14s  %14s", "Broker", "Leader", "Follower")))
+	s.WriteString("\n")
+	for _, b := range t.Brokers {
+		s.WriteString(fmt.Sprintf("  %6d  %14s  %14s\n", b.BrokerID, formatRate(b.Leader), formatRate(b.Follower)))
+	}
+	s.WriteString("\n")
+
+	s.WriteString(headerStyle.Render("Throttled replicas"))
+	s.WriteString("\n")
+	if len(t.Topics) == 0 {
+		s.WriteString(dimStyle.Render("  No topic has throttled replicas, so the broker rates limit nothing"))
+		s.WriteString("\n")
+	}
+	for _, topic := range t.Topics {
+		s.WriteString(fmt.Sprintf("  %s\n", topic.Topic))
+		if topic.Leaders != "" {
+			s.WriteString(dimStyle.Render("    leaders:   "+truncateString(topic.Leaders, 80)) + "\n")
+		}
+		if topic.Followers != "" {
+			s.WriteString(dimStyle.Render("    followers: "+truncateString(topic.Followers, 80)) + "\n")
+		}
+	}
+	s.WriteString("\n")
+
+	switch {
+	case m.editing:
+		s.WriteString("Rate for every broker: " + m.rateInput.View())
+		s.WriteString("\n\n" + dimStyle.Render("Enter: Apply | Esc: Cancel"))
+	case m.confirming:
+		s.WriteString(warnStyle.Render("Remove every broker rate and throttled replica list? (y/n)"))
+	default:
+		help := "s: Set rate | r: Refresh | Esc: Back"
+		if t.Active() {
+			help = "s: Set rate | c: Clear all | r: Refresh | Esc: Back"
+		}
+		s.WriteString(dimStyle.Render(help))
+	}
+	return s.String()
+}
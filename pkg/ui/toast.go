@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	// toastDuration is how long a success or info toast stays on screen
+	toastDuration = 4 * time.Second
+	// errorToastDuration gives errors longer, since they usually need reading
+	errorToastDuration = 8 * time.Second
+	// maxToasts is how many toasts are stacked before the oldest is dropped
+	maxToasts = 3
+)
+
+type toastKind int
+
+const (
+	toastInfo toastKind = iota
+	toastSuccess
+	toastError
+)
+
+// ToastMsg asks the main model to show a transient notification, whatever
+// view is active
+type ToastMsg struct {
+	Kind toastKind
+	Text string
+}
+
+type toastExpiredMsg struct {
+	id int
+}
+
+// showToast returns a command that raises a toast
+func showToast(kind toastKind, text string) tea.Cmd {
+	return func() tea.Msg {
+		return ToastMsg{Kind: kind, Text: text}
+	}
+}
+
+type toast struct {
+	id   int
+	kind toastKind
+	text string
+}
+
+// toasts is the stack of notifications on screen, newest last
+type toasts struct {
+	items  []toast
+	nextID int
+}
+
+// push adds a toast and returns the command that expires it
+func (t *toasts) push(msg ToastMsg) tea.Cmd {
+	t.nextID++
+	id := t.nextID
+	t.items = append(t.items, toast{id: id, kind: msg.Kind, text: msg.Text})
+	if len(t.items) > maxToasts {
+		t.items = t.items[len(t.items)-maxToasts:]
+	}
+
+	d := toastDuration
+	if msg.Kind == toastError {
+		d = errorToastDuration
+	}
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return toastExpiredMsg{id: id}
+	})
+}
+
+// expire removes the toast with the given id, if it is still shown
+func (t *toasts) expire(id int) {
+	for i, item := range t.items {
+		if item.id == id {
+			t.items = append(t.items[:i], t.items[i+1:]...)
+			return
+		}
+	}
+}
+
+// isConnectionError reports whether err means the cluster is unreachable,
+// as opposed to a request the cluster refused
+func isConnectionError(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, sarama.ErrOutOfBrokers) ||
+		errors.Is(err, sarama.ErrNotConnected) ||
+		errors.Is(err, sarama.ErrClosedClient) ||
+		errors.Is(err, io.EOF) ||
+		errors.As(err, &netErr)
+}
+
+// connectionChanged raises a toast when the background fetches lose the
+// cluster, and another once they reach it again
+func (m *Model) connectionChanged(err error) tea.Cmd {
+	switch {
+	case err != nil && isConnectionError(err) && !m.disconnected:
+		m.disconnected = true
+		return m.toasts.push(ToastMsg{Kind: toastError, Text: fmt.Sprintf("Connection lost: %v", err)})
+	case err == nil && m.disconnected:
+		m.disconnected = false
+		return m.toasts.push(ToastMsg{Kind: toastSuccess, Text: "Connection restored"})
+	}
+	return nil
+}
+
+// overlay draws the toasts over the top right corner of view, one per line
+func (t toasts) overlay(view string, width int) string {
+	if len(t.items) == 0 {
+		return view
+	}
+
+	lines := strings.Split(view, "\n")
+	for i, item := range t.items {
+		rendered := item.render(width)
+		if i+1 >= len(lines) {
+			lines = append(lines, "")
+		}
+		// Skip the first line, which holds the tab bar or title
+		line := lines[i+1]
+		left := width - lipgloss.Width(rendered) - 1
+		if left < 0 {
+			left = 0
+		}
+		line = ansi.Truncate(line, left, "")
+		if pad := left - lipgloss.Width(line); pad > 0 {
+			line += strings.Repeat(" ", pad)
+		}
+		lines[i+1] = line + " " + rendered
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (item toast) render(width int) string {
+	style := lipgloss.NewStyle().Bold(true).Padding(0, 1).Foreground(lipgloss.Color("230"))
+	icon := "ℹ️ "
+	switch item.kind {
+	case toastSuccess:
+		style = style.Background(lipgloss.Color("28"))
+		icon = "✅"
+	case toastError:
+		style = style.Background(lipgloss.Color("160"))
+		icon = "❌"
+	default:
+		style = style.Background(lipgloss.Color("57"))
+	}
+
+	text := icon + " " + item.text
+	if max := width/2 - 2; max > 10 {
+		text = truncateString(text, max)
+	}
+	return style.Render(text)
+}
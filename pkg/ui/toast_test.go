@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToasts(t *testing.T) {
+	var ts toasts
+	for _, text := range []string{"one", "two", "three", "four"} {
+		if cmd := ts.push(ToastMsg{Kind: toastSuccess, Text: text}); cmd == nil {
+			t.Fatalf("push(%q) returned no expiry command", text)
+		}
+	}
+	if len(ts.items) != maxToasts || ts.items[0].text != "two" {
+		t.Fatalf("items = %v, want the newest %d", ts.items, maxToasts)
+	}
+
+	ts.expire(1) // Already dropped
+	ts.expire(3)
+	var texts []string
+	for _, item := range ts.items {
+		texts = append(texts, item.text)
+	}
+	if got := strings.Join(texts, ","); got != "two,four" {
+		t.Errorf("after expiry = %s, want two,four", got)
+	}
+}
+
+func TestToastsOverlay(t *testing.T) {
+	ts := toasts{items: []toast{{id: 1, kind: toastInfo, text: "saved"}}}
+	view := "title\n" + strings.Repeat("x", 60) + "\nlast"
+	lines := strings.Split(ts.overlay(view, 40), "\n")
+
+	if len(lines) != 3 || lines[0] != "title" || lines[2] != "last" {
+		t.Fatalf("overlay changed the other lines: %q", lines)
+	}
+	if !strings.Contains(lines[1], "saved") || !strings.HasPrefix(lines[1], "xxxx") {
+		t.Errorf("line 1 = %q, want the toast after the truncated text", lines[1])
+	}
+
+	if got := (toasts{}).overlay(view, 40); got != view {
+		t.Errorf("overlay without toasts = %q, want the view unchanged", got)
+	}
+}
@@ -0,0 +1,221 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+// TopicDetailModel lists the partitions of one topic with their replication
+// health and offsets
+type TopicDetailModel struct {
+	client        *kafka.Client
+	topic         string
+	partitions    []kafka.PartitionDetail
+	loading       bool
+	unhealthyOnly bool // Hide partitions with every replica in sync
+	err           error
+	viewport      viewport.Model
+	width         int
+	height        int
+}
+
+func NewTopicDetailModel(client *kafka.Client, topic string, width, height int) TopicDetailModel {
+	m := TopicDetailModel{
+		client:   client,
+		topic:    topic,
+		loading:  true,
+		viewport: viewport.New(100, 20),
+	}
+	m.resize(width, height)
+	return m
+}
+
+type topicPartitionsMsg struct {
+	partitions []kafka.PartitionDetail
+	err        error
+}
+
+func fetchTopicPartitions(client *kafka.Client, topic string) tea.Cmd {
+	return func() tea.Msg {
+		partitions, err := client.GetTopicPartitions(topic)
+		return topicPartitionsMsg{partitions: partitions, err: err}
+	}
+}
+
+func (m TopicDetailModel) Init() tea.Cmd {
+	return fetchTopicPartitions(m.client, m.topic)
+}
+
+func (m TopicDetailModel) Update(msg tea.Msg) (TopicDetailModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case topicPartitionsMsg:
+		m.loading = false
+		m.partitions, m.err = msg.partitions, msg.err
+		m.refresh()
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.resize(msg.Width, msg.Height)
+		m.refresh()
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			return m, ReturnToListView
+		case "r":
+			m.loading = true
+			return m, fetchTopicPartitions(m.client, m.topic)
+		case "u":
+			m.unhealthyOnly = !m.unhealthyOnly
+			m.refresh()
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+func (m *TopicDetailModel) resize(width, height int) {
+	m.width, m.height = width, height
+	if width > 4 {
+		m.viewport.Width = width - 4
+	}
+	if height > 10 {
+		m.viewport.Height = height - 10
+	}
+}
+
+func (m *TopicDetailModel) refresh() {
+	m.viewport.SetContent(renderTopicPartitions(m.partitions, m.unhealthyOnly))
+}
+
+// formatBrokerIDs lists broker IDs, or a dash for none
+func formatBrokerIDs(ids []int32) string {
+	if len(ids) == 0 {
+		return "-"
+	}
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(int(id))
+	}
+	return strings.Join(parts, ",")
+}
+
+// renderTopicPartitions lists each partition, in red when it has no leader
+// and in orange when its ISR is smaller than its replica set
+func renderTopicPartitions(partitions []kafka.PartitionDetail, unhealthyOnly bool) string {
+	if len(partitions) == 0 {
+		return "No partitions reported."
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	var sb strings.Builder
+	sb.WriteString(headerStyle.Render(fmt.Sprintf("%9s  %6s  %-16s  %-16s  %-10s  %12s  %12s  %12s",
+		"Partition", "Leader", "Replicas", "ISR", "Offline", "Log start", "Log end", "Messages")))
+	sb.WriteString("\n")
+
+	shown := 0
+	for _, p := range partitions {
+		if unhealthyOnly && !p.UnderReplicated() && !p.LeaderOffline() {
+			continue
+		}
+		shown++
+
+		leader := strconv.Itoa(int(p.Leader))
+		if p.LeaderOffline() {
+			leader = "none"
+		}
+		start, end, messages := "-", "-", "-"
+		if p.LogStart >= 0 && p.LogEnd >= 0 {
+			start, end = strconv.FormatInt(p.LogStart, 10), strconv.FormatInt(p.LogEnd, 10)
+			messages = strconv.FormatInt(p.LogEnd-p.LogStart, 10)
+		}
+		line := fmt.Sprintf("%9d  %6s  %-16s  %-16s  %-10s  %12s  %12s  %12s", p.ID, leader,
+			truncateString(formatBrokerIDs(p.Replicas), 16), truncateString(formatBrokerIDs(p.ISR), 16),
+			truncateString(formatBrokerIDs(p.Offline), 10), start, end, messages)
+
+		switch {
+		case p.LeaderOffline():
+			sb.WriteString(errorStyle.Render(line + "  offline"))
+		case p.UnderReplicated():
+			sb.WriteString(warnStyle.Render(line + "  under-replicated"))
+		default:
+			sb.WriteString(line)
+		}
+		sb.WriteString("\n")
+	}
+	if shown == 0 {
+		sb.WriteString(dimStyle.Render("Every partition has a leader and all replicas in sync"))
+		sb.WriteString("\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (m TopicDetailModel) View() string {
+	var s strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Padding(0, 1)
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Bold(true)
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
+	okStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("46"))
+
+	s.WriteString(titleStyle.Render("📄 Topic " + m.topic))
+	s.WriteString("\n\n")
+
+	switch {
+	case m.loading && m.partitions == nil:
+		s.WriteString("Loading partitions...")
+	case m.err != nil:
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(fmt.Sprintf("Error: %v", m.err)))
+	default:
+		under, offline := 0, 0
+		var messages int64
+		for _, p := range m.partitions {
+			if p.LeaderOffline() {
+				offline++
+			} else if p.UnderReplicated() {
+				under++
+			}
+			if p.LogStart >= 0 && p.LogEnd >= 0 {
+				messages += p.LogEnd - p.LogStart
+			}
+		}
+		s.WriteString(fmt.Sprintf("%d partitions, %d messages  ", len(m.partitions), messages))
+		switch {
+		case offline > 0 || under > 0:
+			if offline > 0 {
+				s.WriteString(errorStyle.Render(fmt.Sprintf("%d offline ", offline)))
+			}
+			if under > 0 {
+				s.WriteString(warnStyle.Render(fmt.Sprintf("%d under-replicated", under)))
+			}
+		default:
+			s.WriteString(okStyle.Render("all replicas in sync"))
+		}
+		s.WriteString("\n\n")
+		s.WriteString(m.viewport.View())
+	}
+	s.WriteString("\n\n")
+
+	help := "↑/↓: Scroll | u: Unhealthy only | r: Refresh | Esc: Back"
+	if m.unhealthyOnly {
+		help = "↑/↓: Scroll | u: All partitions | r: Refresh | Esc: Back"
+	}
+	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(help))
+	return s.String()
+}
@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+func TestRenderTopicPartitions(t *testing.T) {
+	partitions := []kafka.PartitionDetail{
+		{PartitionInfo: kafka.PartitionInfo{ID: 0, Leader: 1, Replicas: []int32{1, 2, 3}, ISR: []int32{1, 2, 3}}, LogStart: 10, LogEnd: 110},
+		{PartitionInfo: kafka.PartitionInfo{ID: 1, Leader: 2, Replicas: []int32{2, 3, 1}, ISR: []int32{2}}, LogStart: 0, LogEnd: 5},
+		{PartitionInfo: kafka.PartitionInfo{ID: 2, Leader: -1, Replicas: []int32{3}}, Offline: []int32{3}, LogStart: -1, LogEnd: -1},
+	}
+
+	all := renderTopicPartitions(partitions, false)
+	for _, want := range []string{"1,2,3", "100", "under-replicated", "none", "offline"} {
+		if !strings.Contains(all, want) {
+			t.Errorf("rendered partitions missing %q:\n%s", want, all)
+		}
+	}
+
+	unhealthy := renderTopicPartitions(partitions, true)
+	if lines := strings.Count(unhealthy, "\n") + 1; lines != 3 {
+		t.Errorf("unhealthy only rendered %d lines, want a header and 2 partitions:\n%s", lines, unhealthy)
+	}
+
+	healthy := renderTopicPartitions(partitions[:1], true)
+	if !strings.Contains(healthy, "all replicas in sync") {
+		t.Errorf("healthy topic with unhealthy only = %q", healthy)
+	}
+}
@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// topicHealthCell badges a topic's offline (OFF), under-replicated (URP) and
+// under-min-ISR (<MIN) partitions with their counts, e.g. "URP:2 <MIN:1"
+func (m Model) topicHealthCell(topic string) string {
+	if m.topicHealth == nil {
+		return "…"
+	}
+	health, ok := m.topicHealth[topic]
+	if !ok {
+		return "-"
+	}
+	var badges []string
+	if health.Offline > 0 {
+		badges = append(badges, fmt.Sprintf("OFF:%d", health.Offline))
+	}
+	if health.UnderReplicated > 0 {
+		badges = append(badges, fmt.Sprintf("URP:%d", health.UnderReplicated))
+	}
+	// min.insync.replicas comes with the other configs, so this badge may
+	// appear a moment later
+	if minISR, err := strconv.Atoi(m.topicOverview[topic]["min.insync.replicas"]); err == nil {
+		if n := health.UnderMinISR(minISR); n > 0 {
+			badges = append(badges, fmt.Sprintf("<MIN:%d", n))
+		}
+	}
+	if len(badges) == 0 {
+		return "✓"
+	}
+	return strings.Join(badges, " ")
+}
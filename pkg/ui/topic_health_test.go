@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+func TestTopicHealthCell(t *testing.T) {
+	m := Model{}
+	if got := m.topicHealthCell("orders"); got != "…" {
+		t.Errorf("before health is known = %q", got)
+	}
+
+	m.topicHealth = map[string]kafka.TopicHealth{
+		"orders":   {Offline: 1, UnderReplicated: 2, ISR: []int{3, 1}},
+		"events":   {ISR: []int{3, 3}},
+		"payments": {UnderReplicated: 1, ISR: []int{3, 2}},
+	}
+	tests := map[string]string{"orders": "OFF:1 URP:2", "events": "✓", "payments": "URP:1", "gone": "-"}
+	for topic, want := range tests {
+		if got := m.topicHealthCell(topic); got != want {
+			t.Errorf("%s = %q, want %q", topic, got, want)
+		}
+	}
+
+	// Once min.insync.replicas is known, partitions below it are counted
+	m.topicOverview = map[string]map[string]string{"orders": {"min.insync.replicas": "2"}, "payments": {"min.insync.replicas": "2"}}
+	if got := m.topicHealthCell("orders"); got != "OFF:1 URP:2 <MIN:1" {
+		t.Errorf("orders = %q", got)
+	}
+	if got := m.topicHealthCell("payments"); got != "URP:1" {
+		t.Errorf("payments = %q", got)
+	}
+}
@@ -0,0 +1,187 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/digitalis-io/kconduit/pkg/topictags"
+)
+
+// starredFilter is the topic filter showing only starred topics; any other
+// non-empty filter is a tag
+const starredFilter = "★"
+
+// WithTopicTags lets topics be starred and tagged, keeping the marks in
+// store under cluster
+func (m Model) WithTopicTags(store *topictags.Store, cluster string) Model {
+	m.topicTags = store
+	m.topicTagsCluster = cluster
+	return m
+}
+
+// topicMarks returns the marks of a topic, none when tagging is off
+func (m Model) topicMarks(topic string) topictags.Marks {
+	if m.topicTags == nil {
+		return topictags.Marks{}
+	}
+	return m.topicTags.Get(m.topicTagsCluster, topic)
+}
+
+// topicShown reports whether a topic passes the active star or tag filter
+func (m Model) topicShown(topic string) bool {
+	switch m.topicTagFilter {
+	case "":
+		return true
+	case starredFilter:
+		return m.topicMarks(topic).Starred
+	default:
+		for _, tag := range m.topicMarks(topic).Tags {
+			if tag == m.topicTagFilter {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// topicTagsCell is the Tags column of a topic: a star and its tags
+func (m Model) topicTagsCell(topic string) string {
+	marks := m.topicMarks(topic)
+	cell := strings.Join(marks.Tags, ",")
+	if marks.Starred {
+		cell = strings.TrimSpace("★ " + cell)
+	}
+	return cell
+}
+
+// selectedTopicRow returns the topic under the cursor of the topics table
+func (m Model) selectedTopicRow() (string, bool) {
+	row := m.topicsTable.SelectedRow()
+	if len(row) == 0 {
+		return "", false
+	}
+	return row[0], true
+}
+
+// toggleTopicStar stars or unstars the selected topic
+func (m Model) toggleTopicStar() (tea.Model, tea.Cmd) {
+	topic, ok := m.selectedTopicRow()
+	if m.topicTags == nil || !ok {
+		return m, nil
+	}
+	if _, err := m.topicTags.ToggleStar(m.topicTagsCluster, topic); err != nil {
+		m.recordError("topic tags", err)
+		return m, nil
+	}
+	return m, m.refreshTopicsKeepingSelection()
+}
+
+// startTopicTagging opens the tag input for the selected topic
+func (m Model) startTopicTagging() (tea.Model, tea.Cmd) {
+	topic, ok := m.selectedTopicRow()
+	if m.topicTags == nil || !ok {
+		return m, nil
+	}
+	input := textinput.New()
+	input.Prompt = "# "
+	input.Placeholder = "team-a, billing"
+	input.CharLimit = 200
+	input.Width = 40
+	input.SetValue(strings.Join(m.topicMarks(topic).Tags, ", "))
+	input.CursorEnd()
+	m.topicTagInput = input
+	m.topicTagTarget = topic
+	m.topicTagging = true
+	return m, m.topicTagInput.Focus()
+}
+
+// updateTopicTagInput edits the tags of a topic, saving them on Enter
+func (m Model) updateTopicTagInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		m.topicTagging = false
+		m.topicTagInput.Blur()
+		tags := topictags.ParseTags(m.topicTagInput.Value())
+		if err := m.topicTags.SetTags(m.topicTagsCluster, m.topicTagTarget, tags); err != nil {
+			m.recordError("topic tags", err)
+			return m, nil
+		}
+		return m, m.refreshTopicsKeepingSelection()
+	case "esc":
+		m.topicTagging = false
+		m.topicTagInput.Blur()
+		return m, nil
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+
+	var cmd tea.Cmd
+	m.topicTagInput, cmd = m.topicTagInput.Update(msg)
+	return m, cmd
+}
+
+// cycleTopicTagFilter steps the topics filter through all topics, starred
+// topics and each tag in use
+func (m Model) cycleTopicTagFilter() (tea.Model, tea.Cmd) {
+	if m.topicTags == nil {
+		return m, nil
+	}
+	filters := []string{""}
+	if m.topicTags.HasStarred(m.topicTagsCluster) {
+		filters = append(filters, starredFilter)
+	}
+	filters = append(filters, m.topicTags.Tags(m.topicTagsCluster)...)
+
+	next := 0
+	for i, f := range filters {
+		if f == m.topicTagFilter {
+			next = (i + 1) % len(filters)
+			break
+		}
+	}
+	m.topicTagFilter = filters[next]
+	return m, m.refreshTopicsKeepingSelection()
+}
+
+// refreshTopicsKeepingSelection rebuilds the topics table after its marks or
+// filter changed, loading the config of the topic now selected if the
+// filter moved the cursor
+func (m *Model) refreshTopicsKeepingSelection() tea.Cmd {
+	m.refreshTopicsTable()
+	topic, ok := m.selectedTopicRow()
+	if !ok || topic == m.selectedTopic {
+		return nil
+	}
+	m.selectedTopic = topic
+	m.loadingConfig = true
+	return m.scheduleTopicConfig(topic)
+}
+
+// renderTopicTagStatus is the line above the topics table showing the tag
+// input or the active filter, empty when neither applies
+func (m Model) renderTopicTagStatus() string {
+	if m.topicTagging {
+		return m.topicTagInput.View() + lipgloss.NewStyle().Foreground(lipgloss.Color("241")).
+			Render("  tags of "+m.topicTagTarget+", comma separated")
+	}
+	if m.topicTagFilter == "" {
+		return ""
+	}
+	label := "#" + m.topicTagFilter
+	if m.topicTagFilter == starredFilter {
+		label = "starred"
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("86")).
+		Render(fmt.Sprintf("Showing %d of %d topics: %s (f: next filter)", len(m.topicsTable.Rows()), len(m.topics), label))
+}
+
+// topicTagsHelp is the Topics tab help for starring and tagging
+func (m Model) topicTagsHelp() string {
+	if m.topicTags == nil {
+		return ""
+	}
+	return " | s: Star | #: Tags | f: Filter"
+}
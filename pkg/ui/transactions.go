@@ -0,0 +1,290 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+// TransactionsModel lists the transactional IDs the coordinators hold, to
+// find the open transactions holding back the last stable offset
+type TransactionsModel struct {
+	client       *kafka.Client
+	transactions []kafka.Transaction
+	table        table.Model
+	showAll      bool // Include IDs with no transaction open
+	detail       *kafka.Transaction
+	loading      bool
+	err          error
+	width        int
+	height       int
+}
+
+func NewTransactionsModel(client *kafka.Client) TransactionsModel {
+	columns := []table.Column{
+		{Title: "Transactional ID", Width: 36},
+		{Title: "State", Width: 16},
+		{Title: "Producer ID", Width: 12},
+		{Title: "Epoch", Width: 6},
+		{Title: "Partitions", Width: 10},
+		{Title: "Open for", Width: 10},
+		{Title: "Coordinator", Width: 11},
+	}
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithRows([]table.Row{}),
+		table.WithFocused(true),
+		table.WithHeight(15),
+	)
+
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		BorderBottom(true).
+		Bold(false)
+	s.Selected = s.Selected.
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Bold(false)
+	t.SetStyles(s)
+
+	return TransactionsModel{client: client, table: t, loading: true}
+}
+
+type transactionsMsg struct {
+	transactions []kafka.Transaction
+	err          error
+}
+
+type transactionDetailMsg struct {
+	transaction *kafka.Transaction
+	err         error
+}
+
+func fetchTransactions(client *kafka.Client) tea.Cmd {
+	return func() tea.Msg {
+		transactions, err := client.ListTransactions()
+		return transactionsMsg{transactions: transactions, err: err}
+	}
+}
+
+func describeTransaction(client *kafka.Client, id string) tea.Cmd {
+	return func() tea.Msg {
+		transaction, err := client.DescribeTransaction(id)
+		return transactionDetailMsg{transaction: transaction, err: err}
+	}
+}
+
+func (m TransactionsModel) Init() tea.Cmd {
+	return fetchTransactions(m.client)
+}
+
+// visible returns the transactions the table shows
+func (m TransactionsModel) visible() []kafka.Transaction {
+	if m.showAll {
+		return m.transactions
+	}
+	var active []kafka.Transaction
+	for _, t := range m.transactions {
+		if t.Active() {
+			active = append(active, t)
+		}
+	}
+	return active
+}
+
+func (m *TransactionsModel) updateRows() {
+	now := time.Now()
+	var rows []table.Row
+	for _, t := range m.visible() {
+		rows = append(rows, table.Row{
+			t.TransactionalID,
+			t.State,
+			strconv.FormatInt(t.ProducerID, 10),
+			strconv.Itoa(int(t.ProducerEpoch)),
+			strconv.Itoa(t.PartitionCount()),
+			transactionAge(t, now),
+			strconv.Itoa(int(t.Coordinator)),
+		})
+	}
+	setRowsKeepingCursor(&m.table, rows, firstColumn)
+}
+
+// transactionAge is how long the open transaction has run, flagged when
+// it is past its timeout
+func transactionAge(t kafka.Transaction, now time.Time) string {
+	if t.Started.IsZero() {
+		return "-"
+	}
+	age := now.Sub(t.Started).Round(time.Second).String()
+	if t.Overdue(now) {
+		age += " ⚠"
+	}
+	return age
+}
+
+func (m TransactionsModel) Update(msg tea.Msg) (TransactionsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case transactionsMsg:
+		m.loading = false
+		m.err = msg.err
+		if msg.err == nil {
+			m.transactions = msg.transactions
+			m.updateRows()
+		}
+		return m, nil
+
+	case transactionDetailMsg:
+		m.loading = false
+		m.err = msg.err
+		if msg.err == nil {
+			m.detail = msg.transaction
+		}
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.table.SetHeight(max(msg.Height-12, 5))
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.detail != nil {
+			switch msg.String() {
+			case "esc", "q":
+				m.detail = nil
+			case "r":
+				m.loading = true
+				return m, describeTransaction(m.client, m.detail.TransactionalID)
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "esc", "q":
+			return m, ReturnToListView
+		case "r":
+			m.loading = true
+			return m, fetchTransactions(m.client)
+		case "a":
+			m.showAll = !m.showAll
+			m.updateRows()
+			return m, nil
+		case "enter":
+			if row := m.table.SelectedRow(); row != nil {
+				m.loading = true
+				return m, describeTransaction(m.client, row[0])
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+func (m TransactionsModel) View() string {
+	var s strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Padding(0, 1)
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Bold(true)
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	s.WriteString(titleStyle.Render("🔒 Transactions"))
+	s.WriteString("\n\n")
+
+	if m.err != nil {
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(fmt.Sprintf("❌ %v", m.err)))
+		s.WriteString("\n\n")
+	}
+
+	if t := m.detail; t != nil {
+		now := time.Now()
+		s.WriteString(headerStyle.Render(t.TransactionalID))
+		s.WriteString("\n")
+		s.WriteString(fmt.Sprintf("  State:        %s\n", t.State))
+		s.WriteString(fmt.Sprintf("  Producer:     ID %d, epoch %d\n", t.ProducerID, t.ProducerEpoch))
+		s.WriteString(fmt.Sprintf("  Coordinator:  broker %d\n", t.Coordinator))
+		s.WriteString(fmt.Sprintf("  Timeout:      %s\n", t.Timeout))
+		if !t.Started.IsZero() {
+			s.WriteString(fmt.Sprintf("  Started:      %s (%s ago)\n", t.Started.Format("2006-01-02 15:04:05"), now.Sub(t.Started).Round(time.Second)))
+		}
+		if t.Overdue(now) {
+			s.WriteString("\n")
+			s.WriteString(warnStyle.Render("  ⚠ Open past its timeout. Consumers reading committed data are held at the last stable offset of these partitions"))
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+
+		s.WriteString(headerStyle.Render(fmt.Sprintf("Partitions (%d)", t.PartitionCount())))
+		s.WriteString("\n")
+		if t.PartitionCount() == 0 {
+			s.WriteString(dimStyle.Render("  None"))
+			s.WriteString("\n")
+		}
+		topics := make([]string, 0, len(t.Partitions))
+		for topic := range t.Partitions {
+			topics = append(topics, topic)
+		}
+		sort.Strings(topics)
+		for _, topic := range topics {
+			partitions := make([]string, len(t.Partitions[topic]))
+			for i, p := range t.Partitions[topic] {
+				partitions[i] = strconv.Itoa(int(p))
+			}
+			s.WriteString(fmt.Sprintf("  %s: %s\n", topic, truncateString(strings.Join(partitions, ", "), 80)))
+		}
+		s.WriteString("\n" + dimStyle.Render("r: Refresh | Esc: Back to list"))
+		return s.String()
+	}
+
+	if m.loading && m.transactions == nil {
+		s.WriteString("Loading transactions...")
+		s.WriteString("\n\n" + dimStyle.Render("Esc: Back"))
+		return s.String()
+	}
+
+	shown := "open transactions"
+	if m.showAll {
+		shown = "all transactional IDs"
+	}
+	overdue := 0
+	for _, t := range m.transactions {
+		if t.Overdue(time.Now()) {
+			overdue++
+		}
+	}
+	s.WriteString(fmt.Sprintf("Showing %s, %d of %d IDs", shown, len(m.visible()), len(m.transactions)))
+	if overdue > 0 {
+		s.WriteString("  " + warnStyle.Render(fmt.Sprintf("⚠ %d past their timeout", overdue)))
+	}
+	s.WriteString("\n\n")
+	if len(m.visible()) == 0 {
+		s.WriteString(dimStyle.Render("  No transactions to show"))
+		s.WriteString("\n\n")
+	} else {
+		s.WriteString(m.table.View())
+		s.WriteString("\n\n")
+	}
+
+	toggle := "a: Show all IDs"
+	if m.showAll {
+		toggle = "a: Open only"
+	}
+	s.WriteString(dimStyle.Render("Enter: Describe | " + toggle + " | r: Refresh | Esc: Back"))
+	return s.String()
+}
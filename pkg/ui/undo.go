@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/digitalis-io/kconduit/pkg/kafka"
+)
+
+// maxUndoChangesShown limits the changes listed under each journal entry
+const maxUndoChangesShown = 5
+
+// UndoModel shows the session's change journal and reverts the latest entry
+type UndoModel struct {
+	client  *kafka.Client
+	entries []kafka.JournalEntry // Newest first
+	undoing bool
+	result  string
+	err     error
+	width   int
+	height  int
+}
+
+func NewUndoModel(client *kafka.Client) UndoModel {
+	m := UndoModel{client: client}
+	m.loadEntries()
+	return m
+}
+
+func (m *UndoModel) loadEntries() {
+	m.entries = nil
+	if m.client == nil || m.client.Journal() == nil {
+		return
+	}
+	entries := m.client.Journal().Entries()
+	for i := len(entries) - 1; i >= 0; i-- {
+		m.entries = append(m.entries, entries[i])
+	}
+}
+
+type undoneMsg struct {
+	entry kafka.JournalEntry
+	err   error
+}
+
+func undoLast(client *kafka.Client) tea.Cmd {
+	return func() tea.Msg {
+		entry, err := client.Undo()
+		return undoneMsg{entry: entry, err: err}
+	}
+}
+
+func (m UndoModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m UndoModel) Update(msg tea.Msg) (UndoModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			return m, ReturnToListView
+		case "enter", "u":
+			if !m.undoing && len(m.entries) > 0 {
+				m.undoing = true
+				m.result = ""
+				m.err = nil
+				return m, undoLast(m.client)
+			}
+		}
+
+	case undoneMsg:
+		m.undoing = false
+		m.err = msg.err
+		if msg.entry.Label != "" {
+			m.result = "Undid: " + msg.entry.Label
+		}
+		m.loadEntries()
+		if msg.err != nil {
+			return m, reportError("undo", msg.err)
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+	return m, nil
+}
+
+func (m UndoModel) View() string {
+	var s strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Padding(0, 1)
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	nextStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("229")).Bold(true)
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("208"))
+
+	s.WriteString(titleStyle.Render("↶ UNDO"))
+	s.WriteString("\n\n")
+
+	if m.result != "" {
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render("✅ " + m.result))
+		s.WriteString("\n")
+	}
+	if m.err != nil {
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(fmt.Sprintf("❌ %v", m.err)))
+		s.WriteString("\n")
+	}
+	if m.result != "" || m.err != nil {
+		s.WriteString("\n")
+	}
+
+	if len(m.entries) == 0 {
+		s.WriteString("No changes have been made in this session.\n\n")
+		s.WriteString(dimStyle.Render("Esc: Back"))
+		return s.String()
+	}
+
+	s.WriteString("Changes made in this session, newest first:\n\n")
+	for i, entry := range m.entries {
+		header := fmt.Sprintf("%s [%s] %s", entry.Time.Format("15:04:05"), entry.Source, entry.Label)
+		if i == 0 {
+			s.WriteString(nextStyle.Render("→ " + header))
+		} else {
+			s.WriteString("  " + header)
+		}
+		s.WriteString("\n")
+
+		if len(entry.Changes) > 1 || !entry.Changes[0].Revertible() {
+			for j, ch := range entry.Changes {
+				if j == maxUndoChangesShown {
+					s.WriteString(dimStyle.Render(fmt.Sprintf("      … and %d more", len(entry.Changes)-j)))
+					s.WriteString("\n")
+					break
+				}
+				line := "      • " + ch.String()
+				if !ch.Revertible() {
+					s.WriteString(warnStyle.Render(line + " (cannot be undone)"))
+				} else {
+					s.WriteString(dimStyle.Render(line))
+				}
+				s.WriteString("\n")
+			}
+		}
+	}
+	s.WriteString("\n")
+
+	if m.undoing {
+		s.WriteString("Undoing...\n\n")
+	}
+	s.WriteString(dimStyle.Render("Enter/u: Undo the latest change • Esc: Back"))
+	return s.String()
+}